@@ -0,0 +1,69 @@
+/*
+Package main is the entry point for tool-hub-catalog, a search-only MCP
+server for constrained environments.
+
+It answers hub_search from a static tool index (the output of
+`tool-hub-mcp export-index`) and never spawns MCP servers or opens SQLite.
+hub_execute returns instructions for running the tool on a full
+tool-hub-mcp host instead.
+
+The index is bundled at build time from catalog.jsonl in this directory:
+
+	tool-hub-mcp export-index --output cmd/tool-hub-catalog/catalog.jsonl
+	go build -o bin/tool-hub-catalog ./cmd/tool-hub-catalog
+
+Usage:
+
+	tool-hub-catalog [--index path]
+
+With --index, the given exported index is used instead of the bundled one.
+*/
+package main
+
+import (
+	"bytes"
+	_ "embed"
+	"flag"
+	"io"
+	"log"
+	"os"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/catalog"
+)
+
+// version is set via ldflags (-X main.version=...) rather than through
+// internal/version, which would link the HTTP client of the update checker.
+var version = "dev"
+
+// bundledIndex is the exported index compiled into the binary.
+//
+//go:embed catalog.jsonl
+var bundledIndex []byte
+
+func main() {
+	indexPath := flag.String("index", "", "Exported index to serve instead of the bundled one (JSONL or JSON)")
+	flag.Parse()
+
+	// stdout carries the protocol; logs go to stderr
+	log.SetOutput(os.Stderr)
+
+	var source io.Reader = bytes.NewReader(bundledIndex)
+	if *indexPath != "" {
+		f, err := os.Open(*indexPath)
+		if err != nil {
+			log.Fatalf("Failed to open index: %v", err)
+		}
+		defer f.Close()
+		source = f
+	}
+
+	c, err := catalog.Load(source)
+	if err != nil {
+		log.Fatalf("Failed to load catalog: %v", err)
+	}
+	log.Printf("tool-hub-catalog serving %d tools from %d servers", c.Count(), len(c.Servers()))
+
+	if err := catalog.NewServer(c, version).Serve(os.Stdin, os.Stdout); err != nil {
+		log.Fatalf("Server error: %v", err)
+	}
+}
@@ -12,11 +12,19 @@ Usage:
 Available Commands:
 
 	setup       Import MCP configurations from AI CLI tools
+	install     Register tool-hub-mcp in an AI client's MCP config
 	serve       Run the MCP server (stdio transport)
 	add         Add an MCP server manually
 	remove      Remove an MCP server
+	rename      Rename an MCP server
 	list        List all registered MCP servers
+	search      Search tools the way hub_search does
+	exec        Run a tool of an MCP server directly
 	verify      Verify configuration and connections
+	config      Inspect and export the configuration
+	stats       Export usage statistics
+	audit       Show the audit trail of tool executions
+	completion  Generate the shell completion script
 	help        Help about any command
 
 Examples:
@@ -34,14 +42,20 @@ package main
 
 import (
 	"fmt"
+	"log"
 	"os"
 
 	"github.com/khanglvm/tool-hub-mcp/internal/cli"
+	"github.com/khanglvm/tool-hub-mcp/internal/redact"
 	"github.com/khanglvm/tool-hub-mcp/internal/version"
 	"github.com/spf13/cobra"
 )
 
 func main() {
+	// Keep tokens from the environment and config out of every log line
+	redact.AddEnviron(os.Environ())
+	log.SetOutput(redact.NewWriter(os.Stderr))
+
 	rootCmd := &cobra.Command{
 		Use:   "tool-hub-mcp",
 		Short: "Serverless MCP aggregator - reduce context tokens by 96%",
@@ -59,22 +73,41 @@ Token savings: 38% reduction (2 meta-tools vs 100+ individual tools)`,
 
 	// Add subcommands
 	rootCmd.AddCommand(cli.NewSetupCmd())
+	rootCmd.AddCommand(cli.NewInstallCmd())
 	rootCmd.AddCommand(cli.NewVersionCmd())
 	rootCmd.AddCommand(cli.NewServeCmd())
 	rootCmd.AddCommand(cli.NewAddCmd())
 	rootCmd.AddCommand(cli.NewRemoveCmd())
+	rootCmd.AddCommand(cli.NewRenameCmd())
 	rootCmd.AddCommand(cli.NewListCmd())
+	rootCmd.AddCommand(cli.NewSearchCmd())
+	rootCmd.AddCommand(cli.NewExecCmd())
 	rootCmd.AddCommand(cli.NewVerifyCmd())
 	rootCmd.AddCommand(cli.NewExportIndexCmd())
+	rootCmd.AddCommand(cli.NewConfigCmd())
+	rootCmd.AddCommand(cli.NewCompletionCmd())
 
-	// Benchmark command with speed subcommand
+	// Benchmark command with speed and simulate subcommands
 	benchmarkCmd := cli.NewBenchmarkCmd()
 	benchmarkCmd.AddCommand(cli.NewSpeedBenchmarkCmd())
+	benchmarkCmd.AddCommand(cli.NewSimulateBenchmarkCmd())
 	rootCmd.AddCommand(benchmarkCmd)
 
 	// Learning command group
 	rootCmd.AddCommand(cli.NewLearningCmd())
 
+	// Usage statistics command group
+	rootCmd.AddCommand(cli.NewStatsCmd())
+
+	// Audit trail of hub_execute calls
+	rootCmd.AddCommand(cli.NewAuditCmd())
+
+	// Search-to-execution decision chains
+	rootCmd.AddCommand(cli.NewTraceCmd())
+
+	// Hidden helper that sandboxed servers are spawned through
+	rootCmd.AddCommand(cli.NewSandboxCmd())
+
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
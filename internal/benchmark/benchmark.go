@@ -5,17 +5,19 @@ It compares context token consumption between:
 1. Traditional MCP: Multiple individual servers with all their tools
 2. tool-hub-mcp: Single aggregator with 5 meta-tools
 
-Token estimation uses tiktoken-compatible counting (GPT-4/Claude approximation:
-~4 characters per token for English text, ~3 for JSON/code).
+Token counts use real BPE encodings (cl100k_base, o200k_base) over the JSON
+tool definitions, reported per model family. Servers whose definitions are
+unavailable fall back to per-server tool count estimates.
 */
 package benchmark
 
 import (
-	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/protocol"
 )
 
 // TokenEstimate represents token consumption estimates.
@@ -32,6 +34,20 @@ type BenchmarkResult struct {
 	ToolHub        TokenEstimate `json:"toolHub"`
 	TokenSavings   int           `json:"tokenSavings"`
 	SavingsPercent float64       `json:"savingsPercent"`
+
+	// MeasuredServers is how many servers had actual tool definitions.
+	MeasuredServers int `json:"measuredServers"`
+	// Models breaks the comparison down per model tokenizer.
+	Models []ModelEstimate `json:"models"`
+}
+
+// ModelEstimate is the token comparison for one model family.
+type ModelEstimate struct {
+	Model
+	TraditionalTokens int     `json:"traditionalTokens"`
+	ToolHubTokens     int     `json:"toolHubTokens"`
+	TokenSavings      int     `json:"tokenSavings"`
+	SavingsPercent    float64 `json:"savingsPercent"`
 }
 
 // AverageToolsPerServer is the estimated number of tools per MCP server.
@@ -102,44 +118,145 @@ func getToolCount(serverName string) int {
 	return AverageToolsPerServer
 }
 
-// RunBenchmark compares token consumption between traditional and tool-hub-mcp setups.
+// RunBenchmark compares token consumption between traditional and tool-hub-mcp
+// setups using estimated tool counts for every server.
 func RunBenchmark(cfg *config.Config) *BenchmarkResult {
+	return RunBenchmarkWithTools(cfg, nil)
+}
+
+// RunBenchmarkWithTools is RunBenchmark with the actual tool definitions of
+// some servers, keyed by server name. Those servers are measured with each
+// model's tokenizer; the rest are estimated from known tool counts.
+func RunBenchmarkWithTools(cfg *config.Config, tools map[string][]protocol.Tool) *BenchmarkResult {
 	serverCount := len(cfg.Servers)
 
-	// Estimate traditional setup using known tool counts where available
+	// Collect measured definitions; estimate the rest
 	traditionalTools := 0
+	estimatedTokens := 0
+	measuredServers := 0
+	var definitions []protocol.Tool
 	for name := range cfg.Servers {
-		traditionalTools += getToolCount(name)
+		if defs, ok := tools[name]; ok {
+			traditionalTools += len(defs)
+			definitions = append(definitions, defs...)
+			measuredServers++
+			continue
+		}
+		count := getToolCount(name)
+		traditionalTools += count
+		estimatedTokens += count * AverageTokensPerTool
+	}
+
+	hubDefinitions := GetToolHubToolDefinitions()
+	models := make([]ModelEstimate, 0, len(Models))
+	for _, m := range Models {
+		traditionalTokens := estimatedTokens
+		if len(definitions) > 0 {
+			traditionalTokens += CountTokensWith(m.Encoding, definitions)
+		}
+		models = append(models, newModelEstimate(m, traditionalTokens, CountTokensWith(m.Encoding, hubDefinitions)))
+	}
+
+	// The headline figures use the default encoding
+	headline := models[0]
+	for _, m := range models {
+		if m.Encoding == DefaultEncoding {
+			headline = m
+			break
+		}
+	}
+
+	description := fmt.Sprintf("%d MCP servers with %d total tools", serverCount, traditionalTools)
+	if measuredServers > 0 {
+		description += fmt.Sprintf(" (%d measured)", measuredServers)
 	}
-	traditionalTokens := traditionalTools * AverageTokensPerTool
 
 	traditional := TokenEstimate{
 		ServerCount:      serverCount,
 		ToolCount:        traditionalTools,
-		DefinitionTokens: traditionalTokens,
-		Description:      fmt.Sprintf("%d MCP servers with %d total tools", serverCount, traditionalTools),
+		DefinitionTokens: headline.TraditionalTokens,
+		Description:      description,
 	}
 
-	// tool-hub-mcp setup (fixed 5 meta-tools)
-	toolHubTokens := ToolHubTools * AverageTokensPerTool
-
 	toolHub := TokenEstimate{
 		ServerCount:      1,
 		ToolCount:        ToolHubTools,
-		DefinitionTokens: toolHubTokens,
+		DefinitionTokens: headline.ToolHubTokens,
 		Description:      "1 tool-hub-mcp server with 5 meta-tools",
 	}
 
-	// Calculate savings
+	return &BenchmarkResult{
+		Traditional:     traditional,
+		ToolHub:         toolHub,
+		TokenSavings:    headline.TokenSavings,
+		SavingsPercent:  headline.SavingsPercent,
+		MeasuredServers: measuredServers,
+		Models:          models,
+	}
+}
+
+// newModelEstimate computes the savings for one model.
+func newModelEstimate(m Model, traditionalTokens, toolHubTokens int) ModelEstimate {
 	savings := traditionalTokens - toolHubTokens
-	savingsPercent := float64(savings) / float64(traditionalTokens) * 100
+	percent := 0.0
+	if traditionalTokens > 0 {
+		percent = float64(savings) / float64(traditionalTokens) * 100
+	}
+	return ModelEstimate{
+		Model:             m,
+		TraditionalTokens: traditionalTokens,
+		ToolHubTokens:     toolHubTokens,
+		TokenSavings:      savings,
+		SavingsPercent:    percent,
+	}
+}
 
-	return &BenchmarkResult{
-		Traditional:    traditional,
-		ToolHub:        toolHub,
-		TokenSavings:   savings,
-		SavingsPercent: savingsPercent,
+// ServerCost is the measured context cost of one server's tool definitions.
+type ServerCost struct {
+	Server  string  `json:"server"`
+	Tools   int     `json:"tools"`
+	Tokens  int     `json:"tokens"`
+	Percent float64 `json:"percent"`
+}
+
+// Breakdown measures each server's definitions with DefaultEncoding and
+// returns them sorted by sortBy: "tokens" (default, most expensive first),
+// "tools" or "name".
+func Breakdown(tools map[string][]protocol.Tool, sortBy string) ([]ServerCost, error) {
+	costs := make([]ServerCost, 0, len(tools))
+	total := 0
+	for name, defs := range tools {
+		tokens := CountTokens(defs)
+		total += tokens
+		costs = append(costs, ServerCost{Server: name, Tools: len(defs), Tokens: tokens})
+	}
+	for i := range costs {
+		if total > 0 {
+			costs[i].Percent = float64(costs[i].Tokens) / float64(total) * 100
+		}
 	}
+
+	var less func(a, b ServerCost) bool
+	switch sortBy {
+	case "", "tokens":
+		less = func(a, b ServerCost) bool { return a.Tokens > b.Tokens }
+	case "tools":
+		less = func(a, b ServerCost) bool { return a.Tools > b.Tools }
+	case "name":
+		less = func(a, b ServerCost) bool { return false }
+	default:
+		return nil, fmt.Errorf("invalid sort %q (use tokens, tools or name)", sortBy)
+	}
+	sort.Slice(costs, func(i, j int) bool {
+		if less(costs[i], costs[j]) {
+			return true
+		}
+		if less(costs[j], costs[i]) {
+			return false
+		}
+		return costs[i].Server < costs[j].Server
+	})
+	return costs, nil
 }
 
 // GetToolHubToolDefinitions returns the actual tool definitions used by tool-hub-mcp.
@@ -224,16 +341,9 @@ func GetToolHubToolDefinitions() []map[string]interface{} {
 	}
 }
 
-// CountTokens estimates token count for a JSON structure.
-// Uses approximation: ~3 characters per token for JSON/code.
+// CountTokens counts tokens for a JSON structure using DefaultEncoding.
 func CountTokens(v interface{}) int {
-	data, err := json.Marshal(v)
-	if err != nil {
-		return 0
-	}
-	// JSON/code is more token-dense than natural language
-	// Approximate: 3 characters per token
-	return len(data) / 3
+	return CountTokensWith(DefaultEncoding, v)
 }
 
 // CountActualToolHubTokens counts actual tokens in tool-hub-mcp definitions.
@@ -0,0 +1,69 @@
+package benchmark
+
+// Exchange is one tool call in a conversation: what the model sends and
+// what comes back into its context.
+type Exchange struct {
+	Request  interface{}
+	Response interface{}
+}
+
+// ConversationCost is the input token cost of a simulated conversation.
+type ConversationCost struct {
+	// Turns is the number of model turns: one per tool call plus the answer.
+	Turns int `json:"turns"`
+	// DefinitionTokens is the tool definitions resent on every turn.
+	DefinitionTokens int `json:"definitionTokens"`
+	// ExchangeTokens is the total of all tool call requests and responses.
+	ExchangeTokens int `json:"exchangeTokens"`
+	// TotalInputTokens is what the model reads over the whole conversation.
+	TotalInputTokens int `json:"totalInputTokens"`
+}
+
+// SimulationResult compares the same workflow through both setups.
+type SimulationResult struct {
+	Searches       int              `json:"searches"`
+	Executions     int              `json:"executions"`
+	Traditional    ConversationCost `json:"traditional"`
+	ToolHub        ConversationCost `json:"toolHub"`
+	TokenSavings   int              `json:"tokenSavings"`
+	SavingsPercent float64          `json:"savingsPercent"`
+}
+
+// SimulateConversation costs a conversation in which the model makes the
+// given tool calls one per turn and then answers. Every turn rereads the
+// tool definitions and the history of earlier exchanges.
+func SimulateConversation(definitions interface{}, exchanges []Exchange) ConversationCost {
+	cost := ConversationCost{
+		Turns:            len(exchanges) + 1,
+		DefinitionTokens: CountTokens(definitions),
+	}
+
+	history := 0
+	for _, e := range exchanges {
+		cost.TotalInputTokens += cost.DefinitionTokens + history
+		tokens := CountTokens(e.Request) + CountTokens(e.Response)
+		history += tokens
+		cost.ExchangeTokens += tokens
+	}
+	// Final answer turn
+	cost.TotalInputTokens += cost.DefinitionTokens + history
+
+	return cost
+}
+
+// CompareConversations builds a SimulationResult from both setups' costs.
+func CompareConversations(searches, executions int, traditional, toolHub ConversationCost) *SimulationResult {
+	savings := traditional.TotalInputTokens - toolHub.TotalInputTokens
+	percent := 0.0
+	if traditional.TotalInputTokens > 0 {
+		percent = float64(savings) / float64(traditional.TotalInputTokens) * 100
+	}
+	return &SimulationResult{
+		Searches:       searches,
+		Executions:     executions,
+		Traditional:    traditional,
+		ToolHub:        toolHub,
+		TokenSavings:   savings,
+		SavingsPercent: percent,
+	}
+}
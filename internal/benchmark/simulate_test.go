@@ -0,0 +1,28 @@
+package benchmark
+
+import "testing"
+
+func TestSimulateConversation(t *testing.T) {
+	definitions := []map[string]interface{}{{"name": "get_issue", "description": "Get a Jira issue"}}
+	exchange := Exchange{
+		Request:  map[string]interface{}{"name": "get_issue"},
+		Response: map[string]interface{}{"text": "PROJ-1: Fix login"},
+	}
+
+	cost := SimulateConversation(definitions, []Exchange{exchange, exchange})
+
+	d := CountTokens(definitions)
+	e := CountTokens(exchange.Request) + CountTokens(exchange.Response)
+	if cost.Turns != 3 || cost.DefinitionTokens != d || cost.ExchangeTokens != 2*e {
+		t.Errorf("unexpected cost: %+v", cost)
+	}
+	// Turns reread definitions plus 0, 1 and 2 exchanges of history
+	if want := 3*d + 3*e; cost.TotalInputTokens != want {
+		t.Errorf("TotalInputTokens = %d, want %d", cost.TotalInputTokens, want)
+	}
+
+	result := CompareConversations(0, 2, ConversationCost{TotalInputTokens: 1000}, ConversationCost{TotalInputTokens: 250})
+	if result.TokenSavings != 750 || result.SavingsPercent != 75 {
+		t.Errorf("unexpected comparison: %+v", result)
+	}
+}
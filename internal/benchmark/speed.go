@@ -0,0 +1,97 @@
+package benchmark
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/protocol"
+)
+
+// LatencyStats summarizes a set of latency samples in milliseconds.
+type LatencyStats struct {
+	Samples int     `json:"samples"`
+	MeanMs  float64 `json:"meanMs"`
+	P50Ms   float64 `json:"p50Ms"`
+	P95Ms   float64 `json:"p95Ms"`
+	P99Ms   float64 `json:"p99Ms"`
+}
+
+// NewLatencyStats computes nearest-rank percentiles over samples.
+func NewLatencyStats(samples []time.Duration) LatencyStats {
+	if len(samples) == 0 {
+		return LatencyStats{}
+	}
+
+	sorted := make([]time.Duration, len(samples))
+	copy(sorted, samples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var total time.Duration
+	for _, s := range sorted {
+		total += s
+	}
+
+	return LatencyStats{
+		Samples: len(sorted),
+		MeanMs:  toMs(total / time.Duration(len(sorted))),
+		P50Ms:   toMs(percentile(sorted, 50)),
+		P95Ms:   toMs(percentile(sorted, 95)),
+		P99Ms:   toMs(percentile(sorted, 99)),
+	}
+}
+
+// percentile returns the nearest-rank percentile of sorted samples.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// toMs converts a duration to fractional milliseconds.
+func toMs(d time.Duration) float64 {
+	return math.Round(float64(d)/float64(time.Millisecond)*100) / 100
+}
+
+// ServerSpeed is the latency profile of one server.
+type ServerSpeed struct {
+	Server string `json:"server"`
+	Tools  int    `json:"tools"`
+	// Cold covers spawn, initialize and the first tools/list.
+	Cold LatencyStats `json:"cold"`
+	// Warm covers tools/list against a running process.
+	Warm LatencyStats `json:"warm"`
+	// CallTool is the no-op tool timed for tools/call round trips, if any.
+	CallTool string        `json:"callTool,omitempty"`
+	Call     *LatencyStats `json:"call,omitempty"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// noopToolNames are tools that are safe to call without arguments.
+var noopToolNames = []string{"ping", "noop", "no_op", "health", "healthcheck", "health_check", "version", "get_version"}
+
+// FindNoopTool returns the name of a side-effect-free tool that takes no
+// required arguments, or "" if the server has none.
+func FindNoopTool(tools []protocol.Tool) string {
+	for _, want := range noopToolNames {
+		for _, t := range tools {
+			if strings.EqualFold(t.Name, want) && !hasRequiredArgs(t.InputSchema) {
+				return t.Name
+			}
+		}
+	}
+	return ""
+}
+
+// hasRequiredArgs reports whether an input schema declares required properties.
+func hasRequiredArgs(schema interface{}) bool {
+	m, ok := schema.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	required, ok := m["required"].([]interface{})
+	return ok && len(required) > 0
+}
@@ -0,0 +1,43 @@
+package benchmark
+
+import (
+	"testing"
+	"time"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/protocol"
+)
+
+func TestNewLatencyStats(t *testing.T) {
+	var samples []time.Duration
+	for i := 100; i >= 1; i-- {
+		samples = append(samples, time.Duration(i)*time.Millisecond)
+	}
+
+	stats := NewLatencyStats(samples)
+	if stats.Samples != 100 || stats.P50Ms != 50 || stats.P95Ms != 95 || stats.P99Ms != 99 || stats.MeanMs != 50.5 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	single := NewLatencyStats([]time.Duration{7 * time.Millisecond})
+	if single.P50Ms != 7 || single.P99Ms != 7 {
+		t.Errorf("single sample should be every percentile: %+v", single)
+	}
+
+	if empty := NewLatencyStats(nil); empty.Samples != 0 {
+		t.Errorf("expected empty stats, got %+v", empty)
+	}
+}
+
+func TestFindNoopTool(t *testing.T) {
+	tools := []protocol.Tool{
+		{Name: "create_issue"},
+		{Name: "version", InputSchema: map[string]interface{}{"type": "object", "required": []interface{}{"format"}}},
+		{Name: "Ping", InputSchema: map[string]interface{}{"type": "object"}},
+	}
+	if got := FindNoopTool(tools); got != "Ping" {
+		t.Errorf("FindNoopTool() = %q, want Ping", got)
+	}
+	if got := FindNoopTool(tools[:2]); got != "" {
+		t.Errorf("tools with required arguments are not no-ops, got %q", got)
+	}
+}
@@ -0,0 +1,71 @@
+package benchmark
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/pkoukk/tiktoken-go"
+	tiktoken_loader "github.com/pkoukk/tiktoken-go-loader"
+)
+
+// DefaultEncoding is the BPE encoding used by CountTokens.
+const DefaultEncoding = "cl100k_base"
+
+// Model is a client model family whose context cost is reported.
+type Model struct {
+	Name     string `json:"name"`
+	Encoding string `json:"encoding"`
+	// Approximate is set when the model's own tokenizer is not public and
+	// a comparable BPE encoding stands in for it.
+	Approximate bool `json:"approximate,omitempty"`
+}
+
+// Models are the model families the benchmark reports estimates for.
+var Models = []Model{
+	{Name: "gpt-4o", Encoding: "o200k_base"},
+	{Name: "gpt-4", Encoding: "cl100k_base"},
+	{Name: "claude", Encoding: "cl100k_base", Approximate: true},
+}
+
+var (
+	encodingsMu sync.Mutex
+	encodings   = make(map[string]*tiktoken.Tiktoken)
+)
+
+func init() {
+	// Use the encodings embedded in the binary instead of downloading them
+	tiktoken.SetBpeLoader(tiktoken_loader.NewOfflineLoader())
+}
+
+// getEncoding returns a cached tokenizer for the named encoding.
+func getEncoding(name string) (*tiktoken.Tiktoken, error) {
+	encodingsMu.Lock()
+	defer encodingsMu.Unlock()
+
+	if enc, ok := encodings[name]; ok {
+		return enc, nil
+	}
+	enc, err := tiktoken.GetEncoding(name)
+	if err != nil {
+		return nil, err
+	}
+	encodings[name] = enc
+	return enc, nil
+}
+
+// CountTokensWith counts the tokens in the JSON encoding of v using the
+// named BPE encoding. If the encoding is unavailable it falls back to
+// ~3 characters per token.
+func CountTokensWith(encoding string, v interface{}) int {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return 0
+	}
+
+	enc, err := getEncoding(encoding)
+	if err != nil {
+		// JSON/code is more token-dense than natural language
+		return len(data) / 3
+	}
+	return len(enc.Encode(string(data), nil, nil))
+}
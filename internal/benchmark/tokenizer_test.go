@@ -0,0 +1,56 @@
+package benchmark
+
+import (
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/protocol"
+)
+
+func TestCountTokensWith(t *testing.T) {
+	for _, m := range Models {
+		// "hello world" with quotes: ", hello, " world", "
+		if got := CountTokensWith(m.Encoding, "hello world"); got < 3 || got > 5 {
+			t.Errorf("%s: CountTokensWith(%q) = %d, want 3-5", m.Encoding, "hello world", got)
+		}
+	}
+
+	if _, err := getEncoding("o200k_base"); err != nil {
+		t.Fatalf("embedded o200k_base encoding unavailable: %v", err)
+	}
+}
+
+func TestRunBenchmarkWithTools(t *testing.T) {
+	cfg := &config.Config{Servers: map[string]*config.ServerConfig{
+		"jira":  {Command: "npx"},
+		"local": {Command: "node"},
+	}}
+	tools := map[string][]protocol.Tool{
+		"local": {
+			{Name: "read_file", Description: "Read a file", InputSchema: map[string]interface{}{"type": "object"}},
+			{Name: "write_file", Description: "Write a file", InputSchema: map[string]interface{}{"type": "object"}},
+		},
+	}
+
+	result := RunBenchmarkWithTools(cfg, tools)
+
+	if result.MeasuredServers != 1 {
+		t.Errorf("MeasuredServers = %d, want 1", result.MeasuredServers)
+	}
+	// jira is estimated from the known count, local is measured
+	if want := knownToolCounts["jira"] + 2; result.Traditional.ToolCount != want {
+		t.Errorf("ToolCount = %d, want %d", result.Traditional.ToolCount, want)
+	}
+	if len(result.Models) != len(Models) {
+		t.Fatalf("got %d model estimates, want %d", len(result.Models), len(Models))
+	}
+
+	estimated := knownToolCounts["jira"] * AverageTokensPerTool
+	measured := CountTokensWith(DefaultEncoding, tools["local"])
+	if result.Traditional.DefinitionTokens != estimated+measured {
+		t.Errorf("DefinitionTokens = %d, want %d", result.Traditional.DefinitionTokens, estimated+measured)
+	}
+	if result.ToolHub.DefinitionTokens != CountActualToolHubTokens() {
+		t.Errorf("tool-hub tokens = %d, want measured %d", result.ToolHub.DefinitionTokens, CountActualToolHubTokens())
+	}
+}
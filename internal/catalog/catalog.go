@@ -0,0 +1,142 @@
+/*
+Package catalog answers tool searches from a static, exported tool index.
+
+It is the embedded counterpart of package mcp: no child processes are
+spawned and no SQLite storage is opened, so it suits constrained hosts
+that can only search. Entries use the same format as
+`tool-hub-mcp export-index` (JSONL or a JSON array), are ranked by a
+small in-memory BM25 index, and executions are answered with
+instructions for running the tool on a host with the full tool-hub-mcp.
+
+The package deliberately avoids internal/search: bleve links mmap and
+bbolt, which do not build for GOOS=js or GOOS=wasip1, and this package
+must (see `make check-wasm`).
+*/
+package catalog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Entry is a tool in an exported index (see `tool-hub-mcp export-index`).
+type Entry struct {
+	Tool        string      `json:"tool"`
+	Server      string      `json:"server"`
+	Description string      `json:"description"`
+	InputSchema interface{} `json:"inputSchema"`
+}
+
+// Catalog is a read-only, searchable set of tools.
+type Catalog struct {
+	index   *index
+	servers []string
+	count   int
+}
+
+// Parse reads an exported index in JSONL or JSON array format.
+// Blank lines are ignored; an empty input yields no entries.
+func Parse(r io.Reader) ([]Entry, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read index: %w", err)
+	}
+
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	if data[0] == '[' {
+		var entries []Entry
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return nil, fmt.Errorf("failed to parse index: %w", err)
+		}
+		return entries, nil
+	}
+
+	var entries []Entry
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal(text, &entry); err != nil {
+			return nil, fmt.Errorf("failed to parse index line %d: %w", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// New indexes entries into an in-memory search index. Entries without
+// a server or tool name are skipped.
+func New(entries []Entry) *Catalog {
+	var indexed []Entry
+	seen := make(map[string]bool)
+	for _, e := range entries {
+		if e.Server == "" || e.Tool == "" {
+			continue
+		}
+		indexed = append(indexed, e)
+		seen[e.Server] = true
+	}
+
+	c := &Catalog{index: newIndex(indexed), count: len(indexed)}
+	for server := range seen {
+		c.servers = append(c.servers, server)
+	}
+	sort.Strings(c.servers)
+
+	return c
+}
+
+// Load parses an exported index and builds a catalog from it.
+func Load(r io.Reader) (*Catalog, error) {
+	entries, err := Parse(r)
+	if err != nil {
+		return nil, err
+	}
+	return New(entries), nil
+}
+
+// Servers returns the server names in the catalog, sorted.
+func (c *Catalog) Servers() []string {
+	return c.servers
+}
+
+// Count returns the number of indexed tools.
+func (c *Catalog) Count() int {
+	return c.count
+}
+
+// Search returns the best matching tools, optionally limited to one server.
+func (c *Catalog) Search(query, server string, limit int) []Result {
+	return c.index.search(query, server, limit)
+}
+
+// ExecutionInstructions explains how to run a catalog tool, since the
+// embedded mode cannot spawn servers itself.
+func ExecutionInstructions(server, tool string, args map[string]interface{}) string {
+	if args == nil {
+		args = map[string]interface{}{}
+	}
+	call, _ := json.Marshal(map[string]interface{}{
+		"server":    server,
+		"tool":      tool,
+		"arguments": args,
+	})
+	return fmt.Sprintf(`This catalog is search-only and cannot execute tools.
+
+To run %s/%s, call hub_execute on a host running the full tool-hub-mcp
+(tool-hub-mcp serve) with the server "%s" configured:
+
+%s`, server, tool, server, call)
+}
@@ -0,0 +1,116 @@
+package catalog
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const sampleIndex = `{"tool":"create_issue","server":"jira","description":"Create a Jira issue","inputSchema":{"type":"object"}}
+
+{"tool":"search_documents","server":"outline","description":"Search documents in the knowledge base","inputSchema":{}}
+`
+
+func TestParseFormats(t *testing.T) {
+	entries, err := Parse(strings.NewReader(sampleIndex))
+	if err != nil {
+		t.Fatalf("Parse JSONL failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+
+	array, _ := json.Marshal(entries)
+	fromArray, err := Parse(bytes.NewReader(array))
+	if err != nil || len(fromArray) != 2 {
+		t.Fatalf("Parse JSON array = %v, %v", fromArray, err)
+	}
+
+	empty, err := Parse(strings.NewReader("  \n"))
+	if err != nil || len(empty) != 0 {
+		t.Errorf("empty index should parse to no entries: %v, %v", empty, err)
+	}
+
+	if _, err := Parse(strings.NewReader("{broken\n")); err == nil {
+		t.Error("expected error for malformed line")
+	}
+}
+
+func TestCatalogSearch(t *testing.T) {
+	c, err := Load(strings.NewReader(sampleIndex))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if c.Count() != 2 || len(c.Servers()) != 2 {
+		t.Fatalf("unexpected catalog size: %d tools, servers %v", c.Count(), c.Servers())
+	}
+
+	results := c.Search("jira issue", "", 5)
+	if len(results) == 0 || results[0].ToolName != "create_issue" {
+		t.Errorf("expected create_issue first, got %+v", results)
+	}
+
+	results = c.Search("search", "jira", 5)
+	for _, r := range results {
+		if r.ServerName != "jira" {
+			t.Errorf("server filter ignored: %+v", r)
+		}
+	}
+}
+
+func TestServeSearchAndExecute(t *testing.T) {
+	c, err := Load(strings.NewReader(sampleIndex))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize"}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"hub_search","arguments":{"query":"documents"}}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"hub_execute","arguments":{"server":"outline","tool":"search_documents"}}}`,
+	}, "\n")
+
+	var out bytes.Buffer
+	if err := NewServer(c, "test").Serve(strings.NewReader(input), &out); err != nil {
+		t.Fatalf("Serve failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 responses (notification ignored), got %d:\n%s", len(lines), out.String())
+	}
+	if !strings.Contains(lines[1], "search_documents") {
+		t.Errorf("search response missing tool: %s", lines[1])
+	}
+	if !strings.Contains(lines[2], "search-only") {
+		t.Errorf("execute should return instructions: %s", lines[2])
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	got := strings.Join(tokenize("createIssue create_issue Search-Docs v2"), " ")
+	if want := "create issue create issue search docs v2"; got != want {
+		t.Errorf("tokenize = %q, want %q", got, want)
+	}
+}
+
+func TestCatalogSearchLimitAndMisses(t *testing.T) {
+	c := New([]Entry{
+		{Tool: "list_issues", Server: "jira", Description: "List issues"},
+		{Tool: "get_issue", Server: "jira", Description: "Get an issue"},
+		{Tool: "", Server: "jira", Description: "skipped: no name"},
+	})
+
+	if c.Count() != 2 {
+		t.Fatalf("entries without a name should be skipped, got %d tools", c.Count())
+	}
+	if results := c.Search("issue", "", 1); len(results) != 1 {
+		t.Errorf("limit ignored: %+v", results)
+	}
+	if results := c.Search("kubernetes", "", 5); len(results) != 0 {
+		t.Errorf("unmatched query should return nothing: %+v", results)
+	}
+}
@@ -0,0 +1,169 @@
+package catalog
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// BM25 parameters, matching bleve's defaults used by internal/search.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// fieldWeights boosts matches in the tool name over the description.
+var fieldWeights = map[string]float64{
+	"name":        2,
+	"description": 1,
+	"server":      1,
+}
+
+// Result is a catalog search hit. Its JSON form matches the results of
+// the full server's hub_search.
+type Result struct {
+	ToolName    string      `json:"name"`
+	Description string      `json:"description"`
+	InputSchema interface{} `json:"inputSchema"`
+	ServerName  string      `json:"server"`
+	Score       float64     `json:"score"`
+}
+
+// document is an indexed entry with its term frequencies by field.
+type document struct {
+	entry  Entry
+	terms  map[string]map[string]int
+	length map[string]int
+}
+
+// index is an in-memory BM25 index. It is built once and only read
+// afterwards, so it needs no locking and nothing to release.
+type index struct {
+	docs      []document
+	docFreq   map[string]int
+	avgLength map[string]float64
+}
+
+// newIndex indexes entries.
+func newIndex(entries []Entry) *index {
+	idx := &index{docFreq: make(map[string]int), avgLength: make(map[string]float64)}
+
+	for _, e := range entries {
+		doc := document{
+			entry:  e,
+			terms:  make(map[string]map[string]int),
+			length: make(map[string]int),
+		}
+		seen := make(map[string]bool)
+		for field, text := range map[string]string{"name": e.Tool, "description": e.Description, "server": e.Server} {
+			tokens := tokenize(text)
+			doc.length[field] = len(tokens)
+			idx.avgLength[field] += float64(len(tokens))
+			freqs := make(map[string]int)
+			for _, t := range tokens {
+				freqs[t]++
+				if !seen[t] {
+					seen[t] = true
+					idx.docFreq[t]++
+				}
+			}
+			doc.terms[field] = freqs
+		}
+		idx.docs = append(idx.docs, doc)
+	}
+
+	for field := range idx.avgLength {
+		idx.avgLength[field] /= float64(len(idx.docs))
+	}
+	return idx
+}
+
+// search returns up to limit hits for query, best first, optionally
+// limited to one server.
+func (idx *index) search(query, server string, limit int) []Result {
+	terms := uniqueTokens(query)
+	n := float64(len(idx.docs))
+
+	var results []Result
+	for _, doc := range idx.docs {
+		if server != "" && doc.entry.Server != server {
+			continue
+		}
+		var score float64
+		for _, t := range terms {
+			df := float64(idx.docFreq[t])
+			if df == 0 {
+				continue
+			}
+			idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+			for field, weight := range fieldWeights {
+				tf := float64(doc.terms[field][t])
+				if tf == 0 {
+					continue
+				}
+				norm := 1 - bm25B + bm25B*float64(doc.length[field])/idx.avgLength[field]
+				score += weight * idf * tf * (bm25K1 + 1) / (tf + bm25K1*norm)
+			}
+		}
+		if score == 0 {
+			continue
+		}
+		results = append(results, Result{
+			ToolName:    doc.entry.Tool,
+			Description: doc.entry.Description,
+			InputSchema: doc.entry.InputSchema,
+			ServerName:  doc.entry.Server,
+			Score:       score,
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// tokenize lowercases text and splits it into words. Underscores,
+// hyphens and camelCase boundaries separate words, so "createIssue" and
+// "create_issue" both yield "create" and "issue".
+func tokenize(text string) []string {
+	var tokens []string
+	var word []rune
+	flush := func() {
+		if len(word) > 0 {
+			tokens = append(tokens, strings.ToLower(string(word)))
+			word = word[:0]
+		}
+	}
+
+	runes := []rune(text)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+		if unicode.IsUpper(r) && i > 0 && unicode.IsLower(runes[i-1]) {
+			flush()
+		}
+		word = append(word, r)
+	}
+	flush()
+	return tokens
+}
+
+// uniqueTokens tokenizes text and drops repeated words.
+func uniqueTokens(text string) []string {
+	seen := make(map[string]bool)
+	var tokens []string
+	for _, t := range tokenize(text) {
+		if !seen[t] {
+			seen[t] = true
+			tokens = append(tokens, t)
+		}
+	}
+	return tokens
+}
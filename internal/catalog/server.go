@@ -0,0 +1,214 @@
+package catalog
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/protocol"
+)
+
+// request is an incoming JSON-RPC request.
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      interface{}     `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// response is an outgoing JSON-RPC response.
+type response struct {
+	JSONRPC string      `json:"jsonrpc"`
+	ID      interface{} `json:"id"`
+	Result  interface{} `json:"result,omitempty"`
+	Error   *rpcError   `json:"error,omitempty"`
+}
+
+// rpcError is a JSON-RPC error object.
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Server exposes a Catalog over MCP stdio with the hub_search and
+// hub_execute meta-tools. hub_execute only returns instructions.
+type Server struct {
+	catalog *Catalog
+	version string
+}
+
+// NewServer creates a catalog MCP server reporting version in its
+// serverInfo.
+func NewServer(c *Catalog, version string) *Server {
+	return &Server{catalog: c, version: version}
+}
+
+// Serve reads newline-delimited JSON-RPC requests from r and writes
+// responses to w until r is exhausted. Notifications get no response.
+func (s *Server) Serve(r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	encoder := json.NewEncoder(w)
+
+	for scanner.Scan() {
+		resp := s.handle(scanner.Bytes())
+		if resp == nil {
+			continue
+		}
+		if err := encoder.Encode(resp); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// handle processes one request line.
+func (s *Server) handle(data []byte) *response {
+	var req request
+	if err := json.Unmarshal(data, &req); err != nil {
+		return &response{JSONRPC: "2.0", Error: &rpcError{Code: -32700, Message: "Parse error"}}
+	}
+	if req.ID == nil {
+		return nil
+	}
+
+	switch req.Method {
+	case "initialize":
+		var params struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		json.Unmarshal(req.Params, &params)
+		return s.result(req.ID, map[string]interface{}{
+			"protocolVersion": protocol.Negotiate(params.ProtocolVersion),
+			"capabilities": map[string]interface{}{
+				"tools": map[string]interface{}{},
+			},
+			"serverInfo": map[string]interface{}{
+				"name":    "tool-hub-catalog",
+				"version": s.version,
+			},
+		})
+	case "ping":
+		return s.result(req.ID, map[string]interface{}{})
+	case "tools/list":
+		return s.result(req.ID, map[string]interface{}{"tools": s.tools()})
+	case "tools/call":
+		return s.handleToolsCall(&req)
+	default:
+		return &response{JSONRPC: "2.0", ID: req.ID, Error: &rpcError{Code: -32601, Message: "Method not found"}}
+	}
+}
+
+// tools returns the meta-tool definitions.
+func (s *Server) tools() []map[string]interface{} {
+	servers := strings.Join(s.catalog.Servers(), ", ")
+	if servers == "" {
+		servers = "(none)"
+	}
+
+	return []map[string]interface{}{
+		{
+			"name": "hub_search",
+			"description": fmt.Sprintf(`Search a static catalog of %d tools from external integrations.
+
+Returns tool names, descriptions and input schemas ranked by relevance.
+
+CATALOGED SERVERS: %s`, s.catalog.Count(), servers),
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"query": map[string]interface{}{
+						"type":        "string",
+						"description": "What you want to do (natural language)",
+					},
+					"server": map[string]interface{}{
+						"type":        "string",
+						"description": "Optional: limit results to one server",
+					},
+					"limit": map[string]interface{}{
+						"type":        "integer",
+						"description": "Maximum results (default: 5)",
+					},
+				},
+				"required": []string{"query"},
+			},
+		},
+		{
+			"name": "hub_execute",
+			"description": `Explain how to run a cataloged tool.
+
+This catalog is search-only: the result describes how to execute the tool
+on a host running the full tool-hub-mcp.`,
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"server": map[string]interface{}{
+						"type":        "string",
+						"description": "Server name",
+					},
+					"tool": map[string]interface{}{
+						"type":        "string",
+						"description": "Tool name (from hub_search)",
+					},
+					"arguments": map[string]interface{}{
+						"type":        "object",
+						"description": "Tool arguments (schema from hub_search)",
+					},
+				},
+				"required": []string{"server", "tool"},
+			},
+		},
+	}
+}
+
+// handleToolsCall runs a meta-tool.
+func (s *Server) handleToolsCall(req *request) *response {
+	var params struct {
+		Name      string                 `json:"name"`
+		Arguments map[string]interface{} `json:"arguments"`
+	}
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return &response{JSONRPC: "2.0", ID: req.ID, Error: &rpcError{Code: -32602, Message: fmt.Sprintf("invalid params: %v", err)}}
+	}
+
+	var text string
+	switch params.Name {
+	case "hub_search":
+		query, _ := params.Arguments["query"].(string)
+		server, _ := params.Arguments["server"].(string)
+		limit := 5
+		if l, ok := params.Arguments["limit"].(float64); ok && l > 0 {
+			limit = int(l)
+		}
+		results := s.catalog.Search(query, server, limit)
+		data, err := json.Marshal(map[string]interface{}{
+			"query":   query,
+			"tools":   results,
+			"catalog": true,
+		})
+		if err != nil {
+			return &response{JSONRPC: "2.0", ID: req.ID, Error: &rpcError{Code: -32000, Message: err.Error()}}
+		}
+		text = string(data)
+	case "hub_execute":
+		server, _ := params.Arguments["server"].(string)
+		tool, _ := params.Arguments["tool"].(string)
+		args, _ := params.Arguments["arguments"].(map[string]interface{})
+		text = ExecutionInstructions(server, tool, args)
+	default:
+		return &response{JSONRPC: "2.0", ID: req.ID, Error: &rpcError{Code: -32602, Message: fmt.Sprintf("Unknown tool: %s", params.Name)}}
+	}
+
+	return s.result(req.ID, map[string]interface{}{
+		"content": []map[string]interface{}{
+			{"type": "text", "text": text},
+		},
+	})
+}
+
+// result wraps a successful result.
+func (s *Server) result(id interface{}, result interface{}) *response {
+	return &response{JSONRPC: "2.0", ID: id, Result: result}
+}
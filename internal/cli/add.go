@@ -2,27 +2,34 @@ package cli
 
 import (
 	"bufio"
-	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
 
 	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/redact"
+	"github.com/khanglvm/tool-hub-mcp/internal/registry"
 	"github.com/spf13/cobra"
 )
 
 // NewAddCmd creates the 'add' command for manually adding MCP servers.
 //
-// Supports two modes:
+// Supports three modes:
 // 1. Interactive: Paste MCP config JSON, auto-detect format, preview, confirm
 // 2. Flags: Specify --command, --arg, --env directly
+// 3. Registry: Resolve a server from the MCP registry (--from-registry)
 func NewAddCmd() *cobra.Command {
 	var (
 		command   string
+		cwd       string
+		shell     bool
 		args      []string
 		envVars   []string
 		jsonInput string
 		noConfirm bool
+
+		fromRegistry string
+		registryURL  string
 	)
 
 	cmd := &cobra.Command{
@@ -40,7 +47,12 @@ INTERACTIVE MODE (recommended):
   and ask for confirmation before saving.
 
 FLAG MODE:
-  Specify server details directly with flags.`,
+  Specify server details directly with flags.
+
+REGISTRY MODE:
+  Look the server up in the MCP server registry, resolve its install
+  command (npx, uvx or docker) and prompt for the environment variables
+  it needs. The name defaults to the registry name's last segment.`,
 		Example: `  # Interactive mode - paste JSON when prompted
   tool-hub-mcp add
 
@@ -50,6 +62,12 @@ FLAG MODE:
   # Flag mode - specify details directly
   tool-hub-mcp add jira --command "npx" --arg "-y" --arg "@lvmk/jira-mcp"
 
+  # A command with spaces is split like a shell would
+  tool-hub-mcp add jira --command "npx -y @lvmk/jira-mcp"
+
+  # Install from the MCP server registry
+  tool-hub-mcp add --from-registry io.github.owner/server
+
   # Paste full Claude Code config
   tool-hub-mcp add --json '{
     "mcpServers": {
@@ -58,30 +76,46 @@ FLAG MODE:
     }
   }'`,
 		RunE: func(cmd *cobra.Command, positionalArgs []string) error {
+			// One reader for every prompt, so input it buffers ahead
+			// (piped answers) reaches the next prompt
+			reader := bufio.NewReader(os.Stdin)
+
+			if fromRegistry != "" {
+				name := ""
+				if len(positionalArgs) > 0 {
+					name = positionalArgs[0]
+				}
+				return runAddFromRegistry(reader, fromRegistry, name, registryURL, envVars, noConfirm)
+			}
+
 			// If JSON provided or no name, use interactive/JSON mode
 			if jsonInput != "" || (len(positionalArgs) == 0 && command == "") {
-				return runAddInteractive(jsonInput, noConfirm)
+				return runAddInteractive(reader, jsonInput, noConfirm)
 			}
 
 			// Flag mode
 			if len(positionalArgs) == 0 {
 				return fmt.Errorf("server name required when using flag mode")
 			}
-			return runAddWithFlags(positionalArgs[0], command, args, envVars)
+			return runAddWithFlags(reader, positionalArgs[0], command, cwd, shell, args, envVars)
 		},
 	}
 
 	cmd.Flags().StringVarP(&command, "command", "c", "", "Command to run the MCP server")
+	cmd.Flags().BoolVar(&shell, "shell", false, "Run the command through the shell (sh -c) instead of splitting it")
+	cmd.Flags().StringVar(&cwd, "cwd", "", "Working directory for the MCP server (absolute path)")
 	cmd.Flags().StringArrayVarP(&args, "arg", "a", nil, "Arguments for the command")
 	cmd.Flags().StringArrayVarP(&envVars, "env", "e", nil, "Environment variables (KEY=VALUE)")
 	cmd.Flags().StringVarP(&jsonInput, "json", "j", "", "MCP config JSON (auto-detect format)")
 	cmd.Flags().BoolVarP(&noConfirm, "yes", "y", false, "Skip confirmation prompt")
+	cmd.Flags().StringVar(&fromRegistry, "from-registry", "", "Add a server from the MCP registry by name")
+	cmd.Flags().StringVar(&registryURL, "registry-url", "", "MCP registry to query (default: settings.registryUrl or the official registry)")
 
 	return cmd
 }
 
 // runAddInteractive handles JSON input mode with preview and confirmation.
-func runAddInteractive(jsonInput string, noConfirm bool) error {
+func runAddInteractive(reader *bufio.Reader, jsonInput string, noConfirm bool) error {
 	var input string
 
 	if jsonInput != "" {
@@ -92,14 +126,14 @@ func runAddInteractive(jsonInput string, noConfirm bool) error {
 		fmt.Println("   Supports: Claude Code, OpenCode, or single server format")
 		fmt.Println()
 
-		input = readMultilineInput()
+		input = readMultilineInput(reader)
 		if strings.TrimSpace(input) == "" {
 			return fmt.Errorf("no input provided")
 		}
 	}
 
 	// Parse and detect format
-	servers, format, err := parseAnyMCPConfig(input)
+	servers, format, err := config.ParseAnyMCPConfig(input)
 	if err != nil {
 		return fmt.Errorf("failed to parse config: %w", err)
 	}
@@ -120,7 +154,7 @@ func runAddInteractive(jsonInput string, noConfirm bool) error {
 			fmt.Printf(" (from '%s')", name)
 		}
 		fmt.Println()
-		fmt.Printf("    Command: %s %v\n", server.Command, server.Args)
+		fmt.Printf("    Command: %s %v\n", server.Command, redact.Args(server.Args))
 		if len(server.Env) > 0 {
 			fmt.Printf("    Env:     %d variable(s)\n", len(server.Env))
 		}
@@ -130,7 +164,6 @@ func runAddInteractive(jsonInput string, noConfirm bool) error {
 	// Confirm
 	if !noConfirm {
 		fmt.Print("Add these servers? [Y/n] ")
-		reader := bufio.NewReader(os.Stdin)
 		response, _ := reader.ReadString('\n')
 		response = strings.TrimSpace(strings.ToLower(response))
 
@@ -140,6 +173,11 @@ func runAddInteractive(jsonInput string, noConfirm bool) error {
 		}
 	}
 
+	// Ask for real values instead of saving README placeholders
+	for _, name := range sortedKeys(servers) {
+		fillPlaceholders(reader, config.ToCamelCase(name), servers[name], noConfirm || !stdinIsTerminal())
+	}
+
 	// Load existing config
 	cfg, err := config.Load()
 	if err != nil {
@@ -150,8 +188,9 @@ func runAddInteractive(jsonInput string, noConfirm bool) error {
 	addedCount := 0
 	skippedCount := 0
 
-	for name, server := range servers {
-		camelName := config.ToCamelCase(name)
+	for _, name := range sortedKeys(servers) {
+		server := servers[name]
+		camelName, exists := config.UniqueServerName(cfg.Servers, name)
 
 		// Validate server config
 		if err := config.ValidateServer(camelName, server); err != nil {
@@ -161,13 +200,15 @@ func runAddInteractive(jsonInput string, noConfirm bool) error {
 		}
 
 		// Check for duplicate
-		if _, exists := cfg.Servers[camelName]; exists {
+		if exists {
 			fmt.Printf("  ⚠️  Server '%s' already exists, skipping\n", camelName)
 			skippedCount++
 			continue
 		}
+		warnNameCollision(name, camelName)
 
 		server.Source = "manual"
+		server.SetOriginalName(camelName, name)
 		cfg.Servers[camelName] = server
 		addedCount++
 	}
@@ -193,154 +234,8 @@ func runAddInteractive(jsonInput string, noConfirm bool) error {
 	return nil
 }
 
-// parseAnyMCPConfig attempts to parse various MCP config formats intelligently.
-// Handles many variations including non-standard keys.
-// Returns servers map, detected format name, and error.
-func parseAnyMCPConfig(input string) (map[string]*config.ServerConfig, string, error) {
-	input = strings.TrimSpace(input)
-
-	// Parse as generic JSON first
-	var raw map[string]interface{}
-	if err := json.Unmarshal([]byte(input), &raw); err != nil {
-		return nil, "", fmt.Errorf("invalid JSON: %w", err)
-	}
-
-	// Try to find servers in various wrapper keys
-	wrapperKeys := []string{
-		"mcpServers", "mcp_servers", "MCP_SERVERS", "MCPServers",
-		"mcp", "MCP",
-		"servers", "Servers", "SERVERS",
-		"tools", "Tools", "TOOLS",
-		"context_servers", // Zed format
-	}
-
-	for _, key := range wrapperKeys {
-		if wrapped, ok := raw[key]; ok {
-			if serversMap, ok := wrapped.(map[string]interface{}); ok {
-				servers := parseServersMap(serversMap)
-				if len(servers) > 0 {
-					return servers, fmt.Sprintf("Wrapped (%s)", key), nil
-				}
-			}
-		}
-	}
-
-	// Try to parse as direct servers map: {"serverName": {...}, ...}
-	servers := parseServersMap(raw)
-	if len(servers) > 0 {
-		return servers, "Direct server map", nil
-	}
-
-	// Try single server object: {"command": "...", ...}
-	if server := parseSingleServer(raw); server != nil {
-		// Ask user for name since it's a single server
-		return map[string]*config.ServerConfig{"server": server}, "Single server object", nil
-	}
-
-	return nil, "", fmt.Errorf("could not find valid MCP server configuration")
-}
-
-// parseServersMap parses a map of server name -> server config.
-func parseServersMap(raw map[string]interface{}) map[string]*config.ServerConfig {
-	result := make(map[string]*config.ServerConfig)
-
-	for name, val := range raw {
-		if serverMap, ok := val.(map[string]interface{}); ok {
-			if server := parseSingleServer(serverMap); server != nil {
-				result[name] = server
-			}
-		}
-	}
-
-	return result
-}
-
-// parseSingleServer attempts to parse a single server config from a map.
-// Handles many key variations:
-//   - command: command, cmd, exec, executable, run, bin, binary
-//   - args: args, arguments, argv, params, parameters, options
-//   - env: env, environment, envVars, env_vars, envvars
-func parseSingleServer(raw map[string]interface{}) *config.ServerConfig {
-	// Find command (required)
-	command := findStringKey(raw,
-		"command", "cmd", "exec", "executable", "run", "bin", "binary",
-		"Command", "CMD", "Cmd")
-	if command == "" {
-		return nil
-	}
-
-	// Find args
-	args := findStringArrayKey(raw,
-		"args", "arguments", "argv", "params", "parameters", "options",
-		"Args", "Arguments", "ARGS")
-
-	// Find env
-	env := findStringMapKey(raw,
-		"env", "environment", "envVars", "env_vars", "envvars",
-		"Env", "Environment", "ENV")
-
-	return &config.ServerConfig{
-		Command: command,
-		Args:    args,
-		Env:     config.NormalizeEnvVars(env),
-	}
-}
-
-// findStringKey looks for a string value under any of the given keys.
-func findStringKey(m map[string]interface{}, keys ...string) string {
-	for _, key := range keys {
-		if val, ok := m[key]; ok {
-			if s, ok := val.(string); ok && s != "" {
-				return s
-			}
-		}
-	}
-	return ""
-}
-
-// findStringArrayKey looks for a string array under any of the given keys.
-func findStringArrayKey(m map[string]interface{}, keys ...string) []string {
-	for _, key := range keys {
-		if val, ok := m[key]; ok {
-			if arr, ok := val.([]interface{}); ok {
-				result := make([]string, 0, len(arr))
-				for _, item := range arr {
-					if s, ok := item.(string); ok {
-						result = append(result, s)
-					}
-				}
-				if len(result) > 0 {
-					return result
-				}
-			}
-		}
-	}
-	return nil
-}
-
-// findStringMapKey looks for a string map under any of the given keys.
-func findStringMapKey(m map[string]interface{}, keys ...string) map[string]string {
-	for _, key := range keys {
-		if val, ok := m[key]; ok {
-			if obj, ok := val.(map[string]interface{}); ok {
-				result := make(map[string]string)
-				for k, v := range obj {
-					if s, ok := v.(string); ok {
-						result[k] = s
-					}
-				}
-				if len(result) > 0 {
-					return result
-				}
-			}
-		}
-	}
-	return nil
-}
-
 // readMultilineInput reads input until two consecutive newlines.
-func readMultilineInput() string {
-	reader := bufio.NewReader(os.Stdin)
+func readMultilineInput(reader *bufio.Reader) string {
 	var lines []string
 	emptyCount := 0
 
@@ -367,7 +262,7 @@ func readMultilineInput() string {
 }
 
 // runAddWithFlags handles the traditional flag-based mode.
-func runAddWithFlags(name, command string, args, envVars []string) error {
+func runAddWithFlags(reader *bufio.Reader, name, command, cwd string, shell bool, args, envVars []string) error {
 	if command == "" {
 		return fmt.Errorf("--command is required")
 	}
@@ -392,11 +287,24 @@ func runAddWithFlags(name, command string, args, envVars []string) error {
 		Command: command,
 		Args:    args,
 		Env:     env,
+		Cwd:     cwd,
+		Shell:   shell,
 		Source:  "manual",
 	}
 
-	// Transform name to camelCase
-	camelName := config.ToCamelCase(name)
+	fillPlaceholders(reader, name, server, !stdinIsTerminal())
+
+	// Split "npx -y @pkg/mcp" into the command and its args
+	split, err := server.NormalizeCommand()
+	if err != nil {
+		return err
+	}
+	if split {
+		fmt.Printf("ℹ️  Split command into '%s' with args %v (use --shell to run it through the shell instead)\n", server.Command, redact.Args(server.Args))
+	}
+
+	// Transform name to camelCase, suffixing on collisions
+	camelName, exists := config.UniqueServerName(cfg.Servers, name)
 
 	// Validate server config
 	if err := config.ValidateServer(camelName, server); err != nil {
@@ -404,10 +312,12 @@ func runAddWithFlags(name, command string, args, envVars []string) error {
 	}
 
 	// Check for duplicate
-	if _, exists := cfg.Servers[camelName]; exists {
+	if exists {
 		return fmt.Errorf("server '%s' already exists", camelName)
 	}
+	warnNameCollision(name, camelName)
 
+	server.SetOriginalName(camelName, name)
 	cfg.Servers[camelName] = server
 
 	// Save config
@@ -427,6 +337,173 @@ func runAddWithFlags(name, command string, args, envVars []string) error {
 	return nil
 }
 
+// runAddFromRegistry adds a server resolved from the MCP registry, asking
+// for the environment variables its package declares. Values passed with
+// --env are used as-is; with --yes or without a terminal, required
+// variables must be passed that way.
+func runAddFromRegistry(reader *bufio.Reader, registryName, name, registryURL string, envVars []string, noConfirm bool) error {
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = config.NewConfig()
+	}
+	configureNetwork(cfg)
+	if registryURL == "" {
+		registryURL = cfg.Settings.Registry()
+	}
+
+	entry, err := registry.Lookup(registryURL, registryName)
+	if err != nil {
+		return err
+	}
+	server, vars, err := registry.Resolve(entry)
+	if err != nil {
+		return err
+	}
+
+	if name == "" {
+		name = registry.ShortName(entry.Name)
+	}
+	camelName, exists := config.UniqueServerName(cfg.Servers, name)
+	if exists {
+		return fmt.Errorf("server '%s' already exists", camelName)
+	}
+
+	fmt.Printf("📦 %s %s\n", entry.Name, entry.Version)
+	if entry.Description != "" {
+		fmt.Printf("   %s\n", entry.Description)
+	}
+	if server.IsRemote() {
+		fmt.Printf("   Remote:  %s (%s)\n", server.URL, server.Type)
+	} else if server.IsContainer() {
+		fmt.Printf("   Image:   %s %v\n", server.Container.Image, server.Args)
+	} else {
+		fmt.Printf("   Command: %s %v\n", server.Command, server.Args)
+	}
+	fmt.Println()
+
+	// Explicit --env values win over registry defaults and prompts
+	explicit := make(map[string]bool)
+	for _, e := range envVars {
+		if key, value := parseEnvVar(e); key != "" {
+			if server.Env == nil {
+				server.Env = make(map[string]string)
+			}
+			server.Env[key] = value
+			explicit[key] = true
+		}
+	}
+	if err := promptRegistryEnv(reader, server, vars, explicit, noConfirm || !stdinIsTerminal()); err != nil {
+		return err
+	}
+
+	if err := config.ValidateServer(camelName, server); err != nil {
+		return err
+	}
+
+	if !noConfirm {
+		fmt.Printf("Add server '%s'? [Y/n] ", camelName)
+		response, _ := reader.ReadString('\n')
+		response = strings.TrimSpace(strings.ToLower(response))
+		if response != "" && response != "y" && response != "yes" {
+			fmt.Println("Cancelled.")
+			return nil
+		}
+	}
+
+	redact.Secrets.AddEnv(server.Env)
+	server.Source = "registry"
+	server.SetOriginalName(camelName, name)
+	cfg.Servers[camelName] = server
+
+	configPath, err := config.GetDefaultConfigPath()
+	if err != nil {
+		return fmt.Errorf("failed to get config path: %w", err)
+	}
+	if err := config.Save(cfg, configPath); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	// Auto-regenerate tool index for bash/grep access
+	RegenerateIndex()
+
+	fmt.Printf("✓ Added server '%s' to %s\n", camelName, configPath)
+	return nil
+}
+
+// promptRegistryEnv asks for each variable the package declares that was
+// not passed with --env, hiding secrets. A variable already set in the
+// environment is offered as a ${NAME} reference, expanded at spawn time.
+// Unanswered optional variables are left out. When prompting is not
+// possible, missing required variables are an error naming the --env
+// flags to pass.
+func promptRegistryEnv(reader *bufio.Reader, server *config.ServerConfig, vars []registry.EnvVar, explicit map[string]bool, noPrompt bool) error {
+	if server.Env == nil {
+		server.Env = make(map[string]string)
+	}
+
+	var missing []string
+	for _, v := range vars {
+		if explicit[v.Name] {
+			continue
+		}
+		if _, ok := os.LookupEnv(v.Name); ok && server.Env[v.Name] == "" {
+			server.Env[v.Name] = "${" + v.Name + "}"
+		}
+		if noPrompt {
+			if server.Env[v.Name] == "" && v.IsRequired {
+				missing = append(missing, "--env "+v.Name+"=...")
+			}
+			continue
+		}
+
+		label := v.Name
+		if v.Description != "" {
+			label += " (" + v.Description + ")"
+		}
+		if !v.IsRequired {
+			label += " [optional]"
+		}
+		if value := promptValue(reader, label, server.Env[v.Name], v.IsSecret); value != "" {
+			server.Env[v.Name] = value
+		} else if v.IsRequired {
+			missing = append(missing, "--env "+v.Name+"=...")
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("missing required environment variables, pass %s", strings.Join(missing, " "))
+	}
+	if len(server.Env) == 0 {
+		server.Env = nil
+	}
+	return nil
+}
+
+// fillPlaceholders replaces env values that are placeholders copied from
+// a README (YOUR_API_KEY, <token>) with the real value, asked for on the
+// terminal and read without echo for secrets. Unanswered variables, or all
+// of them when prompting is not possible, become ${NAME} references that
+// are read from the environment when the server starts.
+func fillPlaceholders(reader *bufio.Reader, name string, server *config.ServerConfig, noPrompt bool) {
+	for _, key := range sortedKeys(server.Env) {
+		if !config.IsPlaceholder(server.Env[key]) {
+			continue
+		}
+
+		value := ""
+		if noPrompt {
+			fmt.Printf("  ⚠️  %s: %s is a placeholder (%s), reading it from $%s instead\n", name, key, server.Env[key], key)
+		} else {
+			fmt.Printf("🔑 %s: %s is a placeholder (%s)\n", name, key, server.Env[key])
+			value = promptValue(reader, "   Value for "+key+" (Enter to read $"+key+" from the environment)", "", redact.IsSecretEnvKey(key))
+		}
+		if value == "" {
+			value = "${" + key + "}"
+		}
+		server.Env[key] = value
+	}
+}
+
 // parseEnvVar splits "KEY=VALUE" into key and value.
 func parseEnvVar(s string) (string, string) {
 	for i, c := range s {
@@ -1,9 +1,13 @@
 package cli
 
 import (
+	"bufio"
 	"bytes"
 	"strings"
 	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/registry"
 )
 
 func TestNewAddCmd(t *testing.T) {
@@ -220,3 +224,69 @@ func TestAddCommandExamples(t *testing.T) {
 		}
 	}
 }
+
+func TestAddFromRegistryFlags(t *testing.T) {
+	cmd := NewAddCmd()
+	for _, flag := range []string{"from-registry", "registry-url"} {
+		if cmd.Flags().Lookup(flag) == nil {
+			t.Errorf("Flag %q not registered", flag)
+		}
+	}
+}
+
+func TestPromptRegistryEnvWithoutTerminal(t *testing.T) {
+	t.Setenv("JIRA_EMAIL", "me@acme.dev")
+	vars := []registry.EnvVar{
+		{Name: "JIRA_TOKEN", IsRequired: true, IsSecret: true},
+		{Name: "JIRA_URL", Default: "https://jira.acme.dev"},
+		{Name: "JIRA_EMAIL", IsRequired: true},
+		{Name: "JIRA_PROJECT"},
+	}
+
+	server := &config.ServerConfig{Command: "npx", Env: map[string]string{"JIRA_URL": "https://jira.acme.dev"}}
+	err := promptRegistryEnv(nil, server, vars, nil, true)
+	if err == nil || !strings.Contains(err.Error(), "--env JIRA_TOKEN=...") || strings.Contains(err.Error(), "JIRA_EMAIL") {
+		t.Fatalf("expected only JIRA_TOKEN to be reported missing, got %v", err)
+	}
+
+	server = &config.ServerConfig{Command: "npx", Env: map[string]string{"JIRA_TOKEN": "secret"}}
+	if err := promptRegistryEnv(nil, server, vars, map[string]bool{"JIRA_TOKEN": true}, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]string{"JIRA_TOKEN": "secret", "JIRA_EMAIL": "${JIRA_EMAIL}"}
+	if len(server.Env) != len(want) || server.Env["JIRA_TOKEN"] != "secret" || server.Env["JIRA_EMAIL"] != "${JIRA_EMAIL}" {
+		t.Errorf("Env = %v, want %v", server.Env, want)
+	}
+}
+
+func TestFillPlaceholdersWithoutTerminal(t *testing.T) {
+	server := &config.ServerConfig{Command: "npx", Env: map[string]string{
+		"API_KEY":  "YOUR_API_KEY",
+		"BASE_URL": "https://api.acme.dev",
+		"TOKEN":    "<your-token>",
+	}}
+
+	fillPlaceholders(nil, "acme", server, true)
+
+	want := map[string]string{"API_KEY": "${API_KEY}", "BASE_URL": "https://api.acme.dev", "TOKEN": "${TOKEN}"}
+	for key, value := range want {
+		if server.Env[key] != value {
+			t.Errorf("Env[%s] = %q, want %q", key, server.Env[key], value)
+		}
+	}
+}
+
+func TestFillPlaceholdersSharesReader(t *testing.T) {
+	// Piped answers are buffered by the first prompt; the second server
+	// must still get its own line
+	reader := bufio.NewReader(strings.NewReader("first-key\nsecond-key\n"))
+	jira := &config.ServerConfig{Command: "npx", Env: map[string]string{"JIRA_API_KEY": "YOUR_API_KEY"}}
+	outline := &config.ServerConfig{Command: "npx", Env: map[string]string{"OUTLINE_API_KEY": "<api-key>"}}
+
+	fillPlaceholders(reader, "jira", jira, false)
+	fillPlaceholders(reader, "outline", outline, false)
+
+	if jira.Env["JIRA_API_KEY"] != "first-key" || outline.Env["OUTLINE_API_KEY"] != "second-key" {
+		t.Errorf("answers = %q, %q; want first-key, second-key", jira.Env["JIRA_API_KEY"], outline.Env["OUTLINE_API_KEY"])
+	}
+}
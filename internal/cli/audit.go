@@ -0,0 +1,157 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+// NewAuditCmd creates the 'audit' command for reviewing hub_execute calls.
+func NewAuditCmd() *cobra.Command {
+	var (
+		server     string
+		tool       string
+		since      string
+		until      string
+		failedOnly bool
+		limit      int
+		format     string
+		outputFile string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Show the audit trail of tool executions",
+		Long: `List hub_execute calls recorded in the local audit log
+(~/.local/share/tool-hub-mcp/history.db), newest first.
+
+Each entry has the timestamp, server, tool, a SHA256 hash of the arguments,
+duration and outcome. Arguments themselves are only recorded (with secrets
+redacted) when "settings": {"audit": {"recordArguments": true}} is set.
+
+--since and --until accept a duration ago (e.g. 24h, 7d) or a date
+(YYYY-MM-DD or RFC3339).`,
+		Example: `  # Last 50 calls
+  tool-hub-mcp audit
+
+  # Failed Jira calls in the last day
+  tool-hub-mcp audit --server jira --failed --since 24h
+
+  # Export a week as JSON for compliance review
+  tool-hub-mcp audit --since 7d --limit 0 --format json -o audit.json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "table" && format != "json" {
+				return fmt.Errorf("unsupported format '%s' (use table or json)", format)
+			}
+
+			filter := storage.AuditFilter{
+				Server:     server,
+				Tool:       tool,
+				FailedOnly: failedOnly,
+				Limit:      limit,
+			}
+			var err error
+			if filter.Since, err = parseAuditTime(since); err != nil {
+				return fmt.Errorf("invalid --since: %w", err)
+			}
+			if filter.Until, err = parseAuditTime(until); err != nil {
+				return fmt.Errorf("invalid --until: %w", err)
+			}
+
+			store := storage.NewStorage()
+			if err := store.Init(); err != nil {
+				return fmt.Errorf("failed to initialize storage: %w", err)
+			}
+			defer store.Close()
+
+			entries, err := store.GetAuditEntries(filter)
+			if err != nil {
+				return fmt.Errorf("failed to load audit log: %w", err)
+			}
+
+			out := cmd.OutOrStdout()
+			if outputFile != "" {
+				file, err := os.Create(outputFile)
+				if err != nil {
+					return fmt.Errorf("failed to create output file: %w", err)
+				}
+				defer file.Close()
+				out = file
+			}
+
+			return writeAuditEntries(out, entries, format)
+		},
+	}
+
+	cmd.Flags().StringVarP(&server, "server", "s", "", "Only calls to this server")
+	cmd.Flags().StringVarP(&tool, "tool", "t", "", "Only calls to this tool")
+	cmd.Flags().StringVar(&since, "since", "", "Only calls after this time (e.g. 24h, 7d, 2026-01-31)")
+	cmd.Flags().StringVar(&until, "until", "", "Only calls before this time")
+	cmd.Flags().BoolVar(&failedOnly, "failed", false, "Only failed calls")
+	cmd.Flags().IntVarP(&limit, "limit", "n", 50, "Maximum entries (0 for all)")
+	cmd.Flags().StringVarP(&format, "format", "f", "table", "Output format: table or json")
+	cmd.Flags().StringVarP(&outputFile, "output", "o", "", "Output file (default: stdout)")
+
+	return cmd
+}
+
+// parseAuditTime parses a relative duration ("24h", "7d") as that long ago,
+// or an absolute date. An empty value yields the zero time.
+func parseAuditTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		var n int
+		if _, err := fmt.Sscanf(days, "%d", &n); err == nil && n >= 0 {
+			return time.Now().AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("'%s' is not a duration (24h, 7d) or date (YYYY-MM-DD)", value)
+}
+
+// writeAuditEntries writes entries as a table or JSON array.
+func writeAuditEntries(w io.Writer, entries []storage.AuditEntry, format string) error {
+	if format == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(entries)
+	}
+
+	if len(entries) == 0 {
+		fmt.Fprintln(w, "No audit entries found.")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TIME\tSERVER\tTOOL\tDURATION\tRESULT\tARGS")
+	for _, e := range entries {
+		result := "ok"
+		if !e.Success {
+			result = "error: " + e.Error
+			if len(result) > 60 {
+				result = result[:57] + "..."
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%dms\t%s\t%s\n",
+			e.Timestamp.Local().Format("2006-01-02 15:04:05"),
+			e.Server, e.Tool, e.DurationMs, result, e.ArgsHash[:min(12, len(e.ArgsHash))])
+	}
+	return tw.Flush()
+}
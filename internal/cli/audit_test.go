@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/storage"
+)
+
+func TestParseAuditTime(t *testing.T) {
+	if got, err := parseAuditTime(""); err != nil || !got.IsZero() {
+		t.Errorf("empty value = %v, %v", got, err)
+	}
+
+	got, err := parseAuditTime("7d")
+	if err != nil || time.Since(got) < 7*24*time.Hour-time.Minute {
+		t.Errorf("7d = %v, %v", got, err)
+	}
+
+	got, err = parseAuditTime("90m")
+	if err != nil || time.Since(got) < 89*time.Minute {
+		t.Errorf("90m = %v, %v", got, err)
+	}
+
+	got, err = parseAuditTime("2026-01-31")
+	if err != nil || got.Day() != 31 {
+		t.Errorf("date = %v, %v", got, err)
+	}
+
+	if _, err := parseAuditTime("yesterday"); err == nil {
+		t.Error("expected error for unparseable time")
+	}
+}
+
+func TestWriteAuditEntries(t *testing.T) {
+	entries := []storage.AuditEntry{
+		{Timestamp: time.Now(), Server: "jira", Tool: "search", ArgsHash: "abcdef0123456789", DurationMs: 42, Success: true},
+		{Timestamp: time.Now(), Server: "jira", Tool: "create", DurationMs: 7, Error: "denied"},
+	}
+
+	var table bytes.Buffer
+	if err := writeAuditEntries(&table, entries, "table"); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"SERVER", "42ms", "abcdef012345", "error: denied"} {
+		if !strings.Contains(table.String(), want) {
+			t.Errorf("table output missing %q:\n%s", want, table.String())
+		}
+	}
+
+	var js bytes.Buffer
+	if err := writeAuditEntries(&js, entries, "json"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(js.String(), `"duration_ms": 42`) {
+		t.Errorf("unexpected JSON output:\n%s", js.String())
+	}
+}
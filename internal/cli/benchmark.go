@@ -1,11 +1,17 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
 	"time"
 
 	"github.com/khanglvm/tool-hub-mcp/internal/benchmark"
 	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/protocol"
 	"github.com/khanglvm/tool-hub-mcp/internal/spawner"
 	"github.com/spf13/cobra"
 )
@@ -13,6 +19,8 @@ import (
 // NewBenchmarkCmd creates the 'benchmark' command for token efficiency testing.
 func NewBenchmarkCmd() *cobra.Command {
 	var jsonOutput bool
+	var live bool
+	var sortBy string
 
 	cmd := &cobra.Command{
 		Use:   "benchmark",
@@ -27,24 +35,34 @@ TOOL-HUB-MCP SETUP:
   Single aggregator exposing only 5 meta-tools.
   AI discovers and executes tools on-demand via hub_* commands.
 
-The benchmark estimates token savings based on your registered servers.`,
+Definition tokens are counted with real BPE tokenizers (per model) over the
+tool definitions in the exported index, or fetched from the live servers when
+there is no index. Servers without definitions fall back to estimates.`,
 		Example: `  # Run benchmark with current config
   tool-hub-mcp benchmark
 
   # Output as JSON
-  tool-hub-mcp benchmark --json`,
+  tool-hub-mcp benchmark --json
+
+  # Spawn every server and show which ones cost the most context
+  tool-hub-mcp benchmark --live --sort tokens`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runBenchmark(jsonOutput)
+			return runBenchmark(cmd.OutOrStdout(), jsonOutput, live, sortBy)
 		},
 	}
 
 	cmd.Flags().BoolVarP(&jsonOutput, "json", "j", false, "Output as JSON")
+	cmd.Flags().BoolVar(&live, "live", false, "Spawn each server and measure its real tools, with a per-server breakdown")
+	cmd.Flags().StringVar(&sortBy, "sort", "tokens", "Sort the --live breakdown by tokens, tools or name")
 
 	return cmd
 }
 
-// runBenchmark executes the token efficiency benchmark.
-func runBenchmark(jsonOutput bool) error {
+// runBenchmark executes the token efficiency benchmark. Tool definitions
+// come from the exported index, or from the live servers when there is
+// none or live is set; servers without definitions are estimated. Live
+// runs also break the cost down per server.
+func runBenchmark(w io.Writer, jsonOutput, live bool, sortBy string) error {
 	cfg, err := config.Load()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w\nRun 'tool-hub-mcp setup' first", err)
@@ -54,101 +72,169 @@ func runBenchmark(jsonOutput bool) error {
 		return fmt.Errorf("no servers configured. Run 'tool-hub-mcp setup' or 'tool-hub-mcp add' first")
 	}
 
-	// Run benchmark
-	result := benchmark.RunBenchmark(cfg)
+	var tools []ToolEntry
+	if !live {
+		tools = loadCachedTools(cfg)
+	}
+	if len(tools) == 0 {
+		tools = collectTools(cfg)
+	}
+	byServer := toolsByServer(tools)
 
-	// Also get actual token count for tool-hub-mcp definitions
-	actualToolHubTokens := benchmark.CountActualToolHubTokens()
+	var costs []benchmark.ServerCost
+	if live {
+		if costs, err = benchmark.Breakdown(byServer, sortBy); err != nil {
+			return err
+		}
+	}
+
+	// Run benchmark
+	result := benchmark.RunBenchmarkWithTools(cfg, byServer)
 
 	if jsonOutput {
-		// JSON output
-		fmt.Printf(`{
-  "traditional": {
-    "servers": %d,
-    "estimatedTools": %d,
-    "estimatedTokens": %d
-  },
-  "toolHub": {
-    "servers": 1,
-    "tools": 5,
-    "actualTokens": %d
-  },
-  "savings": {
-    "tokens": %d,
-    "percent": %.1f
-  }
-}
-`, result.Traditional.ServerCount, result.Traditional.ToolCount, result.Traditional.DefinitionTokens,
-			actualToolHubTokens, result.TokenSavings, result.SavingsPercent)
-	} else {
-		// Pretty output
-		fmt.Println()
-		fmt.Println("╔══════════════════════════════════════════════════════════════╗")
-		fmt.Println("║           TOKEN EFFICIENCY BENCHMARK RESULTS                 ║")
-		fmt.Println("╠══════════════════════════════════════════════════════════════╣")
-		fmt.Println("║                                                              ║")
-		fmt.Println("║  📊 TRADITIONAL MCP SETUP                                    ║")
-		fmt.Printf("║     Servers: %-3d                                             ║\n", result.Traditional.ServerCount)
-		fmt.Printf("║     Tools:   %-3d (actual/estimated per server)               ║\n", result.Traditional.ToolCount)
-		fmt.Printf("║     Tokens:  ~%-6d                                         ║\n", result.Traditional.DefinitionTokens)
-		fmt.Println("║                                                              ║")
-		fmt.Println("╠══════════════════════════════════════════════════════════════╣")
-		fmt.Println("║                                                              ║")
-		fmt.Println("║  🚀 TOOL-HUB-MCP SETUP                                       ║")
-		fmt.Printf("║     Servers: %-3d                                             ║\n", result.ToolHub.ServerCount)
-		fmt.Printf("║     Tools:   %-3d (hub_list, hub_discover, hub_search, ...)   ║\n", result.ToolHub.ToolCount)
-		fmt.Printf("║     Tokens:  %-6d (actual)                                  ║\n", actualToolHubTokens)
-		fmt.Println("║                                                              ║")
-		fmt.Println("╠══════════════════════════════════════════════════════════════╣")
-		fmt.Println("║                                                              ║")
-		fmt.Println("║  💰 SAVINGS                                                  ║")
-		fmt.Printf("║     Tokens saved:  ~%-6d                                    ║\n", result.TokenSavings)
-		fmt.Printf("║     Reduction:     %.1f%%                                      ║\n", result.SavingsPercent)
-		fmt.Println("║                                                              ║")
-		fmt.Println("╚══════════════════════════════════════════════════════════════╝")
-		fmt.Println()
-
-		// Show registered servers
-		fmt.Printf("Servers included in benchmark (%d):\n", len(cfg.Servers))
-		for name := range cfg.Servers {
-			fmt.Printf("  • %s\n", name)
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		output := map[string]interface{}{
+			"traditional": map[string]interface{}{
+				"servers":         result.Traditional.ServerCount,
+				"measuredServers": result.MeasuredServers,
+				"tools":           result.Traditional.ToolCount,
+				"tokens":          result.Traditional.DefinitionTokens,
+			},
+			"toolHub": map[string]interface{}{
+				"servers": 1,
+				"tools":   result.ToolHub.ToolCount,
+				"tokens":  result.ToolHub.DefinitionTokens,
+			},
+			"savings": map[string]interface{}{
+				"tokens":  result.TokenSavings,
+				"percent": result.SavingsPercent,
+			},
+			"models": result.Models,
+		}
+		if live {
+			output["servers"] = costs
 		}
-		fmt.Println()
+		return encoder.Encode(output)
+	}
+
+	// Pretty output
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "╔══════════════════════════════════════════════════════════════╗")
+	fmt.Fprintln(w, "║           TOKEN EFFICIENCY BENCHMARK RESULTS                 ║")
+	fmt.Fprintln(w, "╠══════════════════════════════════════════════════════════════╣")
+	fmt.Fprintln(w, "║                                                              ║")
+	fmt.Fprintln(w, "║  📊 TRADITIONAL MCP SETUP                                    ║")
+	fmt.Fprintf(w, "║     Servers: %-3d (%d measured)                               ║\n", result.Traditional.ServerCount, result.MeasuredServers)
+	fmt.Fprintf(w, "║     Tools:   %-3d (actual/estimated per server)               ║\n", result.Traditional.ToolCount)
+	fmt.Fprintf(w, "║     Tokens:  %-6d                                          ║\n", result.Traditional.DefinitionTokens)
+	fmt.Fprintln(w, "║                                                              ║")
+	fmt.Fprintln(w, "╠══════════════════════════════════════════════════════════════╣")
+	fmt.Fprintln(w, "║                                                              ║")
+	fmt.Fprintln(w, "║  🚀 TOOL-HUB-MCP SETUP                                       ║")
+	fmt.Fprintf(w, "║     Servers: %-3d                                             ║\n", result.ToolHub.ServerCount)
+	fmt.Fprintf(w, "║     Tools:   %-3d (hub_list, hub_discover, hub_search, ...)   ║\n", result.ToolHub.ToolCount)
+	fmt.Fprintf(w, "║     Tokens:  %-6d (actual)                                  ║\n", result.ToolHub.DefinitionTokens)
+	fmt.Fprintln(w, "║                                                              ║")
+	fmt.Fprintln(w, "╠══════════════════════════════════════════════════════════════╣")
+	fmt.Fprintln(w, "║                                                              ║")
+	fmt.Fprintln(w, "║  💰 SAVINGS                                                  ║")
+	fmt.Fprintf(w, "║     Tokens saved:  %-6d                                    ║\n", result.TokenSavings)
+	fmt.Fprintf(w, "║     Reduction:     %.1f%%                                      ║\n", result.SavingsPercent)
+	fmt.Fprintln(w, "║                                                              ║")
+	fmt.Fprintln(w, "╚══════════════════════════════════════════════════════════════╝")
+	fmt.Fprintln(w)
+
+	// Per-model breakdown
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "MODEL\tENCODING\tTRADITIONAL\tTOOL-HUB\tSAVED")
+	for _, m := range result.Models {
+		name := m.Name
+		if m.Approximate {
+			name += " (approx.)"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%.1f%%\n", name, m.Encoding, m.TraditionalTokens, m.ToolHubTokens, m.SavingsPercent)
+	}
+	tw.Flush()
+	fmt.Fprintln(w)
+
+	if live {
+		writeServerCosts(w, cfg, costs)
+		return nil
 	}
 
+	// Show registered servers
+	fmt.Fprintf(w, "Servers included in benchmark (%d):\n", len(cfg.Servers))
+	for name := range cfg.Servers {
+		fmt.Fprintf(w, "  • %s\n", name)
+	}
+	fmt.Fprintln(w)
+
 	return nil
 }
 
+// writeServerCosts prints the per-server breakdown and the servers that
+// could not be measured.
+func writeServerCosts(w io.Writer, cfg *config.Config, costs []benchmark.ServerCost) {
+	measured := make(map[string]bool, len(costs))
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SERVER\tTOOLS\tTOKENS\t% OF TOTAL")
+	for _, c := range costs {
+		measured[c.Server] = true
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.1f%%\n", c.Server, c.Tools, c.Tokens, c.Percent)
+	}
+	tw.Flush()
+
+	var failed []string
+	for name := range cfg.Servers {
+		if !measured[name] {
+			failed = append(failed, name)
+		}
+	}
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		fmt.Fprintf(w, "\nNot measured (failed to start): %s\n", strings.Join(failed, ", "))
+	}
+	fmt.Fprintln(w)
+}
+
 // NewSpeedBenchmarkCmd creates the 'benchmark speed' command for latency testing.
 func NewSpeedBenchmarkCmd() *cobra.Command {
 	var iterations int
+	var jsonOutput bool
 
 	cmd := &cobra.Command{
 		Use:   "speed",
 		Short: "Measure tool-hub-mcp internal latency",
-		Long: `Measure the time it takes for tool-hub-mcp to:
-1. Spawn a child MCP process
-2. Send a request (tools/list)
-3. Receive and parse the response
-
-This helps understand the overhead added by the aggregator pattern.`,
+		Long: `Measure, per server and with p50/p95/p99 over the iterations:
+1. Cold start: spawn a fresh child, initialize it and list its tools
+2. Warm latency: tools/list against the already running child
+3. Call round trip: tools/call against a no-op tool (ping, health, version)
+   when the server has one
+
+This helps understand the overhead added by the aggregator pattern.
+Use --json to track regressions in CI.`,
 		Example: `  # Run speed benchmark
   tool-hub-mcp benchmark speed
 
   # Run with more iterations
-  tool-hub-mcp benchmark speed --iterations 5`,
+  tool-hub-mcp benchmark speed --iterations 20
+
+  # Machine-readable output for CI
+  tool-hub-mcp benchmark speed --json`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runSpeedBenchmark(iterations)
+			return runSpeedBenchmark(cmd.OutOrStdout(), iterations, jsonOutput)
 		},
 	}
 
 	cmd.Flags().IntVarP(&iterations, "iterations", "n", 3, "Number of iterations per server")
+	cmd.Flags().BoolVarP(&jsonOutput, "json", "j", false, "Output as JSON")
 
 	return cmd
 }
 
-// runSpeedBenchmark measures internal latency for spawning and querying MCP servers.
-func runSpeedBenchmark(iterations int) error {
+// runSpeedBenchmark measures cold, warm and tools/call latency per server.
+func runSpeedBenchmark(w io.Writer, iterations int, jsonOutput bool) error {
 	cfg, err := config.Load()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
@@ -157,56 +243,123 @@ func runSpeedBenchmark(iterations int) error {
 	if len(cfg.Servers) == 0 {
 		return fmt.Errorf("no servers configured")
 	}
+	if iterations < 1 {
+		return fmt.Errorf("--iterations must be at least 1")
+	}
 
-	fmt.Println()
-	fmt.Println("╔══════════════════════════════════════════════════════════════╗")
-	fmt.Println("║              SPEED BENCHMARK (Internal Latency)              ║")
-	fmt.Println("╠══════════════════════════════════════════════════════════════╣")
-	fmt.Printf("║  Iterations per server: %-3d                                  ║\n", iterations)
-	fmt.Println("╚══════════════════════════════════════════════════════════════╝")
-	fmt.Println()
-
-	pool := spawner.NewPool(5)
-	totalTime := time.Duration(0)
-	successCount := 0
-
+	names := make([]string, 0, len(cfg.Servers))
 	for name, serverCfg := range cfg.Servers {
-		fmt.Printf("Testing: %s\n", name)
+		if !serverCfg.Disabled {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
 
-		var serverTotalTime time.Duration
-		var serverSuccess int
+	// Servers run one at a time so they don't skew each other's timings
+	results := make([]benchmark.ServerSpeed, 0, len(names))
+	for _, name := range names {
+		if !jsonOutput {
+			fmt.Fprintf(w, "Testing: %s\n", name)
+		}
+		results = append(results, measureServerSpeed(cfg, name, iterations))
+	}
 
-		for i := 0; i < iterations; i++ {
-			start := time.Now()
+	if jsonOutput {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(map[string]interface{}{
+			"iterations": iterations,
+			"servers":    results,
+		})
+	}
 
-			tools, err := pool.GetTools(name, serverCfg)
-			elapsed := time.Since(start)
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Latency per server (ms, %d iterations)\n\n", iterations)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SERVER\tCOLD p50/p95/p99\tWARM p50/p95/p99\tCALL p50/p95/p99")
+	for _, r := range results {
+		if r.Error != "" {
+			fmt.Fprintf(tw, "%s\tERROR: %s\t\t\n", r.Server, r.Error)
+			continue
+		}
+		call := "-"
+		if r.Call != nil {
+			call = formatPercentiles(*r.Call) + " (" + r.CallTool + ")"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", r.Server, formatPercentiles(r.Cold), formatPercentiles(r.Warm), call)
+	}
+	tw.Flush()
+	fmt.Fprintln(w)
 
-			if err != nil {
-				fmt.Printf("  Run %d: ERROR - %v\n", i+1, err)
-				continue
-			}
+	return nil
+}
 
-			serverTotalTime += elapsed
-			serverSuccess++
-			fmt.Printf("  Run %d: %v (%d tools discovered)\n", i+1, elapsed.Round(time.Millisecond), len(tools))
+// measureServerSpeed times cold starts with a fresh pool per iteration,
+// then warm tools/list and no-op tools/call round trips on one process.
+func measureServerSpeed(cfg *config.Config, name string, iterations int) benchmark.ServerSpeed {
+	serverCfg := cfg.Servers[name]
+	result := benchmark.ServerSpeed{Server: name}
+
+	var cold []time.Duration
+	var tools []protocol.Tool
+	for i := 0; i < iterations; i++ {
+		pool := spawner.NewPool(1)
+		pool.SetDefaultEnv(cfg.Settings.ChildEnv())
+
+		start := time.Now()
+		t, err := pool.GetTools(name, serverCfg)
+		elapsed := time.Since(start)
+		pool.Close()
+
+		if err != nil {
+			result.Error = err.Error()
+			return result
 		}
+		cold = append(cold, elapsed)
+		tools = t
+	}
+	result.Cold = benchmark.NewLatencyStats(cold)
+	result.Tools = len(tools)
+
+	pool := spawner.NewPool(1)
+	pool.SetDefaultEnv(cfg.Settings.ChildEnv())
+	defer pool.Close()
+	if err := pool.Prespawn(name, serverCfg); err != nil {
+		result.Error = err.Error()
+		return result
+	}
 
-		if serverSuccess > 0 {
-			avgTime := serverTotalTime / time.Duration(serverSuccess)
-			fmt.Printf("  Average: %v\n", avgTime.Round(time.Millisecond))
-			totalTime += serverTotalTime
-			successCount += serverSuccess
+	var warm []time.Duration
+	for i := 0; i < iterations; i++ {
+		start := time.Now()
+		if _, err := pool.GetTools(name, serverCfg); err != nil {
+			result.Error = err.Error()
+			return result
 		}
-		fmt.Println()
+		warm = append(warm, time.Since(start))
 	}
+	result.Warm = benchmark.NewLatencyStats(warm)
 
-	if successCount > 0 {
-		overallAvg := totalTime / time.Duration(successCount)
-		fmt.Println("═══════════════════════════════════════════════════════════════")
-		fmt.Printf("Overall Average Latency: %v\n", overallAvg.Round(time.Millisecond))
-		fmt.Println("═══════════════════════════════════════════════════════════════")
+	if tool := benchmark.FindNoopTool(tools); tool != "" {
+		var calls []time.Duration
+		for i := 0; i < iterations; i++ {
+			start := time.Now()
+			if _, err := pool.CallTool(name, serverCfg, tool, map[string]interface{}{}); err != nil {
+				continue
+			}
+			calls = append(calls, time.Since(start))
+		}
+		if len(calls) > 0 {
+			stats := benchmark.NewLatencyStats(calls)
+			result.CallTool = tool
+			result.Call = &stats
+		}
 	}
 
-	return nil
+	return result
+}
+
+// formatPercentiles renders p50/p95/p99 in milliseconds.
+func formatPercentiles(s benchmark.LatencyStats) string {
+	return fmt.Sprintf("%.1f/%.1f/%.1f", s.P50Ms, s.P95Ms, s.P99Ms)
 }
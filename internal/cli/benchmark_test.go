@@ -2,8 +2,15 @@ package cli
 
 import (
 	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/benchmark"
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/mcptest"
 )
 
 func TestNewBenchmarkCmd(t *testing.T) {
@@ -106,3 +113,140 @@ func TestBenchmarkCommandProperties(t *testing.T) {
 		t.Error("Command missing execution function")
 	}
 }
+
+func TestRunBenchmarkLive(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	cfg := config.NewConfig()
+	cfg.Servers["jira"] = mcptest.Server(t, mcptest.Handlers{
+		"initialize": mcptest.Result(`{}`),
+		"tools/list": mcptest.Result(`{"tools":[{"name":"get_issue","description":"Get a Jira issue by key","inputSchema":{"type":"object"}},{"name":"create_issue","description":"Create a Jira issue","inputSchema":{"type":"object"}}]}`),
+	})
+	cfg.Servers["broken"] = &config.ServerConfig{Command: "false"}
+	if err := config.Save(cfg, filepath.Join(home, ".tool-hub-mcp.json")); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := runBenchmark(&buf, false, true, "tokens"); err != nil {
+		t.Fatalf("runBenchmark --live failed: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "% OF TOTAL") || !strings.Contains(out, "100.0%") {
+		t.Errorf("expected per-server breakdown, got:\n%s", out)
+	}
+	if !strings.Contains(out, "Not measured (failed to start): broken") {
+		t.Errorf("expected broken server to be reported, got:\n%s", out)
+	}
+
+	buf.Reset()
+	if err := runBenchmark(&buf, true, true, "name"); err != nil {
+		t.Fatalf("runBenchmark --live --json failed: %v", err)
+	}
+	var result struct {
+		Servers []benchmark.ServerCost `json:"servers"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(result.Servers) != 1 || result.Servers[0].Server != "jira" || result.Servers[0].Tools != 2 {
+		t.Errorf("unexpected breakdown: %+v", result.Servers)
+	}
+
+	if err := runBenchmark(&buf, false, true, "cost"); err == nil {
+		t.Error("expected error for invalid --sort")
+	}
+}
+
+func TestRunSpeedBenchmark(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	cfg := config.NewConfig()
+	cfg.Servers["local"] = mcptest.Server(t, mcptest.Handlers{
+		"initialize": mcptest.Result(`{}`),
+		"tools/list": mcptest.Result(`{"tools":[{"name":"ping","description":"Health check","inputSchema":{"type":"object"}}]}`),
+		"tools/call": mcptest.Result(`{"content":[{"type":"text","text":"pong"}]}`),
+	})
+	if err := config.Save(cfg, filepath.Join(home, ".tool-hub-mcp.json")); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := runSpeedBenchmark(&buf, 3, true); err != nil {
+		t.Fatalf("runSpeedBenchmark failed: %v", err)
+	}
+	var report struct {
+		Servers []benchmark.ServerSpeed `json:"servers"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if len(report.Servers) != 1 {
+		t.Fatalf("expected 1 server, got %d", len(report.Servers))
+	}
+	s := report.Servers[0]
+	if s.Error != "" || s.Cold.Samples != 3 || s.Warm.Samples != 3 {
+		t.Errorf("unexpected cold/warm samples: %+v", s)
+	}
+	if s.CallTool != "ping" || s.Call == nil || s.Call.Samples != 3 {
+		t.Errorf("expected ping round trips, got %+v", s)
+	}
+
+	buf.Reset()
+	if err := runSpeedBenchmark(&buf, 1, false); err != nil {
+		t.Fatalf("runSpeedBenchmark failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "COLD p50/p95/p99") || !strings.Contains(buf.String(), "(ping)") {
+		t.Errorf("unexpected table output:\n%s", buf.String())
+	}
+}
+
+func TestRunSimulate(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	cfg := config.NewConfig()
+	cfg.Servers["jira"] = mcptest.Server(t, mcptest.Handlers{
+		"initialize": mcptest.Result(`{}`),
+		"tools/list": mcptest.Result(`{"tools":[{"name":"ping","description":"Health check","inputSchema":{"type":"object"}},{"name":"get_issue","description":"Get a Jira issue with all fields, comments and history","inputSchema":{"type":"object","properties":{"key":{"type":"string","description":"Issue key such as PROJ-123"}}}}]}`),
+		"tools/call": mcptest.Result(`{"content":[{"type":"text","text":"pong"}]}`),
+	})
+	if err := config.Save(cfg, filepath.Join(home, ".tool-hub-mcp.json")); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := runSimulate(&buf, 2, 1, "", true); err != nil {
+		t.Fatalf("runSimulate failed: %v", err)
+	}
+	var result benchmark.SimulationResult
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if result.Searches != 2 || result.Executions != 1 {
+		t.Errorf("expected 2 searches and 1 execution, got %+v", result)
+	}
+	if result.ToolHub.Turns != 4 || result.Traditional.Turns != 2 {
+		t.Errorf("unexpected turns: hub %d, traditional %d", result.ToolHub.Turns, result.Traditional.Turns)
+	}
+	if result.ToolHub.ExchangeTokens == 0 || result.Traditional.ExchangeTokens == 0 {
+		t.Errorf("expected exchanged tokens on both sides: %+v", result)
+	}
+
+	workflow := filepath.Join(home, "workflow.json")
+	os.WriteFile(workflow, []byte(`{"steps": [{"search": "jira issue"}, {"execute": {"server": "jira", "tool": "get_issue", "arguments": {"key": "PROJ-1"}}}]}`), 0644)
+	buf.Reset()
+	if err := runSimulate(&buf, 0, 0, workflow, false); err != nil {
+		t.Fatalf("runSimulate --script failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "1 searches, 1 executions") || !strings.Contains(buf.String(), "Tokens saved:") {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+
+	os.WriteFile(workflow, []byte(`{"steps": [{"execute": {"server": "missing", "tool": "x"}}]}`), 0644)
+	if err := runSimulate(&buf, 0, 0, workflow, false); err == nil {
+		t.Error("expected error for unknown server in script")
+	}
+}
@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/catalog"
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/mcp"
+	"github.com/khanglvm/tool-hub-mcp/internal/paths"
+	"github.com/spf13/cobra"
+)
+
+// NewCompletionCmd creates the 'completion' command that prints shell
+// completion scripts. Server and tool names complete dynamically from
+// the config and the exported tool index.
+func NewCompletionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "completion bash|zsh|fish|powershell",
+		Short: "Generate the shell completion script",
+		Long: `Print a completion script for your shell. Besides commands and flags,
+it completes registered server names (remove, rename, exec, verify --server,
+search --server) and the cached tool names of a server (exec <server> <TAB>).
+
+Bash (requires bash-completion):
+  source <(tool-hub-mcp completion bash)
+  # permanently, on Linux:
+  tool-hub-mcp completion bash > /etc/bash_completion.d/tool-hub-mcp
+
+Zsh:
+  tool-hub-mcp completion zsh > "${fpath[1]}/_tool-hub-mcp"
+
+Fish:
+  tool-hub-mcp completion fish > ~/.config/fish/completions/tool-hub-mcp.fish
+
+PowerShell:
+  tool-hub-mcp completion powershell | Out-String | Invoke-Expression`,
+		ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+		Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root := cmd.Root()
+			out := cmd.OutOrStdout()
+			switch args[0] {
+			case "bash":
+				return root.GenBashCompletionV2(out, true)
+			case "zsh":
+				return root.GenZshCompletion(out)
+			case "fish":
+				return root.GenFishCompletion(out, true)
+			case "powershell":
+				return root.GenPowerShellCompletionWithDesc(out)
+			default:
+				return fmt.Errorf("unsupported shell: %s", args[0])
+			}
+		},
+	}
+
+	return cmd
+}
+
+// completeServerNames completes the first positional argument with the
+// names of registered servers.
+func completeServerNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return serverNameCompletions(toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeServerFlag completes a --server flag value.
+func completeServerFlag(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return serverNameCompletions(toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeServerThenTool completes exec's arguments: a server name, then
+// one of that server's cached tool names.
+func completeServerThenTool(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	switch len(args) {
+	case 0:
+		return serverNameCompletions(toComplete), cobra.ShellCompDirectiveNoFileComp
+	case 1:
+		return toolNameCompletions(args[0], toComplete), cobra.ShellCompDirectiveNoFileComp
+	default:
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// serverNameCompletions returns registered server names starting with
+// prefix, with the server's command as the description.
+func serverNameCompletions(prefix string) []string {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil
+	}
+
+	var completions []string
+	for _, name := range sortedKeys(cfg.Servers) {
+		if strings.HasPrefix(name, prefix) {
+			completions = append(completions, name+"\t"+cfg.Servers[name].Command)
+		}
+	}
+	return completions
+}
+
+// toolNameCompletions returns a server's tool names starting with prefix.
+// Tools come from the cached server metadata or the exported index, so
+// completing never spawns a server.
+func toolNameCompletions(serverName, prefix string) []string {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil
+	}
+	key, ok := cfg.ResolveServer(serverName)
+	if !ok {
+		return nil
+	}
+
+	tools := make(map[string]string)
+	if metadata := cfg.Servers[key].Metadata; metadata != nil {
+		for _, tool := range metadata.Tools {
+			tools[tool] = ""
+		}
+	}
+	for _, entry := range readIndexQuietly() {
+		if entry.Server == key {
+			tools[entry.Tool] = mcp.FirstLine(entry.Description)
+		}
+	}
+
+	names := make([]string, 0, len(tools))
+	for name := range tools {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	completions := make([]string, len(names))
+	for i, name := range names {
+		completions[i] = name
+		if description := tools[name]; description != "" {
+			completions[i] += "\t" + description
+		}
+	}
+	return completions
+}
+
+// readIndexQuietly returns the exported index entries without the
+// warnings readExportedIndex prints, which would garble completions.
+func readIndexQuietly() []catalog.Entry {
+	indexPath, err := paths.IndexFile(".jsonl")
+	if err != nil {
+		return nil
+	}
+	file, err := os.Open(indexPath)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	entries, _ := catalog.Parse(file)
+	return entries
+}
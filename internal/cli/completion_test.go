@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func TestCompletionCmdGeneratesScripts(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish", "powershell"} {
+		root := &cobra.Command{Use: "tool-hub-mcp"}
+		root.AddCommand(NewCompletionCmd())
+		root.SetArgs([]string{"completion", shell})
+		var buf bytes.Buffer
+		root.SetOut(&buf)
+
+		if err := root.Execute(); err != nil {
+			t.Errorf("completion %s failed: %v", shell, err)
+		}
+		if !strings.Contains(buf.String(), "tool-hub-mcp") {
+			t.Errorf("completion %s produced no script", shell)
+		}
+	}
+
+	root := &cobra.Command{Use: "tool-hub-mcp"}
+	root.AddCommand(NewCompletionCmd())
+	root.SetArgs([]string{"completion", "tcsh"})
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(&bytes.Buffer{})
+	if err := root.Execute(); err == nil {
+		t.Error("expected error for an unsupported shell")
+	}
+}
+
+func TestCompleteServerThenTool(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("TOOL_HUB_HOME", "")
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_DATA_HOME", "")
+
+	cfg := config.NewConfig()
+	cfg.Servers["jira"] = &config.ServerConfig{
+		Command:  "npx",
+		Metadata: &config.ServerMetadata{Tools: []string{"search_issues", "get_issue", "create_issue"}},
+	}
+	cfg.Servers["github"] = &config.ServerConfig{Command: "uvx"}
+	configPath, err := config.GetDefaultConfigPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := config.Save(cfg, configPath); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := NewExecCmd()
+	servers, directive := completeServerThenTool(cmd, nil, "ji")
+	if !reflect.DeepEqual(servers, []string{"jira\tnpx"}) || directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("server completions = %v, %v", servers, directive)
+	}
+
+	tools, _ := completeServerThenTool(cmd, []string{"jira"}, "")
+	if !reflect.DeepEqual(tools, []string{"create_issue", "get_issue", "search_issues"}) {
+		t.Errorf("tool completions = %v", tools)
+	}
+	if tools, _ := completeServerThenTool(cmd, []string{"jira"}, "get"); !reflect.DeepEqual(tools, []string{"get_issue"}) {
+		t.Errorf("prefixed tool completions = %v", tools)
+	}
+	if tools, _ := completeServerThenTool(cmd, []string{"jira", "get_issue"}, ""); tools != nil {
+		t.Errorf("no completions expected after the tool, got %v", tools)
+	}
+}
@@ -0,0 +1,471 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/redact"
+	"github.com/spf13/cobra"
+)
+
+// NewConfigCmd creates the 'config' command group for working with the
+// tool-hub-mcp configuration file.
+func NewConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Show, edit, validate and export the tool-hub-mcp configuration",
+		Long: `Work with ~/.config/tool-hub-mcp/config.json.
+
+Commands:
+  show          Print the configuration with secrets redacted
+  edit          Edit the configuration in $EDITOR, validating before saving
+  validate      Check a configuration file (exits non-zero on problems)
+  schema        Print the JSON Schema of the configuration file
+  env-template  Emit a .env.example of the env vars the servers need
+  encrypt       Encrypt server env values at rest
+  decrypt       Store server env values in plaintext again`,
+	}
+
+	cmd.AddCommand(newConfigShowCmd())
+	cmd.AddCommand(newConfigEditCmd())
+	cmd.AddCommand(newConfigValidateCmd())
+	cmd.AddCommand(newConfigSchemaCmd())
+	cmd.AddCommand(newConfigEnvTemplateCmd())
+	cmd.AddCommand(newConfigEncryptCmd())
+	cmd.AddCommand(newConfigDecryptCmd())
+
+	return cmd
+}
+
+// newConfigShowCmd prints the loaded configuration with secrets redacted.
+func newConfigShowCmd() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "show",
+		Short: "Print the configuration with secrets redacted",
+		Long: `Print the servers and settings in the config file. Encrypted env values
+are decrypted, and secret env values and arguments are shown as [REDACTED].`,
+		Example: `  tool-hub-mcp config show
+  tool-hub-mcp config show --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runConfigShow(cmd.OutOrStdout(), jsonOutput)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&jsonOutput, "json", "j", false, "Output as JSON")
+
+	return cmd
+}
+
+// runConfigShow writes the redacted configuration as a summary or JSON.
+func runConfigShow(w io.Writer, jsonOutput bool) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	configPath, err := config.GetDefaultConfigPath()
+	if err != nil {
+		return err
+	}
+
+	shown := redactConfig(cfg)
+	if jsonOutput {
+		data, err := json.MarshalIndent(shown, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal config: %w", err)
+		}
+		fmt.Fprintln(w, string(data))
+		return nil
+	}
+
+	fmt.Fprintf(w, "Config: %s\n\n", configPath)
+	fmt.Fprintf(w, "Servers (%d):\n", len(shown.Servers))
+	for _, name := range sortedKeys(shown.Servers) {
+		server := shown.Servers[name]
+		fmt.Fprintf(w, "  %s", name)
+		if server.Disabled {
+			fmt.Fprint(w, " (disabled)")
+		}
+		fmt.Fprintln(w)
+		if server.IsRemote() {
+			fmt.Fprintf(w, "    URL:     %s\n", server.URL)
+		} else {
+			fmt.Fprintf(w, "    Command: %s\n", strings.TrimSpace(server.Command+" "+strings.Join(server.Args, " ")))
+		}
+		if len(server.Env) > 0 {
+			vars := make([]string, 0, len(server.Env))
+			for _, key := range sortedKeys(server.Env) {
+				vars = append(vars, key+"="+server.Env[key])
+			}
+			fmt.Fprintf(w, "    Env:     %s\n", strings.Join(vars, ", "))
+		}
+		if len(server.Headers) > 0 {
+			headers := make([]string, 0, len(server.Headers))
+			for _, key := range sortedKeys(server.Headers) {
+				headers = append(headers, key+": "+server.Headers[key])
+			}
+			fmt.Fprintf(w, "    Headers: %s\n", strings.Join(headers, ", "))
+		}
+		if len(server.Aliases) > 0 {
+			fmt.Fprintf(w, "    Aliases: %s\n", strings.Join(server.Aliases, ", "))
+		}
+		if server.Source != "" {
+			fmt.Fprintf(w, "    Source:  %s\n", server.Source)
+		}
+	}
+
+	if shown.Settings != nil {
+		data, err := json.MarshalIndent(shown.Settings, "  ", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal settings: %w", err)
+		}
+		fmt.Fprintf(w, "\nSettings:\n  %s\n", data)
+	}
+	return nil
+}
+
+// redactConfig returns a copy of cfg with secret env values and arguments
+// masked, for display.
+func redactConfig(cfg *config.Config) *config.Config {
+	shown := *cfg
+	shown.Servers = make(map[string]*config.ServerConfig, len(cfg.Servers))
+	for name, server := range cfg.Servers {
+		if server == nil {
+			continue
+		}
+		copied := *server
+		copied.Args = redact.Args(server.Args)
+		copied.Env = redact.Env(server.Env)
+		copied.Headers = redact.Env(server.Headers)
+		if server.URL != "" {
+			copied.URL = redact.URL(server.URL)
+		}
+		shown.Servers[name] = &copied
+	}
+	return &shown
+}
+
+// newConfigEditCmd opens the config file in the user's editor.
+func newConfigEditCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "edit",
+		Short: "Edit the configuration in $EDITOR, validating before saving",
+		Long: `Open a copy of the config file in $VISUAL or $EDITOR (default: vi). When
+the editor exits, the copy is validated like 'config validate' and saved
+with a backup (config.json.bak) only if it is valid; otherwise you can
+edit it again or keep the config unchanged. Encrypted env values stay
+encrypted; replace one with plaintext to change it.`,
+		Example: `  tool-hub-mcp config edit
+  EDITOR="code --wait" tool-hub-mcp config edit`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runConfigEdit(cmd.OutOrStdout(), cmd.InOrStdin(), editorCommand())
+		},
+	}
+}
+
+// editorCommand returns the user's editor: $VISUAL, $EDITOR, or a
+// platform default.
+func editorCommand() string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if editor := strings.TrimSpace(os.Getenv(env)); editor != "" {
+			return editor
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "vi"
+}
+
+// runConfigEdit edits a temporary copy of the config file and saves it
+// through config.SaveRaw once it validates. Invalid edits are reported and
+// the editor reopened until the user gives up; the copy is then kept so
+// no work is lost.
+func runConfigEdit(w io.Writer, in io.Reader, editor string) error {
+	configPath, err := config.GetDefaultConfigPath()
+	if err != nil {
+		return err
+	}
+
+	original, err := os.ReadFile(configPath)
+	if os.IsNotExist(err) {
+		original, _ = json.MarshalIndent(config.NewConfig(), "", "  ")
+	} else if err != nil {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+
+	tmp, err := os.CreateTemp("", "tool-hub-mcp-config-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	_, err = tmp.Write(original)
+	tmp.Close()
+	if err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+
+	reader := bufio.NewReader(in)
+	for {
+		if err := runEditor(editor, tmpPath); err != nil {
+			return fmt.Errorf("editor failed: %w (your edits are in %s)", err, tmpPath)
+		}
+
+		edited, err := os.ReadFile(tmpPath)
+		if err != nil {
+			return fmt.Errorf("failed to read edited config: %w", err)
+		}
+		if bytes.Equal(edited, original) {
+			os.Remove(tmpPath)
+			fmt.Fprintln(w, "No changes.")
+			return nil
+		}
+
+		err = config.SaveRaw(edited, configPath)
+		if err == nil {
+			os.Remove(tmpPath)
+			fmt.Fprintf(w, "✓ Saved %s (previous version in %s.bak)\n", configPath, configPath)
+			return nil
+		}
+
+		fmt.Fprintf(w, "%v\n\nEdit again? [Y/n] ", err)
+		response, _ := reader.ReadString('\n')
+		response = strings.TrimSpace(strings.ToLower(response))
+		if response != "" && response != "y" && response != "yes" {
+			return fmt.Errorf("config not saved; your edits are in %s", tmpPath)
+		}
+	}
+}
+
+// runEditor opens path in editor, which may include arguments
+// ("code --wait").
+func runEditor(editor, path string) error {
+	fields := strings.Fields(editor)
+	if len(fields) == 0 {
+		return fmt.Errorf("no editor configured, set $EDITOR")
+	}
+	cmd := exec.Command(fields[0], append(fields[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// newConfigValidateCmd checks a config file, e.g. in CI.
+func newConfigValidateCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate [file]",
+		Short: "Check a configuration file",
+		Long: `Check that a config file is valid JSON with a "servers" object and that
+every server passes the checks applied on import (non-empty command, no
+self-reference, valid rate limits). Every problem is listed and the
+command exits non-zero if any is found, so it can gate CI.
+
+Without a file, the current config file is checked.`,
+		Example: `  tool-hub-mcp config validate
+  tool-hub-mcp config validate ./team-config.json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := ""
+			if len(args) == 1 {
+				path = args[0]
+			}
+			return runConfigValidate(cmd.OutOrStdout(), path)
+		},
+	}
+}
+
+// runConfigValidate validates path, or the default config file when empty.
+func runConfigValidate(w io.Writer, path string) error {
+	if path == "" {
+		var err error
+		if path, err = config.GetDefaultConfigPath(); err != nil {
+			return err
+		}
+	}
+	if err := config.ValidateFile(path); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "✓ %s is valid\n", path)
+	return nil
+}
+
+// newConfigSchemaCmd prints the config file's JSON Schema.
+func newConfigSchemaCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "schema",
+		Short: "Print the JSON Schema of the configuration file",
+		Long: `Print the JSON Schema the config file is checked against when it is
+loaded, generated from tool-hub-mcp's config structures. Point an editor
+at it for completion and inline errors while editing the config.`,
+		Example: `  tool-hub-mcp config schema > tool-hub-mcp.schema.json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := config.Schema()
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(data))
+			return nil
+		},
+	}
+}
+
+// newConfigEnvTemplateCmd emits an env var manifest for CI and teammates.
+func newConfigEnvTemplateCmd() *cobra.Command {
+	var outputFile string
+
+	cmd := &cobra.Command{
+		Use:   "env-template",
+		Short: "Emit a .env.example listing env vars referenced by servers",
+		Long: `List every environment variable the configured servers depend on,
+without values, in .env format.
+
+Variables come from each server's "env" keys and from $VAR, ${VAR} and
+${env:VAR} references in its command, args and env values. CI pipelines
+and teammates can use the file to see which secrets to provision.`,
+		Example: `  # Print to stdout
+  tool-hub-mcp config env-template
+
+  # Write a .env.example for the repository
+  tool-hub-mcp config env-template -o .env.example`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			out := cmd.OutOrStdout()
+			if outputFile != "" {
+				file, err := os.Create(outputFile)
+				if err != nil {
+					return fmt.Errorf("failed to create output file: %w", err)
+				}
+				defer file.Close()
+				out = file
+			}
+
+			if err := writeEnvTemplate(out, cfg.EnvVarReferences()); err != nil {
+				return err
+			}
+			if outputFile != "" {
+				fmt.Fprintf(cmd.ErrOrStderr(), "✓ Wrote env template to %s\n", outputFile)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&outputFile, "output", "o", "", "Output file (default: stdout)")
+
+	return cmd
+}
+
+// newConfigEncryptCmd turns on settings.secrets.encrypt and rewrites the
+// config so existing env values are encrypted.
+func newConfigEncryptCmd() *cobra.Command {
+	var keychain bool
+	var keyFile string
+
+	cmd := &cobra.Command{
+		Use:   "encrypt",
+		Short: "Encrypt server env values in the config file",
+		Long: `Encrypt every server env value with AES-256-GCM and keep it encrypted on
+future saves. Loading the config decrypts values transparently.
+
+The key is generated on first use and stored in secret.key next to the
+config file (mode 0600), in --key-file, or with --keychain in the macOS
+Keychain or the Linux Secret Service (secret-tool). Losing the key means
+losing the encrypted values.`,
+		Example: `  tool-hub-mcp config encrypt
+  tool-hub-mcp config encrypt --keychain`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			secrets := &config.SecretSettings{Encrypt: true, KeyFile: keyFile}
+			if keychain {
+				secrets.KeySource = config.KeySourceKeychain
+			}
+			return runConfigSecrets(cmd.OutOrStdout(), secrets)
+		},
+	}
+
+	cmd.Flags().BoolVar(&keychain, "keychain", false, "Store the key in the OS keychain instead of a key file")
+	cmd.Flags().StringVar(&keyFile, "key-file", "", "Key file path (default: secret.key next to the config)")
+
+	return cmd
+}
+
+// newConfigDecryptCmd turns encryption off and rewrites env values in plaintext.
+func newConfigDecryptCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "decrypt",
+		Short: "Store server env values in plaintext again",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runConfigSecrets(cmd.OutOrStdout(), nil)
+		},
+	}
+}
+
+// runConfigSecrets loads the config (decrypting it with the current key),
+// applies the new secret settings and saves it, which re-encrypts or
+// writes plaintext accordingly.
+func runConfigSecrets(w io.Writer, secrets *config.SecretSettings) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	configPath, err := config.GetDefaultConfigPath()
+	if err != nil {
+		return err
+	}
+
+	if cfg.Settings == nil {
+		cfg.Settings = &config.Settings{}
+	}
+	cfg.Settings.Secrets = secrets
+	if err := config.Save(cfg, configPath); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	count := 0
+	for _, server := range cfg.Servers {
+		count += len(server.Env)
+	}
+	if secrets != nil {
+		fmt.Fprintf(w, "✓ Encrypted %d env value(s) in %s\n", count, configPath)
+	} else {
+		fmt.Fprintf(w, "✓ Stored %d env value(s) in plaintext in %s\n", count, configPath)
+	}
+	return nil
+}
+
+// writeEnvTemplate writes variables sorted by name, each preceded by a
+// comment naming the servers that use it. Values are always left empty.
+func writeEnvTemplate(w io.Writer, refs map[string][]string) error {
+	names := make([]string, 0, len(refs))
+	for name := range refs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString("# Environment variables required by tool-hub-mcp servers\n")
+	b.WriteString("# Generated by `tool-hub-mcp config env-template`; fill in values, do not commit secrets.\n")
+	if len(names) == 0 {
+		b.WriteString("\n# No server references environment variables.\n")
+	}
+	for _, name := range names {
+		fmt.Fprintf(&b, "\n# Used by: %s\n%s=\n", strings.Join(refs[name], ", "), name)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,194 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+)
+
+func TestConfigCmdHasEnvTemplate(t *testing.T) {
+	cmd := NewConfigCmd()
+	if sub, _, err := cmd.Find([]string{"env-template"}); err != nil || sub.Name() != "env-template" {
+		t.Fatalf("env-template subcommand not registered: %v", err)
+	}
+}
+
+func TestWriteEnvTemplate(t *testing.T) {
+	var buf bytes.Buffer
+	refs := map[string][]string{
+		"JIRA_TOKEN":   {"jira"},
+		"SHARED_TOKEN": {"github", "jira"},
+	}
+	if err := writeEnvTemplate(&buf, refs); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "# Used by: github, jira\nSHARED_TOKEN=\n") {
+		t.Errorf("missing shared variable entry:\n%s", out)
+	}
+	if strings.Index(out, "JIRA_TOKEN=") > strings.Index(out, "SHARED_TOKEN=") {
+		t.Errorf("variables should be sorted:\n%s", out)
+	}
+
+	buf.Reset()
+	writeEnvTemplate(&buf, map[string][]string{})
+	if !strings.Contains(buf.String(), "No server references") {
+		t.Errorf("expected empty-config note:\n%s", buf.String())
+	}
+}
+
+func TestRunConfigSecrets(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("TOOL_HUB_HOME", "")
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	configPath, err := config.GetDefaultConfigPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.NewConfig()
+	cfg.Servers["jira"] = &config.ServerConfig{Command: "npx", Env: map[string]string{"JIRA_TOKEN": "s3cr3t"}}
+	if err := config.Save(cfg, configPath); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := runConfigSecrets(&buf, &config.SecretSettings{Encrypt: true}); err != nil {
+		t.Fatalf("encrypt failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Encrypted 1 env value") {
+		t.Errorf("unexpected output: %s", buf.String())
+	}
+	if data, _ := os.ReadFile(configPath); strings.Contains(string(data), "s3cr3t") {
+		t.Errorf("env value still in plaintext:\n%s", data)
+	}
+
+	loaded, err := config.Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if loaded.Servers["jira"].Env["JIRA_TOKEN"] != "s3cr3t" {
+		t.Errorf("Load() did not decrypt env value: %v", loaded.Servers["jira"].Env)
+	}
+
+	buf.Reset()
+	if err := runConfigSecrets(&buf, nil); err != nil {
+		t.Fatalf("decrypt failed: %v", err)
+	}
+	if data, _ := os.ReadFile(configPath); !strings.Contains(string(data), "s3cr3t") {
+		t.Errorf("expected plaintext after decrypt:\n%s", data)
+	}
+}
+
+func TestRunConfigShowRedactsSecrets(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("TOOL_HUB_HOME", "")
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	configPath, err := config.GetDefaultConfigPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.NewConfig()
+	cfg.Servers["jira"] = &config.ServerConfig{
+		Command: "npx",
+		Args:    []string{"--token", "tok-arg-value"},
+		Env:     map[string]string{"JIRA_TOKEN": "s3cr3t-value", "REGION": "us"},
+	}
+	if err := config.Save(cfg, configPath); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	for _, jsonOutput := range []bool{false, true} {
+		var buf bytes.Buffer
+		if err := runConfigShow(&buf, jsonOutput); err != nil {
+			t.Fatalf("runConfigShow(%v) failed: %v", jsonOutput, err)
+		}
+		out := buf.String()
+		if strings.Contains(out, "s3cr3t-value") || strings.Contains(out, "tok-arg-value") {
+			t.Errorf("secrets leaked (json=%v):\n%s", jsonOutput, out)
+		}
+		if !strings.Contains(out, "REGION") || !strings.Contains(out, "us") {
+			t.Errorf("non-secret env missing (json=%v):\n%s", jsonOutput, out)
+		}
+	}
+}
+
+func TestRunConfigValidate(t *testing.T) {
+	dir := t.TempDir()
+	valid := dir + "/valid.json"
+	invalid := dir + "/invalid.json"
+	os.WriteFile(valid, []byte(`{"servers": {"jira": {"command": "npx"}}}`), 0644)
+	os.WriteFile(invalid, []byte(`{"servers": {"a": {"command": ""}, "b": {"command": ""}}}`), 0644)
+
+	var buf bytes.Buffer
+	if err := runConfigValidate(&buf, valid); err != nil {
+		t.Errorf("valid config failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "is valid") {
+		t.Errorf("unexpected output: %s", buf.String())
+	}
+
+	err := runConfigValidate(&buf, invalid)
+	if err == nil {
+		t.Fatal("expected error for invalid config")
+	}
+	if !strings.Contains(err.Error(), "server 'a'") || !strings.Contains(err.Error(), "server 'b'") {
+		t.Errorf("expected every problem listed, got: %v", err)
+	}
+}
+
+func TestRunConfigEdit(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("TOOL_HUB_HOME", "")
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	configPath, err := config.GetDefaultConfigPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.NewConfig()
+	cfg.Servers["jira"] = &config.ServerConfig{Command: "npx"}
+	if err := config.Save(cfg, configPath); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	// A fake editor that replaces the file with the given contents
+	writeEditor := func(contents string) string {
+		script := t.TempDir() + "/editor.sh"
+		body := "#!/bin/sh\ncat > \"$1\" <<'JSON'\n" + contents + "\nJSON\n"
+		if err := os.WriteFile(script, []byte(body), 0755); err != nil {
+			t.Fatal(err)
+		}
+		return script
+	}
+
+	// Invalid edits are rejected and the config is left unchanged
+	var buf bytes.Buffer
+	err = runConfigEdit(&buf, strings.NewReader("n\n"), writeEditor(`{"servers": {"jira": {"command": ""}}}`))
+	if err == nil || !strings.Contains(err.Error(), "config not saved") {
+		t.Errorf("expected invalid edit to be rejected, got %v", err)
+	}
+	if loaded, _ := config.Load(); loaded.Servers["jira"].Command != "npx" {
+		t.Error("invalid edit should not be saved")
+	}
+
+	buf.Reset()
+	if err := runConfigEdit(&buf, strings.NewReader(""), writeEditor(`{"servers": {"jira": {"command": "uvx"}}}`)); err != nil {
+		t.Fatalf("valid edit failed: %v", err)
+	}
+	loaded, err := config.Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if loaded.Servers["jira"].Command != "uvx" {
+		t.Errorf("edit not saved: %+v", loaded.Servers["jira"])
+	}
+	if _, err := os.Stat(configPath + ".bak"); err != nil {
+		t.Errorf("expected a backup: %v", err)
+	}
+}
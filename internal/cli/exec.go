@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/spawner"
+	"github.com/spf13/cobra"
+)
+
+// NewExecCmd creates the 'exec' command for calling a child tool directly.
+func NewExecCmd() *cobra.Command {
+	var argsJSON string
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "exec <server> <tool>",
+		Short: "Run a tool of an MCP server directly",
+		Long: `Spawn an MCP server, call one of its tools and print the result.
+
+Useful for checking credentials and debugging a child server outside an AI
+client. Text content is printed as-is; use --json for the raw tools/call result.`,
+		Example: `  tool-hub-mcp exec jira get_issue --args '{"key":"JIRA-123"}'
+  tool-hub-mcp exec github list_repositories --json`,
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeServerThenTool,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runExec(cmd.OutOrStdout(), args[0], args[1], argsJSON, jsonOutput)
+		},
+	}
+
+	cmd.Flags().StringVar(&argsJSON, "args", "{}", "Tool arguments as a JSON object")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Print the raw tools/call result as JSON")
+
+	return cmd
+}
+
+// runExec calls a tool on a child server and prints its result. A result
+// the tool flags with isError is printed and reported as an error.
+func runExec(w io.Writer, serverName, toolName, argsJSON string, jsonOutput bool) error {
+	var args map[string]interface{}
+	if err := json.Unmarshal([]byte(argsJSON), &args); err != nil {
+		return fmt.Errorf("--args must be a JSON object: %w", err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	key, ok := cfg.ResolveServer(serverName)
+	if !ok {
+		return fmt.Errorf("server '%s' not found", serverName)
+	}
+
+	pool := spawner.NewPool(1)
+	pool.SetDefaultEnv(cfg.Settings.ChildEnv())
+	defer pool.Close()
+
+	result, err := pool.CallTool(key, cfg.Servers[key], toolName, args)
+	if err != nil {
+		return fmt.Errorf("failed to call %s/%s: %w", key, toolName, err)
+	}
+
+	var decoded struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+		IsError bool `json:"isError"`
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to encode result: %w", err)
+	}
+	json.Unmarshal(data, &decoded)
+
+	if jsonOutput {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(result); err != nil {
+			return err
+		}
+	} else {
+		for _, block := range decoded.Content {
+			if block.Type == "text" {
+				fmt.Fprintln(w, block.Text)
+			} else {
+				fmt.Fprintf(w, "[%s content omitted; use --json to see it]\n", block.Type)
+			}
+		}
+	}
+
+	if decoded.IsError {
+		return fmt.Errorf("%s/%s reported an error", key, toolName)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/mcptest"
+)
+
+func TestRunExec(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	cfg := config.NewConfig()
+	cfg.Servers["jira"] = mcptest.Server(t, mcptest.Handlers{
+		"initialize": mcptest.Result(`{}`),
+		"tools/call": `case "$line" in
+        *'"name":"fail"'*) ` + mcptest.Result(`{"content":[{"type":"text","text":"bad key"}],"isError":true}`) + ` ;;
+        *) ` + mcptest.Result(`{"content":[{"type":"text","text":"JIRA-123: Fix login"}]}`) + ` ;;
+      esac`,
+	})
+	if err := config.Save(cfg, filepath.Join(home, ".tool-hub-mcp.json")); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := runExec(&buf, "jira", "get_issue", `{"key":"JIRA-123"}`, false); err != nil {
+		t.Fatalf("runExec failed: %v", err)
+	}
+	if strings.TrimSpace(buf.String()) != "JIRA-123: Fix login" {
+		t.Errorf("unexpected output: %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := runExec(&buf, "jira", "get_issue", `{}`, true); err != nil {
+		t.Fatalf("runExec --json failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"content"`) {
+		t.Errorf("expected raw result, got %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := runExec(&buf, "jira", "fail", `{}`, false); err == nil || !strings.Contains(buf.String(), "bad key") {
+		t.Errorf("expected tool error with output, got %v, %q", err, buf.String())
+	}
+
+	if err := runExec(&buf, "jira", "get_issue", `not json`, false); err == nil {
+		t.Error("expected error for invalid --args")
+	}
+	if err := runExec(&buf, "missing", "get_issue", `{}`, false); err == nil {
+		t.Error("expected error for unknown server")
+	}
+}
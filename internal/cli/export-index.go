@@ -1,12 +1,16 @@
 package cli
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 
+	"github.com/khanglvm/tool-hub-mcp/internal/catalog"
 	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/paths"
 	"github.com/khanglvm/tool-hub-mcp/internal/spawner"
 	"github.com/spf13/cobra"
 	"golang.org/x/sys/unix"
@@ -24,17 +28,21 @@ type ToolEntry struct {
 func NewExportIndexCmd() *cobra.Command {
 	var format string
 	var output string
+	var fields []string
 
 	cmd := &cobra.Command{
 		Use:   "export-index",
 		Short: "Export tool index for bash/grep search",
-		Long: `Generate ~/.tool-hub-mcp-index.jsonl with all tools for offline grep/jq searching.
+		Long: `Generate ~/.local/share/tool-hub-mcp/index.jsonl with all tools for offline grep/jq searching.
 
 This command creates a local index file containing tool metadata from all registered
 MCP servers. The index enables fast command-line searches without MCP overhead.
 
-Default output: ~/.tool-hub-mcp-index.jsonl
-Default format: JSONL (one tool per line)`,
+Default output: ~/.local/share/tool-hub-mcp/index.jsonl
+Default format: JSONL (one tool per line)
+
+The csv and markdown formats export a table for spreadsheets and docs, with
+the columns chosen by --fields (tool, server, description, schema).`,
 		Example: `  # Export to default location
   tool-hub-mcp export-index
 
@@ -44,31 +52,43 @@ Default format: JSONL (one tool per line)`,
   # Custom output path
   tool-hub-mcp export-index --output ./tools.jsonl
 
+  # Markdown table of tool names and descriptions
+  tool-hub-mcp export-index --format markdown --fields tool,description --output ./TOOLS.md
+
+  # Spreadsheet with input schemas
+  tool-hub-mcp export-index --format csv --fields server,tool,schema --output ./tools.csv
+
 Grep usage examples:
   # Find Jira tools
-  grep '"jira"' ~/.tool-hub-mcp-index.jsonl
+  grep '"jira"' ~/.local/share/tool-hub-mcp/index.jsonl
 
   # Search descriptions
-  grep -i "search" ~/.tool-hub-mcp-index.jsonl | jq -r '.tool'
+  grep -i "search" ~/.local/share/tool-hub-mcp/index.jsonl | jq -r '.tool'
 
   # Extract all tool names
-  cat ~/.tool-hub-mcp-index.jsonl | jq -r '.tool'
+  cat ~/.local/share/tool-hub-mcp/index.jsonl | jq -r '.tool'
 
   # Count tools per server
-  cat ~/.tool-hub-mcp-index.jsonl | jq -r '.server' | sort | uniq -c`,
+  cat ~/.local/share/tool-hub-mcp/index.jsonl | jq -r '.server' | sort | uniq -c`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runExportIndex(format, output)
+			return runExportIndex(format, output, fields)
 		},
 	}
 
-	cmd.Flags().StringVar(&format, "format", "jsonl", "Output format: json or jsonl")
-	cmd.Flags().StringVar(&output, "output", "", "Output path (default: ~/.tool-hub-mcp-index.jsonl)")
+	cmd.Flags().StringVar(&format, "format", "jsonl", "Output format: jsonl, json, csv or markdown")
+	cmd.Flags().StringSliceVar(&fields, "fields", nil, "Columns for csv and markdown: tool, server, description, schema (default tool,server,description)")
+	cmd.Flags().StringVar(&output, "output", "", "Output path (default: ~/.local/share/tool-hub-mcp/index.jsonl)")
 
 	return cmd
 }
 
 // runExportIndex executes the export-index command.
-func runExportIndex(format, output string) error {
+func runExportIndex(format, output string, fields []string) error {
+	columns, err := parseExportOptions(format, fields)
+	if err != nil {
+		return err
+	}
+
 	// Load config
 	cfg, err := config.Load()
 	if err != nil {
@@ -83,15 +103,10 @@ func runExportIndex(format, output string) error {
 
 	// Default output path
 	if output == "" {
-		home, err := os.UserHomeDir()
+		output, err = paths.IndexFile(exportFormats[format])
 		if err != nil {
-			return fmt.Errorf("failed to get home directory: %w", err)
+			return err
 		}
-		ext := ".jsonl"
-		if format == "json" {
-			ext = ".json"
-		}
-		output = filepath.Join(home, ".tool-hub-mcp-index"+ext)
 	}
 
 	// Acquire file lock to prevent concurrent writes
@@ -101,64 +116,132 @@ func runExportIndex(format, output string) error {
 	}
 	defer releaseFileLock(lockFile)
 
-	// Create spawner pool
-	pool := spawner.NewPool(cfg.Settings.ProcessPoolSize)
-	defer pool.Close()
+	// Stream tools to the file one server at a time
+	return writeIndexFrom(serverTools(cfg), output, format, columns)
+}
 
-	// Collect tools from all servers
-	var allTools []ToolEntry
-	for name, serverCfg := range cfg.Servers {
-		tools, err := pool.GetTools(name, serverCfg)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to fetch tools from %s: %v\n", name, err)
-			continue
-		}
+// toolSource passes tool entries to fn one at a time, stopping at the
+// first error fn returns.
+type toolSource func(fn func(ToolEntry) error) error
+
+// serverTools spawns every configured server in turn and passes on its
+// tools. Servers that fail are reported on stderr and skipped.
+func serverTools(cfg *config.Config) toolSource {
+	return func(fn func(ToolEntry) error) error {
+		pool := spawner.NewPool(cfg.Settings.ProcessPoolLimit())
+		pool.SetDefaultEnv(cfg.Settings.ChildEnv())
+		defer pool.Close()
+
+		for name, serverCfg := range cfg.Servers {
+			tools, err := pool.DiscoverTools(name, serverCfg)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to fetch tools from %s: %v\n", name, err)
+				continue
+			}
 
-		for _, tool := range tools {
-			allTools = append(allTools, ToolEntry{
-				Tool:        tool.Name,
-				Server:      name,
-				Description: tool.Description,
-				InputSchema: tool.InputSchema,
-			})
+			for _, tool := range tools {
+				err := fn(ToolEntry{
+					Tool:        tool.Name,
+					Server:      name,
+					Description: tool.Description,
+					InputSchema: tool.InputSchema,
+				})
+				if err != nil {
+					return err
+				}
+			}
 		}
+		return nil
 	}
+}
 
-	// Write to file
-	return writeIndex(allTools, output, format)
+// collectTools spawns every configured server and gathers its tools.
+// Servers that fail are reported on stderr and skipped.
+func collectTools(cfg *config.Config) []ToolEntry {
+	var allTools []ToolEntry
+	serverTools(cfg)(func(entry ToolEntry) error {
+		allTools = append(allTools, entry)
+		return nil
+	})
+	return allTools
 }
 
 // writeIndex writes the tool index to a file.
 func writeIndex(tools []ToolEntry, path, format string) error {
+	return writeIndexFrom(func(fn func(ToolEntry) error) error {
+		for _, tool := range tools {
+			if err := fn(tool); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, path, format, defaultTableFields)
+}
+
+// writeIndexFrom writes the tools of source to a file as they arrive, so
+// that large installations export without holding every tool in memory.
+// Table formats write the given columns.
+func writeIndexFrom(source toolSource, path, format string, columns []string) error {
 	file, err := os.Create(path)
 	if err != nil {
 		return fmt.Errorf("failed to create index file: %w", err)
 	}
 	defer file.Close()
 
-	encoder := json.NewEncoder(file)
-
-	if format == "json" {
-		// JSON array format
-		encoder.SetIndent("", "  ")
-		if err := encoder.Encode(tools); err != nil {
-			return fmt.Errorf("failed to encode tools: %w", err)
+	w := bufio.NewWriter(file)
+	encoder := json.NewEncoder(w)
+	count := 0
+
+	if isTableFormat(format) {
+		// CSV or Markdown table
+		count, err = writeTable(w, source, format, columns)
+	} else if format == "json" {
+		// JSON array format, written element by element
+		w.WriteString("[")
+		err = source(func(tool ToolEntry) error {
+			data, err := json.MarshalIndent(tool, "  ", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to encode tool: %w", err)
+			}
+			if count > 0 {
+				w.WriteString(",")
+			}
+			w.WriteString("\n  ")
+			w.Write(data)
+			count++
+			return nil
+		})
+		if count > 0 {
+			w.WriteString("\n")
 		}
+		w.WriteString("]\n")
 	} else {
 		// JSONL format (one per line)
-		for _, tool := range tools {
+		err = source(func(tool ToolEntry) error {
 			if err := encoder.Encode(tool); err != nil {
 				return fmt.Errorf("failed to encode tool: %w", err)
 			}
-		}
+			count++
+			return nil
+		})
+	}
+	if err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to write index file: %w", err)
 	}
 
-	fmt.Printf("✓ Exported %d tools to %s\n", len(tools), path)
+	fmt.Printf("✓ Exported %d tools to %s\n", count, path)
 	return nil
 }
 
 // acquireFileLock acquires an exclusive lock on the index file.
 func acquireFileLock(path string) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create index directory: %w", err)
+	}
+
 	lockPath := path + ".lock"
 	lockFile, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0644)
 	if err != nil {
@@ -191,15 +274,48 @@ func releaseFileLock(lockFile *os.File) error {
 	return os.Remove(lockPath)
 }
 
+// readExportedIndex returns the entries of the exported index.jsonl,
+// or nil when it is missing or unreadable.
+func readExportedIndex() []catalog.Entry {
+	indexPath, err := paths.IndexFile(".jsonl")
+	if err != nil {
+		return nil
+	}
+	file, err := os.Open(indexPath)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	entries, err := catalog.Parse(file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring unreadable tool index: %v\n", err)
+		return nil
+	}
+	return entries
+}
+
+// regenerations tracks background index regenerations started by
+// RegenerateIndex.
+var regenerations sync.WaitGroup
+
 // RegenerateIndex silently regenerates the index file in the background.
-// Called by setup/add/remove commands to keep index fresh.
+// Called by setup/add/remove commands to keep index fresh. Paths are
+// resolved before returning, so the export never follows a later change
+// of HOME.
 func RegenerateIndex() {
+	indexPath, err := paths.IndexFile(".jsonl")
+	if err != nil {
+		return
+	}
+	configPath, err := config.GetDefaultConfigPath()
+	if err != nil {
+		return
+	}
+
+	regenerations.Add(1)
 	go func() {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return
-		}
-		indexPath := filepath.Join(home, ".tool-hub-mcp-index.jsonl")
+		defer regenerations.Done()
 
 		// Acquire lock before writing
 		lockFile, err := acquireFileLock(indexPath)
@@ -210,6 +326,10 @@ func RegenerateIndex() {
 		defer releaseFileLock(lockFile)
 
 		// Run export silently (errors ignored)
-		_ = runExportIndex("jsonl", "")
+		cfg, err := config.LoadFrom(configPath)
+		if err != nil || len(cfg.Servers) == 0 {
+			return
+		}
+		_ = writeIndexFrom(serverTools(cfg), indexPath, "jsonl", nil)
 	}()
 }
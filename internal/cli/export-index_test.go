@@ -3,6 +3,7 @@ package cli
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -138,6 +139,33 @@ func TestWriteIndexJSON(t *testing.T) {
 	}
 }
 
+func TestWriteIndexJSONStreamed(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	for _, n := range []int{0, 3} {
+		output := filepath.Join(tmpDir, fmt.Sprintf("index-%d.json", n))
+		tools := make([]ToolEntry, n)
+		for i := range tools {
+			tools[i] = ToolEntry{Tool: fmt.Sprintf("tool_%d", i), Server: "jira"}
+		}
+		if err := writeIndex(tools, output, "json"); err != nil {
+			t.Fatalf("writeIndex failed: %v", err)
+		}
+
+		data, err := os.ReadFile(output)
+		if err != nil {
+			t.Fatalf("Failed to read output file: %v", err)
+		}
+		var entries []ToolEntry
+		if err := json.Unmarshal(data, &entries); err != nil {
+			t.Fatalf("Output is not valid JSON array: %v\n%s", err, data)
+		}
+		if entries == nil || len(entries) != n {
+			t.Errorf("Expected %d entries, got %v", n, entries)
+		}
+	}
+}
+
 func TestAcquireFileLock(t *testing.T) {
 	tmpDir := t.TempDir()
 	testFile := filepath.Join(tmpDir, "test-lock.jsonl")
@@ -243,6 +271,9 @@ func TestConcurrentFileLocking(t *testing.T) {
 }
 
 func TestRegenerateIndexNonBlocking(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Cleanup(regenerations.Wait)
+
 	// Test that RegenerateIndex doesn't block
 	start := time.Now()
 	RegenerateIndex()
@@ -378,7 +409,7 @@ func TestRunExportIndexWithNoConfig(t *testing.T) {
 
 	// Test with custom output path when config doesn't exist
 	// This will fail to load config but should handle gracefully
-	err := runExportIndex("jsonl", output)
+	err := runExportIndex("jsonl", output, nil)
 
 	// Should either return error or handle gracefully
 	// Just verify it doesn't panic
@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// exportFormats maps each export-index format to its file extension.
+var exportFormats = map[string]string{
+	"jsonl":    ".jsonl",
+	"json":     ".json",
+	"csv":      ".csv",
+	"markdown": ".md",
+}
+
+// tableFields are the columns --fields accepts for csv and markdown.
+var tableFields = []string{"tool", "server", "description", "schema"}
+
+// defaultTableFields are the columns exported when --fields is not given.
+var defaultTableFields = []string{"tool", "server", "description"}
+
+// isTableFormat reports whether format is exported as rows and columns.
+func isTableFormat(format string) bool {
+	return format == "csv" || format == "markdown"
+}
+
+// parseExportOptions validates the format and the columns selected with
+// --fields, returning the columns to export (defaultTableFields if none).
+func parseExportOptions(format string, fields []string) ([]string, error) {
+	if _, ok := exportFormats[format]; !ok {
+		return nil, fmt.Errorf("unknown format %q (use jsonl, json, csv or markdown)", format)
+	}
+	if len(fields) == 0 {
+		if isTableFormat(format) {
+			return defaultTableFields, nil
+		}
+		return nil, nil
+	}
+	if !isTableFormat(format) {
+		return nil, fmt.Errorf("--fields applies to the csv and markdown formats only")
+	}
+
+	seen := make(map[string]bool)
+	var columns []string
+	for _, field := range fields {
+		field = strings.ToLower(strings.TrimSpace(field))
+		if !slices.Contains(tableFields, field) {
+			return nil, fmt.Errorf("unknown field %q (use %s)", field, strings.Join(tableFields, ", "))
+		}
+		if !seen[field] {
+			seen[field] = true
+			columns = append(columns, field)
+		}
+	}
+	return columns, nil
+}
+
+// fieldValue formats one column of a tool; the schema is compact JSON.
+func fieldValue(tool ToolEntry, field string) string {
+	switch field {
+	case "tool":
+		return tool.Tool
+	case "server":
+		return tool.Server
+	case "description":
+		return tool.Description
+	case "schema":
+		if tool.InputSchema == nil {
+			return ""
+		}
+		data, _ := json.Marshal(tool.InputSchema)
+		return string(data)
+	}
+	return ""
+}
+
+// writeTable writes the tools of source as a CSV or Markdown table with
+// a header row, returning the number of tools written.
+func writeTable(w *bufio.Writer, source toolSource, format string, fields []string) (int, error) {
+	count := 0
+	row := make([]string, len(fields))
+
+	if format == "csv" {
+		cw := csv.NewWriter(w)
+		cw.Write(fields)
+		err := source(func(tool ToolEntry) error {
+			for i, field := range fields {
+				row[i] = fieldValue(tool, field)
+			}
+			count++
+			return cw.Write(row)
+		})
+		cw.Flush()
+		if err == nil {
+			err = cw.Error()
+		}
+		return count, err
+	}
+
+	separators := make([]string, len(fields))
+	for i := range separators {
+		separators[i] = "---"
+	}
+	writeMarkdownRow(w, fields)
+	writeMarkdownRow(w, separators)
+	err := source(func(tool ToolEntry) error {
+		for i, field := range fields {
+			row[i] = markdownCell(fieldValue(tool, field))
+		}
+		count++
+		writeMarkdownRow(w, row)
+		return nil
+	})
+	return count, err
+}
+
+// writeMarkdownRow writes one row of a Markdown table.
+func writeMarkdownRow(w *bufio.Writer, cells []string) {
+	fmt.Fprintf(w, "| %s |\n", strings.Join(cells, " | "))
+}
+
+// markdownCell escapes a value for a Markdown table cell, which must stay
+// on one line and cannot contain an unescaped pipe.
+func markdownCell(value string) string {
+	value = strings.ReplaceAll(value, "|", `\|`)
+	return strings.Join(strings.Fields(value), " ")
+}
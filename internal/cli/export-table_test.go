@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseExportOptions(t *testing.T) {
+	columns, err := parseExportOptions("csv", nil)
+	if err != nil || strings.Join(columns, ",") != "tool,server,description" {
+		t.Errorf("default columns = %v, %v", columns, err)
+	}
+	columns, err = parseExportOptions("markdown", []string{"Schema", "tool", "schema"})
+	if err != nil || strings.Join(columns, ",") != "schema,tool" {
+		t.Errorf("columns = %v, %v", columns, err)
+	}
+
+	for _, tc := range []struct {
+		format string
+		fields []string
+	}{
+		{"xml", nil},
+		{"csv", []string{"name"}},
+		{"jsonl", []string{"tool"}},
+	} {
+		if _, err := parseExportOptions(tc.format, tc.fields); err == nil {
+			t.Errorf("expected error for format %q with fields %v", tc.format, tc.fields)
+		}
+	}
+}
+
+func TestWriteIndexTables(t *testing.T) {
+	tmpDir := t.TempDir()
+	tools := []ToolEntry{
+		{Tool: "search", Server: "jira", Description: "Search issues,\nusing JQL | filters", InputSchema: map[string]interface{}{"type": "object"}},
+	}
+	source := func(fn func(ToolEntry) error) error {
+		for _, tool := range tools {
+			if err := fn(tool); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	csvPath := filepath.Join(tmpDir, "tools.csv")
+	if err := writeIndexFrom(source, csvPath, "csv", []string{"tool", "description", "schema"}); err != nil {
+		t.Fatalf("writeIndexFrom csv failed: %v", err)
+	}
+	data, _ := os.ReadFile(csvPath)
+	want := "tool,description,schema\nsearch,\"Search issues,\nusing JQL | filters\",\"{\"\"type\"\":\"\"object\"\"}\"\n"
+	if string(data) != want {
+		t.Errorf("csv = %q, want %q", data, want)
+	}
+
+	mdPath := filepath.Join(tmpDir, "tools.md")
+	if err := writeIndexFrom(source, mdPath, "markdown", []string{"server", "description"}); err != nil {
+		t.Fatalf("writeIndexFrom markdown failed: %v", err)
+	}
+	data, _ = os.ReadFile(mdPath)
+	want = "| server | description |\n| --- | --- |\n| jira | Search issues, using JQL \\| filters |\n"
+	if string(data) != want {
+		t.Errorf("markdown = %q, want %q", data, want)
+	}
+}
@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/config/sources"
+	"github.com/spf13/cobra"
+)
+
+// NewInstallCmd creates the 'install' command for registering tool-hub-mcp
+// in an AI client's MCP configuration.
+func NewInstallCmd() *cobra.Command {
+	var (
+		client        string
+		configPath    string
+		disableDirect bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "install",
+		Short: "Register tool-hub-mcp in an AI client's MCP config",
+		Long: `Write a 'tool-hub-mcp serve' entry into an AI client's MCP configuration,
+so the client talks to the hub instead of each server directly.
+
+The previous client config is saved next to it with a .bak suffix.
+
+With --disable-direct, servers that the hub already aggregates are disabled
+in the client: OpenCode entries get "enabled": false, other clients have them
+moved under "` + sources.DisabledServersKey + `" so they can be restored by hand.
+
+Supported clients: ` + strings.Join(sources.ClientNames(), ", "),
+		Example: `  # Register the hub in Claude Code
+  tool-hub-mcp install --client claude
+
+  # Register in Cursor and disable servers the hub now provides
+  tool-hub-mcp install --client cursor --disable-direct`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runInstall(client, configPath, disableDirect)
+		},
+	}
+
+	cmd.Flags().StringVarP(&client, "client", "c", "", "Client to install into ("+strings.Join(sources.ClientNames(), "|")+")")
+	cmd.Flags().StringVar(&configPath, "config", "", "Override the client config file path")
+	cmd.Flags().BoolVar(&disableDirect, "disable-direct", false, "Disable direct servers already aggregated by the hub")
+	cmd.MarkFlagRequired("client")
+
+	return cmd
+}
+
+// runInstall writes the hub entry into the client config.
+func runInstall(client, configPath string, disableDirect bool) error {
+	target, err := sources.ResolveClientTarget(client)
+	if err != nil {
+		return err
+	}
+	if configPath != "" {
+		target.Path = configPath
+	}
+
+	var opts sources.InstallOptions
+	if disableDirect {
+		hubConfig, err := config.LoadOrCreate()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		direct, err := target.ReadServers()
+		if err != nil {
+			return err
+		}
+		opts.DisableServers = aggregatedServers(direct, hubConfig)
+	}
+
+	result, err := sources.Install(target, opts)
+	if err != nil {
+		return fmt.Errorf("failed to install into %s: %w", client, err)
+	}
+
+	action := "Registered"
+	if result.Updated {
+		action = "Updated"
+	}
+	fmt.Printf("✓ %s '%s' in %s\n", action, sources.HubEntryName, result.Path)
+	if result.BackupPath != "" {
+		fmt.Printf("  Backup: %s\n", result.BackupPath)
+	}
+	if len(result.Disabled) > 0 {
+		fmt.Printf("  Disabled %d direct servers: %s\n", len(result.Disabled), strings.Join(result.Disabled, ", "))
+	}
+	fmt.Println()
+	fmt.Printf("Restart %s to pick up the change.\n", client)
+
+	return nil
+}
+
+// aggregatedServers returns the client's direct servers that the hub already
+// serves, matched by original or camelCase name. Self-references are never included.
+func aggregatedServers(direct map[string]*config.ServerConfig, hubConfig *config.Config) []string {
+	var names []string
+	for name, server := range direct {
+		if name == sources.HubEntryName || config.IsSelfReference(server) {
+			continue
+		}
+		if _, ok := config.FindServerKey(hubConfig.Servers, name); ok {
+			names = append(names, name)
+		} else if _, dup := hubConfig.FindDuplicate(server); dup {
+			// Merged into a server imported under another name
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+)
+
+func TestInstallCommandFlags(t *testing.T) {
+	cmd := NewInstallCmd()
+
+	for _, name := range []string{"client", "config", "disable-direct"} {
+		if cmd.Flags().Lookup(name) == nil {
+			t.Errorf("Flag '%s' not registered", name)
+		}
+	}
+}
+
+func TestAggregatedServers(t *testing.T) {
+	hub := &config.Config{Servers: map[string]*config.ServerConfig{
+		"jiraCloud": {Command: "npx"},
+		"myServer2": {Command: "npx", OriginalName: "my_server"},
+		"outline":   {Command: "uvx", Args: []string{"mcp-outline"}},
+	}}
+	direct := map[string]*config.ServerConfig{
+		"jira-cloud":   {Command: "npx"},
+		"my_server":    {Command: "npx"},
+		"unrelated":    {Command: "node"},
+		"docs":         {Command: "uvx", Args: []string{"mcp-outline"}},
+		"tool-hub-mcp": {Command: "tool-hub-mcp", Args: []string{"serve"}},
+	}
+
+	got := aggregatedServers(direct, hub)
+	if !reflect.DeepEqual(got, []string{"docs", "jira-cloud", "my_server"}) {
+		t.Errorf("unexpected aggregated servers: %v", got)
+	}
+}
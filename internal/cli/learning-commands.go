@@ -10,6 +10,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/khanglvm/tool-hub-mcp/internal/paths"
 	"github.com/khanglvm/tool-hub-mcp/internal/storage"
 	"github.com/spf13/cobra"
 )
@@ -83,13 +84,14 @@ func newLearningClearCmd() *cobra.Command {
 				return nil
 			}
 
-			home, err := os.UserHomeDir()
+			dbPath, err := paths.HistoryDB()
 			if err != nil {
-				return fmt.Errorf("failed to get home directory: %w", err)
+				return err
 			}
 
-			dbPath := home + "/.tool-hub-mcp/history.db"
-
+			// WAL files would otherwise replay old data into a new database
+			os.Remove(dbPath + "-wal")
+			os.Remove(dbPath + "-shm")
 			if err := os.Remove(dbPath); err != nil {
 				if os.IsNotExist(err) {
 					fmt.Println("No learning data found")
@@ -113,8 +115,11 @@ func newLearningDisableCmd() *cobra.Command {
 			fmt.Println("To disable tracking, set environment variable:")
 			fmt.Println("  TOOL_HUB_MCP_LEARNING=false")
 			fmt.Println()
-			fmt.Println("Or modify config at ~/.tool-hub-mcp.json to add:")
-			fmt.Println(`  "learning": {"enabled": false}`)
+			fmt.Println("Or run the server with:")
+			fmt.Println("  tool-hub-mcp serve --no-learning")
+			fmt.Println()
+			fmt.Println("Or modify config at ~/.config/tool-hub-mcp/config.json to add:")
+			fmt.Println(`  "settings": {"learning": {"enabled": false}}`)
 
 			return nil
 		},
@@ -131,7 +136,7 @@ func newLearningEnableCmd() *cobra.Command {
 			fmt.Println("To ensure it's active, unset environment variable:")
 			fmt.Println("  unset TOOL_HUB_MCP_LEARNING")
 			fmt.Println()
-			fmt.Println("Or remove learning.disabled from ~/.tool-hub-mcp.json")
+			fmt.Println("Or remove settings.learning.enabled from ~/.config/tool-hub-mcp/config.json")
 
 			return nil
 		},
@@ -18,7 +18,7 @@ func NewLearningCmd() *cobra.Command {
 		Long: `The learning system tracks tool usage to provide intelligent ranking
 and recommendations via ε-greedy multi-armed bandit algorithm.
 
-All data is stored locally in ~/.tool-hub-mcp/history.db with privacy
+All data is stored locally in ~/.local/share/tool-hub-mcp/history.db with privacy
 protection (SHA256 hashing of contexts).
 
 Commands:
@@ -1,10 +1,18 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
 
 	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/redact"
 	"github.com/khanglvm/tool-hub-mcp/internal/spawner"
+	"github.com/khanglvm/tool-hub-mcp/internal/storage"
 	"github.com/spf13/cobra"
 )
 
@@ -17,13 +25,18 @@ func NewListCmd() *cobra.Command {
 		Use:     "list",
 		Aliases: []string{"ls"},
 		Short:   "List all registered MCP servers",
-		Long:    `Display all MCP servers registered in ~/.tool-hub-mcp.json`,
+		Long: `Display all MCP servers registered in ~/.config/tool-hub-mcp/config.json with their
+state, source, tool count and last successful tool discovery.
+
+Tool counts come from the discovery history recorded by serve, the cached
+tool metadata or the exported index; --status spawns each enabled server
+to refresh them.`,
 		Example: `  tool-hub-mcp list
   tool-hub-mcp ls
   tool-hub-mcp list --status  # test connections and show tool counts
   tool-hub-mcp list --json`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runList(jsonOutput, showStatus)
+			return runList(cmd.OutOrStdout(), jsonOutput, showStatus)
 		},
 	}
 
@@ -33,53 +46,191 @@ func NewListCmd() *cobra.Command {
 	return cmd
 }
 
+// serverListing is one server's row in `list` output.
+type serverListing struct {
+	Name          string     `json:"name"`
+	OriginalName  string     `json:"originalName,omitempty"`
+	Enabled       bool       `json:"enabled"`
+	Source        string     `json:"source"`
+	Command       string     `json:"command"`
+	URL           string     `json:"url,omitempty"`
+	Args          []string   `json:"args,omitempty"`
+	EnvCount      int        `json:"envCount,omitempty"`
+	Tools         *int       `json:"tools,omitempty"`
+	LastDiscovery *time.Time `json:"lastDiscovery,omitempty"`
+	Error         string     `json:"error,omitempty"`
+	// Capabilities are known only after --status spawned the server
+	Capabilities *spawner.Capabilities `json:"capabilities,omitempty"`
+}
+
 // runList displays all registered MCP servers.
-func runList(jsonOutput, showStatus bool) error {
+func runList(w io.Writer, jsonOutput, showStatus bool) error {
 	cfg, err := config.Load()
-	if err != nil {
-		fmt.Println("No servers configured.")
-		fmt.Println("Run 'tool-hub-mcp setup' to import from AI CLI tools.")
+	if err != nil || len(cfg.Servers) == 0 {
+		fmt.Fprintln(w, "No servers configured.")
+		fmt.Fprintln(w, "Run 'tool-hub-mcp setup' to import from AI CLI tools.")
 		return nil
 	}
 
-	if len(cfg.Servers) == 0 {
-		fmt.Println("No servers configured.")
-		fmt.Println("Run 'tool-hub-mcp setup' to import from AI CLI tools.")
-		return nil
+	store := storage.NewStorage()
+	if err := store.Init(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: discovery history unavailable: %v\n", err)
 	}
+	defer store.Close()
 
-	fmt.Printf("Registered MCP Servers (%d):\n\n", len(cfg.Servers))
+	statuses, err := store.GetServerStatuses()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	cached := cachedToolCounts()
 
 	// Create spawner pool if status check requested
 	var pool *spawner.Pool
 	if showStatus {
 		pool = spawner.NewPool(3)
+		pool.SetDefaultEnv(cfg.Settings.ChildEnv())
+		defer pool.Close()
+	}
+
+	names := make([]string, 0, len(cfg.Servers))
+	for name := range cfg.Servers {
+		names = append(names, name)
 	}
+	sort.Strings(names)
 
-	for name, server := range cfg.Servers {
-		source := server.Source
-		if source == "" {
-			source = "unknown"
+	listings := make([]serverListing, 0, len(names))
+	for _, name := range names {
+		server := cfg.Servers[name]
+		listing := serverListing{
+			Name:     name,
+			Enabled:  !server.Disabled,
+			Source:   server.Source,
+			Command:  server.Command,
+			URL:      redact.URL(server.URL),
+			Args:     redact.Args(server.Args),
+			EnvCount: len(server.Env),
 		}
-		fmt.Printf("  %s\n", name)
-		fmt.Printf("    Command: %s %v\n", server.Command, server.Args)
-		fmt.Printf("    Source:  %s\n", source)
-		if len(server.Env) > 0 {
-			fmt.Printf("    Env:     %d variables\n", len(server.Env))
+		if listing.Source == "" {
+			listing.Source = "unknown"
+		}
+		if display := server.DisplayName(name); display != name {
+			listing.OriginalName = display
+		}
+
+		// Tool count: discovery history, then cached metadata, then the exported index
+		if status, ok := statuses[name]; ok {
+			if !status.LastSuccess.IsZero() {
+				count, last := status.ToolCount, status.LastSuccess
+				listing.Tools, listing.LastDiscovery = &count, &last
+			}
+			listing.Error = redact.String(status.LastError)
+		}
+		if listing.Tools == nil && server.Metadata != nil && len(server.Metadata.Tools) > 0 {
+			count := len(server.Metadata.Tools)
+			listing.Tools = &count
+		}
+		if count, ok := cached[name]; ok && listing.Tools == nil {
+			listing.Tools = &count
 		}
 
 		// Check status if requested
-		if showStatus {
+		if showStatus && listing.Enabled {
 			tools, err := pool.GetTools(name, server)
+			if err := store.RecordDiscovery(name, len(tools), err); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
 			if err != nil {
-				fmt.Printf("    Status:  ✗ %s\n", err.Error())
+				listing.Error = redact.String(err.Error())
 			} else {
-				fmt.Printf("    Status:  ✓ %d tools\n", len(tools))
+				count, now := len(tools), time.Now()
+				listing.Tools, listing.LastDiscovery, listing.Error = &count, &now, ""
+				if caps, ok := pool.Capabilities(name); ok {
+					listing.Capabilities = &caps
+				}
 			}
 		}
 
-		fmt.Println()
+		listings = append(listings, listing)
 	}
 
+	if jsonOutput {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(listings)
+	}
+
+	return writeServerTable(w, listings)
+}
+
+// writeServerTable prints listings as an aligned table followed by the
+// failure reason of each failing server.
+func writeServerTable(w io.Writer, listings []serverListing) error {
+	fmt.Fprintf(w, "Registered MCP Servers (%d):\n\n", len(listings))
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tSTATE\tSOURCE\tTOOLS\tLAST DISCOVERY\tCOMMAND")
+	for _, l := range listings {
+		name := l.Name
+		if l.OriginalName != "" {
+			name = fmt.Sprintf("%s (from '%s')", l.Name, l.OriginalName)
+		}
+		state := "enabled"
+		if !l.Enabled {
+			state = "disabled"
+		} else if l.Error != "" {
+			state = "failing"
+		}
+		tools := "-"
+		if l.Tools != nil {
+			tools = fmt.Sprintf("%d", *l.Tools)
+		}
+		last := "never"
+		if l.LastDiscovery != nil {
+			last = l.LastDiscovery.Local().Format("2006-01-02 15:04")
+		}
+		target := l.Command
+		if l.URL != "" {
+			target = l.URL
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", name, state, l.Source, tools, last, target)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	var spawned []serverListing
+	for _, l := range listings {
+		if l.Capabilities != nil {
+			spawned = append(spawned, l)
+		}
+	}
+	if len(spawned) > 0 {
+		fmt.Fprintln(w, "\nCapabilities:")
+		for _, l := range spawned {
+			fmt.Fprintf(w, "  %s: %s\n", l.Name, l.Capabilities)
+		}
+	}
+
+	var failing []serverListing
+	for _, l := range listings {
+		if l.Error != "" {
+			failing = append(failing, l)
+		}
+	}
+	if len(failing) > 0 {
+		fmt.Fprintln(w, "\nFailures:")
+		for _, l := range failing {
+			fmt.Fprintf(w, "  %s: %s\n", l.Name, l.Error)
+		}
+	}
 	return nil
 }
+
+// cachedToolCounts returns per-server tool counts from the exported index.
+func cachedToolCounts() map[string]int {
+	counts := make(map[string]int)
+	for _, e := range readExportedIndex() {
+		counts[e.Server]++
+	}
+	return counts
+}
@@ -2,8 +2,14 @@ package cli
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/storage"
 )
 
 func TestNewListCmd(t *testing.T) {
@@ -145,3 +151,55 @@ func TestListCommandFlags(t *testing.T) {
 		})
 	}
 }
+
+func TestRunListShowsDiscoveryState(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	cfg := config.NewConfig()
+	cfg.Servers["jira"] = &config.ServerConfig{Command: "npx", Source: "manual"}
+	cfg.Servers["github"] = &config.ServerConfig{
+		Command:  "npx",
+		Source:   "claude-code",
+		Disabled: true,
+		Metadata: &config.ServerMetadata{Tools: []string{"a", "b", "c"}},
+	}
+	if err := config.Save(cfg, filepath.Join(home, ".tool-hub-mcp.json")); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	store := storage.NewStorage()
+	if err := store.Init(); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	store.RecordDiscovery("jira", 5, nil)
+	store.RecordDiscovery("jira", 0, errors.New("auth failed"))
+	store.Close()
+
+	var buf bytes.Buffer
+	if err := runList(&buf, false, false); err != nil {
+		t.Fatalf("runList failed: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"disabled", "claude-code", "failing", "manual", "jira: auth failed"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("table missing %q:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	if err := runList(&buf, true, false); err != nil {
+		t.Fatalf("runList --json failed: %v", err)
+	}
+	var listings []serverListing
+	if err := json.Unmarshal(buf.Bytes(), &listings); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if len(listings) != 2 || listings[0].Name != "github" || listings[0].Enabled || *listings[0].Tools != 3 {
+		t.Errorf("unexpected github listing: %+v", listings[0])
+	}
+	jira := listings[1]
+	if *jira.Tools != 5 || jira.LastDiscovery == nil || jira.Error != "auth failed" {
+		t.Errorf("unexpected jira listing: %+v", jira)
+	}
+}
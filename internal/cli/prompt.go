@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// stdinIsTerminal reports whether prompts can be answered interactively.
+func stdinIsTerminal() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+// promptValue asks for a value on stdin, showing def (if any) as the answer
+// used when the user just presses Enter. Secret values are read without
+// echo and their default is not shown.
+func promptValue(reader *bufio.Reader, label, def string, secret bool) string {
+	switch {
+	case def != "" && secret:
+		fmt.Printf("%s [keep default]: ", label)
+	case def != "":
+		fmt.Printf("%s [%s]: ", label, def)
+	default:
+		fmt.Printf("%s: ", label)
+	}
+
+	var value string
+	if secret && stdinIsTerminal() {
+		raw, _ := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Println()
+		value = string(raw)
+	} else {
+		line, _ := reader.ReadString('\n')
+		value = line
+	}
+
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return def
+	}
+	return value
+}
@@ -16,7 +16,8 @@ func NewRemoveCmd() *cobra.Command {
 		Long:    `Remove an MCP server from the configuration.`,
 		Example: `  tool-hub-mcp remove jira
   tool-hub-mcp rm jira`,
-		Args: cobra.ExactArgs(1),
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeServerNames,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runRemove(args[0])
 		},
@@ -32,16 +33,13 @@ func runRemove(name string) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	// Try both original name and camelCase
-	camelName := config.ToCamelCase(name)
-
-	if _, exists := cfg.Servers[name]; exists {
-		delete(cfg.Servers, name)
-	} else if _, exists := cfg.Servers[camelName]; exists {
-		delete(cfg.Servers, camelName)
-	} else {
-		return fmt.Errorf("server '%s' not found", name)
+	// Match the config key, source name, aliases or another spelling of
+	// the name (jira-mcp, JiraMcp), as hub_manage does
+	key, exists := cfg.ResolveServer(name)
+	if !exists {
+		return cfg.ServerNotFound(name)
 	}
+	delete(cfg.Servers, key)
 
 	configPath, err := config.GetDefaultConfigPath()
 	if err != nil {
@@ -55,6 +53,6 @@ func runRemove(name string) error {
 	// Auto-regenerate tool index for bash/grep access
 	RegenerateIndex()
 
-	fmt.Printf("✓ Removed server '%s'\n", name)
+	fmt.Printf("✓ Removed server '%s'\n", key)
 	return nil
 }
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"strings"
 	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
 )
 
 func TestNewRemoveCmd(t *testing.T) {
@@ -107,3 +109,34 @@ func TestRemoveCommandValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestRunRemoveResolvesName(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Cleanup(regenerations.Wait)
+
+	cfg := config.NewConfig()
+	cfg.Servers["jiraMcp"] = &config.ServerConfig{Command: "/nonexistent/jira"}
+	configPath, err := config.GetDefaultConfigPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := config.Save(cfg, configPath); err != nil {
+		t.Fatal(err)
+	}
+
+	err = runRemove("jira-mc")
+	if err == nil || !strings.Contains(err.Error(), "Did you mean 'jiraMcp'?") {
+		t.Errorf("expected a suggestion, got %v", err)
+	}
+
+	if err := runRemove("Jira-MCP"); err != nil {
+		t.Fatalf("runRemove() failed: %v", err)
+	}
+	saved, err := config.Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if _, ok := saved.Servers["jiraMcp"]; ok {
+		t.Error("server should be removed")
+	}
+}
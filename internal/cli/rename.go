@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+// NewRenameCmd creates the 'rename' command for renaming MCP servers.
+func NewRenameCmd() *cobra.Command {
+	var keepAlias bool
+
+	cmd := &cobra.Command{
+		Use:   "rename <old> <new>",
+		Short: "Rename an MCP server",
+		Long: `Rename an MCP server in the configuration.
+
+Audit and search history recorded under the old name moves to the new name.
+With --keep-alias the old name stays usable in hub_search and hub_execute.`,
+		Example: `  tool-hub-mcp rename jira jiraCloud
+  tool-hub-mcp rename jira jiraCloud --keep-alias`,
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeServerNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRename(args[0], args[1], keepAlias)
+		},
+	}
+
+	cmd.Flags().BoolVar(&keepAlias, "keep-alias", false, "Keep the old name as an alias")
+
+	return cmd
+}
+
+// runRename renames a server in the config and migrates its stored history.
+// The history migration is reverted if the config cannot be saved.
+func runRename(oldName, newName string, keepAlias bool) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	oldKey, exists := cfg.ResolveServer(oldName)
+	if !exists {
+		return fmt.Errorf("server '%s' not found", oldName)
+	}
+	newKey := config.ToCamelCase(newName)
+
+	if err := cfg.RenameServer(oldKey, newKey, keepAlias); err != nil {
+		return err
+	}
+
+	configPath, err := config.GetDefaultConfigPath()
+	if err != nil {
+		return fmt.Errorf("failed to get config path: %w", err)
+	}
+
+	store := storage.NewStorage()
+	if err := store.Init(); err != nil {
+		return fmt.Errorf("failed to initialize storage: %w", err)
+	}
+	defer store.Close()
+
+	if err := store.RenameServer(oldKey, newKey); err != nil {
+		return fmt.Errorf("failed to migrate history: %w", err)
+	}
+
+	if err := config.Save(cfg, configPath); err != nil {
+		if rbErr := store.RenameServer(newKey, oldKey); rbErr != nil {
+			log.Printf("Warning: failed to restore history for '%s': %v", oldKey, rbErr)
+		}
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	// Auto-regenerate tool index for bash/grep access
+	RegenerateIndex()
+
+	fmt.Printf("✓ Renamed server '%s' to '%s'\n", oldKey, newKey)
+	return nil
+}
@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+)
+
+func TestRenameCommandArgs(t *testing.T) {
+	cmd := NewRenameCmd()
+	if cmd.Use != "rename <old> <new>" {
+		t.Errorf("Expected Use='rename <old> <new>', got %q", cmd.Use)
+	}
+	if err := cmd.Args(cmd, []string{"jira"}); err == nil {
+		t.Error("Expected error with a single argument")
+	}
+	if cmd.Flags().Lookup("keep-alias") == nil {
+		t.Error("Expected --keep-alias flag")
+	}
+}
+
+func TestRunRename(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Cleanup(regenerations.Wait)
+
+	configPath := filepath.Join(home, ".config", "tool-hub-mcp", "config.json")
+	cfg := config.NewConfig()
+	cfg.Servers["jira"] = &config.ServerConfig{Command: "/nonexistent/jira"}
+	if err := config.Save(cfg, configPath); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	if err := runRename("jira", "jira-cloud", true); err != nil {
+		t.Fatalf("runRename() failed: %v", err)
+	}
+
+	loaded, err := config.Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if _, ok := loaded.Servers["jira"]; ok {
+		t.Error("old server name still present")
+	}
+	if key, ok := loaded.ResolveServer("jira"); !ok || key != "jiraCloud" {
+		t.Errorf("ResolveServer(jira) = %q, %v; want jiraCloud via alias", key, ok)
+	}
+
+	if err := runRename("missing", "other", false); err == nil {
+		t.Error("Expected error renaming unknown server")
+	}
+
+	if _, err := os.Stat(configPath); err != nil {
+		t.Errorf("config file missing: %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/spawner"
+	"github.com/spf13/cobra"
+)
+
+// NewSandboxCmd creates the hidden command sandboxed servers are spawned
+// through on Linux: it makes the file system read-only for itself with
+// Landlock, then execs the server.
+func NewSandboxCmd() *cobra.Command {
+	var writable []string
+
+	cmd := &cobra.Command{
+		Use:          spawner.SandboxHelperCommand + " [--writable <path>]... -- <command> [args...]",
+		Short:        "Run a command with a read-only file system (internal)",
+		Hidden:       true,
+		Args:         cobra.MinimumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return spawner.ExecSandboxed(writable, args)
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&writable, "writable", nil, "Path that stays writable (repeatable)")
+	cmd.Flags().SetInterspersed(false)
+
+	return cmd
+}
+
+// parseSandboxFlag parses the setup --sandbox value: a comma-separated
+// list of "no-network" and "read-only", or "strict" for both.
+func parseSandboxFlag(value string) (*config.SandboxSettings, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	sandbox := &config.SandboxSettings{}
+	for _, item := range strings.Split(value, ",") {
+		switch strings.TrimSpace(item) {
+		case "no-network":
+			sandbox.Network = new(bool)
+		case "read-only":
+			sandbox.FSReadOnly = true
+		case "strict":
+			sandbox.Network = new(bool)
+			sandbox.FSReadOnly = true
+		default:
+			return nil, fmt.Errorf("unknown sandbox restriction '%s', use no-network, read-only or strict", strings.TrimSpace(item))
+		}
+	}
+	return sandbox, nil
+}
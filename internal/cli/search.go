@@ -0,0 +1,164 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/mcp"
+	"github.com/khanglvm/tool-hub-mcp/internal/protocol"
+	"github.com/khanglvm/tool-hub-mcp/internal/search"
+	"github.com/khanglvm/tool-hub-mcp/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+// NewSearchCmd creates the 'search' command for testing tool discoverability.
+func NewSearchCmd() *cobra.Command {
+	var server string
+	var limit int
+	var jsonOutput bool
+	var refresh bool
+
+	cmd := &cobra.Command{
+		Use:   "search <query>",
+		Short: "Search tools the way hub_search does",
+		Long: `Run a query through the same search pipeline hub_search uses (synonyms,
+fuzzy matching, field boosts, ranking learned from past executions) and print the
+ranked tools.
+
+Tools come from the exported index (~/.local/share/tool-hub-mcp/index.jsonl) when it
+exists; otherwise, or with --refresh, every server is spawned to list them.`,
+		Example: `  tool-hub-mcp search "create issue"
+  tool-hub-mcp search "create issue" --server jira
+  tool-hub-mcp search screenshot --json --refresh`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSearch(cmd.OutOrStdout(), args[0], server, limit, jsonOutput, refresh)
+		},
+	}
+
+	cmd.Flags().StringVar(&server, "server", "", "Only search tools of this server")
+	cmd.RegisterFlagCompletionFunc("server", completeServerFlag)
+	cmd.Flags().IntVar(&limit, "limit", 10, "Maximum number of results")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+	cmd.Flags().BoolVar(&refresh, "refresh", false, "Ignore the exported index and spawn servers")
+
+	return cmd
+}
+
+// runSearch indexes the configured servers' tools and prints the results.
+func runSearch(w io.Writer, query, server string, limit int, jsonOutput, refresh bool) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if len(cfg.Servers) == 0 {
+		fmt.Fprintln(w, "No servers configured.")
+		fmt.Fprintln(w, "Run 'tool-hub-mcp setup' to import from AI CLI tools.")
+		return nil
+	}
+
+	if server != "" {
+		key, ok := cfg.ResolveServer(server)
+		if !ok {
+			return fmt.Errorf("server '%s' not found", server)
+		}
+		server = key
+	}
+
+	var tools []ToolEntry
+	if !refresh {
+		tools = loadCachedTools(cfg)
+	}
+	if len(tools) == 0 {
+		tools = collectTools(cfg)
+	}
+
+	indexer, err := search.NewIndexer()
+	if err != nil {
+		return fmt.Errorf("failed to create search indexer: %w", err)
+	}
+	defer indexer.Close()
+
+	// Rank with what hub_search learned from earlier executions
+	var history *storage.SQLiteStorage
+	if cfg.LearningEnabled() {
+		history = storage.NewStorage()
+		if err := history.Init(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: search history unavailable: %v\n", err)
+		}
+		defer history.Close()
+	}
+	pipeline, _ := mcp.NewSearchPipeline(indexer, cfg, history)
+
+	for name, serverTools := range toolsByServer(tools) {
+		var tags []string
+		if serverCfg := cfg.Servers[name]; serverCfg != nil {
+			tags = serverCfg.Tags
+		}
+		if err := indexer.IndexServer(name, serverTools, tags...); err != nil {
+			return fmt.Errorf("failed to index %s: %w", name, err)
+		}
+	}
+
+	var filter search.Filter
+	if server != "" {
+		filter.Servers = []string{server}
+	}
+	page, err := pipeline.Search(query, filter, limit, 0)
+	if err != nil {
+		return fmt.Errorf("search failed: %w", err)
+	}
+
+	return writeSearchResults(w, query, page.Results, jsonOutput)
+}
+
+// loadCachedTools reads the exported index, keeping only tools of servers
+// that are still configured. It returns nil when there is no usable index.
+func loadCachedTools(cfg *config.Config) []ToolEntry {
+	var tools []ToolEntry
+	for _, e := range readExportedIndex() {
+		if _, ok := cfg.Servers[e.Server]; ok {
+			tools = append(tools, ToolEntry(e))
+		}
+	}
+	return tools
+}
+
+// toolsByServer groups index entries into tool definitions per server.
+func toolsByServer(tools []ToolEntry) map[string][]protocol.Tool {
+	byServer := make(map[string][]protocol.Tool)
+	for _, t := range tools {
+		byServer[t.Server] = append(byServer[t.Server], protocol.Tool{
+			Name:        t.Tool,
+			Description: t.Description,
+			InputSchema: t.InputSchema,
+		})
+	}
+	return byServer
+}
+
+// writeSearchResults prints results as JSON or a ranked list.
+func writeSearchResults(w io.Writer, query string, results []search.SearchResult, jsonOutput bool) error {
+	if jsonOutput {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(results)
+	}
+
+	if len(results) == 0 {
+		fmt.Fprintf(w, "No tools match %q.\n", query)
+		return nil
+	}
+
+	fmt.Fprintf(w, "Results for %q (%d):\n\n", query, len(results))
+	for i, r := range results {
+		fmt.Fprintf(w, "  %d. %s/%s  (score %.2f)\n", i+1, r.ServerName, r.ToolName, r.Score)
+		if line := mcp.FirstLine(r.Description); line != "" {
+			fmt.Fprintf(w, "     %s\n", line)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/search"
+	"github.com/khanglvm/tool-hub-mcp/internal/storage"
+)
+
+func TestNewSearchCmd(t *testing.T) {
+	cmd := NewSearchCmd()
+	if cmd.Use != "search <query>" {
+		t.Errorf("Expected Use='search <query>', got %q", cmd.Use)
+	}
+	for _, flag := range []string{"server", "limit", "json", "refresh"} {
+		if cmd.Flags().Lookup(flag) == nil {
+			t.Errorf("Flag %q not registered", flag)
+		}
+	}
+}
+
+func TestRunSearchUsesExportedIndex(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	cfg := config.NewConfig()
+	cfg.Servers["jira"] = &config.ServerConfig{Command: "/nonexistent/jira"}
+	cfg.Servers["github"] = &config.ServerConfig{Command: "/nonexistent/github"}
+	if err := config.Save(cfg, filepath.Join(home, ".tool-hub-mcp.json")); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	index := `{"tool":"create_issue","server":"jira","description":"Create a new Jira issue"}
+{"tool":"create_pull_request","server":"github","description":"Open a pull request"}
+{"tool":"stale_tool","server":"removed","description":"Create things"}
+`
+	if err := os.WriteFile(filepath.Join(home, ".tool-hub-mcp-index.jsonl"), []byte(index), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := runSearch(&buf, "make a PR", "", 10, false, false); err != nil {
+		t.Fatalf("runSearch failed: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "1. github/create_pull_request") {
+		t.Errorf("expected github/create_pull_request first, got:\n%s", out)
+	}
+	if strings.Contains(out, "stale_tool") {
+		t.Errorf("tools of unconfigured servers should be skipped:\n%s", out)
+	}
+
+	buf.Reset()
+	if err := runSearch(&buf, "create", "jira", 10, true, false); err != nil {
+		t.Fatalf("runSearch failed: %v", err)
+	}
+	var results []search.SearchResult
+	if err := json.Unmarshal(buf.Bytes(), &results); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, buf.String())
+	}
+	if len(results) != 1 || results[0].ServerName != "jira" {
+		t.Errorf("expected only the jira tool, got %+v", results)
+	}
+
+	if err := runSearch(&buf, "create", "missing", 10, false, false); err == nil {
+		t.Error("expected error for unknown server")
+	}
+}
+
+// TestRunSearchAppliesQueryAffinity checks the CLI ranks with what was
+// executed after earlier searches, like hub_search.
+func TestRunSearchAppliesQueryAffinity(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	cfg := config.NewConfig()
+	cfg.Servers["jira"] = &config.ServerConfig{Command: "/nonexistent/jira"}
+	cfg.Servers["linear"] = &config.ServerConfig{Command: "/nonexistent/linear"}
+	if err := config.Save(cfg, filepath.Join(home, ".tool-hub-mcp.json")); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+	index := `{"tool":"create_issue","server":"jira","description":"Create an issue in a Jira issue tracker project"}
+{"tool":"create_issue","server":"linear","description":"Create an issue in Linear"}
+`
+	if err := os.WriteFile(filepath.Join(home, ".tool-hub-mcp-index.jsonl"), []byte(index), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	first := func() string {
+		t.Helper()
+		var buf bytes.Buffer
+		if err := runSearch(&buf, "create issue", "", 10, true, false); err != nil {
+			t.Fatalf("runSearch failed: %v", err)
+		}
+		var results []search.SearchResult
+		if err := json.Unmarshal(buf.Bytes(), &results); err != nil || len(results) != 2 {
+			t.Fatalf("expected 2 results, got %v (%v)", buf.String(), err)
+		}
+		return results[0].ServerName
+	}
+	before := first()
+	picked := "jira"
+	if before == "jira" {
+		picked = "linear"
+	}
+
+	// This user keeps picking the lower-ranked tool after "create issue"
+	store := storage.NewStorage()
+	if err := store.Init(); err != nil {
+		t.Skipf("learning storage unavailable: %v", err)
+	}
+	now := time.Now()
+	if err := store.RecordSearch(storage.SearchRecord{SearchID: "s1", QueryHash: storage.HashQuery("create issue"), Timestamp: now}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		if err := store.RecordUsage(storage.UsageEvent{ToolName: "create_issue", Server: picked, SearchID: "s1", Success: true, Timestamp: now}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	store.Close()
+
+	if got := first(); got != picked {
+		t.Errorf("expected %s first after learning, got %s", picked, got)
+	}
+}
@@ -4,12 +4,14 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
 	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/httpclient"
 	"github.com/khanglvm/tool-hub-mcp/internal/mcp"
 	"github.com/khanglvm/tool-hub-mcp/internal/version"
 	"github.com/spf13/cobra"
@@ -20,9 +22,15 @@ import (
 // This is the main command that exposes the 5 meta-tools via stdio transport:
 // - hub_list, hub_discover, hub_search, hub_execute, hub_help
 func NewServeCmd() *cobra.Command {
+	var features []string
+	var metricsAddr string
+	var httpAddr string
+	var strict bool
+	var noLearning bool
+
 	cmd := &cobra.Command{
 		Use:   "serve",
-		Short: "Run the MCP server (stdio transport)",
+		Short: "Run the MCP server (stdio or HTTP transport)",
 		Long: `Start the tool-hub-mcp server using stdio transport.
 
 This server exposes 5 meta-tools to AI clients:
@@ -32,29 +40,72 @@ This server exposes 5 meta-tools to AI clients:
   • hub_execute  - Execute a tool from a specific server
   • hub_help     - Get detailed help/schema for a tool
 
-The server spawns child MCP servers on-demand when tools are executed.`,
+The server spawns child MCP servers on-demand when tools are executed.
+
+With --strict, every configured server is validated, spawned and
+initialized before serving, and the server exits with an error if any
+fails instead of continuing with the rest.
+
+With --http, the server listens for MCP over HTTP at /mcp instead of
+stdio, so several clients can share one hub. Each client gets its own
+session (protocol version, searchIds, in-flight requests).`,
 		Example: `  # Run directly
   tool-hub-mcp serve
 
   # Add to Claude Code
-  claude mcp add tool-hub -- tool-hub-mcp serve`,
+  claude mcp add tool-hub -- tool-hub-mcp serve
+
+  # Opt into an experimental feature
+  tool-hub-mcp serve --enable-feature semanticSearch
+
+  # Expose Prometheus metrics at http://127.0.0.1:9464/metrics
+  tool-hub-mcp serve --metrics-addr 127.0.0.1:9464
+
+  # Fail fast if any server is misconfigured (CI smoke tests)
+  tool-hub-mcp serve --strict
+
+  # Share one hub between several clients over HTTP
+  tool-hub-mcp serve --http 127.0.0.1:8765`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runServe()
+			return runServe(features, metricsAddr, httpAddr, strict, noLearning)
 		},
 	}
 
+	cmd.Flags().StringSliceVar(&features, "enable-feature", nil, "Enable an experimental feature (semanticSearch, passthrough, thompsonBandit)")
+	cmd.Flags().StringVar(&metricsAddr, "metrics-addr", "", "Serve Prometheus metrics at /metrics on this address (e.g. 127.0.0.1:9464)")
+	cmd.Flags().StringVar(&httpAddr, "http", "", "Serve MCP over HTTP at /mcp on this address instead of stdio (e.g. 127.0.0.1:8765)")
+	cmd.Flags().BoolVar(&strict, "strict", false, "Refuse to start if any configured server fails validation, spawn or initialize")
+	cmd.Flags().BoolVar(&noLearning, "no-learning", false, "Disable usage tracking and learned ranking; no history database is created")
+
 	return cmd
 }
 
-// runServe starts the MCP server with stdio transport and signal handling.
+// runServe starts the MCP server with stdio (or, given httpAddr, HTTP)
+// transport and signal handling.
 // Implements graceful shutdown on SIGINT/SIGTERM/SIGQUIT.
-func runServe() error {
+func runServe(features []string, metricsAddr, httpAddr string, strict, noLearning bool) error {
 	// Load configuration (creates empty config if missing)
 	cfg, err := config.LoadOrCreate()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
+	// Command-line feature flags add to those enabled in settings
+	if err := cfg.EnableFeatures(features); err != nil {
+		return err
+	}
+	if noLearning {
+		cfg.DisableLearning()
+	}
+
+	// Apply proxy and CA settings to all outbound HTTP
+	configureNetwork(cfg)
+
+	// Install an update downloaded by a previous run before spawning anything
+	if cfg.Settings.AutoUpdateEnabled() {
+		applyPendingUpdate()
+	}
+
 	// Create MCP server
 	server := mcp.NewServer(cfg)
 
@@ -73,24 +124,55 @@ func runServe() error {
 			if err != nil {
 				log.Printf("Failed to reload config: %v", err)
 			} else {
+				newCfg.EnableFeatures(features)
+				if noLearning {
+					newCfg.DisableLearning()
+				}
 				server.ReloadConfig(newCfg)
 			}
 		}
 	}
 
+	// In strict mode every server must come up before we serve
+	if strict {
+		log.Printf("Strict mode: verifying all servers before serving...")
+		if err := server.VerifyServers(); err != nil {
+			server.Close()
+			return fmt.Errorf("strict mode: %w", err)
+		}
+	}
+
 	// Setup signal handling for graceful shutdown
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM, syscall.SIGQUIT)
 
 	// Start background tasks with server context
-	go checkForUpdates(server.Context())
-	server.StartBackgroundDiscovery()
+	if cfg.Settings.UpdatesEnabled() {
+		go checkForUpdates(server.Context(), cfg.Settings.UpdateChannel())
+	}
+	if !strict {
+		// Warm up preload servers in parallel; discovery reuses them
+		go server.PreloadServers()
+
+		// Strict mode already indexed every server
+		server.StartBackgroundDiscovery()
+	}
+
+	if metricsAddr != "" {
+		metricsServer := startMetricsServer(metricsAddr, server)
+		defer metricsServer.Close()
+	}
 
 	// Run server in separate goroutine
 	errChan := make(chan error, 1)
-	go func() {
-		errChan <- server.Run()
-	}()
+	if httpAddr != "" {
+		httpServer := startMCPHTTPServer(httpAddr, server, errChan)
+		defer httpServer.Close()
+	} else {
+		go func() {
+			errChan <- server.Run()
+		}()
+	}
 
 	// Wait for either signal or server error
 	select {
@@ -119,8 +201,67 @@ func runServe() error {
 	}
 }
 
-// checkForUpdates checks for new version in background (context-aware).
-func checkForUpdates(parentCtx context.Context) {
+// startMetricsServer serves the hub's Prometheus metrics on a dedicated
+// listener, since MCP itself runs over stdio. Listener errors are logged
+// and do not stop the MCP server.
+func startMetricsServer(addr string, server *mcp.Server) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", server.Metrics().Handler())
+
+	httpServer := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go func() {
+		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Printf("Warning: metrics endpoint on %s stopped: %v", addr, err)
+		}
+	}()
+	log.Printf("Serving metrics on http://%s/metrics", addr)
+	return httpServer
+}
+
+// startMCPHTTPServer serves MCP at /mcp. A listener error is sent to
+// errChan, which stops the hub.
+func startMCPHTTPServer(addr string, server *mcp.Server, errChan chan<- error) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/mcp", server)
+
+	httpServer := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go func() {
+		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			errChan <- fmt.Errorf("HTTP transport on %s: %w", addr, err)
+		}
+	}()
+	log.Printf("Serving MCP on http://%s/mcp", addr)
+	return httpServer
+}
+
+// configureNetwork applies settings.network to the shared HTTP client.
+// Invalid settings are logged and the environment defaults are kept.
+func configureNetwork(cfg *config.Config) {
+	if cfg.Settings == nil || cfg.Settings.Network == nil {
+		return
+	}
+
+	network := cfg.Settings.Network
+	if err := httpclient.Configure(httpclient.Options{
+		Proxy:    network.Proxy,
+		CABundle: network.CABundle,
+	}); err != nil {
+		log.Printf("Warning: invalid network settings, using environment defaults: %v", err)
+	}
+}
+
+// checkForUpdates checks the release channel for a new version in
+// background (context-aware). Package-managed installs only log the
+// upgrade command; standalone binaries download the update.
+func checkForUpdates(parentCtx context.Context, channel string) {
 	// Check if cancelled before starting
 	select {
 	case <-parentCtx.Done():
@@ -131,7 +272,7 @@ func checkForUpdates(parentCtx context.Context) {
 	ctx, cancel := context.WithTimeout(parentCtx, 10*time.Second)
 	defer cancel()
 
-	latest, err := version.CheckUpdate(ctx)
+	latest, err := version.CheckUpdate(ctx, channel)
 	if err != nil {
 		log.Printf("Update check failed: %v", err)
 		return
@@ -139,6 +280,12 @@ func checkForUpdates(parentCtx context.Context) {
 
 	if latest != "" && latest != version.Version {
 		log.Printf("Update available: %s (current: %s)", latest, version.Version)
+
+		if installer := version.CurrentInstaller(); installer != nil {
+			log.Printf("Installed via %s; upgrade with: %s", installer.Name, installer.UpgradeCommand)
+			return
+		}
+
 		log.Printf("Downloading in background...")
 
 		tempPath, err := version.DownloadUpdate(ctx, latest)
@@ -150,3 +297,32 @@ func checkForUpdates(parentCtx context.Context) {
 		log.Printf("Update downloaded to %s. Will apply on next restart.", tempPath)
 	}
 }
+
+// applyPendingUpdate installs a verified pending update and re-execs the new
+// binary with the same arguments. Returns normally if there is nothing to
+// apply or the restart is not possible.
+func applyPendingUpdate() {
+	// Development builds are never replaced
+	if version.Version == "dev" {
+		return
+	}
+
+	applied, err := version.ApplyPendingUpdate()
+	if err != nil {
+		log.Printf("Warning: failed to apply pending update: %v", err)
+		return
+	}
+	if applied == "" {
+		return
+	}
+	log.Printf("Updated to %s (from %s), restarting...", applied, version.Version)
+
+	execPath, err := os.Executable()
+	if err != nil {
+		log.Printf("Warning: restart failed, update takes effect next start: %v", err)
+		return
+	}
+	if err := syscall.Exec(execPath, os.Args, os.Environ()); err != nil {
+		log.Printf("Warning: restart failed, update takes effect next start: %v", err)
+	}
+}
@@ -7,10 +7,16 @@ allowing for clean separation and easy testing.
 package cli
 
 import (
+	"bufio"
 	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
 
 	"github.com/khanglvm/tool-hub-mcp/internal/config"
 	"github.com/khanglvm/tool-hub-mcp/internal/config/sources"
+	"github.com/khanglvm/tool-hub-mcp/internal/redact"
 	"github.com/spf13/cobra"
 )
 
@@ -20,9 +26,13 @@ import (
 // 1. Scans for AI CLI tools (Claude Code, OpenCode, etc.)
 // 2. Presents found configurations for selection
 // 3. Imports and transforms selected configs to unified camelCase format
-// 4. Saves to ~/.tool-hub-mcp.json
+// 4. Saves to ~/.config/tool-hub-mcp/config.json
 func NewSetupCmd() *cobra.Command {
-	var nonInteractive bool
+	var (
+		nonInteractive bool
+		sandbox        string
+		opts           setupOptions
+	)
 
 	cmd := &cobra.Command{
 		Use:   "setup",
@@ -34,7 +44,20 @@ The setup wizard will:
   1. Detect installed AI CLI tools
   2. Parse their MCP configurations
   3. Transform to unified camelCase format
-  4. Save to ~/.tool-hub-mcp.json
+  4. Save to ~/.config/tool-hub-mcp/config.json
+
+With --replace, the imported servers are then disabled in the source
+configs (backed up as .bak) and replaced with a single tool-hub-mcp entry,
+so the AI client stops loading every server's tools directly.
+
+With --sandbox, the imported servers are restricted when they run:
+"no-network" cuts off network access, "read-only" makes the file system
+read-only, and "strict" does both. Use it for untrusted community servers;
+the "sandbox" field of each server can be adjusted afterwards.
+
+With --dry-run, the servers that would be imported are listed and nothing
+is written. --source limits the scan to some tools and --exclude skips
+servers by name, e.g. for scripted setups in CI.
 
 Supported sources:
   • Claude Code (~/.claude.json, .mcp.json)
@@ -42,29 +65,106 @@ Supported sources:
   • Google Antigravity (~/.gemini/antigravity/mcp_config.json)
   • Gemini CLI (~/.gemini/settings.json)
   • Cursor (~/.cursor/mcp.json)
-  • Windsurf (~/.codeium/windsurf/mcp_config.json)`,
+  • Windsurf (~/.codeium/windsurf/mcp_config.json)
+  • VS Code / Copilot (mcp.json, settings.json, .vscode/mcp.json)
+  • Cline (cline_mcp_settings.json)
+  • Roo Code (~/.roo/mcp.json, .roo/mcp.json)
+  • Zed (~/.config/zed/settings.json)`,
 		Example: `  # Interactive setup
   tool-hub-mcp setup
 
   # Non-interactive (import all found configs)
-  tool-hub-mcp setup --yes`,
+  tool-hub-mcp setup --yes
+
+  # Import, then point the source tools at tool-hub-mcp instead
+  tool-hub-mcp setup --replace
+
+  # Import with every server sandboxed (no network, read-only file system)
+  tool-hub-mcp setup --sandbox strict
+
+  # Preview an import from Claude Code only, without one server
+  tool-hub-mcp setup --dry-run --source claude-code --exclude playwright`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runSetup(nonInteractive)
+			sandboxSettings, err := parseSandboxFlag(sandbox)
+			if err != nil {
+				return err
+			}
+			opts.sandbox = sandboxSettings
+			return runSetup(nonInteractive, opts)
 		},
 	}
 
 	cmd.Flags().BoolVarP(&nonInteractive, "yes", "y", false, "Non-interactive mode (import all)")
+	cmd.Flags().BoolVar(&opts.replace, "replace", false, "Disable imported servers in source configs and register tool-hub-mcp there (with backup)")
+	cmd.Flags().StringVar(&sandbox, "sandbox", "", "Sandbox imported servers: no-network, read-only or strict (comma-separated)")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "List the servers that would be imported without writing anything")
+	cmd.Flags().StringSliceVar(&opts.sources, "source", nil, "Only scan these tools, e.g. claude-code,vscode (comma-separated)")
+	cmd.Flags().StringSliceVar(&opts.exclude, "exclude", nil, "Skip servers with these names (repeatable or comma-separated)")
 
 	return cmd
 }
 
+// setupOptions are the flags shaping what setup imports.
+type setupOptions struct {
+	replace bool
+	sandbox *config.SandboxSettings
+	dryRun  bool
+
+	// sources limits the scan to these source names (empty scans all)
+	sources []string
+
+	// exclude skips servers by name, as written in the source or camelCase
+	exclude []string
+}
+
+// selectSources returns the sources named in names, or all of them when
+// names is empty. Unknown names are an error listing the known ones.
+func selectSources(all []sources.Source, names []string) ([]sources.Source, error) {
+	if len(names) == 0 {
+		return all, nil
+	}
+
+	byName := make(map[string]sources.Source, len(all))
+	known := make([]string, 0, len(all))
+	for _, source := range all {
+		byName[source.Name()] = source
+		known = append(known, source.Name())
+	}
+
+	var selected []sources.Source
+	for _, name := range names {
+		source, ok := byName[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("unknown source '%s' (available: %s)", name, strings.Join(known, ", "))
+		}
+		selected = append(selected, source)
+	}
+	return selected, nil
+}
+
+// isExcluded reports whether a server named name in its source matches
+// an --exclude entry, comparing both the original and camelCase names.
+func isExcluded(name string, exclude []string) bool {
+	for _, entry := range exclude {
+		entry = strings.TrimSpace(entry)
+		if strings.EqualFold(entry, name) || config.ToCamelCase(entry) == config.ToCamelCase(name) {
+			return true
+		}
+	}
+	return false
+}
+
 // runSetup executes the setup wizard logic.
-func runSetup(nonInteractive bool) error {
+func runSetup(nonInteractive bool, opts setupOptions) error {
+	// Scan the selected config sources
+	allSources, err := selectSources(sources.GetAllSources(), opts.sources)
+	if err != nil {
+		return err
+	}
+
 	fmt.Println("🔍 Scanning for AI CLI tools...")
 	fmt.Println()
 
-	// Scan all config sources
-	allSources := sources.GetAllSources()
 	foundConfigs := make(map[string]*sources.SourceResult)
 
 	for _, source := range allSources {
@@ -99,10 +199,18 @@ func runSetup(nonInteractive bool) error {
 	skippedCount := 0
 	skipReasons := make(map[string]int)
 
-	for sourceName, result := range foundConfigs {
-		for name, server := range result.Servers {
-			// Transform server name to camelCase
-			camelName := config.ToCamelCase(name)
+	for _, sourceName := range sortedKeys(foundConfigs) {
+		result := foundConfigs[sourceName]
+		for _, name := range sortedKeys(result.Servers) {
+			server := result.Servers[name]
+			if isExcluded(name, opts.exclude) {
+				skipReasons["excluded"]++
+				skippedCount++
+				continue
+			}
+
+			// Transform server name to camelCase, suffixing on collisions
+			camelName, exists := config.UniqueServerName(mergedConfig.Servers, name)
 
 			// Validation 1: Self-reference check
 			if config.IsSelfReference(server) {
@@ -111,8 +219,16 @@ func runSetup(nonInteractive bool) error {
 				continue
 			}
 
-			// Validation 2: Empty command check
-			if server.Command == "" {
+			// Split single-string commands ("npx -y @pkg/mcp")
+			if _, err := server.NormalizeCommand(); err != nil {
+				fmt.Printf("  ⚠️  Skipping %s: %v\n", camelName, err)
+				skipReasons["invalid-command"]++
+				skippedCount++
+				continue
+			}
+
+			// Validation 2: Empty command check (remote servers have a URL)
+			if server.Command == "" && !server.IsRemote() {
 				fmt.Printf("  ⚠️  Skipping %s: empty command\n", camelName)
 				skipReasons["empty-command"]++
 				skippedCount++
@@ -120,35 +236,62 @@ func runSetup(nonInteractive bool) error {
 			}
 
 			// Validation 3: Duplicate name check
-			if _, exists := mergedConfig.Servers[camelName]; exists {
+			if exists {
 				fmt.Printf("  ⚠️  Server '%s' already exists, skipping\n", camelName)
 				skipReasons["duplicate"]++
 				skippedCount++
 				continue
 			}
 
+			// Validation 4: Same server under another name
+			if key, dup := mergedConfig.FindDuplicate(server); dup && confirmMerge(name, sourceName, key, mergedConfig.Servers[key], !nonInteractive && !opts.dryRun && stdinIsTerminal()) {
+				if !opts.dryRun {
+					mergedConfig.MergeDuplicate(key, name)
+				}
+				fmt.Printf("  🔗 %s (%s) runs the same server as '%s', merged as an alias\n", name, sourceName, key)
+				skipReasons["merged"]++
+				skippedCount++
+				continue
+			}
+			warnNameCollision(name, camelName)
+
 			// Add source metadata
 			server.Source = sourceName
+			server.SetOriginalName(camelName, name)
+			if opts.sandbox != nil {
+				restricted := *opts.sandbox
+				server.Sandbox = &restricted
+			}
 
 			mergedConfig.Servers[camelName] = server
 			totalImported++
+			if opts.dryRun {
+				if server.IsRemote() {
+					fmt.Printf("  + %s (%s): %s\n", camelName, sourceName, redact.URL(server.URL))
+				} else {
+					fmt.Printf("  + %s (%s): %s %v\n", camelName, sourceName, server.Command, redact.Args(server.Args))
+				}
+			}
 		}
 	}
 
-	// Save config
 	configPath, err := config.GetDefaultConfigPath()
 	if err != nil {
 		return fmt.Errorf("failed to get config path: %w", err)
 	}
 
-	if err := config.Save(mergedConfig, configPath); err != nil {
-		return fmt.Errorf("failed to save config: %w", err)
-	}
+	if opts.dryRun {
+		fmt.Printf("\nDry run: would import %d MCP servers to %s (nothing written)\n", totalImported, configPath)
+	} else {
+		if err := config.Save(mergedConfig, configPath); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
 
-	// Auto-regenerate tool index for bash/grep access
-	RegenerateIndex()
+		// Auto-regenerate tool index for bash/grep access
+		RegenerateIndex()
 
-	fmt.Printf("✓ Imported %d MCP servers to %s\n", totalImported, configPath)
+		fmt.Printf("✓ Imported %d MCP servers to %s\n", totalImported, configPath)
+	}
 
 	// Show skip summary
 	if skippedCount > 0 {
@@ -158,6 +301,18 @@ func runSetup(nonInteractive bool) error {
 		}
 	}
 
+	if opts.dryRun {
+		if opts.replace {
+			fmt.Printf("Would replace the imported servers in: %s\n", strings.Join(sortedKeys(foundConfigs), ", "))
+		}
+		return nil
+	}
+
+	if opts.replace {
+		replaceSourceServers(foundConfigs, mergedConfig)
+		return nil
+	}
+
 	fmt.Println()
 	fmt.Println("Next steps:")
 	fmt.Println("  Add tool-hub-mcp to your AI client:")
@@ -171,6 +326,39 @@ func runSetup(nonInteractive bool) error {
 	return nil
 }
 
+// replaceSourceServers rewrites each source config so the servers now served
+// by the hub are disabled and a single tool-hub-mcp entry takes their place.
+// Failures are reported per source and do not abort the others.
+func replaceSourceServers(foundConfigs map[string]*sources.SourceResult, hubConfig *config.Config) {
+	fmt.Println()
+	fmt.Println("🔁 Replacing imported servers in source configs...")
+
+	for sourceName, result := range foundConfigs {
+		target, err := sources.TargetForSource(sourceName, result.ConfigPath)
+		if err != nil {
+			fmt.Printf("  ⚠️  %s: %v\n", sourceName, err)
+			continue
+		}
+
+		installed, err := sources.Install(target, sources.InstallOptions{
+			DisableServers: aggregatedServers(result.Servers, hubConfig),
+		})
+		if err != nil {
+			fmt.Printf("  ⚠️  %s: %v\n", sourceName, err)
+			continue
+		}
+
+		fmt.Printf("  ✓ %s (%s) - disabled %d servers, added '%s'\n",
+			sourceName, installed.Path, len(installed.Disabled), sources.HubEntryName)
+		if installed.BackupPath != "" {
+			fmt.Printf("    Backup: %s\n", installed.BackupPath)
+		}
+	}
+
+	fmt.Println()
+	fmt.Println("Restart your AI clients to pick up the change.")
+}
+
 // RunSetupNonInteractive imports all configs without prompting.
 // Used by serve command for background setup on first run.
 func RunSetupNonInteractive() (int, error) {
@@ -200,28 +388,46 @@ func RunSetupNonInteractive() (int, error) {
 	}
 	totalImported := 0
 
-	for sourceName, result := range foundConfigs {
-		for name, server := range result.Servers {
-			// Transform server name to camelCase
-			camelName := config.ToCamelCase(name)
+	for _, sourceName := range sortedKeys(foundConfigs) {
+		result := foundConfigs[sourceName]
+		for _, name := range sortedKeys(result.Servers) {
+			server := result.Servers[name]
+
+			// Transform server name to camelCase, suffixing on collisions
+			camelName, exists := config.UniqueServerName(mergedConfig.Servers, name)
 
 			// Validation 1: Self-reference check
 			if config.IsSelfReference(server) {
 				continue
 			}
 
-			// Validation 2: Empty command check
-			if server.Command == "" {
+			// Split single-string commands ("npx -y @pkg/mcp")
+			if _, err := server.NormalizeCommand(); err != nil {
+				continue
+			}
+
+			// Validation 2: Empty command check (remote servers have a URL)
+			if server.Command == "" && !server.IsRemote() {
 				continue
 			}
 
 			// Validation 3: Duplicate name check
-			if _, exists := mergedConfig.Servers[camelName]; exists {
+			if exists {
+				continue
+			}
+
+			// Validation 4: Same server under another name
+			if key, dup := mergedConfig.FindDuplicate(server); dup {
+				mergedConfig.MergeDuplicate(key, name)
 				continue
 			}
+			if camelName != config.ToCamelCase(name) {
+				log.Printf("Warning: server name '%s' collides with an existing server, imported as '%s'", name, camelName)
+			}
 
 			// Add source metadata
 			server.Source = sourceName
+			server.SetOriginalName(camelName, name)
 
 			mergedConfig.Servers[camelName] = server
 			totalImported++
@@ -240,3 +446,41 @@ func RunSetupNonInteractive() (int, error) {
 
 	return totalImported, nil
 }
+
+// confirmMerge decides whether a server named name in source, which runs
+// the same thing as the configured server key, is merged into it. With ask
+// the user chooses (merging by default); otherwise duplicates are always
+// merged so the server is not spawned twice.
+func confirmMerge(name, source, key string, existing *config.ServerConfig, ask bool) bool {
+	if !ask {
+		return true
+	}
+	from := existing.Source
+	if from == "" {
+		from = "config"
+	}
+	fmt.Printf("  '%s' (%s) has the same command, args and env as '%s' (%s). Merge them? [Y/n] ", name, source, key, from)
+	reader := bufio.NewReader(os.Stdin)
+	response, _ := reader.ReadString('\n')
+	response = strings.TrimSpace(strings.ToLower(response))
+	return response == "" || response == "y" || response == "yes"
+}
+
+// warnNameCollision tells the user when a server was stored under a
+// suffixed key because its camelCase name was already taken.
+func warnNameCollision(name, key string) {
+	if base := config.ToCamelCase(name); key != base {
+		fmt.Printf("  ⚠️  '%s' collides with existing server '%s', added as '%s'\n", name, base, key)
+	}
+}
+
+// sortedKeys returns a map's keys in sorted order, so imports assign
+// collision suffixes deterministically.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"strings"
 	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config/sources"
 )
 
 func TestNewSetupCmd(t *testing.T) {
@@ -55,6 +57,10 @@ func TestSetupCommandFlags(t *testing.T) {
 	if cmd.Flags().Lookup("yes") == nil {
 		t.Error("Flag 'yes' not registered")
 	}
+
+	if cmd.Flags().Lookup("replace") == nil {
+		t.Error("Flag 'replace' not registered")
+	}
 }
 
 func TestSetupCommandFlagValues(t *testing.T) {
@@ -130,3 +136,66 @@ func TestSetupCommandExecution(t *testing.T) {
 		})
 	}
 }
+
+func TestParseSandboxFlag(t *testing.T) {
+	sandbox, err := parseSandboxFlag("no-network, read-only")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sandbox.NoNetwork() || !sandbox.ReadOnly() {
+		t.Errorf("sandbox = %+v, want no network and read-only", sandbox)
+	}
+
+	if sandbox, _ := parseSandboxFlag("read-only"); sandbox.NoNetwork() || !sandbox.ReadOnly() {
+		t.Errorf("read-only should leave the network alone")
+	}
+	if sandbox, _ := parseSandboxFlag(""); sandbox != nil {
+		t.Errorf("empty flag should not sandbox, got %+v", sandbox)
+	}
+	if _, err := parseSandboxFlag("offline"); err == nil || !strings.Contains(err.Error(), "unknown sandbox restriction") {
+		t.Errorf("error = %v, want unknown restriction", err)
+	}
+}
+
+func TestSetupSelectiveFlags(t *testing.T) {
+	cmd := NewSetupCmd()
+	if err := cmd.ParseFlags([]string{"--dry-run", "--source", "claude-code,vscode", "--exclude", "playwright", "--exclude", "my-server"}); err != nil {
+		t.Fatalf("ParseFlags() failed: %v", err)
+	}
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); !dryRun {
+		t.Error("dry-run flag not set")
+	}
+	if got, _ := cmd.Flags().GetStringSlice("source"); len(got) != 2 || got[1] != "vscode" {
+		t.Errorf("source = %v", got)
+	}
+	if got, _ := cmd.Flags().GetStringSlice("exclude"); len(got) != 2 {
+		t.Errorf("exclude = %v", got)
+	}
+}
+
+func TestSelectSources(t *testing.T) {
+	all := sources.GetAllSources()
+
+	selected, err := selectSources(all, nil)
+	if err != nil || len(selected) != len(all) {
+		t.Errorf("no --source should keep all sources, got %d, %v", len(selected), err)
+	}
+
+	selected, err = selectSources(all, []string{"Claude-Code", " zed"})
+	if err != nil || len(selected) != 2 || selected[0].Name() != "claude-code" || selected[1].Name() != "zed" {
+		t.Errorf("unexpected selection: %v, %v", selected, err)
+	}
+
+	if _, err := selectSources(all, []string{"cursor"}); err == nil || !strings.Contains(err.Error(), "available: claude-code") {
+		t.Errorf("expected an unknown source error listing sources, got %v", err)
+	}
+}
+
+func TestIsExcluded(t *testing.T) {
+	exclude := []string{"my-server", "Playwright"}
+	for name, want := range map[string]bool{"my-server": true, "myServer": true, "playwright": true, "github": false} {
+		if got := isExcluded(name, exclude); got != want {
+			t.Errorf("isExcluded(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
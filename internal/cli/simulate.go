@@ -0,0 +1,278 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/benchmark"
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/mcp"
+	"github.com/khanglvm/tool-hub-mcp/internal/protocol"
+	"github.com/khanglvm/tool-hub-mcp/internal/spawner"
+	"github.com/spf13/cobra"
+)
+
+// simulationStep is one tool call in a simulated workflow.
+type simulationStep struct {
+	Search  string       `json:"search,omitempty"`
+	Execute *executeStep `json:"execute,omitempty"`
+}
+
+// executeStep calls a child tool.
+type executeStep struct {
+	Server    string                 `json:"server"`
+	Tool      string                 `json:"tool"`
+	Arguments map[string]interface{} `json:"arguments,omitempty"`
+}
+
+// simulationScript is the --script file format.
+type simulationScript struct {
+	Steps []simulationStep `json:"steps"`
+}
+
+// NewSimulateBenchmarkCmd creates the 'benchmark simulate' command.
+func NewSimulateBenchmarkCmd() *cobra.Command {
+	var searches int
+	var executions int
+	var scriptPath string
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "simulate",
+		Short: "Replay a workflow through the hub and a traditional setup",
+		Long: `Replay a scripted workflow of tool calls two ways and count the tokens the
+model actually reads:
+
+TOOL-HUB-MCP: hub_search for each search step and hub_execute for each
+execution, through an in-process hub with the meta-tool definitions in context.
+
+TRADITIONAL: every child tool definition in context and each execution sent
+straight to the child; searches are not needed.
+
+Each tool call is a model turn that rereads the definitions and the history so
+far. Without --script, searches use your tools' names as queries and
+executions call side-effect-free tools (ping, health, version) when a server
+has one. Scripted executions really run, once per setup.
+
+Script format:
+  {"steps": [
+    {"search": "create jira issue"},
+    {"execute": {"server": "jira", "tool": "get_issue", "arguments": {"key": "PROJ-1"}}}
+  ]}`,
+		Example: `  # 5 searches and 3 executions generated from your servers
+  tool-hub-mcp benchmark simulate --searches 5 --executions 3
+
+  # Replay a recorded workflow
+  tool-hub-mcp benchmark simulate --script workflow.json --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSimulate(cmd.OutOrStdout(), searches, executions, scriptPath, jsonOutput)
+		},
+	}
+
+	cmd.Flags().IntVar(&searches, "searches", 3, "Number of generated search steps")
+	cmd.Flags().IntVar(&executions, "executions", 2, "Number of generated execution steps")
+	cmd.Flags().StringVar(&scriptPath, "script", "", "JSON workflow to replay instead of generated steps")
+	cmd.Flags().BoolVarP(&jsonOutput, "json", "j", false, "Output as JSON")
+
+	return cmd
+}
+
+// runSimulate replays the workflow and prints the token comparison.
+func runSimulate(w io.Writer, searches, executions int, scriptPath string, jsonOutput bool) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if len(cfg.Servers) == 0 {
+		return fmt.Errorf("no servers configured")
+	}
+
+	tools := loadCachedTools(cfg)
+	if len(tools) == 0 {
+		tools = collectTools(cfg)
+	}
+	byServer := toolsByServer(tools)
+
+	var steps []simulationStep
+	if scriptPath != "" {
+		if steps, err = readSimulationScript(scriptPath); err != nil {
+			return err
+		}
+	} else {
+		steps = generateSteps(byServer, searches, executions)
+	}
+
+	hub := mcp.NewServer(cfg)
+	defer hub.Close()
+	if err := hub.IndexTools(); err != nil {
+		return fmt.Errorf("failed to index tools: %w", err)
+	}
+
+	hubDefinitions, err := callHub(hub, "tools/list", nil)
+	if err != nil {
+		return err
+	}
+
+	pool := spawner.NewPool(cfg.Settings.ProcessPoolLimit())
+	pool.SetDefaultEnv(cfg.Settings.ChildEnv())
+	defer pool.Close()
+
+	var hubExchanges, traditionalExchanges []benchmark.Exchange
+	searchCount, executionCount := 0, 0
+	for i, step := range steps {
+		switch {
+		case step.Search != "":
+			params := map[string]interface{}{
+				"name":      "hub_search",
+				"arguments": map[string]interface{}{"query": step.Search},
+			}
+			result, err := callHub(hub, "tools/call", params)
+			if err != nil {
+				return fmt.Errorf("step %d: %w", i+1, err)
+			}
+			hubExchanges = append(hubExchanges, benchmark.Exchange{Request: params, Response: result})
+			searchCount++
+
+		case step.Execute != nil:
+			e := step.Execute
+			serverCfg, ok := cfg.Servers[e.Server]
+			if !ok {
+				return fmt.Errorf("step %d: server '%s' not found", i+1, e.Server)
+			}
+			if e.Arguments == nil {
+				e.Arguments = map[string]interface{}{}
+			}
+
+			params := map[string]interface{}{
+				"name": "hub_execute",
+				"arguments": map[string]interface{}{
+					"server":    e.Server,
+					"tool":      e.Tool,
+					"arguments": e.Arguments,
+				},
+			}
+			result, err := callHub(hub, "tools/call", params)
+			if err != nil {
+				return fmt.Errorf("step %d: %w", i+1, err)
+			}
+			hubExchanges = append(hubExchanges, benchmark.Exchange{Request: params, Response: result})
+
+			direct := map[string]interface{}{"name": e.Tool, "arguments": e.Arguments}
+			childResult, err := pool.CallTool(e.Server, serverCfg, e.Tool, e.Arguments)
+			if err != nil {
+				return fmt.Errorf("step %d: %s/%s failed: %w", i+1, e.Server, e.Tool, err)
+			}
+			traditionalExchanges = append(traditionalExchanges, benchmark.Exchange{Request: direct, Response: childResult})
+			executionCount++
+
+		default:
+			return fmt.Errorf("step %d: needs either search or execute", i+1)
+		}
+	}
+
+	// The traditional client sees every child tool up front
+	var traditionalDefinitions []protocol.Tool
+	for _, name := range sortedKeys(byServer) {
+		traditionalDefinitions = append(traditionalDefinitions, byServer[name]...)
+	}
+
+	result := benchmark.CompareConversations(searchCount, executionCount,
+		benchmark.SimulateConversation(traditionalDefinitions, traditionalExchanges),
+		benchmark.SimulateConversation(hubDefinitions, hubExchanges))
+
+	if jsonOutput {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	}
+
+	fmt.Fprintf(w, "Simulated workflow: %d searches, %d executions\n\n", result.Searches, result.Executions)
+	if scriptPath == "" && executionCount < executions {
+		fmt.Fprintf(w, "Note: only %d of %d executions ran; no other side-effect-free tools were found.\n\n", executionCount, executions)
+	}
+	writeConversationCosts(w, result)
+	return nil
+}
+
+// writeConversationCosts prints both setups side by side.
+func writeConversationCosts(w io.Writer, r *benchmark.SimulationResult) {
+	rows := []struct {
+		label string
+		cost  benchmark.ConversationCost
+	}{
+		{"Traditional", r.Traditional},
+		{"tool-hub-mcp", r.ToolHub},
+	}
+
+	fmt.Fprintf(w, "%-14s %6s %12s %10s %12s\n", "SETUP", "TURNS", "DEFINITIONS", "EXCHANGED", "TOTAL INPUT")
+	for _, row := range rows {
+		fmt.Fprintf(w, "%-14s %6d %12d %10d %12d\n", row.label, row.cost.Turns,
+			row.cost.DefinitionTokens, row.cost.ExchangeTokens, row.cost.TotalInputTokens)
+	}
+	fmt.Fprintf(w, "\nTokens saved: %d (%.1f%%)\n", r.TokenSavings, r.SavingsPercent)
+}
+
+// callHub sends one request to the in-process hub and returns its result.
+func callHub(hub *mcp.Server, method string, params interface{}) (interface{}, error) {
+	request := map[string]interface{}{"jsonrpc": "2.0", "id": 1, "method": method}
+	if params != nil {
+		request["params"] = params
+	}
+	data, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := hub.HandleRequest(data)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error != nil {
+		return nil, fmt.Errorf("%s failed: %s", method, resp.Error.Message)
+	}
+	return resp.Result, nil
+}
+
+// readSimulationScript loads workflow steps from a JSON file.
+func readSimulationScript(path string) ([]simulationStep, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read script: %w", err)
+	}
+	var script simulationScript
+	if err := json.Unmarshal(data, &script); err != nil {
+		return nil, fmt.Errorf("invalid script: %w", err)
+	}
+	if len(script.Steps) == 0 {
+		return nil, fmt.Errorf("script has no steps")
+	}
+	return script.Steps, nil
+}
+
+// generateSteps builds a deterministic workflow: searches for tool names
+// and executions of side-effect-free tools, cycling when there are fewer
+// candidates than requested steps.
+func generateSteps(byServer map[string][]protocol.Tool, searches, executions int) []simulationStep {
+	var queries []string
+	var noops []*executeStep
+	for _, name := range sortedKeys(byServer) {
+		for _, t := range byServer[name] {
+			queries = append(queries, strings.NewReplacer("_", " ", "-", " ").Replace(t.Name))
+		}
+		if tool := benchmark.FindNoopTool(byServer[name]); tool != "" {
+			noops = append(noops, &executeStep{Server: name, Tool: tool})
+		}
+	}
+
+	var steps []simulationStep
+	for i := 0; i < searches && len(queries) > 0; i++ {
+		steps = append(steps, simulationStep{Search: queries[i%len(queries)]})
+	}
+	for i := 0; i < executions && len(noops) > 0; i++ {
+		steps = append(steps, simulationStep{Execute: noops[i%len(noops)]})
+	}
+	return steps
+}
@@ -0,0 +1,154 @@
+/*
+Package cli provides commands for exporting aggregated usage statistics.
+
+These commands turn the learning database into per-day, per-tool usage
+reports that can be loaded into spreadsheets or dashboards.
+*/
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+// NewStatsCmd creates the stats command group.
+func NewStatsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Usage statistics for team insights",
+		Long: `Report how registered MCP tools are actually used.
+
+Statistics are derived from the local learning database
+(~/.local/share/tool-hub-mcp/history.db) and never leave the machine unless exported.
+
+Commands:
+  export  Export per-day, per-tool usage counts and success rates`,
+	}
+
+	cmd.AddCommand(newStatsExportCmd())
+
+	return cmd
+}
+
+// newStatsExportCmd exports daily usage counts as CSV or JSON.
+func newStatsExportCmd() *cobra.Command {
+	var (
+		format     string
+		outputFile string
+		days       int
+		anonymize  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export per-day, per-tool usage counts and success rates",
+		Example: `  # CSV heatmap of the last 30 days
+  tool-hub-mcp stats export --format csv
+
+  # Last week as JSON, tool names hashed for sharing
+  tool-hub-mcp stats export --format json --days 7 --anonymize
+
+  # Write to a file
+  tool-hub-mcp stats export --format csv -o usage.csv`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "csv" && format != "json" {
+				return fmt.Errorf("unsupported format '%s' (use csv or json)", format)
+			}
+			if days <= 0 {
+				return fmt.Errorf("--days must be positive")
+			}
+
+			store := storage.NewStorage()
+			if err := store.Init(); err != nil {
+				return fmt.Errorf("failed to initialize storage: %w", err)
+			}
+			defer store.Close()
+
+			stats, err := store.GetDailyUsage(time.Now().AddDate(0, 0, -days))
+			if err != nil {
+				return fmt.Errorf("failed to load usage statistics: %w", err)
+			}
+
+			if anonymize {
+				stats = anonymizeUsage(stats)
+			}
+
+			out := cmd.OutOrStdout()
+			if outputFile != "" {
+				file, err := os.Create(outputFile)
+				if err != nil {
+					return fmt.Errorf("failed to create output file: %w", err)
+				}
+				defer file.Close()
+				out = file
+			}
+
+			return writeUsageStats(out, stats, format)
+		},
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", "csv", "Output format: csv or json")
+	cmd.Flags().StringVarP(&outputFile, "output", "o", "", "Output file (default: stdout)")
+	cmd.Flags().IntVarP(&days, "days", "d", 30, "Number of days to include")
+	cmd.Flags().BoolVar(&anonymize, "anonymize", false, "Replace tool names with stable hashes")
+
+	return cmd
+}
+
+// anonymizeUsage replaces tool names with short, stable hashes.
+// The same tool always maps to the same hash, so exports can still be
+// compared over time without revealing which integrations are installed.
+func anonymizeUsage(stats []storage.DailyToolUsage) []storage.DailyToolUsage {
+	result := make([]storage.DailyToolUsage, len(stats))
+	for i, d := range stats {
+		d.ToolName = "tool-" + storage.HashQuery(d.ToolName)[:12]
+		result[i] = d
+	}
+	return result
+}
+
+// writeUsageStats writes daily usage rows in the requested format.
+func writeUsageStats(w io.Writer, stats []storage.DailyToolUsage, format string) error {
+	if format == "json" {
+		type row struct {
+			storage.DailyToolUsage
+			SuccessRate float64 `json:"success_rate"`
+		}
+		rows := make([]row, 0, len(stats))
+		for _, d := range stats {
+			rows = append(rows, row{DailyToolUsage: d, SuccessRate: d.SuccessRate()})
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(rows)
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"day", "tool", "uses", "successes", "failures", "success_rate", "contexts"}); err != nil {
+		return err
+	}
+	for _, d := range stats {
+		record := []string{
+			d.Day,
+			d.ToolName,
+			strconv.Itoa(d.Uses),
+			strconv.Itoa(d.Successes),
+			strconv.Itoa(d.Uses - d.Successes),
+			strconv.FormatFloat(d.SuccessRate(), 'f', 3, 64),
+			strconv.Itoa(d.Contexts),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/storage"
+)
+
+func TestNewStatsCmd(t *testing.T) {
+	cmd := NewStatsCmd()
+	if cmd.Use != "stats" {
+		t.Errorf("Expected Use='stats', got %q", cmd.Use)
+	}
+
+	export, _, err := cmd.Find([]string{"export"})
+	if err != nil || export == nil {
+		t.Fatal("export subcommand not registered")
+	}
+	for _, flag := range []string{"format", "output", "days", "anonymize"} {
+		if export.Flags().Lookup(flag) == nil {
+			t.Errorf("Flag %q not registered", flag)
+		}
+	}
+}
+
+func TestStatsExportInvalidFormat(t *testing.T) {
+	cmd := NewStatsCmd()
+	cmd.SetArgs([]string{"export", "--format", "xml"})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "unsupported format") {
+		t.Errorf("Expected unsupported format error, got %v", err)
+	}
+}
+
+func TestWriteUsageStatsCSV(t *testing.T) {
+	stats := []storage.DailyToolUsage{
+		{Day: "2026-01-01", ToolName: "create_issue", Uses: 4, Successes: 3, Contexts: 2},
+	}
+
+	var buf bytes.Buffer
+	if err := writeUsageStats(&buf, stats, "csv"); err != nil {
+		t.Fatalf("writeUsageStats failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected header + 1 row, got %d lines", len(lines))
+	}
+	if lines[0] != "day,tool,uses,successes,failures,success_rate,contexts" {
+		t.Errorf("Unexpected header: %s", lines[0])
+	}
+	if lines[1] != "2026-01-01,create_issue,4,3,1,0.750,2" {
+		t.Errorf("Unexpected row: %s", lines[1])
+	}
+}
+
+func TestWriteUsageStatsJSON(t *testing.T) {
+	stats := []storage.DailyToolUsage{
+		{Day: "2026-01-01", ToolName: "search", Uses: 2, Successes: 1},
+	}
+
+	var buf bytes.Buffer
+	if err := writeUsageStats(&buf, stats, "json"); err != nil {
+		t.Fatalf("writeUsageStats failed: %v", err)
+	}
+
+	var rows []map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &rows); err != nil {
+		t.Fatalf("Invalid JSON output: %v", err)
+	}
+	if len(rows) != 1 || rows[0]["tool_name"] != "search" || rows[0]["success_rate"] != 0.5 {
+		t.Errorf("Unexpected JSON rows: %v", rows)
+	}
+}
+
+func TestAnonymizeUsage(t *testing.T) {
+	stats := []storage.DailyToolUsage{
+		{Day: "2026-01-01", ToolName: "create_issue"},
+		{Day: "2026-01-02", ToolName: "create_issue"},
+	}
+
+	anon := anonymizeUsage(stats)
+	if anon[0].ToolName == "create_issue" || !strings.HasPrefix(anon[0].ToolName, "tool-") {
+		t.Errorf("Tool name not anonymized: %s", anon[0].ToolName)
+	}
+	if anon[0].ToolName != anon[1].ToolName {
+		t.Error("Anonymized names should be stable for the same tool")
+	}
+	if stats[0].ToolName != "create_issue" {
+		t.Error("anonymizeUsage should not modify its input")
+	}
+}
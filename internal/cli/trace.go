@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+// NewTraceCmd creates the 'trace' command for following a search to the
+// executions it led to.
+func NewTraceCmd() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "trace <searchId>",
+		Short: "Show what happened after a hub_search",
+		Long: `Show the decision chain of one hub_search: the query, the results it showed,
+and every hub_execute call made with its searchId (tool chosen, its rank in the
+results, argument hash, latency and outcome).
+
+The query text is only recorded when "settings": {"audit": {"recordQueries": true}}
+is set; otherwise its SHA256 hash is shown.`,
+		Example: `  tool-hub-mcp trace 3f2b6c1e-8d4a-4c2e-9f57-0a1b2c3d4e5f
+  tool-hub-mcp trace 3f2b6c1e-8d4a-4c2e-9f57-0a1b2c3d4e5f --json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store := storage.NewStorage()
+			if err := store.Init(); err != nil {
+				return fmt.Errorf("failed to initialize storage: %w", err)
+			}
+			defer store.Close()
+
+			trace, err := store.GetTrace(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to load trace: %w", err)
+			}
+			if trace == nil {
+				return fmt.Errorf("no search with id '%s' in the history", args[0])
+			}
+			return writeTrace(cmd.OutOrStdout(), trace, jsonOutput)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&jsonOutput, "json", "j", false, "Output as JSON")
+
+	return cmd
+}
+
+// writeTrace prints a trace as text or JSON.
+func writeTrace(w io.Writer, trace *storage.Trace, jsonOutput bool) error {
+	if jsonOutput {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(trace)
+	}
+
+	search := trace.Search
+	fmt.Fprintf(w, "Search %s at %s\n", search.SearchID, search.Timestamp.Local().Format("2006-01-02 15:04:05"))
+	if search.Query != "" {
+		fmt.Fprintf(w, "  Query: %s\n", search.Query)
+	} else {
+		fmt.Fprintf(w, "  Query hash: %s (query text not recorded)\n", search.QueryHash)
+	}
+
+	ranks := make(map[string]int, len(search.Results))
+	fmt.Fprintf(w, "  Results (%d):\n", search.ResultsCount)
+	for i, id := range search.Results {
+		ranks[id] = i + 1
+		fmt.Fprintf(w, "    %d. %s\n", i+1, id)
+	}
+
+	if len(trace.Executions) == 0 {
+		fmt.Fprintln(w, "\nNo executions followed this search.")
+		return nil
+	}
+
+	fmt.Fprintf(w, "\nExecutions (%d):\n", len(trace.Executions))
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  TIME\tTOOL\tRANK\tARGS\tDURATION\tRESULT\tEXECUTION ID")
+	for _, e := range trace.Executions {
+		id := e.Server + "/" + e.Tool
+		rank := "not shown"
+		if r, ok := ranks[id]; ok {
+			rank = fmt.Sprintf("%d", r)
+		}
+		result := "ok"
+		if !e.Success {
+			result = "error: " + e.Error
+			if len(result) > 60 {
+				result = result[:57] + "..."
+			}
+		}
+		fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\t%dms\t%s\t%s\n",
+			e.Timestamp.Local().Format("15:04:05"), id, rank,
+			e.ArgsHash[:min(12, len(e.ArgsHash))], e.DurationMs, result, e.ExecutionID)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/storage"
+)
+
+func TestWriteTrace(t *testing.T) {
+	trace := &storage.Trace{
+		Search: storage.SearchRecord{
+			SearchID:     "s-1",
+			QueryHash:    "abc123",
+			Timestamp:    time.Now(),
+			ResultsCount: 2,
+			Results:      []string{"jira/search_issues", "jira/create_issue"},
+		},
+		Executions: []storage.AuditEntry{
+			{Timestamp: time.Now(), Server: "jira", Tool: "create_issue", ArgsHash: "0123456789abcdef", DurationMs: 42, Success: true, ExecutionID: "e-1"},
+			{Timestamp: time.Now(), Server: "github", Tool: "create_issue", Success: false, Error: "auth failed", ExecutionID: "e-2"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := writeTrace(&buf, trace, false); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{"Query hash: abc123", "2. jira/create_issue", "42ms", "e-1", "not shown", "error: auth failed"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("trace output missing %q:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	if err := writeTrace(&buf, trace, true); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `"execution_id": "e-1"`) {
+		t.Errorf("JSON output missing execution id:\n%s", buf.String())
+	}
+}
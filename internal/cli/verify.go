@@ -1,33 +1,73 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/redact"
+	"github.com/khanglvm/tool-hub-mcp/internal/spawner"
 	"github.com/spf13/cobra"
 )
 
 // NewVerifyCmd creates the 'verify' command for verifying configuration.
 func NewVerifyCmd() *cobra.Command {
+	var server string
+	var jsonOutput bool
+
 	cmd := &cobra.Command{
 		Use:   "verify",
 		Short: "Verify configuration and connections",
-		Long: `Verify that the configuration is valid and optionally test
-connections to registered MCP servers.`,
-		Example: `  tool-hub-mcp verify`,
+		Long: `Verify that the configuration is valid and that every registered MCP
+server starts: each server is spawned and sent initialize and tools/list.
+
+Reports tool counts, startup and listing latency, and environment variables
+the server references but that have no value. Exits non-zero if any checked
+server fails, so it can gate CI.`,
+		Example: `  tool-hub-mcp verify
+  tool-hub-mcp verify --server jira
+  tool-hub-mcp verify --json`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runVerify()
+			return runVerify(cmd.OutOrStdout(), server, jsonOutput)
 		},
 	}
 
+	cmd.Flags().StringVar(&server, "server", "", "Only verify this server")
+	cmd.RegisterFlagCompletionFunc("server", completeServerFlag)
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+
 	return cmd
 }
 
-// runVerify validates the configuration.
-func runVerify() error {
+// serverCheck is the health check result of one server.
+type serverCheck struct {
+	Server     string   `json:"server"`
+	OK         bool     `json:"ok"`
+	Tools      int      `json:"tools"`
+	StartupMs  int64    `json:"startupMs"`
+	ListMs     int64    `json:"listMs"`
+	Version    string   `json:"version,omitempty"`
+	MissingEnv []string `json:"missingEnv,omitempty"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// verifyReport is the JSON output of verify.
+type verifyReport struct {
+	ConfigPath    string        `json:"configPath"`
+	Writable      bool          `json:"writable"`
+	WritableError string        `json:"writableError,omitempty"`
+	Servers       []serverCheck `json:"servers"`
+}
+
+// runVerify validates the configuration and health-checks the servers.
+func runVerify(w io.Writer, serverFilter string, jsonOutput bool) error {
 	configPath, err := config.GetDefaultConfigPath()
 	if err != nil {
 		return fmt.Errorf("failed to get config path: %w", err)
@@ -39,67 +79,133 @@ func runVerify() error {
 		return err // Will use our enhanced errors
 	}
 
-	fmt.Println("✓ Config file is readable")
-	fmt.Printf("  Path: %s\n", configPath)
-	fmt.Printf("  Servers: %d\n", len(cfg.Servers))
+	servers := cfg.Servers
+	if serverFilter != "" {
+		key, ok := cfg.ResolveServer(serverFilter)
+		if !ok {
+			return fmt.Errorf("server '%s' not found", serverFilter)
+		}
+		servers = map[string]*config.ServerConfig{key: cfg.Servers[key]}
+	}
+
+	report := verifyReport{ConfigPath: configPath, Writable: true}
 
 	// Check write permissions (warn but don't fail)
-	writeCheckErr := checkConfigWritable(configPath)
-	if writeCheckErr != nil {
-		fmt.Println("⚠️  Config file is not writable")
-		fmt.Printf("  %s\n", writeCheckErr)
-	} else {
-		fmt.Println("✓ Config file is writable")
+	if writeCheckErr := checkConfigWritable(configPath); writeCheckErr != nil {
+		report.Writable = false
+		report.WritableError = writeCheckErr.Error()
 	}
 
-	// Validate each server
-	for name, server := range cfg.Servers {
-		if server.Command == "" {
-			fmt.Printf("✗ %s: missing command\n", name)
-			continue
+	if !jsonOutput {
+		fmt.Fprintln(w, "✓ Config file is readable")
+		fmt.Fprintf(w, "  Path: %s\n", configPath)
+		fmt.Fprintf(w, "  Servers: %d\n", len(cfg.Servers))
+		if report.Writable {
+			fmt.Fprintln(w, "✓ Config file is writable")
+		} else {
+			fmt.Fprintln(w, "⚠️  Config file is not writable")
+			fmt.Fprintf(w, "  %s\n", report.WritableError)
 		}
+	}
 
-		// Check npx packages for existence
-		if server.Command == "npx" && len(server.Args) > 0 {
-			pkg := getNpmPackageName(server.Args)
-			if pkg != "" {
-				if err := validateNpmPackage(pkg); err != nil {
-					fmt.Printf("✗ %s: package %s not found in npm registry\n", name, pkg)
-					continue
-				}
-			}
+	report.Servers = checkServers(cfg, servers)
+
+	failed := 0
+	for _, check := range report.Servers {
+		if !check.OK {
+			failed++
 		}
+	}
 
-		fmt.Printf("✓ %s: %s\n", name, server.Command)
+	if jsonOutput {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		for _, check := range report.Servers {
+			writeServerCheck(w, check)
+		}
 	}
 
+	if failed > 0 {
+		return fmt.Errorf("%d of %d servers failed verification", failed, len(report.Servers))
+	}
 	return nil
 }
 
-// getNpmPackageName extracts npm package name from args (handles -y flags).
-func getNpmPackageName(args []string) string {
-	for _, arg := range args {
-		if arg == "-y" || arg == "--yes" {
-			continue
-		}
-		// Skip flags
-		if len(arg) > 0 && arg[0] == '-' {
-			continue
-		}
-		// Return first non-flag arg as package name
-		return arg
+// checkServers spawns the servers in parallel and runs initialize and
+// tools/list on each. Results are sorted by server name.
+func checkServers(cfg *config.Config, servers map[string]*config.ServerConfig) []serverCheck {
+	pool := spawner.NewPool(len(servers))
+	pool.SetDefaultEnv(cfg.Settings.ChildEnv())
+	defer pool.Close()
+
+	checks := make([]serverCheck, 0, len(servers))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, server := range servers {
+		wg.Add(1)
+		go func(name string, server *config.ServerConfig) {
+			defer wg.Done()
+			check := checkServer(pool, name, server)
+			mu.Lock()
+			checks = append(checks, check)
+			mu.Unlock()
+		}(name, server)
 	}
-	return ""
+	wg.Wait()
+
+	sort.Slice(checks, func(i, j int) bool { return checks[i].Server < checks[j].Server })
+	return checks
 }
 
-// validateNpmPackage checks if npm package exists using npm view.
-func validateNpmPackage(pkg string) error {
-	cmd := exec.Command("npm", "view", pkg, "name")
-	cmd.Stderr = nil
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("package not found: %s", pkg)
+// checkServer health-checks one server.
+func checkServer(pool *spawner.Pool, name string, server *config.ServerConfig) serverCheck {
+	check := serverCheck{Server: name, MissingEnv: server.MissingEnvVars()}
+
+	if err := config.ValidateServer(name, server); err != nil {
+		check.Error = redact.String(err.Error())
+		return check
+	}
+
+	started := time.Now()
+	if err := pool.Prespawn(name, server); err != nil {
+		check.Error = redact.String(err.Error())
+		return check
+	}
+	check.StartupMs = time.Since(started).Milliseconds()
+	if info, ok := pool.ServerInfo(name); ok && info.Name != "" {
+		check.Version = strings.TrimSpace(info.Name + " " + info.Version)
+	}
+
+	listed := time.Now()
+	tools, err := pool.GetTools(name, server)
+	if err != nil {
+		check.Error = redact.String(fmt.Sprintf("tools/list failed: %v", err))
+		return check
+	}
+	check.ListMs = time.Since(listed).Milliseconds()
+	check.Tools = len(tools)
+	check.OK = true
+	return check
+}
+
+// writeServerCheck prints one server's result.
+func writeServerCheck(w io.Writer, check serverCheck) {
+	if check.OK {
+		fmt.Fprintf(w, "✓ %s: %d tools, started in %dms, listed in %dms", check.Server, check.Tools, check.StartupMs, check.ListMs)
+		if check.Version != "" {
+			fmt.Fprintf(w, " (%s)", check.Version)
+		}
+		fmt.Fprintln(w)
+	} else {
+		fmt.Fprintf(w, "✗ %s: %s\n", check.Server, check.Error)
+	}
+	if len(check.MissingEnv) > 0 {
+		fmt.Fprintf(w, "    Missing env: %s\n", strings.Join(check.MissingEnv, ", "))
 	}
-	return nil
 }
 
 // checkConfigWritable tests if we can write to the config file
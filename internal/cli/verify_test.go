@@ -2,8 +2,13 @@ package cli
 
 import (
 	"bytes"
+	"encoding/json"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/mcptest"
 )
 
 func TestNewVerifyCmd(t *testing.T) {
@@ -83,3 +88,43 @@ func TestVerifyCommandExecution(t *testing.T) {
 		})
 	}
 }
+
+func TestRunVerifyHealthChecks(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	cfg := config.NewConfig()
+	cfg.Servers["jira"] = mcptest.Server(t, mcptest.Handlers{
+		"initialize": mcptest.Result(`{"serverInfo":{"name":"jira-mcp","version":"1.2.0"}}`),
+		"tools/list": mcptest.Result(`{"tools":[{"name":"search"},{"name":"create"}]}`),
+	})
+	cfg.Servers["jira"].Env = map[string]string{"JIRA_TOKEN": ""}
+	cfg.Servers["broken"] = &config.ServerConfig{Command: "/nonexistent/server"}
+	if err := config.Save(cfg, filepath.Join(home, ".tool-hub-mcp.json")); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	err := runVerify(&buf, "", false)
+	if err == nil || !strings.Contains(err.Error(), "1 of 2 servers failed") {
+		t.Errorf("expected one failed server, got %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"✓ jira: 2 tools", "(jira-mcp 1.2.0)", "Missing env: JIRA_TOKEN", "✗ broken:"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	if err := runVerify(&buf, "jira", true); err != nil {
+		t.Fatalf("verify --server jira failed: %v", err)
+	}
+	var report verifyReport
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, buf.String())
+	}
+	if len(report.Servers) != 1 || !report.Servers[0].OK || report.Servers[0].Tools != 2 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+}
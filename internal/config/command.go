@@ -0,0 +1,98 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// SplitCommand splits a shell-style command line into words. Words are
+// separated by unquoted whitespace; single quotes keep text literally,
+// double quotes allow \" and \\ escapes, and a backslash outside quotes
+// escapes the next character. No expansion is performed.
+func SplitCommand(line string) ([]string, error) {
+	var (
+		words   []string
+		word    strings.Builder
+		inWord  bool
+		quote   rune
+		escaped bool
+	)
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case quote == '"':
+			switch r {
+			case '"':
+				quote = 0
+			case '\\':
+				escaped = true
+			default:
+				word.WriteRune(r)
+			}
+		case r == '\\':
+			escaped = true
+			inWord = true
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case unicode.IsSpace(r):
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote in command: %s", quote, line)
+	}
+	if escaped {
+		return nil, fmt.Errorf("trailing backslash in command: %s", line)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// NormalizeCommand splits a single-string command such as
+// "npx -y @pkg/mcp", as many client configs write it, into the command
+// and leading args. It reports whether the command was split. Commands
+// run through a shell, and paths that exist with spaces in them
+// ("/Applications/My App/server"), are left alone.
+func (c *ServerConfig) NormalizeCommand() (bool, error) {
+	command := strings.TrimSpace(c.Command)
+	if c.Shell || !strings.ContainsFunc(command, unicode.IsSpace) {
+		return false, nil
+	}
+	if _, err := os.Stat(command); err == nil {
+		return false, nil
+	}
+
+	words, err := SplitCommand(command)
+	if err != nil {
+		return false, err
+	}
+	if len(words) == 0 {
+		return false, nil
+	}
+
+	c.Command = words[0]
+	c.Args = append(words[1:], c.Args...)
+	return true, nil
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"npx -y @pkg/mcp", []string{"npx", "-y", "@pkg/mcp"}},
+		{"  uvx   mcp-server-fetch  ", []string{"uvx", "mcp-server-fetch"}},
+		{`node "/opt/my server/index.js" --name 'a b'`, []string{"node", "/opt/my server/index.js", "--name", "a b"}},
+		{`echo "say \"hi\"" 'it\s' a\ b ''`, []string{"echo", `say "hi"`, `it\s`, "a b", ""}},
+	}
+	for _, tt := range tests {
+		got, err := SplitCommand(tt.line)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitCommand(%q) = %q, %v; want %q", tt.line, got, err, tt.want)
+		}
+	}
+
+	if _, err := SplitCommand(`node "index.js`); err == nil || !strings.Contains(err.Error(), "unterminated") {
+		t.Errorf("error = %v, want unterminated quote", err)
+	}
+}
+
+func TestNormalizeCommand(t *testing.T) {
+	server := &ServerConfig{Command: "npx -y @lvmk/jira-mcp", Args: []string{"--verbose"}}
+	split, err := server.NormalizeCommand()
+	if err != nil || !split {
+		t.Fatalf("NormalizeCommand() = %v, %v", split, err)
+	}
+	if server.Command != "npx" || !reflect.DeepEqual(server.Args, []string{"-y", "@lvmk/jira-mcp", "--verbose"}) {
+		t.Errorf("got %q %q", server.Command, server.Args)
+	}
+
+	shell := &ServerConfig{Command: "cat config.json | node server.js", Shell: true}
+	if split, _ := shell.NormalizeCommand(); split || shell.Command != "cat config.json | node server.js" {
+		t.Errorf("shell commands should not be split, got %q", shell.Command)
+	}
+
+	path := filepath.Join(t.TempDir(), "My Server")
+	os.WriteFile(path, []byte("#!/bin/sh\n"), 0755)
+	existing := &ServerConfig{Command: path}
+	if split, _ := existing.NormalizeCommand(); split || existing.Command != path {
+		t.Errorf("existing paths with spaces should not be split, got %q", existing.Command)
+	}
+}
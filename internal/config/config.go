@@ -1,12 +1,13 @@
 /*
 Package config handles loading, saving, and transforming tool-hub-mcp configuration.
 
-Configuration is stored in ~/.tool-hub-mcp.json and uses a unified camelCase format
+Configuration is stored in ~/.config/tool-hub-mcp/config.json and uses a unified camelCase format
 regardless of the source (Claude Code, OpenCode, etc.).
 
 Schema:
 
 	{
+	  "version": 1,
 	  "servers": {
 	    "serverName": {
 	      "command": "npx",
@@ -18,7 +19,8 @@ Schema:
 	  "settings": {
 	    "cacheToolMetadata": true,
 	    "processPoolSize": 3,
-	    "timeoutSeconds": 30
+	    "timeoutSeconds": 30,
+	    "environment": {"timezone": "UTC", "locale": "en_US.UTF-8"}
 	  }
 	}
 */
@@ -26,17 +28,30 @@ package config
 
 import (
 	"fmt"
-	"os"
-	"path/filepath"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/paths"
 )
 
 // Config represents the root configuration structure.
 type Config struct {
+	// Version is the config format version (ConfigVersion). Files written
+	// before it was introduced have none and are migrated on load.
+	Version int `json:"version,omitempty"`
+
 	// Servers maps server names (camelCase) to their configurations.
 	Servers map[string]*ServerConfig `json:"servers"`
 
 	// Settings contains global configuration options.
 	Settings *Settings `json:"settings,omitempty"`
+
+	// runtimeFeatures holds features enabled for this process only (CLI flags).
+	runtimeFeatures map[string]bool
+
+	// learningOff disables learning for this process only (serve --no-learning).
+	learningOff bool
 }
 
 // ServerConfig represents a single MCP server configuration.
@@ -50,11 +65,414 @@ type ServerConfig struct {
 	// Env contains environment variables for the server.
 	Env map[string]string `json:"env,omitempty"`
 
+	// EnvPassthrough lists the host variables the server inherits, by name
+	// or pattern (e.g., "AWS_*"). Unset inherits DefaultEnvPassthrough;
+	// ["*"] inherits the whole environment. Env always applies on top.
+	EnvPassthrough []string `json:"envPassthrough,omitempty"`
+
+	// Type is the transport declared by the source config: "stdio", or
+	// "http"/"sse" (also "streamable-http", OpenCode's "remote") for
+	// remote servers. Empty means stdio.
+	Type string `json:"type,omitempty"`
+
+	// URL is the endpoint of a remote server, query parameters included.
+	URL string `json:"url,omitempty"`
+
+	// Headers are sent with every request to a remote server (e.g.
+	// Authorization). Values that look like secrets are redacted in output.
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Shell runs Command, followed by Args, as a shell command line
+	// (sh -c, or cmd /c on Windows) for invocations that need pipes,
+	// redirection or variable expansion.
+	Shell bool `json:"shell,omitempty"`
+
+	// Cwd is the working directory the server runs in (inside the
+	// container for containerized servers). Empty inherits the hub's.
+	Cwd string `json:"cwd,omitempty"`
+
+	// ExpandPaths expands ~ and $VAR references in the command, args, env
+	// values and cwd when the server is spawned (default: true).
+	ExpandPaths *bool `json:"expandPaths,omitempty"`
+
 	// Source indicates where this config was imported from (e.g., "claude-code").
 	Source string `json:"source,omitempty"`
 
+	// OriginalName is the server's name in its source config when it differs
+	// from the camelCase key (e.g., "my-server", or a collision-suffixed key).
+	OriginalName string `json:"originalName,omitempty"`
+
+	// Aliases are alternative names accepted by hub_search and hub_execute
+	// (e.g., a previous name kept after `rename`).
+	Aliases []string `json:"aliases,omitempty"`
+
+	// Tags group servers for hub_search's tags filter (e.g., "devops",
+	// "docs"). Matching is case-insensitive.
+	Tags []string `json:"tags,omitempty"`
+
 	// Metadata contains cached tool information.
 	Metadata *ServerMetadata `json:"metadata,omitempty"`
+
+	// RateLimit declares the upstream provider's request quota (optional).
+	RateLimit *RateLimit `json:"rateLimit,omitempty"`
+
+	// Throttle caps the hub_execute call rate to this server, across all
+	// clients (optional).
+	Throttle *Throttle `json:"throttle,omitempty"`
+
+	// Cache opts the server into reusing hub_execute results of its
+	// read-only tools (optional).
+	Cache *CacheSettings `json:"cache,omitempty"`
+
+	// IdempotentTools lists name patterns of tools that are safe to call
+	// again after a transient failure (e.g., "get_*"). Tools the server
+	// annotates with readOnlyHint or idempotentHint are retried without a
+	// pattern.
+	IdempotentTools []string `json:"idempotentTools,omitempty"`
+
+	// PathArguments maps tool names to arguments that accept a file path.
+	// Oversized string values for these arguments are offloaded to a temp
+	// file and replaced with its path. The "*" key applies to every tool.
+	PathArguments map[string][]string `json:"pathArguments,omitempty"`
+
+	// Preload spawns and initializes the server when `serve` starts instead
+	// of on first use, hiding cold-start latency (e.g., npx downloads).
+	Preload bool `json:"preload,omitempty"`
+
+	// Disabled keeps the server registered but never spawned: it is not
+	// indexed and hub_execute refuses to call it.
+	Disabled bool `json:"disabled,omitempty"`
+
+	// Runtime runs the server in a container instead of on the host:
+	// "docker" or "podman". Command and Args then run inside
+	// Container.Image; an empty Command uses the image's entrypoint.
+	Runtime string `json:"runtime,omitempty"`
+
+	// Container configures the image, volumes and network of a server
+	// with a container runtime.
+	Container *ContainerSettings `json:"container,omitempty"`
+
+	// Limits caps the memory and CPU time the server's process may use.
+	Limits *ResourceLimits `json:"limits,omitempty"`
+
+	// Sandbox restricts what the server's process may access, for
+	// untrusted community servers.
+	Sandbox *SandboxSettings `json:"sandbox,omitempty"`
+}
+
+// SandboxSettings restricts a child process. On Linux it runs in its own
+// network namespace and under Landlock, on macOS under sandbox-exec, and
+// in containers through runtime flags; other platforms refuse to spawn a
+// sandboxed server.
+type SandboxSettings struct {
+	// Network allows network access. false cuts the process off from the
+	// network; unset or true leaves it as is.
+	Network *bool `json:"network,omitempty"`
+
+	// FSReadOnly makes the file system read-only for the process, except
+	// for the Writable paths.
+	FSReadOnly bool `json:"fsReadOnly,omitempty"`
+
+	// Writable lists paths that stay writable under FSReadOnly (e.g. a
+	// cache or output directory).
+	Writable []string `json:"writable,omitempty"`
+}
+
+// NoNetwork reports whether the sandbox cuts off network access.
+func (s *SandboxSettings) NoNetwork() bool {
+	return s != nil && s.Network != nil && !*s.Network
+}
+
+// ReadOnly reports whether the sandbox makes the file system read-only.
+func (s *SandboxSettings) ReadOnly() bool {
+	return s != nil && s.FSReadOnly
+}
+
+// Enabled reports whether the sandbox restricts anything.
+func (s *SandboxSettings) Enabled() bool {
+	return s.NoNetwork() || s.ReadOnly()
+}
+
+// ResourceLimits bounds a child process. Zero fields are unlimited. On
+// Unix they are applied as rlimits (RLIMIT_DATA, RLIMIT_CPU), on Windows
+// through a job object, and for containers as runtime flags.
+type ResourceLimits struct {
+	// MemoryMB caps the process's data memory in megabytes.
+	MemoryMB int `json:"memoryMB,omitempty"`
+
+	// CPUSeconds caps the CPU time the process may consume over its
+	// lifetime; it is terminated once the limit is reached.
+	CPUSeconds int `json:"cpuSeconds,omitempty"`
+}
+
+// Container runtimes accepted in a server's "runtime".
+const (
+	RuntimeDocker = "docker"
+	RuntimePodman = "podman"
+)
+
+// Image pull policies accepted in container.pull.
+const (
+	PullMissing = "missing"
+	PullAlways  = "always"
+	PullNever   = "never"
+)
+
+// ContainerSettings describes how a containerized server is run.
+type ContainerSettings struct {
+	// Image is the container image, e.g. "mcp/github:latest".
+	Image string `json:"image"`
+
+	// Volumes are bind mounts in "host:container[:ro]" form.
+	Volumes []string `json:"volumes,omitempty"`
+
+	// Network is passed as --network (e.g., "none", "bridge", "host").
+	// Empty uses the runtime's default network.
+	Network string `json:"network,omitempty"`
+
+	// Pull is the image pull policy: "missing" (default) pulls the image
+	// before the first spawn if it is not present, "always" pulls before
+	// every spawn and "never" requires it to be present.
+	Pull string `json:"pull,omitempty"`
+}
+
+// IsRemote reports whether the server is reached over HTTP rather than
+// spawned as a child process.
+func (c *ServerConfig) IsRemote() bool {
+	if c.URL != "" {
+		return true
+	}
+	switch strings.ToLower(c.Type) {
+	case "http", "sse", "streamable-http", "streamablehttp", "remote":
+		return true
+	}
+	return false
+}
+
+// IsContainer reports whether the server runs in a container runtime.
+func (c *ServerConfig) IsContainer() bool {
+	return c.Runtime != ""
+}
+
+// AcceptsPath reports whether the config marks a tool argument as a file path.
+func (c *ServerConfig) AcceptsPath(toolName, argName string) bool {
+	for _, key := range []string{toolName, "*"} {
+		for _, name := range c.PathArguments[key] {
+			if name == argName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RateLimit describes a provider quota for a SaaS-backed server.
+// Example: GitHub's REST API allows {"requests": 5000, "period": "1h"}.
+type RateLimit struct {
+	// Requests is the number of tool calls allowed per period.
+	Requests int `json:"requests"`
+
+	// Period is the quota window as a Go duration string (e.g., "1h", "1m").
+	Period string `json:"period"`
+
+	// MaxWaitSeconds is how long a call may be delayed waiting for quota
+	// before the hub gives up and reports the limit instead (default: 5).
+	MaxWaitSeconds *int `json:"maxWaitSeconds,omitempty"`
+
+	// NearExhaustion is the share of the quota (0-1] after which calls are
+	// paced period/requests apart instead of let through in bursts
+	// (default: 0.9; 1 never paces).
+	NearExhaustion *float64 `json:"nearExhaustion,omitempty"`
+}
+
+// DefaultThrottleBurst is the burst allowed when a throttle sets none.
+const DefaultThrottleBurst = 5
+
+// Throttle is a token bucket limiting hub_execute calls: CallsPerMinute
+// on average, with bursts of up to Burst calls. Calls over the limit are
+// rejected with a rate_limited result carrying retryAfterSeconds.
+// Example: {"callsPerMinute": 30, "burst": 5}.
+type Throttle struct {
+	CallsPerMinute float64 `json:"callsPerMinute"`
+
+	// Burst is how many calls may run back to back (default: 5).
+	Burst int `json:"burst,omitempty"`
+}
+
+// BurstSize returns the configured burst, or DefaultThrottleBurst.
+func (t *Throttle) BurstSize() int {
+	if t.Burst > 0 {
+		return t.Burst
+	}
+	return DefaultThrottleBurst
+}
+
+// Default retry policy for transient child failures.
+const (
+	DefaultRetryAttempts   = 3
+	DefaultRetryBackoff    = 200 * time.Millisecond
+	DefaultRetryMaxBackoff = 5 * time.Second
+)
+
+// RetrySettings controls how requests that fail for a transient reason
+// (the child exited, closed its pipes or did not answer in time) are
+// retried. Spawning a server and tools/list are always retried; tool calls
+// only when idempotent. Example: {"maxAttempts": 3, "backoff": "200ms"}.
+type RetrySettings struct {
+	// MaxAttempts is the total number of tries per request (default: 3).
+	// 1 disables retries.
+	MaxAttempts int `json:"maxAttempts,omitempty"`
+
+	// Backoff is the delay before the first retry, as a Go duration
+	// string, doubled for each further one (default: 200ms).
+	Backoff string `json:"backoff,omitempty"`
+
+	// MaxBackoff caps the delay between retries (default: 5s).
+	MaxBackoff string `json:"maxBackoff,omitempty"`
+}
+
+// Attempts returns the configured number of tries, or DefaultRetryAttempts.
+func (r *RetrySettings) Attempts() int {
+	if r == nil || r.MaxAttempts == 0 {
+		return DefaultRetryAttempts
+	}
+	return r.MaxAttempts
+}
+
+// Delays returns the parsed initial and maximum backoff.
+func (r *RetrySettings) Delays() (backoff, maxBackoff time.Duration, err error) {
+	backoff, maxBackoff = DefaultRetryBackoff, DefaultRetryMaxBackoff
+	if r == nil {
+		return backoff, maxBackoff, nil
+	}
+	if r.Backoff != "" {
+		if backoff, err = time.ParseDuration(r.Backoff); err != nil || backoff < 0 {
+			return 0, 0, fmt.Errorf("invalid retry backoff '%s': use a duration like \"200ms\"", r.Backoff)
+		}
+	}
+	if r.MaxBackoff != "" {
+		if maxBackoff, err = time.ParseDuration(r.MaxBackoff); err != nil || maxBackoff < 0 {
+			return 0, 0, fmt.Errorf("invalid retry maxBackoff '%s': use a duration like \"5s\"", r.MaxBackoff)
+		}
+	}
+	return backoff, maxBackoff, nil
+}
+
+// Default circuit breaker for repeatedly failing servers.
+const (
+	DefaultCircuitThreshold = 5
+	DefaultCircuitCooldown  = time.Minute
+)
+
+// CircuitBreakerSettings controls when a server that keeps failing to
+// spawn or answer is taken out of service. After Threshold consecutive
+// failures its circuit opens for Cooldown: hub_execute rejects calls to it
+// immediately and hub_search marks its tools as degraded. The first call
+// after the cooldown tries the server again.
+// Example: {"threshold": 5, "cooldown": "1m"}.
+type CircuitBreakerSettings struct {
+	// Disabled never opens circuits.
+	Disabled bool `json:"disabled,omitempty"`
+
+	// Threshold is the number of consecutive failures that opens the
+	// circuit (default: 5).
+	Threshold int `json:"threshold,omitempty"`
+
+	// Cooldown is how long the circuit stays open, as a Go duration
+	// string (default: 1m).
+	Cooldown string `json:"cooldown,omitempty"`
+}
+
+// FailureThreshold returns the configured threshold, or 0 when disabled.
+func (c *CircuitBreakerSettings) FailureThreshold() int {
+	switch {
+	case c == nil:
+		return DefaultCircuitThreshold
+	case c.Disabled:
+		return 0
+	case c.Threshold == 0:
+		return DefaultCircuitThreshold
+	}
+	return c.Threshold
+}
+
+// CooldownDuration returns the parsed cooldown.
+func (c *CircuitBreakerSettings) CooldownDuration() (time.Duration, error) {
+	if c == nil || c.Cooldown == "" {
+		return DefaultCircuitCooldown, nil
+	}
+	cooldown, err := time.ParseDuration(c.Cooldown)
+	if err != nil || cooldown <= 0 {
+		return 0, fmt.Errorf("invalid circuit breaker cooldown '%s': use a positive duration like \"1m\"", c.Cooldown)
+	}
+	return cooldown, nil
+}
+
+// CacheSettings selects the tools whose results may be reused and for how
+// long. Example: {"ttl": "10m", "tools": ["get_*", "search_*"]}.
+type CacheSettings struct {
+	// TTL is how long a result is reused, as a Go duration string
+	// (default: 5m).
+	TTL string `json:"ttl,omitempty"`
+
+	// Tools lists name patterns of read-only tools (e.g., "get_*"). Tools
+	// the server annotates with readOnlyHint are cached without a pattern.
+	Tools []string `json:"tools,omitempty"`
+}
+
+// DefaultCacheTTL is how long cached results are reused by default.
+const DefaultCacheTTL = 5 * time.Minute
+
+// Duration returns the parsed cache TTL.
+func (c *CacheSettings) Duration() (time.Duration, error) {
+	if c.TTL == "" {
+		return DefaultCacheTTL, nil
+	}
+	ttl, err := time.ParseDuration(c.TTL)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cache ttl '%s': %w", c.TTL, err)
+	}
+	if ttl <= 0 {
+		return 0, fmt.Errorf("cache ttl must be positive")
+	}
+	return ttl, nil
+}
+
+// DefaultRateLimitMaxWait is the default delay allowed before rejecting a call.
+const DefaultRateLimitMaxWait = 5 * time.Second
+
+// DefaultNearExhaustion is the default share of a quota after which calls
+// are paced.
+const DefaultNearExhaustion = 0.9
+
+// Window returns the parsed quota period.
+func (r *RateLimit) Window() (time.Duration, error) {
+	period, err := time.ParseDuration(r.Period)
+	if err != nil {
+		return 0, fmt.Errorf("invalid rate limit period '%s': %w", r.Period, err)
+	}
+	if period <= 0 {
+		return 0, fmt.Errorf("rate limit period must be positive")
+	}
+	return period, nil
+}
+
+// MaxWait returns how long a call may be delayed waiting for quota.
+func (r *RateLimit) MaxWait() time.Duration {
+	if r.MaxWaitSeconds == nil {
+		return DefaultRateLimitMaxWait
+	}
+	return time.Duration(*r.MaxWaitSeconds) * time.Second
+}
+
+// PaceFrom returns how many requests in a period are let through before
+// calls are paced.
+func (r *RateLimit) PaceFrom() int {
+	share := DefaultNearExhaustion
+	if r.NearExhaustion != nil {
+		share = *r.NearExhaustion
+	}
+	return int(math.Ceil(share * float64(r.Requests)))
 }
 
 // ServerMetadata contains cached information about a server's tools.
@@ -67,6 +485,16 @@ type ServerMetadata struct {
 
 	// LastUpdated is when the metadata was last refreshed.
 	LastUpdated string `json:"lastUpdated,omitempty"`
+
+	// ServerName is the serverInfo name the child reported on initialize.
+	ServerName string `json:"serverName,omitempty"`
+
+	// ServerVersion is the serverInfo version the child reported on initialize.
+	ServerVersion string `json:"serverVersion,omitempty"`
+
+	// ToolsHash fingerprints the full tool list; the cache is refreshed
+	// only when it (or the server version) changes.
+	ToolsHash string `json:"toolsHash,omitempty"`
 }
 
 // Settings contains global configuration options.
@@ -74,32 +502,590 @@ type Settings struct {
 	// CacheToolMetadata enables caching of tool definitions.
 	CacheToolMetadata bool `json:"cacheToolMetadata,omitempty"`
 
-	// ProcessPoolSize is the max number of concurrent MCP server processes.
+	// ProcessPoolSize is how many MCP server processes are kept running.
+	// Spawning another stops the least recently used idle one; when all
+	// are busy the pool grows past it (MaxChildProcesses is the hard cap).
+	// Preload servers and processes only spawned to index tools do not
+	// count. 0 or negative never stops idle servers. New configs start
+	// with DefaultProcessPoolSize.
 	ProcessPoolSize int `json:"processPoolSize,omitempty"`
 
 	// TimeoutSeconds is the default timeout for MCP operations.
 	TimeoutSeconds int `json:"timeoutSeconds,omitempty"`
+
+	// Features enables experimental subsystems by name (e.g., "semanticSearch").
+	Features map[string]bool `json:"features,omitempty"`
+
+	// Environment standardizes TZ, LANG, and proxy variables for child processes.
+	Environment *ChildEnvironment `json:"environment,omitempty"`
+
+	// Network configures outbound HTTP made by tool-hub-mcp itself.
+	Network *NetworkSettings `json:"network,omitempty"`
+
+	// RegistryURL is the MCP server registry `add --from-registry` queries
+	// (default: the official registry).
+	RegistryURL string `json:"registryUrl,omitempty"`
+
+	// Telemetry exports spans and metrics to an OpenTelemetry collector.
+	Telemetry *TelemetrySettings `json:"telemetry,omitempty"`
+
+	// MaxResponseBytes caps the hub_execute output returned inline.
+	// Larger results are truncated and kept for paging via hub_fetch_result.
+	// 0 uses DefaultMaxResponseBytes; a negative value disables the cap.
+	MaxResponseBytes int `json:"maxResponseBytes,omitempty"`
+
+	// MaxArgumentBytes caps a single hub_execute string argument. Larger
+	// values are offloaded to a temp file for path-accepting arguments and
+	// rejected otherwise. 0 uses DefaultMaxArgumentBytes; negative disables.
+	MaxArgumentBytes int `json:"maxArgumentBytes,omitempty"`
+
+	// Audit configures the hub_execute audit log.
+	Audit *AuditSettings `json:"audit,omitempty"`
+
+	// MaxConcurrentCallsPerServer bounds in-flight hub_execute calls to one
+	// child. Further calls are queued in arrival order. 0 means unlimited.
+	MaxConcurrentCallsPerServer int `json:"maxConcurrentCallsPerServer,omitempty"`
+
+	// MaxChildProcesses caps the number of child servers running at once.
+	// Spawning another one fails until a child exits. 0 means unlimited.
+	MaxChildProcesses int `json:"maxChildProcesses,omitempty"`
+
+	// Retry configures retries of transient child failures.
+	Retry *RetrySettings `json:"retry,omitempty"`
+
+	// CircuitBreaker stops calling servers that keep failing for a while.
+	CircuitBreaker *CircuitBreakerSettings `json:"circuitBreaker,omitempty"`
+
+	// MaxMessageBytes caps a single JSON-RPC message read from the client.
+	// 0 uses DefaultMaxMessageBytes; a negative value disables the cap.
+	MaxMessageBytes int `json:"maxMessageBytes,omitempty"`
+
+	// MaxDescriptionChars caps tool descriptions stored in the search index.
+	// 0 uses DefaultMaxDescriptionChars; a negative value disables the cap.
+	MaxDescriptionChars int `json:"maxDescriptionChars,omitempty"`
+
+	// Search tunes hub_search query handling.
+	Search *SearchSettings `json:"search,omitempty"`
+
+	// Descriptions controls how much the meta-tool descriptions in
+	// tools/list say.
+	Descriptions *DescriptionSettings `json:"descriptions,omitempty"`
+
+	// MetaTools selects the meta-tools tools/list exposes, by name (e.g.
+	// adding "hub_list" for weaker models, or only hub_search and
+	// hub_execute for the smallest tool list). Unset uses DefaultMetaTools.
+	MetaTools []string `json:"metaTools,omitempty"`
+
+	// AutoUpdate installs a downloaded, verified release on the next serve
+	// start and restarts into it. Defaults to true; set false to opt out.
+	AutoUpdate *bool `json:"autoUpdate,omitempty"`
+
+	// Update configures the GitHub self-updater.
+	Update *UpdateSettings `json:"update,omitempty"`
+
+	// Learning configures usage tracking and learned tool ranking.
+	Learning *LearningSettings `json:"learning,omitempty"`
+
+	// Secrets configures encryption of server env values at rest.
+	Secrets *SecretSettings `json:"secrets,omitempty"`
+
+	// Confirmation configures which tools hub_execute only runs with
+	// confirm: true.
+	Confirmation *ConfirmationSettings `json:"confirmation,omitempty"`
+
+	// ClientThrottle caps each client's hub_execute call rate across all
+	// servers, so one runaway agent loop cannot starve the others.
+	ClientThrottle *Throttle `json:"clientThrottle,omitempty"`
+
+	// Hooks are external commands run before or after meta-tool calls,
+	// in order, to enforce policy or transform arguments and results.
+	Hooks []HookConfig `json:"hooks,omitempty"`
+
+	// Webhooks receive JSON events for executions and server changes.
+	Webhooks []WebhookConfig `json:"webhooks,omitempty"`
+}
+
+// WebhookConfig is an HTTP endpoint that hub events are POSTed to.
+type WebhookConfig struct {
+	// URL receives the events (e.g., a Slack incoming webhook).
+	URL string `json:"url"`
+
+	// Events are globs of the event types to send ("execution.failed",
+	// "server.*"). Empty sends every event.
+	Events []string `json:"events,omitempty"`
+
+	// Headers are sent with every request (e.g., an Authorization token).
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// WebhookTargets returns the configured webhooks, or nil.
+func (s *Settings) WebhookTargets() []WebhookConfig {
+	if s == nil {
+		return nil
+	}
+	return s.Webhooks
+}
+
+// Hook stages.
+const (
+	HookStagePre  = "pre"
+	HookStagePost = "post"
+)
+
+// DefaultHookTimeout bounds a hook command that sets no timeout.
+const DefaultHookTimeout = 5 * time.Second
+
+// HookConfig is an external command in the tools/call middleware chain.
+// It receives the call as JSON on stdin and may answer on stdout.
+type HookConfig struct {
+	// Name identifies the hook in logs and veto messages (default: command).
+	Name string `json:"name,omitempty"`
+
+	// Command and Args start the hook, without a shell.
+	Command string   `json:"command"`
+	Args    []string `json:"args,omitempty"`
+
+	// Stage is "pre" (before the call; may veto or rewrite arguments) or
+	// "post" (after the call; may rewrite the result).
+	Stage string `json:"stage"`
+
+	// Tools are case-insensitive globs of the meta-tools the hook runs
+	// for ("hub_execute"), or of the child tool hub_execute calls ("delete_*",
+	// "github/*"). Empty runs the hook for every call.
+	Tools []string `json:"tools,omitempty"`
+
+	// Timeout bounds one run ("2s"); DefaultHookTimeout when empty.
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// Duration returns the hook timeout, or DefaultHookTimeout.
+func (h HookConfig) Duration() time.Duration {
+	if d, err := time.ParseDuration(h.Timeout); err == nil && d > 0 {
+		return d
+	}
+	return DefaultHookTimeout
+}
+
+// Label returns the hook's name, or its command when unnamed.
+func (h HookConfig) Label() string {
+	if h.Name != "" {
+		return h.Name
+	}
+	return h.Command
+}
+
+// ClientThrottleConfig returns the per-client throttle, or nil if unset.
+func (s *Settings) ClientThrottleConfig() *Throttle {
+	if s == nil {
+		return nil
+	}
+	return s.ClientThrottle
+}
+
+// HookCommands returns the configured hooks, or nil.
+func (s *Settings) HookCommands() []HookConfig {
+	if s == nil {
+		return nil
+	}
+	return s.Hooks
+}
+
+// DefaultDestructivePatterns are the tool name globs that need explicit
+// confirmation unless settings.confirmation.patterns overrides them.
+var DefaultDestructivePatterns = []string{
+	"delete_*", "*_delete", "drop_*", "remove_*", "*_remove",
+	"destroy_*", "purge_*", "truncate_*",
+}
+
+// ConfirmationSettings controls the destructive tool policy.
+type ConfirmationSettings struct {
+	// Disabled runs every tool without confirmation.
+	Disabled bool `json:"disabled,omitempty"`
+
+	// Patterns are case-insensitive tool name globs ("delete_*") or
+	// "server/tool" globs ("github/*_repo") that replace the defaults.
+	Patterns []string `json:"patterns,omitempty"`
+}
+
+// SecretSettings controls how server env values are stored on disk.
+type SecretSettings struct {
+	// Encrypt stores env values encrypted with AES-256-GCM. Loading
+	// decrypts them transparently; saving with Encrypt off writes them
+	// back in plaintext.
+	Encrypt bool `json:"encrypt,omitempty"`
+
+	// KeySource is where the key is kept: "file" (default) or "keychain"
+	// (macOS Keychain, or the Secret Service via secret-tool on Linux).
+	KeySource string `json:"keySource,omitempty"`
+
+	// KeyFile overrides the key file path, secret.key next to the config
+	// file by default.
+	KeyFile string `json:"keyFile,omitempty"`
+}
+
+// LearningSettings controls the learning subsystem.
+type LearningSettings struct {
+	// Enabled turns usage tracking, search history and learned ranking on
+	// (the default) or off. When off, history.db is not created or opened.
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Algorithm picks the bandit that balances learned rankings against
+	// exploration: "epsilonGreedy" (default) or "thompson".
+	Algorithm string `json:"algorithm,omitempty"`
+}
+
+// UpdateSettings controls how serve checks for new releases.
+type UpdateSettings struct {
+	// Disabled turns off update checks and downloads entirely, e.g. when
+	// another tool manages upgrades.
+	Disabled bool `json:"disabled,omitempty"`
+
+	// Channel selects the releases to follow: "stable" (default) or
+	// "beta", which includes prereleases.
+	Channel string `json:"channel,omitempty"`
+}
+
+// SearchSettings tunes how hub_search interprets queries.
+type SearchSettings struct {
+	// Synonyms adds query expansions on top of the built-in table, e.g.
+	// {"deploy": ["release", "ship"]}. Keys and values may be phrases.
+	Synonyms map[string][]string `json:"synonyms,omitempty"`
+
+	// Boosts weights matches in each field; unset fields keep the
+	// defaults (name 3, description 1, server 1).
+	Boosts *FieldBoosts `json:"boosts,omitempty"`
+
+	// Language selects how tool text is tokenized: "auto" (default) also
+	// indexes Chinese, Japanese and Korean text in bigrams, "cjk" applies
+	// that to all text, "unicode" keeps every word (no English stop words)
+	// and "standard" is English-oriented.
+	Language string `json:"language,omitempty"`
+}
+
+// DescriptionSettings controls the size of the meta-tool descriptions sent
+// with every tools/list. Example: {"verbosity": "compact", "maxServers": 10}.
+type DescriptionSettings struct {
+	// Verbosity is "full" (default), "compact" (without usage examples
+	// and when-to-use lists) or "minimal" (a one-line summary per tool).
+	Verbosity string `json:"verbosity,omitempty"`
+
+	// MaxServers caps the server names listed in descriptions; the rest
+	// are counted. 0 uses DefaultDescriptionServers; negative lists all.
+	MaxServers int `json:"maxServers,omitempty"`
+}
+
+// DescriptionVerbosities are the accepted values of
+// DescriptionSettings.Verbosity.
+var DescriptionVerbosities = []string{"full", "compact", "minimal"}
+
+// KnownMetaTools are the meta-tools the hub implements.
+var KnownMetaTools = []string{
+	"hub_search", "hub_list", "hub_discover", "hub_execute", "hub_schema",
+	"hub_help", "hub_status", "hub_fetch_result", "hub_manage",
+}
+
+// DefaultMetaTools are the meta-tools exposed when settings.metaTools is
+// unset.
+var DefaultMetaTools = []string{
+	"hub_search", "hub_discover", "hub_execute", "hub_schema",
+	"hub_status", "hub_fetch_result", "hub_manage",
+}
+
+// DefaultProcessPoolSize is the pool size new configs start with.
+const DefaultProcessPoolSize = 3
+
+// DefaultDescriptionServers is how many server names descriptions list.
+const DefaultDescriptionServers = 15
+
+// SearchLanguages are the accepted values of SearchSettings.Language.
+var SearchLanguages = []string{"auto", "standard", "cjk", "unicode"}
+
+// FieldBoosts are relative ranking weights for the indexed tool fields.
+type FieldBoosts struct {
+	Name        float64 `json:"name,omitempty"`
+	Description float64 `json:"description,omitempty"`
+	Server      float64 `json:"server,omitempty"`
+}
+
+// AuditSettings controls what the audit log records. Every hub_execute call
+// is logged (server, tool, argument hash, duration, outcome) unless disabled.
+type AuditSettings struct {
+	// Disabled turns the audit log off.
+	Disabled bool `json:"disabled,omitempty"`
+
+	// RecordArguments also stores the call arguments, with values of
+	// secret-looking keys (tokens, passwords, API keys) redacted.
+	RecordArguments bool `json:"recordArguments,omitempty"`
+
+	// RecordQueries stores hub_search query text for `trace`; otherwise
+	// only its hash is kept.
+	RecordQueries bool `json:"recordQueries,omitempty"`
+}
+
+// DefaultMaxArgumentBytes is the largest string argument sent to a child inline.
+const DefaultMaxArgumentBytes = 256 * 1024
+
+// DefaultMaxMessageBytes is the largest client message the server accepts.
+const DefaultMaxMessageBytes = 16 * 1024 * 1024
+
+// MessageLimit returns the effective client message size limit in bytes,
+// or 0 when messages are not capped.
+func (s *Settings) MessageLimit() int {
+	if s == nil || s.MaxMessageBytes == 0 {
+		return DefaultMaxMessageBytes
+	}
+	if s.MaxMessageBytes < 0 {
+		return 0
+	}
+	return s.MaxMessageBytes
+}
+
+// DefaultMaxDescriptionChars is the longest tool description indexed as is.
+const DefaultMaxDescriptionChars = 1024
+
+// DescriptionLimit returns the effective indexed description length in
+// characters, or 0 when descriptions are not capped.
+func (s *Settings) DescriptionLimit() int {
+	if s == nil || s.MaxDescriptionChars == 0 {
+		return DefaultMaxDescriptionChars
+	}
+	if s.MaxDescriptionChars < 0 {
+		return 0
+	}
+	return s.MaxDescriptionChars
+}
+
+// DefaultMaxResponseBytes is the inline hub_execute output budget (~16k tokens).
+const DefaultMaxResponseBytes = 64 * 1024
+
+// ResponseBudget returns the effective inline response size in bytes,
+// or 0 when responses are not capped.
+func (s *Settings) ResponseBudget() int {
+	if s == nil || s.MaxResponseBytes == 0 {
+		return DefaultMaxResponseBytes
+	}
+	if s.MaxResponseBytes < 0 {
+		return 0
+	}
+	return s.MaxResponseBytes
+}
+
+// AuditEnabled reports whether hub_execute calls are written to the audit log.
+func (s *Settings) AuditEnabled() bool {
+	return s == nil || s.Audit == nil || !s.Audit.Disabled
+}
+
+// AuditArguments reports whether the audit log stores redacted arguments.
+func (s *Settings) AuditArguments() bool {
+	return s != nil && s.Audit != nil && !s.Audit.Disabled && s.Audit.RecordArguments
+}
+
+// AuditQueries reports whether hub_search query text is stored for traces.
+func (s *Settings) AuditQueries() bool {
+	return s != nil && s.Audit != nil && !s.Audit.Disabled && s.Audit.RecordQueries
+}
+
+// ArgumentBudget returns the effective per-argument size limit in bytes,
+// or 0 when arguments are not capped.
+func (s *Settings) ArgumentBudget() int {
+	if s == nil || s.MaxArgumentBytes == 0 {
+		return DefaultMaxArgumentBytes
+	}
+	if s.MaxArgumentBytes < 0 {
+		return 0
+	}
+	return s.MaxArgumentBytes
+}
+
+// CallConcurrency returns the per-server call limit, or 0 when unlimited.
+func (s *Settings) CallConcurrency() int {
+	if s == nil || s.MaxConcurrentCallsPerServer < 0 {
+		return 0
+	}
+	return s.MaxConcurrentCallsPerServer
+}
+
+// ChildProcessLimit returns the max number of running children, or 0
+// when unlimited.
+func (s *Settings) ChildProcessLimit() int {
+	if s == nil || s.MaxChildProcesses < 0 {
+		return 0
+	}
+	return s.MaxChildProcesses
+}
+
+// ProcessPoolLimit returns how many server processes are kept running,
+// or 0 when idle ones are never stopped.
+func (s *Settings) ProcessPoolLimit() int {
+	if s == nil || s.ProcessPoolSize < 0 {
+		return 0
+	}
+	return s.ProcessPoolSize
+}
+
+// RetryConfig returns the retry settings, or nil for the defaults.
+func (s *Settings) RetryConfig() *RetrySettings {
+	if s == nil {
+		return nil
+	}
+	return s.Retry
+}
+
+// CircuitBreakerConfig returns the circuit breaker settings, or nil for
+// the defaults.
+func (s *Settings) CircuitBreakerConfig() *CircuitBreakerSettings {
+	if s == nil {
+		return nil
+	}
+	return s.CircuitBreaker
+}
+
+// AutoUpdateEnabled reports whether serve applies pending updates on start.
+func (s *Settings) AutoUpdateEnabled() bool {
+	return s.UpdatesEnabled() && (s == nil || s.AutoUpdate == nil || *s.AutoUpdate)
+}
+
+// UpdatesEnabled reports whether serve checks GitHub for new releases.
+func (s *Settings) UpdatesEnabled() bool {
+	return s == nil || s.Update == nil || !s.Update.Disabled
+}
+
+// Registry returns the configured registry URL, or "" for the default.
+func (s *Settings) Registry() string {
+	if s == nil {
+		return ""
+	}
+	return s.RegistryURL
+}
+
+// UpdateChannel returns the configured release channel, "stable" by default.
+func (s *Settings) UpdateChannel() string {
+	if s == nil || s.Update == nil || s.Update.Channel == "" {
+		return "stable"
+	}
+	return strings.ToLower(s.Update.Channel)
+}
+
+// SearchSynonyms returns the user-defined query synonyms, or nil.
+func (s *Settings) SearchSynonyms() map[string][]string {
+	if s == nil || s.Search == nil {
+		return nil
+	}
+	return s.Search.Synonyms
+}
+
+// SearchLanguage returns the configured search language, or "auto".
+func (s *Settings) SearchLanguage() string {
+	if s == nil || s.Search == nil || s.Search.Language == "" {
+		return "auto"
+	}
+	return s.Search.Language
+}
+
+// DescriptionVerbosity returns the configured meta-tool description
+// verbosity, or "full".
+func (s *Settings) DescriptionVerbosity() string {
+	if s == nil || s.Descriptions == nil || s.Descriptions.Verbosity == "" {
+		return "full"
+	}
+	return s.Descriptions.Verbosity
+}
+
+// DescriptionServerLimit returns how many server names meta-tool
+// descriptions list, or 0 when all are listed.
+func (s *Settings) DescriptionServerLimit() int {
+	if s == nil || s.Descriptions == nil || s.Descriptions.MaxServers == 0 {
+		return DefaultDescriptionServers
+	}
+	if s.Descriptions.MaxServers < 0 {
+		return 0
+	}
+	return s.Descriptions.MaxServers
+}
+
+// ExposedMetaTools returns the meta-tools to expose, DefaultMetaTools
+// when none are configured.
+func (s *Settings) ExposedMetaTools() []string {
+	if s == nil || len(s.MetaTools) == 0 {
+		return DefaultMetaTools
+	}
+	return s.MetaTools
+}
+
+// SearchBoosts returns the configured field boosts (zero when unset).
+func (s *Settings) SearchBoosts() FieldBoosts {
+	if s == nil || s.Search == nil || s.Search.Boosts == nil {
+		return FieldBoosts{}
+	}
+	return *s.Search.Boosts
+}
+
+// DestructivePatterns returns the tool globs that require confirmation,
+// or nil when the policy is disabled.
+func (s *Settings) DestructivePatterns() []string {
+	if s == nil || s.Confirmation == nil {
+		return DefaultDestructivePatterns
+	}
+	if s.Confirmation.Disabled {
+		return nil
+	}
+	if s.Confirmation.Patterns == nil {
+		return DefaultDestructivePatterns
+	}
+	return s.Confirmation.Patterns
+}
+
+// NetworkSettings configures the proxy and trusted CAs for outbound HTTP
+// (update checks, registry discovery, remote connectors, embedding APIs).
+type NetworkSettings struct {
+	// Proxy is an explicit proxy URL. When empty, HTTPS_PROXY/NO_PROXY apply.
+	Proxy string `json:"proxy,omitempty"`
+
+	// CABundle is a PEM file of extra trusted roots (corporate MITM proxies).
+	CABundle string `json:"caBundle,omitempty"`
+}
+
+// TelemetrySettings configures OTLP/HTTP export of spans and metrics.
+// Unset fields fall back to the standard OTEL_EXPORTER_OTLP_ENDPOINT,
+// OTEL_EXPORTER_OTLP_HEADERS and OTEL_SERVICE_NAME variables.
+type TelemetrySettings struct {
+	// Endpoint is the collector's base URL (e.g., "http://localhost:4318");
+	// /v1/traces and /v1/metrics are appended.
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Headers are sent with every export (e.g., an API key).
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// ServiceName is the service.name resource attribute (default: tool-hub-mcp).
+	ServiceName string `json:"serviceName,omitempty"`
+}
+
+// TelemetryConfig returns the telemetry settings, or nil if unset.
+func (s *Settings) TelemetryConfig() *TelemetrySettings {
+	if s == nil {
+		return nil
+	}
+	return s.Telemetry
 }
 
 // NewConfig creates a new empty configuration with initialized maps.
 func NewConfig() *Config {
 	return &Config{
 		Servers: make(map[string]*ServerConfig),
+		Version: ConfigVersion,
 		Settings: &Settings{
 			CacheToolMetadata: true,
-			ProcessPoolSize:   3,
+			ProcessPoolSize:   DefaultProcessPoolSize,
 			TimeoutSeconds:    30,
 		},
 	}
 }
 
-// GetDefaultConfigPath returns the path to ~/.tool-hub-mcp.json
+// GetDefaultConfigPath returns the path to the config file,
+// ~/.config/tool-hub-mcp/config.json unless TOOL_HUB_HOME or
+// XDG_CONFIG_HOME say otherwise (see package paths).
 func GetDefaultConfigPath() (string, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return "", fmt.Errorf("failed to get home directory: %w", err)
-	}
-	return filepath.Join(home, ".tool-hub-mcp.json"), nil
+	return paths.ConfigFile()
 }
 
 // Load reads the configuration from the default path.
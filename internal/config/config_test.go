@@ -89,3 +89,56 @@ func TestLoadNonExistent(t *testing.T) {
 		t.Error("LoadFrom should fail for non-existent file")
 	}
 }
+
+func TestAcceptsPathWildcard(t *testing.T) {
+	cfg := &ServerConfig{PathArguments: map[string][]string{"*": {"path"}, "write": {"body"}}}
+	if !cfg.AcceptsPath("read", "path") || !cfg.AcceptsPath("write", "body") {
+		t.Error("expected configured path arguments to be accepted")
+	}
+	if cfg.AcceptsPath("read", "body") {
+		t.Error("body is only a path argument for write")
+	}
+}
+
+func TestSettingsBudgets(t *testing.T) {
+	var nilSettings *Settings
+	if nilSettings.ResponseBudget() != DefaultMaxResponseBytes || nilSettings.ArgumentBudget() != DefaultMaxArgumentBytes {
+		t.Error("nil settings should use default budgets")
+	}
+
+	s := &Settings{MaxResponseBytes: -1, MaxArgumentBytes: 1024}
+	if s.ResponseBudget() != 0 {
+		t.Error("negative maxResponseBytes should disable the cap")
+	}
+	if s.ArgumentBudget() != 1024 {
+		t.Errorf("ArgumentBudget = %d, want 1024", s.ArgumentBudget())
+	}
+
+	if nilSettings.DescriptionLimit() != DefaultMaxDescriptionChars {
+		t.Error("nil settings should use the default description limit")
+	}
+	if (&Settings{MaxDescriptionChars: -1}).DescriptionLimit() != 0 {
+		t.Error("negative maxDescriptionChars should disable the cap")
+	}
+}
+
+func TestSettingsUpdates(t *testing.T) {
+	var nilSettings *Settings
+	if !nilSettings.UpdatesEnabled() || !nilSettings.AutoUpdateEnabled() || nilSettings.UpdateChannel() != "stable" {
+		t.Error("nil settings should check stable updates and auto-apply them")
+	}
+
+	off := false
+	s := &Settings{AutoUpdate: &off, Update: &UpdateSettings{Channel: "Beta"}}
+	if !s.UpdatesEnabled() || s.AutoUpdateEnabled() {
+		t.Error("autoUpdate false should only disable applying updates")
+	}
+	if s.UpdateChannel() != "beta" {
+		t.Errorf("UpdateChannel = %q, want beta", s.UpdateChannel())
+	}
+
+	s = &Settings{Update: &UpdateSettings{Disabled: true}}
+	if s.UpdatesEnabled() || s.AutoUpdateEnabled() {
+		t.Error("disabled updater should neither check nor apply updates")
+	}
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ContentKey identifies what a server runs regardless of its name: its
+// command and args (or remote URL), how and where the command runs
+// (shell, cwd) and the names of its env variables.
+// Env values are left out so the same server configured with different
+// tokens in two tools still matches. The command is compared by base
+// name (/usr/local/bin/npx, npx.cmd and npx are equal) and npx's -y flag
+// is ignored.
+func (c *ServerConfig) ContentKey() string {
+	var parts []string
+	if c.IsRemote() {
+		parts = append(parts, "remote", strings.TrimRight(c.URL, "/"))
+	} else {
+		command := strings.ToLower(filepath.Base(strings.TrimSpace(c.Command)))
+		command = strings.TrimSuffix(strings.TrimSuffix(command, ".cmd"), ".exe")
+		parts = append(parts, c.Runtime, command)
+		if c.Shell {
+			parts = append(parts, "shell")
+		}
+		if c.Cwd != "" {
+			parts = append(parts, "cwd:"+filepath.Clean(c.Cwd))
+		}
+		if c.Container != nil {
+			parts = append(parts, c.Container.Image)
+		}
+		for _, arg := range c.Args {
+			if command == "npx" && (arg == "-y" || arg == "--yes") {
+				continue
+			}
+			parts = append(parts, arg)
+		}
+	}
+
+	keys := make([]string, 0, len(c.Env))
+	for key := range c.Env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	parts = append(parts, "env:"+strings.Join(keys, ","))
+
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// FindDuplicate returns the key of a configured server running the same
+// thing as server (see ContentKey).
+func (c *Config) FindDuplicate(server *ServerConfig) (string, bool) {
+	key := server.ContentKey()
+	for _, name := range sortedServerKeys(c.Servers) {
+		if c.Servers[name].ContentKey() == key {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// MergeDuplicate folds a duplicate imported as name into the server
+// stored under key: name becomes an alias, so hub_search and hub_execute
+// still accept it, and only one process is spawned for both.
+func (c *Config) MergeDuplicate(key, name string) {
+	server, ok := c.Servers[key]
+	if !ok {
+		return
+	}
+	// A name that already resolves (to this or another server) is kept
+	alias := ToCamelCase(name)
+	if _, taken := c.ResolveServer(alias); taken {
+		return
+	}
+	server.Aliases = append(server.Aliases, alias)
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestContentKey(t *testing.T) {
+	base := &ServerConfig{Command: "npx", Args: []string{"-y", "@lvmk/jira-mcp"}, Env: map[string]string{"JIRA_TOKEN": "a"}}
+
+	same := []*ServerConfig{
+		{Command: "/usr/local/bin/npx", Args: []string{"@lvmk/jira-mcp"}, Env: map[string]string{"JIRA_TOKEN": "b"}},
+		{Command: "npx.cmd", Args: []string{"--yes", "@lvmk/jira-mcp"}, Env: map[string]string{"JIRA_TOKEN": ""}},
+	}
+	for _, server := range same {
+		if server.ContentKey() != base.ContentKey() {
+			t.Errorf("%+v should match %+v", server, base)
+		}
+	}
+
+	different := []*ServerConfig{
+		{Command: "npx", Args: []string{"-y", "@lvmk/outline-mcp"}, Env: map[string]string{"JIRA_TOKEN": "a"}},
+		{Command: "npx", Args: []string{"-y", "@lvmk/jira-mcp"}},
+		{Command: "npx", Args: []string{"-y", "@lvmk/jira-mcp"}, Env: map[string]string{"JIRA_TOKEN": "a", "JIRA_URL": "x"}},
+		{Command: "uvx", Args: []string{"-y", "@lvmk/jira-mcp"}, Env: map[string]string{"JIRA_TOKEN": "a"}},
+		{Command: "npx", Args: []string{"-y", "@lvmk/jira-mcp"}, Env: map[string]string{"JIRA_TOKEN": "a"}, Shell: true},
+	}
+	for _, server := range different {
+		if server.ContentKey() == base.ContentKey() {
+			t.Errorf("%+v should not match %+v", server, base)
+		}
+	}
+
+	remote := &ServerConfig{Type: "sse", URL: "https://mcp.linear.app/sse/"}
+	if remote.ContentKey() != (&ServerConfig{Type: "http", URL: "https://mcp.linear.app/sse"}).ContentKey() {
+		t.Error("remote servers should match on URL")
+	}
+}
+
+func TestContentKeyCwd(t *testing.T) {
+	a := &ServerConfig{Command: "node", Args: []string{"server.js"}, Cwd: "/srv/jira-mcp"}
+	b := &ServerConfig{Command: "node", Args: []string{"server.js"}, Cwd: "/srv/outline-mcp"}
+	if a.ContentKey() == b.ContentKey() {
+		t.Error("servers running the same command in different directories should not match")
+	}
+
+	cleaned := &ServerConfig{Command: "node", Args: []string{"server.js"}, Cwd: "/srv/jira-mcp/"}
+	if a.ContentKey() != cleaned.ContentKey() {
+		t.Error("cwd should be compared as a cleaned path")
+	}
+}
+
+func TestMergeDuplicate(t *testing.T) {
+	cfg := &Config{Servers: map[string]*ServerConfig{
+		"jira":   {Command: "npx", Args: []string{"-y", "@lvmk/jira-mcp"}},
+		"github": {Command: "npx", Args: []string{"-y", "@modelcontextprotocol/server-github"}},
+	}}
+
+	dup := &ServerConfig{Command: "npx", Args: []string{"@lvmk/jira-mcp"}}
+	key, ok := cfg.FindDuplicate(dup)
+	if !ok || key != "jira" {
+		t.Fatalf("FindDuplicate = %q, %v, want jira", key, ok)
+	}
+
+	cfg.MergeDuplicate(key, "jira-cloud")
+	cfg.MergeDuplicate(key, "jira-cloud")
+	cfg.MergeDuplicate(key, "github") // taken by another server
+	if want := []string{"jiraCloud"}; !reflect.DeepEqual(cfg.Servers["jira"].Aliases, want) {
+		t.Errorf("Aliases = %v, want %v", cfg.Servers["jira"].Aliases, want)
+	}
+	if resolved, _ := cfg.ResolveServer("jiraCloud"); resolved != "jira" {
+		t.Errorf("alias should resolve to jira, got %q", resolved)
+	}
+
+	if _, ok := cfg.FindDuplicate(&ServerConfig{Command: "node", Args: []string{"server.js"}}); ok {
+		t.Error("unexpected duplicate")
+	}
+}
@@ -0,0 +1,112 @@
+package config
+
+import (
+	"path"
+	"strings"
+)
+
+// ChildEnvironment standardizes variables injected into every spawned
+// MCP server. Children otherwise inherit whatever environment the AI client
+// was launched with, which produces e.g. timezone-shifted timestamps.
+//
+// A server's own "env" entries always take precedence over these defaults.
+type ChildEnvironment struct {
+	// Timezone is exported as TZ (e.g., "UTC", "Europe/Berlin").
+	Timezone string `json:"timezone,omitempty"`
+
+	// Locale is exported as LANG (e.g., "en_US.UTF-8").
+	Locale string `json:"locale,omitempty"`
+
+	// HTTPProxy is exported as HTTP_PROXY and http_proxy.
+	HTTPProxy string `json:"httpProxy,omitempty"`
+
+	// HTTPSProxy is exported as HTTPS_PROXY and https_proxy.
+	HTTPSProxy string `json:"httpsProxy,omitempty"`
+
+	// NoProxy is exported as NO_PROXY and no_proxy.
+	NoProxy string `json:"noProxy,omitempty"`
+}
+
+// Vars returns the environment variables to inject, omitting unset fields.
+func (e *ChildEnvironment) Vars() map[string]string {
+	vars := make(map[string]string)
+	if e == nil {
+		return vars
+	}
+
+	set := func(value string, keys ...string) {
+		if value == "" {
+			return
+		}
+		for _, key := range keys {
+			vars[key] = value
+		}
+	}
+
+	set(e.Timezone, "TZ")
+	set(e.Locale, "LANG")
+	// Proxy variables are read in either case depending on the runtime
+	set(e.HTTPProxy, "HTTP_PROXY", "http_proxy")
+	set(e.HTTPSProxy, "HTTPS_PROXY", "https_proxy")
+	set(e.NoProxy, "NO_PROXY", "no_proxy")
+
+	return vars
+}
+
+// ChildEnv returns the variables injected into all child processes.
+func (s *Settings) ChildEnv() map[string]string {
+	if s == nil {
+		return map[string]string{}
+	}
+	return s.Environment.Vars()
+}
+
+// DefaultEnvPassthrough is the host environment a server inherits when it
+// sets no EnvPassthrough: what runtimes need to find executables, home and
+// temp directories, locale and proxies, but no credentials.
+var DefaultEnvPassthrough = []string{
+	"PATH", "HOME", "USER", "LOGNAME", "SHELL", "TERM",
+	"TMPDIR", "TMP", "TEMP",
+	"LANG", "LANGUAGE", "LC_*", "TZ",
+	"HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY", "ALL_PROXY",
+	"SSL_CERT_FILE", "SSL_CERT_DIR", "XDG_*",
+	// Windows
+	"SYSTEMROOT", "SYSTEMDRIVE", "WINDIR", "COMSPEC", "PATHEXT",
+	"USERNAME", "USERPROFILE", "HOMEDRIVE", "HOMEPATH",
+	"APPDATA", "LOCALAPPDATA", "PROGRAMDATA", "PROGRAMFILES", "PROGRAMFILES(X86)",
+	"NUMBER_OF_PROCESSORS", "PROCESSOR_ARCHITECTURE",
+}
+
+// InheritsEnv reports whether the server receives the host variable name.
+// Names are matched case-insensitively against EnvPassthrough (or the
+// default set). Variables the server references from its command, args or
+// env values are always inherited, since they cannot work otherwise.
+func (c *ServerConfig) InheritsEnv(name string) bool {
+	patterns := c.EnvPassthrough
+	if patterns == nil {
+		patterns = DefaultEnvPassthrough
+	}
+	upper := strings.ToUpper(name)
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(strings.ToUpper(pattern), upper); ok {
+			return true
+		}
+	}
+
+	texts := append([]string{c.Command}, c.Args...)
+	for key, value := range c.Env {
+		// An empty value defers to the host (see MissingEnvVars)
+		if key == name && value == "" {
+			return true
+		}
+		texts = append(texts, value)
+	}
+	for _, text := range texts {
+		for _, match := range envReferencePattern.FindAllStringSubmatch(text, -1) {
+			if match[1] == name || match[2] == name {
+				return true
+			}
+		}
+	}
+	return false
+}
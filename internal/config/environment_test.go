@@ -0,0 +1,73 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChildEnvironmentVars(t *testing.T) {
+	env := &ChildEnvironment{
+		Timezone:   "UTC",
+		Locale:     "en_US.UTF-8",
+		HTTPSProxy: "http://proxy:3128",
+		NoProxy:    "localhost",
+	}
+
+	want := map[string]string{
+		"TZ":          "UTC",
+		"LANG":        "en_US.UTF-8",
+		"HTTPS_PROXY": "http://proxy:3128",
+		"https_proxy": "http://proxy:3128",
+		"NO_PROXY":    "localhost",
+		"no_proxy":    "localhost",
+	}
+	if got := env.Vars(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Vars() = %v, want %v", got, want)
+	}
+}
+
+func TestSettingsChildEnvNilSafe(t *testing.T) {
+	var settings *Settings
+	if len(settings.ChildEnv()) != 0 {
+		t.Error("nil settings should inject nothing")
+	}
+	if len((&Settings{}).ChildEnv()) != 0 {
+		t.Error("empty settings should inject nothing")
+	}
+}
+
+func TestInheritsEnv(t *testing.T) {
+	server := &ServerConfig{
+		Command: "npx",
+		Args:    []string{"--token", "${GITHUB_TOKEN}"},
+		Env:     map[string]string{"JIRA_URL": "$JIRA_HOST/api", "API_KEY": ""},
+	}
+
+	tests := map[string]bool{
+		"PATH":            true, // default set
+		"Path":            true, // matched case-insensitively (Windows)
+		"LC_ALL":          true, // default pattern
+		"AWS_SECRET_KEY":  false,
+		"GITHUB_TOKEN":    true, // referenced from args
+		"JIRA_HOST":       true, // referenced from env values
+		"API_KEY":         true, // empty env value defers to the host
+		"OPENAI_API_KEY":  false,
+		"SSH_AUTH_SOCK":   false,
+		"XDG_CONFIG_HOME": true,
+	}
+	for name, want := range tests {
+		if got := server.InheritsEnv(name); got != want {
+			t.Errorf("InheritsEnv(%q) = %v, want %v", name, got, want)
+		}
+	}
+
+	server.EnvPassthrough = []string{"AWS_*"}
+	if !server.InheritsEnv("AWS_SECRET_KEY") || server.InheritsEnv("PATH") {
+		t.Error("an explicit envPassthrough should replace the default set")
+	}
+
+	server.EnvPassthrough = []string{"*"}
+	if !server.InheritsEnv("OPENAI_API_KEY") {
+		t.Error(`["*"] should inherit everything`)
+	}
+}
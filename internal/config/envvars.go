@@ -0,0 +1,98 @@
+package config
+
+import (
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// envReferencePattern matches $VAR, ${VAR} and ${env:VAR} (VS Code style)
+// references inside commands, arguments and env values.
+var envReferencePattern = regexp.MustCompile(`\$\{(?:env:)?([A-Za-z_][A-Za-z0-9_]*)\}|\$([A-Za-z_][A-Za-z0-9_]*)`)
+
+// placeholderPattern matches values copied from READMEs that stand in for
+// a real value: YOUR_API_KEY, your-token-here, <token>, [API_KEY],
+// {{TOKEN}}, xxxx, changeme, REPLACE_ME.
+var placeholderPattern = regexp.MustCompile(`(?i)^(?:your[\s_-].*|.*[\s_-]here|<[^<>]*>|\[[^\[\]]*\]|\{\{.*\}\}|x{3,}|\*{3,}|\.{3}|change[_-]?me|replace[_-]?me|placeholder|todo)$`)
+
+// IsPlaceholder reports whether an env value is a placeholder for a real
+// value rather than the value itself.
+func IsPlaceholder(value string) bool {
+	return placeholderPattern.MatchString(strings.TrimSpace(value))
+}
+
+// EnvVarReferences returns every environment variable the configured
+// servers depend on, mapped to the sorted names of the servers using it.
+// This covers the keys of each server's env map plus variables referenced
+// from its command, args and env values.
+func (c *Config) EnvVarReferences() map[string][]string {
+	refs := make(map[string][]string)
+	add := func(name, server string) {
+		for _, existing := range refs[name] {
+			if existing == server {
+				return
+			}
+		}
+		refs[name] = append(refs[name], server)
+	}
+
+	for serverName, server := range c.Servers {
+		texts := append([]string{server.Command}, server.Args...)
+		for key, value := range server.Env {
+			add(key, serverName)
+			texts = append(texts, value)
+		}
+		for _, text := range texts {
+			for _, match := range envReferencePattern.FindAllStringSubmatch(text, -1) {
+				name := match[1]
+				if name == "" {
+					name = match[2]
+				}
+				add(name, serverName)
+			}
+		}
+	}
+
+	for _, servers := range refs {
+		sort.Strings(servers)
+	}
+	return refs
+}
+
+// MissingEnvVars returns the sorted names of variables the server needs but
+// that have no value: env keys left empty, and references in its command,
+// args or env values to variables that are neither in its env map nor set
+// in the current environment.
+func (c *ServerConfig) MissingEnvVars() []string {
+	var missing []string
+	seen := make(map[string]bool)
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			missing = append(missing, name)
+		}
+	}
+
+	texts := append([]string{c.Command}, c.Args...)
+	for key, value := range c.Env {
+		if value == "" && os.Getenv(key) == "" {
+			add(key)
+		}
+		texts = append(texts, value)
+	}
+	for _, text := range texts {
+		for _, match := range envReferencePattern.FindAllStringSubmatch(text, -1) {
+			name := match[1]
+			if name == "" {
+				name = match[2]
+			}
+			if c.Env[name] == "" && os.Getenv(name) == "" {
+				add(name)
+			}
+		}
+	}
+
+	sort.Strings(missing)
+	return missing
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEnvVarReferences(t *testing.T) {
+	cfg := &Config{Servers: map[string]*ServerConfig{
+		"jira": {
+			Command: "npx",
+			Args:    []string{"-y", "jira-mcp", "--url", "${JIRA_URL}"},
+			Env:     map[string]string{"JIRA_TOKEN": "", "AUTH": "Bearer $SHARED_TOKEN"},
+		},
+		"github": {
+			Command: "docker",
+			Args:    []string{"run", "-e", "GITHUB_TOKEN=${env:GITHUB_TOKEN}"},
+			Env:     map[string]string{"SHARED_TOKEN": "x"},
+		},
+		"plain": {Command: "node", Args: []string{"server.js", "costs $5"}},
+	}}
+
+	got := cfg.EnvVarReferences()
+	want := map[string][]string{
+		"JIRA_URL":     {"jira"},
+		"JIRA_TOKEN":   {"jira"},
+		"AUTH":         {"jira"},
+		"SHARED_TOKEN": {"github", "jira"},
+		"GITHUB_TOKEN": {"github"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("EnvVarReferences() = %v, want %v", got, want)
+	}
+}
+
+func TestMissingEnvVars(t *testing.T) {
+	t.Setenv("SET_TOKEN", "x")
+
+	server := &ServerConfig{
+		Command: "npx",
+		Args:    []string{"--token", "${SET_TOKEN}", "--org", "$UNSET_ORG"},
+		Env: map[string]string{
+			"EMPTY_KEY": "",
+			"URL":       "https://${UNSET_HOST}/api",
+			"LOCAL":     "value",
+			"DERIVED":   "${LOCAL}",
+		},
+	}
+
+	got := server.MissingEnvVars()
+	want := []string{"EMPTY_KEY", "UNSET_HOST", "UNSET_ORG"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MissingEnvVars() = %v, want %v", got, want)
+	}
+}
+
+func TestIsPlaceholder(t *testing.T) {
+	placeholders := []string{"YOUR_API_KEY", "your-token-here", "<your-token>", "[API_KEY]", "{{TOKEN}}",
+		"xxxxxxxx", "changeme", "REPLACE_ME", "paste_token_here", "your github token", "..."}
+	for _, value := range placeholders {
+		if !IsPlaceholder(value) {
+			t.Errorf("IsPlaceholder(%q) = false, want true", value)
+		}
+	}
+
+	values := []string{"ghp_1234567890abcdef", "https://jira.acme.dev", "${JIRA_TOKEN}", "true", "", "yourcompany.atlassian.net", "Bearer abc"}
+	for _, value := range values {
+		if IsPlaceholder(value) {
+			t.Errorf("IsPlaceholder(%q) = true, want false", value)
+		}
+	}
+}
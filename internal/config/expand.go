@@ -0,0 +1,114 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ExpandsPaths reports whether ~ and $VAR references are expanded at
+// spawn time (see Expanded).
+func (c *ServerConfig) ExpandsPaths() bool {
+	return c.ExpandPaths == nil || *c.ExpandPaths
+}
+
+// Expanded returns a copy of the server with ~ and $VAR, ${VAR} and
+// ${env:VAR} references expanded in its command, args, env values and cwd,
+// so configs imported from other clients work wherever the hub runs.
+// Variables are looked up in the server's env, then the host environment;
+// unset ones are left as written. A relative command (./server) is
+// resolved against cwd.
+//
+// Servers with expandPaths: false are returned as-is. For shell servers
+// only cwd is expanded, since their shell expands the command line itself.
+// Inside containers only variables are expanded: ~ and relative paths
+// belong to the container.
+func (c *ServerConfig) Expanded() *ServerConfig {
+	if !c.ExpandsPaths() {
+		return c
+	}
+
+	home, _ := os.UserHomeDir()
+	expand := func(value string) string {
+		value = expandVars(value, c.Env)
+		if !c.IsContainer() {
+			value = expandHome(value, home)
+		}
+		return value
+	}
+
+	copied := *c
+	copied.Cwd = expand(c.Cwd)
+	if c.Shell {
+		return &copied
+	}
+	copied.Command = expand(c.Command)
+	copied.Args = make([]string, len(c.Args))
+	for i, arg := range c.Args {
+		copied.Args[i] = expand(arg)
+	}
+	if c.Env != nil {
+		copied.Env = make(map[string]string, len(c.Env))
+		for key, value := range c.Env {
+			copied.Env[key] = expand(value)
+		}
+	}
+
+	if !c.IsContainer() && copied.Cwd != "" && isRelativePath(copied.Command) {
+		copied.Command = filepath.Join(copied.Cwd, copied.Command)
+	}
+	return &copied
+}
+
+// expandVars replaces variable references (envReferencePattern) with their
+// values from env or the host environment, keeping unset ones.
+func expandVars(value string, env map[string]string) string {
+	if !strings.Contains(value, "$") {
+		return value
+	}
+	return envReferencePattern.ReplaceAllStringFunc(value, func(ref string) string {
+		match := envReferencePattern.FindStringSubmatch(ref)
+		name := match[1]
+		if name == "" {
+			name = match[2]
+		}
+		if v, ok := env[name]; ok && v != "" && !strings.Contains(v, "$") {
+			return v
+		}
+		if v, ok := os.LookupEnv(name); ok {
+			return v
+		}
+		return ref
+	})
+}
+
+// expandHome replaces a leading ~, or one following "=" as in
+// --root=~/projects, with the home directory.
+func expandHome(value, home string) string {
+	if home == "" {
+		return value
+	}
+	prefix, rest := "", value
+	if i := strings.Index(value, "="); i >= 0 && strings.HasPrefix(value, "-") {
+		prefix, rest = value[:i+1], value[i+1:]
+	}
+	if !isHomePath(rest) {
+		return value
+	}
+	return prefix + home + rest[1:]
+}
+
+// isHomePath reports whether path is ~ or starts with ~/.
+func isHomePath(path string) bool {
+	return path == "~" || strings.HasPrefix(path, "~/") || strings.HasPrefix(path, `~\`)
+}
+
+// isRelativePath reports whether path explicitly starts with ./ or ../.
+func isRelativePath(path string) bool {
+	for _, prefix := range []string{"./", "../", `.\`, `..\`} {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
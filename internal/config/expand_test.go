@@ -0,0 +1,67 @@
+package config
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestExpanded(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("PROJECTS", "/src")
+	t.Setenv("GITHUB_TOKEN", "ghp_host")
+
+	server := &ServerConfig{
+		Command: "./bin/server",
+		Args:    []string{"~/notes", "--root=~/projects", "${PROJECTS}/app", "$UNSET_VAR", "price~1"},
+		Env:     map[string]string{"TOKEN": "${env:GITHUB_TOKEN}", "DATA_DIR": "~/data", "REGION": "eu"},
+		Cwd:     "~/work",
+	}
+	got := server.Expanded()
+
+	if got.Cwd != home+"/work" {
+		t.Errorf("Cwd = %q", got.Cwd)
+	}
+	if got.Command != filepath.Join(got.Cwd, "bin/server") {
+		t.Errorf("relative command should resolve against cwd, got %q", got.Command)
+	}
+	wantArgs := []string{home + "/notes", "--root=" + home + "/projects", "/src/app", "$UNSET_VAR", "price~1"}
+	if !reflect.DeepEqual(got.Args, wantArgs) {
+		t.Errorf("Args = %v, want %v", got.Args, wantArgs)
+	}
+	wantEnv := map[string]string{"TOKEN": "ghp_host", "DATA_DIR": home + "/data", "REGION": "eu"}
+	if !reflect.DeepEqual(got.Env, wantEnv) {
+		t.Errorf("Env = %v, want %v", got.Env, wantEnv)
+	}
+	if server.Args[0] != "~/notes" || server.Env["DATA_DIR"] != "~/data" {
+		t.Error("Expanded must not modify the original config")
+	}
+
+	// Server env takes precedence over the host
+	server = &ServerConfig{Command: "npx", Args: []string{"--token=$GITHUB_TOKEN"}, Env: map[string]string{"GITHUB_TOKEN": "ghp_server"}}
+	if got := server.Expanded().Args[0]; got != "--token=ghp_server" {
+		t.Errorf("expected the server's env value, got %q", got)
+	}
+}
+
+func TestExpandedOptOut(t *testing.T) {
+	off := false
+	server := &ServerConfig{Command: "npx", Args: []string{"~/notes"}, ExpandPaths: &off}
+	if got := server.Expanded(); got.Args[0] != "~/notes" {
+		t.Errorf("expandPaths: false should keep args, got %q", got.Args[0])
+	}
+
+	// Shell servers expand the command line themselves
+	server = &ServerConfig{Command: "cat ~/notes | server", Shell: true}
+	if got := server.Expanded(); got.Command != server.Command {
+		t.Errorf("shell command should be kept, got %q", got.Command)
+	}
+
+	// ~ inside a container refers to the container's home
+	server = &ServerConfig{Runtime: RuntimeDocker, Container: &ContainerSettings{Image: "mcp/git"}, Args: []string{"~/repo"}}
+	if got := server.Expanded(); got.Args[0] != "~/repo" {
+		t.Errorf("container args should keep ~, got %q", got.Args[0])
+	}
+}
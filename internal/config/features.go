@@ -0,0 +1,80 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Experimental features that can be enabled individually via
+// settings.features or the serve --enable-feature flag.
+const (
+	// FeatureSemanticSearch fuses embedding similarity into hub_search ranking.
+	FeatureSemanticSearch = "semanticSearch"
+
+	// FeaturePassthrough forwards child tool results without re-encoding.
+	FeaturePassthrough = "passthrough"
+
+	// FeatureThompsonBandit ranks tools with Thompson sampling instead of ε-greedy.
+	FeatureThompsonBandit = "thompsonBandit"
+)
+
+// KnownFeatures maps experimental feature names to short descriptions.
+var KnownFeatures = map[string]string{
+	FeatureSemanticSearch: "hybrid BM25 + semantic search ranking",
+	FeaturePassthrough:    "structured child result passthrough in hub_execute",
+	FeatureThompsonBandit: "Thompson sampling tool ranking",
+}
+
+// FeatureEnabled reports whether an experimental feature is switched on,
+// either in settings.features or for this process via EnableFeatures.
+func (c *Config) FeatureEnabled(name string) bool {
+	if c == nil {
+		return false
+	}
+	if c.runtimeFeatures[name] {
+		return true
+	}
+	return c.Settings != nil && c.Settings.Features[name]
+}
+
+// EnabledFeatures returns the names of all enabled features, sorted.
+func (c *Config) EnabledFeatures() []string {
+	var names []string
+	for name := range KnownFeatures {
+		if c.FeatureEnabled(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// EnableFeatures switches on the given features for this process only,
+// validating their names. Runtime features are never written back to disk,
+// so CLI flags can opt into previews without editing the config file.
+func (c *Config) EnableFeatures(names []string) error {
+	for _, name := range names {
+		if _, ok := KnownFeatures[name]; !ok {
+			return fmt.Errorf("unknown feature '%s' (known: %s)", name, knownFeatureList())
+		}
+	}
+
+	if c.runtimeFeatures == nil {
+		c.runtimeFeatures = make(map[string]bool)
+	}
+	for _, name := range names {
+		c.runtimeFeatures[name] = true
+	}
+	return nil
+}
+
+// knownFeatureList returns a comma-separated, sorted list of feature names.
+func knownFeatureList() string {
+	names := make([]string, 0, len(KnownFeatures))
+	for name := range KnownFeatures {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
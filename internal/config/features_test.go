@@ -0,0 +1,60 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFeatureEnabled(t *testing.T) {
+	var nilConfig *Config
+	if nilConfig.FeatureEnabled(FeatureSemanticSearch) {
+		t.Error("nil config should have all features off")
+	}
+
+	cfg := &Config{Settings: &Settings{Features: map[string]bool{
+		FeatureSemanticSearch: true,
+		FeatureThompsonBandit: false,
+	}}}
+	if !cfg.FeatureEnabled(FeatureSemanticSearch) {
+		t.Error("semanticSearch should be enabled")
+	}
+	if cfg.FeatureEnabled(FeatureThompsonBandit) {
+		t.Error("thompsonBandit should be disabled")
+	}
+	if got := cfg.EnabledFeatures(); !reflect.DeepEqual(got, []string{FeatureSemanticSearch}) {
+		t.Errorf("unexpected enabled features: %v", got)
+	}
+}
+
+func TestEnableFeatures(t *testing.T) {
+	cfg := &Config{Servers: map[string]*ServerConfig{}}
+
+	if err := cfg.EnableFeatures([]string{FeaturePassthrough, FeatureThompsonBandit}); err != nil {
+		t.Fatalf("EnableFeatures failed: %v", err)
+	}
+	if !cfg.FeatureEnabled(FeaturePassthrough) || !cfg.FeatureEnabled(FeatureThompsonBandit) {
+		t.Error("features not enabled")
+	}
+
+	err := cfg.EnableFeatures([]string{"warpDrive"})
+	if err == nil || !strings.Contains(err.Error(), "unknown feature") {
+		t.Errorf("expected unknown feature error, got %v", err)
+	}
+}
+
+func TestRuntimeFeaturesNotPersisted(t *testing.T) {
+	cfg := NewConfig()
+	if err := cfg.EnableFeatures([]string{FeatureSemanticSearch}); err != nil {
+		t.Fatalf("EnableFeatures failed: %v", err)
+	}
+
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if strings.Contains(string(data), FeatureSemanticSearch) {
+		t.Errorf("runtime features leaked into saved config: %s", data)
+	}
+}
@@ -0,0 +1,15 @@
+//go:build darwin
+
+package config
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// detachTerminal starts cmd in a new session without a controlling
+// terminal, so security reads a prompted password from stdin instead of
+// /dev/tty.
+func detachTerminal(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
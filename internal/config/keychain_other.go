@@ -0,0 +1,8 @@
+//go:build !darwin
+
+package config
+
+import "os/exec"
+
+// detachTerminal is only needed for the macOS security tool.
+func detachTerminal(cmd *exec.Cmd) {}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"os"
+	"strings"
+)
+
+// LearningEnvVar switches learning off when set to "false" or "0".
+const LearningEnvVar = "TOOL_HUB_MCP_LEARNING"
+
+// Learning algorithms accepted in settings.learning.algorithm.
+const (
+	AlgorithmEpsilonGreedy = "epsilonGreedy"
+	AlgorithmThompson      = "thompson"
+)
+
+// LearningEnabled reports whether usage tracking and learned ranking are
+// on. It is off when disabled for this process, by LearningEnvVar, or in
+// settings.learning.enabled.
+func (c *Config) LearningEnabled() bool {
+	if c == nil {
+		return true
+	}
+	if c.learningOff {
+		return false
+	}
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(LearningEnvVar))) {
+	case "false", "0", "off", "no":
+		return false
+	}
+	s := c.Settings
+	return s == nil || s.Learning == nil || s.Learning.Enabled == nil || *s.Learning.Enabled
+}
+
+// DisableLearning turns learning off for this process only; like runtime
+// features it is never written back to disk.
+func (c *Config) DisableLearning() {
+	c.learningOff = true
+}
+
+// LearningAlgorithm returns the bandit used for learned ranking. Thompson
+// sampling is chosen by settings.learning.algorithm or the thompsonBandit
+// experimental feature; anything else falls back to ε-greedy.
+func (c *Config) LearningAlgorithm() string {
+	if c == nil {
+		return AlgorithmEpsilonGreedy
+	}
+	if c.FeatureEnabled(FeatureThompsonBandit) {
+		return AlgorithmThompson
+	}
+	s := c.Settings
+	if s != nil && s.Learning != nil && strings.EqualFold(s.Learning.Algorithm, AlgorithmThompson) {
+		return AlgorithmThompson
+	}
+	return AlgorithmEpsilonGreedy
+}
@@ -0,0 +1,56 @@
+package config
+
+import "testing"
+
+func TestLearningEnabled(t *testing.T) {
+	t.Setenv(LearningEnvVar, "")
+
+	var nilCfg *Config
+	if !nilCfg.LearningEnabled() || !NewConfig().LearningEnabled() {
+		t.Error("learning should be on by default")
+	}
+
+	off := false
+	cfg := NewConfig()
+	cfg.Settings.Learning = &LearningSettings{Enabled: &off}
+	if cfg.LearningEnabled() {
+		t.Error("settings.learning.enabled false should disable learning")
+	}
+
+	cfg = NewConfig()
+	cfg.DisableLearning()
+	if cfg.LearningEnabled() {
+		t.Error("DisableLearning should disable learning")
+	}
+
+	t.Setenv(LearningEnvVar, "false")
+	if NewConfig().LearningEnabled() {
+		t.Errorf("%s=false should disable learning", LearningEnvVar)
+	}
+}
+
+func TestLearningAlgorithm(t *testing.T) {
+	var nilCfg *Config
+	if nilCfg.LearningAlgorithm() != AlgorithmEpsilonGreedy || NewConfig().LearningAlgorithm() != AlgorithmEpsilonGreedy {
+		t.Error("ε-greedy should be the default")
+	}
+
+	cfg := NewConfig()
+	cfg.Settings.Learning = &LearningSettings{Algorithm: "Thompson"}
+	if cfg.LearningAlgorithm() != AlgorithmThompson {
+		t.Error("settings.learning.algorithm should select thompson")
+	}
+
+	cfg.Settings.Learning.Algorithm = "ucb"
+	if cfg.LearningAlgorithm() != AlgorithmEpsilonGreedy {
+		t.Error("unknown algorithms should fall back to ε-greedy")
+	}
+
+	cfg = NewConfig()
+	if err := cfg.EnableFeatures([]string{FeatureThompsonBandit}); err != nil {
+		t.Fatalf("EnableFeatures failed: %v", err)
+	}
+	if cfg.LearningAlgorithm() != AlgorithmThompson {
+		t.Error("thompsonBandit feature should select thompson")
+	}
+}
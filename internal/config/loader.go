@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"runtime"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/redact"
 )
 
 // LoadFrom reads config with enhanced error handling
@@ -34,6 +36,22 @@ func LoadFrom(path string) (*Config, error) {
 		return nil, fmt.Errorf("failed to read config: %w", err)
 	}
 
+	problems, err := validateSchema(data)
+	if err != nil {
+		return nil, &InvalidConfigError{
+			Path:    path,
+			Message: fmt.Sprintf("JSON parse error: %v", err),
+			Hint:    "Restore from .bak file if available",
+		}
+	}
+	if len(problems) > 0 {
+		return nil, &InvalidConfigError{
+			Path:    path,
+			Message: formatProblems(problems),
+			Hint:    "Run 'tool-hub-mcp config schema' for the expected format, or restore from .bak",
+		}
+	}
+
 	var cfg Config
 	if err := json.Unmarshal(data, &cfg); err != nil {
 		return nil, &InvalidConfigError{
@@ -47,10 +65,49 @@ func LoadFrom(path string) (*Config, error) {
 	if cfg.Servers == nil {
 		cfg.Servers = make(map[string]*ServerConfig)
 	}
+	migrate(&cfg)
+
+	// Decrypt env values stored with settings.secrets.encrypt
+	if err := decryptSecrets(&cfg); err != nil {
+		return nil, err
+	}
+
+	// Mask secret env values wherever they later show up in output
+	for _, server := range cfg.Servers {
+		if server != nil {
+			redact.Secrets.AddEnv(server.Env)
+			redact.Secrets.AddEnv(server.Headers)
+		}
+	}
+	for _, webhook := range cfg.Settings.WebhookTargets() {
+		// Webhook URLs (Slack, Teams) carry their token in the path
+		redact.Secrets.Add(webhook.URL)
+		redact.Secrets.AddEnv(webhook.Headers)
+	}
+	if t := cfg.Settings.TelemetryConfig(); t != nil {
+		redact.Secrets.AddEnv(t.Headers)
+	}
 
 	return &cfg, nil
 }
 
+// ConfigVersion is the config format version written by NewConfig and
+// recorded on load, so the next save persists the migrated config.
+const ConfigVersion = 1
+
+// migrate upgrades a config written by an older release. Unversioned
+// configs all got processPoolSize 3 from NewConfig while the pool never
+// stopped idle servers; keeping it would stop servers after upgrading,
+// so it is treated as unset (unlimited).
+func migrate(cfg *Config) {
+	if cfg.Version < 1 && cfg.Settings != nil && cfg.Settings.ProcessPoolSize == DefaultProcessPoolSize {
+		cfg.Settings.ProcessPoolSize = 0
+	}
+	if cfg.Version < ConfigVersion {
+		cfg.Version = ConfigVersion
+	}
+}
+
 // getReadPermissionFix returns platform-specific fix command
 func getReadPermissionFix(path string) string {
 	switch runtime.GOOS {
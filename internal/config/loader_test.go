@@ -175,3 +175,36 @@ func TestLoadFromWithEmptyCommand(t *testing.T) {
 		t.Errorf("expected empty command, got %q", cfg.Servers["testServer"].Command)
 	}
 }
+
+// TestLoadFromMigratesPoolSize verifies the processPoolSize older releases
+// saved into every config is cleared, while a versioned one is kept.
+func TestLoadFromMigratesPoolSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    int
+		version int
+	}{
+		{"legacy default", `{"servers": {}, "settings": {"processPoolSize": 3}}`, 0, ConfigVersion},
+		{"legacy custom size", `{"servers": {}, "settings": {"processPoolSize": 5}}`, 5, ConfigVersion},
+		{"versioned", `{"version": 1, "servers": {}, "settings": {"processPoolSize": 3}}`, 3, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.json")
+			if err := os.WriteFile(path, []byte(tt.data), 0644); err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := LoadFrom(path)
+			if err != nil {
+				t.Fatalf("LoadFrom failed: %v", err)
+			}
+			if got := cfg.Settings.ProcessPoolLimit(); got != tt.want {
+				t.Errorf("ProcessPoolLimit() = %d, want %d", got, tt.want)
+			}
+			if cfg.Version != tt.version {
+				t.Errorf("Version = %d, want %d", cfg.Version, tt.version)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/fuzzy"
+)
+
+// UniqueServerName returns the config key for a server named name in its
+// source. ToCamelCase can map distinct names ("my-server", "my_server") to
+// the same key; when the key belongs to a server with a different original
+// name, a numeric suffix is appended (myServer2, myServer3, ...).
+// exists is true if name itself is already registered under the returned key,
+// including under a key it was renamed to.
+func UniqueServerName(servers map[string]*ServerConfig, name string) (key string, exists bool) {
+	for key, server := range servers {
+		if server.OriginalName == name {
+			return key, true
+		}
+	}
+
+	base := ToCamelCase(name)
+	for i := 1; ; i++ {
+		key = base
+		if i > 1 {
+			key = base + strconv.Itoa(i)
+		}
+		existing, ok := servers[key]
+		if !ok {
+			return key, false
+		}
+		if existing.matchesName(key, base, name) {
+			return key, true
+		}
+	}
+}
+
+// FindServerKey returns the key of the server registered from the source
+// name, so re-synced source entries match even after collision suffixing.
+func FindServerKey(servers map[string]*ServerConfig, name string) (string, bool) {
+	for key, server := range servers {
+		if server.OriginalName == name {
+			return key, true
+		}
+	}
+	key := ToCamelCase(name)
+	if server, ok := servers[key]; ok && server.matchesName(key, key, name) {
+		return key, true
+	}
+	return "", false
+}
+
+// SetOriginalName records name as the server's original name if it
+// differs from key.
+func (c *ServerConfig) SetOriginalName(key, name string) {
+	if name != key {
+		c.OriginalName = name
+	}
+}
+
+// DisplayName returns the name to show for a server stored under key:
+// its original source name when known, otherwise the key.
+func (c *ServerConfig) DisplayName(key string) string {
+	if c.OriginalName != "" {
+		return c.OriginalName
+	}
+	return key
+}
+
+// matchesName reports whether the server stored under key was registered
+// from name. Entries without OriginalName (the key was the name, or the
+// config predates collision handling) match on their unsuffixed key.
+func (c *ServerConfig) matchesName(key, base, name string) bool {
+	if c.OriginalName != "" {
+		return c.OriginalName == name
+	}
+	return key == base
+}
+
+// ResolveServer returns the key of the server called name: its config key,
+// original source name, or one of its aliases, in that order.
+func (c *Config) ResolveServer(name string) (string, bool) {
+	if _, ok := c.Servers[name]; ok {
+		return name, true
+	}
+	if key, ok := FindServerKey(c.Servers, name); ok {
+		return key, true
+	}
+	for key, server := range c.Servers {
+		for _, alias := range server.Aliases {
+			if alias == name {
+				return key, true
+			}
+		}
+	}
+	return c.resolveFold(name)
+}
+
+// resolveFold matches name against server keys, source names and aliases
+// ignoring case and separators ("Jira-MCP" finds jiraMcp). It only
+// succeeds when exactly one server matches.
+func (c *Config) resolveFold(name string) (string, bool) {
+	folded := foldName(name)
+	if folded == "" {
+		return "", false
+	}
+
+	match := ""
+	for key, server := range c.Servers {
+		candidates := append([]string{key, server.OriginalName}, server.Aliases...)
+		for _, candidate := range candidates {
+			if candidate != "" && foldName(candidate) == folded {
+				if match != "" && match != key {
+					return "", false
+				}
+				match = key
+				break
+			}
+		}
+	}
+	return match, match != ""
+}
+
+// foldName normalizes a server name for case-insensitive matching.
+func foldName(name string) string {
+	return strings.ToLower(ToCamelCase(strings.TrimSpace(name)))
+}
+
+// ServerNotFoundError is returned when a server name matches no server.
+type ServerNotFoundError struct {
+	Name       string
+	Suggestion string   // nearest server name, if any is close enough
+	Available  []string // sorted server keys
+}
+
+func (e *ServerNotFoundError) Error() string {
+	if e.Suggestion != "" {
+		return fmt.Sprintf("server '%s' not found. Did you mean '%s'?", e.Name, e.Suggestion)
+	}
+	return fmt.Sprintf("server '%s' not found. Available servers: %s", e.Name, strings.Join(e.Available, ", "))
+}
+
+// ServerNotFound returns the error for an unknown server name, suggesting
+// the nearest registered name.
+func (c *Config) ServerNotFound(name string) *ServerNotFoundError {
+	return &ServerNotFoundError{
+		Name:       name,
+		Suggestion: c.SuggestServer(name),
+		Available:  sortedServerKeys(c.Servers),
+	}
+}
+
+// SuggestServer returns the server key, source name or alias closest to
+// name by edit distance, or "" when none is within a third of its length
+// (at least 2 edits).
+func (c *Config) SuggestServer(name string) string {
+	folded := foldName(name)
+	if folded == "" {
+		return ""
+	}
+
+	maxDistance := len(folded) / 3
+	if maxDistance < 2 {
+		maxDistance = 2
+	}
+
+	best, bestDistance := "", maxDistance+1
+	for _, key := range sortedServerKeys(c.Servers) {
+		server := c.Servers[key]
+		candidates := append([]string{key, server.OriginalName}, server.Aliases...)
+		for _, candidate := range candidates {
+			if candidate == "" {
+				continue
+			}
+			if d := fuzzy.Distance(folded, foldName(candidate)); d < bestDistance {
+				best, bestDistance = key, d
+			}
+		}
+	}
+	return best
+}
+
+// sortedServerKeys returns server keys in sorted order so suggestions and
+// listings are deterministic.
+func sortedServerKeys(servers map[string]*ServerConfig) []string {
+	keys := make([]string, 0, len(servers))
+	for key := range servers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// RenameServer moves the server stored under oldKey to newKey. With
+// keepAlias the old key stays usable as an alias. newKey must not already
+// name another server. The source name is kept in OriginalName.
+func (c *Config) RenameServer(oldKey, newKey string, keepAlias bool) error {
+	server, ok := c.Servers[oldKey]
+	if !ok {
+		return fmt.Errorf("server '%s' not found", oldKey)
+	}
+	if newKey == oldKey {
+		return fmt.Errorf("server is already named '%s'", newKey)
+	}
+	if key, taken := c.ResolveServer(newKey); taken && key != oldKey {
+		return fmt.Errorf("name '%s' is already used by server '%s'", newKey, key)
+	}
+
+	aliases := make([]string, 0, len(server.Aliases)+1)
+	for _, alias := range server.Aliases {
+		if alias != newKey {
+			aliases = append(aliases, alias)
+		}
+	}
+	if keepAlias {
+		aliases = append(aliases, oldKey)
+	}
+	server.Aliases = aliases
+	if len(server.Aliases) == 0 {
+		server.Aliases = nil
+	}
+
+	// Keep the source name so re-importing updates the renamed entry
+	if server.OriginalName == "" {
+		server.OriginalName = oldKey
+	}
+
+	delete(c.Servers, oldKey)
+	c.Servers[newKey] = server
+	return nil
+}
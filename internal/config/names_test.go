@@ -0,0 +1,156 @@
+package config
+
+import "testing"
+
+func TestUniqueServerNameSuffixesCollisions(t *testing.T) {
+	servers := map[string]*ServerConfig{}
+
+	key, exists := UniqueServerName(servers, "my-server")
+	if key != "myServer" || exists {
+		t.Fatalf("first name = %s, %v", key, exists)
+	}
+	servers[key] = &ServerConfig{Command: "a"}
+	servers[key].SetOriginalName(key, "my-server")
+
+	key, exists = UniqueServerName(servers, "my_server")
+	if key != "myServer2" || exists {
+		t.Fatalf("colliding name = %s, %v; want myServer2", key, exists)
+	}
+	servers[key] = &ServerConfig{Command: "b"}
+	servers[key].SetOriginalName(key, "my_server")
+
+	// Re-importing either name finds its own entry
+	if key, exists := UniqueServerName(servers, "my_server"); key != "myServer2" || !exists {
+		t.Errorf("re-import of my_server = %s, %v", key, exists)
+	}
+	if key, exists := UniqueServerName(servers, "my-server"); key != "myServer" || !exists {
+		t.Errorf("re-import of my-server = %s, %v", key, exists)
+	}
+
+	// A third spelling takes the next suffix
+	if key, _ := UniqueServerName(servers, "MyServer"); key != "myServer3" {
+		t.Errorf("third spelling = %s, want myServer3", key)
+	}
+}
+
+func TestUniqueServerNameLegacyEntries(t *testing.T) {
+	// Configs written before collision handling have no originalName
+	servers := map[string]*ServerConfig{"jiraMcp": {Command: "npx"}}
+
+	if key, exists := UniqueServerName(servers, "jira-mcp"); key != "jiraMcp" || !exists {
+		t.Errorf("legacy entry should be treated as the same server: %s, %v", key, exists)
+	}
+}
+
+func TestFindServerKey(t *testing.T) {
+	servers := map[string]*ServerConfig{
+		"myServer":  {OriginalName: "my-server"},
+		"myServer2": {OriginalName: "my_server"},
+		"jira":      {},
+	}
+
+	tests := map[string]string{
+		"my-server": "myServer",
+		"my_server": "myServer2",
+		"jira":      "jira",
+	}
+	for name, want := range tests {
+		if got, ok := FindServerKey(servers, name); !ok || got != want {
+			t.Errorf("FindServerKey(%s) = %s, %v; want %s", name, got, ok, want)
+		}
+	}
+	if _, ok := FindServerKey(servers, "MyServer"); ok {
+		t.Error("unregistered spelling should not match a suffixed entry")
+	}
+}
+
+func TestDisplayName(t *testing.T) {
+	if got := (&ServerConfig{OriginalName: "my_server"}).DisplayName("myServer2"); got != "my_server" {
+		t.Errorf("DisplayName = %s", got)
+	}
+	if got := (&ServerConfig{}).DisplayName("jira"); got != "jira" {
+		t.Errorf("DisplayName = %s", got)
+	}
+}
+
+func TestRenameServer(t *testing.T) {
+	cfg := NewConfig()
+	cfg.Servers["jira"] = &ServerConfig{Command: "npx"}
+	cfg.Servers["github"] = &ServerConfig{Command: "npx", Aliases: []string{"gh"}}
+
+	if err := cfg.RenameServer("jira", "gh", false); err == nil {
+		t.Error("renaming onto another server's alias should fail")
+	}
+	if err := cfg.RenameServer("jira", "tracker", true); err != nil {
+		t.Fatalf("RenameServer failed: %v", err)
+	}
+
+	server, ok := cfg.Servers["tracker"]
+	if !ok || cfg.Servers["jira"] != nil {
+		t.Fatalf("expected server moved to tracker, got %v", cfg.Servers)
+	}
+	if server.OriginalName != "jira" || len(server.Aliases) != 1 || server.Aliases[0] != "jira" {
+		t.Errorf("unexpected renamed server: %+v", server)
+	}
+
+	// Old name, alias and source name all resolve
+	for _, name := range []string{"tracker", "jira"} {
+		if key, ok := cfg.ResolveServer(name); !ok || key != "tracker" {
+			t.Errorf("ResolveServer(%s) = %s, %v", name, key, ok)
+		}
+	}
+	if key, ok := cfg.ResolveServer("gh"); !ok || key != "github" {
+		t.Errorf("ResolveServer(gh) = %s, %v", key, ok)
+	}
+
+	// Re-importing the source name updates the renamed entry
+	if key, exists := UniqueServerName(cfg.Servers, "jira"); key != "tracker" || !exists {
+		t.Errorf("re-import of jira = %s, %v", key, exists)
+	}
+}
+
+func TestResolveServerIgnoresCaseAndSeparators(t *testing.T) {
+	cfg := NewConfig()
+	cfg.Servers["jiraMcp"] = &ServerConfig{Command: "npx", Aliases: []string{"tickets"}}
+	cfg.Servers["myServer"] = &ServerConfig{Command: "a", OriginalName: "my-server"}
+	cfg.Servers["myServer2"] = &ServerConfig{Command: "b", OriginalName: "my_server"}
+
+	tests := map[string]string{
+		"jira-mcp": "jiraMcp",
+		"JIRA_MCP": "jiraMcp",
+		"JiraMcp":  "jiraMcp",
+		"Tickets":  "jiraMcp",
+	}
+	for name, want := range tests {
+		if got, ok := cfg.ResolveServer(name); !ok || got != want {
+			t.Errorf("ResolveServer(%s) = %s, %v; want %s", name, got, ok, want)
+		}
+	}
+
+	// "MyServer" folds to both myServer and my_server: ambiguous
+	if key, ok := cfg.ResolveServer("MyServer"); ok {
+		t.Errorf("ambiguous name should not resolve, got %s", key)
+	}
+}
+
+func TestServerNotFoundSuggestsNearestName(t *testing.T) {
+	cfg := NewConfig()
+	cfg.Servers["jira"] = &ServerConfig{Command: "npx"}
+	cfg.Servers["github"] = &ServerConfig{Command: "npx", Aliases: []string{"gh"}}
+
+	err := cfg.ServerNotFound("githb")
+	if err.Suggestion != "github" {
+		t.Errorf("Suggestion = %q, want github", err.Suggestion)
+	}
+	if err.Error() != "server 'githb' not found. Did you mean 'github'?" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+
+	err = cfg.ServerNotFound("outline")
+	if err.Suggestion != "" {
+		t.Errorf("unrelated name should have no suggestion, got %q", err.Suggestion)
+	}
+	if err.Error() != "server 'outline' not found. Available servers: github, jira" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+}
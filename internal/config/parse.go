@@ -0,0 +1,169 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ParseAnyMCPConfig attempts to parse various MCP config formats intelligently.
+// Handles many variations including non-standard keys.
+// Returns servers map, detected format name, and error.
+func ParseAnyMCPConfig(input string) (map[string]*ServerConfig, string, error) {
+	input = strings.TrimSpace(input)
+
+	// Parse as generic JSON first
+	var raw map[string]interface{}
+	if err := json.Unmarshal([]byte(input), &raw); err != nil {
+		return nil, "", fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	// Try to find servers in various wrapper keys
+	wrapperKeys := []string{
+		"mcpServers", "mcp_servers", "MCP_SERVERS", "MCPServers",
+		"mcp", "MCP",
+		"servers", "Servers", "SERVERS",
+		"tools", "Tools", "TOOLS",
+		"context_servers", // Zed format
+	}
+
+	for _, key := range wrapperKeys {
+		if wrapped, ok := raw[key]; ok {
+			if serversMap, ok := wrapped.(map[string]interface{}); ok {
+				servers := parseServersMap(serversMap)
+				if len(servers) > 0 {
+					return servers, fmt.Sprintf("Wrapped (%s)", key), nil
+				}
+			}
+		}
+	}
+
+	// Try to parse as direct servers map: {"serverName": {...}, ...}
+	servers := parseServersMap(raw)
+	if len(servers) > 0 {
+		return servers, "Direct server map", nil
+	}
+
+	// Try single server object: {"command": "...", ...}
+	if server := parseSingleServer(raw); server != nil {
+		// Callers name the single server; "server" is a placeholder
+		return map[string]*ServerConfig{"server": server}, "Single server object", nil
+	}
+
+	return nil, "", fmt.Errorf("could not find valid MCP server configuration")
+}
+
+// parseServersMap parses a map of server name -> server config.
+func parseServersMap(raw map[string]interface{}) map[string]*ServerConfig {
+	result := make(map[string]*ServerConfig)
+
+	for name, val := range raw {
+		if serverMap, ok := val.(map[string]interface{}); ok {
+			if server := parseSingleServer(serverMap); server != nil {
+				result[name] = server
+			}
+		}
+	}
+
+	return result
+}
+
+// parseSingleServer attempts to parse a single server config from a map.
+// Handles many key variations:
+//   - command: command, cmd, exec, executable, run, bin, binary
+//   - args: args, arguments, argv, params, parameters, options
+//   - env: env, environment, envVars, env_vars, envvars
+//   - remote servers: url, serverUrl, endpoint; headers; type, transport
+func parseSingleServer(raw map[string]interface{}) *ServerConfig {
+	// Find command or, for remote servers, URL (one is required)
+	command := findStringKey(raw,
+		"command", "cmd", "exec", "executable", "run", "bin", "binary",
+		"Command", "CMD", "Cmd")
+	url := findStringKey(raw, "url", "serverUrl", "server_url", "endpoint", "URL", "Url")
+	if command == "" && url == "" {
+		return nil
+	}
+
+	// Find args
+	args := findStringArrayKey(raw,
+		"args", "arguments", "argv", "params", "parameters", "options",
+		"Args", "Arguments", "ARGS")
+
+	// Find env
+	env := findStringMapKey(raw,
+		"env", "environment", "envVars", "env_vars", "envvars",
+		"Env", "Environment", "ENV")
+
+	// Find working directory
+	cwd := findStringKey(raw,
+		"cwd", "workingDirectory", "working_directory", "workdir", "dir",
+		"Cwd", "WorkingDirectory")
+
+	server := &ServerConfig{
+		Command: command,
+		Args:    args,
+		Env:     NormalizeEnvVars(env),
+		Cwd:     cwd,
+		Type:    findStringKey(raw, "type", "transport", "Type"),
+		URL:     url,
+		Headers: findStringMapKey(raw, "headers", "http_headers", "httpHeaders", "Headers"),
+	}
+	if shell, ok := raw["shell"].(bool); ok {
+		server.Shell = shell
+	}
+	// A command that does not split is kept as written
+	server.NormalizeCommand()
+	return server
+}
+
+// findStringKey looks for a string value under any of the given keys.
+func findStringKey(m map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		if val, ok := m[key]; ok {
+			if s, ok := val.(string); ok && s != "" {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+// findStringArrayKey looks for a string array under any of the given keys.
+func findStringArrayKey(m map[string]interface{}, keys ...string) []string {
+	for _, key := range keys {
+		if val, ok := m[key]; ok {
+			if arr, ok := val.([]interface{}); ok {
+				result := make([]string, 0, len(arr))
+				for _, item := range arr {
+					if s, ok := item.(string); ok {
+						result = append(result, s)
+					}
+				}
+				if len(result) > 0 {
+					return result
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// findStringMapKey looks for a string map under any of the given keys.
+func findStringMapKey(m map[string]interface{}, keys ...string) map[string]string {
+	for _, key := range keys {
+		if val, ok := m[key]; ok {
+			if obj, ok := val.(map[string]interface{}); ok {
+				result := make(map[string]string)
+				for k, v := range obj {
+					if s, ok := v.(string); ok {
+						result[k] = s
+					}
+				}
+				if len(result) > 0 {
+					return result
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParseAnyMCPConfigRemote(t *testing.T) {
+	servers, _, err := ParseAnyMCPConfig(`{"mcpServers": {
+		"linear": {"type": "sse", "url": "https://mcp.linear.app/sse?workspace=acme", "headers": {"Authorization": "Bearer lin_123456"}},
+		"jira": {"command": "npx", "args": ["-y", "@lvmk/jira-mcp"]}
+	}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	linear := servers["linear"]
+	if linear == nil || !linear.IsRemote() || linear.Type != "sse" || linear.URL != "https://mcp.linear.app/sse?workspace=acme" {
+		t.Fatalf("remote server = %+v", linear)
+	}
+	if linear.Headers["Authorization"] != "Bearer lin_123456" {
+		t.Errorf("headers = %v", linear.Headers)
+	}
+	if err := ValidateServer("linear", linear); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if servers["jira"].IsRemote() {
+		t.Error("stdio server reported as remote")
+	}
+
+	// Round-trips through the config file
+	data, _ := json.Marshal(linear)
+	var saved ServerConfig
+	if err := json.Unmarshal(data, &saved); err != nil || saved.URL != linear.URL || saved.Type != "sse" || saved.Headers["Authorization"] == "" {
+		t.Errorf("round trip = %s", data)
+	}
+}
+
+func TestValidateServerRemote(t *testing.T) {
+	for _, server := range []*ServerConfig{
+		{Type: "http"},
+		{URL: "ftp://mcp.example.com"},
+		{URL: "mcp.example.com/mcp"},
+	} {
+		if err := ValidateServer("remote", server); err == nil || !strings.Contains(err.Error(), "url") {
+			t.Errorf("ValidateServer(%+v) = %v, want url error", server, err)
+		}
+	}
+}
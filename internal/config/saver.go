@@ -23,7 +23,11 @@ func Save(cfg *Config, path string) error {
 		fmt.Fprintf(os.Stderr, "Warning: failed to create backup: %v\n", err)
 	}
 
-	// 2. Marshal JSON
+	// 2. Encrypt env values if configured, then marshal JSON
+	cfg, err := encryptSecrets(cfg)
+	if err != nil {
+		return err
+	}
 	data, err := json.MarshalIndent(cfg, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
@@ -42,6 +46,22 @@ func Save(cfg *Config, path string) error {
 	return atomicWrite(path, data)
 }
 
+// SaveRaw validates hand-edited config file contents and writes them
+// through Save, with the same backup and atomic write. Encrypted env
+// values are kept as they are; new plaintext values are encrypted when
+// settings.secrets.encrypt is on.
+func SaveRaw(data []byte, path string) error {
+	if err := invalidConfig(path, ValidateData(data)); err != nil {
+		return err
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return fmt.Errorf("failed to parse config: %w", err)
+	}
+	return Save(&cfg, path)
+}
+
 func backupConfig(path string) error {
 	// Read existing file
 	data, err := os.ReadFile(path)
@@ -70,7 +90,7 @@ func validateJSON(data []byte) error {
 
 	// Validate each server config
 	for name, srv := range cfg.Servers {
-		if srv.Command == "" {
+		if srv.Command == "" && !srv.IsContainer() && !srv.IsRemote() {
 			return fmt.Errorf("server %s: empty command field", name)
 		}
 	}
@@ -98,6 +118,10 @@ func atomicWrite(path string, data []byte) error {
 func checkWritePermission(path string) error {
 	dir := filepath.Dir(path)
 
+	// The config directory does not exist yet on first run; failures to
+	// create it are reported by the writability check below
+	os.MkdirAll(dir, 0755)
+
 	// Check if directory exists and is writable
 	if err := checkDirectoryWritable(dir); err != nil {
 		return &PermissionError{
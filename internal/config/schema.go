@@ -0,0 +1,233 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// schemaDialect is the JSON Schema version the generated schema declares.
+const schemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// schemaNode is the subset of JSON Schema generated from the config
+// structs: types, object properties, array items and map values.
+type schemaNode struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Type                 schemaType             `json:"type,omitempty"`
+	Properties           map[string]*schemaNode `json:"properties,omitempty"`
+	Items                *schemaNode            `json:"items,omitempty"`
+	AdditionalProperties *schemaNode            `json:"additionalProperties,omitempty"`
+}
+
+// schemaType lists the JSON types a value may have. It marshals as a
+// single string when there is only one.
+type schemaType []string
+
+func (t schemaType) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+// configSchema is generated once from the Config struct.
+var configSchema = func() *schemaNode {
+	node := schemaFor(reflect.TypeOf(Config{}))
+	node.Schema = schemaDialect
+	node.Title = "tool-hub-mcp configuration"
+	return node
+}()
+
+// Schema returns the JSON Schema of the config file, generated from the
+// Config struct and its json tags.
+func Schema() ([]byte, error) {
+	return json.MarshalIndent(configSchema, "", "  ")
+}
+
+// schemaFor builds the schema of a Go type. Pointers, maps, slices and
+// interfaces may also be null, as encoding/json accepts null for them.
+func schemaFor(t reflect.Type) *schemaNode {
+	nullable := false
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+		nullable = true
+	}
+
+	node := &schemaNode{}
+	switch t.Kind() {
+	case reflect.String:
+		node.Type = schemaType{"string"}
+	case reflect.Bool:
+		node.Type = schemaType{"boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		node.Type = schemaType{"integer"}
+	case reflect.Float32, reflect.Float64:
+		node.Type = schemaType{"number"}
+	case reflect.Slice, reflect.Array:
+		node.Type = schemaType{"array"}
+		node.Items = schemaFor(t.Elem())
+		nullable = nullable || t.Kind() == reflect.Slice
+	case reflect.Map:
+		node.Type = schemaType{"object"}
+		node.AdditionalProperties = schemaFor(t.Elem())
+		nullable = true
+	case reflect.Struct:
+		node.Type = schemaType{"object"}
+		node.Properties = make(map[string]*schemaNode)
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name := jsonFieldName(field)
+			if name == "" {
+				continue
+			}
+			node.Properties[name] = schemaFor(field.Type)
+		}
+	default:
+		// interface{} and other kinds accept any value
+		return node
+	}
+
+	if nullable {
+		node.Type = append(node.Type, "null")
+	}
+	return node
+}
+
+// jsonFieldName returns the JSON key of an exported struct field, or ""
+// for fields encoding/json skips.
+func jsonFieldName(field reflect.StructField) string {
+	if !field.IsExported() {
+		return ""
+	}
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name
+	}
+	return field.Name
+}
+
+// validateSchema checks config file contents against the schema. It
+// returns a JSON syntax error as is, or one error per mismatched value
+// naming its path ("servers.jira.args[1] must be a string").
+func validateSchema(data []byte) ([]error, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	var problems []error
+	configSchema.validate(value, "", &problems)
+	return problems, nil
+}
+
+// validate appends a problem for every value that does not match n.
+func (n *schemaNode) validate(value interface{}, path string, problems *[]error) {
+	if len(n.Type) == 0 {
+		return
+	}
+	if !n.accepts(value) {
+		*problems = append(*problems, fmt.Errorf("%s must be %s", displayPath(path), n.describe()))
+		return
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			child := n.AdditionalProperties
+			if n.Properties != nil {
+				child = n.Properties[key]
+			}
+			if child != nil {
+				child.validate(v[key], joinPath(path, key), problems)
+			}
+		}
+	case []interface{}:
+		if n.Items != nil {
+			for i, item := range v {
+				n.Items.validate(item, fmt.Sprintf("%s[%d]", path, i), problems)
+			}
+		}
+	}
+}
+
+// accepts reports whether value has one of n's types.
+func (n *schemaNode) accepts(value interface{}) bool {
+	for _, typ := range n.Type {
+		switch v := value.(type) {
+		case nil:
+			if typ == "null" {
+				return true
+			}
+		case string:
+			if typ == "string" {
+				return true
+			}
+		case bool:
+			if typ == "boolean" {
+				return true
+			}
+		case json.Number:
+			if typ == "number" {
+				return true
+			}
+			if _, err := strconv.ParseInt(v.String(), 10, 64); err == nil && typ == "integer" {
+				return true
+			}
+		case []interface{}:
+			if typ == "array" {
+				return true
+			}
+		case map[string]interface{}:
+			if typ == "object" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// describe names the expected type for error messages.
+func (n *schemaNode) describe() string {
+	var names []string
+	for _, typ := range n.Type {
+		switch typ {
+		case "null":
+			continue
+		case "object", "array", "integer":
+			names = append(names, "an "+typ)
+		default:
+			names = append(names, "a "+typ)
+		}
+	}
+	return strings.Join(names, " or ")
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func displayPath(path string) string {
+	if path == "" {
+		return "config"
+	}
+	return path
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSchemaFromStructs(t *testing.T) {
+	data, err := Schema()
+	if err != nil {
+		t.Fatalf("Schema() failed: %v", err)
+	}
+
+	var schema map[string]interface{}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+	if schema["$schema"] != schemaDialect {
+		t.Errorf("$schema = %v", schema["$schema"])
+	}
+
+	servers := schema["properties"].(map[string]interface{})["servers"].(map[string]interface{})
+	server := servers["additionalProperties"].(map[string]interface{})
+	props := server["properties"].(map[string]interface{})
+	if args := props["args"].(map[string]interface{}); args["items"].(map[string]interface{})["type"] != "string" {
+		t.Errorf("args items should be strings: %v", args)
+	}
+	if props["command"].(map[string]interface{})["type"] != "string" {
+		t.Errorf("command should be a string: %v", props["command"])
+	}
+	if _, ok := props["rateLimit"]; !ok {
+		t.Error("nested structs should be included")
+	}
+}
+
+func TestValidateSchemaPaths(t *testing.T) {
+	data := `{
+		"servers": {
+			"jira": {"command": "npx", "args": ["-y", 42], "env": {"TOKEN": true}, "preload": "yes"},
+			"github": {"command": "npx", "rateLimit": {"requests": 1.5, "period": "1h"}}
+		},
+		"settings": {"timeoutSeconds": "30", "learning": null, "unknownKey": 1}
+	}`
+
+	problems, err := validateSchema([]byte(data))
+	if err != nil {
+		t.Fatalf("validateSchema() failed: %v", err)
+	}
+
+	want := []string{
+		"servers.github.rateLimit.requests must be an integer",
+		"servers.jira.args[1] must be a string",
+		"servers.jira.env.TOKEN must be a string",
+		"servers.jira.preload must be a boolean",
+		"settings.timeoutSeconds must be an integer",
+	}
+	if len(problems) != len(want) {
+		t.Fatalf("got %d problems, want %d: %v", len(problems), len(want), problems)
+	}
+	for i, problem := range problems {
+		if problem.Error() != want[i] {
+			t.Errorf("problem %d = %q, want %q", i, problem.Error(), want[i])
+		}
+	}
+
+	if problems, _ := validateSchema([]byte(`[]`)); len(problems) != 1 || problems[0].Error() != "config must be an object" {
+		t.Errorf("expected root type error, got %v", problems)
+	}
+}
+
+func TestLoadFromReportsSchemaErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	os.WriteFile(path, []byte(`{"servers": {"jira": {"command": "npx", "args": [1]}}}`), 0644)
+
+	_, err := LoadFrom(path)
+	if _, ok := err.(*InvalidConfigError); !ok {
+		t.Fatalf("expected InvalidConfigError, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "servers.jira.args[0] must be a string") {
+		t.Errorf("error should name the path: %v", err)
+	}
+}
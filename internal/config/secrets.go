@@ -0,0 +1,308 @@
+package config
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/paths"
+)
+
+// Key sources accepted in settings.secrets.keySource.
+const (
+	KeySourceFile     = "file"
+	KeySourceKeychain = "keychain"
+)
+
+// encryptedPrefix marks an env value encrypted with AES-256-GCM; the rest
+// is base64(nonce || ciphertext).
+const encryptedPrefix = "enc:v1:"
+
+// keychainService and keychainAccount name the OS keychain entry holding
+// the base64 encryption key.
+const (
+	keychainService = "tool-hub-mcp"
+	keychainAccount = "env-encryption-key"
+)
+
+// SecretKeyError is returned when encrypted env values cannot be read or
+// written because the encryption key is unavailable.
+type SecretKeyError struct {
+	Source string
+	Err    error
+}
+
+func (e *SecretKeyError) Error() string {
+	return fmt.Sprintf("encryption key unavailable (%s): %v", e.Source, e.Err)
+}
+
+func (e *SecretKeyError) Unwrap() error {
+	return e.Err
+}
+
+// EncryptEnv reports whether env values are encrypted when saved.
+func (s *Settings) EncryptEnv() bool {
+	return s != nil && s.Secrets != nil && s.Secrets.Encrypt
+}
+
+// IsEncrypted reports whether a config value is an encrypted secret.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, encryptedPrefix)
+}
+
+// encryptSecrets returns the config to write to disk: cfg itself when
+// encryption is off, otherwise a copy whose env values are encrypted.
+// cfg is never modified, so callers keep using plaintext values.
+func encryptSecrets(cfg *Config) (*Config, error) {
+	if !cfg.Settings.EncryptEnv() {
+		return cfg, nil
+	}
+
+	key, err := loadSecretKey(cfg.Settings.Secrets, true)
+	if err != nil {
+		return nil, err
+	}
+
+	out := *cfg
+	out.Servers = make(map[string]*ServerConfig, len(cfg.Servers))
+	for name, server := range cfg.Servers {
+		if server == nil || len(server.Env) == 0 {
+			out.Servers[name] = server
+			continue
+		}
+		copied := *server
+		copied.Env = make(map[string]string, len(server.Env))
+		for k, v := range server.Env {
+			if v != "" && !IsEncrypted(v) {
+				if v, err = encryptValue(key, v); err != nil {
+					return nil, fmt.Errorf("failed to encrypt %s env %s: %w", name, k, err)
+				}
+			}
+			copied.Env[k] = v
+		}
+		out.Servers[name] = &copied
+	}
+	return &out, nil
+}
+
+// decryptSecrets decrypts encrypted env values in place after loading.
+// The key is only looked up when an encrypted value is present.
+func decryptSecrets(cfg *Config) error {
+	var key []byte
+	for name, server := range cfg.Servers {
+		if server == nil {
+			continue
+		}
+		for k, v := range server.Env {
+			if !IsEncrypted(v) {
+				continue
+			}
+			if key == nil {
+				var settings *SecretSettings
+				if cfg.Settings != nil {
+					settings = cfg.Settings.Secrets
+				}
+				var err error
+				if key, err = loadSecretKey(settings, false); err != nil {
+					return err
+				}
+			}
+			plain, err := decryptValue(key, v)
+			if err != nil {
+				return fmt.Errorf("failed to decrypt %s env %s: %w", name, k, err)
+			}
+			server.Env[k] = plain
+		}
+	}
+	return nil
+}
+
+// encryptValue seals a value with AES-256-GCM under a fresh nonce.
+func encryptValue(key []byte, plain string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(plain), nil)
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decryptValue opens a value produced by encryptValue.
+func decryptValue(key []byte, value string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encryptedPrefix))
+	if err != nil {
+		return "", fmt.Errorf("invalid encrypted value: %w", err)
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", errors.New("invalid encrypted value: too short")
+	}
+	plain, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", errors.New("wrong key or corrupted value")
+	}
+	return string(plain), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// loadSecretKey returns the 32-byte encryption key from the configured
+// source, generating and storing a new one when create is set and none
+// exists yet.
+func loadSecretKey(settings *SecretSettings, create bool) ([]byte, error) {
+	source := KeySourceFile
+	if settings != nil && settings.KeySource != "" {
+		source = strings.ToLower(settings.KeySource)
+	}
+
+	var (
+		encoded string
+		err     error
+		store   func(string) error
+	)
+	switch source {
+	case KeySourceFile:
+		path, pathErr := secretKeyPath(settings)
+		if pathErr != nil {
+			return nil, &SecretKeyError{Source: source, Err: pathErr}
+		}
+		var data []byte
+		data, err = os.ReadFile(path)
+		encoded = string(bytes.TrimSpace(data))
+		if os.IsNotExist(err) {
+			err = errKeyNotFound
+		}
+		store = func(k string) error {
+			if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+				return err
+			}
+			return os.WriteFile(path, []byte(k+"\n"), 0600)
+		}
+	case KeySourceKeychain:
+		encoded, err = keychainGet()
+		store = keychainSet
+	default:
+		return nil, &SecretKeyError{Source: source, Err: fmt.Errorf("unknown key source, use %q or %q", KeySourceFile, KeySourceKeychain)}
+	}
+
+	if errors.Is(err, errKeyNotFound) && create {
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, &SecretKeyError{Source: source, Err: err}
+		}
+		if err := store(base64.StdEncoding.EncodeToString(key)); err != nil {
+			return nil, &SecretKeyError{Source: source, Err: err}
+		}
+		return key, nil
+	}
+	if err != nil {
+		return nil, &SecretKeyError{Source: source, Err: err}
+	}
+
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(key) != 32 {
+		return nil, &SecretKeyError{Source: source, Err: errors.New("key is not 32 base64-encoded bytes")}
+	}
+	return key, nil
+}
+
+// errKeyNotFound means no key has been stored yet.
+var errKeyNotFound = errors.New("no key found")
+
+// secretKeyPath returns settings.secrets.keyFile, or secret.key next to
+// the config file.
+func secretKeyPath(settings *SecretSettings) (string, error) {
+	if settings != nil && settings.KeyFile != "" {
+		return settings.KeyFile, nil
+	}
+	dir, err := paths.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "secret.key"), nil
+}
+
+// keychainGet reads the key from the macOS Keychain or the Secret Service
+// (via secret-tool) on Linux.
+func keychainGet() (string, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "find-generic-password", "-s", keychainService, "-a", keychainAccount, "-w")
+	case "linux":
+		cmd = exec.Command("secret-tool", "lookup", "service", keychainService, "account", keychainAccount)
+	default:
+		return "", fmt.Errorf("OS keychain is not supported on %s, use keySource %q", runtime.GOOS, KeySourceFile)
+	}
+
+	if cmd.Err != nil {
+		return "", cmd.Err
+	}
+
+	out, err := cmd.Output()
+	value := strings.TrimSpace(string(out))
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil && value != "":
+		return value, nil
+	case err == nil, errors.As(err, &exitErr) && isKeychainNotFound(exitErr.ExitCode()):
+		return "", errKeyNotFound
+	default:
+		// e.g. a locked keychain: never treat it as missing, or a new key
+		// would replace the one existing values were encrypted with
+		return "", fmt.Errorf("failed to read key from keychain: %w", err)
+	}
+}
+
+// isKeychainNotFound reports whether a keychain lookup exit code means
+// the entry does not exist (44 for security, 1 for secret-tool).
+func isKeychainNotFound(code int) bool {
+	if runtime.GOOS == "darwin" {
+		return code == 44
+	}
+	return code == 1
+}
+
+// keychainSet stores the key in the OS keychain.
+func keychainSet(value string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		// A trailing -w without a value makes security prompt for the
+		// key, and its retype, so it never appears in the process list
+		cmd = exec.Command("security", "add-generic-password", "-s", keychainService, "-a", keychainAccount, "-w")
+		cmd.Stdin = strings.NewReader(value + "\n" + value + "\n")
+		detachTerminal(cmd)
+	case "linux":
+		cmd = exec.Command("secret-tool", "store", "--label", "tool-hub-mcp env encryption key", "service", keychainService, "account", keychainAccount)
+		cmd.Stdin = strings.NewReader(value)
+	default:
+		return fmt.Errorf("OS keychain is not supported on %s", runtime.GOOS)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to store key in keychain: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEncryptedEnvRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.json")
+	keyFile := filepath.Join(dir, "secret.key")
+
+	cfg := NewConfig()
+	cfg.Settings.Secrets = &SecretSettings{Encrypt: true, KeyFile: keyFile}
+	cfg.Servers["jira"] = &ServerConfig{Command: "npx", Env: map[string]string{"JIRA_TOKEN": "s3cr3t", "EMPTY": ""}}
+
+	if err := Save(cfg, configPath); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	// The in-memory config keeps plaintext values
+	if cfg.Servers["jira"].Env["JIRA_TOKEN"] != "s3cr3t" {
+		t.Error("Save() modified the caller's env values")
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "s3cr3t") || !strings.Contains(string(data), encryptedPrefix) {
+		t.Errorf("env value not encrypted on disk:\n%s", data)
+	}
+	if info, err := os.Stat(keyFile); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("expected key file with mode 0600, got %v, %v", info, err)
+	}
+
+	loaded, err := LoadFrom(configPath)
+	if err != nil {
+		t.Fatalf("LoadFrom() failed: %v", err)
+	}
+	if got := loaded.Servers["jira"].Env["JIRA_TOKEN"]; got != "s3cr3t" {
+		t.Errorf("decrypted value = %q, want s3cr3t", got)
+	}
+
+	// Saving again with encryption off writes plaintext
+	loaded.Settings.Secrets = nil
+	if err := Save(loaded, configPath); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+	if data, _ := os.ReadFile(configPath); !strings.Contains(string(data), "s3cr3t") {
+		t.Errorf("expected plaintext after disabling encryption:\n%s", data)
+	}
+}
+
+func TestEncryptedEnvWithoutKey(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.json")
+	keyFile := filepath.Join(dir, "secret.key")
+
+	cfg := NewConfig()
+	cfg.Settings.Secrets = &SecretSettings{Encrypt: true, KeyFile: keyFile}
+	cfg.Servers["jira"] = &ServerConfig{Command: "npx", Env: map[string]string{"JIRA_TOKEN": "s3cr3t"}}
+	if err := Save(cfg, configPath); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	if err := os.Remove(keyFile); err != nil {
+		t.Fatal(err)
+	}
+	_, err := LoadFrom(configPath)
+	var keyErr *SecretKeyError
+	if !errors.As(err, &keyErr) {
+		t.Fatalf("expected SecretKeyError without the key, got %v", err)
+	}
+
+	// A different key cannot decrypt the values
+	if _, err := loadSecretKey(cfg.Settings.Secrets, true); err != nil {
+		t.Fatalf("failed to create new key: %v", err)
+	}
+	if _, err := LoadFrom(configPath); err == nil || !strings.Contains(err.Error(), "wrong key") {
+		t.Errorf("expected wrong key error, got %v", err)
+	}
+}
@@ -22,6 +22,11 @@ import (
 //	      "command": "npx",
 //	      "args": ["-y", "@package/name"],
 //	      "env": {"KEY": "value"}
+//	    },
+//	    "remote-server": {
+//	      "type": "http",
+//	      "url": "https://mcp.example.com/mcp",
+//	      "headers": {"Authorization": "Bearer ..."}
 //	    }
 //	  }
 //	}
@@ -34,9 +39,12 @@ type claudeCodeConfig struct {
 
 // claudeServerConfig represents a single MCP server in Claude Code config.
 type claudeServerConfig struct {
+	Type    string            `json:"type"`
 	Command string            `json:"command"`
 	Args    []string          `json:"args"`
 	Env     map[string]string `json:"env"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers"`
 }
 
 // NewClaudeCodeSource creates a new Claude Code configuration source.
@@ -96,9 +104,12 @@ func (s *ClaudeCodeSource) parseFile(path string) (*SourceResult, error) {
 	servers := make(map[string]*config.ServerConfig)
 	for name, server := range cfg.MCPServers {
 		servers[name] = &config.ServerConfig{
+			Type:    server.Type,
 			Command: server.Command,
 			Args:    server.Args,
 			Env:     config.NormalizeEnvVars(server.Env),
+			URL:     server.URL,
+			Headers: server.Headers,
 			Source:  s.Name(),
 		}
 	}
@@ -0,0 +1,47 @@
+package sources
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// ClineSource reads MCP configurations from the Cline VS Code extension.
+//
+// Configuration location:
+//   - <VS Code user dir>/globalStorage/saoudrizwan.claude-dev/settings/cline_mcp_settings.json
+//
+// Format:
+//
+//	{
+//	  "mcpServers": {
+//	    "serverName": {
+//	      "command": "npx",
+//	      "args": ["-y", "@package/name"],
+//	      "env": {"KEY": "value"},
+//	      "disabled": false
+//	    }
+//	  }
+//	}
+type ClineSource struct{}
+
+// NewClineSource creates a new Cline configuration source.
+func NewClineSource() *ClineSource {
+	return &ClineSource{}
+}
+
+// Name returns the source identifier.
+func (s *ClineSource) Name() string {
+	return "cline"
+}
+
+// Scan searches for and parses Cline MCP configurations.
+func (s *ClineSource) Scan() (*SourceResult, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(vscodeUserDir(home), "globalStorage",
+		"saoudrizwan.claude-dev", "settings", "cline_mcp_settings.json")
+	return parseMCPServersFile(path, s.Name())
+}
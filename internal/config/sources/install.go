@@ -0,0 +1,310 @@
+package sources
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+)
+
+// HubEntryName is the server name tool-hub-mcp registers itself under
+// in client configurations.
+const HubEntryName = "tool-hub-mcp"
+
+// DisabledServersKey is the sibling key that parks direct servers in clients
+// without a per-server "enabled" switch. Clients ignore unknown keys, so this
+// acts like commenting the entries out while keeping them restorable.
+const DisabledServersKey = "toolHubDisabledServers"
+
+// ClientTarget describes where an AI client stores its MCP servers
+// and how tool-hub-mcp writes itself into that file.
+type ClientTarget struct {
+	// Client is the client identifier (e.g., "claude", "cursor").
+	Client string
+
+	// Path is the client configuration file.
+	Path string
+
+	// ServersKey is the key holding the server map. Nested sections are
+	// written with dots (e.g., "mcp.servers" in VS Code settings.json).
+	ServersKey string
+
+	// disableField is the per-entry switch used to disable a server:
+	// "enabled" (set to false) or "disabled" (set to true). When empty,
+	// entries are parked under DisabledServersKey instead.
+	disableField string
+
+	// entryType is written as the hub entry's "type", if set.
+	entryType string
+
+	// jsonc marks editor settings files, which hold comments and
+	// unrelated settings: only the server sections are rewritten and
+	// the rest of the file is kept byte-for-byte.
+	jsonc bool
+}
+
+// InstallOptions controls how Install modifies a client configuration.
+type InstallOptions struct {
+	// DisableServers lists direct server names (as written in the client
+	// config) to disable because tool-hub-mcp now aggregates them.
+	DisableServers []string
+}
+
+// InstallResult summarizes the changes Install made.
+type InstallResult struct {
+	// Path is the client configuration file that was written.
+	Path string
+
+	// BackupPath is the backup of the previous file, empty on first write.
+	BackupPath string
+
+	// Updated is true if a tool-hub-mcp entry already existed and was replaced.
+	Updated bool
+
+	// Disabled lists the direct servers that were disabled.
+	Disabled []string
+}
+
+// ClientNames returns the clients supported by install, sorted.
+func ClientNames() []string {
+	return []string{"claude", "cursor", "opencode", "windsurf"}
+}
+
+// ResolveClientTarget returns the configuration target for a client.
+func ResolveClientTarget(client string) (*ClientTarget, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	switch client {
+	case "claude":
+		return &ClientTarget{Client: client, Path: filepath.Join(home, ".claude.json"), ServersKey: "mcpServers"}, nil
+	case "cursor":
+		return &ClientTarget{Client: client, Path: filepath.Join(home, ".cursor", "mcp.json"), ServersKey: "mcpServers"}, nil
+	case "windsurf":
+		return &ClientTarget{Client: client, Path: filepath.Join(home, ".codeium", "windsurf", "mcp_config.json"), ServersKey: "mcpServers"}, nil
+	case "opencode":
+		// Reuse whichever OpenCode config already exists, same precedence as Scan
+		paths := []string{
+			filepath.Join(home, ".opencode.json"),
+			"opencode.json",
+			filepath.Join(home, ".config", "opencode", "opencode.json"),
+		}
+		path := paths[len(paths)-1]
+		for _, p := range paths {
+			if _, err := os.Stat(p); err == nil {
+				path = p
+				break
+			}
+		}
+		return &ClientTarget{Client: client, Path: path, ServersKey: "mcp", disableField: "enabled", entryType: "local"}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported client '%s' (supported: %s)", client, strings.Join(ClientNames(), ", "))
+}
+
+// TargetForSource returns the install target for a config file that was
+// imported by the named source, so it can be rewritten in place.
+func TargetForSource(sourceName, path string) (*ClientTarget, error) {
+	switch sourceName {
+	case "claude-code":
+		return &ClientTarget{Client: "claude", Path: path, ServersKey: "mcpServers"}, nil
+	case "opencode":
+		return &ClientTarget{Client: "opencode", Path: path, ServersKey: "mcp", disableField: "enabled", entryType: "local"}, nil
+	case "vscode":
+		key := "servers"
+		if filepath.Base(path) == "settings.json" {
+			key = "mcp.servers"
+		}
+		return &ClientTarget{Client: "vscode", Path: path, ServersKey: key, entryType: "stdio", jsonc: true}, nil
+	case "cline", "roo-code":
+		return &ClientTarget{Client: sourceName, Path: path, ServersKey: "mcpServers", disableField: "disabled"}, nil
+	case "zed":
+		return &ClientTarget{Client: "zed", Path: path, ServersKey: "context_servers", jsonc: true}, nil
+	}
+	return nil, fmt.Errorf("source '%s' does not support write-back", sourceName)
+}
+
+// ReadServers returns the direct servers currently registered in the client,
+// keyed by their original names. A missing file yields an empty map.
+func (t *ClientTarget) ReadServers() (map[string]*config.ServerConfig, error) {
+	doc, err := t.load()
+	if err != nil {
+		return nil, err
+	}
+
+	servers := make(map[string]*config.ServerConfig)
+	for name, raw := range serverMap(doc, t.ServersKey) {
+		entry, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		server := &config.ServerConfig{Source: t.Client}
+		if cmd, ok := entry["command"].(string); ok {
+			server.Command = cmd
+		}
+		if args, ok := entry["args"].([]interface{}); ok {
+			for _, arg := range args {
+				if s, ok := arg.(string); ok {
+					server.Args = append(server.Args, s)
+				}
+			}
+		}
+		servers[name] = server
+	}
+	return servers, nil
+}
+
+// Install registers `tool-hub-mcp serve` in the client configuration,
+// backing up the previous file. Unrelated keys are preserved.
+func Install(t *ClientTarget, opts InstallOptions) (*InstallResult, error) {
+	doc, err := t.load()
+	if err != nil {
+		return nil, err
+	}
+
+	servers := serverMap(doc, t.ServersKey)
+	result := &InstallResult{Path: t.Path}
+	_, result.Updated = servers[HubEntryName]
+	servers[HubEntryName] = t.hubEntry()
+
+	for _, name := range opts.DisableServers {
+		entry, ok := servers[name]
+		if !ok || name == HubEntryName {
+			continue
+		}
+		if t.disableField != "" {
+			if m, ok := entry.(map[string]interface{}); ok {
+				m[t.disableField] = t.disableField == "disabled"
+			}
+		} else {
+			parked := serverMap(doc, DisabledServersKey)
+			parked[name] = entry
+			delete(servers, name)
+		}
+		result.Disabled = append(result.Disabled, name)
+	}
+	sort.Strings(result.Disabled)
+
+	backupPath, err := t.write(doc)
+	if err != nil {
+		return nil, err
+	}
+	result.BackupPath = backupPath
+
+	return result, nil
+}
+
+// hubEntry returns the server entry that launches tool-hub-mcp.
+func (t *ClientTarget) hubEntry() map[string]interface{} {
+	entry := map[string]interface{}{
+		"command": "tool-hub-mcp",
+		"args":    []string{"serve"},
+	}
+	if t.entryType != "" {
+		entry["type"] = t.entryType
+	}
+	if t.disableField != "" {
+		entry[t.disableField] = t.disableField == "enabled"
+	}
+	return entry
+}
+
+// load reads the client config as a generic document.
+// Numbers are kept as json.Number so unrelated values round-trip exactly.
+func (t *ClientTarget) load() (map[string]interface{}, error) {
+	data, err := os.ReadFile(t.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]interface{}{}, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", t.Path, err)
+	}
+
+	doc := map[string]interface{}{}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return doc, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(stripJSONC(data)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", t.Path, err)
+	}
+	return doc, nil
+}
+
+// write backs up the existing file and atomically replaces it.
+func (t *ClientTarget) write(doc map[string]interface{}) (string, error) {
+	existing, readErr := os.ReadFile(t.Path)
+	data, err := t.encode(doc, existing)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal %s: %w", t.Path, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(t.Path), 0755); err != nil {
+		return "", err
+	}
+
+	mode := os.FileMode(0644)
+	backupPath := ""
+	if readErr == nil {
+		if info, err := os.Stat(t.Path); err == nil {
+			mode = info.Mode().Perm()
+		}
+		backupPath = t.Path + ".bak"
+		if err := os.WriteFile(backupPath, existing, mode); err != nil {
+			return "", fmt.Errorf("failed to back up %s: %w", t.Path, err)
+		}
+	}
+
+	tmpPath := t.Path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, mode); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmpPath, t.Path); err != nil {
+		return "", err
+	}
+	return backupPath, nil
+}
+
+// encode serializes doc. For JSONC targets with existing content, only
+// the server map and the parked servers are replaced in existing.
+func (t *ClientTarget) encode(doc map[string]interface{}, existing []byte) ([]byte, error) {
+	if !t.jsonc || len(bytes.TrimSpace(existing)) == 0 {
+		data, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	}
+
+	data, err := setJSONCMember(existing, t.ServersKey, serverMap(doc, t.ServersKey))
+	if err != nil {
+		return nil, err
+	}
+	if parked, ok := doc[DisabledServersKey]; ok {
+		data, err = setJSONCMember(data, DisabledServersKey, parked)
+	}
+	return data, err
+}
+
+// serverMap returns the map under a dotted key path, creating it if needed.
+func serverMap(doc map[string]interface{}, key string) map[string]interface{} {
+	current := doc
+	for _, part := range strings.Split(key, ".") {
+		next, ok := current[part].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			current[part] = next
+		}
+		current = next
+	}
+	return current
+}
@@ -0,0 +1,261 @@
+package sources
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveClientTarget(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	for _, client := range ClientNames() {
+		target, err := ResolveClientTarget(client)
+		if err != nil {
+			t.Fatalf("ResolveClientTarget(%s) failed: %v", client, err)
+		}
+		if target.Path == "" || target.ServersKey == "" {
+			t.Errorf("incomplete target for %s: %+v", client, target)
+		}
+	}
+
+	if _, err := ResolveClientTarget("notepad"); err == nil {
+		t.Error("expected error for unsupported client")
+	}
+}
+
+func TestTargetForSource(t *testing.T) {
+	for _, source := range GetAllSources() {
+		target, err := TargetForSource(source.Name(), "/tmp/config.json")
+		if err != nil {
+			t.Errorf("TargetForSource(%s) failed: %v", source.Name(), err)
+			continue
+		}
+		if target.Path != "/tmp/config.json" {
+			t.Errorf("path not kept for %s: %s", source.Name(), target.Path)
+		}
+	}
+
+	if _, err := TargetForSource("unknown", "/tmp/x.json"); err == nil {
+		t.Error("expected error for unknown source")
+	}
+}
+
+func TestInstallPreservesKeysAndDisablesServers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mcp.json")
+	original := `{
+  "numStartups": 12345678901234567,
+  "mcpServers": {
+    "jira": {"command": "npx", "args": ["-y", "jira-mcp"]},
+    "local": {"command": "./local"}
+  }
+}`
+	if err := os.WriteFile(path, []byte(original), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	target := &ClientTarget{Client: "cursor", Path: path, ServersKey: "mcpServers"}
+	result, err := Install(target, InstallOptions{DisableServers: []string{"jira"}})
+	if err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+	if result.Updated {
+		t.Error("expected fresh install")
+	}
+	if result.BackupPath == "" {
+		t.Error("expected backup of existing config")
+	}
+	if len(result.Disabled) != 1 || result.Disabled[0] != "jira" {
+		t.Errorf("unexpected disabled list: %v", result.Disabled)
+	}
+
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "12345678901234567") {
+		t.Error("large number was not preserved exactly")
+	}
+
+	var doc struct {
+		Servers  map[string]interface{} `json:"mcpServers"`
+		Disabled map[string]interface{} `json:"toolHubDisabledServers"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid JSON written: %v", err)
+	}
+	if _, ok := doc.Servers[HubEntryName]; !ok {
+		t.Error("hub entry not registered")
+	}
+	if _, ok := doc.Servers["jira"]; ok {
+		t.Error("jira should have been moved out of mcpServers")
+	}
+	if _, ok := doc.Disabled["jira"]; !ok {
+		t.Error("jira should be parked under the disabled key")
+	}
+	if _, ok := doc.Servers["local"]; !ok {
+		t.Error("unrelated server was removed")
+	}
+
+	info, _ := os.Stat(path)
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("file mode not preserved: %v", info.Mode().Perm())
+	}
+
+	// Second install updates in place
+	result, err = Install(target, InstallOptions{})
+	if err != nil {
+		t.Fatalf("second Install failed: %v", err)
+	}
+	if !result.Updated {
+		t.Error("expected existing hub entry to be updated")
+	}
+}
+
+func TestInstallOpenCodeUsesEnabledFlag(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "opencode.json")
+	original := `{"mcp": {"jira": {"type": "local", "command": "npx", "enabled": true}}}`
+	if err := os.WriteFile(path, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	target := &ClientTarget{Client: "opencode", Path: path, ServersKey: "mcp", disableField: "enabled", entryType: "local"}
+	if _, err := Install(target, InstallOptions{DisableServers: []string{"jira"}}); err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+
+	servers, err := target.ReadServers()
+	if err != nil {
+		t.Fatalf("ReadServers failed: %v", err)
+	}
+	if servers[HubEntryName] == nil || servers[HubEntryName].Command != "tool-hub-mcp" {
+		t.Errorf("hub entry missing: %+v", servers[HubEntryName])
+	}
+
+	data, _ := os.ReadFile(path)
+	var doc struct {
+		MCP map[string]struct {
+			Enabled bool `json:"enabled"`
+		} `json:"mcp"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.MCP["jira"].Enabled {
+		t.Error("jira should be disabled in place")
+	}
+	if !doc.MCP[HubEntryName].Enabled {
+		t.Error("hub entry should be enabled")
+	}
+}
+
+func TestInstallNestedKeyAndDisabledField(t *testing.T) {
+	dir := t.TempDir()
+
+	settings := filepath.Join(dir, "settings.json")
+	writeFile(t, settings, `{
+  // editor settings
+  "editor.fontSize": 14,
+  "mcp": {"servers": {"github": {"type": "stdio", "command": "npx"}}},
+}`)
+	target, _ := TargetForSource("vscode", settings)
+	if _, err := Install(target, InstallOptions{DisableServers: []string{"github"}}); err != nil {
+		t.Fatalf("Install into VS Code settings failed: %v", err)
+	}
+	result, err := NewVSCodeSource().parseFile(settings, true)
+	if err != nil || result == nil {
+		t.Fatalf("parseFile after install = %v, %v", result, err)
+	}
+	if len(result.Servers) != 1 || result.Servers[HubEntryName] == nil {
+		t.Errorf("expected only the hub entry to remain: %+v", result.Servers)
+	}
+
+	cline := filepath.Join(dir, "cline_mcp_settings.json")
+	writeFile(t, cline, `{"mcpServers": {"jira": {"command": "npx"}}}`)
+	target, _ = TargetForSource("cline", cline)
+	if _, err := Install(target, InstallOptions{DisableServers: []string{"jira"}}); err != nil {
+		t.Fatalf("Install into Cline failed: %v", err)
+	}
+	result, err = parseMCPServersFile(cline, "cline")
+	if err != nil || result == nil {
+		t.Fatalf("parseMCPServersFile after install = %v, %v", result, err)
+	}
+	if result.Servers["jira"] != nil || result.Servers[HubEntryName] == nil {
+		t.Errorf("expected jira disabled and hub enabled: %+v", result.Servers)
+	}
+}
+
+func TestInstallCreatesMissingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "mcp.json")
+	target := &ClientTarget{Client: "windsurf", Path: path, ServersKey: "mcpServers"}
+
+	result, err := Install(target, InstallOptions{})
+	if err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+	if result.BackupPath != "" {
+		t.Error("no backup expected for a new file")
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("config not created: %v", err)
+	}
+}
+
+func TestInstallKeepsJSONCSettingsOutsideServers(t *testing.T) {
+	dir := t.TempDir()
+
+	head := `{
+  // Editor appearance
+  "editor.fontSize": 14, /* keep this */
+  "files.exclude": {"**/.git": true},
+  "mcp": {
+    // Servers tool-hub-mcp takes over
+    "servers": `
+	tail := `
+  },
+  // Trailing settings
+  "workbench.colorTheme": "Default Dark+",
+}
+`
+	settings := filepath.Join(dir, "settings.json")
+	writeFile(t, settings, head+`{"github": {"type": "stdio", "command": "npx"}}`+tail)
+
+	target, _ := TargetForSource("vscode", settings)
+	if _, err := Install(target, InstallOptions{DisableServers: []string{"github"}}); err != nil {
+		t.Fatalf("Install into VS Code settings failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(settings)
+	got := string(data)
+	if !strings.HasPrefix(got, head) {
+		t.Errorf("settings before the servers section changed:\n%s", got)
+	}
+	// The parked servers are added after the last member, ahead of its trailing comma
+	if !strings.Contains(got, `"workbench.colorTheme": "Default Dark+",
+  "`+DisabledServersKey+`": {`) || !strings.HasSuffix(got, "},\n}\n") {
+		t.Errorf("settings after the servers section changed:\n%s", got)
+	}
+
+	result, err := NewVSCodeSource().parseFile(settings, true)
+	if err != nil || result == nil {
+		t.Fatalf("parseFile after install = %v, %v", result, err)
+	}
+	if len(result.Servers) != 1 || result.Servers[HubEntryName] == nil {
+		t.Errorf("expected only the hub entry to remain: %+v", result.Servers)
+	}
+
+	zed := filepath.Join(dir, "zed.json")
+	writeFile(t, zed, "{\n\t// Zed settings\n\t\"theme\": \"One Dark\"\n}\n")
+	target, _ = TargetForSource("zed", zed)
+	if _, err := Install(target, InstallOptions{}); err != nil {
+		t.Fatalf("Install into Zed settings failed: %v", err)
+	}
+	data, _ = os.ReadFile(zed)
+	if want := "{\n\t// Zed settings\n\t\"theme\": \"One Dark\",\n\t\"context_servers\": {\n\t\t\"" + HubEntryName + "\": {"; !strings.HasPrefix(string(data), want) {
+		t.Errorf("expected context_servers appended with tabs:\n%s", data)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(stripJSONC(data), &doc); err != nil {
+		t.Errorf("Zed settings no longer parse: %v\n%s", err, data)
+	}
+}
@@ -0,0 +1,316 @@
+package sources
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// stripJSONC converts JSON-with-comments (as used by VS Code and Zed
+// settings files) into plain JSON by removing // and /* */ comments and
+// trailing commas. String contents are left untouched.
+func stripJSONC(data []byte) []byte {
+	return stripTrailingCommas(stripComments(data))
+}
+
+// stripComments removes // line comments and /* */ block comments.
+func stripComments(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	inString := false
+
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+
+		if inString {
+			out = append(out, c)
+			if c == '\\' && i+1 < len(data) {
+				i++
+				out = append(out, data[i])
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+
+		switch {
+		case c == '"':
+			inString = true
+			out = append(out, c)
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			// Skip to end of line, keeping the newline
+			for i+1 < len(data) && data[i+1] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			i += 2
+			for i+1 < len(data) && !(data[i] == '*' && data[i+1] == '/') {
+				i++
+			}
+			i++
+		default:
+			out = append(out, c)
+		}
+	}
+
+	return out
+}
+
+// stripTrailingCommas removes commas directly followed by '}' or ']'.
+func stripTrailingCommas(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	inString := false
+
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+
+		if inString {
+			out = append(out, c)
+			if c == '\\' && i+1 < len(data) {
+				i++
+				out = append(out, data[i])
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+
+		if c == '"' {
+			inString = true
+		} else if c == ',' {
+			j := i + 1
+			for j < len(data) && isJSONSpace(data[j]) {
+				j++
+			}
+			if j < len(data) && (data[j] == '}' || data[j] == ']') {
+				continue
+			}
+		}
+		out = append(out, c)
+	}
+
+	return out
+}
+
+// isJSONSpace reports whether c is JSON insignificant whitespace.
+func isJSONSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// setJSONCMember sets the member at a dotted key path of a JSONC
+// document to value, adding missing members. Only the bytes of that
+// member change; comments and formatting elsewhere are kept as written.
+func setJSONCMember(data []byte, key string, value interface{}) ([]byte, error) {
+	obj := skipJSONCSpace(data, 0)
+	if obj >= len(data) || data[obj] != '{' {
+		return nil, fmt.Errorf("top-level value is not an object")
+	}
+
+	path := strings.Split(key, ".")
+	for depth, name := range path {
+		members, closing, err := jsoncMembers(data, obj)
+		if err != nil {
+			return nil, err
+		}
+
+		// The last duplicate wins, as with encoding/json
+		var found *jsoncMember
+		for i := range members {
+			if members[i].name == name {
+				found = &members[i]
+			}
+		}
+
+		// Nest value under the rest of the path
+		v := value
+		for j := len(path) - 1; j > depth; j-- {
+			v = map[string]interface{}{path[j]: v}
+		}
+
+		switch {
+		case found == nil:
+			return insertJSONCMember(data, obj, closing, members, name, v)
+		case depth == len(path)-1 || data[found.valueStart] != '{':
+			encoded, err := marshalJSONC(data, v, lineIndent(data, found.keyStart))
+			if err != nil {
+				return nil, err
+			}
+			return splice(data, found.valueStart, found.valueEnd, encoded), nil
+		}
+		obj = found.valueStart
+	}
+	return data, nil
+}
+
+// jsoncMember is an object member, located by byte offsets.
+type jsoncMember struct {
+	name       string
+	keyStart   int
+	valueStart int
+	valueEnd   int
+}
+
+// jsoncMembers lists the members of the object starting at obj and
+// returns the offset of its closing brace.
+func jsoncMembers(data []byte, obj int) ([]jsoncMember, int, error) {
+	var members []jsoncMember
+	i := skipJSONCSpace(data, obj+1)
+	for i < len(data) {
+		if data[i] == '}' {
+			return members, i, nil
+		}
+		if data[i] != '"' {
+			return nil, 0, fmt.Errorf("malformed JSONC at offset %d", i)
+		}
+
+		keyEnd := jsoncStringEnd(data, i)
+		var name string
+		if err := json.Unmarshal(data[i:keyEnd], &name); err != nil {
+			return nil, 0, fmt.Errorf("malformed JSONC key at offset %d: %w", i, err)
+		}
+		colon := skipJSONCSpace(data, keyEnd)
+		if colon >= len(data) || data[colon] != ':' {
+			return nil, 0, fmt.Errorf("malformed JSONC at offset %d", colon)
+		}
+		start := skipJSONCSpace(data, colon+1)
+		end := jsoncValueEnd(data, start)
+		members = append(members, jsoncMember{name: name, keyStart: i, valueStart: start, valueEnd: end})
+
+		i = skipJSONCSpace(data, end)
+		if i < len(data) && data[i] == ',' {
+			i = skipJSONCSpace(data, i+1)
+		}
+	}
+	return nil, 0, fmt.Errorf("unterminated JSONC object at offset %d", obj)
+}
+
+// insertJSONCMember adds a member after the last one of the object at obj.
+func insertJSONCMember(data []byte, obj, closing int, members []jsoncMember, name string, value interface{}) ([]byte, error) {
+	indent := lineIndent(data, obj) + indentUnit(data)
+	if len(members) > 0 {
+		indent = lineIndent(data, members[0].keyStart)
+	}
+
+	key, _ := json.Marshal(name)
+	encoded, err := marshalJSONC(data, value, indent)
+	if err != nil {
+		return nil, err
+	}
+	member := "\n" + indent + string(key) + ": " + string(encoded)
+
+	if len(members) == 0 {
+		return splice(data, obj+1, closing, []byte(member+"\n"+lineIndent(data, obj))), nil
+	}
+	// A trailing comma after the last member now follows the new one
+	last := members[len(members)-1].valueEnd
+	return splice(data, last, last, []byte(","+member)), nil
+}
+
+// marshalJSONC encodes value indented to continue a line starting with
+// prefix, using the document's indent unit.
+func marshalJSONC(data []byte, value interface{}, prefix string) ([]byte, error) {
+	return json.MarshalIndent(value, prefix, indentUnit(data))
+}
+
+// splice replaces data[start:end] with insert.
+func splice(data []byte, start, end int, insert []byte) []byte {
+	out := make([]byte, 0, len(data)-(end-start)+len(insert))
+	out = append(out, data[:start]...)
+	out = append(out, insert...)
+	return append(out, data[end:]...)
+}
+
+// skipJSONCSpace returns the offset of the first byte at or after i that
+// is neither whitespace nor part of a comment.
+func skipJSONCSpace(data []byte, i int) int {
+	for i < len(data) {
+		switch {
+		case isJSONSpace(data[i]):
+			i++
+		case data[i] == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+		case data[i] == '/' && i+1 < len(data) && data[i+1] == '*':
+			i += 2
+			for i+1 < len(data) && !(data[i] == '*' && data[i+1] == '/') {
+				i++
+			}
+			i += 2
+		default:
+			return i
+		}
+	}
+	return len(data)
+}
+
+// jsoncStringEnd returns the offset just past the string starting at i.
+func jsoncStringEnd(data []byte, i int) int {
+	for i++; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return len(data)
+}
+
+// jsoncValueEnd returns the offset just past the value starting at i.
+func jsoncValueEnd(data []byte, i int) int {
+	if i >= len(data) {
+		return i
+	}
+	switch data[i] {
+	case '"':
+		return jsoncStringEnd(data, i)
+	case '{', '[':
+		depth := 0
+		for i < len(data) {
+			switch c := data[i]; {
+			case c == '"':
+				i = jsoncStringEnd(data, i)
+				continue
+			case c == '/':
+				if next := skipJSONCSpace(data, i); next != i {
+					i = next
+					continue
+				}
+			case c == '{' || c == '[':
+				depth++
+			case c == '}' || c == ']':
+				depth--
+				if depth == 0 {
+					return i + 1
+				}
+			}
+			i++
+		}
+		return i
+	}
+	// Numbers, true, false and null
+	for i < len(data) && !isJSONSpace(data[i]) && !strings.ContainsRune(",}]/", rune(data[i])) {
+		i++
+	}
+	return i
+}
+
+// lineIndent returns the leading whitespace of the line containing pos.
+func lineIndent(data []byte, pos int) string {
+	start := bytes.LastIndexByte(data[:pos], '\n') + 1
+	end := start
+	for end < len(data) && (data[end] == ' ' || data[end] == '\t') {
+		end++
+	}
+	return string(data[start:end])
+}
+
+// indentUnit returns "\t" for tab-indented documents and two spaces
+// otherwise.
+func indentUnit(data []byte) string {
+	if bytes.Contains(data, []byte("\n\t")) {
+		return "\t"
+	}
+	return "  "
+}
@@ -41,6 +41,7 @@ type openCodeServerConfig struct {
 	Command string            `json:"command"` // For local type
 	Args    []string          `json:"args"`
 	URL     string            `json:"url"` // For remote type
+	Headers map[string]string `json:"headers"`
 	Env     map[string]string `json:"env"`
 	Enabled *bool             `json:"enabled"` // Optional, defaults to true
 }
@@ -106,9 +107,14 @@ func (s *OpenCodeSource) parseFile(path string) (*SourceResult, error) {
 			continue
 		}
 
-		// Only support local (command-based) servers for now
+		// Remote servers are kept with their URL and headers
 		if server.Type == "remote" {
-			// TODO: Support remote MCP servers
+			servers[name] = &config.ServerConfig{
+				Type:    server.Type,
+				URL:     server.URL,
+				Headers: server.Headers,
+				Source:  s.Name(),
+			}
 			continue
 		}
 
@@ -0,0 +1,51 @@
+package sources
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// RooCodeSource reads MCP configurations from the Roo Code VS Code extension.
+//
+// Configuration locations:
+//   - ~/.roo/mcp.json (global user config)
+//   - <VS Code user dir>/globalStorage/rooveterinaryinc.roo-cline/settings/mcp_settings.json
+//   - .roo/mcp.json (project-level config)
+//
+// Format is the same as Cline ("mcpServers" with an optional "disabled" flag).
+type RooCodeSource struct{}
+
+// NewRooCodeSource creates a new Roo Code configuration source.
+func NewRooCodeSource() *RooCodeSource {
+	return &RooCodeSource{}
+}
+
+// Name returns the source identifier.
+func (s *RooCodeSource) Name() string {
+	return "roo-code"
+}
+
+// Scan searches for and parses Roo Code MCP configurations.
+func (s *RooCodeSource) Scan() (*SourceResult, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	// Try config locations in order of precedence
+	paths := []string{
+		filepath.Join(home, ".roo", "mcp.json"),
+		filepath.Join(vscodeUserDir(home), "globalStorage",
+			"rooveterinaryinc.roo-cline", "settings", "mcp_settings.json"),
+		filepath.Join(".roo", "mcp.json"),
+	}
+
+	for _, path := range paths {
+		result, err := parseMCPServersFile(path, s.Name())
+		if err == nil && result != nil {
+			return result, nil
+		}
+	}
+
+	return nil, nil
+}
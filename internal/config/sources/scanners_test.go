@@ -0,0 +1,152 @@
+package sources
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// writeFile creates parent directories and writes content.
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStripJSONC(t *testing.T) {
+	input := `{
+  // line comment
+  "url": "http://example.com/*not-a-comment*/", /* block */
+  "list": [1, 2,],
+  "nested": {"a": "x,}", // trailing
+  },
+}`
+	want := `{
+  
+  "url": "http://example.com/*not-a-comment*/", 
+  "list": [1, 2],
+  "nested": {"a": "x,}" 
+  }
+}`
+	if got := string(stripJSONC([]byte(input))); got != want {
+		t.Errorf("stripJSONC() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestVSCodeSourceScan(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("user dir layout differs per OS")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	writeFile(t, filepath.Join(home, ".config", "Code", "User", "settings.json"), `{
+  // Copilot agent servers
+  "mcp": {
+    "servers": {
+      "github": {"type": "stdio", "command": "npx", "args": ["-y", "gh-mcp"], "env": {"GITHUB_TOKEN": "x"}},
+      "remote": {"type": "http", "url": "https://example.com/mcp?team=a", "headers": {"Authorization": "Bearer x"}},
+    }
+  }
+}`)
+
+	result, err := NewVSCodeSource().Scan()
+	if err != nil || result == nil {
+		t.Fatalf("Scan() = %v, %v", result, err)
+	}
+	if len(result.Servers) != 2 || result.Servers["github"] == nil {
+		t.Fatalf("unexpected servers: %+v", result.Servers)
+	}
+	if result.Servers["github"].Source != "vscode" {
+		t.Errorf("unexpected source: %s", result.Servers["github"].Source)
+	}
+
+	// Remote servers are kept, not dropped
+	remote := result.Servers["remote"]
+	if remote == nil || !remote.IsRemote() || remote.Type != "http" || remote.URL != "https://example.com/mcp?team=a" || remote.Headers["Authorization"] != "Bearer x" {
+		t.Errorf("remote server = %+v", remote)
+	}
+}
+
+func TestClineSourceScan(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("user dir layout differs per OS")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	writeFile(t, filepath.Join(home, ".config", "Code", "User", "globalStorage",
+		"saoudrizwan.claude-dev", "settings", "cline_mcp_settings.json"), `{
+  "mcpServers": {
+    "jira": {"command": "npx", "args": ["-y", "jira-mcp"]},
+    "off": {"command": "npx", "disabled": true}
+  }
+}`)
+
+	result, err := NewClineSource().Scan()
+	if err != nil || result == nil {
+		t.Fatalf("Scan() = %v, %v", result, err)
+	}
+	if len(result.Servers) != 1 || result.Servers["jira"] == nil {
+		t.Errorf("disabled server should be skipped: %+v", result.Servers)
+	}
+}
+
+func TestRooCodeSourceScan(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	writeFile(t, filepath.Join(home, ".roo", "mcp.json"),
+		`{"mcpServers": {"outline": {"command": "uvx", "args": ["mcp-outline"]}}}`)
+
+	result, err := NewRooCodeSource().Scan()
+	if err != nil || result == nil {
+		t.Fatalf("Scan() = %v, %v", result, err)
+	}
+	if result.Servers["outline"] == nil || result.Servers["outline"].Command != "uvx" {
+		t.Errorf("unexpected servers: %+v", result.Servers)
+	}
+}
+
+func TestZedSourceParse(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.json")
+	writeFile(t, path, `{
+  "theme": "One Dark",
+  "context_servers": {
+    "flat": {"command": "npx", "args": ["-y", "flat-mcp"], "env": {"TOKEN": "x"}},
+    "nested": {"command": {"path": "node", "args": ["server.js"], "env": {}}},
+    "extension": {"settings": {}},
+  }
+}`)
+
+	result, err := NewZedSource().parseFile(path)
+	if err != nil || result == nil {
+		t.Fatalf("parseFile() = %v, %v", result, err)
+	}
+	if len(result.Servers) != 2 {
+		t.Fatalf("expected 2 servers, got %+v", result.Servers)
+	}
+	if result.Servers["flat"].Command != "npx" || len(result.Servers["flat"].Args) != 2 {
+		t.Errorf("flat form not parsed: %+v", result.Servers["flat"])
+	}
+	if result.Servers["nested"].Command != "node" || result.Servers["nested"].Args[0] != "server.js" {
+		t.Errorf("nested form not parsed: %+v", result.Servers["nested"])
+	}
+}
+
+func TestScannersMissingConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Chdir(t.TempDir())
+
+	for _, source := range []Source{NewVSCodeSource(), NewClineSource(), NewRooCodeSource(), NewZedSource()} {
+		result, err := source.Scan()
+		if err != nil || result != nil {
+			t.Errorf("%s: expected nothing found, got %v, %v", source.Name(), result, err)
+		}
+	}
+}
@@ -8,7 +8,10 @@ Supported sources:
   - Gemini CLI: ~/.gemini/settings.json
   - Cursor: ~/.cursor/mcp.json
   - Windsurf: ~/.codeium/windsurf/mcp_config.json
-  - Roo Code: ~/.roo/mcp.json
+  - Roo Code: ~/.roo/mcp.json, Roo extension settings, .roo/mcp.json
+  - VS Code (Copilot): <user dir>/mcp.json, settings.json "mcp", .vscode/mcp.json
+  - Cline: Cline extension cline_mcp_settings.json
+  - Zed: ~/.config/zed/settings.json "context_servers"
 */
 package sources
 
@@ -41,11 +44,14 @@ func GetAllSources() []Source {
 	return []Source{
 		NewClaudeCodeSource(),
 		NewOpenCodeSource(),
+		NewVSCodeSource(),
+		NewClineSource(),
+		NewRooCodeSource(),
+		NewZedSource(),
 		// Future sources can be added here:
 		// NewAntigravitySource(),
 		// NewGeminiCLISource(),
 		// NewCursorSource(),
 		// NewWindsurfSource(),
-		// NewRooCodeSource(),
 	}
 }
@@ -0,0 +1,190 @@
+package sources
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+)
+
+// VSCodeSource reads MCP configurations from VS Code (GitHub Copilot agent mode).
+//
+// Configuration locations:
+//   - <user dir>/mcp.json (user-level config)
+//   - <user dir>/settings.json, "mcp" section (legacy location)
+//   - .vscode/mcp.json (workspace config)
+//
+// Format:
+//
+//	{
+//	  "servers": {
+//	    "serverName": {
+//	      "type": "stdio",
+//	      "command": "npx",
+//	      "args": ["-y", "@package/name"],
+//	      "env": {"KEY": "value"}
+//	    }
+//	  }
+//	}
+type VSCodeSource struct{}
+
+// vscodeMCPConfig represents the VS Code mcp.json structure.
+type vscodeMCPConfig struct {
+	Servers map[string]stdioServerConfig `json:"servers"`
+}
+
+// vscodeSettings represents the MCP section of VS Code settings.json.
+type vscodeSettings struct {
+	MCP vscodeMCPConfig `json:"mcp"`
+}
+
+// stdioServerConfig is the server entry shape shared by VS Code-family
+// clients. Remote entries have a type "http"/"sse" and a URL instead of
+// a command.
+type stdioServerConfig struct {
+	Type     string            `json:"type"`
+	Command  string            `json:"command"`
+	Args     []string          `json:"args"`
+	Env      map[string]string `json:"env"`
+	URL      string            `json:"url"`
+	Headers  map[string]string `json:"headers"`
+	Disabled bool              `json:"disabled"`
+}
+
+// NewVSCodeSource creates a new VS Code configuration source.
+func NewVSCodeSource() *VSCodeSource {
+	return &VSCodeSource{}
+}
+
+// Name returns the source identifier.
+func (s *VSCodeSource) Name() string {
+	return "vscode"
+}
+
+// Scan searches for and parses VS Code MCP configurations.
+func (s *VSCodeSource) Scan() (*SourceResult, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	userDir := vscodeUserDir(home)
+
+	// User-level mcp.json
+	result, err := s.parseFile(filepath.Join(userDir, "mcp.json"), false)
+	if err == nil && result != nil {
+		return result, nil
+	}
+
+	// Legacy "mcp" section in settings.json
+	result, err = s.parseFile(filepath.Join(userDir, "settings.json"), true)
+	if err == nil && result != nil {
+		return result, nil
+	}
+
+	// Workspace config
+	return s.parseFile(filepath.Join(".vscode", "mcp.json"), false)
+}
+
+// parseFile reads an mcp.json file, or the "mcp" section of settings.json.
+func (s *VSCodeSource) parseFile(path string, settings bool) (*SourceResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	data = stripJSONC(data)
+
+	var cfg vscodeMCPConfig
+	if settings {
+		var wrapper vscodeSettings
+		if err := json.Unmarshal(data, &wrapper); err != nil {
+			return nil, err
+		}
+		cfg = wrapper.MCP
+	} else if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+
+	return stdioServersResult(path, s.Name(), cfg.Servers), nil
+}
+
+// vscodeUserDir returns the VS Code user settings directory for this OS.
+func vscodeUserDir(home string) string {
+	switch runtime.GOOS {
+	case "darwin":
+		return filepath.Join(home, "Library", "Application Support", "Code", "User")
+	case "windows":
+		if appData := os.Getenv("APPDATA"); appData != "" {
+			return filepath.Join(appData, "Code", "User")
+		}
+		return filepath.Join(home, "AppData", "Roaming", "Code", "User")
+	default:
+		return filepath.Join(home, ".config", "Code", "User")
+	}
+}
+
+// parseMCPServersFile reads a file with a top-level "mcpServers" map,
+// the format used by Cline and Roo Code.
+func parseMCPServersFile(path, sourceName string) (*SourceResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var cfg struct {
+		MCPServers map[string]stdioServerConfig `json:"mcpServers"`
+	}
+	if err := json.Unmarshal(stripJSONC(data), &cfg); err != nil {
+		return nil, err
+	}
+
+	return stdioServersResult(path, sourceName, cfg.MCPServers), nil
+}
+
+// stdioServersResult converts enabled entries to internal format.
+// Returns nil if nothing usable was found.
+func stdioServersResult(path, sourceName string, entries map[string]stdioServerConfig) *SourceResult {
+	servers := make(map[string]*config.ServerConfig)
+	for name, server := range entries {
+		if server.Disabled {
+			continue
+		}
+
+		// Remote servers are kept with their URL and headers
+		if server.URL != "" || server.Type == "http" || server.Type == "sse" {
+			servers[name] = &config.ServerConfig{
+				Type:    server.Type,
+				URL:     server.URL,
+				Headers: server.Headers,
+				Source:  sourceName,
+			}
+			continue
+		}
+		if server.Command == "" {
+			continue
+		}
+
+		servers[name] = &config.ServerConfig{
+			Command: server.Command,
+			Args:    server.Args,
+			Env:     config.NormalizeEnvVars(server.Env),
+			Source:  sourceName,
+		}
+	}
+
+	if len(servers) == 0 {
+		return nil
+	}
+
+	return &SourceResult{
+		ConfigPath: path,
+		Servers:    servers,
+	}
+}
@@ -0,0 +1,130 @@
+package sources
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+)
+
+// ZedSource reads MCP configurations (context servers) from the Zed editor.
+//
+// Configuration location:
+//   - ~/.config/zed/settings.json (Linux, macOS)
+//   - %APPDATA%/Zed/settings.json (Windows)
+//
+// Format (both the current flat and the older nested command forms):
+//
+//	{
+//	  "context_servers": {
+//	    "serverName": {
+//	      "command": "npx",
+//	      "args": ["-y", "@package/name"],
+//	      "env": {"KEY": "value"}
+//	    },
+//	    "legacyServer": {
+//	      "command": {"path": "npx", "args": ["-y", "@package/name"], "env": {}}
+//	    }
+//	  }
+//	}
+type ZedSource struct{}
+
+// zedSettings represents the context_servers section of Zed settings.
+type zedSettings struct {
+	ContextServers map[string]zedServerConfig `json:"context_servers"`
+}
+
+// zedServerConfig represents a single Zed context server.
+// Command is either a string (flat form) or an object (nested form).
+type zedServerConfig struct {
+	Command json.RawMessage   `json:"command"`
+	Args    []string          `json:"args"`
+	Env     map[string]string `json:"env"`
+}
+
+// zedCommand is the nested command form.
+type zedCommand struct {
+	Path string            `json:"path"`
+	Args []string          `json:"args"`
+	Env  map[string]string `json:"env"`
+}
+
+// NewZedSource creates a new Zed configuration source.
+func NewZedSource() *ZedSource {
+	return &ZedSource{}
+}
+
+// Name returns the source identifier.
+func (s *ZedSource) Name() string {
+	return "zed"
+}
+
+// Scan searches for and parses Zed context server configurations.
+func (s *ZedSource) Scan() (*SourceResult, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(home, ".config", "zed", "settings.json")
+	if runtime.GOOS == "windows" {
+		if appData := os.Getenv("APPDATA"); appData != "" {
+			path = filepath.Join(appData, "Zed", "settings.json")
+		}
+	}
+
+	return s.parseFile(path)
+}
+
+// parseFile reads and parses a Zed settings file.
+func (s *ZedSource) parseFile(path string) (*SourceResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var cfg zedSettings
+	if err := json.Unmarshal(stripJSONC(data), &cfg); err != nil {
+		return nil, err
+	}
+
+	servers := make(map[string]*config.ServerConfig)
+	for name, server := range cfg.ContextServers {
+		var command string
+		args, env := server.Args, server.Env
+
+		if err := json.Unmarshal(server.Command, &command); err != nil {
+			var nested zedCommand
+			if err := json.Unmarshal(server.Command, &nested); err != nil {
+				continue
+			}
+			command, args, env = nested.Path, nested.Args, nested.Env
+		}
+
+		// Extension-provided servers have no command; skip them
+		if command == "" {
+			continue
+		}
+
+		servers[name] = &config.ServerConfig{
+			Command: command,
+			Args:    args,
+			Env:     config.NormalizeEnvVars(env),
+			Source:  s.Name(),
+		}
+	}
+
+	if len(servers) == 0 {
+		return nil, nil
+	}
+
+	return &SourceResult{
+		ConfigPath: path,
+		Servers:    servers,
+	}, nil
+}
@@ -7,9 +7,16 @@ to detect and prevent configuration issues.
 package config
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
 )
 
 // IsSelfReference checks if a server config refers to tool-hub-mcp itself.
@@ -36,8 +43,17 @@ func IsSelfReference(server *ServerConfig) bool {
 // ValidateServer checks if a server config is valid for import.
 // Returns an error if validation fails.
 func ValidateServer(name string, server *ServerConfig) error {
-	// Check for empty command
-	if server.Command == "" {
+	// Remote servers have a URL instead of a command; containerized
+	// servers may rely on the image's entrypoint
+	if server.IsRemote() {
+		if err := validateURL(server.URL); err != nil {
+			return fmt.Errorf("server '%s': %w", name, err)
+		}
+	} else if server.IsContainer() {
+		if err := validateContainer(server); err != nil {
+			return fmt.Errorf("server '%s': %w", name, err)
+		}
+	} else if server.Command == "" {
 		return fmt.Errorf("server '%s': empty command", name)
 	}
 
@@ -46,5 +62,280 @@ func ValidateServer(name string, server *ServerConfig) error {
 		return fmt.Errorf("server '%s': self-reference detected (tool-hub-mcp cannot import itself)", name)
 	}
 
+	// Check rate limit declaration
+	if server.RateLimit != nil {
+		if server.RateLimit.Requests <= 0 {
+			return fmt.Errorf("server '%s': rate limit requests must be positive", name)
+		}
+		if _, err := server.RateLimit.Window(); err != nil {
+			return fmt.Errorf("server '%s': %w", name, err)
+		}
+		if wait := server.RateLimit.MaxWaitSeconds; wait != nil && *wait < 0 {
+			return fmt.Errorf("server '%s': rate limit maxWaitSeconds must not be negative", name)
+		}
+		if share := server.RateLimit.NearExhaustion; share != nil && (*share <= 0 || *share > 1) {
+			return fmt.Errorf("server '%s': rate limit nearExhaustion must be above 0 and at most 1", name)
+		}
+	}
+
+	// Check inherited environment patterns
+	for _, pattern := range server.EnvPassthrough {
+		if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+			return fmt.Errorf("server '%s': invalid envPassthrough pattern %q", name, pattern)
+		}
+	}
+
+	// Check call throttle
+	if server.Throttle != nil {
+		if err := ValidateThrottle(server.Throttle); err != nil {
+			return fmt.Errorf("server '%s': %w", name, err)
+		}
+	}
+
+	// Check result cache settings
+	if server.Cache != nil {
+		if _, err := server.Cache.Duration(); err != nil {
+			return fmt.Errorf("server '%s': %w", name, err)
+		}
+	}
+
+	// Check idempotent tool patterns
+	for _, pattern := range server.IdempotentTools {
+		if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+			return fmt.Errorf("server '%s': invalid idempotentTools pattern %q", name, pattern)
+		}
+	}
+
+	// Check working directory
+	if server.Cwd != "" && !isAbsPath(server, server.Cwd) && !(server.ExpandsPaths() && !server.IsContainer() && isHomePath(server.Cwd)) {
+		return fmt.Errorf("server '%s': cwd '%s' must be an absolute path", name, server.Cwd)
+	}
+
+	// Check resource limits
+	if server.Limits != nil && (server.Limits.MemoryMB < 0 || server.Limits.CPUSeconds < 0) {
+		return fmt.Errorf("server '%s': resource limits cannot be negative", name)
+	}
+
+	// Check sandbox paths
+	if server.Sandbox != nil {
+		for _, path := range server.Sandbox.Writable {
+			if !isAbsPath(server, path) {
+				return fmt.Errorf("server '%s': sandbox writable path '%s' must be absolute", name, path)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateURL checks the endpoint of a remote server.
+func validateURL(rawURL string) error {
+	if rawURL == "" {
+		return fmt.Errorf("url is required for remote servers")
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("invalid url: must be an http or https URL")
+	}
+	return nil
+}
+
+// isAbsPath reports whether path is absolute where the server runs: on
+// the host, or inside its (Linux) container.
+func isAbsPath(server *ServerConfig, path string) bool {
+	if server.IsContainer() {
+		return strings.HasPrefix(path, "/")
+	}
+	return filepath.IsAbs(path)
+}
+
+// validateContainer checks the runtime and container settings of a
+// containerized server.
+func validateContainer(server *ServerConfig) error {
+	switch server.Runtime {
+	case RuntimeDocker, RuntimePodman:
+	default:
+		return fmt.Errorf("unknown runtime '%s', use %q or %q", server.Runtime, RuntimeDocker, RuntimePodman)
+	}
+	if server.Container == nil || strings.TrimSpace(server.Container.Image) == "" {
+		return fmt.Errorf("container.image is required for runtime '%s'", server.Runtime)
+	}
+	switch server.Container.Pull {
+	case "", PullMissing, PullAlways, PullNever:
+	default:
+		return fmt.Errorf("unknown container.pull '%s', use %q, %q or %q", server.Container.Pull, PullMissing, PullAlways, PullNever)
+	}
+	for _, volume := range server.Container.Volumes {
+		if !strings.Contains(volume, ":") {
+			return fmt.Errorf("container volume '%s' must be host:container", volume)
+		}
+	}
+	return nil
+}
+
+// ValidateData checks the contents of a config file: valid JSON matching
+// the config schema, with a servers object, and every server passing
+// ValidateServer. It returns all problems found, in path order, or nil if
+// the config is valid.
+func ValidateData(data []byte) []error {
+	problems, err := validateSchema(data)
+	if err != nil {
+		return []error{fmt.Errorf("JSON parse error: %v", err)}
+	}
+	if len(problems) > 0 {
+		return problems
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return []error{fmt.Errorf("JSON parse error: %v", err)}
+	}
+	if cfg.Servers == nil {
+		return []error{fmt.Errorf("missing 'servers' field")}
+	}
+
+	names := make([]string, 0, len(cfg.Servers))
+	for name := range cfg.Servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		server := cfg.Servers[name]
+		if server == nil {
+			problems = append(problems, fmt.Errorf("server '%s': empty definition", name))
+			continue
+		}
+		if err := ValidateServer(name, server); err != nil {
+			problems = append(problems, err)
+		}
+	}
+
+	if t := cfg.Settings.TelemetryConfig(); t != nil && t.Endpoint != "" {
+		if err := validateURL(t.Endpoint); err != nil {
+			problems = append(problems, fmt.Errorf("settings.telemetry endpoint: %w", err))
+		}
+	}
+	if t := cfg.Settings.ClientThrottleConfig(); t != nil {
+		if err := ValidateThrottle(t); err != nil {
+			problems = append(problems, fmt.Errorf("settings.clientThrottle: %w", err))
+		}
+	}
+	if retry := cfg.Settings.RetryConfig(); retry != nil {
+		if retry.MaxAttempts < 0 {
+			problems = append(problems, fmt.Errorf("settings.retry: maxAttempts cannot be negative"))
+		}
+		if _, _, err := retry.Delays(); err != nil {
+			problems = append(problems, fmt.Errorf("settings.retry: %w", err))
+		}
+	}
+	if breaker := cfg.Settings.CircuitBreakerConfig(); breaker != nil {
+		if breaker.Threshold < 0 {
+			problems = append(problems, fmt.Errorf("settings.circuitBreaker: threshold cannot be negative"))
+		}
+		if _, err := breaker.CooldownDuration(); err != nil {
+			problems = append(problems, fmt.Errorf("settings.circuitBreaker: %w", err))
+		}
+	}
+	if verbosity := cfg.Settings.DescriptionVerbosity(); !slices.Contains(DescriptionVerbosities, verbosity) {
+		problems = append(problems, fmt.Errorf("settings.descriptions.verbosity: unknown verbosity %q (use %s)", verbosity, strings.Join(DescriptionVerbosities, ", ")))
+	}
+	metaTools := cfg.Settings.ExposedMetaTools()
+	for _, name := range metaTools {
+		if !slices.Contains(KnownMetaTools, name) {
+			problems = append(problems, fmt.Errorf("settings.metaTools: unknown meta-tool %q (use %s)", name, strings.Join(KnownMetaTools, ", ")))
+		}
+	}
+	if !slices.Contains(metaTools, "hub_execute") {
+		problems = append(problems, fmt.Errorf("settings.metaTools: hub_execute is required to run tools"))
+	}
+	if language := cfg.Settings.SearchLanguage(); !slices.Contains(SearchLanguages, language) {
+		problems = append(problems, fmt.Errorf("settings.search.language: unknown language %q (use %s)", language, strings.Join(SearchLanguages, ", ")))
+	}
+	for i, webhook := range cfg.Settings.WebhookTargets() {
+		if err := ValidateWebhook(webhook); err != nil {
+			problems = append(problems, fmt.Errorf("settings.webhooks[%d]: %w", i, err))
+		}
+	}
+	for i, hook := range cfg.Settings.HookCommands() {
+		if err := ValidateHook(hook); err != nil {
+			problems = append(problems, fmt.Errorf("settings.hooks[%d]: %w", i, err))
+		}
+	}
+	return problems
+}
+
+// ValidateWebhook checks a webhook has an http(s) URL and valid event globs.
+func ValidateWebhook(webhook WebhookConfig) error {
+	if webhook.URL == "" {
+		return fmt.Errorf("url is required")
+	}
+	if err := validateURL(webhook.URL); err != nil {
+		return err
+	}
+	for _, pattern := range webhook.Events {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid event pattern '%s'", pattern)
+		}
+	}
+	return nil
+}
+
+// ValidateThrottle checks a throttle has a positive rate and no negative burst.
+func ValidateThrottle(t *Throttle) error {
+	if t.CallsPerMinute <= 0 {
+		return fmt.Errorf("throttle callsPerMinute must be positive")
+	}
+	if t.Burst < 0 {
+		return fmt.Errorf("throttle burst cannot be negative")
+	}
+	return nil
+}
+
+// ValidateHook checks a hook has a command, a known stage and a valid timeout.
+func ValidateHook(hook HookConfig) error {
+	if strings.TrimSpace(hook.Command) == "" {
+		return fmt.Errorf("command is required")
+	}
+	if hook.Stage != HookStagePre && hook.Stage != HookStagePost {
+		return fmt.Errorf("stage must be '%s' or '%s', got '%s'", HookStagePre, HookStagePost, hook.Stage)
+	}
+	if hook.Timeout != "" {
+		if d, err := time.ParseDuration(hook.Timeout); err != nil || d <= 0 {
+			return fmt.Errorf("invalid timeout '%s': use a positive duration like \"5s\"", hook.Timeout)
+		}
+	}
 	return nil
 }
+
+// ValidateFile checks a config file with ValidateData. All problems are
+// reported in a single InvalidConfigError.
+func ValidateFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+	return invalidConfig(path, ValidateData(data))
+}
+
+// invalidConfig wraps validation problems in an InvalidConfigError, or
+// returns nil when there are none.
+func invalidConfig(path string, problems []error) error {
+	if len(problems) == 0 {
+		return nil
+	}
+	return &InvalidConfigError{
+		Path:    path,
+		Message: formatProblems(problems),
+		Hint:    "Fix the listed problems and validate again",
+	}
+}
+
+// formatProblems lists validation problems one per line.
+func formatProblems(problems []error) string {
+	lines := make([]string, len(problems))
+	for i, problem := range problems {
+		lines[i] = "  - " + problem.Error()
+	}
+	return strings.Join(lines, "\n")
+}
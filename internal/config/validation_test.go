@@ -134,6 +134,22 @@ func TestValidateServer(t *testing.T) {
 			},
 			expectError: false,
 		},
+		{
+			name:        "Home-relative cwd",
+			serverName:  "notes",
+			server:      &ServerConfig{Command: "npx", Cwd: "~/notes"},
+			expectError: false,
+		},
+		{
+			name:       "Invalid envPassthrough pattern",
+			serverName: "aws",
+			server: &ServerConfig{
+				Command:        "npx",
+				EnvPassthrough: []string{"AWS_["},
+			},
+			expectError: true,
+			errorMsg:    "envPassthrough",
+		},
 	}
 
 	for _, tt := range tests {
@@ -167,3 +183,336 @@ func TestValidateServer(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateServerRateLimit(t *testing.T) {
+	negative, zero := -1, 0
+	half, none, over := 0.5, 0.0, 1.5
+	tests := []struct {
+		name      string
+		rateLimit *RateLimit
+		wantErr   bool
+	}{
+		{"no limit", nil, false},
+		{"valid limit", &RateLimit{Requests: 5000, Period: "1h"}, false},
+		{"zero requests", &RateLimit{Requests: 0, Period: "1h"}, true},
+		{"invalid period", &RateLimit{Requests: 10, Period: "hourly"}, true},
+		{"negative period", &RateLimit{Requests: 10, Period: "-1m"}, true},
+		{"negative max wait", &RateLimit{Requests: 10, Period: "1h", MaxWaitSeconds: &negative}, true},
+		{"zero max wait", &RateLimit{Requests: 10, Period: "1h", MaxWaitSeconds: &zero}, false},
+		{"near exhaustion", &RateLimit{Requests: 10, Period: "1h", NearExhaustion: &half}, false},
+		{"zero near exhaustion", &RateLimit{Requests: 10, Period: "1h", NearExhaustion: &none}, true},
+		{"near exhaustion above 1", &RateLimit{Requests: 10, Period: "1h", NearExhaustion: &over}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &ServerConfig{Command: "npx", RateLimit: tt.rateLimit}
+			err := ValidateServer("github", server)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateServer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRateLimitMaxWait(t *testing.T) {
+	limit := &RateLimit{Requests: 1, Period: "1m"}
+	if limit.MaxWait() != DefaultRateLimitMaxWait {
+		t.Errorf("Expected default max wait, got %v", limit.MaxWait())
+	}
+
+	zero := 0
+	limit.MaxWaitSeconds = &zero
+	if limit.MaxWait() != 0 {
+		t.Errorf("Expected zero max wait, got %v", limit.MaxWait())
+	}
+}
+
+func TestRateLimitPaceFrom(t *testing.T) {
+	limit := &RateLimit{Requests: 5000, Period: "1h"}
+	if got := limit.PaceFrom(); got != 4500 {
+		t.Errorf("PaceFrom() = %d, want 4500", got)
+	}
+	all := 1.0
+	limit.NearExhaustion = &all
+	if got := limit.PaceFrom(); got != 5000 {
+		t.Errorf("PaceFrom() = %d, want 5000", got)
+	}
+}
+
+func TestValidateData(t *testing.T) {
+	valid := `{"servers": {"jira": {"command": "npx", "args": ["-y", "@lvmk/jira-mcp"]}}}`
+	if problems := ValidateData([]byte(valid)); problems != nil {
+		t.Errorf("valid config reported problems: %v", problems)
+	}
+
+	if problems := ValidateData([]byte(`{"servers": {`)); len(problems) != 1 || !strings.Contains(problems[0].Error(), "JSON parse error") {
+		t.Errorf("expected a parse error, got %v", problems)
+	}
+	if problems := ValidateData([]byte(`{}`)); len(problems) != 1 || !strings.Contains(problems[0].Error(), "missing 'servers'") {
+		t.Errorf("expected missing servers, got %v", problems)
+	}
+
+	// Every invalid server is reported, in name order
+	invalid := `{"servers": {
+		"zed": {"command": ""},
+		"api": {"command": "npx", "rateLimit": {"requests": 0}},
+		"ok": {"command": "npx"}
+	}}`
+	problems := ValidateData([]byte(invalid))
+	if len(problems) != 2 {
+		t.Fatalf("expected 2 problems, got %v", problems)
+	}
+	if !strings.Contains(problems[0].Error(), "'api'") || !strings.Contains(problems[1].Error(), "'zed'") {
+		t.Errorf("problems out of order: %v", problems)
+	}
+}
+
+func TestValidateServerContainer(t *testing.T) {
+	tests := []struct {
+		name    string
+		server  *ServerConfig
+		wantErr string
+	}{
+		{"entrypoint only", &ServerConfig{Runtime: "docker", Container: &ContainerSettings{Image: "mcp/github"}}, ""},
+		{"missing image", &ServerConfig{Runtime: "docker"}, "container.image is required"},
+		{"unknown runtime", &ServerConfig{Runtime: "lxc", Container: &ContainerSettings{Image: "x"}}, "unknown runtime"},
+		{"bad pull", &ServerConfig{Runtime: "podman", Container: &ContainerSettings{Image: "x", Pull: "sometimes"}}, "unknown container.pull"},
+		{"bad volume", &ServerConfig{Runtime: "docker", Container: &ContainerSettings{Image: "x", Volumes: []string{"/data"}}}, "must be host:container"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateServer("srv", tt.server)
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateServerLimits(t *testing.T) {
+	server := &ServerConfig{Command: "node", Limits: &ResourceLimits{MemoryMB: 512, CPUSeconds: 60}}
+	if err := ValidateServer("srv", server); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	server.Limits.CPUSeconds = -1
+	if err := ValidateServer("srv", server); err == nil || !strings.Contains(err.Error(), "cannot be negative") {
+		t.Errorf("error = %v, want negative limit error", err)
+	}
+
+	var settings *Settings
+	if settings.ChildProcessLimit() != 0 {
+		t.Error("nil settings should not limit children")
+	}
+	if (&Settings{MaxChildProcesses: 4}).ChildProcessLimit() != 4 {
+		t.Error("ChildProcessLimit() should return the configured limit")
+	}
+	if settings.ProcessPoolLimit() != 0 {
+		t.Error("nil settings should not limit the pool")
+	}
+	if (&Settings{ProcessPoolSize: -1}).ProcessPoolLimit() != 0 || (&Settings{ProcessPoolSize: 5}).ProcessPoolLimit() != 5 {
+		t.Error("ProcessPoolLimit() should return 0 for negative sizes and the configured size otherwise")
+	}
+}
+
+func TestValidateServerSandbox(t *testing.T) {
+	network := false
+	server := &ServerConfig{Command: "node", Sandbox: &SandboxSettings{Network: &network, FSReadOnly: true, Writable: []string{"/tmp"}}}
+	if err := ValidateServer("srv", server); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if !server.Sandbox.NoNetwork() || !server.Sandbox.ReadOnly() || !server.Sandbox.Enabled() {
+		t.Error("sandbox should deny network and writes")
+	}
+
+	server.Sandbox.Writable = []string{"cache"}
+	if err := ValidateServer("srv", server); err == nil || !strings.Contains(err.Error(), "must be absolute") {
+		t.Errorf("error = %v, want relative path error", err)
+	}
+
+	var none *SandboxSettings
+	if none.Enabled() {
+		t.Error("nil sandbox should not restrict anything")
+	}
+}
+
+func TestValidateServerCwd(t *testing.T) {
+	if err := ValidateServer("srv", &ServerConfig{Command: "node", Cwd: "projects/mcp"}); err == nil || !strings.Contains(err.Error(), "absolute path") {
+		t.Errorf("error = %v, want absolute path error", err)
+	}
+	container := &ServerConfig{Runtime: "docker", Container: &ContainerSettings{Image: "x"}, Cwd: "/app"}
+	if err := ValidateServer("srv", container); err != nil {
+		t.Errorf("container cwd: unexpected error: %v", err)
+	}
+
+	servers, _, err := ParseAnyMCPConfig(`{"mcpServers": {"local": {"command": "node", "args": ["index.js"], "cwd": "/srv/mcp"}}}`)
+	if err != nil || servers["local"] == nil || servers["local"].Cwd != "/srv/mcp" {
+		t.Errorf("ParseAnyMCPConfig() cwd = %+v, %v", servers["local"], err)
+	}
+}
+
+func TestValidateServerCache(t *testing.T) {
+	for _, ttl := range []string{"soon", "-1m"} {
+		server := &ServerConfig{Command: "node", Cache: &CacheSettings{TTL: ttl}}
+		if err := ValidateServer("srv", server); err == nil || !strings.Contains(err.Error(), "cache ttl") {
+			t.Errorf("ttl %q: error = %v, want cache ttl error", ttl, err)
+		}
+	}
+	server := &ServerConfig{Command: "node", Cache: &CacheSettings{Tools: []string{"get_*"}}}
+	if err := ValidateServer("srv", server); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if ttl, _ := server.Cache.Duration(); ttl != DefaultCacheTTL {
+		t.Errorf("default ttl = %v, want %v", ttl, DefaultCacheTTL)
+	}
+}
+
+func TestValidateDataTelemetry(t *testing.T) {
+	valid := `{"servers": {"a": {"command": "npx"}}, "settings": {"telemetry": {"endpoint": "http://localhost:4318"}}}`
+	if problems := ValidateData([]byte(valid)); problems != nil {
+		t.Errorf("valid telemetry endpoint reported problems: %v", problems)
+	}
+
+	invalid := `{"servers": {"a": {"command": "npx"}}, "settings": {"telemetry": {"endpoint": "localhost:4318"}}}`
+	problems := ValidateData([]byte(invalid))
+	if len(problems) != 1 || !strings.Contains(problems[0].Error(), "settings.telemetry endpoint") {
+		t.Errorf("expected a telemetry endpoint problem, got %v", problems)
+	}
+}
+
+func TestValidateHook(t *testing.T) {
+	tests := []struct {
+		hook    HookConfig
+		wantErr string
+	}{
+		{HookConfig{Command: "policy", Stage: HookStagePre}, ""},
+		{HookConfig{Command: "log", Stage: HookStagePost, Timeout: "2s"}, ""},
+		{HookConfig{Stage: HookStagePre}, "command is required"},
+		{HookConfig{Command: "policy", Stage: "during"}, "stage must be"},
+		{HookConfig{Command: "policy", Stage: HookStagePre, Timeout: "soon"}, "invalid timeout"},
+	}
+	for _, tt := range tests {
+		err := ValidateHook(tt.hook)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("ValidateHook(%+v) = %v, want nil", tt.hook, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("ValidateHook(%+v) = %v, want %q", tt.hook, err, tt.wantErr)
+		}
+	}
+
+	data := `{"servers": {}, "settings": {"hooks": [{"command": "policy", "stage": "pre"}, {"command": "", "stage": "post"}]}}`
+	problems := ValidateData([]byte(data))
+	if len(problems) != 1 || !strings.Contains(problems[0].Error(), "settings.hooks[1]") {
+		t.Errorf("expected a problem for hooks[1], got %v", problems)
+	}
+}
+
+func TestValidateThrottle(t *testing.T) {
+	if err := ValidateThrottle(&Throttle{CallsPerMinute: 30}); err != nil {
+		t.Errorf("valid throttle rejected: %v", err)
+	}
+	if err := ValidateThrottle(&Throttle{}); err == nil || !strings.Contains(err.Error(), "must be positive") {
+		t.Errorf("expected a rate error, got %v", err)
+	}
+	if err := ValidateThrottle(&Throttle{CallsPerMinute: 30, Burst: -1}); err == nil {
+		t.Error("expected a negative burst error")
+	}
+
+	data := `{"servers": {"search": {"command": "npx", "throttle": {"callsPerMinute": 0}}}, "settings": {"clientThrottle": {"callsPerMinute": -5}}}`
+	problems := ValidateData([]byte(data))
+	if len(problems) != 2 {
+		t.Errorf("expected server and client throttle problems, got %v", problems)
+	}
+}
+
+func TestValidateWebhook(t *testing.T) {
+	if err := ValidateWebhook(WebhookConfig{URL: "https://hooks.slack.com/services/T/B/X", Events: []string{"execution.failed"}}); err != nil {
+		t.Errorf("valid webhook rejected: %v", err)
+	}
+	if err := ValidateWebhook(WebhookConfig{}); err == nil || !strings.Contains(err.Error(), "url is required") {
+		t.Errorf("expected a missing url error, got %v", err)
+	}
+	if err := ValidateWebhook(WebhookConfig{URL: "hooks.slack.com"}); err == nil {
+		t.Error("expected an invalid url error")
+	}
+	if err := ValidateWebhook(WebhookConfig{URL: "https://example.com", Events: []string{"server.["}}); err == nil || !strings.Contains(err.Error(), "invalid event pattern") {
+		t.Errorf("expected an invalid pattern error, got %v", err)
+	}
+}
+
+func TestValidateDataSearchLanguage(t *testing.T) {
+	valid := []byte(`{"servers": {}, "settings": {"search": {"language": "cjk"}}}`)
+	if problems := ValidateData(valid); len(problems) != 0 {
+		t.Errorf("expected no problems, got %v", problems)
+	}
+
+	invalid := []byte(`{"servers": {}, "settings": {"search": {"language": "klingon"}}}`)
+	problems := ValidateData(invalid)
+	if len(problems) != 1 || !strings.Contains(problems[0].Error(), "settings.search.language") {
+		t.Errorf("expected a settings.search.language problem, got %v", problems)
+	}
+}
+
+func TestValidateDataRetry(t *testing.T) {
+	valid := []byte(`{"servers": {"jira": {"command": "npx", "idempotentTools": ["get_*"]}}, "settings": {"retry": {"maxAttempts": 2, "backoff": "100ms", "maxBackoff": "1s"}}}`)
+	if problems := ValidateData(valid); len(problems) != 0 {
+		t.Errorf("expected no problems, got %v", problems)
+	}
+
+	invalid := []byte(`{"servers": {"jira": {"command": "npx", "idempotentTools": ["get_["]}}, "settings": {"retry": {"maxAttempts": -1, "backoff": "soon"}}}`)
+	problems := ValidateData(invalid)
+	if len(problems) != 3 {
+		t.Fatalf("expected 3 problems, got %v", problems)
+	}
+	for i, want := range []string{"idempotentTools", "maxAttempts", "retry backoff"} {
+		if !strings.Contains(problems[i].Error(), want) {
+			t.Errorf("problem %d = %v, want it to mention %s", i, problems[i], want)
+		}
+	}
+}
+
+func TestValidateDataCircuitBreaker(t *testing.T) {
+	valid := []byte(`{"servers": {}, "settings": {"circuitBreaker": {"threshold": 3, "cooldown": "30s"}}}`)
+	if problems := ValidateData(valid); len(problems) != 0 {
+		t.Errorf("expected no problems, got %v", problems)
+	}
+
+	invalid := []byte(`{"servers": {}, "settings": {"circuitBreaker": {"threshold": -1, "cooldown": "0s"}}}`)
+	if problems := ValidateData(invalid); len(problems) != 2 {
+		t.Errorf("expected 2 settings.circuitBreaker problems, got %v", problems)
+	}
+}
+
+func TestValidateDataDescriptionVerbosity(t *testing.T) {
+	valid := []byte(`{"servers": {}, "settings": {"descriptions": {"verbosity": "compact", "maxServers": 10}}}`)
+	if problems := ValidateData(valid); len(problems) != 0 {
+		t.Errorf("expected no problems, got %v", problems)
+	}
+
+	invalid := []byte(`{"servers": {}, "settings": {"descriptions": {"verbosity": "terse"}}}`)
+	problems := ValidateData(invalid)
+	if len(problems) != 1 || !strings.Contains(problems[0].Error(), "settings.descriptions.verbosity") {
+		t.Errorf("expected a settings.descriptions.verbosity problem, got %v", problems)
+	}
+}
+
+func TestValidateDataMetaTools(t *testing.T) {
+	valid := []byte(`{"servers": {}, "settings": {"metaTools": ["hub_list", "hub_search", "hub_execute"]}}`)
+	if problems := ValidateData(valid); len(problems) != 0 {
+		t.Errorf("expected no problems, got %v", problems)
+	}
+
+	invalid := []byte(`{"servers": {}, "settings": {"metaTools": ["hub_search", "hub_teleport"]}}`)
+	problems := ValidateData(invalid)
+	if len(problems) != 2 || !strings.Contains(problems[0].Error(), "hub_teleport") || !strings.Contains(problems[1].Error(), "hub_execute") {
+		t.Errorf("expected unknown and missing meta-tool problems, got %v", problems)
+	}
+}
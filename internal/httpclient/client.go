@@ -0,0 +1,96 @@
+/*
+Package httpclient provides the shared HTTP client factory for all outbound
+network access (update checks, remote connectors, registry discovery,
+embedding APIs).
+
+Clients honor HTTPS_PROXY/HTTP_PROXY/NO_PROXY from the environment, or an
+explicit proxy from settings, and can trust an additional CA bundle for
+corporate TLS-intercepting proxies. Configure is called once at startup;
+every subsystem then obtains clients through New.
+*/
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+// Options configures outbound HTTP.
+type Options struct {
+	// Proxy is an explicit proxy URL for all requests. When empty, the
+	// standard HTTPS_PROXY, HTTP_PROXY, and NO_PROXY variables are used.
+	Proxy string
+
+	// CABundle is a PEM file of extra root certificates trusted in
+	// addition to the system pool.
+	CABundle string
+}
+
+var (
+	mu        sync.RWMutex
+	transport http.RoundTripper
+)
+
+// Configure applies options to every client created afterwards.
+// On error the previous configuration is kept.
+func Configure(opts Options) error {
+	t, err := buildTransport(opts)
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	transport = t
+	mu.Unlock()
+	return nil
+}
+
+// New returns an HTTP client with the given timeout using the shared
+// proxy and CA configuration.
+func New(timeout time.Duration) *http.Client {
+	mu.RLock()
+	t := transport
+	mu.RUnlock()
+
+	if t == nil {
+		t = http.DefaultTransport
+	}
+	return &http.Client{Timeout: timeout, Transport: t}
+}
+
+// buildTransport creates a transport for the given options.
+func buildTransport(opts Options) (*http.Transport, error) {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+
+	if opts.Proxy != "" {
+		proxyURL, err := url.Parse(opts.Proxy)
+		if err != nil || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL '%s'", opts.Proxy)
+		}
+		t.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if opts.CABundle != "" {
+		pem, err := os.ReadFile(opts.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", opts.CABundle)
+		}
+		t.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	return t, nil
+}
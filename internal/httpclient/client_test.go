@@ -0,0 +1,92 @@
+package httpclient
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// reset restores the default transport after a test.
+func reset(t *testing.T) {
+	t.Cleanup(func() {
+		mu.Lock()
+		transport = nil
+		mu.Unlock()
+	})
+}
+
+func TestCABundleTrustsCustomRoot(t *testing.T) {
+	reset(t)
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// The self-signed test certificate is not trusted by default
+	if _, err := New(5 * time.Second).Get(server.URL); err == nil {
+		t.Fatal("expected TLS verification failure without CA bundle")
+	}
+
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(bundle, certPEM, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Configure(Options{CABundle: bundle}); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+
+	resp, err := New(5 * time.Second).Get(server.URL)
+	if err != nil {
+		t.Fatalf("request with CA bundle failed: %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestExplicitProxy(t *testing.T) {
+	reset(t)
+
+	var proxied bool
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = true
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxy.Close()
+
+	if err := Configure(Options{Proxy: proxy.URL}); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+
+	resp, err := New(5 * time.Second).Get("http://example.invalid/releases")
+	if err != nil {
+		t.Fatalf("request through proxy failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if !proxied {
+		t.Error("request did not go through the configured proxy")
+	}
+}
+
+func TestConfigureErrors(t *testing.T) {
+	reset(t)
+
+	if err := Configure(Options{Proxy: "not a url"}); err == nil {
+		t.Error("expected error for invalid proxy")
+	}
+	if err := Configure(Options{CABundle: "/nonexistent/ca.pem"}); err == nil {
+		t.Error("expected error for missing CA bundle")
+	}
+
+	empty := filepath.Join(t.TempDir(), "empty.pem")
+	os.WriteFile(empty, []byte("not a certificate"), 0644)
+	if err := Configure(Options{CABundle: empty}); err == nil {
+		t.Error("expected error for CA bundle without certificates")
+	}
+}
@@ -0,0 +1,58 @@
+package learning
+
+import (
+	"math"
+	"time"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/storage"
+)
+
+const (
+	// affinityWindow is how far back per-query outcomes are considered.
+	affinityWindow = 30 * 24 * time.Hour
+
+	// affinityConfidence is the number of selections at which a query's
+	// history carries ~63% of its full weight.
+	affinityConfidence = 3.0
+)
+
+// AffinityStore returns per-query tool outcomes. *storage.SQLiteStorage
+// satisfies it.
+type AffinityStore interface {
+	GetQueryAffinity(queryHash string, since time.Time) ([]storage.ToolAffinity, error)
+}
+
+// ContextualScore rates a tool for one query from the outcomes of earlier
+// executions after the same query (0-1). The smoothed success rate is
+// scaled by confidence, so a single lucky call counts for little and a
+// tool that keeps failing sinks.
+func ContextualScore(a storage.ToolAffinity) float64 {
+	if a.Selections <= 0 {
+		return 0
+	}
+	successRate := float64(a.Successes+1) / float64(a.Selections+2)
+	confidence := 1 - math.Exp(-float64(a.Selections)/affinityConfidence)
+	return successRate * confidence
+}
+
+// QueryAffinity returns contextual scores keyed by "server/tool" for a
+// query, learned from what was executed after earlier identical searches.
+func QueryAffinity(query string, store AffinityStore) map[string]float64 {
+	if store == nil || query == "" {
+		return nil
+	}
+
+	affinities, err := store.GetQueryAffinity(storage.HashQuery(query), time.Now().Add(-affinityWindow))
+	if err != nil || len(affinities) == 0 {
+		return nil
+	}
+
+	scores := make(map[string]float64, len(affinities))
+	for _, a := range affinities {
+		if a.Server == "" {
+			continue
+		}
+		scores[a.Server+"/"+a.ToolName] = ContextualScore(a)
+	}
+	return scores
+}
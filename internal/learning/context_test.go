@@ -0,0 +1,54 @@
+package learning
+
+import (
+	"testing"
+	"time"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/storage"
+)
+
+func TestContextualScore(t *testing.T) {
+	none := ContextualScore(storage.ToolAffinity{})
+	once := ContextualScore(storage.ToolAffinity{Selections: 1, Successes: 1})
+	often := ContextualScore(storage.ToolAffinity{Selections: 10, Successes: 10})
+	failing := ContextualScore(storage.ToolAffinity{Selections: 10, Successes: 1})
+
+	if none != 0 {
+		t.Errorf("no history should score 0, got %f", none)
+	}
+	if !(often > once && once > 0) {
+		t.Errorf("more successful selections should score higher: once=%f often=%f", once, often)
+	}
+	if failing >= often/2 {
+		t.Errorf("a mostly failing tool should score low: failing=%f often=%f", failing, often)
+	}
+	if often > 1 {
+		t.Errorf("score should stay within 0-1, got %f", often)
+	}
+}
+
+type affinityStoreFunc func(queryHash string, since time.Time) ([]storage.ToolAffinity, error)
+
+func (f affinityStoreFunc) GetQueryAffinity(queryHash string, since time.Time) ([]storage.ToolAffinity, error) {
+	return f(queryHash, since)
+}
+
+func TestQueryAffinity(t *testing.T) {
+	store := affinityStoreFunc(func(queryHash string, since time.Time) ([]storage.ToolAffinity, error) {
+		if queryHash != storage.HashQuery("create issue") {
+			return nil, nil
+		}
+		return []storage.ToolAffinity{
+			{Server: "linear", ToolName: "create_issue", Selections: 4, Successes: 4},
+			{ToolName: "legacy_tool", Selections: 9, Successes: 9},
+		}, nil
+	})
+
+	scores := QueryAffinity("create issue", store)
+	if len(scores) != 1 || scores["linear/create_issue"] <= 0 {
+		t.Errorf("expected a score for linear/create_issue only, got %v", scores)
+	}
+	if scores := QueryAffinity("other", store); len(scores) != 0 {
+		t.Errorf("expected no scores for an unseen query, got %v", scores)
+	}
+}
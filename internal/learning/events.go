@@ -37,6 +37,12 @@ type UsageEvent struct {
 
 	// SearchID is the search session identifier (optional).
 	SearchID string
+
+	// Success indicates whether the tool execution completed without error.
+	Success bool
+
+	// Server is the server that ran the tool (optional).
+	Server string
 }
 
 // NewUsageEvent creates a new usage event for tracking.
@@ -61,6 +67,9 @@ func (e UsageEvent) ToStorage() storage.UsageEvent {
 		Selected:       e.Selected,
 		Rating:         e.Rating,
 		WasRecommended: e.WasRecommended,
+		Success:        e.Success,
+		Server:         e.Server,
+		SearchID:       e.SearchID,
 	}
 }
 
@@ -25,10 +25,18 @@ const (
 
 	// maxRating is the maximum possible rating (for normalization).
 	maxRating = 5.0
+
+	// failurePenalty is how much of the score a tool loses when every
+	// recorded execution failed (0.8 = up to 80%).
+	failurePenalty = 0.8
+
+	// minOutcomeSamples is how many executions a tool needs before its
+	// failure rate affects the score, so one bad call isn't punished.
+	minOutcomeSamples = 3
 )
 
 // Score calculates a tool's score based on usage history.
-// Formula: 0.6*frequency + 0.3*recency + 0.1*rating
+// Formula: (0.6*frequency + 0.3*recency + 0.1*rating) * (1 - 0.8*failureRate)
 func Score(toolName string, history []storage.UsageEvent) float64 {
 	if len(history) == 0 {
 		return 0.0
@@ -42,9 +50,35 @@ func Score(toolName string, history []storage.UsageEvent) float64 {
 	// Combined score
 	score := frequencyWeight*freq + recencyWeight*recency + ratingWeight*rating
 
+	// Penalize tools that frequently fail when executed
+	score *= 1 - failurePenalty*calculateFailureRate(toolName, history)
+
 	return score
 }
 
+// calculateFailureRate returns the share of the tool's executions that
+// failed (0-1). Returns 0 until the tool has minOutcomeSamples executions.
+func calculateFailureRate(toolName string, history []storage.UsageEvent) float64 {
+	total := 0
+	failed := 0
+
+	for _, event := range history {
+		if event.ToolName != toolName {
+			continue
+		}
+		total++
+		if !event.Success {
+			failed++
+		}
+	}
+
+	if total < minOutcomeSamples {
+		return 0.0
+	}
+
+	return float64(failed) / float64(total)
+}
+
 // calculateFrequency measures how often a tool is used (normalized 0-1).
 // Counts usage in the last 7 days, normalized by a reasonable max (e.g., 100 uses).
 func calculateFrequency(toolName string, history []storage.UsageEvent) float64 {
@@ -362,3 +362,53 @@ func (e *errorMockStorage) RecordSearch(search storage.SearchRecord) error {
 func (e *errorMockStorage) Cleanup(retention time.Duration) error {
 	return nil
 }
+
+func TestCalculateFailureRate(t *testing.T) {
+	now := time.Now()
+	history := []storage.UsageEvent{
+		{ToolName: "tool_a", Timestamp: now, Success: false},
+		{ToolName: "tool_a", Timestamp: now, Success: true},
+	}
+
+	// Too few executions to judge
+	if rate := calculateFailureRate("tool_a", history); rate != 0.0 {
+		t.Errorf("expected no failure rate below %d samples, got %f", minOutcomeSamples, rate)
+	}
+
+	history = append(history,
+		storage.UsageEvent{ToolName: "tool_a", Timestamp: now, Success: false},
+		storage.UsageEvent{ToolName: "tool_a", Timestamp: now, Success: false},
+		storage.UsageEvent{ToolName: "tool_b", Timestamp: now, Success: false},
+	)
+
+	if rate := calculateFailureRate("tool_a", history); rate != 0.75 {
+		t.Errorf("expected failure rate 0.75, got %f", rate)
+	}
+}
+
+func TestRankTools_PenalizesFailures(t *testing.T) {
+	mockStore := newMockStorage()
+	now := time.Now()
+
+	for i := 0; i < 5; i++ {
+		mockStore.RecordUsage(storage.UsageEvent{
+			ToolName:  "flaky",
+			Timestamp: now.Add(-time.Hour),
+			Success:   false,
+		})
+		mockStore.RecordUsage(storage.UsageEvent{
+			ToolName:  "reliable",
+			Timestamp: now.Add(-time.Hour),
+			Success:   true,
+		})
+	}
+
+	result := RankTools([]string{"flaky", "reliable"}, mockStore)
+
+	if len(result) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(result))
+	}
+	if result[0].ToolName != "reliable" {
+		t.Errorf("expected 'reliable' ranked above failing tool, got '%s'", result[0].ToolName)
+	}
+}
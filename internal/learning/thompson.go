@@ -0,0 +1,149 @@
+package learning
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/storage"
+)
+
+// ThompsonSampling implements Thompson sampling for tool selection.
+// Each tool's success probability is modelled as Beta(successes+1,
+// failures+1); a draw from every posterior decides the ranking, so tools
+// with little history are explored in proportion to their uncertainty
+// instead of at a fixed rate like ε-greedy.
+type ThompsonSampling struct {
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// NewThompsonSampling creates a Thompson sampling bandit. A non-zero seed
+// makes its draws reproducible; zero seeds from the current time.
+func NewThompsonSampling(seed int64) *ThompsonSampling {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &ThompsonSampling{rng: rand.New(rand.NewSource(seed))}
+}
+
+// Sample draws a success probability from Beta(successes+1, failures+1).
+func (t *ThompsonSampling) Sample(successes, failures int) float64 {
+	if successes < 0 {
+		successes = 0
+	}
+	if failures < 0 {
+		failures = 0
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	x := t.gamma(float64(successes + 1))
+	y := t.gamma(float64(failures + 1))
+	return x / (x + y)
+}
+
+// gamma draws from Gamma(shape, 1) for shape >= 1 using the
+// Marsaglia-Tsang method. Callers must hold t.mu.
+func (t *ThompsonSampling) gamma(shape float64) float64 {
+	d := shape - 1.0/3.0
+	c := 1 / math.Sqrt(9*d)
+	for {
+		x := t.rng.NormFloat64()
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		u := t.rng.Float64()
+		if math.Log(u) < 0.5*x*x+d-d*v+d*math.Log(v) {
+			return d * v
+		}
+	}
+}
+
+// SelectTool selects the tool with the highest posterior draw, using each
+// tool's execution outcomes over the frequency window.
+func (t *ThompsonSampling) SelectTool(toolNames []string, storage storage.Storage) string {
+	ranked := t.SelectRankedTools(toolNames, storage)
+	if len(ranked) == 0 {
+		return ""
+	}
+	return ranked[0]
+}
+
+// SelectRankedTools returns tools ordered by one posterior draw each.
+func (t *ThompsonSampling) SelectRankedTools(toolNames []string, storage storage.Storage) []string {
+	if len(toolNames) == 0 {
+		return []string{}
+	}
+
+	scores := make([]ToolScore, 0, len(toolNames))
+	for _, toolName := range toolNames {
+		history, err := storage.GetUsageHistory(toolName, time.Now().Add(-frequencyWindow))
+		if err != nil {
+			history = nil
+		}
+		successes, failures := countOutcomes(toolName, history)
+		scores = append(scores, ToolScore{
+			ToolName: toolName,
+			Score:    t.Sample(successes, failures),
+		})
+	}
+
+	// Sort by sampled score descending
+	for i := 0; i < len(scores); i++ {
+		for j := i + 1; j < len(scores); j++ {
+			if scores[j].Score > scores[i].Score {
+				scores[i], scores[j] = scores[j], scores[i]
+			}
+		}
+	}
+
+	result := make([]string, len(scores))
+	for i, score := range scores {
+		result[i] = score.ToolName
+	}
+	return result
+}
+
+// SampleQueryAffinity is the Thompson counterpart of QueryAffinity: each
+// tool executed after earlier identical searches gets a draw from the
+// Beta posterior of its outcomes in that query context, keyed by
+// "server/tool".
+func (t *ThompsonSampling) SampleQueryAffinity(query string, store AffinityStore) map[string]float64 {
+	if store == nil || query == "" {
+		return nil
+	}
+
+	affinities, err := store.GetQueryAffinity(storage.HashQuery(query), time.Now().Add(-affinityWindow))
+	if err != nil || len(affinities) == 0 {
+		return nil
+	}
+
+	scores := make(map[string]float64, len(affinities))
+	for _, a := range affinities {
+		if a.Server == "" || a.Selections <= 0 {
+			continue
+		}
+		scores[a.Server+"/"+a.ToolName] = t.Sample(a.Successes, a.Selections-a.Successes)
+	}
+	return scores
+}
+
+// countOutcomes tallies successful and failed executions of a tool.
+func countOutcomes(toolName string, history []storage.UsageEvent) (successes, failures int) {
+	for _, event := range history {
+		if event.ToolName != toolName {
+			continue
+		}
+		if event.Success {
+			successes++
+		} else {
+			failures++
+		}
+	}
+	return successes, failures
+}
@@ -0,0 +1,91 @@
+package learning
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/storage"
+)
+
+func TestThompsonSampling_DeterministicSeed(t *testing.T) {
+	a := NewThompsonSampling(42)
+	b := NewThompsonSampling(42)
+
+	for i := 0; i < 10; i++ {
+		if x, y := a.Sample(3, 2), b.Sample(3, 2); x != y {
+			t.Fatalf("draw %d differs with the same seed: %f != %f", i, x, y)
+		}
+	}
+}
+
+func TestThompsonSampling_SampleMean(t *testing.T) {
+	ts := NewThompsonSampling(7)
+
+	const draws = 5000
+	sum := 0.0
+	for i := 0; i < draws; i++ {
+		x := ts.Sample(8, 0)
+		if x < 0 || x > 1 {
+			t.Fatalf("sample out of range: %f", x)
+		}
+		sum += x
+	}
+
+	// Beta(9, 1) has mean 0.9
+	if mean := sum / draws; math.Abs(mean-0.9) > 0.02 {
+		t.Errorf("expected mean ~0.9, got %f", mean)
+	}
+}
+
+func TestThompsonSampling_SelectRankedTools(t *testing.T) {
+	mockStore := newMockStorage()
+	now := time.Now()
+
+	for i := 0; i < 30; i++ {
+		mockStore.RecordUsage(storage.UsageEvent{ToolName: "reliable", Timestamp: now, Success: true})
+		mockStore.RecordUsage(storage.UsageEvent{ToolName: "flaky", Timestamp: now, Success: i%10 == 0})
+	}
+
+	ts := NewThompsonSampling(1)
+	wins := 0
+	for i := 0; i < 100; i++ {
+		if ts.SelectTool([]string{"flaky", "reliable"}, mockStore) == "reliable" {
+			wins++
+		}
+	}
+	if wins < 95 {
+		t.Errorf("expected reliable tool to win almost always, won %d/100", wins)
+	}
+
+	if got := ts.SelectRankedTools(nil, mockStore); len(got) != 0 {
+		t.Errorf("expected empty ranking, got %v", got)
+	}
+}
+
+// affinityStore returns fixed per-query outcomes.
+type affinityStore []storage.ToolAffinity
+
+func (a affinityStore) GetQueryAffinity(queryHash string, since time.Time) ([]storage.ToolAffinity, error) {
+	return a, nil
+}
+
+func TestThompsonSampling_SampleQueryAffinity(t *testing.T) {
+	store := affinityStore{
+		{Server: "jira", ToolName: "create_issue", Selections: 20, Successes: 20},
+		{Server: "linear", ToolName: "create_issue", Selections: 20, Successes: 0},
+		{ToolName: "legacy", Selections: 3, Successes: 3},
+	}
+
+	scores := NewThompsonSampling(3).SampleQueryAffinity("create issue", store)
+
+	if len(scores) != 2 {
+		t.Fatalf("expected 2 scored tools, got %v", scores)
+	}
+	if scores["jira/create_issue"] <= scores["linear/create_issue"] {
+		t.Errorf("expected succeeding tool to outscore failing one: %v", scores)
+	}
+	if NewThompsonSampling(3).SampleQueryAffinity("", store) != nil {
+		t.Error("expected no scores for an empty query")
+	}
+}
@@ -0,0 +1,44 @@
+package mcp
+
+import (
+	"sort"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/learning"
+	"github.com/khanglvm/tool-hub-mcp/internal/search"
+)
+
+// affinityBoost is how much a fully confident query history can raise a
+// result's score (0.5 = up to +50%).
+const affinityBoost = 0.5
+
+// applyQueryAffinity re-ranks results using what was executed after
+// earlier searches for the same query, so "create issue" can favor jira
+// for one user and linear for another. With Thompson sampling the boost
+// is a posterior draw, so uncertain tools still get explored.
+func (p *SearchPipeline) applyQueryAffinity(query string, results []search.SearchResult) []search.SearchResult {
+	if p.history == nil || len(results) < 2 {
+		return results
+	}
+
+	var scores map[string]float64
+	if p.thompson != nil {
+		scores = p.thompson.SampleQueryAffinity(query, p.history)
+	} else {
+		scores = learning.QueryAffinity(query, p.history)
+	}
+	if len(scores) == 0 {
+		return results
+	}
+
+	reranked := make([]search.SearchResult, len(results))
+	copy(reranked, results)
+	for i := range reranked {
+		if affinity, ok := scores[toolID(reranked[i])]; ok {
+			reranked[i].Score *= 1 + affinityBoost*affinity
+		}
+	}
+	sort.SliceStable(reranked, func(i, j int) bool {
+		return reranked[i].Score > reranked[j].Score
+	})
+	return reranked
+}
@@ -0,0 +1,73 @@
+package mcp
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/protocol"
+)
+
+func TestQueryAffinityReranksByPastSelections(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg := &config.Config{Servers: map[string]*config.ServerConfig{
+		"jira":   {Command: "echo"},
+		"linear": {Command: "echo"},
+	}}
+	server := NewServer(cfg)
+	defer server.Close()
+	if server.indexer == nil || server.tracker == nil {
+		t.Skip("indexer or learning storage unavailable")
+	}
+
+	tool := func(desc string) []protocol.Tool {
+		return []protocol.Tool{{Name: "create_issue", Description: desc, InputSchema: json.RawMessage(`{}`)}}
+	}
+	server.indexer.IndexServer("jira", tool("Create an issue in a Jira issue tracker project"))
+	server.indexer.IndexServer("linear", tool("Create an issue in Linear"))
+
+	search := func(query string) (string, []string) {
+		out, err := server.execHubSearch(query, "", 10)
+		if err != nil {
+			t.Fatalf("search failed: %v", err)
+		}
+		var resp struct {
+			SearchID string `json:"searchId"`
+			Results  []struct {
+				ID string `json:"id"`
+			} `json:"results"`
+		}
+		if err := json.Unmarshal([]byte(out), &resp); err != nil {
+			t.Fatalf("invalid response: %v", err)
+		}
+		var ids []string
+		for _, r := range resp.Results {
+			ids = append(ids, r.ID)
+		}
+		return resp.SearchID, ids
+	}
+
+	searchID, ids := search("create issue")
+	if len(ids) != 2 {
+		t.Fatalf("expected 2 results, got %v", ids)
+	}
+	second := ids[1]
+	_, unrelated := search("create")
+
+	// This user keeps picking the lower-ranked tool after "create issue"
+	picked := strings.TrimSuffix(second, "/create_issue")
+	for i := 0; i < 5; i++ {
+		server.trackUsage(picked, "create_issue", searchID, true)
+	}
+	server.tracker.Stop()
+
+	if _, ids = search("create issue"); ids[0] != second {
+		t.Errorf("expected %s first after learning, got %v", second, ids)
+	}
+	// Other queries are unaffected
+	if _, ids = search("create"); ids[0] != unrelated[0] {
+		t.Errorf("expected unchanged ranking for another query, got %v (was %v)", ids, unrelated)
+	}
+}
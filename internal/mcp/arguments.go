@@ -0,0 +1,146 @@
+package mcp
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+)
+
+// pathFormats are inputSchema "format" values that mark a string property
+// as a file path, letting tools opt in to argument offloading themselves.
+var pathFormats = map[string]bool{
+	"path":      true,
+	"file-path": true,
+	"filepath":  true,
+}
+
+// argumentBudget returns the per-argument size limit in bytes (0 = unlimited).
+// Thread-safe: acquires read lock.
+func (s *Server) argumentBudget() int {
+	s.configMu.RLock()
+	defer s.configMu.RUnlock()
+
+	return s.config.Settings.ArgumentBudget()
+}
+
+// offloadLargeArguments guards the child's stdin framing against huge
+// arguments. String arguments over the budget are written to temp files
+// and replaced with the file path when the argument accepts a path (per
+// ServerConfig.PathArguments or a path format in the tool's inputSchema);
+// otherwise an argument_too_large ToolError is returned.
+// The returned cleanup removes any temp files and is always non-nil.
+func (s *Server) offloadLargeArguments(serverName string, cfg *config.ServerConfig, toolName string, args map[string]interface{}) (map[string]interface{}, func(), error) {
+	cleanup := func() {}
+
+	budget := s.argumentBudget()
+	if budget <= 0 {
+		return args, cleanup, nil
+	}
+
+	var oversized []string
+	for name, value := range args {
+		if str, ok := value.(string); ok && len(str) > budget {
+			oversized = append(oversized, name)
+		}
+	}
+	if len(oversized) == 0 {
+		return args, cleanup, nil
+	}
+	sort.Strings(oversized)
+
+	// Schema annotations are only looked up if the config doesn't decide
+	var schemaPaths map[string]bool
+
+	rewritten := make(map[string]interface{}, len(args))
+	for name, value := range args {
+		rewritten[name] = value
+	}
+
+	var files []string
+	cleanup = func() {
+		for _, f := range files {
+			if err := os.Remove(f); err != nil && !os.IsNotExist(err) {
+				log.Printf("Warning: failed to remove offloaded argument file %s: %v", f, err)
+			}
+		}
+	}
+
+	for _, name := range oversized {
+		value := args[name].(string)
+		if !cfg.AcceptsPath(toolName, name) && schemaPaths == nil {
+			schemaPaths = s.schemaPathArguments(serverName, cfg, toolName)
+		}
+		if !cfg.AcceptsPath(toolName, name) && !schemaPaths[name] {
+			cleanup()
+			return nil, func() {}, &ToolError{
+				Code: "argument_too_large",
+				Message: fmt.Sprintf("argument '%s' is %d bytes, over the %d byte limit, and tool '%s' does not accept a file path for it",
+					name, len(value), budget, toolName),
+				Details: map[string]interface{}{
+					"argument": name,
+					"bytes":    len(value),
+					"maxBytes": budget,
+				},
+			}
+		}
+
+		path, err := writeArgumentFile(value)
+		if err != nil {
+			cleanup()
+			return nil, func() {}, fmt.Errorf("failed to offload argument '%s': %w", name, err)
+		}
+		files = append(files, path)
+		rewritten[name] = path
+		log.Printf("Offloaded %d byte argument '%s' for %s/%s to %s", len(value), name, serverName, toolName, path)
+	}
+
+	return rewritten, cleanup, nil
+}
+
+// schemaPathArguments returns the tool's string properties annotated with a
+// path format. Schema lookup failures are logged and treated as no annotations.
+func (s *Server) schemaPathArguments(serverName string, cfg *config.ServerConfig, toolName string) map[string]bool {
+	paths := make(map[string]bool)
+
+	tools, err := s.spawner.GetTools(serverName, cfg)
+	if err != nil {
+		log.Printf("Warning: failed to read schema for %s/%s: %v", serverName, toolName, err)
+		return paths
+	}
+
+	for _, tool := range tools {
+		if tool.Name != toolName {
+			continue
+		}
+		schema, _ := tool.InputSchema.(map[string]interface{})
+		properties, _ := schema["properties"].(map[string]interface{})
+		for name, raw := range properties {
+			prop, _ := raw.(map[string]interface{})
+			if format, _ := prop["format"].(string); pathFormats[format] {
+				paths[name] = true
+			}
+		}
+	}
+	return paths
+}
+
+// writeArgumentFile stores an argument value in a private temp file.
+func writeArgumentFile(value string) (string, error) {
+	f, err := os.CreateTemp("", "tool-hub-arg-*")
+	if err != nil {
+		return "", err
+	}
+	if _, err := f.WriteString(value); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
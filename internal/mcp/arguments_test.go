@@ -0,0 +1,77 @@
+package mcp
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+)
+
+func TestOffloadLargeArgumentsConfigDriven(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg := config.NewConfig()
+	cfg.Settings.MaxArgumentBytes = 8
+	serverCfg := &config.ServerConfig{
+		Command:       "echo",
+		PathArguments: map[string][]string{"upload": {"content"}},
+	}
+	cfg.Servers["files"] = serverCfg
+	server := NewServer(cfg)
+	defer server.Close()
+
+	args := map[string]interface{}{"content": "a large file body", "name": "small"}
+	rewritten, cleanup, err := server.offloadLargeArguments("files", serverCfg, "upload", args)
+	if err != nil {
+		t.Fatalf("offloadLargeArguments failed: %v", err)
+	}
+
+	path, _ := rewritten["content"].(string)
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "a large file body" {
+		t.Fatalf("offloaded file = %q, %v", data, err)
+	}
+	if rewritten["name"] != "small" {
+		t.Error("small arguments should be passed through")
+	}
+	if args["content"] != "a large file body" {
+		t.Error("caller's arguments should not be modified")
+	}
+
+	cleanup()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("cleanup should remove the offloaded file")
+	}
+}
+
+func TestOffloadLargeArgumentsSchemaDriven(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	script := writeFakeServer(t, "1.0.0", `[{"name":"upload","inputSchema":{"type":"object","properties":{"file":{"type":"string","format":"path"},"text":{"type":"string"}}}}]`)
+	cfg := config.NewConfig()
+	cfg.Settings.MaxArgumentBytes = 8
+	serverCfg := &config.ServerConfig{Command: "sh", Args: []string{script}}
+	cfg.Servers["files"] = serverCfg
+	server := NewServer(cfg)
+	defer server.Close()
+
+	rewritten, cleanup, err := server.offloadLargeArguments("files", serverCfg, "upload", map[string]interface{}{"file": strings.Repeat("x", 64)})
+	if err != nil {
+		t.Fatalf("annotated path argument should be offloaded: %v", err)
+	}
+	defer cleanup()
+	if path, _ := rewritten["file"].(string); !strings.Contains(path, "tool-hub-arg-") {
+		t.Errorf("expected temp file path, got %q", path)
+	}
+
+	_, _, err = server.offloadLargeArguments("files", serverCfg, "upload", map[string]interface{}{"text": strings.Repeat("x", 64)})
+	var toolErr *ToolError
+	if !errors.As(err, &toolErr) || toolErr.Code != "argument_too_large" {
+		t.Fatalf("expected argument_too_large, got %v", err)
+	}
+	if toolErr.Details["argument"] != "text" || toolErr.Details["maxBytes"] != 8 {
+		t.Errorf("unexpected details: %v", toolErr.Details)
+	}
+}
@@ -0,0 +1,75 @@
+package mcp
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/redact"
+	"github.com/khanglvm/tool-hub-mcp/internal/storage"
+)
+
+// redactedValue replaces secret argument values in the audit log.
+const redactedValue = redact.Placeholder
+
+// auditCall identifies an audited hub_execute call. ExecutionID and the
+// searchId the client passed link the call into its search's trace.
+type auditCall struct {
+	Server      string
+	Tool        string
+	Args        map[string]interface{}
+	SearchID    string
+	ExecutionID string
+}
+
+// recordAudit writes a hub_execute call to the audit log.
+func (s *Server) recordAudit(call auditCall, started time.Time, callErr error) {
+	s.configMu.RLock()
+	enabled := s.config.Settings.AuditEnabled()
+	withArgs := s.config.Settings.AuditArguments()
+	s.configMu.RUnlock()
+
+	if !enabled || s.storage == nil {
+		return
+	}
+
+	args := call.Args
+	if args == nil {
+		args = map[string]interface{}{}
+	}
+	argsJSON, _ := json.Marshal(args)
+
+	entry := storage.AuditEntry{
+		Timestamp:   started,
+		Server:      call.Server,
+		Tool:        call.Tool,
+		ArgsHash:    storage.HashQuery(string(argsJSON)),
+		DurationMs:  time.Since(started).Milliseconds(),
+		Success:     callErr == nil,
+		ExecutionID: call.ExecutionID,
+		SearchID:    call.SearchID,
+	}
+	if callErr != nil {
+		entry.Error = redact.String(callErr.Error())
+	}
+	if withArgs {
+		redacted, _ := json.Marshal(redactArguments(args))
+		entry.Args = string(redacted)
+	}
+
+	s.storage.RecordAudit(entry)
+}
+
+// auditQueries reports whether hub_search query text is stored for traces.
+// Thread-safe: acquires read lock.
+func (s *Server) auditQueries() bool {
+	s.configMu.RLock()
+	defer s.configMu.RUnlock()
+
+	return s.config.Settings.AuditQueries()
+}
+
+// redactArguments returns a copy of value with secret-looking keys and
+// known secret values redacted at any depth.
+func redactArguments(value interface{}) interface{} {
+	return redact.Value(value)
+}
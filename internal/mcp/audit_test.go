@@ -0,0 +1,77 @@
+package mcp
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/storage"
+)
+
+func TestRedactArguments(t *testing.T) {
+	args := map[string]interface{}{
+		"query":  "open issues",
+		"apiKey": "sk-123",
+		"nested": map[string]interface{}{"auth-token": "abc", "page": 2.0},
+		"items":  []interface{}{map[string]interface{}{"password": "p"}},
+	}
+
+	redacted := redactArguments(args).(map[string]interface{})
+	if redacted["query"] != "open issues" || redacted["apiKey"] != redactedValue {
+		t.Errorf("unexpected top-level redaction: %v", redacted)
+	}
+	nested := redacted["nested"].(map[string]interface{})
+	if nested["auth-token"] != redactedValue || nested["page"] != 2.0 {
+		t.Errorf("unexpected nested redaction: %v", nested)
+	}
+	item := redacted["items"].([]interface{})[0].(map[string]interface{})
+	if item["password"] != redactedValue {
+		t.Errorf("secrets in arrays should be redacted: %v", item)
+	}
+	if args["apiKey"] != "sk-123" {
+		t.Error("original arguments must not be modified")
+	}
+}
+
+func TestHubExecuteIsAudited(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg := config.NewConfig()
+	cfg.Settings.Audit = &config.AuditSettings{RecordArguments: true}
+	server := NewServer(cfg)
+	defer server.Close()
+
+	server.handleToolsCall(&MCPRequest{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  "tools/call",
+		Params:  json.RawMessage(`{"name":"hub_execute","arguments":{"server":"missing","tool":"search","arguments":{"q":"x","token":"secret"}}}`),
+	})
+
+	entries, err := server.storage.GetAuditEntries(storage.AuditFilter{})
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected one audit entry, got %+v, %v", entries, err)
+	}
+	e := entries[0]
+	if e.Server != "missing" || e.Tool != "search" || e.Success || e.Error == "" {
+		t.Errorf("unexpected audit entry: %+v", e)
+	}
+	if e.ArgsHash == "" || e.Args != `{"q":"x","token":"[REDACTED]"}` {
+		t.Errorf("expected hash and redacted args, got %+v", e)
+	}
+}
+
+func TestRecordAuditDisabled(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg := config.NewConfig()
+	cfg.Settings.Audit = &config.AuditSettings{Disabled: true}
+	server := NewServer(cfg)
+	defer server.Close()
+
+	server.recordAudit(auditCall{Server: "jira", Tool: "search"}, time.Now(), nil)
+	if entries, _ := server.storage.GetAuditEntries(storage.AuditFilter{}); len(entries) != 0 {
+		t.Errorf("disabled audit log should record nothing, got %+v", entries)
+	}
+}
@@ -0,0 +1,170 @@
+package mcp
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/protocol"
+)
+
+// maxCachedResults bounds memory held by cached results; the oldest entry
+// is evicted first.
+const maxCachedResults = 256
+
+// cachedResult is a child tools/call result kept for reuse.
+type cachedResult struct {
+	response interface{}
+	expires  time.Time
+}
+
+// resultCache reuses hub_execute results of read-only tools on servers
+// that opted in with a cache setting, keyed by server, tool and a hash of
+// the arguments. Thread-safe.
+type resultCache struct {
+	mu      sync.Mutex
+	entries map[string]*cachedResult
+	order   []string // keys, oldest first
+
+	// readOnly holds "server/tool" ids the servers annotated as read-only
+	readOnly map[string]bool
+}
+
+// newResultCache creates an empty result cache.
+func newResultCache() *resultCache {
+	return &resultCache{
+		entries:  make(map[string]*cachedResult),
+		readOnly: make(map[string]bool),
+	}
+}
+
+// cacheKey identifies a call. encoding/json sorts map keys, so equal
+// arguments hash equally whatever their order.
+func cacheKey(serverName, toolName string, args map[string]interface{}) (string, bool) {
+	data, err := json.Marshal(args)
+	if err != nil {
+		return "", false
+	}
+	sum := sha256.Sum256(data)
+	return serverName + "/" + toolName + "#" + hex.EncodeToString(sum[:]), true
+}
+
+// markReadOnly records which of a server's tools are annotated read-only,
+// replacing what was recorded for it before.
+func (c *resultCache) markReadOnly(serverName string, tools []protocol.Tool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	prefix := serverName + "/"
+	for id := range c.readOnly {
+		if strings.HasPrefix(id, prefix) {
+			delete(c.readOnly, id)
+		}
+	}
+	for _, tool := range tools {
+		if tool.ReadOnly() {
+			c.readOnly[prefix+tool.Name] = true
+		}
+	}
+}
+
+// cacheable returns the TTL for a tool's results, or 0 if they are not
+// cached: the server must opt in, and the tool must match one of its
+// patterns or be annotated read-only.
+func (c *resultCache) cacheable(serverName string, server *config.ServerConfig, toolName string) time.Duration {
+	if server.Cache == nil {
+		return 0
+	}
+	ttl, err := server.Cache.Duration()
+	if err != nil {
+		return 0
+	}
+
+	c.mu.Lock()
+	readOnly := c.readOnly[serverName+"/"+toolName]
+	c.mu.Unlock()
+	if readOnly {
+		return ttl
+	}
+	for _, pattern := range server.Cache.Tools {
+		if ok, _ := path.Match(strings.ToLower(pattern), strings.ToLower(toolName)); ok {
+			return ttl
+		}
+	}
+	return 0
+}
+
+// get returns a cached response, or false if none is fresh.
+func (c *resultCache) get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	return entry.response, true
+}
+
+// put stores a response for ttl.
+func (c *resultCache) put(key string, response interface{}, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.entries[key]; !exists {
+		for len(c.order) >= maxCachedResults {
+			delete(c.entries, c.order[0])
+			c.order = c.order[1:]
+		}
+		c.order = append(c.order, key)
+	}
+	c.entries[key] = &cachedResult{response: response, expires: time.Now().Add(ttl)}
+}
+
+// invalidate drops cached results of one server, or of all servers when
+// serverName is empty.
+func (c *resultCache) invalidate(serverName string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	prefix := serverName + "/"
+	kept := c.order[:0]
+	for _, key := range c.order {
+		if serverName == "" || strings.HasPrefix(key, prefix) {
+			delete(c.entries, key)
+			continue
+		}
+		kept = append(kept, key)
+	}
+	c.order = kept
+}
+
+// cachedCall returns a cached response for the call if there is one.
+// Otherwise it returns a store function that caches a successful response
+// (nil when the tool's results are not cached).
+func (s *Server) cachedCall(serverName string, server *config.ServerConfig, toolName string, args map[string]interface{}) (interface{}, bool, func(interface{})) {
+	ttl := s.cache.cacheable(serverName, server, toolName)
+	if ttl == 0 {
+		return nil, false, nil
+	}
+	key, ok := cacheKey(serverName, toolName, args)
+	if !ok {
+		return nil, false, nil
+	}
+	if response, hit := s.cache.get(key); hit {
+		log.Printf("Cache hit for %s/%s", serverName, toolName)
+		return response, true, nil
+	}
+	return nil, false, func(response interface{}) {
+		// Failures are not reused: the next call may succeed
+		if !isErrorResult(response) {
+			s.cache.put(key, response, ttl)
+		}
+	}
+}
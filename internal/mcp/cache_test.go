@@ -0,0 +1,89 @@
+package mcp
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/mcptest"
+	"github.com/khanglvm/tool-hub-mcp/internal/protocol"
+)
+
+// writeCountingServer writes a fake MCP server whose tools/call result
+// reports how many calls it has received.
+func writeCountingServer(t *testing.T, toolsJSON string) string {
+	t.Helper()
+	return mcptest.Script(t, mcptest.Handlers{
+		"initialize": mcptest.Result(`{}`),
+		"tools/list": mcptest.Result(`{"tools":` + toolsJSON + `}`),
+		"tools/call": "n=$((n+1)); " + mcptest.Result(`{"content":[{"type":"text","text":"call '$n'"}]}`),
+	})
+}
+
+func TestHubExecuteCachesReadOnlyTools(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	script := writeCountingServer(t, `[{"name":"get_issue"},{"name":"fetch_page","annotations":{"readOnlyHint":true}},{"name":"create_issue"}]`)
+	cfg := config.NewConfig()
+	cfg.Servers["jira"] = &config.ServerConfig{
+		Command: "sh",
+		Args:    []string{script},
+		Cache:   &config.CacheSettings{Tools: []string{"get_*"}},
+	}
+	server := NewServer(cfg)
+	defer server.Close()
+	if err := server.IndexTools(); err != nil {
+		t.Fatalf("IndexTools failed: %v", err)
+	}
+
+	call := func(tool string, args map[string]interface{}) string {
+		t.Helper()
+		out, err := server.execHubExecute(nil, "jira", tool, args, "", nil)
+		if err != nil {
+			t.Fatalf("hub_execute %s failed: %v", tool, err)
+		}
+		return out.(string)
+	}
+
+	first := call("get_issue", map[string]interface{}{"key": "A-1", "fields": "all"})
+	if again := call("get_issue", map[string]interface{}{"fields": "all", "key": "A-1"}); again != first {
+		t.Errorf("pattern-matched tool not cached: %q then %q", first, again)
+	}
+	if other := call("get_issue", map[string]interface{}{"key": "A-2"}); other == first {
+		t.Error("different arguments must not share a cache entry")
+	}
+
+	annotated := call("fetch_page", nil)
+	if again := call("fetch_page", nil); again != annotated {
+		t.Errorf("readOnlyHint tool not cached: %q then %q", annotated, again)
+	}
+
+	write := call("create_issue", nil)
+	if again := call("create_issue", nil); again == write || !strings.Contains(again, "call") {
+		t.Errorf("write tool must not be cached: %q then %q", write, again)
+	}
+
+	server.cache.invalidate("jira")
+	if fresh := call("get_issue", map[string]interface{}{"key": "A-1", "fields": "all"}); fresh == first {
+		t.Error("invalidate should drop the server's cached results")
+	}
+}
+
+func TestResultCacheWithoutOptIn(t *testing.T) {
+	cache := newResultCache()
+	cache.markReadOnly("jira", []protocol.Tool{{Name: "get_issue", Annotations: map[string]interface{}{"readOnlyHint": true}}})
+
+	if ttl := cache.cacheable("jira", &config.ServerConfig{}, "get_issue"); ttl != 0 {
+		t.Errorf("servers without a cache setting must not be cached, got ttl %v", ttl)
+	}
+	server := &config.ServerConfig{Cache: &config.CacheSettings{TTL: "1m"}}
+	if ttl := cache.cacheable("jira", server, "get_issue"); ttl != time.Minute {
+		t.Errorf("ttl = %v, want 1m", ttl)
+	}
+
+	cache.put("jira/get_issue#x", "old", -time.Second)
+	if _, ok := cache.get("jira/get_issue#x"); ok {
+		t.Error("expired entries must not be returned")
+	}
+}
@@ -0,0 +1,151 @@
+package mcp
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/spawner"
+)
+
+// circuit is the failure record of one server.
+type circuit struct {
+	failures  int
+	openUntil time.Time
+	lastError string
+}
+
+// circuitBreaker takes servers that keep failing to spawn or answer out
+// of service for a cooldown, so calls fail fast instead of each waiting
+// for a spawn or response timeout. Thread-safe.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int // 0 disables the breaker
+	cooldown  time.Duration
+	circuits  map[string]*circuit
+	now       func() time.Time
+}
+
+// newCircuitBreaker creates a breaker from the circuit breaker settings.
+func newCircuitBreaker(settings *config.Settings) *circuitBreaker {
+	b := &circuitBreaker{circuits: make(map[string]*circuit), now: time.Now}
+	b.configure(settings)
+	return b
+}
+
+// configure applies the circuit breaker settings. Invalid cooldowns fall
+// back to the default (validation reports them).
+func (b *circuitBreaker) configure(settings *config.Settings) {
+	breaker := settings.CircuitBreakerConfig()
+	cooldown, err := breaker.CooldownDuration()
+	if err != nil {
+		log.Printf("Warning: %v, using default cooldown", err)
+		cooldown = config.DefaultCircuitCooldown
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.threshold = breaker.FailureThreshold()
+	b.cooldown = cooldown
+}
+
+// openUntil returns when a server's open circuit closes again, or false
+// if calls to it may proceed (always for a nil breaker).
+func (b *circuitBreaker) openUntil(name string) (time.Time, bool) {
+	if b == nil {
+		return time.Time{}, false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c, ok := b.circuits[name]
+	if !ok || b.threshold <= 0 || !b.now().Before(c.openUntil) {
+		return time.Time{}, false
+	}
+	return c.openUntil, true
+}
+
+// success closes a server's circuit.
+func (b *circuitBreaker) success(name string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.circuits, name)
+}
+
+// failure records a failed spawn or call. Reaching the threshold opens
+// the circuit; while the failure streak lasts, a failed attempt after the
+// cooldown opens it again right away.
+func (b *circuitBreaker) failure(name string, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c, ok := b.circuits[name]
+	if !ok {
+		c = &circuit{}
+		b.circuits[name] = c
+	}
+	c.failures++
+	c.lastError = err.Error()
+	if b.threshold > 0 && c.failures >= b.threshold {
+		c.openUntil = b.now().Add(b.cooldown)
+		log.Printf("Warning: %s failed %d times in a row, circuit open until %s", name, c.failures, c.openUntil.Format(time.RFC3339))
+	}
+}
+
+// record updates a server's circuit with the outcome of a spawn or call.
+// Errors the child answered with, and the hub's own child limit, say
+// nothing about the server's health and are not counted.
+func (b *circuitBreaker) record(name string, err error) {
+	var rpcErr *spawner.RPCError
+	var limitErr *spawner.ChildLimitError
+	switch {
+	case err == nil:
+		b.success(name)
+	case errors.As(err, &rpcErr), errors.As(err, &limitErr):
+	default:
+		b.failure(name, err)
+	}
+}
+
+// reset forgets a server's failures, e.g. after its config changed.
+func (b *circuitBreaker) reset(name string) {
+	b.success(name)
+}
+
+// unavailableError builds the server_unavailable ToolError for a call to
+// a server whose circuit is open.
+func (b *circuitBreaker) unavailableError(name string, until time.Time) *ToolError {
+	b.mu.Lock()
+	lastError := ""
+	failures := 0
+	if c, ok := b.circuits[name]; ok {
+		lastError, failures = c.lastError, c.failures
+	}
+	b.mu.Unlock()
+
+	seconds := int(math.Ceil(until.Sub(b.now()).Seconds()))
+	return &ToolError{
+		Code: "server_unavailable",
+		Message: fmt.Sprintf("server '%s' unavailable (circuit open until %s) after %d consecutive failures",
+			name, until.Format(time.RFC3339), failures),
+		Details: map[string]interface{}{
+			"server":            name,
+			"openUntil":         until.Format(time.RFC3339),
+			"retryAfterSeconds": seconds,
+			"lastError":         lastError,
+		},
+	}
+}
+
+// markDegraded flags a search result of a server whose circuit is open,
+// so the model can prefer an alternative until it closes.
+func (s *Server) markDegraded(result map[string]interface{}, serverName string) {
+	if until, open := s.circuits.openUntil(serverName); open {
+		result["degraded"] = true
+		result["unavailableUntil"] = until.Format(time.RFC3339)
+	}
+}
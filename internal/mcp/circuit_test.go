@@ -0,0 +1,92 @@
+package mcp
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/search"
+	"github.com/khanglvm/tool-hub-mcp/internal/spawner"
+)
+
+func TestCircuitBreakerOpensAndCloses(t *testing.T) {
+	settings := &config.Settings{CircuitBreaker: &config.CircuitBreakerSettings{Threshold: 2, Cooldown: "1m"}}
+	b := newCircuitBreaker(settings)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	b.now = func() time.Time { return now }
+
+	failure := errors.New("failed to read response: EOF")
+	b.record("jira", failure)
+	if _, open := b.openUntil("jira"); open {
+		t.Fatal("circuit should stay closed below the threshold")
+	}
+
+	// Errors the child answered with do not count
+	b.record("jira", &spawner.RPCError{Code: -32602, Message: "invalid params"})
+	b.record("jira", failure)
+	until, open := b.openUntil("jira")
+	if !open || !until.Equal(now.Add(time.Minute)) {
+		t.Fatalf("expected circuit open until %v, got %v (open=%v)", now.Add(time.Minute), until, open)
+	}
+
+	// After the cooldown one attempt goes through; failing reopens at once
+	now = now.Add(time.Minute)
+	if _, open := b.openUntil("jira"); open {
+		t.Fatal("circuit should let a call through after the cooldown")
+	}
+	b.record("jira", failure)
+	if _, open := b.openUntil("jira"); !open {
+		t.Fatal("a failure after the cooldown should reopen the circuit")
+	}
+
+	now = now.Add(time.Minute)
+	b.record("jira", nil)
+	b.record("jira", failure)
+	if _, open := b.openUntil("jira"); open {
+		t.Error("a success should reset the failure count")
+	}
+}
+
+func TestCircuitBreakerDisabled(t *testing.T) {
+	b := newCircuitBreaker(&config.Settings{CircuitBreaker: &config.CircuitBreakerSettings{Disabled: true}})
+	for i := 0; i < 10; i++ {
+		b.record("jira", errors.New("EOF"))
+	}
+	if _, open := b.openUntil("jira"); open {
+		t.Error("disabled breaker should never open")
+	}
+}
+
+func TestHubExecuteFailsFastWhenCircuitOpen(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg := config.NewConfig()
+	cfg.Settings.CircuitBreaker = &config.CircuitBreakerSettings{Threshold: 2}
+	cfg.Servers["jira"] = &config.ServerConfig{Command: "/nonexistent/jira-mcp"}
+	server := NewServer(cfg)
+	defer server.Close()
+
+	for i := 0; i < 2; i++ {
+		_, err := server.execHubExecute(nil, "jira", "get_issue", nil, "", nil)
+		var toolErr *ToolError
+		if err == nil || errors.As(err, &toolErr) {
+			t.Fatalf("call %d: expected a spawn failure, got %v", i+1, err)
+		}
+	}
+
+	_, err := server.execHubExecute(nil, "jira", "get_issue", nil, "", nil)
+	var toolErr *ToolError
+	if !errors.As(err, &toolErr) || toolErr.Code != "server_unavailable" {
+		t.Fatalf("expected server_unavailable, got %v", err)
+	}
+	if !strings.Contains(toolErr.Message, "circuit open until") || toolErr.Details["retryAfterSeconds"] == nil {
+		t.Errorf("unexpected error: %+v", toolErr)
+	}
+
+	formatted := server.formatSearchResults([]search.SearchResult{{ToolName: "get_issue", ServerName: "jira"}}, false, true)
+	if formatted[0]["degraded"] != true || formatted[0]["unavailableUntil"] == nil {
+		t.Errorf("expected search result marked degraded, got %v", formatted[0])
+	}
+}
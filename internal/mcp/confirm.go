@@ -0,0 +1,95 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/redact"
+)
+
+// destructivePattern returns the first pattern a tool matches, or "" when
+// it may run without confirmation. Patterns containing "/" match
+// "server/tool"; others match the tool name alone.
+func destructivePattern(patterns []string, serverName, toolName string) string {
+	tool := strings.ToLower(toolName)
+	qualified := strings.ToLower(serverName) + "/" + tool
+	for _, pattern := range patterns {
+		lower := strings.ToLower(pattern)
+		target := tool
+		if strings.Contains(lower, "/") {
+			target = qualified
+		}
+		if ok, _ := path.Match(lower, target); ok {
+			return pattern
+		}
+	}
+	return ""
+}
+
+// requiredConfirmation returns the destructive pattern a call matches
+// under the current settings, or "".
+func (s *Server) requiredConfirmation(serverName, toolName string) string {
+	s.configMu.RLock()
+	patterns := s.config.Settings.DestructivePatterns()
+	s.configMu.RUnlock()
+	return destructivePattern(patterns, serverName, toolName)
+}
+
+// checkConfirmation returns a confirmation_required ToolError for a
+// destructive tool called without confirm: true, so an agent misfire never
+// reaches the child. The response echoes the (redacted) call to confirm.
+func (s *Server) checkConfirmation(serverName, toolName string, args map[string]interface{}, confirm bool) error {
+	if confirm {
+		return nil
+	}
+	pattern := s.requiredConfirmation(serverName, toolName)
+	if pattern == "" {
+		return nil
+	}
+	return &ToolError{
+		Code: "confirmation_required",
+		Message: fmt.Sprintf("tool '%s' on server '%s' is destructive (matches '%s') and was not run. "+
+			"Confirm with the user, then call hub_execute again with the same arguments and confirm: true",
+			toolName, serverName, pattern),
+		Details: map[string]interface{}{
+			"server":    serverName,
+			"tool":      toolName,
+			"pattern":   pattern,
+			"arguments": redact.Value(args),
+		},
+	}
+}
+
+// execDryRun describes the call hub_execute would make without running it.
+func (s *Server) execDryRun(serverName, toolName string, args map[string]interface{}) (string, error) {
+	s.configMu.RLock()
+	server, exists := s.config.Servers[serverName]
+	s.configMu.RUnlock()
+
+	if !exists {
+		return "", s.serverNotFound(serverName)
+	}
+	if server.Disabled {
+		return "", fmt.Errorf("server '%s' is disabled", serverName)
+	}
+
+	plan := map[string]interface{}{
+		"dryRun":               true,
+		"server":               serverName,
+		"tool":                 toolName,
+		"arguments":            redact.Value(args),
+		"requiresConfirmation": false,
+	}
+	if pattern := s.requiredConfirmation(serverName, toolName); pattern != "" {
+		plan["requiresConfirmation"] = true
+		plan["pattern"] = pattern
+	}
+
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode dry run: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,78 @@
+package mcp
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+)
+
+func TestDestructivePattern(t *testing.T) {
+	cases := []struct {
+		patterns []string
+		server   string
+		tool     string
+		want     string
+	}{
+		{config.DefaultDestructivePatterns, "db", "delete_row", "delete_*"},
+		{config.DefaultDestructivePatterns, "db", "DROP_TABLE", "drop_*"},
+		{config.DefaultDestructivePatterns, "jira", "issue_remove", "*_remove"},
+		{config.DefaultDestructivePatterns, "jira", "create_issue", ""},
+		{[]string{"github/*_repo"}, "github", "archive_repo", "github/*_repo"},
+		{[]string{"github/*_repo"}, "gitlab", "archive_repo", ""},
+	}
+	for _, c := range cases {
+		if got := destructivePattern(c.patterns, c.server, c.tool); got != c.want {
+			t.Errorf("destructivePattern(%v, %s, %s) = %q, want %q", c.patterns, c.server, c.tool, got, c.want)
+		}
+	}
+}
+
+func TestHubExecuteRequiresConfirmation(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	script := writeFakeCallServer(t, `{"content":[{"type":"text","text":"deleted"}]}`)
+	cfg := config.NewConfig()
+	cfg.Servers["db"] = &config.ServerConfig{Command: "sh", Args: []string{script}}
+	server := NewServer(cfg)
+	defer server.Close()
+
+	call := func(extra string) string {
+		t.Helper()
+		resp, err := server.handleToolsCall(&MCPRequest{
+			JSONRPC: "2.0",
+			ID:      1,
+			Method:  "tools/call",
+			Params:  json.RawMessage(`{"name":"hub_execute","arguments":{"server":"db","tool":"delete_row","arguments":{"id":7,"apiToken":"x"}` + extra + `}}`),
+		})
+		if err != nil || resp.Error != nil {
+			t.Fatalf("hub_execute failed: %v %+v", err, resp.Error)
+		}
+		data, _ := json.Marshal(resp.Result)
+		return string(data)
+	}
+
+	pending := call("")
+	if !strings.Contains(pending, "confirmation_required") || !strings.Contains(pending, `"isError":true`) {
+		t.Errorf("expected confirmation_required error result, got %s", pending)
+	}
+	if strings.Contains(pending, "deleted") || strings.Contains(pending, `\"apiToken\":\"x\"`) {
+		t.Errorf("pending response ran the tool or leaked a secret: %s", pending)
+	}
+
+	preview := call(`,"dryRun":true`)
+	if !strings.Contains(preview, `\"requiresConfirmation\": true`) || strings.Contains(preview, "deleted") {
+		t.Errorf("unexpected dry run result: %s", preview)
+	}
+
+	if confirmed := call(`,"confirm":true`); !strings.Contains(confirmed, "deleted") {
+		t.Errorf("confirmed call did not run the tool: %s", confirmed)
+	}
+
+	// Disabling the policy runs destructive tools directly
+	server.config.Settings.Confirmation = &config.ConfirmationSettings{Disabled: true}
+	if direct := call(""); !strings.Contains(direct, "deleted") {
+		t.Errorf("expected tool to run with the policy disabled: %s", direct)
+	}
+}
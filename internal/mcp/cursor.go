@@ -0,0 +1,47 @@
+package mcp
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/storage"
+)
+
+// searchCursor is the decoded form of a hub_search nextCursor. It binds an
+// offset to the query it was issued for, so a cursor cannot silently be
+// replayed against a different search.
+type searchCursor struct {
+	Query  string `json:"q"`
+	Offset int    `json:"o"`
+}
+
+// cursorQueryKey returns the short query fingerprint stored in cursors.
+func cursorQueryKey(query, serverFilter string) string {
+	return storage.HashQuery(normalizeSearchQuery(query, serverFilter))[:16]
+}
+
+// encodeSearchCursor returns an opaque cursor for the next page.
+func encodeSearchCursor(query, serverFilter string, offset int) string {
+	data, _ := json.Marshal(searchCursor{Query: cursorQueryKey(query, serverFilter), Offset: offset})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeSearchCursor returns the offset encoded in a cursor, verifying that
+// it belongs to the same query and server filter.
+func decodeSearchCursor(cursor, query, serverFilter string) (int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cursor")
+	}
+
+	var c searchCursor
+	if err := json.Unmarshal(data, &c); err != nil || c.Offset < 0 {
+		return 0, fmt.Errorf("invalid cursor")
+	}
+	if c.Query != cursorQueryKey(query, serverFilter) {
+		return 0, fmt.Errorf("cursor belongs to a different query; repeat the original query and server with it")
+	}
+
+	return c.Offset, nil
+}
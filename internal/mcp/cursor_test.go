@@ -0,0 +1,68 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/protocol"
+)
+
+func TestSearchCursorRoundTrip(t *testing.T) {
+	cursor := encodeSearchCursor("all tools", "", 20)
+
+	offset, err := decodeSearchCursor(cursor, "  All Tools ", "")
+	if err != nil || offset != 20 {
+		t.Errorf("decode = %d, %v; want 20", offset, err)
+	}
+
+	if _, err := decodeSearchCursor(cursor, "other query", ""); err == nil {
+		t.Error("expected error for cursor from a different query")
+	}
+	if _, err := decodeSearchCursor("%%%", "all tools", ""); err == nil {
+		t.Error("expected error for malformed cursor")
+	}
+}
+
+func TestHubSearchPagination(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	server := NewServer(&config.Config{Servers: map[string]*config.ServerConfig{}})
+	defer server.Close()
+	if server.indexer == nil {
+		t.Skip("indexer not available")
+	}
+
+	tools := make([]protocol.Tool, 5)
+	for i := range tools {
+		tools[i] = protocol.Tool{Name: fmt.Sprintf("issue_%d", i), Description: "Issue tool", InputSchema: json.RawMessage(`{}`)}
+	}
+	server.indexer.IndexServer("jira", tools)
+
+	seen := 0
+	req := searchRequest{Query: "issue", Limit: 2}
+	for pages := 0; pages < 5; pages++ {
+		result, err := server.runHubSearch(req)
+		if err != nil {
+			t.Fatalf("search failed: %v", err)
+		}
+		var data map[string]interface{}
+		json.Unmarshal([]byte(result), &data)
+
+		seen += len(data["results"].([]interface{}))
+		if data["totalMatches"] != float64(5) {
+			t.Errorf("expected totalMatches 5, got %v", data["totalMatches"])
+		}
+
+		cursor, ok := data["nextCursor"].(string)
+		if !ok {
+			break
+		}
+		req.Cursor = cursor
+	}
+
+	if seen != 5 {
+		t.Errorf("expected to page through 5 results, saw %d", seen)
+	}
+}
@@ -0,0 +1,129 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/search"
+)
+
+// equivalentSimilarity is the minimum word overlap (Jaccard) between two
+// descriptions, server names excluded, for same-named tools to be grouped.
+const equivalentSimilarity = 0.8
+
+// toolID returns the canonical "server/tool" identifier of a search result.
+func toolID(r search.SearchResult) string {
+	return r.ServerName + "/" + r.ToolName
+}
+
+// resultGroup is a ranked result plus the equivalent tools folded into it.
+type resultGroup struct {
+	search.SearchResult
+	alternatives []search.SearchResult
+}
+
+// dedupeResults groups tools that share a name and have near-identical
+// descriptions (e.g. create_issue on two trackers). Each group keeps the
+// rank of its best result.
+func dedupeResults(results []search.SearchResult) []resultGroup {
+	groups := make([]resultGroup, 0, len(results))
+	words := make([]map[string]bool, 0, len(results))
+
+	for _, r := range results {
+		rw := descriptionWords(r)
+		merged := false
+		for i := range groups {
+			if groups[i].ToolName == r.ToolName && jaccard(words[i], rw) >= equivalentSimilarity {
+				groups[i].alternatives = append(groups[i].alternatives, r)
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			groups = append(groups, resultGroup{SearchResult: r})
+			words = append(words, rw)
+		}
+	}
+	return groups
+}
+
+// descriptionWords returns the lowercased words of a tool description,
+// without the words of its server name so "Create a Jira issue" and
+// "Create a GitHub issue" compare as equal.
+func descriptionWords(r search.SearchResult) map[string]bool {
+	split := func(s string) []string {
+		return strings.FieldsFunc(strings.ToLower(s), func(c rune) bool {
+			return !unicode.IsLetter(c) && !unicode.IsDigit(c)
+		})
+	}
+
+	skip := make(map[string]bool)
+	for _, w := range split(splitCamelCase(r.ServerName)) {
+		skip[w] = true
+	}
+	skip[strings.ToLower(r.ServerName)] = true
+
+	words := make(map[string]bool)
+	for _, w := range split(r.Description) {
+		if !skip[w] {
+			words[w] = true
+		}
+	}
+	return words
+}
+
+// splitCamelCase inserts spaces at lower-to-upper case boundaries
+// ("jiraCloud" → "jira Cloud").
+func splitCamelCase(s string) string {
+	var b strings.Builder
+	prev := rune(0)
+	for _, c := range s {
+		if unicode.IsUpper(c) && unicode.IsLower(prev) {
+			b.WriteRune(' ')
+		}
+		b.WriteRune(c)
+		prev = c
+	}
+	return b.String()
+}
+
+// jaccard returns |a ∩ b| / |a ∪ b|; two empty sets are identical.
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	shared := 0
+	for w := range a {
+		if b[w] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// disambiguationHints returns a hint per result ID for tool names offered
+// by more than one server in the result set, naming the other servers.
+func disambiguationHints(results []search.SearchResult) map[string]string {
+	servers := make(map[string][]string)
+	for _, r := range results {
+		servers[r.ToolName] = append(servers[r.ToolName], r.ServerName)
+	}
+
+	hints := make(map[string]string)
+	for _, r := range results {
+		all := servers[r.ToolName]
+		if len(all) < 2 {
+			continue
+		}
+		others := make([]string, 0, len(all)-1)
+		for _, name := range all {
+			if name != r.ServerName {
+				others = append(others, name)
+			}
+		}
+		hints[toolID(r)] = fmt.Sprintf("%s on %s (also offered by %s); call hub_execute with server %q",
+			r.ToolName, r.ServerName, strings.Join(others, ", "), r.ServerName)
+	}
+	return hints
+}
@@ -0,0 +1,59 @@
+package mcp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/search"
+)
+
+func TestDedupeResults(t *testing.T) {
+	results := []search.SearchResult{
+		{ToolName: "create_issue", Description: "Create a new Jira issue", ServerName: "jira", Score: 3},
+		{ToolName: "create_issue", Description: "Create a new GitHub issue", ServerName: "github", Score: 2},
+		{ToolName: "create_issue", Description: "File a bug report with screenshots", ServerName: "bugTracker", Score: 1.5},
+		{ToolName: "search", Description: "Search issues", ServerName: "jira", Score: 1},
+	}
+
+	groups := dedupeResults(results)
+	if len(groups) != 3 {
+		t.Fatalf("expected 3 groups, got %d: %+v", len(groups), groups)
+	}
+	if toolID(groups[0].SearchResult) != "jira/create_issue" || len(groups[0].alternatives) != 1 ||
+		groups[0].alternatives[0].ServerName != "github" {
+		t.Errorf("expected github folded into jira, got %+v", groups[0])
+	}
+	if groups[1].ServerName != "bugTracker" || len(groups[1].alternatives) != 0 {
+		t.Errorf("different description should not be grouped: %+v", groups[1])
+	}
+}
+
+func TestFormatSearchResultsHints(t *testing.T) {
+	s := &Server{}
+	results := []search.SearchResult{
+		{ToolName: "create_issue", Description: "Create a new Jira issue", ServerName: "jira", Score: 3},
+		{ToolName: "create_issue", Description: "Create a new GitHub issue", ServerName: "github", Score: 2},
+		{ToolName: "search", Description: "Search issues", ServerName: "jira", Score: 1},
+	}
+
+	formatted := s.formatSearchResults(results, false, false)
+	if len(formatted) != 3 || formatted[0]["id"] != "jira/create_issue" {
+		t.Fatalf("unexpected results: %v", formatted)
+	}
+	hint, _ := formatted[0]["hint"].(string)
+	if !strings.Contains(hint, "github") || !strings.Contains(hint, `"jira"`) {
+		t.Errorf("hint should name the other server and the one to call, got %q", hint)
+	}
+	if _, ok := formatted[2]["hint"]; ok {
+		t.Errorf("unique tool name should have no hint: %v", formatted[2])
+	}
+
+	deduped := s.formatSearchResults(results, true, false)
+	if len(deduped) != 2 {
+		t.Fatalf("expected 2 deduped results, got %d", len(deduped))
+	}
+	alts, _ := deduped[0]["alternatives"].([]map[string]interface{})
+	if len(alts) != 1 || alts[0]["id"] != "github/create_issue" {
+		t.Errorf("expected github alternative, got %v", deduped[0]["alternatives"])
+	}
+}
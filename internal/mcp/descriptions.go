@@ -0,0 +1,93 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Meta-tool description verbosities (settings.descriptions.verbosity).
+const (
+	verbosityFull    = "full"
+	verbosityCompact = "compact"
+	verbosityMinimal = "minimal"
+)
+
+// verboseSections start description paragraphs that compact descriptions
+// leave out: when-to-use lists and examples the model can do without.
+var verboseSections = []string{"CALL THIS FIRST", "USE THIS TOOL", "EXAMPLES", "ANTI-PATTERNS", "LEARNING"}
+
+// listServers joins server names for a description. Past limit (0 means
+// no limit) the remaining names are counted instead.
+func listServers(names []string, limit int) string {
+	if limit <= 0 || len(names) <= limit {
+		return strings.Join(names, ", ")
+	}
+	return fmt.Sprintf("%s and %d more (hub_status lists all)", strings.Join(names[:limit], ", "), len(names)-limit)
+}
+
+// trimDescription shortens a meta-tool description to a verbosity.
+// Descriptions are paragraphs separated by blank lines, the first being a
+// one-line summary: compact drops the verbose sections, minimal keeps
+// only the summary.
+func trimDescription(description, verbosity string) string {
+	paragraphs := strings.Split(description, "\n\n")
+	switch verbosity {
+	case verbosityMinimal:
+		return paragraphs[0]
+	case verbosityCompact:
+		kept := paragraphs[:1]
+		for _, paragraph := range paragraphs[1:] {
+			if !isVerboseSection(paragraph) {
+				kept = append(kept, paragraph)
+			}
+		}
+		return strings.Join(kept, "\n\n")
+	}
+	return description
+}
+
+// isVerboseSection reports whether a paragraph is one compact drops.
+func isVerboseSection(paragraph string) bool {
+	for _, prefix := range verboseSections {
+		if strings.HasPrefix(paragraph, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// trimMetaTools applies the description settings to the meta-tools:
+// descriptions are shortened to the configured verbosity, and when there
+// are more servers than descriptions list, the server enums are dropped
+// as well (hub_execute still accepts any registered name or alias).
+func (s *Server) trimMetaTools(tools []map[string]interface{}) {
+	s.configMu.RLock()
+	verbosity := s.config.Settings.DescriptionVerbosity()
+	limit := s.config.Settings.DescriptionServerLimit()
+	servers := len(s.config.Servers)
+	s.configMu.RUnlock()
+
+	for _, tool := range tools {
+		if description, ok := tool["description"].(string); ok {
+			tool["description"] = trimDescription(description, verbosity)
+		}
+		if limit > 0 && servers > limit {
+			dropServerEnums(tool)
+		}
+	}
+}
+
+// dropServerEnums removes the server name enums from a meta-tool's
+// server and servers arguments.
+func dropServerEnums(tool map[string]interface{}) {
+	schema, _ := tool["inputSchema"].(map[string]interface{})
+	properties, _ := schema["properties"].(map[string]interface{})
+	if server, ok := properties["server"].(map[string]interface{}); ok {
+		delete(server, "enum")
+	}
+	if servers, ok := properties["servers"].(map[string]interface{}); ok {
+		if items, ok := servers["items"].(map[string]interface{}); ok {
+			delete(items, "enum")
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+)
+
+func TestListServers(t *testing.T) {
+	names := []string{"a", "b", "c", "d"}
+	if got := listServers(names, 0); got != "a, b, c, d" {
+		t.Errorf("uncapped list = %q", got)
+	}
+	if got := listServers(names, 2); got != "a, b and 2 more (hub_status lists all)" {
+		t.Errorf("capped list = %q", got)
+	}
+}
+
+func TestTrimDescription(t *testing.T) {
+	description := "Run a tool.\n\nUSE THIS TOOL AFTER:\n• searching\n\nIMPORTANT: search first.\n\nEXAMPLES:\n• x\n\nCURRENTLY REGISTERED: jira"
+
+	if got := trimDescription(description, verbosityFull); got != description {
+		t.Errorf("full description changed: %q", got)
+	}
+	if got := trimDescription(description, verbosityCompact); got != "Run a tool.\n\nIMPORTANT: search first.\n\nCURRENTLY REGISTERED: jira" {
+		t.Errorf("compact description = %q", got)
+	}
+	if got := trimDescription(description, verbosityMinimal); got != "Run a tool." {
+		t.Errorf("minimal description = %q", got)
+	}
+}
+
+// metaTools returns the tools/list meta-tools by name.
+func metaTools(t *testing.T, server *Server) map[string]map[string]interface{} {
+	t.Helper()
+	resp, err := server.handleToolsList(&MCPRequest{ID: 1})
+	if err != nil || resp.Error != nil {
+		t.Fatalf("tools/list failed: %v %+v", err, resp.Error)
+	}
+	tools := make(map[string]map[string]interface{})
+	for _, tool := range resp.Result.(map[string]interface{})["tools"].([]map[string]interface{}) {
+		tools[tool["name"].(string)] = tool
+	}
+	return tools
+}
+
+func TestToolsListCapsServerList(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg := config.NewConfig()
+	cfg.Settings.Descriptions = &config.DescriptionSettings{MaxServers: 3}
+	for i := 0; i < 5; i++ {
+		cfg.Servers[fmt.Sprintf("server%d", i)] = &config.ServerConfig{Command: "/nonexistent/server", Disabled: true}
+	}
+	server := NewServer(cfg)
+	defer server.Close()
+
+	execute := metaTools(t, server)["hub_execute"]
+	if description := execute["description"].(string); !strings.Contains(description, "server0, server1, server2 and 2 more") {
+		t.Errorf("expected a capped server list, got:\n%s", description)
+	}
+	serverArg := execute["inputSchema"].(map[string]interface{})["properties"].(map[string]interface{})["server"].(map[string]interface{})
+	if _, ok := serverArg["enum"]; ok {
+		t.Error("server enum should be dropped when the list is capped")
+	}
+}
+
+func TestToolsListMinimalVerbosity(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg := config.NewConfig()
+	cfg.Settings.Descriptions = &config.DescriptionSettings{Verbosity: "minimal"}
+	cfg.Servers["jira"] = &config.ServerConfig{Command: "/nonexistent/jira", Disabled: true}
+	server := NewServer(cfg)
+	defer server.Close()
+
+	for name, tool := range metaTools(t, server) {
+		if description := tool["description"].(string); strings.Contains(description, "\n") {
+			t.Errorf("%s: minimal description should be one line, got:\n%s", name, description)
+		}
+	}
+	serverArg := metaTools(t, server)["hub_execute"]["inputSchema"].(map[string]interface{})["properties"].(map[string]interface{})["server"].(map[string]interface{})
+	if _, ok := serverArg["enum"]; !ok {
+		t.Error("server enum should be kept below the server cap")
+	}
+}
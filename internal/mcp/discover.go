@@ -0,0 +1,67 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// execHubDiscover lists every tool a server offers, spawning it if needed.
+// Compact mode returns only names and one-line descriptions.
+func (s *Server) execHubDiscover(serverName string, compact bool) (string, error) {
+	s.configMu.RLock()
+	server, exists := s.config.Servers[serverName]
+	s.configMu.RUnlock()
+
+	if !exists {
+		return "", s.serverNotFound(serverName)
+	}
+	if server.Disabled {
+		return "", fmt.Errorf("server '%s' is disabled", serverName)
+	}
+
+	tools, err := s.spawner.GetTools(serverName, server)
+	if err != nil {
+		return "", fmt.Errorf("failed to list tools from '%s': %w", serverName, err)
+	}
+
+	entries := make([]map[string]interface{}, 0, len(tools))
+	for _, tool := range tools {
+		entry := map[string]interface{}{
+			"id":   serverName + "/" + tool.Name,
+			"name": tool.Name,
+		}
+		if compact {
+			entry["description"] = FirstLine(tool.Description)
+		} else {
+			entry["description"] = tool.Description
+			entry["inputSchema"] = tool.InputSchema
+		}
+		entries = append(entries, entry)
+	}
+
+	response := map[string]interface{}{
+		"server":     serverName,
+		"totalTools": len(tools),
+		"tools":      entries,
+	}
+	if compact {
+		response["hint"] = "Call hub_discover without compact, or hub_search, to get a tool's inputSchema."
+	}
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal response: %w", err)
+	}
+	return s.budgetResponse(string(data)), nil
+}
+
+// FirstLine returns the first non-empty line of text, trimmed.
+func FirstLine(text string) string {
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
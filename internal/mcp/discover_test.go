@@ -0,0 +1,95 @@
+package mcp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+)
+
+func TestHubDiscover(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	script := writeFakeServer(t, "1.0.0",
+		`[{"name":"search","description":"Search issues\\nSupports JQL.","inputSchema":{"type":"object"}},{"name":"create","description":"Create an issue"}]`)
+	cfg := config.NewConfig()
+	cfg.Servers["jira"] = &config.ServerConfig{Command: "sh", Args: []string{script}, Aliases: []string{"tickets"}}
+	server := NewServer(cfg)
+	defer server.Close()
+
+	var full struct {
+		Server     string                   `json:"server"`
+		TotalTools int                      `json:"totalTools"`
+		Tools      []map[string]interface{} `json:"tools"`
+	}
+	out, err := server.execHubDiscover("jira", false)
+	if err != nil {
+		t.Fatalf("execHubDiscover failed: %v", err)
+	}
+	if err := json.Unmarshal([]byte(out), &full); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if full.TotalTools != 2 || full.Tools[0]["id"] != "jira/search" || full.Tools[0]["inputSchema"] == nil {
+		t.Errorf("unexpected full listing: %s", out)
+	}
+
+	resp, _ := server.handleToolsCall(&MCPRequest{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  "tools/call",
+		Params:  json.RawMessage(`{"name":"hub_discover","arguments":{"server":"tickets","compact":true}}`),
+	})
+	if resp.Error != nil {
+		t.Fatalf("hub_discover via alias failed: %v", resp.Error)
+	}
+	text := resp.Result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	var compact struct {
+		Tools []map[string]interface{} `json:"tools"`
+	}
+	if err := json.Unmarshal([]byte(text), &compact); err != nil {
+		t.Fatalf("invalid JSON %q: %v", text, err)
+	}
+	if len(compact.Tools) != 2 || compact.Tools[0]["description"] != "Search issues" {
+		t.Errorf("compact mode should keep one-line descriptions: %s", text)
+	}
+	if _, ok := compact.Tools[0]["inputSchema"]; ok {
+		t.Errorf("compact mode should omit schemas: %s", text)
+	}
+
+	if _, err := server.execHubDiscover("missing", false); err == nil {
+		t.Error("expected error for unknown server")
+	}
+}
+
+func TestDisabledServerSkippedAndDiscoveryRecorded(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	script := writeFakeServer(t, "1.0.0", `[{"name":"search","description":"Search issues"}]`)
+	cfg := config.NewConfig()
+	cfg.Servers["jira"] = &config.ServerConfig{Command: "sh", Args: []string{script}}
+	cfg.Servers["github"] = &config.ServerConfig{Command: "sh", Args: []string{script}, Disabled: true}
+	server := NewServer(cfg)
+	defer server.Close()
+
+	if err := server.IndexTools(); err != nil {
+		t.Fatalf("IndexTools failed: %v", err)
+	}
+
+	statuses, err := server.storage.GetServerStatuses()
+	if err != nil {
+		t.Fatalf("GetServerStatuses failed: %v", err)
+	}
+	if statuses["jira"].ToolCount != 1 || statuses["jira"].LastSuccess.IsZero() {
+		t.Errorf("expected jira discovery recorded, got %+v", statuses["jira"])
+	}
+	if _, ok := statuses["github"]; ok {
+		t.Error("disabled server should not be discovered")
+	}
+
+	if _, err := server.execHubExecute(nil, "github", "search", nil, "", nil); err == nil {
+		t.Error("hub_execute should refuse a disabled server")
+	}
+	if _, err := server.execHubDiscover("github", false); err == nil {
+		t.Error("hub_discover should refuse a disabled server")
+	}
+}
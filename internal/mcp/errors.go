@@ -0,0 +1,53 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/redact"
+)
+
+// ToolError is an execution failure reported to the client as a structured
+// tool result (isError: true) instead of a JSON-RPC protocol error, so the
+// model can read the details and react (e.g., retry later).
+type ToolError struct {
+	// Code is a stable machine-readable error identifier (e.g., "rate_limited").
+	Code string `json:"error"`
+
+	// Message is a human-readable explanation.
+	Message string `json:"message"`
+
+	// Details carries error-specific fields (e.g., retryAfterSeconds).
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+// Error implements the error interface.
+func (e *ToolError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// JSON returns the compact JSON representation sent to the client.
+func (e *ToolError) JSON() string {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Sprintf(`{"error":%q,"message":%q}`, e.Code, e.Message)
+	}
+	return string(data)
+}
+
+// toolErrorResponse wraps a ToolError as an isError tool result.
+func toolErrorResponse(id interface{}, toolErr *ToolError) *MCPResponse {
+	return &MCPResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Result: map[string]interface{}{
+			"content": []map[string]interface{}{
+				{
+					"type": "text",
+					"text": redact.String(toolErr.JSON()),
+				},
+			},
+			"isError": true,
+		},
+	}
+}
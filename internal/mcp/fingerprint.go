@@ -0,0 +1,180 @@
+package mcp
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/protocol"
+)
+
+// indexUpToDate reports whether a server's indexed tools match fingerprint.
+func (s *Server) indexUpToDate(serverName, fingerprint string) bool {
+	s.fingerprintMu.Lock()
+	defer s.fingerprintMu.Unlock()
+	return fingerprint != "" && s.indexedFingerprints[serverName] == fingerprint
+}
+
+// indexFingerprint extends a tools fingerprint with the server's tags, so
+// that retagging a server reindexes its tools.
+func indexFingerprint(toolsFingerprint string, tags []string) string {
+	if toolsFingerprint == "" || len(tags) == 0 {
+		return toolsFingerprint
+	}
+	sorted := append([]string(nil), tags...)
+	sort.Strings(sorted)
+	return toolsFingerprint + "#" + strings.ToLower(strings.Join(sorted, ","))
+}
+
+// markIndexed records the fingerprint of a server's indexed tools.
+func (s *Server) markIndexed(serverName, fingerprint string) {
+	s.fingerprintMu.Lock()
+	defer s.fingerprintMu.Unlock()
+	s.indexedFingerprints[serverName] = fingerprint
+}
+
+// forgetIndexed drops a removed server's fingerprint so that re-adding it
+// indexes its tools again.
+func (s *Server) forgetIndexed(serverName string) {
+	s.fingerprintMu.Lock()
+	defer s.fingerprintMu.Unlock()
+	delete(s.indexedFingerprints, serverName)
+}
+
+// forgetAllIndexed drops every fingerprint after the index was cleared.
+func (s *Server) forgetAllIndexed() {
+	s.fingerprintMu.Lock()
+	defer s.fingerprintMu.Unlock()
+	s.indexedFingerprints = make(map[string]string)
+}
+
+// renameIndexed moves a server's fingerprint to its new name.
+func (s *Server) renameIndexed(oldName, newName string) {
+	s.fingerprintMu.Lock()
+	defer s.fingerprintMu.Unlock()
+	if fp, ok := s.indexedFingerprints[oldName]; ok {
+		delete(s.indexedFingerprints, oldName)
+		s.indexedFingerprints[newName] = fp
+	}
+}
+
+// refreshServerMetadata compares the running server's serverInfo and tool
+// fingerprint with the cached ServerMetadata and updates the cache only when
+// they differ. Returns true if the metadata changed and should be persisted.
+func (s *Server) refreshServerMetadata(serverName string, serverCfg *config.ServerConfig, tools []protocol.Tool, fingerprint string) bool {
+	info, _ := s.spawner.ServerInfo(serverName)
+
+	s.fingerprintMu.Lock()
+	defer s.fingerprintMu.Unlock()
+
+	old := serverCfg.Metadata
+	if old != nil && old.ToolsHash == fingerprint &&
+		old.ServerName == info.Name && old.ServerVersion == info.Version {
+		return false
+	}
+
+	names := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		names = append(names, tool.Name)
+	}
+	sort.Strings(names)
+
+	updated := &config.ServerMetadata{
+		Tools:         names,
+		LastUpdated:   time.Now().Format(time.RFC3339),
+		ServerName:    info.Name,
+		ServerVersion: info.Version,
+		ToolsHash:     fingerprint,
+	}
+	if old != nil {
+		updated.Description = old.Description
+
+		// Only report changes against a previously fingerprinted cache
+		if old.ToolsHash != "" {
+			log.Print(describeMetadataChange(serverName, old, updated))
+		}
+	}
+
+	serverCfg.Metadata = updated
+	return true
+}
+
+// describeMetadataChange summarizes an upgrade for the log, e.g.
+// "jira-mcp upgraded 1.2.0 → 1.3.0, 2 new tools".
+func describeMetadataChange(serverName string, old, updated *config.ServerMetadata) string {
+	name := updated.ServerName
+	if name == "" {
+		name = serverName
+	}
+
+	var parts []string
+	if old.ServerVersion != updated.ServerVersion {
+		parts = append(parts, fmt.Sprintf("%s upgraded %s → %s", name, versionOrUnknown(old.ServerVersion), versionOrUnknown(updated.ServerVersion)))
+	} else {
+		parts = append(parts, fmt.Sprintf("%s tools changed", name))
+	}
+
+	oldTools := make(map[string]bool, len(old.Tools))
+	for _, tool := range old.Tools {
+		oldTools[tool] = true
+	}
+	added := 0
+	for _, tool := range updated.Tools {
+		if !oldTools[tool] {
+			added++
+		}
+		delete(oldTools, tool)
+	}
+	removed := len(oldTools)
+
+	if added > 0 {
+		parts = append(parts, pluralize(added, "new tool"))
+	}
+	if removed > 0 {
+		parts = append(parts, pluralize(removed, "removed tool"))
+	}
+	if added == 0 && removed == 0 && old.ToolsHash != updated.ToolsHash {
+		parts = append(parts, "tool definitions updated")
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// persistMetadata saves refreshed metadata to the config file when tool
+// metadata caching is enabled (the default).
+func (s *Server) persistMetadata() {
+	if s.config.Settings != nil && !s.config.Settings.CacheToolMetadata {
+		return
+	}
+
+	configPath, err := config.GetDefaultConfigPath()
+	if err != nil {
+		log.Printf("Warning: failed to get config path: %v", err)
+		return
+	}
+
+	s.fingerprintMu.Lock()
+	defer s.fingerprintMu.Unlock()
+	if err := config.Save(s.config, configPath); err != nil {
+		log.Printf("Warning: failed to save tool metadata cache: %v", err)
+	}
+}
+
+// versionOrUnknown substitutes a placeholder for servers without a version.
+func versionOrUnknown(version string) string {
+	if version == "" {
+		return "unknown"
+	}
+	return version
+}
+
+// pluralize formats a count with a singular noun, adding "s" when needed.
+func pluralize(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,129 @@
+package mcp
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/mcptest"
+)
+
+// writeFakeServer writes a shell MCP server reporting the given version
+// and tools JSON, and returns its path.
+func writeFakeServer(t *testing.T, version, toolsJSON string) string {
+	t.Helper()
+	return mcptest.Script(t, mcptest.Handlers{
+		"initialize": mcptest.Result(`{"serverInfo":{"name":"jira-mcp","version":"` + version + `"}}`),
+		"tools/list": mcptest.Result(`{"tools":` + toolsJSON + `}`),
+	})
+}
+
+func TestDescribeMetadataChange(t *testing.T) {
+	old := &config.ServerMetadata{ServerVersion: "1.2.0", Tools: []string{"search"}, ToolsHash: "a"}
+	updated := &config.ServerMetadata{ServerName: "jira-mcp", ServerVersion: "1.3.0", Tools: []string{"create", "search", "update"}, ToolsHash: "b"}
+
+	got := describeMetadataChange("jira", old, updated)
+	if got != "jira-mcp upgraded 1.2.0 → 1.3.0, 2 new tools" {
+		t.Errorf("unexpected summary: %q", got)
+	}
+
+	same := &config.ServerMetadata{ServerVersion: "1.2.0", Tools: []string{"search"}, ToolsHash: "c"}
+	if got := describeMetadataChange("jira", old, same); got != "jira tools changed, tool definitions updated" {
+		t.Errorf("unexpected summary: %q", got)
+	}
+}
+
+func TestIndexToolsRefreshesMetadataOnFingerprintChange(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	script := writeFakeServer(t, "1.3.0", `[{"name":"search","description":"Search issues"}]`)
+	cfg := &config.Config{
+		Servers: map[string]*config.ServerConfig{
+			"jira": {
+				Command: "sh",
+				Args:    []string{script},
+				Metadata: &config.ServerMetadata{
+					ServerName:    "jira-mcp",
+					ServerVersion: "1.2.0",
+					Tools:         []string{"search"},
+					ToolsHash:     "stale",
+				},
+			},
+		},
+	}
+
+	server := NewServer(cfg)
+	defer server.Close()
+	if server.indexer == nil {
+		t.Skip("indexer not available")
+	}
+
+	if err := server.IndexTools(); err != nil {
+		t.Fatalf("IndexTools failed: %v", err)
+	}
+
+	meta := cfg.Servers["jira"].Metadata
+	if meta.ServerVersion != "1.3.0" || meta.ToolsHash == "stale" || meta.LastUpdated == "" {
+		t.Errorf("metadata not refreshed: %+v", meta)
+	}
+
+	// The refreshed cache is persisted to the config file
+	if _, err := os.Stat(filepath.Join(home, ".config", "tool-hub-mcp", "config.json")); err != nil {
+		t.Errorf("metadata cache not saved: %v", err)
+	}
+
+	// A second pass with an unchanged server keeps metadata and index as-is
+	lastUpdated := meta.LastUpdated
+	hash := meta.ToolsHash
+	if err := server.IndexTools(); err != nil {
+		t.Fatalf("second IndexTools failed: %v", err)
+	}
+	if cfg.Servers["jira"].Metadata.LastUpdated != lastUpdated {
+		t.Error("unchanged fingerprint should not rewrite metadata")
+	}
+	if !server.indexUpToDate("jira", hash) {
+		t.Error("index fingerprint not recorded")
+	}
+}
+
+func TestHubManageIndexesOnlyChangedServer(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	// The script counts its tools/list requests
+	counter := filepath.Join(t.TempDir(), "lists")
+	cfg := config.NewConfig()
+	cfg.Servers["counted"] = mcptest.Server(t, mcptest.Handlers{
+		"initialize": mcptest.Result(`{"serverInfo":{"name":"counted","version":"1.0.0"}}`),
+		"tools/list": "echo x >> " + counter + "; " +
+			mcptest.Result(`{"tools":[{"name":"count","inputSchema":{"type":"object"}}]}`),
+	})
+	lists := func() int {
+		data, _ := os.ReadFile(counter)
+		return strings.Count(string(data), "x")
+	}
+
+	server := NewServer(cfg)
+	defer server.Close()
+	if err := server.IndexTools(); err != nil {
+		t.Fatalf("IndexTools failed: %v", err)
+	}
+	before := lists()
+
+	jira := writeFakeServer(t, "1.0.0", `[{"name":"search","inputSchema":{"type":"object"}}]`)
+	if _, err := server.addServer("jira", "sh", []string{jira}, nil); err != nil {
+		t.Fatalf("addServer failed: %v", err)
+	}
+	if _, err := server.removeServer("jira"); err != nil {
+		t.Fatalf("removeServer failed: %v", err)
+	}
+	if got := lists(); got != before {
+		t.Errorf("adding and removing jira listed the other server's tools %d more times", got-before)
+	}
+
+	if results, _ := server.indexer.SearchBM25("count", 10); len(results) != 1 {
+		t.Errorf("expected the other server's tools to stay indexed, got %v", results)
+	}
+}
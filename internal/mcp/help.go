@@ -0,0 +1,119 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/search"
+	"github.com/khanglvm/tool-hub-mcp/internal/storage"
+)
+
+// maxHelpExamples bounds the argument examples hub_help returns.
+const maxHelpExamples = 3
+
+// helpExampleScan bounds the audit entries scanned for distinct examples.
+const helpExampleScan = 50
+
+// execHubHelp returns everything known about one tool: its input schema,
+// the output schema, title and annotations the child declared, the
+// arguments of recent successful calls from the audit log and the
+// invalid-params errors earlier calls got, so the model can copy a shape
+// that worked instead of guessing from the schema.
+func (s *Server) execHubHelp(serverName, toolName string) (string, error) {
+	s.configMu.RLock()
+	server, exists := s.config.Servers[serverName]
+	s.configMu.RUnlock()
+
+	if !exists {
+		return "", s.serverNotFound(serverName)
+	}
+	if server.Disabled {
+		return "", fmt.Errorf("server '%s' is disabled", serverName)
+	}
+	if toolName == "" {
+		return "", fmt.Errorf("tool is required")
+	}
+
+	tools, err := s.spawner.GetTools(serverName, server)
+	if err != nil {
+		return "", fmt.Errorf("failed to list tools from '%s': %w", serverName, err)
+	}
+
+	for _, tool := range tools {
+		if tool.Name != toolName {
+			continue
+		}
+		response := map[string]interface{}{
+			"id":          serverName + "/" + tool.Name,
+			"server":      serverName,
+			"name":        tool.Name,
+			"description": tool.Description,
+			"inputSchema": tool.InputSchema,
+		}
+		if example := search.ExampleArguments(tool.InputSchema); example != nil {
+			response["exampleArguments"] = example
+		}
+		if tool.Title != "" {
+			response["title"] = tool.Title
+		}
+		if tool.OutputSchema != nil {
+			response["outputSchema"] = tool.OutputSchema
+		}
+		if len(tool.Annotations) > 0 {
+			response["annotations"] = tool.Annotations
+		}
+		if mistakes := s.commonMistakes(serverName, tool.Name); len(mistakes) > 0 {
+			response["commonMistakes"] = mistakes
+		}
+		if examples := s.argumentExamples(serverName, tool.Name); len(examples) > 0 {
+			response["examples"] = examples
+		} else {
+			response["hint"] = "No recorded examples. Set settings.audit.recordArguments to true to learn examples from successful calls."
+		}
+
+		data, err := json.Marshal(response)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal response: %w", err)
+		}
+		return s.budgetResponse(string(data)), nil
+	}
+	return "", fmt.Errorf("tool '%s' not found on server '%s'", toolName, serverName)
+}
+
+// argumentExamples returns the distinct arguments of a tool's most recent
+// successful calls, newest first. The audit log stores them redacted; they
+// are redacted again in case the patterns changed since.
+func (s *Server) argumentExamples(serverName, toolName string) []interface{} {
+	if s.storage == nil {
+		return nil
+	}
+	entries, err := s.storage.GetAuditEntries(storage.AuditFilter{
+		Server:        serverName,
+		Tool:          toolName,
+		SucceededOnly: true,
+		WithArgs:      true,
+		Limit:         helpExampleScan,
+	})
+	if err != nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var examples []interface{}
+	for _, entry := range entries {
+		if seen[entry.ArgsHash] {
+			continue
+		}
+		seen[entry.ArgsHash] = true
+
+		var args interface{}
+		if err := json.Unmarshal([]byte(entry.Args), &args); err != nil {
+			continue
+		}
+		examples = append(examples, redactArguments(args))
+		if len(examples) == maxHelpExamples {
+			break
+		}
+	}
+	return examples
+}
@@ -0,0 +1,70 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+)
+
+func TestHubHelpReturnsSchemasAndExamples(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	script := writeFakeServer(t, "1.0.0",
+		`[{"name":"search_issues","title":"Search Issues","description":"Search issues","inputSchema":{"type":"object","properties":{"jql":{"type":"string"}}},"outputSchema":{"type":"object","properties":{"total":{"type":"number"}}},"annotations":{"readOnlyHint":true}}]`)
+	cfg := config.NewConfig()
+	cfg.Servers["jira"] = &config.ServerConfig{Command: "sh", Args: []string{script}}
+	cfg.Settings.Audit = &config.AuditSettings{RecordArguments: true}
+	server := NewServer(cfg)
+	defer server.Close()
+
+	out, err := server.execHubHelp("jira", "search_issues")
+	if err != nil {
+		t.Fatalf("hub_help failed: %v", err)
+	}
+	if !strings.Contains(out, `"exampleArguments":{}`) {
+		t.Errorf("expected synthesized example arguments: %s", out)
+	}
+	if !strings.Contains(out, "recordArguments") {
+		t.Errorf("expected a hint when no examples are recorded: %s", out)
+	}
+
+	call := auditCall{Server: "jira", Tool: "search_issues"}
+	call.Args = map[string]interface{}{"jql": "project = A", "token": "secret"}
+	server.recordAudit(call, time.Now(), nil)
+	server.recordAudit(call, time.Now(), nil)
+	call.Args = map[string]interface{}{"jql": "project = B"}
+	server.recordAudit(call, time.Now(), nil)
+	call.Args = map[string]interface{}{"jql": "bad"}
+	server.recordAudit(call, time.Now(), errors.New("invalid jql"))
+
+	out, err = server.execHubHelp("jira", "search_issues")
+	if err != nil {
+		t.Fatalf("hub_help failed: %v", err)
+	}
+	var help struct {
+		Title        string                   `json:"title"`
+		OutputSchema map[string]interface{}   `json:"outputSchema"`
+		Annotations  map[string]interface{}   `json:"annotations"`
+		Examples     []map[string]interface{} `json:"examples"`
+	}
+	if err := json.Unmarshal([]byte(out), &help); err != nil {
+		t.Fatalf("invalid response: %v\n%s", err, out)
+	}
+	if help.Title != "Search Issues" || help.OutputSchema == nil || help.Annotations["readOnlyHint"] != true {
+		t.Errorf("expected the child's title, output schema and annotations: %s", out)
+	}
+	if len(help.Examples) != 2 {
+		t.Fatalf("expected two distinct successful examples, got %s", out)
+	}
+	if help.Examples[0]["jql"] != "project = B" || help.Examples[1]["token"] != redactedValue {
+		t.Errorf("expected redacted examples, newest first: %v", help.Examples)
+	}
+
+	if _, err := server.execHubHelp("jira", "missing"); err == nil {
+		t.Error("expected error for unknown tool")
+	}
+}
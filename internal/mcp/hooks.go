@@ -0,0 +1,240 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/redact"
+)
+
+// toolCall is a tools/call request as seen by middleware.
+type toolCall struct {
+	ID        interface{}
+	Name      string
+	Arguments map[string]interface{}
+}
+
+// callHandler answers a tools/call.
+type callHandler func(call *toolCall) (*MCPResponse, error)
+
+// Middleware wraps tools/call handling. It may rewrite call.Arguments
+// before calling next, answer without calling next to veto the call, or
+// rewrite the response next returns.
+type Middleware func(call *toolCall, next callHandler) (*MCPResponse, error)
+
+// Use appends in-process middleware. It runs before the configured hook
+// commands, in the order added.
+func (s *Server) Use(m Middleware) {
+	s.middlewareMu.Lock()
+	defer s.middlewareMu.Unlock()
+	s.middleware = append(s.middleware, m)
+}
+
+// middlewareChain returns the in-process middleware followed by the
+// configured hook commands, read fresh so a config reload applies.
+func (s *Server) middlewareChain() []Middleware {
+	s.middlewareMu.Lock()
+	chain := append([]Middleware(nil), s.middleware...)
+	s.middlewareMu.Unlock()
+
+	s.configMu.RLock()
+	hooks := s.config.Settings.HookCommands()
+	s.configMu.RUnlock()
+
+	for _, hook := range hooks {
+		chain = append(chain, commandHook(hook))
+	}
+	return chain
+}
+
+// handleToolsCall runs a tools/call through the middleware chain.
+func (s *Server) handleToolsCall(req *MCPRequest) (*MCPResponse, error) {
+	chain := s.middlewareChain()
+	if len(chain) == 0 {
+		return s.callTool(req)
+	}
+
+	var params struct {
+		Name      string                 `json:"name"`
+		Arguments map[string]interface{} `json:"arguments"`
+	}
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return nil, fmt.Errorf("invalid params: %w", err)
+	}
+
+	handler := func(call *toolCall) (*MCPResponse, error) {
+		rewritten, err := withArguments(req, call.Arguments)
+		if err != nil {
+			return nil, err
+		}
+		return s.callTool(rewritten)
+	}
+	for i := len(chain) - 1; i >= 0; i-- {
+		m, next := chain[i], handler
+		handler = func(call *toolCall) (*MCPResponse, error) { return m(call, next) }
+	}
+	return handler(&toolCall{ID: req.ID, Name: params.Name, Arguments: params.Arguments})
+}
+
+// withArguments returns a copy of req with its arguments replaced,
+// keeping the other params (name, _meta) as sent.
+func withArguments(req *MCPRequest, args map[string]interface{}) (*MCPRequest, error) {
+	var params map[string]json.RawMessage
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return nil, fmt.Errorf("invalid params: %w", err)
+	}
+	encoded, err := json.Marshal(args)
+	if err != nil {
+		return nil, fmt.Errorf("invalid arguments: %w", err)
+	}
+	params["arguments"] = encoded
+
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	rewritten := *req
+	rewritten.Params = raw
+	return &rewritten, nil
+}
+
+// hookInput is the JSON a hook command receives on stdin.
+type hookInput struct {
+	Stage     string                 `json:"stage"`
+	Tool      string                 `json:"tool"`
+	Arguments map[string]interface{} `json:"arguments"`
+	Result    interface{}            `json:"result,omitempty"`
+	Error     string                 `json:"error,omitempty"`
+}
+
+// hookOutput is the optional JSON a hook command prints on stdout.
+type hookOutput struct {
+	// Veto stops a pre-stage call; Reason is reported to the client.
+	Veto   bool   `json:"veto,omitempty"`
+	Reason string `json:"reason,omitempty"`
+
+	// Arguments replace the call's arguments (pre stage).
+	Arguments map[string]interface{} `json:"arguments,omitempty"`
+
+	// Result replaces the call's result (post stage).
+	Result map[string]interface{} `json:"result,omitempty"`
+}
+
+// commandHook adapts a configured hook command to Middleware. A pre hook
+// that fails (non-zero exit, timeout, invalid output) vetoes the call, so
+// policy hooks fail closed; a failing post hook is logged and ignored.
+func commandHook(hook config.HookConfig) Middleware {
+	return func(call *toolCall, next callHandler) (*MCPResponse, error) {
+		if !hookMatches(hook.Tools, call) {
+			return next(call)
+		}
+
+		if hook.Stage == config.HookStagePre {
+			out, err := runHook(hook, hookInput{Stage: hook.Stage, Tool: call.Name, Arguments: call.Arguments})
+			if err != nil {
+				return toolErrorResponse(call.ID, &ToolError{
+					Code:    "hook_failed",
+					Message: fmt.Sprintf("hook '%s' failed, call not run: %v", hook.Label(), err),
+					Details: map[string]interface{}{"hook": hook.Label()},
+				}), nil
+			}
+			if out.Veto {
+				reason := out.Reason
+				if reason == "" {
+					reason = "no reason given"
+				}
+				return toolErrorResponse(call.ID, &ToolError{
+					Code:    "vetoed",
+					Message: fmt.Sprintf("hook '%s' vetoed the call: %s", hook.Label(), reason),
+					Details: map[string]interface{}{"hook": hook.Label(), "reason": reason},
+				}), nil
+			}
+			if out.Arguments != nil {
+				call.Arguments = out.Arguments
+			}
+			return next(call)
+		}
+
+		resp, err := next(call)
+		if err != nil || resp == nil {
+			return resp, err
+		}
+		input := hookInput{Stage: hook.Stage, Tool: call.Name, Arguments: call.Arguments, Result: resp.Result}
+		if resp.Error != nil {
+			input.Error = resp.Error.Message
+		}
+		out, hookErr := runHook(hook, input)
+		if hookErr != nil {
+			log.Printf("Warning: hook '%s' failed: %v", hook.Label(), hookErr)
+			return resp, nil
+		}
+		if out.Result != nil {
+			return &MCPResponse{JSONRPC: "2.0", ID: resp.ID, Result: out.Result}, nil
+		}
+		return resp, nil
+	}
+}
+
+// hookMatches reports whether a call is in scope of the hook's tool globs:
+// the meta-tool name, or for hub_execute the child tool (see
+// destructivePattern for the "server/tool" form).
+func hookMatches(patterns []string, call *toolCall) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(strings.ToLower(pattern), strings.ToLower(call.Name)); ok {
+			return true
+		}
+	}
+	if call.Name != "hub_execute" {
+		return false
+	}
+	server, _ := call.Arguments["server"].(string)
+	tool, _ := call.Arguments["tool"].(string)
+	return tool != "" && destructivePattern(patterns, server, tool) != ""
+}
+
+// runHook runs a hook command with input on stdin and decodes its stdout.
+// Empty output means no change.
+func runHook(hook config.HookConfig, input hookInput) (*hookOutput, error) {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), hook.Duration())
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, hook.Command, hook.Args...)
+	cmd.Stdin = bytes.NewReader(data)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("timed out after %v", hook.Duration())
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, redact.String(msg))
+		}
+		return nil, err
+	}
+
+	out := &hookOutput{}
+	if len(bytes.TrimSpace(stdout.Bytes())) == 0 {
+		return out, nil
+	}
+	if err := json.Unmarshal(stdout.Bytes(), out); err != nil {
+		return nil, fmt.Errorf("invalid output: %w", err)
+	}
+	return out, nil
+}
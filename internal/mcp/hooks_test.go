@@ -0,0 +1,163 @@
+package mcp
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+)
+
+// writeHook writes a shell hook script and returns its path.
+func writeHook(t *testing.T, body string) string {
+	t.Helper()
+	script := filepath.Join(t.TempDir(), "hook.sh")
+	if err := os.WriteFile(script, []byte(body), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return script
+}
+
+// callText calls a meta-tool and returns the response text and isError.
+func callText(t *testing.T, server *Server, name string, args map[string]interface{}) (string, bool) {
+	t.Helper()
+	params, _ := json.Marshal(map[string]interface{}{"name": name, "arguments": args})
+	resp, err := server.handleToolsCall(&MCPRequest{JSONRPC: "2.0", ID: 1, Method: "tools/call", Params: params})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Error != nil {
+		return resp.Error.Message, true
+	}
+	result := resp.Result.(map[string]interface{})
+	isError, _ := result["isError"].(bool)
+	switch content := result["content"].(type) {
+	case []map[string]interface{}:
+		return content[0]["text"].(string), isError
+	case []interface{}:
+		return content[0].(map[string]interface{})["text"].(string), isError
+	}
+	t.Fatalf("unexpected result: %v", result)
+	return "", false
+}
+
+func TestPreHookVetoes(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	script := writeCountingServer(t, `[{"name":"create_issue"}]`)
+	cfg := config.NewConfig()
+	cfg.Servers["jira"] = &config.ServerConfig{Command: "sh", Args: []string{script}}
+	hook := writeHook(t, `cat >/dev/null; echo '{"veto": true, "reason": "no writes on Fridays"}'`)
+	cfg.Settings = &config.Settings{Hooks: []config.HookConfig{
+		{Name: "policy", Command: "sh", Args: []string{hook}, Stage: config.HookStagePre, Tools: []string{"create_*"}},
+	}}
+	server := NewServer(cfg)
+	defer server.Close()
+
+	text, isError := callText(t, server, "hub_execute", map[string]interface{}{"server": "jira", "tool": "create_issue"})
+	if !isError || !strings.Contains(text, `"vetoed"`) || !strings.Contains(text, "no writes on Fridays") {
+		t.Errorf("expected a veto, got %s", text)
+	}
+
+	// Calls outside the hook's tools are not affected
+	if text, isError := callText(t, server, "hub_status", nil); isError {
+		t.Errorf("hub_status should not be vetoed: %s", text)
+	}
+}
+
+func TestPreHookFailureVetoes(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg := config.NewConfig()
+	hook := writeHook(t, `echo "policy store unreachable" >&2; exit 1`)
+	cfg.Settings = &config.Settings{Hooks: []config.HookConfig{
+		{Command: "sh", Args: []string{hook}, Stage: config.HookStagePre},
+	}}
+	server := NewServer(cfg)
+	defer server.Close()
+
+	text, isError := callText(t, server, "hub_status", nil)
+	if !isError || !strings.Contains(text, `"hook_failed"`) || !strings.Contains(text, "policy store unreachable") {
+		t.Errorf("expected a failing pre hook to stop the call, got %s", text)
+	}
+}
+
+func TestHooksRewriteArgumentsAndResult(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	seen := filepath.Join(t.TempDir(), "seen.json")
+	pre := writeHook(t, `cat >/dev/null; echo '{"arguments": {"server": "rewritten"}}'`)
+	post := writeHook(t, `cat > `+seen+`; echo '{"result": {"content": [{"type": "text", "text": "replaced"}]}}'`)
+	cfg := config.NewConfig()
+	cfg.Settings = &config.Settings{Hooks: []config.HookConfig{
+		{Command: "sh", Args: []string{pre}, Stage: config.HookStagePre, Tools: []string{"hub_discover"}},
+		{Command: "sh", Args: []string{post}, Stage: config.HookStagePost, Tools: []string{"hub_discover"}},
+	}}
+	server := NewServer(cfg)
+	defer server.Close()
+
+	text, _ := callText(t, server, "hub_discover", map[string]interface{}{"server": "original"})
+	if text != "replaced" {
+		t.Errorf("post hook result not applied, got %q", text)
+	}
+
+	data, err := os.ReadFile(seen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var input hookInput
+	if err := json.Unmarshal(data, &input); err != nil {
+		t.Fatal(err)
+	}
+	if input.Stage != config.HookStagePost || input.Tool != "hub_discover" || input.Arguments["server"] != "rewritten" {
+		t.Errorf("post hook saw unexpected input: %+v", input)
+	}
+	if input.Error == "" && input.Result == nil {
+		t.Errorf("post hook should see the call outcome: %s", data)
+	}
+}
+
+func TestMiddlewareOrder(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	server := NewServer(config.NewConfig())
+	defer server.Close()
+
+	var order []string
+	for _, name := range []string{"first", "second"} {
+		name := name
+		server.Use(func(call *toolCall, next callHandler) (*MCPResponse, error) {
+			order = append(order, name)
+			return next(call)
+		})
+	}
+
+	callText(t, server, "hub_status", nil)
+	if strings.Join(order, ",") != "first,second" {
+		t.Errorf("middleware ran in order %v", order)
+	}
+}
+
+func TestHookMatches(t *testing.T) {
+	execute := &toolCall{Name: "hub_execute", Arguments: map[string]interface{}{"server": "github", "tool": "delete_repo"}}
+	tests := []struct {
+		patterns []string
+		call     *toolCall
+		want     bool
+	}{
+		{nil, execute, true},
+		{[]string{"hub_execute"}, execute, true},
+		{[]string{"delete_*"}, execute, true},
+		{[]string{"github/*"}, execute, true},
+		{[]string{"jira/*"}, execute, false},
+		{[]string{"delete_*"}, &toolCall{Name: "hub_search"}, false},
+		{[]string{"HUB_*"}, &toolCall{Name: "hub_search"}, true},
+	}
+	for _, tt := range tests {
+		if got := hookMatches(tt.patterns, tt.call); got != tt.want {
+			t.Errorf("hookMatches(%v, %s) = %v, want %v", tt.patterns, tt.call.Name, got, tt.want)
+		}
+	}
+}
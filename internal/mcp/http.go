@@ -0,0 +1,238 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// sessionHeader carries the session ID of the Streamable HTTP transport.
+	sessionHeader = "Mcp-Session-Id"
+
+	// sessionIdleTimeout expires HTTP sessions that made no request.
+	sessionIdleTimeout = 30 * time.Minute
+)
+
+// ServeHTTP serves MCP over the Streamable HTTP transport, answering each
+// POST with a JSON response (no SSE streams, so HTTP clients do not receive
+// server-initiated notifications such as progress).
+//
+// Each client gets its own session at initialize, identified by the
+// Mcp-Session-Id response header it must echo on later requests. Sessions
+// have their own negotiated protocol, searchIds and in-flight requests: a
+// client disconnecting cancels its pending requests, and DELETE ends the
+// session.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !allowedOrigin(r) {
+		http.Error(w, "forbidden origin", http.StatusForbidden)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		s.handleHTTPPost(w, r)
+	case http.MethodDelete:
+		s.handleHTTPDelete(w, r)
+	default:
+		w.Header().Set("Allow", "POST, DELETE")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleHTTPPost handles one JSON-RPC message or batch.
+func (s *Server) handleHTTPPost(w http.ResponseWriter, r *http.Request) {
+	s.configMu.RLock()
+	limit := s.config.Settings.MessageLimit()
+	s.configMu.RUnlock()
+
+	body := r.Body
+	if limit > 0 {
+		body = http.MaxBytesReader(w, r.Body, int64(limit))
+	}
+	data, err := io.ReadAll(body)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		http.Error(w, "message exceeds settings.maxMessageBytes", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err != nil {
+		http.Error(w, "failed to read request", http.StatusBadRequest)
+		return
+	}
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		http.Error(w, "empty request", http.StatusBadRequest)
+		return
+	}
+
+	var messages []json.RawMessage
+	batch := data[0] == '['
+	if batch {
+		if err := json.Unmarshal(data, &messages); err != nil || len(messages) == 0 {
+			writeJSON(w, &MCPResponse{JSONRPC: "2.0", Error: &MCPError{Code: -32600, Message: "Invalid Request: malformed or empty batch"}})
+			return
+		}
+	} else {
+		messages = []json.RawMessage{data}
+	}
+
+	var sess *session
+	if !batch && isInitialize(data) {
+		sess = s.openSession()
+		w.Header().Set(sessionHeader, sess.id)
+	} else {
+		id := r.Header.Get(sessionHeader)
+		if id == "" {
+			http.Error(w, "missing "+sessionHeader+" header: send initialize first", http.StatusBadRequest)
+			return
+		}
+		if sess = s.lookupSession(id); sess == nil {
+			http.Error(w, "unknown or expired session", http.StatusNotFound)
+			return
+		}
+	}
+	sess.touch()
+
+	reqs := make([]pendingRequest, len(messages))
+	for i, msg := range messages {
+		reqs[i] = begin(sess, msg)
+	}
+
+	done := make(chan []*MCPResponse, 1)
+	go func() {
+		responses := make([]*MCPResponse, 0, len(reqs))
+		for _, req := range reqs {
+			if resp := s.respond(req); resp != nil {
+				responses = append(responses, resp)
+			}
+		}
+		done <- responses
+	}()
+
+	var responses []*MCPResponse
+	select {
+	case responses = <-done:
+	case <-r.Context().Done():
+		// The client went away: drop the responses it can no longer read
+		cancelled := 0
+		for _, req := range reqs {
+			if req.cancelled != nil {
+				req.cancelled.Store(true)
+				cancelled++
+			}
+		}
+		log.Printf("HTTP client of session %s disconnected, cancelled %d request(s)", sess.id, cancelled)
+		return
+	}
+
+	switch {
+	case len(responses) == 0:
+		w.WriteHeader(http.StatusAccepted)
+	case batch:
+		writeJSON(w, responses)
+	default:
+		writeJSON(w, responses[0])
+	}
+}
+
+// handleHTTPDelete ends a session, cancelling its in-flight requests.
+func (s *Server) handleHTTPDelete(w http.ResponseWriter, r *http.Request) {
+	s.sessionsMu.Lock()
+	sess, ok := s.sessions[r.Header.Get(sessionHeader)]
+	delete(s.sessions, r.Header.Get(sessionHeader))
+	s.sessionsMu.Unlock()
+
+	if !ok {
+		http.Error(w, "unknown or expired session", http.StatusNotFound)
+		return
+	}
+	s.closeSession(sess, "ended by client")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// openSession starts an HTTP session, expiring idle ones first.
+func (s *Server) openSession() *session {
+	sess := newSession(uuid.New().String(), nil)
+
+	var expired []*session
+	s.sessionsMu.Lock()
+	for id, old := range s.sessions {
+		if old.idleSince() > sessionIdleTimeout {
+			delete(s.sessions, id)
+			expired = append(expired, old)
+		}
+	}
+	s.sessions[sess.id] = sess
+	s.sessionsMu.Unlock()
+
+	for _, old := range expired {
+		s.closeSession(old, "idle")
+	}
+	return sess
+}
+
+// lookupSession returns an HTTP session by ID, or nil. A session idle
+// past sessionIdleTimeout is expired on the spot rather than revived.
+func (s *Server) lookupSession(id string) *session {
+	s.sessionsMu.Lock()
+	sess, ok := s.sessions[id]
+	if !ok || sess.idleSince() <= sessionIdleTimeout {
+		s.sessionsMu.Unlock()
+		return sess
+	}
+	delete(s.sessions, id)
+	s.sessionsMu.Unlock()
+
+	s.closeSession(sess, "idle")
+	return nil
+}
+
+// closeSession releases a session that was removed from s.sessions.
+func (s *Server) closeSession(sess *session, reason string) {
+	cancelled := sess.cancelAll()
+	s.searches.forget(sess)
+	log.Printf("Session %s closed (%s), cancelled %d in-flight request(s)", sess.id, reason, cancelled)
+}
+
+// writeJSON writes v as a JSON response body.
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Warning: failed to write HTTP response: %v", err)
+	}
+}
+
+// allowedOrigin guards against DNS rebinding: browsers may only reach the
+// hub from a loopback origin or the host being served.
+func allowedOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	host := u.Hostname()
+	if host == "localhost" {
+		return true
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return true
+	}
+	requestHost, _, err := net.SplitHostPort(r.Host)
+	if err != nil {
+		requestHost = r.Host
+	}
+	return strings.EqualFold(host, requestHost)
+}
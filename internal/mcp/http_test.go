@@ -0,0 +1,207 @@
+package mcp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/protocol"
+)
+
+// postMCP sends one message to the HTTP transport.
+func postMCP(t *testing.T, server *Server, sessionID, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(body))
+	if sessionID != "" {
+		req.Header.Set(sessionHeader, sessionID)
+	}
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+	return rec
+}
+
+// openHTTPSession initializes a session with a protocol version.
+func openHTTPSession(t *testing.T, server *Server, version string) string {
+	t.Helper()
+	rec := postMCP(t, server, "", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"`+version+`"}}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("initialize returned %d: %s", rec.Code, rec.Body)
+	}
+	id := rec.Header().Get(sessionHeader)
+	if id == "" {
+		t.Fatal("initialize did not return a session ID")
+	}
+	return id
+}
+
+func TestHTTPSessionsNegotiateSeparately(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	server := NewServer(config.NewConfig())
+	defer server.Close()
+
+	oldClient := openHTTPSession(t, server, protocol.Version20241105)
+	newClient := openHTTPSession(t, server, protocol.Version20250618)
+	if oldClient == newClient {
+		t.Fatal("sessions should have distinct IDs")
+	}
+
+	annotated := func(sessionID string) bool {
+		rec := postMCP(t, server, sessionID, `{"jsonrpc":"2.0","id":2,"method":"tools/list"}`)
+		var resp struct {
+			Result struct {
+				Tools []map[string]interface{} `json:"tools"`
+			} `json:"result"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || len(resp.Result.Tools) == 0 {
+			t.Fatalf("unexpected tools/list response %s: %v", rec.Body, err)
+		}
+		_, ok := resp.Result.Tools[0]["annotations"]
+		return ok
+	}
+	if annotated(oldClient) {
+		t.Error("2024-11-05 session should not get tool annotations")
+	}
+	if !annotated(newClient) {
+		t.Error("2025-06-18 session should get tool annotations")
+	}
+
+	// The stdio client is unaffected by HTTP sessions
+	if got := server.stdio.protocol(); got != protocol.Latest {
+		t.Errorf("stdio protocol = %s, want %s", got, protocol.Latest)
+	}
+}
+
+func TestHTTPSessionLifecycle(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	server := NewServer(config.NewConfig())
+	defer server.Close()
+
+	ping := `{"jsonrpc":"2.0","id":2,"method":"ping"}`
+	if rec := postMCP(t, server, "", ping); rec.Code != http.StatusBadRequest {
+		t.Errorf("request without a session: %d, want 400", rec.Code)
+	}
+	if rec := postMCP(t, server, "unknown", ping); rec.Code != http.StatusNotFound {
+		t.Errorf("request with an unknown session: %d, want 404", rec.Code)
+	}
+
+	id := openHTTPSession(t, server, protocol.Latest)
+	if rec := postMCP(t, server, id, ping); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"result":{}`) {
+		t.Errorf("ping: %d %s", rec.Code, rec.Body)
+	}
+	if rec := postMCP(t, server, id, `{"jsonrpc":"2.0","method":"notifications/initialized"}`); rec.Code != http.StatusAccepted {
+		t.Errorf("notification: %d, want 202", rec.Code)
+	}
+	rec := postMCP(t, server, id, `[{"jsonrpc":"2.0","id":3,"method":"ping"},{"jsonrpc":"2.0","id":4,"method":"ping"}]`)
+	var batch []MCPResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &batch); err != nil || len(batch) != 2 {
+		t.Errorf("batch: %s (%v)", rec.Body, err)
+	}
+
+	del := httptest.NewRequest(http.MethodDelete, "/mcp", nil)
+	del.Header.Set(sessionHeader, id)
+	delRec := httptest.NewRecorder()
+	server.ServeHTTP(delRec, del)
+	if delRec.Code != http.StatusNoContent {
+		t.Errorf("DELETE: %d, want 204", delRec.Code)
+	}
+	if rec := postMCP(t, server, id, ping); rec.Code != http.StatusNotFound {
+		t.Errorf("request after DELETE: %d, want 404", rec.Code)
+	}
+}
+
+func TestHTTPExpiresIdleSessionOnLookup(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	server := NewServer(config.NewConfig())
+	defer server.Close()
+
+	ping := `{"jsonrpc":"2.0","id":2,"method":"ping"}`
+	id := openHTTPSession(t, server, protocol.Latest)
+	active := openHTTPSession(t, server, protocol.Latest)
+
+	server.lookupSession(id).lastSeen.Store(time.Now().Add(-sessionIdleTimeout - time.Minute).UnixNano())
+	if rec := postMCP(t, server, id, ping); rec.Code != http.StatusNotFound {
+		t.Errorf("request on an idle session: %d, want 404", rec.Code)
+	}
+	if rec := postMCP(t, server, id, ping); rec.Code != http.StatusNotFound {
+		t.Errorf("idle session should stay expired: %d, want 404", rec.Code)
+	}
+	if rec := postMCP(t, server, active, ping); rec.Code != http.StatusOK {
+		t.Errorf("active session: %d, want 200", rec.Code)
+	}
+
+	server.sessionsMu.Lock()
+	_, kept := server.sessions[id]
+	server.sessionsMu.Unlock()
+	if kept {
+		t.Error("expired session should be removed")
+	}
+}
+
+func TestHTTPRejectsForeignOrigin(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	server := NewServer(config.NewConfig())
+	defer server.Close()
+
+	tests := []struct {
+		origin string
+		want   int
+	}{
+		{"http://evil.example", http.StatusForbidden},
+		{"http://localhost:3000", http.StatusOK},
+		{"http://127.0.0.1", http.StatusOK},
+		{"", http.StatusOK},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"initialize"}`))
+		if tt.origin != "" {
+			req.Header.Set("Origin", tt.origin)
+		}
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("origin %q: %d, want %d", tt.origin, rec.Code, tt.want)
+		}
+	}
+}
+
+func TestSearchIdsAreScopedToSessions(t *testing.T) {
+	a, b := newSession("a", nil), newSession("b", nil)
+	owners := newSearchOwners()
+	owners.record("search-1", a)
+
+	if owners.belongsElsewhere("search-1", a) {
+		t.Error("a searchId belongs to the session that issued it")
+	}
+	if !owners.belongsElsewhere("search-1", b) {
+		t.Error("another session's searchId should be rejected")
+	}
+	if owners.belongsElsewhere("from-an-earlier-run", b) {
+		t.Error("unknown searchIds should be accepted")
+	}
+
+	owners.forget(a)
+	if owners.belongsElsewhere("search-1", b) {
+		t.Error("searchIds of a closed session should be forgotten")
+	}
+}
+
+func TestSessionCancelAll(t *testing.T) {
+	sess := newSession("s", nil)
+	first, second := sess.trackRequest("1"), sess.trackRequest("2")
+	sess.untrackRequest("2")
+
+	if n := sess.cancelAll(); n != 1 {
+		t.Errorf("cancelAll cancelled %d requests, want 1", n)
+	}
+	if !first.Load() || second.Load() {
+		t.Errorf("only in-flight requests should be cancelled: %v %v", first.Load(), second.Load())
+	}
+}
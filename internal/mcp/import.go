@@ -0,0 +1,127 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/redact"
+	"github.com/khanglvm/tool-hub-mcp/internal/webhook"
+)
+
+// importConfigText returns the config argument of a hub_manage import as
+// JSON text. Clients may pass the pasted config either as a string or as
+// an object.
+func importConfigText(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", fmt.Errorf("config is required for import operation")
+	case string:
+		return v, nil
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("invalid config: %w", err)
+		}
+		return string(data), nil
+	}
+}
+
+// execHubImport handles the hub_manage import operation: it registers
+// every server found in a pasted client config (Claude Code, OpenCode,
+// Zed, ...), in the formats the CLI add command accepts. name, when set,
+// names a config that holds a single server object.
+func (s *Server) execHubImport(configValue interface{}, name string) (string, error) {
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+
+	input, err := importConfigText(configValue)
+	if err != nil {
+		return "", err
+	}
+
+	servers, format, err := config.ParseAnyMCPConfig(input)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse config: %w", err)
+	}
+	if single, ok := servers["server"]; ok && len(servers) == 1 && strings.TrimSpace(name) != "" {
+		servers = map[string]*config.ServerConfig{strings.TrimSpace(name): single}
+	}
+
+	names := make([]string, 0, len(servers))
+	for serverName := range servers {
+		names = append(names, serverName)
+	}
+	// Sorted so collision suffixes are assigned deterministically
+	sort.Strings(names)
+
+	var added, skipped []string
+	for _, serverName := range names {
+		server := servers[serverName]
+		key, exists := config.UniqueServerName(s.config.Servers, serverName)
+
+		if err := config.ValidateServer(key, server); err != nil {
+			skipped = append(skipped, fmt.Sprintf("%s: %v", key, err))
+			continue
+		}
+		if exists {
+			skipped = append(skipped, fmt.Sprintf("%s: already exists", key))
+			continue
+		}
+		if base := config.ToCamelCase(serverName); key != base {
+			log.Printf("Warning: '%s' collides with existing server '%s', added as '%s'", serverName, base, key)
+		}
+
+		server.Source = "hub_manage"
+		server.SetOriginalName(key, serverName)
+		s.config.Servers[key] = server
+		redact.Secrets.AddEnv(server.Env)
+		added = append(added, key)
+	}
+
+	if len(added) == 0 {
+		return "", fmt.Errorf("no servers imported from %s config. Skipped: %s", format, strings.Join(skipped, "; "))
+	}
+
+	rollback := func() {
+		for _, key := range added {
+			delete(s.config.Servers, key)
+		}
+	}
+
+	// Save config atomically
+	configPath, err := config.GetDefaultConfigPath()
+	if err != nil {
+		rollback()
+		return "", fmt.Errorf("failed to get config path: %w", err)
+	}
+
+	if err := config.Save(s.config, configPath); err != nil {
+		rollback()
+		return "", fmt.Errorf("failed to save config: %w. Config rolled back", err)
+	}
+	for _, key := range added {
+		s.webhooks.Send(webhook.Event{Type: webhook.ServerAdded, Server: key})
+	}
+
+	// Index only the imported servers (must hold lock)
+	s.indexServersUnsafe(added...)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "✓ Imported %d server(s) from %s config.\n", len(added), format)
+	for _, key := range added {
+		server := s.config.Servers[key]
+		fmt.Fprintf(&b, "\n• %s\n  Command: %s %v", key, server.Command, redact.Args(server.Args))
+	}
+	if len(skipped) > 0 {
+		fmt.Fprintf(&b, "\n\nSkipped %d server(s):", len(skipped))
+		for _, reason := range skipped {
+			fmt.Fprintf(&b, "\n• %s", reason)
+		}
+	}
+	fmt.Fprintf(&b, "\n\nConfig saved to: %s\nIndexing triggered.", configPath)
+	return b.String(), nil
+}
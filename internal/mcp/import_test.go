@@ -0,0 +1,78 @@
+package mcp
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+)
+
+func TestHubManageImport(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg := config.NewConfig()
+	cfg.Servers["jira"] = &config.ServerConfig{Command: "/nonexistent/jira"}
+	server := NewServer(cfg)
+	defer server.Close()
+
+	resp, _ := server.handleToolsCall(&MCPRequest{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  "tools/call",
+		Params: json.RawMessage(`{"name":"hub_manage","arguments":{"operation":"import","config":{"mcpServers":{
+			"jira": {"command": "npx", "args": ["-y", "@lvmk/jira-mcp"]},
+			"outline-wiki": {"command": "/nonexistent/outline", "env": {"API_TOKEN": "tok-123456"}}
+		}}}}`),
+	})
+	if resp.Error != nil {
+		t.Fatalf("import failed: %v", resp.Error.Message)
+	}
+	text := resp.Result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	if !strings.Contains(text, "Imported 1 server(s)") || !strings.Contains(text, "jira: already exists") {
+		t.Errorf("unexpected summary: %s", text)
+	}
+
+	imported, ok := server.config.Servers["outlineWiki"]
+	if !ok {
+		t.Fatalf("expected server 'outlineWiki', got %v", server.getServerNamesList())
+	}
+	if imported.Source != "hub_manage" || imported.OriginalName != "outline-wiki" {
+		t.Errorf("unexpected imported server: %+v", imported)
+	}
+	if server.config.Servers["jira"].Command != "/nonexistent/jira" {
+		t.Error("existing server should not be overwritten")
+	}
+
+	saved, err := config.Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if _, ok := saved.Servers["outlineWiki"]; !ok {
+		t.Error("import was not saved")
+	}
+}
+
+func TestHubManageImportSingleServer(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	server := NewServer(config.NewConfig())
+	defer server.Close()
+
+	if _, err := server.execHubImport(`{"command": "/nonexistent/github"}`, "github"); err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+	if _, ok := server.config.Servers["github"]; !ok {
+		t.Errorf("single server should be named 'github', got %v", server.getServerNamesList())
+	}
+
+	if _, err := server.execHubImport(nil, ""); err == nil {
+		t.Error("expected error without config")
+	}
+	if _, err := server.execHubImport(`not json`, ""); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+	if _, err := server.execHubImport(`{"github": {"command": "/nonexistent/github"}}`, ""); err == nil {
+		t.Error("expected error when every server is skipped")
+	}
+}
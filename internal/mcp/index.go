@@ -0,0 +1,114 @@
+package mcp
+
+import (
+	"log"
+	"os"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/catalog"
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/paths"
+	"github.com/khanglvm/tool-hub-mcp/internal/protocol"
+	"github.com/khanglvm/tool-hub-mcp/internal/search"
+)
+
+// openIndexer creates the search index. With tool metadata caching on it
+// is persisted, so search works before discovery finishes; otherwise, or
+// when the persisted index cannot be opened (e.g. another hub instance
+// holds it), it lives in memory.
+func openIndexer(cfg *config.Config) (*search.Indexer, error) {
+	var indexer *search.Indexer
+	if cfg.Settings != nil && cfg.Settings.CacheToolMetadata {
+		indexPath, err := paths.SearchIndex()
+		if err == nil {
+			indexer, err = search.NewIndexerWithPath(indexPath)
+		}
+		if err != nil {
+			log.Printf("Warning: failed to open persisted search index, using an in-memory one: %v", err)
+		}
+	}
+	if indexer == nil {
+		var err error
+		if indexer, err = search.NewIndexer(); err != nil {
+			return nil, err
+		}
+	}
+	return indexer, nil
+}
+
+// prepareIndex brings an opened index up to date with cfg. An index
+// emptied at open, because its schema version or (cleared) language
+// changed, is refilled from the exported tool index; servers missing from
+// it are indexed again by discovery.
+func prepareIndex(indexer *search.Indexer, cfg *config.Config, cleared bool) {
+	if indexer.Rebuilt() {
+		log.Printf("Search index schema migrated from version %d to %d, reindexing tools", indexer.RebuiltFrom(), search.SchemaVersion)
+	}
+	if indexer.Rebuilt() || cleared {
+		restoreIndex(indexer, cfg)
+	}
+	pruneIndex(indexer, cfg)
+}
+
+// restoreIndex indexes the tools of enabled servers found in the exported
+// tool index (see `tool-hub-mcp export-index`).
+func restoreIndex(indexer *search.Indexer, cfg *config.Config) {
+	indexPath, err := paths.IndexFile(".jsonl")
+	if err != nil {
+		return
+	}
+	file, err := os.Open(indexPath)
+	if err != nil {
+		log.Printf("No exported tool index at %s; discovery will rebuild the search index", indexPath)
+		return
+	}
+	defer file.Close()
+
+	entries, err := catalog.Parse(file)
+	if err != nil {
+		log.Printf("Warning: ignoring unreadable tool index, discovery will rebuild the search index: %v", err)
+		return
+	}
+
+	byServer := make(map[string][]protocol.Tool)
+	for _, e := range entries {
+		if server := cfg.Servers[e.Server]; server == nil || server.Disabled || e.Tool == "" {
+			continue
+		}
+		byServer[e.Server] = append(byServer[e.Server], protocol.Tool{
+			Name:        e.Tool,
+			Description: e.Description,
+			InputSchema: e.InputSchema,
+		})
+	}
+
+	restored := 0
+	for name, tools := range byServer {
+		if err := indexer.IndexServer(name, tools, cfg.Servers[name].Tags...); err != nil {
+			log.Printf("Warning: failed to restore %s from the tool index: %v", name, err)
+			continue
+		}
+		restored += len(tools)
+	}
+	log.Printf("Restored %d tools of %d servers from %s", restored, len(byServer), indexPath)
+}
+
+// pruneIndex removes persisted tools of servers that were removed or
+// disabled while the hub was not running.
+func pruneIndex(indexer *search.Indexer, cfg *config.Config) {
+	stale := make(map[string]bool)
+	err := indexer.EachTool(0, func(r search.SearchResult) error {
+		if server := cfg.Servers[r.ServerName]; server == nil || server.Disabled {
+			stale[r.ServerName] = true
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Warning: failed to read the search index: %v", err)
+		return
+	}
+	for name := range stale {
+		if err := indexer.RemoveServer(name); err != nil {
+			log.Printf("Warning: failed to remove %s from the search index: %v", name, err)
+		}
+	}
+}
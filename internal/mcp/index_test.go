@@ -0,0 +1,88 @@
+package mcp
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/paths"
+)
+
+// TestOutdatedIndexRestoredFromCache verifies a persisted index with an
+// older schema version is rebuilt from the exported tool index at open,
+// keeping only configured servers.
+func TestOutdatedIndexRestoredFromCache(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	// An index written before schema versioning
+	indexPath, err := paths.SearchIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(indexPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	old, err := bleve.New(indexPath, bleve.NewIndexMapping())
+	if err != nil {
+		t.Fatal(err)
+	}
+	old.Index("jira/stale", map[string]interface{}{"name": "stale", "server": "jira"})
+	old.Close()
+
+	exported, err := paths.IndexFile(".jsonl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(exported, []byte(`{"tool":"create_issue","server":"jira","description":"Create a Jira issue"}
+{"tool":"get_file","server":"figma","description":"Get a Figma file"}
+{"tool":"gone","server":"removed","description":"Tool of a removed server"}
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.NewConfig()
+	cfg.Servers["jira"] = &config.ServerConfig{Command: "/nonexistent/jira"}
+	cfg.Servers["figma"] = &config.ServerConfig{Command: "/nonexistent/figma"}
+	server := NewServer(cfg)
+	defer server.Close()
+
+	if !server.indexer.Rebuilt() {
+		t.Fatal("expected the outdated index to be rebuilt")
+	}
+	if count, _ := server.indexer.Count(); count != 2 {
+		t.Errorf("expected the 2 cached tools of configured servers, got %d", count)
+	}
+	results, err := server.indexer.SearchBM25("jira issue", 5)
+	if err != nil || len(results) == 0 || results[0].ToolName != "create_issue" {
+		t.Errorf("expected restored tools to be searchable, got %+v (%v)", results, err)
+	}
+}
+
+// TestPersistedIndexPrunesRemovedServers verifies tools of servers removed
+// while the hub was stopped are dropped when the index is reopened.
+func TestPersistedIndexPrunesRemovedServers(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	script := writeFakeServer(t, "1.0.0", `[{"name":"search","description":"Search issues"}]`)
+	cfg := config.NewConfig()
+	cfg.Servers["jira"] = &config.ServerConfig{Command: "sh", Args: []string{script}}
+	cfg.Servers["linear"] = &config.ServerConfig{Command: "sh", Args: []string{script}}
+	server := NewServer(cfg)
+	if err := server.IndexTools(); err != nil {
+		t.Fatal(err)
+	}
+	server.Close()
+
+	delete(cfg.Servers, "linear")
+	server = NewServer(cfg)
+	defer server.Close()
+	if server.indexer.Rebuilt() {
+		t.Error("an up-to-date index should be reused")
+	}
+	results, err := server.indexer.GetAllTools(10)
+	if err != nil || len(results) != 1 || results[0].ServerName != "jira" {
+		t.Errorf("expected only jira's persisted tool, got %+v (%v)", results, err)
+	}
+}
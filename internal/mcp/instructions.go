@@ -0,0 +1,99 @@
+package mcp
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/redact"
+)
+
+// maxInstructionServers bounds the server names listed in the initialize
+// instructions; the rest are counted.
+const maxInstructionServers = 40
+
+// maxInstructionError bounds each failure reason in the instructions.
+const maxInstructionError = 120
+
+// instructions returns the initialize result's instructions: the
+// registered servers, how to go from hub_search to hub_execute, and the
+// servers that are currently failing. Clients that surface instructions
+// get this guidance without it costing tool-description tokens.
+func (s *Server) instructions() string {
+	s.configMu.RLock()
+	var names, disabled []string
+	tags := make(map[string][]string)
+	for name, server := range s.config.Servers {
+		if server.Disabled {
+			disabled = append(disabled, name)
+			continue
+		}
+		names = append(names, name)
+		tags[name] = server.Tags
+	}
+	failed := make(map[string]string, len(s.failedServers))
+	for name, msg := range s.failedServers {
+		failed[name] = msg
+	}
+	s.configMu.RUnlock()
+	sort.Strings(names)
+	sort.Strings(disabled)
+
+	var b strings.Builder
+	if len(names) == 0 {
+		b.WriteString("Tool Hub proxies the tools of other MCP servers, but none are registered yet. ")
+		b.WriteString("Add one with hub_manage (operation: \"add\" or \"import\").")
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "Tool Hub gives access to the tools of %d MCP server(s) through a few meta-tools instead of listing them all.\n\n", len(names))
+	b.WriteString("Servers: ")
+	for i, name := range names {
+		if i == maxInstructionServers {
+			fmt.Fprintf(&b, ", and %d more", len(names)-i)
+			break
+		}
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(name)
+		if len(tags[name]) > 0 {
+			fmt.Fprintf(&b, " (%s)", strings.Join(tags[name], ", "))
+		}
+	}
+	b.WriteString("\n")
+	if len(disabled) > 0 {
+		fmt.Fprintf(&b, "Disabled: %s\n", strings.Join(disabled, ", "))
+	}
+
+	b.WriteString("\nTo use a tool:\n")
+	b.WriteString("1. Call hub_search with what you want to do (e.g. \"create a ticket\"), optionally filtered by server or tags. Results include each tool's inputSchema and a searchId.\n")
+	b.WriteString("2. Call hub_execute with the result's server and tool, the arguments its schema asks for, and the searchId.\n")
+	b.WriteString("Use hub_status to check which servers are running and hub_manage to add, update or remove servers.")
+
+	var unavailable []string
+	for _, name := range names {
+		switch until, open := s.circuits.openUntil(name); {
+		case open:
+			unavailable = append(unavailable, fmt.Sprintf("- %s: unavailable until %s", name, until.Format(time.RFC3339)))
+		case failed[name] != "":
+			unavailable = append(unavailable, fmt.Sprintf("- %s: %s", name, shortError(failed[name])))
+		}
+	}
+	if len(unavailable) > 0 {
+		b.WriteString("\n\nCurrently failing (their tools may be missing from search results):\n")
+		b.WriteString(strings.Join(unavailable, "\n"))
+	}
+	return b.String()
+}
+
+// shortError returns the first line of a failure reason, redacted and
+// shortened for the instructions.
+func shortError(msg string) string {
+	line := []rune(redact.String(FirstLine(msg)))
+	if len(line) > maxInstructionError {
+		return string(line[:maxInstructionError]) + "…"
+	}
+	return string(line)
+}
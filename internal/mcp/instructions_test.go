@@ -0,0 +1,52 @@
+package mcp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/protocol"
+)
+
+func TestInitializeInstructions(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg := config.NewConfig()
+	cfg.Servers["jira"] = &config.ServerConfig{Command: "/nonexistent/jira", Tags: []string{"tickets"}}
+	cfg.Servers["github"] = &config.ServerConfig{Command: "/nonexistent/github"}
+	cfg.Servers["legacy"] = &config.ServerConfig{Command: "/nonexistent/legacy", Disabled: true}
+	server := NewServer(cfg)
+	defer server.Close()
+	server.configMu.Lock()
+	server.failedServers["jira"] = "failed to spawn: exec: \"/nonexistent/jira\": no such file or directory\nstack..."
+	server.configMu.Unlock()
+
+	instructions, _ := initializeWith(t, server, protocol.Latest)["instructions"].(string)
+	for _, want := range []string{
+		"2 MCP server(s)",
+		"Servers: github, jira (tickets)",
+		"Disabled: legacy",
+		"hub_search",
+		"hub_execute",
+		"- jira: failed to spawn",
+	} {
+		if !strings.Contains(instructions, want) {
+			t.Errorf("instructions missing %q:\n%s", want, instructions)
+		}
+	}
+	if strings.Contains(instructions, "stack...") {
+		t.Errorf("failure reasons should be one line:\n%s", instructions)
+	}
+}
+
+func TestInitializeInstructionsWithoutServers(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	server := NewServer(config.NewConfig())
+	defer server.Close()
+
+	instructions, _ := initializeWith(t, server, protocol.Latest)["instructions"].(string)
+	if !strings.Contains(instructions, "none are registered") || !strings.Contains(instructions, "hub_manage") {
+		t.Errorf("unexpected instructions: %s", instructions)
+	}
+}
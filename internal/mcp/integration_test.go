@@ -7,8 +7,8 @@ import (
 
 	"github.com/khanglvm/tool-hub-mcp/internal/config"
 	"github.com/khanglvm/tool-hub-mcp/internal/learning"
+	"github.com/khanglvm/tool-hub-mcp/internal/protocol"
 	"github.com/khanglvm/tool-hub-mcp/internal/search"
-	"github.com/khanglvm/tool-hub-mcp/internal/spawner"
 )
 
 // TestSearchWorkflow tests the complete search workflow
@@ -183,7 +183,7 @@ func TestIndexerIntegration(t *testing.T) {
 	}
 
 	// Index mock tools
-	tools := []spawner.Tool{
+	tools := []protocol.Tool{
 		{
 			Name:        "create_issue",
 			Description: "Create a Jira issue",
@@ -255,7 +255,7 @@ func TestHybridSearchIntegration(t *testing.T) {
 	}
 
 	// Index tools
-	tools := []spawner.Tool{
+	tools := []protocol.Tool{
 		{
 			Name:        "create_ticket",
 			Description: "Create a support ticket",
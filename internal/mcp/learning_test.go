@@ -0,0 +1,30 @@
+package mcp
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+)
+
+func TestLearningDisabledCreatesNoDatabase(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	cfg := &config.Config{Servers: map[string]*config.ServerConfig{}}
+	cfg.DisableLearning()
+
+	server := NewServer(cfg)
+	if server.tracker != nil || server.storage != nil {
+		t.Error("expected no tracker or storage with learning disabled")
+	}
+	if _, err := server.execHubSearch("anything", "", 5); err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	server.Close()
+
+	if _, err := os.Stat(filepath.Join(home, ".local", "share", "tool-hub-mcp", "history.db")); !os.IsNotExist(err) {
+		t.Errorf("history.db should not be created, stat err = %v", err)
+	}
+}
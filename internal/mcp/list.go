@@ -0,0 +1,82 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+)
+
+// execHubList lists the registered servers with what is known about them
+// without spawning any: description, tags, aliases and the number of tools
+// from the metadata cache, and whether the server is disabled or failing.
+func (s *Server) execHubList() (string, error) {
+	s.configMu.RLock()
+	names := make([]string, 0, len(s.config.Servers))
+	for name := range s.config.Servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	servers := make([]map[string]interface{}, 0, len(names))
+	for _, name := range names {
+		server := s.config.Servers[name]
+		entry := map[string]interface{}{
+			"name":    name,
+			"enabled": !server.Disabled,
+		}
+		if len(server.Tags) > 0 {
+			entry["tags"] = server.Tags
+		}
+		if len(server.Aliases) > 0 {
+			entry["aliases"] = server.Aliases
+		}
+		if server.Metadata != nil {
+			if server.Metadata.Description != "" {
+				entry["description"] = server.Metadata.Description
+			}
+			if len(server.Metadata.Tools) > 0 {
+				entry["toolCount"] = len(server.Metadata.Tools)
+			}
+		}
+		if msg, ok := s.failedServers[name]; ok {
+			entry["error"] = shortError(msg)
+		}
+		if until, open := s.circuits.openUntil(name); open {
+			entry["unavailableUntil"] = until.Format(time.RFC3339)
+		}
+		servers = append(servers, entry)
+	}
+	s.configMu.RUnlock()
+
+	response := map[string]interface{}{
+		"totalServers": len(servers),
+		"servers":      servers,
+		"hint":         "Call hub_search with what you want to do, or hub_discover with a server name, to find tools.",
+	}
+	data, err := json.Marshal(response)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal response: %w", err)
+	}
+	return s.budgetResponse(string(data)), nil
+}
+
+// metaToolEnabled reports whether settings.metaTools exposes a meta-tool.
+func (s *Server) metaToolEnabled(name string) bool {
+	s.configMu.RLock()
+	defer s.configMu.RUnlock()
+	return slices.Contains(s.config.Settings.ExposedMetaTools(), name)
+}
+
+// selectMetaTools keeps the meta-tools settings.metaTools exposes, in
+// their usual order.
+func (s *Server) selectMetaTools(tools []map[string]interface{}) []map[string]interface{} {
+	selected := tools[:0]
+	for _, tool := range tools {
+		if name, _ := tool["name"].(string); s.metaToolEnabled(name) {
+			selected = append(selected, tool)
+		}
+	}
+	return selected
+}
@@ -0,0 +1,72 @@
+package mcp
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+)
+
+func TestHubListDescribesServers(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg := config.NewConfig()
+	cfg.Servers["jira"] = &config.ServerConfig{
+		Command:  "/nonexistent/jira",
+		Disabled: true,
+		Tags:     []string{"tickets"},
+		Metadata: &config.ServerMetadata{Description: "Jira Cloud", Tools: []string{"create_issue", "search"}},
+	}
+	cfg.Servers["github"] = &config.ServerConfig{Command: "/nonexistent/github", Disabled: true}
+	server := NewServer(cfg)
+	defer server.Close()
+
+	out, err := server.execHubList()
+	if err != nil {
+		t.Fatalf("hub_list failed: %v", err)
+	}
+	var response struct {
+		TotalServers int                      `json:"totalServers"`
+		Servers      []map[string]interface{} `json:"servers"`
+	}
+	if err := json.Unmarshal([]byte(out), &response); err != nil {
+		t.Fatalf("invalid response: %v\n%s", err, out)
+	}
+	if response.TotalServers != 2 || response.Servers[0]["name"] != "github" {
+		t.Fatalf("expected servers sorted by name, got %s", out)
+	}
+	jira := response.Servers[1]
+	if jira["description"] != "Jira Cloud" || jira["toolCount"] != float64(2) || jira["enabled"] != false {
+		t.Errorf("unexpected jira entry: %v", jira)
+	}
+}
+
+func TestMetaToolSelection(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg := config.NewConfig()
+	cfg.Settings.MetaTools = []string{"hub_list", "hub_search", "hub_execute"}
+	server := NewServer(cfg)
+	defer server.Close()
+
+	var names []string
+	for name := range metaTools(t, server) {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if strings.Join(names, ",") != "hub_execute,hub_list,hub_search" {
+		t.Errorf("expected the selected meta-tools, got %v", names)
+	}
+
+	resp, err := server.handleToolsCall(&MCPRequest{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  "tools/call",
+		Params:  json.RawMessage(`{"name":"hub_status","arguments":{}}`),
+	})
+	if err != nil || resp.Error == nil || !strings.Contains(resp.Error.Message, "Unknown tool") {
+		t.Errorf("expected unexposed meta-tool to be unknown, got %+v %v", resp, err)
+	}
+}
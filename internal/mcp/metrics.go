@@ -0,0 +1,72 @@
+package mcp
+
+import (
+	"time"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/metrics"
+)
+
+// serverMetrics are the Prometheus metrics reported by the hub.
+type serverMetrics struct {
+	registry   *metrics.Registry
+	searches   *metrics.CounterVec
+	executions *metrics.CounterVec
+	latency    *metrics.HistogramVec
+	spawns     *metrics.CounterVec
+}
+
+// newServerMetrics registers the hub's metrics. Gauges read the index and
+// tracker at scrape time.
+func newServerMetrics(s *Server) *serverMetrics {
+	r := metrics.NewRegistry()
+	m := &serverMetrics{
+		registry:   r,
+		searches:   r.Counter("toolhub_searches_total", "hub_search calls by outcome.", "status"),
+		executions: r.Counter("toolhub_executions_total", "hub_execute calls by server and outcome.", "server", "status"),
+		latency:    r.Histogram("toolhub_execution_duration_seconds", "hub_execute latency by server.", metrics.DefaultBuckets, "server"),
+		spawns:     r.Counter("toolhub_process_spawns_total", "Child MCP server processes started, by server.", "server"),
+	}
+
+	r.GaugeFunc("toolhub_index_documents", "Tools in the search index.", func() float64 {
+		if s.indexer == nil {
+			return 0
+		}
+		count, err := s.indexer.Count()
+		if err != nil {
+			return 0
+		}
+		return float64(count)
+	})
+	r.GaugeFunc("toolhub_tracker_queue_depth", "Usage events waiting to be written by the learning tracker.", func() float64 {
+		if s.tracker == nil {
+			return 0
+		}
+		return float64(s.tracker.GetEventQueueSize())
+	})
+
+	return m
+}
+
+// Metrics returns the registry to expose on a /metrics endpoint.
+func (s *Server) Metrics() *metrics.Registry {
+	return s.metrics.registry
+}
+
+// observeSearch counts a hub_search call.
+func (m *serverMetrics) observeSearch(err error) {
+	m.searches.Inc(outcome(err))
+}
+
+// observeExecution counts a hub_execute call and records its latency.
+func (m *serverMetrics) observeExecution(serverName string, elapsed time.Duration, err error) {
+	m.executions.Inc(serverName, outcome(err))
+	m.latency.Observe(elapsed.Seconds(), serverName)
+}
+
+// outcome maps an error to a status label.
+func outcome(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "success"
+}
@@ -0,0 +1,44 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+)
+
+func TestHubExecuteIsCounted(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	server := NewServer(config.NewConfig())
+	defer server.Close()
+
+	server.handleToolsCall(&MCPRequest{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  "tools/call",
+		Params:  json.RawMessage(`{"name":"hub_execute","arguments":{"server":"missing","tool":"search","arguments":{}}}`),
+	})
+
+	if got := server.metrics.executions.Value("missing", "error"); got != 1 {
+		t.Errorf("expected one failed execution, got %v", got)
+	}
+
+	var buf bytes.Buffer
+	if err := server.Metrics().WriteText(&buf); err != nil {
+		t.Fatalf("WriteText failed: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		`toolhub_executions_total{server="missing",status="error"} 1`,
+		`toolhub_execution_duration_seconds_count{server="missing"} 1`,
+		"toolhub_index_documents 0",
+		"toolhub_tracker_queue_depth",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics output missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package mcp
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/spawner"
+)
+
+// invalidParamsCode is the JSON-RPC error code for invalid params.
+const invalidParamsCode = -32602
+
+// maxHelpMistakes bounds the common mistakes hub_help returns.
+const maxHelpMistakes = 5
+
+// recordArgumentMistake remembers a hub_execute call the child rejected
+// with invalid params, so hub_help can warn about the mistake next time.
+// Other failures are not argument mistakes and are ignored.
+func (s *Server) recordArgumentMistake(serverName, toolName string, callErr error) {
+	var rpcErr *spawner.RPCError
+	if s.storage == nil || !errors.As(callErr, &rpcErr) || rpcErr.Code != invalidParamsCode {
+		return
+	}
+	if err := s.storage.RecordArgumentMistake(serverName, toolName, shortError(rpcErr.Message)); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+}
+
+// commonMistakes returns the argument mistakes recorded for a tool, most
+// frequent first, for hub_help.
+func (s *Server) commonMistakes(serverName, toolName string) []map[string]interface{} {
+	if s.storage == nil {
+		return nil
+	}
+	mistakes, err := s.storage.GetArgumentMistakes(serverName, toolName, maxHelpMistakes)
+	if err != nil {
+		log.Printf("Warning: %v", err)
+		return nil
+	}
+
+	var common []map[string]interface{}
+	for _, mistake := range mistakes {
+		common = append(common, map[string]interface{}{
+			"error":    mistake.Error,
+			"count":    mistake.Count,
+			"lastSeen": mistake.LastSeen.Format(time.RFC3339),
+		})
+	}
+	return common
+}
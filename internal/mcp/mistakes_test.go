@@ -0,0 +1,53 @@
+package mcp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/mcptest"
+)
+
+// writeRejectingServer writes a fake MCP server that rejects every
+// tools/call with an invalid params error.
+func writeRejectingServer(t *testing.T) string {
+	t.Helper()
+	return mcptest.Script(t, mcptest.Handlers{
+		"initialize": mcptest.Result(`{}`),
+		"tools/list": mcptest.Result(`{"tools":[{"name":"search","inputSchema":{"type":"object","required":["jql"],"properties":{"jql":{"type":"string"}}}}]}`),
+		"tools/call": mcptest.Error(-32602, "jql: required"),
+	})
+}
+
+func TestHubHelpReportsArgumentMistakes(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg := config.NewConfig()
+	cfg.Servers["jira"] = &config.ServerConfig{Command: "sh", Args: []string{writeRejectingServer(t)}}
+	server := NewServer(cfg)
+	defer server.Close()
+
+	for i := 0; i < 2; i++ {
+		if _, err := server.execHubExecute(nil, "jira", "search", map[string]interface{}{"query": "x"}, "", nil); err == nil {
+			t.Fatal("expected the call to be rejected")
+		}
+	}
+
+	out, err := server.execHubHelp("jira", "search")
+	if err != nil {
+		t.Fatalf("hub_help failed: %v", err)
+	}
+	var help struct {
+		CommonMistakes []map[string]interface{} `json:"commonMistakes"`
+	}
+	if err := json.Unmarshal([]byte(out), &help); err != nil {
+		t.Fatalf("invalid response: %v\n%s", err, out)
+	}
+	if len(help.CommonMistakes) != 1 {
+		t.Fatalf("expected one common mistake, got %s", out)
+	}
+	mistake := help.CommonMistakes[0]
+	if mistake["error"] != "jql: required" || mistake["count"] != float64(2) {
+		t.Errorf("unexpected mistake: %v", mistake)
+	}
+}
@@ -0,0 +1,97 @@
+package mcp
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+)
+
+// notifyToolsListChanged tells clients to refetch tools/list, as the
+// server set or index contents changed. Skipped before initialize.
+func (s *Server) notifyToolsListChanged() {
+	for _, sess := range s.clientSessions() {
+		if sess.ready.Load() {
+			sess.send("notifications/tools/list_changed", nil)
+		}
+	}
+}
+
+// handleChildNotification reacts to notifications from child servers:
+// list changes trigger a reindex, log messages go to the hub log, and
+// progress is relayed to the client. It runs on the child's reader
+// goroutine, so work that talks to the child is moved off it.
+func (s *Server) handleChildNotification(serverName, method string, params json.RawMessage) {
+	switch method {
+	case "notifications/tools/list_changed":
+		log.Printf("Tools changed on %s, reindexing", serverName)
+		go s.reindexServer(serverName)
+	case "notifications/message":
+		logChildMessage(serverName, params)
+	case "notifications/progress":
+		s.forwardProgress(params)
+	}
+}
+
+// reindexServer refreshes one server's tools after it reported a change.
+// Thread-safe: acquires write lock.
+func (s *Server) reindexServer(serverName string) {
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+
+	s.indexServersUnsafe(serverName)
+}
+
+// sameServerNames reports whether two configs register the same servers.
+func sameServerNames(a, b map[string]*config.ServerConfig) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name := range a {
+		if _, ok := b[name]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// handleNotification routes a notification from the client. Unknown
+// notifications are ignored, as JSON-RPC forbids replying to them.
+func (s *Server) handleNotification(req *MCPRequest) {
+	switch req.Method {
+	case "notifications/initialized":
+		s.sessionOf(req).ready.Store(true)
+	case "notifications/cancelled":
+		var params struct {
+			RequestID json.RawMessage `json:"requestId"`
+			Reason    string          `json:"reason"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil || len(params.RequestID) == 0 {
+			return
+		}
+		s.sessionOf(req).cancelRequest(normalizeID(params.RequestID), params.Reason)
+	}
+}
+
+// requestKey returns a message's ID in canonical form, or "" when the
+// message has no ID (a notification).
+func requestKey(data []byte) string {
+	var msg struct {
+		ID json.RawMessage `json:"id"`
+	}
+	if err := json.Unmarshal(data, &msg); err != nil || len(msg.ID) == 0 {
+		return ""
+	}
+	return normalizeID(msg.ID)
+}
+
+// normalizeID re-encodes a JSON-RPC ID so equal IDs compare equal
+// regardless of formatting (e.g., 7 and 7.0; "7" stays distinct).
+func normalizeID(raw json.RawMessage) string {
+	var id interface{}
+	if err := json.Unmarshal(raw, &id); err != nil {
+		return string(raw)
+	}
+	data, _ := json.Marshal(id)
+	return string(data)
+}
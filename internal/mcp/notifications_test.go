@@ -0,0 +1,195 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/mcptest"
+)
+
+// syncBuffer is a bytes.Buffer safe for the background reindex goroutine.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestChildListChangedReindexes(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	dir := t.TempDir()
+	toolsFile := filepath.Join(dir, "tools.json")
+	os.WriteFile(toolsFile, []byte(`[{"name":"search","description":"Search issues"}]`), 0644)
+	cfg := config.NewConfig()
+	cfg.Servers["jira"] = mcptest.Server(t, mcptest.Handlers{
+		"initialize": mcptest.Result(`{}`),
+		"tools/list": mcptest.Result(`{"tools":'"$(cat ` + toolsFile + `)"'}`),
+		"tools/call": mcptest.Notify("notifications/tools/list_changed", "") + "; " + mcptest.Result(`{"content":[]}`),
+	})
+	server := NewServer(cfg)
+	defer server.Close()
+	out := &syncBuffer{}
+	server.out = out
+
+	if err := server.IndexTools(); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "" {
+		t.Errorf("no notifications expected before initialize, got %s", out.String())
+	}
+	server.handleInitialize(&MCPRequest{ID: 1})
+
+	os.WriteFile(toolsFile, []byte(`[{"name":"search","description":"Search issues"},{"name":"create_issue","description":"Create an issue"}]`), 0644)
+	if _, err := server.spawner.CallTool("jira", cfg.Servers["jira"], "search", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		count, _ := server.indexer.Count()
+		if count == 2 && strings.Contains(out.String(), "notifications/tools/list_changed") {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected reindex to 2 tools and an upstream notification, got %d tools and output %q", count, out.String())
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestReloadConfigNotifiesOnServerSetChange(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	server := NewServer(config.NewConfig())
+	server.indexer.Close()
+	server.indexer = nil
+	defer server.Close()
+	var out bytes.Buffer
+	server.out = &out
+	server.handleInitialize(&MCPRequest{ID: 1})
+
+	server.ReloadConfig(config.NewConfig())
+	if out.Len() != 0 {
+		t.Errorf("unchanged server set should not notify, got %s", out.String())
+	}
+
+	cfg := config.NewConfig()
+	cfg.Servers["jira"] = &config.ServerConfig{Command: "jira-mcp"}
+	server.ReloadConfig(cfg)
+	if !strings.Contains(out.String(), `{"jsonrpc":"2.0","method":"notifications/tools/list_changed"}`) {
+		t.Errorf("expected list_changed notification, got %q", out.String())
+	}
+}
+
+func TestChildProgressAndLoggingForwarded(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	var logs syncBuffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	cfg := config.NewConfig()
+	cfg.Servers["jira"] = mcptest.Server(t, mcptest.Handlers{
+		"initialize": mcptest.Result(`{}`),
+		"tools/call": `tok=$(echo "$line" | sed 's/.*"progressToken":"\([^"]*\)".*/\1/'); ` +
+			mcptest.Notify("notifications/message", `{"level":"info","logger":"db","data":"connected"}`) + "; " +
+			mcptest.Notify("notifications/progress", `{"progressToken":"'$tok'","progress":50,"total":100}`) + "; " +
+			mcptest.Result(`{"content":[{"type":"text","text":"done"}]}`),
+	})
+	server := NewServer(cfg)
+	defer server.Close()
+	out := &syncBuffer{}
+	server.out = out
+
+	resp, err := server.handleToolsCall(&MCPRequest{
+		JSONRPC: "2.0",
+		ID:      7,
+		Method:  "tools/call",
+		Params:  json.RawMessage(`{"name":"hub_execute","arguments":{"server":"jira","tool":"export"},"_meta":{"progressToken":"client-7"}}`),
+	})
+	if err != nil || resp.Error != nil {
+		t.Fatalf("hub_execute failed: %v %+v", err, resp)
+	}
+
+	if !strings.Contains(out.String(), `{"jsonrpc":"2.0","method":"notifications/progress","params":{"progress":50,"progressToken":"client-7","total":100}}`) {
+		t.Errorf("expected relayed progress under the client token, got %q", out.String())
+	}
+	if !strings.Contains(logs.String(), "[jira/db] info: connected") {
+		t.Errorf("expected child log message in hub log, got %q", logs.String())
+	}
+	if len(server.progressRelays) != 0 {
+		t.Errorf("progress relay should be released after the call, got %v", server.progressRelays)
+	}
+}
+
+func TestClientNotificationsGetNoResponse(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	server := NewServer(config.NewConfig())
+	defer server.Close()
+
+	for _, msg := range []string{
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","method":"notifications/roots/list_changed"}`,
+		`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":99}}`,
+	} {
+		resp, err := server.handleRequest([]byte(msg))
+		if resp != nil || err != nil {
+			t.Errorf("%s: expected no response, got %+v, %v", msg, resp, err)
+		}
+	}
+	if !server.stdio.ready.Load() {
+		t.Error("notifications/initialized should mark the client ready")
+	}
+
+	resp, _ := server.handleRequest([]byte(`{"jsonrpc":"2.0","id":1,"method":"unknown/method"}`))
+	if resp == nil || resp.Error == nil || resp.Error.Code != -32601 {
+		t.Errorf("unknown requests should still get Method not found, got %+v", resp)
+	}
+}
+
+func TestCancelledRequestGetsNoResponse(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg := config.NewConfig()
+	cfg.Servers["slow"] = mcptest.Server(t, mcptest.Handlers{
+		"initialize": mcptest.Result(`{}`),
+		"tools/call": "sleep 0.3; " + mcptest.Result(`{"content":[]}`),
+	})
+	server := NewServer(cfg)
+	defer server.Close()
+	out := &syncBuffer{}
+	server.out = out
+
+	input := `{"jsonrpc":"2.0","id":"call-1","method":"tools/call","params":{"name":"hub_execute","arguments":{"server":"slow","tool":"wait"}}}
+{"jsonrpc":"2.0","id":2,"method":"tools/list"}
+{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":"call-1","reason":"user aborted"}}
+`
+	if err := server.serve(strings.NewReader(input)); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 1 || !strings.Contains(lines[0], `"id":2`) {
+		t.Errorf("expected only the tools/list response, got %q", out.String())
+	}
+}
@@ -0,0 +1,69 @@
+package mcp
+
+import (
+	"encoding/json"
+	"log"
+)
+
+// toolContent is a child tools/call result forwarded with its MCP content
+// typing intact, so image and resource blocks reach the client natively
+// instead of as stringified JSON (experimental "passthrough" feature).
+type toolContent struct {
+	Content           []map[string]interface{} `json:"content"`
+	IsError           bool                     `json:"isError,omitempty"`
+	StructuredContent interface{}              `json:"structuredContent,omitempty"`
+
+	// Meta is the hub's own _meta for the result (e.g. retry details);
+	// a child's _meta is not forwarded.
+	Meta map[string]interface{} `json:"-"`
+}
+
+// result returns the hub's tools/call result for the forwarded content.
+func (c *toolContent) result() map[string]interface{} {
+	result := map[string]interface{}{
+		"content": c.Content,
+	}
+	if c.IsError {
+		result["isError"] = true
+	}
+	if c.StructuredContent != nil {
+		result["structuredContent"] = c.StructuredContent
+	}
+	if len(c.Meta) > 0 {
+		result["_meta"] = c.Meta
+	}
+	return result
+}
+
+// passthroughContent decodes a child tools/call result into typed content
+// blocks. Text blocks are still subject to the response budget; other
+// block types are forwarded unchanged unless the client's protocol revision
+// predates them (see adaptContent). Returns nil if the child's result
+// has no content array, in which case callers fall back to stringifying it.
+func (s *Server) passthroughContent(response interface{}, protocolVersion string) *toolContent {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return nil
+	}
+
+	var content toolContent
+	if err := json.Unmarshal(data, &content); err != nil {
+		log.Printf("Warning: child result is not MCP content, stringifying: %v", err)
+		return nil
+	}
+	if content.Content == nil {
+		return nil
+	}
+
+	adaptContent(&content, protocolVersion)
+
+	for _, block := range content.Content {
+		if block["type"] != "text" {
+			continue
+		}
+		if text, ok := block["text"].(string); ok {
+			block["text"] = s.budgetResponse(text)
+		}
+	}
+	return &content
+}
@@ -0,0 +1,112 @@
+package mcp
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/mcptest"
+	"github.com/khanglvm/tool-hub-mcp/internal/protocol"
+)
+
+// writeFakeCallServer writes a fake MCP server whose tools/call returns callResult.
+func writeFakeCallServer(t *testing.T, callResult string) string {
+	t.Helper()
+	return mcptest.Script(t, mcptest.Handlers{
+		"initialize": mcptest.Result(`{"serverInfo":{"name":"shots","version":"1.0.0"}}`),
+		"tools/call": mcptest.Result(callResult),
+	})
+}
+
+func callHubExecute(t *testing.T, server *Server) map[string]interface{} {
+	t.Helper()
+	resp, err := server.handleToolsCall(&MCPRequest{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  "tools/call",
+		Params:  json.RawMessage(`{"name":"hub_execute","arguments":{"server":"shots","tool":"screenshot"}}`),
+	})
+	if err != nil || resp.Error != nil {
+		t.Fatalf("hub_execute failed: %v %+v", err, resp.Error)
+	}
+	result, ok := resp.Result.(map[string]interface{})
+	if !ok {
+		t.Fatalf("unexpected result type %T", resp.Result)
+	}
+	return result
+}
+
+func TestHubExecutePassthroughForwardsContentBlocks(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	script := writeFakeCallServer(t, `{"content":[{"type":"text","text":"captured"},{"type":"image","data":"iVBORw0KGgo=","mimeType":"image/png"}],"isError":false}`)
+	cfg := config.NewConfig()
+	cfg.Servers["shots"] = &config.ServerConfig{Command: "sh", Args: []string{script}}
+	if err := cfg.EnableFeatures([]string{config.FeaturePassthrough}); err != nil {
+		t.Fatal(err)
+	}
+	server := NewServer(cfg)
+	defer server.Close()
+
+	content, ok := callHubExecute(t, server)["content"].([]map[string]interface{})
+	if !ok || len(content) != 2 {
+		t.Fatalf("expected 2 forwarded blocks, got %+v", content)
+	}
+	if content[0]["text"] != "captured" {
+		t.Errorf("text block altered: %+v", content[0])
+	}
+	if content[1]["type"] != "image" || content[1]["mimeType"] != "image/png" {
+		t.Errorf("image block not forwarded natively: %+v", content[1])
+	}
+}
+
+func TestHubExecuteWithoutPassthroughStringifies(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	script := writeFakeCallServer(t, `{"content":[{"type":"image","data":"iVBORw0KGgo=","mimeType":"image/png"}]}`)
+	cfg := config.NewConfig()
+	cfg.Servers["shots"] = &config.ServerConfig{Command: "sh", Args: []string{script}}
+	server := NewServer(cfg)
+	defer server.Close()
+
+	content := callHubExecute(t, server)["content"].([]map[string]interface{})
+	text, _ := content[0]["text"].(string)
+	if content[0]["type"] != "text" || !strings.Contains(text, `"mimeType": "image/png"`) {
+		t.Errorf("expected stringified child result, got %+v", content[0])
+	}
+}
+
+func TestPassthroughContentFallsBackWithoutContentArray(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	server := NewServer(config.NewConfig())
+	defer server.Close()
+
+	if got := server.passthroughContent(map[string]interface{}{"value": 1}, protocol.Latest); got != nil {
+		t.Errorf("expected nil for non-content result, got %+v", got)
+	}
+	got := server.passthroughContent(map[string]interface{}{"content": []interface{}{}, "isError": true}, protocol.Latest)
+	if got == nil || !got.IsError {
+		t.Errorf("expected isError to be forwarded, got %+v", got)
+	}
+}
+
+func TestIsErrorResult(t *testing.T) {
+	cases := []struct {
+		response interface{}
+		want     bool
+	}{
+		{map[string]interface{}{"isError": true, "content": []interface{}{}}, true},
+		{map[string]interface{}{"isError": false}, false},
+		{map[string]interface{}{"content": []interface{}{}}, false},
+		{"plain text", false},
+		{nil, false},
+	}
+
+	for _, c := range cases {
+		if got := isErrorResult(c.response); got != c.want {
+			t.Errorf("isErrorResult(%v) = %v, want %v", c.response, got, c.want)
+		}
+	}
+}
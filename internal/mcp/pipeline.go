@@ -0,0 +1,69 @@
+package mcp
+
+import (
+	"log"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/learning"
+	"github.com/khanglvm/tool-hub-mcp/internal/search"
+	"github.com/khanglvm/tool-hub-mcp/internal/storage"
+)
+
+// SearchPipeline ranks tools the way hub_search does: BM25 over an index
+// set up from the search settings, fused with semantic similarity when
+// that experimental feature is enabled, then re-ranked by the query
+// affinity learned from earlier executions.
+type SearchPipeline struct {
+	indexer  *search.Indexer
+	history  *storage.SQLiteStorage
+	thompson *learning.ThompsonSampling // nil unless the thompson algorithm is selected
+	hybrid   bool
+}
+
+// NewSearchPipeline applies cfg's search settings (synonyms, field boosts,
+// description limit, language) to indexer and returns the pipeline over
+// it. history provides the learned query affinity and may be nil. cleared
+// reports that a language change emptied the index, so every server must
+// be indexed again.
+func NewSearchPipeline(indexer *search.Indexer, cfg *config.Config, history *storage.SQLiteStorage) (p *SearchPipeline, cleared bool) {
+	settings := cfg.Settings
+	indexer.SetSynonyms(settings.SearchSynonyms())
+	indexer.SetMaxDescriptionLength(settings.DescriptionLimit())
+	b := settings.SearchBoosts()
+	indexer.SetFieldBoosts(search.FieldBoosts{Name: b.Name, Description: b.Description, Server: b.Server})
+	cleared, err := indexer.SetLanguage(settings.SearchLanguage())
+	if err != nil {
+		log.Printf("Warning: failed to set search language: %v", err)
+	}
+
+	p = &SearchPipeline{
+		indexer: indexer,
+		history: history,
+		hybrid:  cfg.FeatureEnabled(config.FeatureSemanticSearch),
+	}
+	if cfg.LearningAlgorithm() == config.AlgorithmThompson {
+		p.thompson = learning.NewThompsonSampling(0)
+	}
+	return p, cleared
+}
+
+// Search returns one page of results for query, scoped by filter.
+func (p *SearchPipeline) Search(query string, filter search.Filter, limit, offset int) (*search.Page, error) {
+	var page *search.Page
+	var err error
+	if !filter.IsEmpty() {
+		// Search within specific servers or tags
+		page, err = p.indexer.SearchFilteredPage(query, filter, limit, offset)
+	} else if p.hybrid {
+		// Experimental: fuse semantic similarity into BM25 ranking
+		page, err = p.indexer.SearchHybridPage(query, limit, offset, search.DefaultFusionConfig)
+	} else {
+		// Search across all servers
+		page, err = p.indexer.SearchBM25Page(query, limit, offset)
+	}
+	if err != nil {
+		return nil, err
+	}
+	page.Results = p.applyQueryAffinity(query, page.Results)
+	return page, nil
+}
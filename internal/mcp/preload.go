@@ -0,0 +1,51 @@
+package mcp
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+)
+
+// preloadServers returns the names of the enabled servers marked preload,
+// which the pool never stops to make room.
+func preloadServers(cfg *config.Config) []string {
+	var names []string
+	for name, server := range cfg.Servers {
+		if server.Preload && !server.Disabled {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// PreloadServers spawns and initializes every server marked preload, in
+// parallel, and blocks until all have started or failed. Failures are
+// logged; the server is retried on first use as usual.
+func (s *Server) PreloadServers() {
+	s.configMu.RLock()
+	servers := make(map[string]*config.ServerConfig)
+	for name, cfg := range s.config.Servers {
+		if cfg.Preload && !cfg.Disabled {
+			servers[name] = cfg
+		}
+	}
+	s.configMu.RUnlock()
+
+	var wg sync.WaitGroup
+	for name, cfg := range servers {
+		wg.Add(1)
+		go func(name string, cfg *config.ServerConfig) {
+			defer wg.Done()
+
+			started := time.Now()
+			if err := s.spawner.Prespawn(name, cfg); err != nil {
+				log.Printf("Warning: failed to preload %s: %v", name, err)
+				return
+			}
+			log.Printf("Preloaded %s in %v", name, time.Since(started).Round(time.Millisecond))
+		}(name, cfg)
+	}
+	wg.Wait()
+}
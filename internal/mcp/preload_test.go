@@ -0,0 +1,74 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+)
+
+func TestPreloadServers(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	script := writeFakeServer(t, "1.0.0", `[]`)
+	cfg := config.NewConfig()
+	cfg.Servers["jira"] = &config.ServerConfig{Command: "sh", Args: []string{script}, Preload: true}
+	cfg.Servers["figma"] = &config.ServerConfig{Command: "sh", Args: []string{script}}
+	cfg.Servers["broken"] = &config.ServerConfig{Command: "/nonexistent/tool-hub-test-server", Preload: true}
+
+	server := NewServer(cfg)
+	defer server.Close()
+
+	server.PreloadServers()
+
+	if _, ok := server.spawner.ServerInfo("jira"); !ok {
+		t.Error("preload server should be running")
+	}
+	if _, ok := server.spawner.ServerInfo("figma"); ok {
+		t.Error("servers without preload should stay lazy")
+	}
+	if got := server.metrics.spawns.Value("jira"); got != 1 {
+		t.Errorf("expected one spawn for jira, got %v", got)
+	}
+}
+
+// TestPreloadSurvivesDiscovery verifies indexing every server keeps
+// preload servers running under a small pool, and that spawns made for
+// discovery do not count against it.
+func TestPreloadSurvivesDiscovery(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	script := writeFakeServer(t, "1.0.0", `[{"name":"search","description":"Search"}]`)
+	cfg := config.NewConfig()
+	cfg.Settings.ProcessPoolSize = 1
+	cfg.Servers["jira"] = &config.ServerConfig{Command: "sh", Args: []string{script}, Preload: true}
+	for _, name := range []string{"figma", "slack", "notion"} {
+		cfg.Servers[name] = &config.ServerConfig{Command: "sh", Args: []string{script}}
+	}
+
+	server := NewServer(cfg)
+	defer server.Close()
+
+	server.PreloadServers()
+	if err := server.IndexTools(); err != nil {
+		t.Fatalf("IndexTools failed: %v", err)
+	}
+	if got := len(server.spawner.Stats()); got != 4 {
+		t.Errorf("discovery should not stop servers, %d running", got)
+	}
+
+	// A call claims one slot; the next stops an idle pooled server only
+	for _, name := range []string{"figma", "slack"} {
+		if _, err := server.spawner.GetTools(name, cfg.Servers[name]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, ok := server.spawner.ServerInfo("jira"); !ok {
+		t.Error("preload server should never be evicted")
+	}
+	if _, ok := server.spawner.ServerInfo("figma"); ok {
+		t.Error("least recently used pooled server should be evicted")
+	}
+	if _, ok := server.spawner.ServerInfo("notion"); !ok {
+		t.Error("servers only spawned for discovery should not be evicted")
+	}
+}
@@ -0,0 +1,100 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/khanglvm/tool-hub-mcp/internal/spawner"
+)
+
+// queueNotifier reports a hub_execute call waiting on the server's
+// concurrency limit. With a progress token the client gets a
+// notifications/progress "busy, queued" update instead of silence.
+func (s *Server) queueNotifier(sess *session, serverName string, progressToken interface{}) spawner.QueueFunc {
+	return func(position int) {
+		log.Printf("Server %s is busy, call queued at position %d", serverName, position)
+		if progressToken == nil {
+			return
+		}
+
+		s.orStdio(sess).send("notifications/progress", map[string]interface{}{
+			"progressToken": progressToken,
+			"progress":      0,
+			"message":       fmt.Sprintf("busy, queued: server '%s' is at its concurrent call limit (position %d)", serverName, position),
+			"_meta": map[string]interface{}{
+				"status":   "queued",
+				"server":   serverName,
+				"position": position,
+			},
+		})
+	}
+}
+
+// progressRelay routes a child's progress to the calling client.
+type progressRelay struct {
+	session *session
+	token   interface{}
+}
+
+// relayProgress registers a child-side progress token for a client token.
+// Children get their own token so that concurrent calls on one child never
+// share one. The returned release must be called when the call ends.
+func (s *Server) relayProgress(sess *session, clientToken interface{}) (string, func()) {
+	childToken := "toolhub-" + uuid.New().String()
+
+	s.progressMu.Lock()
+	s.progressRelays[childToken] = progressRelay{session: s.orStdio(sess), token: clientToken}
+	s.progressMu.Unlock()
+
+	return childToken, func() {
+		s.progressMu.Lock()
+		delete(s.progressRelays, childToken)
+		s.progressMu.Unlock()
+	}
+}
+
+// forwardProgress relays a child's notifications/progress to the client
+// under the client's token. Progress for unknown tokens is dropped.
+func (s *Server) forwardProgress(params json.RawMessage) {
+	var progress map[string]interface{}
+	if err := json.Unmarshal(params, &progress); err != nil {
+		return
+	}
+	childToken, _ := progress["progressToken"].(string)
+
+	s.progressMu.Lock()
+	relay, ok := s.progressRelays[childToken]
+	s.progressMu.Unlock()
+	if !ok {
+		return
+	}
+
+	progress["progressToken"] = relay.token
+	relay.session.send("notifications/progress", progress)
+}
+
+// logChildMessage writes a child's notifications/message to the hub log.
+func logChildMessage(serverName string, params json.RawMessage) {
+	var msg struct {
+		Level  string          `json:"level"`
+		Logger string          `json:"logger"`
+		Data   json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(params, &msg); err != nil {
+		return
+	}
+
+	// Plain string data is logged without JSON quoting
+	var text string
+	if err := json.Unmarshal(msg.Data, &text); err != nil {
+		text = string(msg.Data)
+	}
+
+	source := serverName
+	if msg.Logger != "" {
+		source += "/" + msg.Logger
+	}
+	log.Printf("[%s] %s: %s", source, msg.Level, text)
+}
@@ -0,0 +1,43 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+)
+
+func TestQueueNotifierSendsProgress(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	server := NewServer(config.NewConfig())
+	defer server.Close()
+	var out bytes.Buffer
+	server.out = &out
+
+	server.queueNotifier(nil, "jira", nil)(1)
+	if out.Len() != 0 {
+		t.Fatalf("no notification expected without a progress token, got %s", out.String())
+	}
+
+	server.queueNotifier(nil, "jira", "tok-1")(2)
+
+	var msg struct {
+		Method string `json:"method"`
+		Params struct {
+			ProgressToken string                 `json:"progressToken"`
+			Message       string                 `json:"message"`
+			Meta          map[string]interface{} `json:"_meta"`
+		} `json:"params"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &msg); err != nil {
+		t.Fatalf("invalid notification %q: %v", out.String(), err)
+	}
+	if msg.Method != "notifications/progress" || msg.Params.ProgressToken != "tok-1" {
+		t.Errorf("unexpected notification: %+v", msg)
+	}
+	if msg.Params.Meta["status"] != "queued" || msg.Params.Meta["server"] != "jira" || msg.Params.Meta["position"] != 2.0 {
+		t.Errorf("unexpected queue details: %v", msg.Params.Meta)
+	}
+}
@@ -0,0 +1,57 @@
+package mcp
+
+import "github.com/khanglvm/tool-hub-mcp/internal/protocol"
+
+// metaToolAnnotations describes each meta-tool's behavior for clients on
+// 2025-03-26 or later, which may use the hints to skip confirmations.
+var metaToolAnnotations = map[string]map[string]interface{}{
+	"hub_search":       {"title": "Search Tools", "readOnlyHint": true, "openWorldHint": false},
+	"hub_list":         {"title": "List Servers", "readOnlyHint": true, "openWorldHint": false},
+	"hub_discover":     {"title": "Discover Server Tools", "readOnlyHint": true, "openWorldHint": false},
+	"hub_execute":      {"title": "Execute Tool", "readOnlyHint": false, "destructiveHint": true, "openWorldHint": true},
+	"hub_schema":       {"title": "Get Tool Schema", "readOnlyHint": true, "openWorldHint": false},
+	"hub_help":         {"title": "Get Tool Help", "readOnlyHint": true, "openWorldHint": false},
+	"hub_status":       {"title": "Server Status", "readOnlyHint": true, "openWorldHint": false},
+	"hub_fetch_result": {"title": "Fetch Result Page", "readOnlyHint": true, "openWorldHint": false},
+	"hub_manage":       {"title": "Manage Servers", "readOnlyHint": false, "destructiveHint": true, "idempotentHint": false, "openWorldHint": false},
+}
+
+// annotateMetaTools adds what the negotiated revision defines to the
+// tools/list entries: annotations from 2025-03-26, and a top-level title
+// from 2025-06-18. Older clients get the entries unchanged.
+func annotateMetaTools(tools []map[string]interface{}, version string) {
+	if !protocol.HasToolAnnotations(version) {
+		return
+	}
+	for _, tool := range tools {
+		name, _ := tool["name"].(string)
+		annotations, ok := metaToolAnnotations[name]
+		if !ok {
+			continue
+		}
+		tool["annotations"] = annotations
+		if protocol.HasStructuredOutput(version) {
+			tool["title"] = annotations["title"]
+		}
+	}
+}
+
+// adaptContent drops from a forwarded child result what the client's
+// revision does not define: structuredContent before 2025-06-18, and
+// resource_link blocks, which are replaced by a text block naming the URI.
+func adaptContent(content *toolContent, version string) {
+	if protocol.HasStructuredOutput(version) {
+		return
+	}
+	content.StructuredContent = nil
+	for i, block := range content.Content {
+		if block["type"] != "resource_link" {
+			continue
+		}
+		uri, _ := block["uri"].(string)
+		content.Content[i] = map[string]interface{}{
+			"type": "text",
+			"text": "Resource: " + uri,
+		}
+	}
+}
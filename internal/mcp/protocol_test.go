@@ -0,0 +1,123 @@
+package mcp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/protocol"
+)
+
+func initializeWith(t *testing.T, server *Server, version string) map[string]interface{} {
+	t.Helper()
+	resp, err := server.handleInitialize(&MCPRequest{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  "initialize",
+		Params:  json.RawMessage(`{"protocolVersion":"` + version + `","capabilities":{}}`),
+	})
+	if err != nil || resp.Error != nil {
+		t.Fatalf("initialize failed: %v %+v", err, resp.Error)
+	}
+	return resp.Result.(map[string]interface{})
+}
+
+func TestInitializeNegotiatesProtocolVersion(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	tests := map[string]string{
+		protocol.Version20241105: protocol.Version20241105,
+		protocol.Version20250326: protocol.Version20250326,
+		protocol.Version20250618: protocol.Version20250618,
+		"1999-01-01":             protocol.Latest,
+	}
+	for requested, want := range tests {
+		server := NewServer(config.NewConfig())
+		result := initializeWith(t, server, requested)
+		if result["protocolVersion"] != want {
+			t.Errorf("requested %s: got %v, want %s", requested, result["protocolVersion"], want)
+		}
+		_, hasTitle := result["serverInfo"].(map[string]interface{})["title"]
+		if hasTitle != protocol.HasStructuredOutput(want) {
+			t.Errorf("requested %s: serverInfo title present = %v", requested, hasTitle)
+		}
+		server.Close()
+	}
+}
+
+func TestToolsListAdaptsToProtocolVersion(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	server := NewServer(config.NewConfig())
+	defer server.Close()
+
+	listTools := func() []map[string]interface{} {
+		resp, err := server.handleToolsList(&MCPRequest{ID: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.Result.(map[string]interface{})["tools"].([]map[string]interface{})
+	}
+
+	initializeWith(t, server, protocol.Version20241105)
+	for _, tool := range listTools() {
+		if _, ok := tool["annotations"]; ok {
+			t.Errorf("%v: annotations sent to a 2024-11-05 client", tool["name"])
+		}
+	}
+
+	initializeWith(t, server, protocol.Version20250618)
+	for _, tool := range listTools() {
+		annotations, ok := tool["annotations"].(map[string]interface{})
+		if !ok || tool["title"] == nil {
+			t.Errorf("%v: missing annotations or title for a 2025-06-18 client", tool["name"])
+			continue
+		}
+		if tool["name"] == "hub_search" && annotations["readOnlyHint"] != true {
+			t.Errorf("hub_search should be read-only, got %+v", annotations)
+		}
+	}
+}
+
+func TestPassthroughAdaptsContentToProtocolVersion(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	server := NewServer(config.NewConfig())
+	defer server.Close()
+
+	response := func() map[string]interface{} {
+		return map[string]interface{}{
+			"content": []interface{}{
+				map[string]interface{}{"type": "resource_link", "uri": "file:///report.pdf", "name": "report"},
+			},
+			"structuredContent": map[string]interface{}{"pages": 3},
+		}
+	}
+
+	initializeWith(t, server, protocol.Version20250618)
+	got := server.passthroughContent(response(), server.stdio.protocol())
+	if got.StructuredContent == nil || got.Content[0]["type"] != "resource_link" {
+		t.Errorf("2025-06-18 client should get content unchanged, got %+v", got)
+	}
+
+	initializeWith(t, server, protocol.Version20250326)
+	got = server.passthroughContent(response(), server.stdio.protocol())
+	if got.StructuredContent != nil {
+		t.Errorf("structuredContent sent to a 2025-03-26 client: %+v", got.StructuredContent)
+	}
+	if got.Content[0]["type"] != "text" || got.Content[0]["text"] != "Resource: file:///report.pdf" {
+		t.Errorf("resource_link not converted for a 2025-03-26 client: %+v", got.Content[0])
+	}
+}
+
+func TestPing(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	server := NewServer(config.NewConfig())
+	defer server.Close()
+
+	resp, err := server.handleRequest([]byte(`{"jsonrpc":"2.0","id":7,"method":"ping"}`))
+	if err != nil || resp.Error != nil {
+		t.Fatalf("ping failed: %v %+v", err, resp)
+	}
+}
@@ -0,0 +1,154 @@
+package mcp
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/ratelimit"
+)
+
+// acquireQuota consumes one request from a server's provider quota.
+// Once the quota is nearly exhausted (RateLimit.NearExhaustion) calls are
+// paced, and calls that would exceed it wait for a request to leave the
+// window. Short waits (RateLimit.MaxWait) are slept through, otherwise a
+// structured rate_limited ToolError is returned so the child never
+// reaches the provider's 429 response.
+func (s *Server) acquireQuota(serverName string, cfg *config.ServerConfig) error {
+	if cfg.RateLimit == nil {
+		return nil
+	}
+
+	window := s.quotaWindow(serverName, cfg.RateLimit)
+	if window == nil {
+		return nil
+	}
+
+	ok, retryAfter := window.Allow(time.Now())
+	if ok {
+		return nil
+	}
+
+	if retryAfter <= cfg.RateLimit.MaxWait() {
+		log.Printf("Rate limit reached for %s, delaying call by %v", serverName, retryAfter.Round(time.Millisecond))
+		select {
+		case <-time.After(retryAfter):
+		case <-s.ctx.Done():
+			return s.ctx.Err()
+		}
+		if ok, retryAfter = window.Allow(time.Now()); ok {
+			return nil
+		}
+	}
+
+	return &ToolError{
+		Code: "rate_limited",
+		Message: fmt.Sprintf("rate limit near exhaustion for server '%s' (%d requests per %s), retry after %ds",
+			serverName, window.Limit(), window.Period(), int(math.Ceil(retryAfter.Seconds()))),
+		Details: map[string]interface{}{
+			"server":            serverName,
+			"limit":             window.Limit(),
+			"period":            window.Period().String(),
+			"retryAfterSeconds": int(math.Ceil(retryAfter.Seconds())),
+		},
+	}
+}
+
+// quotaWindow returns the sliding window for a server, creating it on first
+// use or when the configured limit changed. Returns nil for invalid limits.
+func (s *Server) quotaWindow(serverName string, limit *config.RateLimit) *ratelimit.Window {
+	period, err := limit.Window()
+	if err != nil || limit.Requests <= 0 {
+		log.Printf("Warning: ignoring invalid rate limit for %s: %v", serverName, err)
+		return nil
+	}
+
+	s.quotasMu.Lock()
+	defer s.quotasMu.Unlock()
+
+	window, exists := s.quotas[serverName]
+	if !exists || window.Limit() != limit.Requests || window.Period() != period || window.PaceFrom() != limit.PaceFrom() {
+		window = ratelimit.NewPacedWindow(limit.Requests, period, limit.PaceFrom())
+		s.quotas[serverName] = window
+	}
+	return window
+}
+
+// throttleCall takes a token from the calling client's bucket and then the
+// server's bucket (Settings.ClientThrottle, ServerConfig.Throttle). Unlike
+// provider quotas, throttled calls are never delayed: the agent is told
+// when to retry so a runaway loop backs off instead of queueing up.
+func (s *Server) throttleCall(sess *session, serverName string, cfg *config.ServerConfig) error {
+	s.configMu.RLock()
+	clientLimit := s.config.Settings.ClientThrottleConfig()
+	s.configMu.RUnlock()
+
+	now := time.Now()
+	if clientLimit != nil {
+		if ok, retryAfter := sess.throttleBucket(clientLimit).Take(now); !ok {
+			return throttledError("client", serverName, clientLimit, retryAfter)
+		}
+	}
+	if cfg.Throttle != nil {
+		if ok, retryAfter := s.serverBucket(serverName, cfg.Throttle).Take(now); !ok {
+			return throttledError("server", serverName, cfg.Throttle, retryAfter)
+		}
+	}
+	return nil
+}
+
+// throttledError builds the rate_limited ToolError for a rejected call.
+// scope is "client" or "server", naming the throttle that was exceeded.
+func throttledError(scope, serverName string, limit *config.Throttle, retryAfter time.Duration) *ToolError {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	message := fmt.Sprintf("too many calls to server '%s' (%g per minute), retry after %ds",
+		serverName, limit.CallsPerMinute, seconds)
+	if scope == "client" {
+		message = fmt.Sprintf("too many hub_execute calls from this client (%g per minute), retry after %ds",
+			limit.CallsPerMinute, seconds)
+	}
+	return &ToolError{
+		Code:    "rate_limited",
+		Message: message,
+		Details: map[string]interface{}{
+			"scope":             scope,
+			"server":            serverName,
+			"callsPerMinute":    limit.CallsPerMinute,
+			"burst":             limit.BurstSize(),
+			"retryAfterSeconds": seconds,
+		},
+	}
+}
+
+// serverBucket returns the token bucket for a server, creating it on first
+// use or when the configured throttle changed.
+func (s *Server) serverBucket(serverName string, limit *config.Throttle) *ratelimit.Bucket {
+	s.quotasMu.Lock()
+	defer s.quotasMu.Unlock()
+
+	bucket, exists := s.buckets[serverName]
+	if !exists || !bucketMatches(bucket, limit) {
+		bucket = ratelimit.NewBucket(limit.CallsPerMinute, limit.BurstSize())
+		s.buckets[serverName] = bucket
+	}
+	return bucket
+}
+
+// throttleBucket returns the session's token bucket, recreating it when
+// the configured throttle changed.
+func (sess *session) throttleBucket(limit *config.Throttle) *ratelimit.Bucket {
+	sess.bucketMu.Lock()
+	defer sess.bucketMu.Unlock()
+
+	if sess.bucket == nil || !bucketMatches(sess.bucket, limit) {
+		sess.bucket = ratelimit.NewBucket(limit.CallsPerMinute, limit.BurstSize())
+	}
+	return sess.bucket
+}
+
+// bucketMatches reports whether bucket was built from limit.
+func bucketMatches(bucket *ratelimit.Bucket, limit *config.Throttle) bool {
+	return bucket.PerMinute() == limit.CallsPerMinute && bucket.Burst() == limit.BurstSize()
+}
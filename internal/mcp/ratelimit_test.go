@@ -0,0 +1,168 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+)
+
+func TestAcquireQuota(t *testing.T) {
+	noWait := 0
+	cfg := &config.Config{
+		Servers: map[string]*config.ServerConfig{
+			"github": {
+				Command:   "echo",
+				RateLimit: &config.RateLimit{Requests: 2, Period: "1h", MaxWaitSeconds: &noWait},
+			},
+		},
+	}
+
+	server := NewServer(cfg)
+	defer server.Close()
+
+	serverCfg := cfg.Servers["github"]
+	for i := 0; i < 2; i++ {
+		if err := server.acquireQuota("github", serverCfg); err != nil {
+			t.Fatalf("call %d should be allowed: %v", i+1, err)
+		}
+	}
+
+	err := server.acquireQuota("github", serverCfg)
+	var toolErr *ToolError
+	if !errors.As(err, &toolErr) {
+		t.Fatalf("expected ToolError, got %v", err)
+	}
+	if toolErr.Code != "rate_limited" {
+		t.Errorf("expected rate_limited code, got %s", toolErr.Code)
+	}
+	if retry, ok := toolErr.Details["retryAfterSeconds"].(int); !ok || retry <= 0 {
+		t.Errorf("expected positive retryAfterSeconds, got %v", toolErr.Details["retryAfterSeconds"])
+	}
+}
+
+func TestAcquireQuotaUnlimited(t *testing.T) {
+	cfg := &config.Config{
+		Servers: map[string]*config.ServerConfig{
+			"jira": {Command: "echo"},
+		},
+	}
+
+	server := NewServer(cfg)
+	defer server.Close()
+
+	for i := 0; i < 100; i++ {
+		if err := server.acquireQuota("jira", cfg.Servers["jira"]); err != nil {
+			t.Fatalf("unlimited server should never be limited: %v", err)
+		}
+	}
+}
+
+func TestHubExecuteRateLimitedResult(t *testing.T) {
+	noWait := 0
+	cfg := &config.Config{
+		Servers: map[string]*config.ServerConfig{
+			"github": {
+				Command:   "echo",
+				RateLimit: &config.RateLimit{Requests: 1, Period: "1h", MaxWaitSeconds: &noWait},
+			},
+		},
+	}
+
+	server := NewServer(cfg)
+	defer server.Close()
+
+	// Exhaust the quota without spawning the child
+	server.acquireQuota("github", cfg.Servers["github"])
+
+	req := MCPRequest{
+		JSONRPC: "2.0",
+		ID:      7,
+		Method:  "tools/call",
+		Params:  json.RawMessage(`{"name":"hub_execute","arguments":{"server":"github","tool":"list_repos"}}`),
+	}
+
+	resp, err := server.handleToolsCall(&req)
+	if err != nil {
+		t.Fatalf("handleToolsCall failed: %v", err)
+	}
+	if resp.Error != nil {
+		t.Fatalf("expected structured tool result, got JSON-RPC error: %v", resp.Error)
+	}
+
+	result := resp.Result.(map[string]interface{})
+	if result["isError"] != true {
+		t.Error("expected isError: true")
+	}
+	text := result["content"].([]map[string]interface{})[0]["text"].(string)
+	if !strings.Contains(text, `"error":"rate_limited"`) || !strings.Contains(text, "retryAfterSeconds") {
+		t.Errorf("unexpected rate limit payload: %s", text)
+	}
+}
+
+func TestThrottleCall(t *testing.T) {
+	cfg := &config.Config{
+		Servers: map[string]*config.ServerConfig{
+			"search": {Command: "echo", Throttle: &config.Throttle{CallsPerMinute: 1, Burst: 2}},
+			"docs":   {Command: "echo"},
+		},
+		Settings: &config.Settings{ClientThrottle: &config.Throttle{CallsPerMinute: 1, Burst: 3}},
+	}
+
+	server := NewServer(cfg)
+	defer server.Close()
+
+	// The server throttle is shared by every client
+	other := newSession("other", nil)
+	if err := server.throttleCall(server.stdio, "search", cfg.Servers["search"]); err != nil {
+		t.Fatalf("first call should be allowed: %v", err)
+	}
+	if err := server.throttleCall(other, "search", cfg.Servers["search"]); err != nil {
+		t.Fatalf("second call should be allowed: %v", err)
+	}
+	err := server.throttleCall(other, "search", cfg.Servers["search"])
+	var toolErr *ToolError
+	if !errors.As(err, &toolErr) || toolErr.Code != "rate_limited" {
+		t.Fatalf("expected rate_limited ToolError, got %v", err)
+	}
+	if toolErr.Details["scope"] != "server" || toolErr.Details["retryAfterSeconds"] != 60 {
+		t.Errorf("unexpected details: %v", toolErr.Details)
+	}
+
+	// The client throttle spans servers: stdio has taken one of its three
+	if err := server.throttleCall(server.stdio, "docs", cfg.Servers["docs"]); err != nil {
+		t.Fatalf("call within the client burst should be allowed: %v", err)
+	}
+	if err := server.throttleCall(server.stdio, "docs", cfg.Servers["docs"]); err != nil {
+		t.Fatalf("call within the client burst should be allowed: %v", err)
+	}
+	err = server.throttleCall(server.stdio, "docs", cfg.Servers["docs"])
+	if !errors.As(err, &toolErr) || toolErr.Details["scope"] != "client" {
+		t.Fatalf("expected a client-scoped rate_limited error, got %v", err)
+	}
+	if !strings.Contains(toolErr.Message, "retry after") {
+		t.Errorf("message should say when to retry: %s", toolErr.Message)
+	}
+}
+
+func TestThrottleRebuiltOnConfigChange(t *testing.T) {
+	limit := &config.Throttle{CallsPerMinute: 1, Burst: 1}
+	cfg := &config.Config{Servers: map[string]*config.ServerConfig{
+		"search": {Command: "echo", Throttle: limit},
+	}}
+
+	server := NewServer(cfg)
+	defer server.Close()
+
+	server.throttleCall(server.stdio, "search", cfg.Servers["search"])
+	if err := server.throttleCall(server.stdio, "search", cfg.Servers["search"]); err == nil {
+		t.Fatal("expected the throttle to reject the second call")
+	}
+
+	cfg.Servers["search"].Throttle = &config.Throttle{CallsPerMinute: 60, Burst: 10}
+	if err := server.throttleCall(server.stdio, "search", cfg.Servers["search"]); err != nil {
+		t.Errorf("a raised throttle should start a fresh bucket: %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package mcp
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/storage"
+)
+
+func TestHubExecuteResolvesAlias(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg := config.NewConfig()
+	cfg.Servers["jira"] = &config.ServerConfig{Command: "/nonexistent/jira", Aliases: []string{"tickets"}}
+	server := NewServer(cfg)
+	defer server.Close()
+
+	server.handleToolsCall(&MCPRequest{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  "tools/call",
+		Params:  json.RawMessage(`{"name":"hub_execute","arguments":{"server":"tickets","tool":"search"}}`),
+	})
+
+	entries, err := server.storage.GetAuditEntries(storage.AuditFilter{})
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected one audit entry, got %+v, %v", entries, err)
+	}
+	if entries[0].Server != "jira" {
+		t.Errorf("alias should resolve to 'jira', got %q", entries[0].Server)
+	}
+}
+
+func TestHubManageRename(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg := config.NewConfig()
+	cfg.Servers["jira"] = &config.ServerConfig{Command: "/nonexistent/jira"}
+	server := NewServer(cfg)
+	defer server.Close()
+
+	if _, err := server.execHubManage("rename", "jira", "", "", nil, nil); err == nil {
+		t.Error("expected error without newName")
+	}
+
+	if _, err := server.execHubManage("rename", "jira", "jira-cloud", "", nil, nil); err != nil {
+		t.Fatalf("rename failed: %v", err)
+	}
+	if _, ok := server.config.Servers["jiraCloud"]; !ok {
+		t.Fatalf("expected server 'jiraCloud', got %v", server.getServerNamesList())
+	}
+	if _, ok := server.config.Servers["jira"]; ok {
+		t.Error("old server name should be gone")
+	}
+
+	saved, err := config.Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if _, ok := saved.Servers["jiraCloud"]; !ok {
+		t.Error("rename was not saved")
+	}
+
+	if _, err := server.execHubManage("rename", "missing", "other", "", nil, nil); err == nil {
+		t.Error("expected error renaming unknown server")
+	}
+}
+
+func TestHubManageRemoveResolvesName(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg := config.NewConfig()
+	cfg.Servers["jiraMcp"] = &config.ServerConfig{Command: "/nonexistent/jira"}
+	server := NewServer(cfg)
+	defer server.Close()
+
+	if _, err := server.execHubManage("remove", "jiraMc", "", "", nil, nil); err == nil || !strings.Contains(err.Error(), "Did you mean 'jiraMcp'?") {
+		t.Errorf("expected a suggestion, got %v", err)
+	}
+	if _, err := server.execHubManage("remove", "jira-mcp", "", "", nil, nil); err != nil {
+		t.Fatalf("remove failed: %v", err)
+	}
+	if _, ok := server.config.Servers["jiraMcp"]; ok {
+		t.Error("server should be removed")
+	}
+
+	// Added names are normalized the same way
+	if _, err := server.execHubManage("add", "outline-wiki", "", "/nonexistent/outline", nil, nil); err != nil {
+		t.Fatalf("add failed: %v", err)
+	}
+	added, ok := server.config.Servers["outlineWiki"]
+	if !ok || added.OriginalName != "outline-wiki" {
+		t.Errorf("expected server 'outlineWiki', got %v", server.getServerNamesList())
+	}
+}
@@ -0,0 +1,99 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/catalog"
+	"github.com/khanglvm/tool-hub-mcp/internal/search"
+)
+
+// indexResourceURI names the resource holding the whole tool catalog.
+const indexResourceURI = "toolhub://index"
+
+// indexResourceMimeType is the type of the catalog: JSONL, one tool per
+// line in the export-index format.
+const indexResourceMimeType = "application/jsonl"
+
+// handleResourcesList lists the hub's resources: the tool catalog, which
+// an agent can read once instead of paging through hub_search.
+func (s *Server) handleResourcesList(req *MCPRequest) (*MCPResponse, error) {
+	return &MCPResponse{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result: map[string]interface{}{
+			"resources": []map[string]interface{}{
+				{
+					"uri":         indexResourceURI,
+					"name":        "index",
+					"title":       "Tool Index",
+					"description": "Every indexed tool of every server, one JSON object per line with tool, server, description and inputSchema (the export-index format).",
+					"mimeType":    indexResourceMimeType,
+				},
+			},
+		},
+	}, nil
+}
+
+// handleResourcesRead returns the content of a resource.
+func (s *Server) handleResourcesRead(req *MCPRequest) (*MCPResponse, error) {
+	var params struct {
+		URI string `json:"uri"`
+	}
+	if err := json.Unmarshal(req.Params, &params); err != nil || params.URI == "" {
+		return &MCPResponse{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Error:   &MCPError{Code: -32602, Message: "Invalid params"},
+		}, nil
+	}
+	if params.URI != indexResourceURI {
+		return &MCPResponse{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Error:   &MCPError{Code: -32002, Message: fmt.Sprintf("Resource not found: %s", params.URI)},
+		}, nil
+	}
+
+	text, err := s.indexResource()
+	if err != nil {
+		return &MCPResponse{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Error:   &MCPError{Code: -32603, Message: err.Error()},
+		}, nil
+	}
+
+	return &MCPResponse{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result: map[string]interface{}{
+			"contents": []map[string]interface{}{
+				{"uri": indexResourceURI, "mimeType": indexResourceMimeType, "text": text},
+			},
+		},
+	}, nil
+}
+
+// indexResource writes the search index as JSONL, a page at a time.
+func (s *Server) indexResource() (string, error) {
+	if s.indexer == nil {
+		return "", fmt.Errorf("tool index unavailable")
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	err := s.indexer.EachTool(search.DefaultToolPageSize, func(r search.SearchResult) error {
+		return encoder.Encode(catalog.Entry{
+			Tool:        r.ToolName,
+			Server:      r.ServerName,
+			Description: r.Description,
+			InputSchema: r.InputSchema,
+		})
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to read tool index: %w", err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,49 @@
+package mcp
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/catalog"
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+)
+
+func TestIndexResource(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	script := writeFakeServer(t, "1.0.0",
+		`[{"name":"create_issue","description":"Create an issue","inputSchema":{"type":"object"}},{"name":"get_issue","description":"Get an issue","inputSchema":{"type":"object"}}]`)
+	cfg := config.NewConfig()
+	cfg.Servers["jira"] = &config.ServerConfig{Command: "sh", Args: []string{script}}
+	server := NewServer(cfg)
+	defer server.Close()
+	if err := server.IndexTools(); err != nil {
+		t.Fatalf("IndexTools failed: %v", err)
+	}
+
+	resp, _ := server.dispatch(&MCPRequest{JSONRPC: "2.0", ID: 1, Method: "resources/list"})
+	out, _ := json.Marshal(resp.Result)
+	if !strings.Contains(string(out), indexResourceURI) {
+		t.Errorf("resources/list missing %s: %s", indexResourceURI, out)
+	}
+
+	resp, _ = server.dispatch(&MCPRequest{JSONRPC: "2.0", ID: 2, Method: "resources/read", Params: json.RawMessage(`{"uri":"toolhub://index"}`)})
+	if resp.Error != nil {
+		t.Fatalf("resources/read failed: %+v", resp.Error)
+	}
+	contents := resp.Result.(map[string]interface{})["contents"].([]map[string]interface{})
+	text := contents[0]["text"].(string)
+	entries, err := catalog.Parse(strings.NewReader(text))
+	if err != nil {
+		t.Fatalf("resource is not a valid index: %v\n%s", err, text)
+	}
+	if len(entries) != 2 || entries[0].Server != "jira" || entries[0].Tool != "create_issue" {
+		t.Errorf("unexpected entries: %+v", entries)
+	}
+
+	resp, _ = server.dispatch(&MCPRequest{JSONRPC: "2.0", ID: 3, Method: "resources/read", Params: json.RawMessage(`{"uri":"toolhub://missing"}`)})
+	if resp.Error == nil || resp.Error.Code != -32002 {
+		t.Errorf("expected resource not found, got %+v", resp)
+	}
+}
@@ -0,0 +1,176 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// resultTTL is how long a truncated result stays retrievable.
+	resultTTL = 30 * time.Minute
+
+	// maxStoredResults bounds memory held by truncated results;
+	// the oldest entry is evicted first.
+	maxStoredResults = 32
+)
+
+// storedResult is a full hub_execute output kept for paging.
+type storedResult struct {
+	data    string
+	created time.Time
+}
+
+// resultStore keeps oversized hub_execute outputs in memory so the client
+// can page through them with hub_fetch_result. Thread-safe.
+type resultStore struct {
+	mu      sync.Mutex
+	results map[string]*storedResult
+	order   []string // result IDs, oldest first
+}
+
+// newResultStore creates an empty result store.
+func newResultStore() *resultStore {
+	return &resultStore{results: make(map[string]*storedResult)}
+}
+
+// put stores data and returns its result ID.
+func (rs *resultStore) put(data string) string {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	rs.evictLocked(time.Now())
+	for len(rs.order) >= maxStoredResults {
+		delete(rs.results, rs.order[0])
+		rs.order = rs.order[1:]
+	}
+
+	id := uuid.New().String()
+	rs.results[id] = &storedResult{data: data, created: time.Now()}
+	rs.order = append(rs.order, id)
+	return id
+}
+
+// get returns the stored data, or false if unknown or expired.
+func (rs *resultStore) get(id string) (string, bool) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	rs.evictLocked(time.Now())
+	r, ok := rs.results[id]
+	if !ok {
+		return "", false
+	}
+	return r.data, true
+}
+
+// evictLocked drops expired results. Caller must hold mu.
+func (rs *resultStore) evictLocked(now time.Time) {
+	for len(rs.order) > 0 {
+		r := rs.results[rs.order[0]]
+		if r != nil && now.Sub(r.created) < resultTTL {
+			return
+		}
+		delete(rs.results, rs.order[0])
+		rs.order = rs.order[1:]
+	}
+}
+
+// responseBudget returns the inline hub_execute output limit in bytes (0 = unlimited).
+// Thread-safe: acquires read lock.
+func (s *Server) responseBudget() int {
+	s.configMu.RLock()
+	defer s.configMu.RUnlock()
+
+	return s.config.Settings.ResponseBudget()
+}
+
+// budgetResponse returns result unchanged when it fits the response budget.
+// Otherwise the full result is stored and the first chunk is returned with
+// a resultId the client can pass to hub_fetch_result for the rest.
+func (s *Server) budgetResponse(result string) string {
+	budget := s.responseBudget()
+	if budget <= 0 || len(result) <= budget {
+		return result
+	}
+
+	resultID := s.results.put(result)
+	chunk := chunkAt(result, 0, budget)
+	return marshalChunk(map[string]interface{}{
+		"truncated":  true,
+		"resultId":   resultID,
+		"totalBytes": len(result),
+		"offset":     0,
+		"nextOffset": len(chunk),
+		"content":    chunk,
+		"hint":       "Output exceeded the response budget. Call hub_fetch_result with this resultId and nextOffset to read more.",
+	})
+}
+
+// execHubFetchResult returns a chunk of a stored result starting at offset.
+// length defaults to the response budget.
+func (s *Server) execHubFetchResult(resultID string, offset, length int) (string, error) {
+	data, ok := s.results.get(resultID)
+	if !ok {
+		return "", &ToolError{
+			Code:    "result_not_found",
+			Message: fmt.Sprintf("result '%s' not found or expired (results are kept for %s)", resultID, resultTTL),
+		}
+	}
+
+	if offset < 0 || offset > len(data) {
+		return "", &ToolError{
+			Code:    "invalid_offset",
+			Message: fmt.Sprintf("offset %d is outside the result (totalBytes: %d)", offset, len(data)),
+		}
+	}
+	if length <= 0 {
+		length = s.responseBudget()
+	}
+	if length <= 0 {
+		length = len(data)
+	}
+
+	chunk := chunkAt(data, offset, length)
+	response := map[string]interface{}{
+		"resultId":   resultID,
+		"totalBytes": len(data),
+		"offset":     offset,
+		"content":    chunk,
+	}
+	if next := offset + len(chunk); next < len(data) {
+		response["nextOffset"] = next
+	}
+	return marshalChunk(response), nil
+}
+
+// chunkAt returns up to length bytes of data starting at offset, shortened
+// so it never ends inside a multi-byte UTF-8 sequence.
+func chunkAt(data string, offset, length int) string {
+	end := offset + length
+	if end >= len(data) {
+		return data[offset:]
+	}
+	for end > offset && !utf8.RuneStart(data[end]) {
+		end--
+	}
+	if end == offset {
+		// A single rune larger than length; return it whole to make progress
+		_, size := utf8.DecodeRuneInString(data[offset:])
+		end = offset + size
+	}
+	return data[offset:end]
+}
+
+// marshalChunk encodes a paging response as compact JSON.
+func marshalChunk(response map[string]interface{}) string {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return fmt.Sprintf(`{"error":%q}`, err.Error())
+	}
+	return string(data)
+}
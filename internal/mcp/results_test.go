@@ -0,0 +1,116 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+)
+
+func TestBudgetResponsePagesThroughFullResult(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg := config.NewConfig()
+	cfg.Settings.MaxResponseBytes = 10
+	server := NewServer(cfg)
+	defer server.Close()
+
+	if got := server.budgetResponse("short"); got != "short" {
+		t.Errorf("small result should pass through, got %q", got)
+	}
+
+	full := "héllo wörld, this is a long result"
+	var first struct {
+		Truncated  bool   `json:"truncated"`
+		ResultID   string `json:"resultId"`
+		TotalBytes int    `json:"totalBytes"`
+		NextOffset int    `json:"nextOffset"`
+		Content    string `json:"content"`
+	}
+	if err := json.Unmarshal([]byte(server.budgetResponse(full)), &first); err != nil {
+		t.Fatalf("truncated response is not JSON: %v", err)
+	}
+	if !first.Truncated || first.ResultID == "" || first.TotalBytes != len(full) {
+		t.Fatalf("unexpected truncated response: %+v", first)
+	}
+
+	reassembled := first.Content
+	offset := first.NextOffset
+	for offset > 0 {
+		out, err := server.execHubFetchResult(first.ResultID, offset, 0)
+		if err != nil {
+			t.Fatalf("execHubFetchResult failed: %v", err)
+		}
+		var page struct {
+			Content    string `json:"content"`
+			NextOffset int    `json:"nextOffset"`
+		}
+		if err := json.Unmarshal([]byte(out), &page); err != nil {
+			t.Fatal(err)
+		}
+		if len(page.Content) > 10 {
+			t.Errorf("page exceeds budget: %q", page.Content)
+		}
+		reassembled += page.Content
+		offset = page.NextOffset
+	}
+	if reassembled != full {
+		t.Errorf("reassembled %q, want %q", reassembled, full)
+	}
+}
+
+func TestBudgetResponseDisabled(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg := config.NewConfig()
+	cfg.Settings.MaxResponseBytes = -1
+	server := NewServer(cfg)
+	defer server.Close()
+
+	large := strings.Repeat("x", config.DefaultMaxResponseBytes*2)
+	if got := server.budgetResponse(large); got != large {
+		t.Error("disabled budget should never truncate")
+	}
+}
+
+func TestExecHubFetchResultErrors(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	server := NewServer(config.NewConfig())
+	defer server.Close()
+
+	var toolErr *ToolError
+	_, err := server.execHubFetchResult("missing", 0, 0)
+	if !errors.As(err, &toolErr) || toolErr.Code != "result_not_found" {
+		t.Errorf("expected result_not_found, got %v", err)
+	}
+
+	id := server.results.put("data")
+	_, err = server.execHubFetchResult(id, 99, 0)
+	if !errors.As(err, &toolErr) || toolErr.Code != "invalid_offset" {
+		t.Errorf("expected invalid_offset, got %v", err)
+	}
+}
+
+func TestChunkAtRespectsRuneBoundaries(t *testing.T) {
+	data := "aé€"
+	if got := chunkAt(data, 0, 2); got != "a" {
+		t.Errorf("chunkAt split a rune: %q", got)
+	}
+	if got := chunkAt(data, 3, 1); got != "€" {
+		t.Errorf("oversized rune should be returned whole, got %q", got)
+	}
+}
+
+func TestResultStoreEvictsOldest(t *testing.T) {
+	store := newResultStore()
+	first := store.put("first")
+	for i := 0; i < maxStoredResults; i++ {
+		store.put("more")
+	}
+	if _, ok := store.get(first); ok {
+		t.Error("oldest result should have been evicted")
+	}
+}
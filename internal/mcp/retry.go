@@ -0,0 +1,131 @@
+package mcp
+
+import (
+	"log"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/protocol"
+	"github.com/khanglvm/tool-hub-mcp/internal/redact"
+	"github.com/khanglvm/tool-hub-mcp/internal/spawner"
+)
+
+// retryPolicy converts the retry settings for the spawner pool. Invalid
+// delays fall back to the defaults (validation reports them).
+func retryPolicy(settings *config.Settings) spawner.RetryPolicy {
+	retry := settings.RetryConfig()
+	backoff, maxBackoff, err := retry.Delays()
+	if err != nil {
+		log.Printf("Warning: %v, using default retry backoff", err)
+		backoff, maxBackoff = config.DefaultRetryBackoff, config.DefaultRetryMaxBackoff
+	}
+	return spawner.RetryPolicy{
+		Attempts:   retry.Attempts(),
+		Backoff:    backoff,
+		MaxBackoff: maxBackoff,
+	}
+}
+
+// idempotentTools holds "server/tool" ids the servers annotated as
+// idempotent or read-only. Thread-safe.
+type idempotentTools struct {
+	mu  sync.Mutex
+	ids map[string]bool
+}
+
+// newIdempotentTools creates an empty set.
+func newIdempotentTools() *idempotentTools {
+	return &idempotentTools{ids: make(map[string]bool)}
+}
+
+// mark records which of a server's tools are idempotent, replacing what
+// was recorded for it before.
+func (t *idempotentTools) mark(serverName string, tools []protocol.Tool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	prefix := serverName + "/"
+	for id := range t.ids {
+		if strings.HasPrefix(id, prefix) {
+			delete(t.ids, id)
+		}
+	}
+	for _, tool := range tools {
+		if tool.Idempotent() {
+			t.ids[prefix+tool.Name] = true
+		}
+	}
+}
+
+// has reports whether a tool was annotated idempotent.
+func (t *idempotentTools) has(serverName, toolName string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.ids[serverName+"/"+toolName]
+}
+
+// idempotent reports whether a tool call may be retried after a transient
+// failure: the server annotated the tool as idempotent, or it matches one
+// of the server's idempotentTools patterns.
+func (s *Server) idempotent(serverName string, server *config.ServerConfig, toolName string) bool {
+	if s.idempotentTools.has(serverName, toolName) {
+		return true
+	}
+	for _, pattern := range server.IdempotentTools {
+		if ok, _ := path.Match(strings.ToLower(pattern), strings.ToLower(toolName)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// callRetries collects the failed attempts of a hub_execute call.
+type callRetries struct {
+	mu     sync.Mutex
+	errors []string
+}
+
+// record is a spawner.RetryFunc.
+func (r *callRetries) record(attempt int, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errors = append(r.errors, redact.String(err.Error()))
+}
+
+// count returns the number of retries made.
+func (r *callRetries) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.errors)
+}
+
+// meta returns the retry metadata returned with the result: the total
+// number of attempts and why the earlier ones failed.
+func (r *callRetries) meta() map[string]interface{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return map[string]interface{}{
+		"attempts": len(r.errors) + 1,
+		"errors":   append([]string{}, r.errors...),
+	}
+}
+
+// withRetryMeta attaches the retry metadata to a formatted hub_execute
+// result as _meta.retry. Text results become a single text block.
+func withRetryMeta(result interface{}, retries *callRetries) interface{} {
+	if retries.count() == 0 {
+		return result
+	}
+
+	content, ok := result.(*toolContent)
+	if !ok {
+		content = &toolContent{Content: []map[string]interface{}{{"type": "text", "text": result}}}
+	}
+	if content.Meta == nil {
+		content.Meta = make(map[string]interface{})
+	}
+	content.Meta["retry"] = retries.meta()
+	return content
+}
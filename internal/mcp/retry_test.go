@@ -0,0 +1,72 @@
+package mcp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/mcptest"
+)
+
+// writeCrashOnceServer writes a fake server whose first process exits on
+// tools/call; later processes answer it.
+func writeCrashOnceServer(t *testing.T) string {
+	t.Helper()
+	return mcptest.Script(t, mcptest.Handlers{
+		"initialize": mcptest.Result(`{"serverInfo":{"name":"jira","version":"1.0.0"}}`),
+		"tools/call": mcptest.CrashOnce(t.TempDir()) + "; " +
+			mcptest.Result(`{"content":[{"type":"text","text":"PROJ-1"}]}`),
+	})
+}
+
+func TestHubExecuteRetriesIdempotentTool(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg := config.NewConfig()
+	cfg.Settings.Retry = &config.RetrySettings{Backoff: "1ms"}
+	cfg.Servers["jira"] = &config.ServerConfig{
+		Command:         "sh",
+		Args:            []string{writeCrashOnceServer(t)},
+		IdempotentTools: []string{"get_*"},
+	}
+	server := NewServer(cfg)
+	defer server.Close()
+
+	resp, err := server.handleToolsCall(&MCPRequest{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  "tools/call",
+		Params:  json.RawMessage(`{"name":"hub_execute","arguments":{"server":"jira","tool":"get_issue"}}`),
+	})
+	if err != nil || resp.Error != nil {
+		t.Fatalf("hub_execute should succeed on retry: %v %+v", err, resp.Error)
+	}
+
+	result := resp.Result.(map[string]interface{})
+	meta, _ := result["_meta"].(map[string]interface{})
+	retry, _ := meta["retry"].(map[string]interface{})
+	if retry["attempts"] != 2 {
+		t.Fatalf("expected retry metadata with 2 attempts, got %+v", result)
+	}
+	if errors, _ := retry["errors"].([]string); len(errors) != 1 {
+		t.Errorf("expected the first attempt's error, got %+v", retry["errors"])
+	}
+}
+
+func TestHubExecuteDoesNotRetryOtherTools(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg := config.NewConfig()
+	cfg.Settings.Retry = &config.RetrySettings{Backoff: "1ms"}
+	cfg.Servers["jira"] = &config.ServerConfig{
+		Command:         "sh",
+		Args:            []string{writeCrashOnceServer(t)},
+		IdempotentTools: []string{"get_*"},
+	}
+	server := NewServer(cfg)
+	defer server.Close()
+
+	if _, err := server.execHubExecute(nil, "jira", "create_issue", nil, "", nil); err == nil {
+		t.Error("expected the non-idempotent call to fail without a retry")
+	}
+}
@@ -0,0 +1,65 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Search result detail levels (hub_search "detail" argument).
+const (
+	detailFull    = "full"
+	detailCompact = "compact"
+)
+
+// parseDetail validates a hub_search detail level; empty means full.
+func parseDetail(detail string) (string, error) {
+	switch detail {
+	case "", detailFull:
+		return detailFull, nil
+	case detailCompact:
+		return detailCompact, nil
+	default:
+		return "", fmt.Errorf("invalid detail '%s': use 'compact' or 'full'", detail)
+	}
+}
+
+// execHubSchema returns one tool's input schema, so compact search results
+// can be followed by a schema fetch for just the tool the model picked.
+func (s *Server) execHubSchema(serverName, toolName string) (string, error) {
+	s.configMu.RLock()
+	server, exists := s.config.Servers[serverName]
+	s.configMu.RUnlock()
+
+	if !exists {
+		return "", s.serverNotFound(serverName)
+	}
+	if server.Disabled {
+		return "", fmt.Errorf("server '%s' is disabled", serverName)
+	}
+	if toolName == "" {
+		return "", fmt.Errorf("tool is required")
+	}
+
+	tools, err := s.spawner.GetTools(serverName, server)
+	if err != nil {
+		return "", fmt.Errorf("failed to list tools from '%s': %w", serverName, err)
+	}
+
+	for _, tool := range tools {
+		if tool.Name != toolName {
+			continue
+		}
+		data, err := json.Marshal(map[string]interface{}{
+			"id":          serverName + "/" + tool.Name,
+			"server":      serverName,
+			"name":        tool.Name,
+			"description": tool.Description,
+			"inputSchema": tool.InputSchema,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal response: %w", err)
+		}
+		return s.budgetResponse(string(data)), nil
+	}
+	return "", fmt.Errorf("tool '%s' not found on server '%s'", toolName, serverName)
+}
@@ -0,0 +1,62 @@
+package mcp
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+)
+
+func TestHubSearchCompactAndHubSchema(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	script := writeFakeServer(t, "1.0.0",
+		`[{"name":"search_issues","description":"Search issues\\nSupports JQL.","inputSchema":{"type":"object","properties":{"jql":{"type":"string"}}}}]`)
+	cfg := config.NewConfig()
+	cfg.Servers["jira"] = &config.ServerConfig{Command: "sh", Args: []string{script}}
+	server := NewServer(cfg)
+	defer server.Close()
+	if err := server.IndexTools(); err != nil {
+		t.Fatalf("IndexTools failed: %v", err)
+	}
+
+	out, err := server.runHubSearch(searchRequest{Query: "search issues", Detail: "compact"})
+	if err != nil {
+		t.Fatalf("compact search failed: %v", err)
+	}
+	var compact struct {
+		Hint    string                   `json:"hint"`
+		Results []map[string]interface{} `json:"results"`
+	}
+	if err := json.Unmarshal([]byte(out), &compact); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if len(compact.Results) != 1 || compact.Hint == "" {
+		t.Fatalf("unexpected compact response: %s", out)
+	}
+	hit := compact.Results[0]
+	if hit["id"] != "jira/search_issues" || hit["description"] != "Search issues" {
+		t.Errorf("unexpected compact result: %+v", hit)
+	}
+	for _, field := range []string{"inputSchema", "score", "matchedFields"} {
+		if _, ok := hit[field]; ok {
+			t.Errorf("compact result should omit %s: %+v", field, hit)
+		}
+	}
+
+	if _, err := server.runHubSearch(searchRequest{Query: "search", Detail: "verbose"}); err == nil {
+		t.Error("expected error for invalid detail")
+	}
+
+	schema, err := server.execHubSchema("jira", "search_issues")
+	if err != nil {
+		t.Fatalf("execHubSchema failed: %v", err)
+	}
+	if !strings.Contains(schema, `"jql"`) {
+		t.Errorf("schema missing properties: %s", schema)
+	}
+	if _, err := server.execHubSchema("jira", "missing"); err == nil {
+		t.Error("expected error for unknown tool")
+	}
+}
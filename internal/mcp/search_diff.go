@@ -0,0 +1,98 @@
+package mcp
+
+import (
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/search"
+	"github.com/khanglvm/tool-hub-mcp/internal/storage"
+)
+
+// normalizeSearchQuery reduces a query to a stable key: case-insensitive,
+// whitespace-collapsed, and scoped to the server filter if any.
+func normalizeSearchQuery(query, serverFilter string) string {
+	normalized := strings.Join(strings.Fields(strings.ToLower(query)), " ")
+	if serverFilter != "" {
+		normalized += "|" + serverFilter
+	}
+	return normalized
+}
+
+// diffSearchResults compares results with the last result set persisted for
+// the same normalized query, stores the new set, and returns a
+// changedSinceLastSearch marker. Long-running agents can use it to notice
+// that a server update added a capability they previously lacked.
+func (s *Server) diffSearchResults(query, serverFilter string, results []search.SearchResult) map[string]interface{} {
+	current := make([]string, 0, len(results))
+	for _, r := range results {
+		current = append(current, r.ServerName+"/"+r.ToolName)
+	}
+
+	marker := map[string]interface{}{
+		"changed":      false,
+		"newTools":     []map[string]string{},
+		"removedTools": []map[string]string{},
+	}
+
+	if s.storage == nil {
+		return marker
+	}
+
+	queryHash := storage.HashQuery(normalizeSearchQuery(query, serverFilter))
+	previous, err := s.storage.GetSearchSnapshot(queryHash)
+	if err != nil {
+		log.Printf("Warning: failed to load search snapshot: %v", err)
+	}
+	if err := s.storage.SaveSearchSnapshot(queryHash, current); err != nil {
+		log.Printf("Warning: failed to save search snapshot: %v", err)
+	}
+
+	if previous == nil {
+		marker["firstSearch"] = true
+		return marker
+	}
+
+	added, removed := diffToolSets(previous.Tools, current)
+	marker["changed"] = len(added) > 0 || len(removed) > 0
+	marker["newTools"] = splitToolIDs(added)
+	marker["removedTools"] = splitToolIDs(removed)
+	marker["lastSearchedAt"] = previous.UpdatedAt.Format(time.RFC3339)
+
+	return marker
+}
+
+// diffToolSets returns the IDs present only in current (added) and only in
+// previous (removed), each sorted.
+func diffToolSets(previous, current []string) (added, removed []string) {
+	prevSet := make(map[string]bool, len(previous))
+	for _, id := range previous {
+		prevSet[id] = true
+	}
+	currSet := make(map[string]bool, len(current))
+	for _, id := range current {
+		currSet[id] = true
+		if !prevSet[id] {
+			added = append(added, id)
+		}
+	}
+	for _, id := range previous {
+		if !currSet[id] {
+			removed = append(removed, id)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
+
+// splitToolIDs turns "server/tool" IDs into objects usable with hub_execute.
+func splitToolIDs(ids []string) []map[string]string {
+	tools := make([]map[string]string, 0, len(ids))
+	for _, id := range ids {
+		server, tool, _ := strings.Cut(id, "/")
+		tools = append(tools, map[string]string{"server": server, "name": tool})
+	}
+	return tools
+}
@@ -0,0 +1,114 @@
+package mcp
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/protocol"
+)
+
+func TestNormalizeSearchQuery(t *testing.T) {
+	if normalizeSearchQuery("  Create   JIRA issue ", "") != "create jira issue" {
+		t.Error("query not normalized")
+	}
+	if normalizeSearchQuery("create issue", "jira") == normalizeSearchQuery("create issue", "") {
+		t.Error("server filter should scope the key")
+	}
+}
+
+func TestDiffToolSets(t *testing.T) {
+	added, removed := diffToolSets(
+		[]string{"jira/search", "jira/old"},
+		[]string{"jira/search", "jira/create_issue"},
+	)
+	if !reflect.DeepEqual(added, []string{"jira/create_issue"}) {
+		t.Errorf("unexpected added: %v", added)
+	}
+	if !reflect.DeepEqual(removed, []string{"jira/old"}) {
+		t.Errorf("unexpected removed: %v", removed)
+	}
+}
+
+func TestHubSearchChangedSinceLastSearch(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg := &config.Config{
+		Servers: map[string]*config.ServerConfig{
+			"jira": {Command: "echo"},
+		},
+	}
+	server := NewServer(cfg)
+	defer server.Close()
+
+	if server.indexer == nil || server.storage == nil {
+		t.Skip("indexer or storage not available")
+	}
+
+	server.indexer.IndexServer("jira", []protocol.Tool{
+		{Name: "search_issues", Description: "Search jira issues", InputSchema: json.RawMessage(`{}`)},
+	})
+
+	search := func() map[string]interface{} {
+		result, err := server.runHubSearch(searchRequest{Query: "jira issues", IncludeChanges: true})
+		if err != nil {
+			t.Fatalf("search failed: %v", err)
+		}
+		var data map[string]interface{}
+		if err := json.Unmarshal([]byte(result), &data); err != nil {
+			t.Fatalf("invalid response: %v", err)
+		}
+		marker, ok := data["changedSinceLastSearch"].(map[string]interface{})
+		if !ok {
+			t.Fatalf("changedSinceLastSearch missing: %s", result)
+		}
+		return marker
+	}
+
+	first := search()
+	if first["firstSearch"] != true {
+		t.Errorf("expected firstSearch marker, got %v", first)
+	}
+
+	unchanged := search()
+	if unchanged["changed"] != false {
+		t.Errorf("expected no changes, got %v", unchanged)
+	}
+
+	// A server update adds a new capability
+	server.indexer.IndexServer("jira", []protocol.Tool{
+		{Name: "search_issues", Description: "Search jira issues", InputSchema: json.RawMessage(`{}`)},
+		{Name: "create_issues", Description: "Create jira issues", InputSchema: json.RawMessage(`{}`)},
+	})
+
+	changed := search()
+	if changed["changed"] != true {
+		t.Fatalf("expected changes, got %v", changed)
+	}
+	newTools, _ := changed["newTools"].([]interface{})
+	if len(newTools) != 1 || newTools[0].(map[string]interface{})["name"] != "create_issues" {
+		t.Errorf("unexpected newTools: %v", changed["newTools"])
+	}
+}
+
+func TestHubSearchOmitsChangesByDefault(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	server := NewServer(&config.Config{Servers: map[string]*config.ServerConfig{}})
+	defer server.Close()
+
+	if server.indexer == nil {
+		t.Skip("indexer not available")
+	}
+
+	result, err := server.execHubSearch("anything", "", 5)
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	var data map[string]interface{}
+	json.Unmarshal([]byte(result), &data)
+	if _, ok := data["changedSinceLastSearch"]; ok {
+		t.Error("marker should only be included on request")
+	}
+}
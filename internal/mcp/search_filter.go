@@ -0,0 +1,50 @@
+package mcp
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/search"
+)
+
+// filter combines the server, servers and tags arguments into one search
+// filter. Duplicate servers are dropped.
+func (req searchRequest) filter() search.Filter {
+	seen := make(map[string]bool)
+	var servers []string
+	for _, name := range append([]string{req.Server}, req.Servers...) {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			servers = append(servers, name)
+		}
+	}
+	return search.Filter{Servers: servers, Tags: req.Tags}
+}
+
+// filterKey is the canonical form of a filter that binds cursors and
+// result diffs to it. A single-server filter keeps the plain server name.
+func filterKey(filter search.Filter) string {
+	if len(filter.Tags) == 0 && len(filter.Servers) <= 1 {
+		return strings.Join(filter.Servers, "")
+	}
+	servers := append([]string(nil), filter.Servers...)
+	sort.Strings(servers)
+	tags := make([]string, len(filter.Tags))
+	for i, tag := range filter.Tags {
+		tags[i] = strings.ToLower(strings.TrimSpace(tag))
+	}
+	sort.Strings(tags)
+	return strings.Join(servers, ",") + "#" + strings.Join(tags, ",")
+}
+
+// stringArgs reads a string array argument, skipping non-string items.
+func stringArgs(value interface{}) []string {
+	items, _ := value.([]interface{})
+	var values []string
+	for _, item := range items {
+		if s, ok := item.(string); ok && s != "" {
+			values = append(values, s)
+		}
+	}
+	return values
+}
@@ -0,0 +1,125 @@
+package mcp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/search"
+)
+
+func TestHubSearchServersAndTags(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	script := writeFakeServer(t, "1.0.0", `[{"name":"create_issue","description":"Create an issue","inputSchema":{"type":"object"}}]`)
+	cfg := config.NewConfig()
+	cfg.Servers["jira"] = &config.ServerConfig{Command: "sh", Args: []string{script}, Tags: []string{"tracking"}}
+	cfg.Servers["linear"] = &config.ServerConfig{Command: "sh", Args: []string{script}, Tags: []string{"Tracking", "planning"}}
+	cfg.Servers["github"] = &config.ServerConfig{Command: "sh", Args: []string{script}}
+	server := NewServer(cfg)
+	defer server.Close()
+	if err := server.IndexTools(); err != nil {
+		t.Fatalf("IndexTools failed: %v", err)
+	}
+
+	servers := func(req searchRequest) map[string]bool {
+		t.Helper()
+		out, err := server.runHubSearch(req)
+		if err != nil {
+			t.Fatalf("search failed: %v", err)
+		}
+		var resp struct {
+			Results []struct {
+				Server string `json:"server"`
+			} `json:"results"`
+		}
+		if err := json.Unmarshal([]byte(out), &resp); err != nil {
+			t.Fatalf("invalid JSON %q: %v", out, err)
+		}
+		found := make(map[string]bool)
+		for _, r := range resp.Results {
+			found[r.Server] = true
+		}
+		return found
+	}
+
+	if got := servers(searchRequest{Query: "issue", Servers: []string{"jira", "github"}}); len(got) != 2 || !got["jira"] || !got["github"] {
+		t.Errorf("servers filter: got %v", got)
+	}
+	if got := servers(searchRequest{Query: "issue", Tags: []string{"TRACKING"}}); len(got) != 2 || !got["jira"] || !got["linear"] {
+		t.Errorf("tags filter: got %v", got)
+	}
+	if got := servers(searchRequest{Query: "issue", Server: "github", Tags: []string{"planning"}}); len(got) != 0 {
+		t.Errorf("server and tags filters should both apply: got %v", got)
+	}
+}
+
+func TestHubSearchFacets(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	script := writeFakeServer(t, "1.0.0", `[{"name":"create_issue","description":"Create an issue","inputSchema":{"type":"object"}}]`)
+	cfg := config.NewConfig()
+	cfg.Servers["jira"] = &config.ServerConfig{Command: "sh", Args: []string{script}, Tags: []string{"tracking"}}
+	cfg.Servers["linear"] = &config.ServerConfig{Command: "sh", Args: []string{script}}
+	server := NewServer(cfg)
+	defer server.Close()
+	if err := server.IndexTools(); err != nil {
+		t.Fatalf("IndexTools failed: %v", err)
+	}
+
+	out, err := server.runHubSearch(searchRequest{Query: "issue", Limit: 1})
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	var resp struct {
+		Facets search.Facets `json:"facets"`
+	}
+	if err := json.Unmarshal([]byte(out), &resp); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if resp.Facets.Servers["jira"] != 1 || resp.Facets.Servers["linear"] != 1 || resp.Facets.Tags["tracking"] != 1 {
+		t.Errorf("unexpected facets: %s", out)
+	}
+}
+
+func TestHubSearchSuggestions(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	script := writeFakeServer(t, "1.0.0", `[{"name":"create_issue","description":"Create an issue","inputSchema":{"type":"object"}}]`)
+	cfg := config.NewConfig()
+	cfg.Servers["jira"] = &config.ServerConfig{Command: "sh", Args: []string{script}}
+	server := NewServer(cfg)
+	defer server.Close()
+	if err := server.IndexTools(); err != nil {
+		t.Fatalf("IndexTools failed: %v", err)
+	}
+
+	out, err := server.runHubSearch(searchRequest{Query: "kreat isuee"})
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	var resp struct {
+		Results     []interface{} `json:"results"`
+		Suggestions []string      `json:"suggestions"`
+	}
+	if err := json.Unmarshal([]byte(out), &resp); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if len(resp.Results) != 0 || len(resp.Suggestions) == 0 || resp.Suggestions[0] != "create issue" {
+		t.Errorf("expected the suggestion \"create issue\", got %s", out)
+	}
+}
+
+func TestFilterKey(t *testing.T) {
+	if key := filterKey(search.Filter{Servers: []string{"jira"}}); key != "jira" {
+		t.Errorf("single-server key = %q, want the server name", key)
+	}
+	a := filterKey(search.Filter{Servers: []string{"jira", "linear"}, Tags: []string{"Docs"}})
+	b := filterKey(search.Filter{Servers: []string{"linear", "jira"}, Tags: []string{"docs"}})
+	if a != b {
+		t.Errorf("equivalent filters have different keys: %q, %q", a, b)
+	}
+	if filterKey(search.Filter{Tags: []string{"jira"}}) == "jira" {
+		t.Error("a tag filter should not share a key with a server filter")
+	}
+}
@@ -1,20 +1,28 @@
 /*
 Package mcp implements the MCP server that exposes meta-tools.
 
-The server uses stdio transport and exposes 3 meta-tools:
+The server uses stdio transport (or HTTP, see ServeHTTP) and exposes these meta-tools (settings.metaTools chooses which):
   - hub_search: Semantic search for tools across all servers (with discovery)
+  - hub_list: List registered servers without spawning them
+  - hub_discover: List every tool a specific server offers
   - hub_execute: Execute a tool from a specific server (with learning)
-  - hub_manage: Add or remove MCP servers from configuration
+  - hub_fetch_result: Page through hub_execute output that exceeded the response budget
+  - hub_schema: Fetch one tool's input schema (after a compact hub_search)
+  - hub_help: Fetch a tool's schemas, annotations and recent argument examples
+  - hub_status: Show each server's state, protocol version and capabilities
+  - hub_manage: Add, import, update, rename or remove MCP servers in configuration
 */
 package mcp
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -22,10 +30,15 @@ import (
 	"github.com/google/uuid"
 	"github.com/khanglvm/tool-hub-mcp/internal/config"
 	"github.com/khanglvm/tool-hub-mcp/internal/learning"
+	"github.com/khanglvm/tool-hub-mcp/internal/protocol"
+	"github.com/khanglvm/tool-hub-mcp/internal/ratelimit"
+	"github.com/khanglvm/tool-hub-mcp/internal/redact"
 	"github.com/khanglvm/tool-hub-mcp/internal/search"
 	"github.com/khanglvm/tool-hub-mcp/internal/spawner"
 	"github.com/khanglvm/tool-hub-mcp/internal/storage"
+	"github.com/khanglvm/tool-hub-mcp/internal/telemetry"
 	"github.com/khanglvm/tool-hub-mcp/internal/version"
+	"github.com/khanglvm/tool-hub-mcp/internal/webhook"
 )
 
 // Server represents the tool-hub-mcp MCP server.
@@ -36,56 +49,180 @@ type Server struct {
 	indexer       *search.Indexer
 	storage       *storage.SQLiteStorage
 	tracker       *learning.Tracker
+	pipeline      *SearchPipeline   // nil without an indexer; guarded by configMu
 	failedServers map[string]string // serverName → error message
 
+	// quotas tracks provider rate limit consumption per server
+	quotas   map[string]*ratelimit.Window
+	quotasMu sync.Mutex
+
+	// buckets throttles hub_execute calls per server (ServerConfig.Throttle),
+	// guarded by quotasMu
+	buckets map[string]*ratelimit.Bucket
+
+	// indexedFingerprints maps server names to the tool fingerprint last
+	// indexed; fingerprintMu also guards ServerMetadata updates
+	indexedFingerprints map[string]string
+	fingerprintMu       sync.Mutex
+
+	// results holds oversized hub_execute outputs for hub_fetch_result
+	results *resultStore
+
+	// cache reuses results of read-only tools (per-server opt-in)
+	cache *resultCache
+
+	// idempotentTools are the tools hub_execute may retry by annotation
+	idempotentTools *idempotentTools
+
+	// circuits fail calls fast to servers that keep failing
+	circuits *circuitBreaker
+
+	// metrics are exposed for Prometheus scraping (see Metrics)
+	metrics *serverMetrics
+
+	// out receives responses and notifications; outMu keeps lines whole
+	out   io.Writer
+	outMu sync.Mutex
+
+	// progressRelays maps progress tokens sent to children to the client's
+	// token for the same hub_execute call
+	progressRelays map[string]progressRelay
+	progressMu     sync.Mutex
+
+	// stdio is the client on stdin/stdout; sessions are HTTP clients by
+	// Mcp-Session-Id (see ServeHTTP)
+	stdio      *session
+	sessions   map[string]*session
+	sessionsMu sync.Mutex
+
+	// searches records which session issued each searchId
+	searches *searchOwners
+
 	// Context for background goroutines (update checker, discovery)
 	ctx    context.Context
 	cancel context.CancelFunc
 
 	// closeOnce ensures Close() is idempotent (safe to call multiple times)
 	closeOnce sync.Once
+
+	// tracer exports spans and metrics over OTLP (nil when not configured)
+	tracer *telemetry.Exporter
+
+	// middleware wraps tools/call handling, before configured hook commands
+	middleware   []Middleware
+	middlewareMu sync.Mutex
+
+	// webhooks posts execution and server events to configured endpoints
+	webhooks *webhook.Dispatcher
 }
 
 // NewServer creates a new MCP server with the given configuration.
 func NewServer(cfg *config.Config) *Server {
-	poolSize := 3
-	if cfg.Settings != nil && cfg.Settings.ProcessPoolSize > 0 {
-		poolSize = cfg.Settings.ProcessPoolSize
-	}
-
 	// Create search indexer
-	indexer, err := search.NewIndexer()
+	indexer, err := openIndexer(cfg)
 	if err != nil {
 		log.Printf("Warning: failed to create search indexer: %v", err)
 		indexer = nil
 	}
 
-	// Create storage layer
-	str := storage.NewStorage()
-	if err := str.Init(); err != nil {
-		log.Printf("Warning: failed to initialize storage: %v", err)
-		// Storage is optional, continue without it
-	}
-
-	// Create learning tracker
+	// Create storage layer and learning tracker, unless the user opted out
+	// of learning: then no history database is created at all
+	var str *storage.SQLiteStorage
 	var tracker *learning.Tracker
-	if str != nil {
+	if cfg.LearningEnabled() {
+		str = storage.NewStorage()
+		if err := str.Init(); err != nil {
+			log.Printf("Warning: failed to initialize storage: %v", err)
+			// Storage is optional, continue without it
+		}
 		tracker = learning.NewTracker(str)
+	} else {
+		log.Printf("Learning disabled: usage, search history and audit log are not recorded")
+	}
+
+	var pipeline *SearchPipeline
+	if indexer != nil {
+		var cleared bool
+		pipeline, cleared = NewSearchPipeline(indexer, cfg, str)
+		prepareIndex(indexer, cfg, cleared)
+	}
+
+	// Log experimental subsystems the user opted into
+	for _, feature := range cfg.EnabledFeatures() {
+		log.Printf("Experimental feature enabled: %s (%s)", feature, config.KnownFeatures[feature])
 	}
 
+	// Standardize the environment children are launched with
+	pool := spawner.NewPool(cfg.Settings.ProcessPoolLimit())
+	pool.SetDefaultEnv(cfg.Settings.ChildEnv())
+	pool.SetMaxConcurrentCalls(cfg.Settings.CallConcurrency())
+	pool.SetMaxChildren(cfg.Settings.ChildProcessLimit())
+	pool.SetPinned(preloadServers(cfg))
+	pool.SetRetryPolicy(retryPolicy(cfg.Settings))
+
 	// Create cancellable context for background tasks
 	ctx, cancel := context.WithCancel(context.Background())
 
-	return &Server{
-		config:        cfg,
-		spawner:       spawner.NewPool(poolSize),
-		indexer:       indexer,
-		storage:       str,
-		tracker:       tracker,
-		failedServers: make(map[string]string),
-		ctx:           ctx,
-		cancel:        cancel,
+	s := &Server{
+		config:              cfg,
+		spawner:             pool,
+		indexer:             indexer,
+		storage:             str,
+		tracker:             tracker,
+		pipeline:            pipeline,
+		failedServers:       make(map[string]string),
+		quotas:              make(map[string]*ratelimit.Window),
+		buckets:             make(map[string]*ratelimit.Bucket),
+		indexedFingerprints: make(map[string]string),
+		results:             newResultStore(),
+		cache:               newResultCache(),
+		idempotentTools:     newIdempotentTools(),
+		circuits:            newCircuitBreaker(cfg.Settings),
+		progressRelays:      make(map[string]progressRelay),
+		sessions:            make(map[string]*session),
+		searches:            newSearchOwners(),
+		out:                 os.Stdout,
+		ctx:                 ctx,
+		cancel:              cancel,
+	}
+
+	s.stdio = newSession("stdio", s.sendNotification)
+	s.metrics = newServerMetrics(s)
+	pool.SetSpawnObserver(func(name string) { s.metrics.spawns.Inc(name) })
+	pool.SetNotificationHandler(s.handleChildNotification)
+
+	s.webhooks = webhook.New(webhookTargets(cfg.Settings))
+
+	// Export spans and metrics when a collector is configured
+	s.tracer = telemetry.New(telemetryConfig(cfg.Settings.TelemetryConfig()))
+	if s.tracer != nil {
+		s.tracer.SetMetrics(s.metrics.registry)
+		pool.SetTracer(s.tracer)
+		log.Printf("Exporting telemetry to %s", redact.URL(s.tracer.Endpoint()))
+	}
+
+	return s
+}
+
+// telemetryConfig builds the exporter config from settings and the
+// standard OTEL_* variables.
+func telemetryConfig(settings *config.TelemetrySettings) telemetry.Config {
+	cfg := telemetry.Config{ServiceVersion: version.Version}
+	if settings != nil {
+		cfg.Endpoint = settings.Endpoint
+		cfg.Headers = settings.Headers
+		cfg.ServiceName = settings.ServiceName
+	}
+	return cfg.WithEnv()
+}
+
+// webhookTargets converts the configured webhooks for the dispatcher.
+func webhookTargets(settings *config.Settings) []webhook.Target {
+	var targets []webhook.Target
+	for _, w := range settings.WebhookTargets() {
+		targets = append(targets, webhook.Target{URL: w.URL, Events: w.Events, Headers: w.Headers})
 	}
+	return targets
 }
 
 // Close gracefully shuts down the server and cleans up all resources.
@@ -132,6 +269,14 @@ func (s *Server) Close() error {
 			}
 		}
 
+		// 5. Deliver queued webhook events
+		s.webhooks.Close(webhookShutdownTimeout)
+
+		// 6. Flush telemetry (sends spans recorded during shutdown)
+		if s.tracer != nil {
+			s.tracer.Close()
+		}
+
 		log.Println("Server shutdown complete")
 	})
 
@@ -161,23 +306,22 @@ func (s *Server) indexToolsUnsafe() error {
 	s.failedServers = make(map[string]string)
 
 	// Index each server's tools
+	metadataChanged := false
+	indexChanged := false
 	for serverName, serverCfg := range s.config.Servers {
-		tools, err := s.spawner.GetTools(serverName, serverCfg)
-		if err != nil {
-			// Capture error for this server
-			s.failedServers[serverName] = err.Error()
-			log.Printf("Warning: failed to get tools from %s: %v", serverName, err)
-			continue
-		}
-
-		if err := s.indexer.IndexServer(serverName, tools); err != nil {
-			// Capture indexing error
-			s.failedServers[serverName] = fmt.Sprintf("indexing failed: %v", err)
-			log.Printf("Warning: failed to index tools from %s: %v", serverName, err)
+		if serverCfg.Disabled {
 			continue
 		}
+		indexed, refreshed := s.indexServerUnsafe(serverName, serverCfg)
+		indexChanged = indexChanged || indexed
+		metadataChanged = metadataChanged || refreshed
+	}
 
-		log.Printf("Indexed %d tools from %s", len(tools), serverName)
+	if metadataChanged {
+		s.persistMetadata()
+	}
+	if indexChanged {
+		s.notifyToolsListChanged()
 	}
 
 	// Log total indexed count
@@ -193,6 +337,97 @@ func (s *Server) indexToolsUnsafe() error {
 	return nil
 }
 
+// indexServersUnsafe indexes only the named servers, leaving the rest of
+// the index untouched, so that adding or changing one server doesn't
+// spawn every other (caller must hold lock).
+func (s *Server) indexServersUnsafe(names ...string) {
+	if s.indexer == nil {
+		return
+	}
+
+	metadataChanged := false
+	indexChanged := false
+	for _, serverName := range names {
+		serverCfg, exists := s.config.Servers[serverName]
+		if !exists || serverCfg.Disabled {
+			continue
+		}
+		delete(s.failedServers, serverName)
+		indexed, refreshed := s.indexServerUnsafe(serverName, serverCfg)
+		indexChanged = indexChanged || indexed
+		metadataChanged = metadataChanged || refreshed
+	}
+
+	if metadataChanged {
+		s.persistMetadata()
+	}
+	if indexChanged {
+		s.notifyToolsListChanged()
+	}
+}
+
+// indexServerUnsafe fetches one server's tools and reindexes them if they
+// changed. Failures are recorded in failedServers. Reports whether the
+// index and the metadata cache changed (caller must hold lock).
+func (s *Server) indexServerUnsafe(serverName string, serverCfg *config.ServerConfig) (indexed, metadataChanged bool) {
+	span := s.tracer.Start("index "+serverName, telemetry.KindInternal)
+	span.SetAttribute("mcp.server", serverName)
+	var spanErr error
+	defer func() {
+		span.SetAttribute("index.rebuilt", indexed)
+		span.End(spanErr)
+	}()
+
+	// Listing tools must not stop servers in use to stay within the pool
+	tools, err := s.spawner.DiscoverTools(serverName, serverCfg)
+	s.recordDiscovery(serverName, len(tools), err)
+	s.circuits.record(serverName, err)
+	span.SetAttribute("index.tools", len(tools))
+	if err != nil {
+		spanErr = err
+		// Capture error for this server
+		s.failedServers[serverName] = err.Error()
+		log.Printf("Warning: failed to get tools from %s: %v", serverName, err)
+		return false, false
+	}
+
+	s.cache.markReadOnly(serverName, tools)
+	s.idempotentTools.mark(serverName, tools)
+
+	// Refresh the metadata cache when the server or its tools changed
+	fingerprint := spawner.ToolsFingerprint(tools)
+	metadataChanged = s.refreshServerMetadata(serverName, serverCfg, tools, fingerprint)
+
+	// Skip rebuilding the index when the tool list and tags are unchanged
+	fingerprint = indexFingerprint(fingerprint, serverCfg.Tags)
+	if s.indexUpToDate(serverName, fingerprint) {
+		log.Printf("Tools unchanged for %s, keeping index", serverName)
+		return false, metadataChanged
+	}
+
+	if err := s.indexer.IndexServer(serverName, tools, serverCfg.Tags...); err != nil {
+		spanErr = err
+		// Capture indexing error
+		s.failedServers[serverName] = fmt.Sprintf("indexing failed: %v", err)
+		log.Printf("Warning: failed to index tools from %s: %v", serverName, err)
+		return false, metadataChanged
+	}
+	s.markIndexed(serverName, fingerprint)
+
+	log.Printf("Indexed %d tools from %s", len(tools), serverName)
+	return true, metadataChanged
+}
+
+// recordDiscovery stores the outcome of listing a server's tools for `list`.
+func (s *Server) recordDiscovery(serverName string, toolCount int, discoveryErr error) {
+	if s.storage == nil {
+		return
+	}
+	if err := s.storage.RecordDiscovery(serverName, toolCount, discoveryErr); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+}
+
 // StartBackgroundDiscovery starts tool indexing in background goroutine.
 // Server accepts requests immediately; search improves as indexing completes.
 // Goroutine exits when server context is cancelled.
@@ -226,39 +461,51 @@ func (s *Server) ReloadConfig(newCfg *config.Config) {
 	s.configMu.Lock()
 	defer s.configMu.Unlock()
 
+	serversChanged := !sameServerNames(s.config.Servers, newCfg.Servers)
+	if serversChanged {
+		s.announceServerChanges(s.config.Servers, newCfg.Servers)
+	}
 	s.config = newCfg
+	s.cache.invalidate("")
+	s.webhooks.SetTargets(webhookTargets(newCfg.Settings))
+	s.spawner.SetDefaultEnv(newCfg.Settings.ChildEnv())
+	s.spawner.SetMaxConcurrentCalls(newCfg.Settings.CallConcurrency())
+	s.spawner.SetMaxSize(newCfg.Settings.ProcessPoolLimit())
+	s.spawner.SetMaxChildren(newCfg.Settings.ChildProcessLimit())
+	s.spawner.SetPinned(preloadServers(newCfg))
+	s.spawner.SetRetryPolicy(retryPolicy(newCfg.Settings))
+	s.circuits.configure(newCfg.Settings)
 
 	// Re-index tools with new server list using unsafe version (already holding lock)
 	if s.indexer != nil {
+		var cleared bool
+		if s.pipeline, cleared = NewSearchPipeline(s.indexer, newCfg, s.storage); cleared {
+			// Every server must be indexed again with the new analyzers
+			s.forgetAllIndexed()
+		}
 		if err := s.indexToolsUnsafe(); err != nil {
 			log.Printf("Warning: failed to reindex tools after config reload: %v", err)
 		}
 	}
 
+	if serversChanged {
+		// hub_search lists the available servers in its description
+		s.notifyToolsListChanged()
+	}
+
 	log.Printf("Config reloaded: %d servers registered", len(newCfg.Servers))
 }
 
 // Run starts the MCP server using stdio transport.
-// This blocks until stdin is closed.
+// This blocks until stdin is closed and in-flight requests have finished.
 func (s *Server) Run() error {
-	scanner := bufio.NewScanner(os.Stdin)
-
-	for scanner.Scan() {
-		line := scanner.Bytes()
-
-		response, err := s.handleRequest(line)
-		if err != nil {
-			// Send error response
-			s.sendError(err)
-			continue
-		}
-
-		if response != nil {
-			s.sendResponse(response)
-		}
-	}
+	return s.serve(os.Stdin)
+}
 
-	return scanner.Err()
+// HandleRequest processes a single JSON-RPC message in-process, as if it
+// had arrived on stdin. It returns nil for notifications.
+func (s *Server) HandleRequest(data []byte) (*MCPResponse, error) {
+	return s.handleRequest(data)
 }
 
 // MCPRequest represents an incoming MCP JSON-RPC request.
@@ -267,6 +514,9 @@ type MCPRequest struct {
 	ID      interface{}     `json:"id"`
 	Method  string          `json:"method"`
 	Params  json.RawMessage `json:"params,omitempty"`
+
+	// session is the client the request arrived from (nil = stdio)
+	session *session
 }
 
 // MCPResponse represents an outgoing MCP JSON-RPC response.
@@ -283,20 +533,60 @@ type MCPError struct {
 	Message string `json:"message"`
 }
 
-// handleRequest processes an incoming MCP request.
+// handleRequest processes an incoming MCP request from the stdio client.
 func (s *Server) handleRequest(data []byte) (*MCPResponse, error) {
+	return s.handleSessionRequest(s.stdio, data)
+}
+
+// handleSessionRequest processes an incoming MCP request from a session.
+func (s *Server) handleSessionRequest(sess *session, data []byte) (*MCPResponse, error) {
 	var req MCPRequest
 	if err := json.Unmarshal(data, &req); err != nil {
 		return nil, fmt.Errorf("invalid JSON-RPC request: %w", err)
 	}
+	req.session = sess
 
+	// Notifications have no ID and never get a response
+	if requestKey(data) == "" {
+		s.handleNotification(&req)
+		return nil, nil
+	}
+
+	span := s.tracer.Start(req.Method, telemetry.KindServer)
+	span.SetAttribute("rpc.system", "jsonrpc")
+	span.SetAttribute("rpc.method", req.Method)
+	if req.Method == "tools/call" {
+		var call struct {
+			Name string `json:"name"`
+		}
+		if json.Unmarshal(req.Params, &call) == nil {
+			span.SetAttribute("mcp.tool", call.Name)
+		}
+	}
+	resp, err := s.dispatch(&req)
+	spanErr := err
+	if err == nil && resp != nil && resp.Error != nil {
+		spanErr = errors.New(resp.Error.Message)
+	}
+	span.End(spanErr)
+	return resp, err
+}
+
+// dispatch routes a request to its method handler.
+func (s *Server) dispatch(req *MCPRequest) (*MCPResponse, error) {
 	switch req.Method {
 	case "initialize":
-		return s.handleInitialize(&req)
+		return s.handleInitialize(req)
+	case "ping":
+		return &MCPResponse{JSONRPC: "2.0", ID: req.ID, Result: map[string]interface{}{}}, nil
 	case "tools/list":
-		return s.handleToolsList(&req)
+		return s.handleToolsList(req)
 	case "tools/call":
-		return s.handleToolsCall(&req)
+		return s.handleToolsCall(req)
+	case "resources/list":
+		return s.handleResourcesList(req)
+	case "resources/read":
+		return s.handleResourcesRead(req)
 	default:
 		return &MCPResponse{
 			JSONRPC: "2.0",
@@ -306,20 +596,51 @@ func (s *Server) handleRequest(data []byte) (*MCPResponse, error) {
 	}
 }
 
-// handleInitialize handles the MCP initialize request.
+// handleInitialize handles the MCP initialize request, negotiating the
+// protocol revision: the client's requested revision if supported,
+// otherwise the newest one.
 func (s *Server) handleInitialize(req *MCPRequest) (*MCPResponse, error) {
+	var params struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	if len(req.Params) > 0 {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return &MCPResponse{
+				JSONRPC: "2.0",
+				ID:      req.ID,
+				Error:   &MCPError{Code: -32602, Message: "Invalid params"},
+			}, nil
+		}
+	}
+	negotiated := protocol.Negotiate(params.ProtocolVersion)
+	if params.ProtocolVersion != "" && negotiated != params.ProtocolVersion {
+		log.Printf("Warning: client requested unsupported protocol version %s, offering %s", params.ProtocolVersion, negotiated)
+	}
+	sess := s.sessionOf(req)
+	sess.protocolVersion.Store(negotiated)
+	sess.ready.Store(true)
+
+	serverInfo := map[string]interface{}{
+		"name":    "tool-hub-mcp",
+		"version": version.Version,
+	}
+	if protocol.HasStructuredOutput(negotiated) {
+		serverInfo["title"] = "Tool Hub"
+	}
+
 	return &MCPResponse{
 		JSONRPC: "2.0",
 		ID:      req.ID,
 		Result: map[string]interface{}{
-			"protocolVersion": "2024-11-05",
+			"protocolVersion": negotiated,
 			"capabilities": map[string]interface{}{
-				"tools": map[string]interface{}{},
-			},
-			"serverInfo": map[string]interface{}{
-				"name":    "tool-hub-mcp",
-				"version": version.Version,
+				"tools": map[string]interface{}{
+					"listChanged": true,
+				},
+				"resources": map[string]interface{}{},
 			},
+			"serverInfo":   serverInfo,
+			"instructions": s.instructions(),
 		},
 	}, nil
 }
@@ -360,7 +681,7 @@ ANTI-PATTERNS (don't do this):
 
 CURRENTLY REGISTERED: %s
 
-Returns: JSON with searchId (for tracking), results array with tool details (name, description, inputSchema, expectedResponse), server, score, matchReason.`, serverList),
+Returns: JSON with searchId (for tracking), results array with tool details (id as "server/tool", name, description, inputSchema, exampleArguments with the required arguments filled in, expectedResponse), server, score, matchedFields, highlights (matched fragments with terms in **bold**). When nothing matches, suggestions lists similar queries to try. Tools with the same name on several servers carry a hint naming the server to call; pass dedupe: true to group equivalent tools. When more results exist, nextCursor is included; pass it back as cursor to get the next page. facets counts all matches per server and tag; narrow with servers or tags. Pass detail: "compact" to get only ids, servers and one-line descriptions, then hub_schema for the tool you pick.`, serverList),
 			"inputSchema": map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
@@ -373,14 +694,92 @@ Returns: JSON with searchId (for tracking), results array with tool details (nam
 						"description": "Optional: filter to specific server",
 						"enum":        s.getServerNamesList(),
 					},
+					"servers": map[string]interface{}{
+						"type":        "array",
+						"description": "Optional: filter to any of these servers",
+						"items": map[string]interface{}{
+							"type": "string",
+							"enum": s.getServerNamesList(),
+						},
+					},
+					"tags": map[string]interface{}{
+						"type":        "array",
+						"description": "Optional: filter to servers with any of these tags (from the servers' config)",
+						"items":       map[string]interface{}{"type": "string"},
+					},
 					"limit": map[string]interface{}{
 						"type":        "number",
 						"description": "Optional: max results (default 10)",
 					},
+					"offset": map[string]interface{}{
+						"type":        "number",
+						"description": "Optional: skip this many results (default 0)",
+					},
+					"cursor": map[string]interface{}{
+						"type":        "string",
+						"description": "Optional: nextCursor from a previous response, to fetch the next page",
+					},
+					"includeChanges": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Optional: report tools added or removed since the last identical search",
+					},
+					"dedupe": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Optional: group equivalent tools from different servers into one result with alternatives",
+					},
+					"detail": map[string]interface{}{
+						"type":        "string",
+						"enum":        []string{detailCompact, detailFull},
+						"description": "Optional: \"compact\" omits inputSchema and trims descriptions to one line (default \"full\")",
+					},
 				},
 				"required": []string{"query"},
 			},
 		},
+		{
+			"name": "hub_list",
+			"description": fmt.Sprintf(`List the registered servers with their descriptions, tags and tool counts.
+
+USE THIS TOOL when:
+• You want an overview of the available integrations before searching
+• You need the exact server name to pass to hub_discover or hub_search
+
+Does not start any server; servers that are disabled or failing are marked.
+
+CURRENTLY REGISTERED: %s`, serverList),
+			"inputSchema": map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{},
+			},
+		},
+		{
+			"name": "hub_discover",
+			"description": fmt.Sprintf(`List every tool a specific integration offers.
+
+USE THIS TOOL when:
+• You know which server to use and want to see all of its tools
+• hub_search queries keep missing the tool you expect
+
+Pass compact: true to get only tool names and one-line descriptions, then use
+hub_search or hub_discover without compact for the input schemas you need.
+
+CURRENTLY REGISTERED: %s`, serverList),
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"server": map[string]interface{}{
+						"type":        "string",
+						"description": "Server name",
+						"enum":        s.getServerNamesList(),
+					},
+					"compact": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Optional: return only names and one-line descriptions (default false)",
+					},
+				},
+				"required": []string{"server"},
+			},
+		},
 		{
 			"name": "hub_execute",
 			"description": fmt.Sprintf(`Run a tool from an external integration.
@@ -396,6 +795,10 @@ Only call hub_execute when you have the tool details from hub_search.
 LEARNING: Optionally pass searchId from hub_search to improve tool recommendations.
 This helps the system learn which tools work best for specific queries.
 
+DESTRUCTIVE TOOLS: Tools like delete_*, drop_* and *_remove are not run unless
+confirm is true; without it you get a confirmation_required result. Ask the user
+before retrying with confirm: true. Use dryRun to preview a call.
+
 CURRENTLY REGISTERED: %s`, serverList),
 			"inputSchema": map[string]interface{}{
 				"type": "object",
@@ -417,18 +820,116 @@ CURRENTLY REGISTERED: %s`, serverList),
 						"type":        "string",
 						"description": "Optional: search session ID from hub_search to link this execution for learning",
 					},
+					"confirm": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Set true to run a destructive tool after the user confirmed it",
+					},
+					"dryRun": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Set true to preview the call (and whether it needs confirm) without running it",
+					},
+				},
+				"required": []string{"server", "tool"},
+			},
+		},
+		{
+			"name": "hub_schema",
+			"description": `Get one tool's full input schema.
+
+USE THIS TOOL after hub_search with detail: "compact", to fetch the schema of
+the tool you picked before calling hub_execute.`,
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"server": map[string]interface{}{
+						"type":        "string",
+						"description": "Server name",
+						"enum":        s.getServerNamesList(),
+					},
+					"tool": map[string]interface{}{
+						"type":        "string",
+						"description": "Tool name (from hub_search)",
+					},
+				},
+				"required": []string{"server", "tool"},
+			},
+		},
+		{
+			"name": "hub_help",
+			"description": `Get everything known about one tool before calling it.
+
+Returns the full input schema, the output schema and annotations the server
+declares, exampleArguments with the required arguments filled in, the
+arguments of recent successful calls to copy from, and commonMistakes: the
+invalid-params errors earlier calls were rejected with.
+
+USE THIS TOOL when hub_execute rejected your arguments, or when a tool's schema
+alone does not make clear how to call it.`,
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"server": map[string]interface{}{
+						"type":        "string",
+						"description": "Server name",
+						"enum":        s.getServerNamesList(),
+					},
+					"tool": map[string]interface{}{
+						"type":        "string",
+						"description": "Tool name (from hub_search)",
+					},
 				},
 				"required": []string{"server", "tool"},
 			},
 		},
+		{
+			"name": "hub_status",
+			"description": `Show the state of every registered server.
+
+USE THIS TOOL when a server's tools are missing from hub_search or hub_execute
+fails to reach a server. Reports whether each server is running, its version,
+MCP protocol version and declared capabilities, and its last error, plus the
+process pool: each running child's pid, age, idle time and memory.`,
+			"inputSchema": map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{},
+			},
+		},
+		{
+			"name": "hub_fetch_result",
+			"description": `Read more of a large hub_execute output.
+
+USE THIS TOOL when hub_execute returns "truncated": true.
+Pass the resultId and nextOffset from that response; repeat with each new
+nextOffset until it is no longer returned. Results expire after 30 minutes.`,
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"resultId": map[string]interface{}{
+						"type":        "string",
+						"description": "Result ID from a truncated hub_execute response",
+					},
+					"offset": map[string]interface{}{
+						"type":        "integer",
+						"description": "Byte offset to read from (default: 0)",
+					},
+					"length": map[string]interface{}{
+						"type":        "integer",
+						"description": "Maximum bytes to return (default: the response budget)",
+					},
+				},
+				"required": []string{"resultId"},
+			},
+		},
 		{
 			"name": "hub_manage",
-			"description": `Manage MCP servers by adding or removing them from configuration.
+			"description": `Manage MCP servers by adding, updating or removing them from configuration.
 
 USE THIS TOOL when:
 • User asks to "add a server" or "register an MCP server"
 • User asks to "remove a server" or "unregister a server"
+• User asks to change a server's env var, command or args, or to enable/disable it
 • User provides server configuration details
+• User pastes an MCP client config (mcpServers, mcp, context_servers) to register
 
 OPERATIONS:
 1. add - Register a new MCP server
@@ -438,6 +939,18 @@ OPERATIONS:
 2. remove - Unregister an MCP server
    - Required: name
 
+3. rename - Rename an MCP server (history and index move with it)
+   - Required: name, newName
+
+4. update - Change fields of an existing server; omitted fields are kept
+   - Required: name
+   - Optional: command, args (replace), env (merged; null removes a key), enabled
+
+5. import - Register every server in a pasted client config (Claude Code, OpenCode, Zed, ...)
+   - Required: config (JSON object or string)
+   - Optional: name (for a config holding a single server object)
+   - Existing servers are skipped
+
 IMPORTANT:
 • Server names will be normalized to camelCase
 • Config is validated before saving
@@ -447,6 +960,9 @@ IMPORTANT:
 EXAMPLES:
 • Add: {"operation": "add", "name": "jira", "command": "npx", "args": ["-y", "@lvmk/jira-mcp"], "env": {"API_KEY": "..."}}
 • Remove: {"operation": "remove", "name": "jira"}
+• Rename: {"operation": "rename", "name": "jira", "newName": "jiraCloud"}
+• Update: {"operation": "update", "name": "jira", "env": {"API_KEY": "new-key"}}
+• Import: {"operation": "import", "config": {"mcpServers": {"jira": {"command": "npx", "args": ["-y", "@lvmk/jira-mcp"]}}}}
 
 CURRENTLY REGISTERED: ` + serverList,
 			"inputSchema": map[string]interface{}{
@@ -454,37 +970,53 @@ CURRENTLY REGISTERED: ` + serverList,
 				"properties": map[string]interface{}{
 					"operation": map[string]interface{}{
 						"type":        "string",
-						"enum":        []string{"add", "remove"},
-						"description": "Operation to perform (add or remove)",
+						"enum":        []string{"add", "remove", "rename", "update", "import"},
+						"description": "Operation to perform (add, remove, rename, update or import)",
 					},
 					"name": map[string]interface{}{
 						"type":        "string",
-						"description": "Server name (will be normalized to camelCase)",
+						"description": "Server name (will be normalized to camelCase; not needed for import)",
+					},
+					"newName": map[string]interface{}{
+						"type":        "string",
+						"description": "New server name (required for rename operation)",
 					},
 					"command": map[string]interface{}{
 						"type":        "string",
-						"description": "Command to execute (required for add operation)",
+						"description": "Command to execute (required for add, optional for update). A command line with spaces (\"npx -y @pkg/mcp\") is split into command and args",
 					},
 					"args": map[string]interface{}{
 						"type": "array",
 						"items": map[string]interface{}{
 							"type": "string",
 						},
-						"description": "Command arguments (required for add operation)",
+						"description": "Command arguments (required for add; replaces the args on update)",
 					},
 					"env": map[string]interface{}{
 						"type": "object",
 						"additionalProperties": map[string]interface{}{
-							"type": "string",
+							"type": []string{"string", "null"},
 						},
-						"description": "Environment variables (optional for add operation)",
+						"description": "Environment variables (optional for add; merged on update, null removes a variable)",
+					},
+					"enabled": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Enable or disable the server (update operation only)",
+					},
+					"config": map[string]interface{}{
+						"type":        []string{"object", "string"},
+						"description": "MCP client config to import, e.g. {\"mcpServers\": {...}} (required for import operation)",
 					},
 				},
-				"required": []string{"operation", "name"},
+				"required": []string{"operation"},
 			},
 		},
 	}
 
+	tools = s.selectMetaTools(tools)
+	s.trimMetaTools(tools)
+	annotateMetaTools(tools, s.sessionOf(req).protocol())
+
 	return &MCPResponse{
 		JSONRPC: "2.0",
 		ID:      req.ID,
@@ -494,7 +1026,17 @@ CURRENTLY REGISTERED: ` + serverList,
 	}, nil
 }
 
-// getServerNames returns a comma-separated list of server names.
+// featureEnabled reports whether an experimental feature is switched on.
+// Thread-safe: acquires read lock.
+func (s *Server) featureEnabled(name string) bool {
+	s.configMu.RLock()
+	defer s.configMu.RUnlock()
+
+	return s.config.FeatureEnabled(name)
+}
+
+// getServerNames returns a sorted, comma-separated list of server names,
+// capped at the description server limit.
 func (s *Server) getServerNames() string {
 	s.configMu.RLock()
 	defer s.configMu.RUnlock()
@@ -503,14 +1045,8 @@ func (s *Server) getServerNames() string {
 	for name := range s.config.Servers {
 		names = append(names, name)
 	}
-	result := ""
-	for i, name := range names {
-		if i > 0 {
-			result += ", "
-		}
-		result += name
-	}
-	return result
+	sort.Strings(names)
+	return listServers(names, s.config.Settings.DescriptionServerLimit())
 }
 
 // getServerNamesList returns server names as a slice for enum.
@@ -545,11 +1081,15 @@ func (s *Server) getFailedServers() []map[string]interface{} {
 	return result
 }
 
-// handleToolsCall handles tool execution requests.
-func (s *Server) handleToolsCall(req *MCPRequest) (*MCPResponse, error) {
+// callTool executes a meta-tool call (after middleware).
+func (s *Server) callTool(req *MCPRequest) (*MCPResponse, error) {
+	sess := s.sessionOf(req)
 	var params struct {
 		Name      string                 `json:"name"`
 		Arguments map[string]interface{} `json:"arguments"`
+		Meta      struct {
+			ProgressToken interface{} `json:"progressToken"`
+		} `json:"_meta"`
 	}
 
 	if err := json.Unmarshal(req.Params, &params); err != nil {
@@ -559,47 +1099,131 @@ func (s *Server) handleToolsCall(req *MCPRequest) (*MCPResponse, error) {
 	var result interface{}
 	var err error
 
-	switch params.Name {
+	// Meta-tools left out of settings.metaTools are unknown to clients
+	name := params.Name
+	if !s.metaToolEnabled(name) {
+		name = ""
+	}
+
+	switch name {
 	case "hub_search":
 		query, _ := params.Arguments["query"].(string)
 		server, _ := params.Arguments["server"].(string)
+		if server != "" {
+			server = s.resolveServerName(server)
+		}
+		var servers []string
+		for _, name := range stringArgs(params.Arguments["servers"]) {
+			servers = append(servers, s.resolveServerName(name))
+		}
 		limitFloat, _ := params.Arguments["limit"].(float64)
-		limit := int(limitFloat)
-		result, err = s.execHubSearch(query, server, limit)
+		offsetFloat, _ := params.Arguments["offset"].(float64)
+		cursor, _ := params.Arguments["cursor"].(string)
+		includeChanges, _ := params.Arguments["includeChanges"].(bool)
+		dedupe, _ := params.Arguments["dedupe"].(bool)
+		detail, _ := params.Arguments["detail"].(string)
+		result, err = s.runHubSearch(searchRequest{
+			Query:          query,
+			Server:         server,
+			Servers:        servers,
+			Tags:           stringArgs(params.Arguments["tags"]),
+			Limit:          int(limitFloat),
+			Offset:         int(offsetFloat),
+			Cursor:         cursor,
+			IncludeChanges: includeChanges,
+			Dedupe:         dedupe,
+			Detail:         detail,
+			Session:        sess,
+		})
+		s.metrics.observeSearch(err)
+	case "hub_list":
+		result, err = s.execHubList()
+	case "hub_discover":
+		serverName, _ := params.Arguments["server"].(string)
+		compact, _ := params.Arguments["compact"].(bool)
+		result, err = s.execHubDiscover(s.resolveServerName(serverName), compact)
 	case "hub_execute":
 		serverName, _ := params.Arguments["server"].(string)
+		serverName = s.resolveServerName(serverName)
 		toolName, _ := params.Arguments["tool"].(string)
 		args, _ := params.Arguments["arguments"].(map[string]interface{})
 		searchId, _ := params.Arguments["searchId"].(string)
-		result, err = s.execHubExecute(serverName, toolName, args, searchId)
+		confirm, _ := params.Arguments["confirm"].(bool)
+		if dryRun, _ := params.Arguments["dryRun"].(bool); dryRun {
+			result, err = s.execDryRun(serverName, toolName, args)
+			break
+		}
+		if err = s.checkConfirmation(serverName, toolName, args, confirm); err != nil {
+			break
+		}
+		started := time.Now()
+		executionID := uuid.New().String()
+		s.webhooks.Send(webhook.Event{Type: webhook.ExecutionStarted, Server: serverName, Tool: toolName, ExecutionID: executionID})
+		if s.searches.belongsElsewhere(searchId, sess) {
+			log.Printf("Warning: searchId %s was issued to another session, not attributing this call to it", searchId)
+			searchId = ""
+		}
+		result, err = s.execHubExecute(sess, serverName, toolName, args, searchId, params.Meta.ProgressToken)
+		s.recordAudit(auditCall{
+			Server:      serverName,
+			Tool:        toolName,
+			Args:        args,
+			SearchID:    searchId,
+			ExecutionID: executionID,
+		}, started, err)
+		s.announceExecution(serverName, toolName, executionID, started, err)
+		s.metrics.observeExecution(serverName, time.Since(started), err)
+	case "hub_schema":
+		serverName, _ := params.Arguments["server"].(string)
+		toolName, _ := params.Arguments["tool"].(string)
+		result, err = s.execHubSchema(s.resolveServerName(serverName), toolName)
+	case "hub_help":
+		serverName, _ := params.Arguments["server"].(string)
+		toolName, _ := params.Arguments["tool"].(string)
+		result, err = s.execHubHelp(s.resolveServerName(serverName), toolName)
+	case "hub_status":
+		result, err = s.execHubStatus(sess)
+	case "hub_fetch_result":
+		resultID, _ := params.Arguments["resultId"].(string)
+		offsetFloat, _ := params.Arguments["offset"].(float64)
+		lengthFloat, _ := params.Arguments["length"].(float64)
+		result, err = s.execHubFetchResult(resultID, int(offsetFloat), int(lengthFloat))
 	case "hub_manage":
 		operation, _ := params.Arguments["operation"].(string)
 		name, _ := params.Arguments["name"].(string)
-		command, _ := params.Arguments["command"].(string)
-
-		// Parse args array
-		var args []string
-		if argsInterface, ok := params.Arguments["args"].([]interface{}); ok {
-			args = make([]string, len(argsInterface))
-			for i, v := range argsInterface {
-				if str, ok := v.(string); ok {
-					args[i] = str
+		switch operation {
+		case "update":
+			result, err = s.execHubUpdate(name, parseServerPatch(params.Arguments))
+		case "import":
+			result, err = s.execHubImport(params.Arguments["config"], name)
+		default:
+			newName, _ := params.Arguments["newName"].(string)
+			command, _ := params.Arguments["command"].(string)
+
+			// Parse args array
+			var args []string
+			if argsInterface, ok := params.Arguments["args"].([]interface{}); ok {
+				args = make([]string, len(argsInterface))
+				for i, v := range argsInterface {
+					if str, ok := v.(string); ok {
+						args[i] = str
+					}
 				}
 			}
-		}
 
-		// Parse env map
-		var env map[string]string
-		if envInterface, ok := params.Arguments["env"].(map[string]interface{}); ok {
-			env = make(map[string]string)
-			for k, v := range envInterface {
-				if str, ok := v.(string); ok {
-					env[k] = str
+			// Parse env map
+			var env map[string]string
+			if envInterface, ok := params.Arguments["env"].(map[string]interface{}); ok {
+				env = make(map[string]string)
+				for k, v := range envInterface {
+					if str, ok := v.(string); ok {
+						env[k] = str
+					}
 				}
 			}
-		}
 
-		result, err = s.execHubManage(operation, name, command, args, env)
+			result, err = s.execHubManage(operation, name, newName, command, args, env)
+		}
 	default:
 		return &MCPResponse{
 			JSONRPC: "2.0",
@@ -608,11 +1232,25 @@ func (s *Server) handleToolsCall(req *MCPRequest) (*MCPResponse, error) {
 		}, nil
 	}
 
+	var toolErr *ToolError
+	if errors.As(err, &toolErr) {
+		// Structured failures are tool results the model can act on
+		return toolErrorResponse(req.ID, toolErr), nil
+	}
+
 	if err != nil {
 		return &MCPResponse{
 			JSONRPC: "2.0",
 			ID:      req.ID,
-			Error:   &MCPError{Code: -32000, Message: err.Error()},
+			Error:   &MCPError{Code: -32000, Message: redact.String(err.Error())},
+		}, nil
+	}
+
+	if content, ok := result.(*toolContent); ok {
+		return &MCPResponse{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Result:  content.result(),
 		}, nil
 	}
 
@@ -633,8 +1271,54 @@ func (s *Server) handleToolsCall(req *MCPRequest) (*MCPResponse, error) {
 // execHubSearch searches for tools across all servers using BM25 semantic search.
 // Returns rich JSON response with searchId, tool details, schemas, and failed servers.
 func (s *Server) execHubSearch(query, serverFilter string, limit int) (string, error) {
+	return s.runHubSearch(searchRequest{Query: query, Server: serverFilter, Limit: limit})
+}
+
+// searchRequest holds the arguments of a hub_search call.
+type searchRequest struct {
+	Query  string
+	Server string
+	Limit  int
+
+	// Servers and Tags further restrict results to tools of any of the
+	// servers, and of servers with any of the tags.
+	Servers []string
+	Tags    []string
+
+	// Offset skips that many ranked results; Cursor (a previous nextCursor)
+	// takes precedence when set.
+	Offset int
+	Cursor string
+
+	// IncludeChanges adds a changedSinceLastSearch marker to the response.
+	IncludeChanges bool
+
+	// Dedupe groups equivalent tools from different servers.
+	Dedupe bool
+
+	// Detail is "full" (with inputSchema) or "compact" (names, servers and
+	// one-line descriptions only); empty means full.
+	Detail string
+
+	// Session is the client searching; its searchIds are not accepted from
+	// other sessions. nil means stdio.
+	Session *session
+}
+
+// runHubSearch performs a hub_search with all optional arguments.
+func (s *Server) runHubSearch(req searchRequest) (string, error) {
+	query, limit := req.Query, req.Limit
+	filter := req.filter()
+	serverFilter := filterKey(filter)
+
+	detail, err := parseDetail(req.Detail)
+	if err != nil {
+		return "", err
+	}
+
 	// Generate unique searchId for tracking
 	searchID := uuid.New().String()
+	s.searches.record(searchID, s.orStdio(req.Session))
 
 	// Default limit if not specified
 	if limit <= 0 {
@@ -646,21 +1330,27 @@ func (s *Server) execHubSearch(query, serverFilter string, limit int) (string, e
 		return s.execHubSearchFallback(query, searchID)
 	}
 
-	var results []search.SearchResult
-	var err error
-
-	// Perform search with optional server filter
-	if serverFilter != "" {
-		// Search within specific server
-		results, err = s.indexer.SearchByServer(query, serverFilter, limit)
-	} else {
-		// Search across all servers
-		results, err = s.indexer.SearchBM25(query, limit)
+	// Resolve the page to return
+	offset := req.Offset
+	if req.Cursor != "" {
+		if offset, err = decodeSearchCursor(req.Cursor, query, serverFilter); err != nil {
+			return "", err
+		}
+	}
+	if offset < 0 {
+		offset = 0
 	}
 
+	s.configMu.RLock()
+	pipeline := s.pipeline
+	s.configMu.RUnlock()
+
+	// Perform search with optional server and tag filters
+	page, err := pipeline.Search(query, filter, limit, offset)
 	if err != nil {
 		return "", fmt.Errorf("search failed: %w", err)
 	}
+	results := page.Results
 
 	// Store search in history for learning
 	if s.storage != nil {
@@ -669,6 +1359,13 @@ func (s *Server) execHubSearch(query, serverFilter string, limit int) (string, e
 			QueryHash:    storage.HashQuery(query),
 			Timestamp:    time.Now(),
 			ResultsCount: len(results),
+			Results:      make([]string, 0, len(results)),
+		}
+		for _, r := range results {
+			searchRecord.Results = append(searchRecord.Results, toolID(r))
+		}
+		if s.auditQueries() {
+			searchRecord.Query = query
 		}
 		if err := s.storage.RecordSearch(searchRecord); err != nil {
 			log.Printf("Warning: failed to record search: %v", err)
@@ -676,11 +1373,48 @@ func (s *Server) execHubSearch(query, serverFilter string, limit int) (string, e
 	}
 
 	// Build rich response
+	formatted := s.formatSearchResults(results, req.Dedupe, detail == detailCompact)
 	response := map[string]interface{}{
 		"searchId":     searchID,
 		"query":        query,
-		"totalResults": len(results),
-		"results":      s.formatSearchResults(results),
+		"totalResults": len(formatted),
+		"results":      formatted,
+	}
+	if detail == detailCompact {
+		response["hint"] = "Call hub_schema with a result's server and name to get its inputSchema before hub_execute."
+	}
+
+	// Let clients iterate through large result sets page by page
+	if offset > 0 {
+		response["offset"] = offset
+	}
+	if page.Total >= 0 {
+		response["totalMatches"] = page.Total
+	}
+	if page.Facets != nil {
+		response["facets"] = page.Facets
+	}
+
+	// Offer "did you mean" queries instead of a dead end
+	if len(results) == 0 && offset == 0 {
+		suggestions, err := s.indexer.Suggest(query)
+		if err != nil {
+			log.Printf("Warning: failed to suggest queries: %v", err)
+		} else if len(suggestions) > 0 {
+			response["suggestions"] = suggestions
+			response["hint"] = "No tools matched. Try one of the suggestions as the query."
+		}
+	}
+	if page.HasMore {
+		response["nextCursor"] = encodeSearchCursor(query, serverFilter, offset+len(results))
+	}
+
+	// Compare against the previous result set for this query (first page only)
+	if offset == 0 {
+		changes := s.diffSearchResults(query, serverFilter, results)
+		if req.IncludeChanges {
+			response["changedSinceLastSearch"] = changes
+		}
 	}
 
 	// Add failed servers (always include for consistent schema)
@@ -701,17 +1435,73 @@ func (s *Server) execHubSearch(query, serverFilter string, limit int) (string, e
 }
 
 // formatSearchResults converts search results to compact format with tool details.
-func (s *Server) formatSearchResults(results []search.SearchResult) []map[string]interface{} {
-	formatted := make([]map[string]interface{}, 0, len(results))
+// Each result carries its canonical "server/tool" id; tool names offered by
+// several servers get a disambiguation hint. With dedupe, equivalent tools
+// are folded into their best-ranked result as alternatives. Compact results
+// keep only the id, name, server and first description line.
+func (s *Server) formatSearchResults(results []search.SearchResult, dedupe, compact bool) []map[string]interface{} {
+	hints := disambiguationHints(results)
+
+	var groups []resultGroup
+	if dedupe {
+		groups = dedupeResults(results)
+	} else {
+		groups = make([]resultGroup, len(results))
+		for i, r := range results {
+			groups[i] = resultGroup{SearchResult: r}
+		}
+	}
 
-	for _, result := range results {
+	formatted := make([]map[string]interface{}, 0, len(groups))
+
+	for _, result := range groups {
+		id := toolID(result.SearchResult)
+		if compact {
+			entry := map[string]interface{}{
+				"id":          id,
+				"name":        result.ToolName,
+				"server":      result.ServerName,
+				"description": FirstLine(result.Description),
+			}
+			s.markDegraded(entry, result.ServerName)
+			formatted = append(formatted, entry)
+			continue
+		}
 		toolDetail := map[string]interface{}{
+			"id":          id,
 			"name":        result.ToolName,
 			"description": result.Description,
 			"inputSchema": result.InputSchema,
 			"server":      result.ServerName,
 			"score":       result.Score,
 		}
+		if result.Example != nil {
+			toolDetail["exampleArguments"] = result.Example
+		}
+		if len(result.Tags) > 0 {
+			toolDetail["tags"] = result.Tags
+		}
+		if len(result.MatchedFields) > 0 {
+			toolDetail["matchedFields"] = result.MatchedFields
+		}
+		if len(result.Highlights) > 0 {
+			toolDetail["highlights"] = result.Highlights
+		}
+		if hint, ok := hints[id]; ok {
+			toolDetail["hint"] = hint
+		}
+		if len(result.alternatives) > 0 {
+			alternatives := make([]map[string]interface{}, 0, len(result.alternatives))
+			for _, alt := range result.alternatives {
+				alternatives = append(alternatives, map[string]interface{}{
+					"id":     toolID(alt),
+					"server": alt.ServerName,
+					"score":  alt.Score,
+				})
+			}
+			toolDetail["alternatives"] = alternatives
+		}
+		s.markDegraded(toolDetail, result.ServerName)
 
 		formatted = append(formatted, toolDetail)
 	}
@@ -771,31 +1561,123 @@ func (s *Server) execHubSearchFallback(query, searchID string) (string, error) {
 }
 
 // execHubExecute executes a tool from a server.
-func (s *Server) execHubExecute(serverName, toolName string, args map[string]interface{}, searchId string) (string, error) {
+// The result is a string, or a *toolContent when passthrough is enabled
+// or the call was retried (see withRetryMeta).
+// When the client supplied a progressToken, it receives a progress
+// notification if the call is queued behind the server's concurrency
+// limit, and any progress the child reports for the call.
+func (s *Server) execHubExecute(sess *session, serverName, toolName string, args map[string]interface{}, searchId string, progressToken interface{}) (interface{}, error) {
+	sess = s.orStdio(sess)
 	s.configMu.RLock()
 	server, exists := s.config.Servers[serverName]
 	s.configMu.RUnlock()
 
 	if !exists {
-		return "", fmt.Errorf("server '%s' not found", serverName)
+		return nil, s.serverNotFound(serverName)
+	}
+	if server.Disabled {
+		return nil, fmt.Errorf("server '%s' is disabled", serverName)
 	}
 
+	// Reuse a fresh result of a read-only tool instead of calling the child
+	cached, hit, store := s.cachedCall(serverName, server, toolName, args)
+	if hit {
+		s.trackUsage(serverName, toolName, searchId, true)
+		return s.formatToolResponse(cached, sess.protocol())
+	}
+
+	// Fail fast while the server's circuit is open
+	if until, open := s.circuits.openUntil(serverName); open {
+		return nil, s.circuits.unavailableError(serverName, until)
+	}
+
+	// Reject calls over the client or server throttle
+	if err := s.throttleCall(sess, serverName, server); err != nil {
+		return nil, err
+	}
+
+	// Respect the provider quota before reaching the child
+	if err := s.acquireQuota(serverName, server); err != nil {
+		return nil, err
+	}
+
+	// Keep oversized arguments out of the child's stdin framing
+	args, cleanup, err := s.offloadLargeArguments(serverName, server, toolName, args)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
 	// Execute tool
-	result, err := s.spawner.ExecuteTool(serverName, server, toolName, args)
+	retries := &callRetries{}
+	opts := spawner.CallOptions{
+		OnQueued:   s.queueNotifier(sess, serverName, progressToken),
+		Idempotent: s.idempotent(serverName, server, toolName),
+		OnRetry:    retries.record,
+	}
+	if progressToken != nil {
+		childToken, release := s.relayProgress(sess, progressToken)
+		defer release()
+		opts.ProgressToken = childToken
+	}
+	response, err := s.spawner.CallToolWithOptions(serverName, server, toolName, args, opts)
+	s.circuits.record(serverName, err)
 	if err != nil {
 		// Track failed execution
-		s.trackUsage(toolName, searchId, false)
-		return "", fmt.Errorf("failed to execute tool: %w", err)
+		s.trackUsage(serverName, toolName, searchId, false)
+		s.recordArgumentMistake(serverName, toolName, err)
+		if n := retries.count(); n > 0 {
+			return nil, fmt.Errorf("failed to execute tool after %d attempts: %w", n+1, err)
+		}
+		return nil, fmt.Errorf("failed to execute tool: %w", err)
+	}
+
+	// A result flagged isError is a tool-level failure, not a success
+	s.trackUsage(serverName, toolName, searchId, !isErrorResult(response))
+	if store != nil {
+		store(response)
+	}
+
+	result, err := s.formatToolResponse(response, sess.protocol())
+	if err != nil {
+		return nil, err
+	}
+	return withRetryMeta(result, retries), nil
+}
+
+// formatToolResponse renders a child tools/call result for the client:
+// typed content blocks when passthrough is enabled, otherwise indented
+// JSON within the response budget. protocolVersion is the client's.
+func (s *Server) formatToolResponse(response interface{}, protocolVersion string) (interface{}, error) {
+	// Forward typed content blocks (images, resources) natively
+	if s.featureEnabled(config.FeaturePassthrough) {
+		if content := s.passthroughContent(response, protocolVersion); content != nil {
+			return content, nil
+		}
+	}
+
+	result, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode tool result: %w", err)
 	}
 
-	// Track successful execution
-	s.trackUsage(toolName, searchId, true)
+	return s.budgetResponse(string(result)), nil
+}
 
-	return result, nil
+// isErrorResult reports whether a child tools/call result carries
+// isError: true, i.e. the call reached the tool but the tool failed.
+func isErrorResult(response interface{}) bool {
+	result, ok := response.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	isError, _ := result["isError"].(bool)
+	return isError
 }
 
 // trackUsage records tool usage for learning (non-blocking).
-func (s *Server) trackUsage(toolName, searchId string, success bool) {
+// The raw searchId links the event to its search for per-query learning.
+func (s *Server) trackUsage(serverName, toolName, searchId string, success bool) {
 	if s.tracker == nil {
 		return
 	}
@@ -813,6 +1695,9 @@ func (s *Server) trackUsage(toolName, searchId string, success bool) {
 		Timestamp:   time.Now(),
 		Selected:    true,
 		Rating:      0,
+		Success:     success,
+		Server:      serverName,
+		SearchID:    searchId,
 	}
 
 	// Non-blocking track
@@ -824,15 +1709,17 @@ func (s *Server) trackUsage(toolName, searchId string, success bool) {
 	}
 }
 
-// execHubManage handles server management operations (add/remove).
-func (s *Server) execHubManage(operation, name, command string, args []string, env map[string]string) (string, error) {
+// execHubManage handles server management operations (add/remove/rename).
+// Updates and imports go through execHubUpdate and execHubImport, which
+// need their raw arguments.
+func (s *Server) execHubManage(operation, name, newName, command string, args []string, env map[string]string) (string, error) {
 	// Acquire write lock for config modification
 	s.configMu.Lock()
 	defer s.configMu.Unlock()
 
 	// Validate operation
-	if operation != "add" && operation != "remove" {
-		return "", fmt.Errorf("invalid operation '%s'. Must be 'add' or 'remove'", operation)
+	if operation != "add" && operation != "remove" && operation != "rename" {
+		return "", fmt.Errorf("invalid operation '%s'. Must be 'add', 'remove', 'rename', 'update' or 'import'", operation)
 	}
 
 	// Validate name
@@ -848,6 +1735,8 @@ func (s *Server) execHubManage(operation, name, command string, args []string, e
 		return s.addServer(name, command, args, env)
 	case "remove":
 		return s.removeServer(name)
+	case "rename":
+		return s.renameServer(name, newName)
 	default:
 		return "", fmt.Errorf("unsupported operation: %s", operation)
 	}
@@ -865,9 +1754,14 @@ func (s *Server) addServer(name, command string, args []string, env map[string]s
 		args = []string{} // Default to empty array
 	}
 
-	// Check if server already exists
-	if _, exists := s.config.Servers[name]; exists {
-		return "", fmt.Errorf("server '%s' already exists. Use hub_execute to list servers or remove first", name)
+	// Normalize the name the same way the CLI does, and check if the
+	// server already exists
+	key, exists := config.UniqueServerName(s.config.Servers, name)
+	if exists {
+		return "", fmt.Errorf("server '%s' already exists. Use hub_execute to list servers or remove first", key)
+	}
+	if base := config.ToCamelCase(name); key != base {
+		log.Printf("Warning: '%s' collides with existing server '%s', added as '%s'", name, base, key)
 	}
 
 	// Create server config
@@ -877,9 +1771,15 @@ func (s *Server) addServer(name, command string, args []string, env map[string]s
 		Env:     env,
 		Source:  "hub_manage",
 	}
+	if _, err := serverCfg.NormalizeCommand(); err != nil {
+		return "", err
+	}
+	serverCfg.SetOriginalName(key, name)
+	name = key
 
 	// Add to config
 	s.config.Servers[name] = serverCfg
+	redact.Secrets.AddEnv(env)
 
 	// Save config atomically
 	configPath, err := config.GetDefaultConfigPath()
@@ -894,28 +1794,24 @@ func (s *Server) addServer(name, command string, args []string, env map[string]s
 		delete(s.config.Servers, name)
 		return "", fmt.Errorf("failed to save config: %w. Config rolled back", err)
 	}
+	s.webhooks.Send(webhook.Event{Type: webhook.ServerAdded, Server: name})
 
-	// Trigger reindexing (must hold lock)
-	if s.indexer != nil {
-		if err := s.indexToolsUnsafe(); err != nil {
-			log.Printf("Warning: failed to reindex after adding server '%s': %v", name, err)
-		}
-	}
+	// Index only the new server (must hold lock)
+	s.indexServersUnsafe(name)
 
 	return fmt.Sprintf("✓ Server '%s' added successfully.\n\nCommand: %s\nArgs: %v\n\nConfig saved to: %s\nIndexing triggered.",
-		name, command, args, configPath), nil
+		name, command, redact.Args(args), configPath), nil
 }
 
 // removeServer removes an MCP server from the configuration.
 func (s *Server) removeServer(name string) (string, error) {
-	// Check if server exists
-	if _, exists := s.config.Servers[name]; !exists {
-		availableServers := make([]string, 0, len(s.config.Servers))
-		for serverName := range s.config.Servers {
-			availableServers = append(availableServers, serverName)
-		}
-		return "", fmt.Errorf("server '%s' not found. Available servers: %v", name, availableServers)
+	// Check if server exists, accepting source names, aliases and other
+	// spellings of its name
+	key, exists := s.config.ResolveServer(name)
+	if !exists {
+		return "", s.config.ServerNotFound(name)
 	}
+	name = key
 
 	// Backup server config for potential rollback
 	backupCfg := s.config.Servers[name]
@@ -936,6 +1832,7 @@ func (s *Server) removeServer(name string) (string, error) {
 		s.config.Servers[name] = backupCfg
 		return "", fmt.Errorf("failed to save config: %w. Config rolled back", err)
 	}
+	s.webhooks.Send(webhook.Event{Type: webhook.ServerRemoved, Server: name})
 
 	// Remove from indexer if available
 	if s.indexer != nil {
@@ -943,22 +1840,129 @@ func (s *Server) removeServer(name string) (string, error) {
 			log.Printf("Warning: failed to remove server '%s' from index: %v", name, err)
 		}
 	}
+	s.forgetIndexed(name)
+	s.cache.invalidate(name)
+	s.circuits.reset(name)
+	delete(s.failedServers, name)
+	s.notifyToolsListChanged()
+
+	return fmt.Sprintf("✓ Server '%s' removed successfully.\n\nConfig saved to: %s\nIndexing triggered.",
+		name, configPath), nil
+}
+
+// renameServer renames an MCP server. Its audit and search history, index
+// entries and running process move to the new name; on failure the config
+// and history are restored.
+func (s *Server) renameServer(name, newName string) (string, error) {
+	newName = strings.TrimSpace(newName)
+	if newName == "" {
+		return "", fmt.Errorf("newName cannot be empty for rename operation")
+	}
+
+	oldKey, exists := s.config.ResolveServer(name)
+	if !exists {
+		return "", s.config.ServerNotFound(name)
+	}
+	newKey := config.ToCamelCase(newName)
+
+	// Backup server config for potential rollback
+	backup := *s.config.Servers[oldKey]
+	rollback := func() {
+		delete(s.config.Servers, newKey)
+		s.config.Servers[oldKey] = &backup
+	}
+
+	if err := s.config.RenameServer(oldKey, newKey, false); err != nil {
+		return "", err
+	}
+
+	if s.storage != nil {
+		if err := s.storage.RenameServer(oldKey, newKey); err != nil {
+			rollback()
+			return "", fmt.Errorf("failed to migrate history: %w", err)
+		}
+	}
+
+	configPath, err := config.GetDefaultConfigPath()
+	if err == nil {
+		err = config.Save(s.config, configPath)
+	}
+	if err != nil {
+		rollback()
+		if s.storage != nil {
+			if rbErr := s.storage.RenameServer(newKey, oldKey); rbErr != nil {
+				log.Printf("Warning: failed to restore history for '%s': %v", oldKey, rbErr)
+			}
+		}
+		return "", fmt.Errorf("failed to save config: %w. Config rolled back", err)
+	}
 
-	// Trigger reindexing (must hold lock)
 	if s.indexer != nil {
-		if err := s.indexToolsUnsafe(); err != nil {
-			log.Printf("Warning: failed to reindex after removing server '%s': %v", name, err)
+		if err := s.indexer.RenameServer(oldKey, newKey); err != nil {
+			log.Printf("Warning: failed to rename server '%s' in index: %v", oldKey, err)
 		}
 	}
+	s.spawner.Rename(oldKey, newKey)
+	s.renameIndexed(oldKey, newKey)
+	s.cache.invalidate(oldKey)
+	s.circuits.reset(oldKey)
+	if msg, ok := s.failedServers[oldKey]; ok {
+		delete(s.failedServers, oldKey)
+		s.failedServers[newKey] = msg
+	}
+	s.notifyToolsListChanged()
+	s.webhooks.Send(webhook.Event{Type: webhook.ServerRemoved, Server: oldKey})
+	s.webhooks.Send(webhook.Event{Type: webhook.ServerAdded, Server: newKey})
 
-	return fmt.Sprintf("✓ Server '%s' removed successfully.\n\nConfig saved to: %s\nIndexing triggered.",
-		name, configPath), nil
+	return fmt.Sprintf("✓ Server '%s' renamed to '%s'.\n\nConfig saved to: %s", oldKey, newKey, configPath), nil
+}
+
+// serverNotFound returns the error for an unknown server name.
+// Thread-safe: acquires read lock.
+func (s *Server) serverNotFound(name string) error {
+	s.configMu.RLock()
+	defer s.configMu.RUnlock()
+	return s.config.ServerNotFound(name)
+}
+
+// resolveServerName maps a server name, source name or alias to its config
+// key. Unknown names are returned unchanged.
+// Thread-safe: acquires read lock.
+func (s *Server) resolveServerName(name string) string {
+	s.configMu.RLock()
+	defer s.configMu.RUnlock()
+
+	if key, ok := s.config.ResolveServer(name); ok {
+		return key
+	}
+	return name
 }
 
 // sendResponse writes a JSON-RPC response to stdout.
 func (s *Server) sendResponse(resp *MCPResponse) {
 	data, _ := json.Marshal(resp)
-	fmt.Println(string(data))
+	s.writeLine(data)
+}
+
+// sendNotification writes a JSON-RPC notification to stdout.
+// params may be nil for notifications without parameters.
+func (s *Server) sendNotification(method string, params interface{}) {
+	msg := map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  method,
+	}
+	if params != nil {
+		msg["params"] = params
+	}
+	data, _ := json.Marshal(msg)
+	s.writeLine(data)
+}
+
+// writeLine writes one newline-delimited message.
+func (s *Server) writeLine(data []byte) {
+	s.outMu.Lock()
+	defer s.outMu.Unlock()
+	fmt.Fprintln(s.out, string(data))
 }
 
 // sendError writes an error response to stdout.
@@ -9,7 +9,7 @@ import (
 
 	"github.com/khanglvm/tool-hub-mcp/internal/config"
 	"github.com/khanglvm/tool-hub-mcp/internal/learning"
-	"github.com/khanglvm/tool-hub-mcp/internal/spawner"
+	"github.com/khanglvm/tool-hub-mcp/internal/protocol"
 )
 
 // TestHandleToolsList tests tools/list RPC handler
@@ -69,7 +69,7 @@ func TestHandleToolsList(t *testing.T) {
 		}
 	}
 
-	expectedTools := []string{"hub_search", "hub_execute", "hub_manage"}
+	expectedTools := []string{"hub_search", "hub_discover", "hub_execute", "hub_fetch_result", "hub_manage"}
 	for _, expected := range expectedTools {
 		if !toolNames[expected] {
 			t.Errorf("missing expected tool: %s", expected)
@@ -157,7 +157,7 @@ func TestHubSearchBM25Ranking(t *testing.T) {
 
 			// Index mock tools for testing BM25
 			if server.indexer != nil {
-				tools := []spawner.Tool{
+				tools := []protocol.Tool{
 					{
 						Name:        "create_issue",
 						Description: "Create a new issue in the system",
@@ -256,7 +256,7 @@ func TestHubExecuteWithLearning(t *testing.T) {
 	defer server.Close()
 
 	// Test execution without searchId
-	_, err := server.execHubExecute("echo", "test_tool", map[string]interface{}{}, "")
+	_, err := server.execHubExecute(nil, "echo", "test_tool", map[string]interface{}{}, "", nil)
 	if err == nil {
 		// Echo server doesn't support tools/call - expected
 		t.Log("Expected error for echo server (no MCP support)")
@@ -264,7 +264,7 @@ func TestHubExecuteWithLearning(t *testing.T) {
 
 	// Test execution with searchId
 	searchID := "test-search-id-123"
-	_, err = server.execHubExecute("echo", "test_tool", map[string]interface{}{}, searchID)
+	_, err = server.execHubExecute(nil, "echo", "test_tool", map[string]interface{}{}, searchID, nil)
 	if err == nil {
 		t.Log("Echo server doesn't support MCP - expected error")
 	}
@@ -286,7 +286,7 @@ func TestHubExecuteWithLearning(t *testing.T) {
 	}
 
 	// Test with non-existent server
-	_, err = server.execHubExecute("nonexistent", "test_tool", map[string]interface{}{}, "")
+	_, err = server.execHubExecute(nil, "nonexistent", "test_tool", map[string]interface{}{}, "", nil)
 	if err == nil {
 		t.Error("expected error for non-existent server")
 	}
@@ -311,7 +311,7 @@ func TestConcurrentToolCalls(t *testing.T) {
 
 	// Index test tools
 	if server.indexer != nil {
-		tools := []spawner.Tool{
+		tools := []protocol.Tool{
 			{
 				Name:        "concurrent_test",
 				Description: "Tool for concurrent testing",
@@ -470,14 +470,14 @@ func TestSearchWithServerFilter(t *testing.T) {
 
 	// Index tools from multiple servers
 	if server.indexer != nil {
-		jiraTools := []spawner.Tool{
+		jiraTools := []protocol.Tool{
 			{
 				Name:        "create_issue",
 				Description: "Create Jira issue",
 				InputSchema: json.RawMessage(`{}`),
 			},
 		}
-		githubTools := []spawner.Tool{
+		githubTools := []protocol.Tool{
 			{
 				Name:        "create_issue",
 				Description: "Create GitHub issue",
@@ -546,7 +546,7 @@ func TestBanditRanking(t *testing.T) {
 
 	// Index tools
 	if server.indexer != nil {
-		tools := []spawner.Tool{
+		tools := []protocol.Tool{
 			{
 				Name:        "tool_a",
 				Description: "First test tool",
@@ -582,7 +582,7 @@ func TestBanditRanking(t *testing.T) {
 	if server.tracker != nil && server.storage != nil {
 		// Track multiple successful uses of tool_a
 		for i := 0; i < 5; i++ {
-			server.trackUsage("tool_a", searchID, true)
+			server.trackUsage("test", "tool_a", searchID, true)
 		}
 
 		// Wait for flush
@@ -617,7 +617,7 @@ func TestIndexReload(t *testing.T) {
 
 	// Initial index
 	if server.indexer != nil {
-		tools := []spawner.Tool{
+		tools := []protocol.Tool{
 			{Name: "tool1", Description: "Tool 1", InputSchema: json.RawMessage(`{}`)},
 		}
 		_ = server.indexer.IndexServer("server1", tools)
@@ -660,7 +660,7 @@ func TestSearchResultsStructure(t *testing.T) {
 
 	// Index tools
 	if server.indexer != nil {
-		tools := []spawner.Tool{
+		tools := []protocol.Tool{
 			{
 				Name:        "example_tool",
 				Description: "Example tool for testing",
@@ -736,7 +736,7 @@ func TestCompactJSON(t *testing.T) {
 
 	// Index tools
 	if server.indexer != nil {
-		tools := []spawner.Tool{
+		tools := []protocol.Tool{
 			{
 				Name:        "test_tool",
 				Description: "Test tool for compact JSON validation",
@@ -794,7 +794,7 @@ func TestRPCHybridSearchWorkflow(t *testing.T) {
 	}
 
 	// Index tools
-	tools := []spawner.Tool{
+	tools := []protocol.Tool{
 		{
 			Name:        "create_task",
 			Description: "Create a new task in project management",
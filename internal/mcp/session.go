@@ -0,0 +1,200 @@
+package mcp
+
+import (
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/protocol"
+	"github.com/khanglvm/tool-hub-mcp/internal/ratelimit"
+)
+
+// maxTrackedSearches bounds the searchId → session map; the oldest
+// searches are forgotten first.
+const maxTrackedSearches = 4096
+
+// session is one connected client's state: its negotiated protocol,
+// in-flight requests and notification channel. Stdio serves a single
+// session; the HTTP transport gives each client its own.
+type session struct {
+	id string
+
+	// protocolVersion holds the revision negotiated in initialize (a string)
+	protocolVersion atomic.Value
+
+	// ready is set once the client has initialized and may receive
+	// notifications
+	ready atomic.Bool
+
+	// notify delivers a notification to the client; nil drops them
+	notify func(method string, params interface{})
+
+	// inflight maps request IDs (see requestKey) to a flag set when the
+	// client cancels the request
+	inflight   map[string]*atomic.Bool
+	inflightMu sync.Mutex
+
+	// lastSeen is when the session last made a request (UnixNano)
+	lastSeen atomic.Int64
+
+	// bucket throttles the session's hub_execute calls
+	// (Settings.ClientThrottle), created on first use
+	bucket   *ratelimit.Bucket
+	bucketMu sync.Mutex
+}
+
+// newSession creates a session. notify may be nil.
+func newSession(id string, notify func(method string, params interface{})) *session {
+	sess := &session{id: id, notify: notify, inflight: make(map[string]*atomic.Bool)}
+	sess.touch()
+	return sess
+}
+
+// touch records activity on the session.
+func (sess *session) touch() {
+	sess.lastSeen.Store(time.Now().UnixNano())
+}
+
+// idleSince returns how long the session has been inactive.
+func (sess *session) idleSince() time.Duration {
+	return time.Since(time.Unix(0, sess.lastSeen.Load()))
+}
+
+// protocol returns the revision negotiated with the client, or the newest
+// revision if the client has not initialized.
+func (sess *session) protocol() string {
+	if v, ok := sess.protocolVersion.Load().(string); ok {
+		return v
+	}
+	return protocol.Latest
+}
+
+// send delivers a notification, if the session's transport can.
+func (sess *session) send(method string, params interface{}) {
+	if sess.notify != nil {
+		sess.notify(method, params)
+	}
+}
+
+// trackRequest registers an in-flight request and returns its cancel flag.
+func (sess *session) trackRequest(key string) *atomic.Bool {
+	cancelled := &atomic.Bool{}
+	sess.inflightMu.Lock()
+	sess.inflight[key] = cancelled
+	sess.inflightMu.Unlock()
+	return cancelled
+}
+
+// untrackRequest removes a finished request.
+func (sess *session) untrackRequest(key string) {
+	sess.inflightMu.Lock()
+	delete(sess.inflight, key)
+	sess.inflightMu.Unlock()
+}
+
+// cancelRequest marks an in-flight request cancelled so its response is
+// dropped. The underlying tool call still runs to completion.
+func (sess *session) cancelRequest(key, reason string) {
+	sess.inflightMu.Lock()
+	cancelled, ok := sess.inflight[key]
+	sess.inflightMu.Unlock()
+	if !ok {
+		return
+	}
+
+	cancelled.Store(true)
+	if reason != "" {
+		log.Printf("Client cancelled request %s: %s", key, reason)
+	}
+}
+
+// cancelAll cancels every in-flight request, e.g. when the client
+// disconnects, and returns how many there were.
+func (sess *session) cancelAll() int {
+	sess.inflightMu.Lock()
+	defer sess.inflightMu.Unlock()
+	for _, cancelled := range sess.inflight {
+		cancelled.Store(true)
+	}
+	return len(sess.inflight)
+}
+
+// sessionOf returns the session a request arrived on, defaulting to stdio
+// for requests handled in-process.
+func (s *Server) sessionOf(req *MCPRequest) *session {
+	if req != nil && req.session != nil {
+		return req.session
+	}
+	return s.stdio
+}
+
+// orStdio returns sess, or the stdio session when sess is nil.
+func (s *Server) orStdio(sess *session) *session {
+	if sess != nil {
+		return sess
+	}
+	return s.stdio
+}
+
+// clientSessions returns the stdio session and every HTTP session.
+func (s *Server) clientSessions() []*session {
+	s.sessionsMu.Lock()
+	defer s.sessionsMu.Unlock()
+	sessions := []*session{s.stdio}
+	for _, sess := range s.sessions {
+		sessions = append(sessions, sess)
+	}
+	return sessions
+}
+
+// searchOwners records which session issued each searchId, so one
+// client's searchIds are never attributed to another client's executions.
+type searchOwners struct {
+	mu     sync.Mutex
+	owners map[string]*session
+	order  []string
+}
+
+func newSearchOwners() *searchOwners {
+	return &searchOwners{owners: make(map[string]*session)}
+}
+
+// record notes that sess issued searchID.
+func (o *searchOwners) record(searchID string, sess *session) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if _, ok := o.owners[searchID]; ok {
+		return
+	}
+	o.owners[searchID] = sess
+	o.order = append(o.order, searchID)
+	if len(o.order) > maxTrackedSearches {
+		delete(o.owners, o.order[0])
+		o.order = o.order[1:]
+	}
+}
+
+// belongsElsewhere reports whether searchID was issued by a session other
+// than sess. Unknown ids (e.g. from an earlier run) are not rejected.
+func (o *searchOwners) belongsElsewhere(searchID string, sess *session) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	owner, ok := o.owners[searchID]
+	return ok && owner != sess
+}
+
+// forget drops every searchId a session issued.
+func (o *searchOwners) forget(sess *session) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	kept := o.order[:0]
+	for _, id := range o.order {
+		if o.owners[id] == sess {
+			delete(o.owners, id)
+			continue
+		}
+		kept = append(kept, id)
+	}
+	o.order = kept
+}
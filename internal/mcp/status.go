@@ -0,0 +1,98 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/version"
+)
+
+// execHubStatus reports the hub's state and, for each configured server,
+// whether it is running, what it reported in initialize and any failure.
+// Servers that have not been spawned yet show no serverInfo or capabilities.
+// protocolVersion is the one negotiated with the calling session.
+func (s *Server) execHubStatus(sess *session) (string, error) {
+	s.configMu.RLock()
+	names := make([]string, 0, len(s.config.Servers))
+	disabled := make(map[string]bool, len(s.config.Servers))
+	for name, server := range s.config.Servers {
+		names = append(names, name)
+		disabled[name] = server.Disabled
+	}
+	failed := make(map[string]string, len(s.failedServers))
+	for name, msg := range s.failedServers {
+		failed[name] = msg
+	}
+	s.configMu.RUnlock()
+	sort.Strings(names)
+
+	servers := make([]map[string]interface{}, 0, len(names))
+	for _, name := range names {
+		entry := map[string]interface{}{
+			"name":    name,
+			"enabled": !disabled[name],
+			"running": false,
+		}
+		if info, ok := s.spawner.ServerInfo(name); ok {
+			entry["running"] = true
+			entry["serverInfo"] = map[string]interface{}{"name": info.Name, "version": info.Version}
+			if info.ProtocolVersion != "" {
+				entry["protocolVersion"] = info.ProtocolVersion
+			}
+		}
+		if caps, ok := s.spawner.Capabilities(name); ok {
+			entry["capabilities"] = caps
+			if caps.Resources || caps.Prompts {
+				entry["hint"] = "Only tools are available through the hub; this server's resources and prompts are not forwarded."
+			}
+		}
+		if msg, ok := failed[name]; ok {
+			entry["error"] = msg
+		}
+		if until, open := s.circuits.openUntil(name); open {
+			entry["circuitOpenUntil"] = until.Format(time.RFC3339)
+		}
+		servers = append(servers, entry)
+	}
+
+	response := map[string]interface{}{
+		"version":         version.Version,
+		"protocolVersion": s.orStdio(sess).protocol(),
+		"servers":         servers,
+		"pool":            s.poolStatus(),
+	}
+	data, err := json.Marshal(response)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal response: %w", err)
+	}
+	return s.budgetResponse(string(data)), nil
+}
+
+// poolStatus describes the process pool: its size (0 when idle servers are
+// never stopped) and each running process, least recently used first,
+// with its age, idle time and resident memory where the OS reports it.
+func (s *Server) poolStatus() map[string]interface{} {
+	now := time.Now()
+	stats := s.spawner.Stats()
+	processes := make([]map[string]interface{}, 0, len(stats))
+	for _, stat := range stats {
+		process := map[string]interface{}{
+			"server":         stat.Server,
+			"pid":            stat.PID,
+			"ageSeconds":     int(now.Sub(stat.Started).Seconds()),
+			"idleSeconds":    int(now.Sub(stat.LastUsed).Seconds()),
+			"activeRequests": stat.ActiveRequests,
+		}
+		if stat.MemoryBytes > 0 {
+			process["memoryBytes"] = stat.MemoryBytes
+		}
+		processes = append(processes, process)
+	}
+	return map[string]interface{}{
+		"size":      s.spawner.MaxSize(),
+		"running":   len(processes),
+		"processes": processes,
+	}
+}
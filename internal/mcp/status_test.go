@@ -0,0 +1,71 @@
+package mcp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/mcptest"
+)
+
+func TestHubStatusReportsCapabilities(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg := config.NewConfig()
+	cfg.Servers["docs"] = mcptest.Server(t, mcptest.Handlers{
+		"initialize": mcptest.Result(`{"protocolVersion":"2025-03-26","capabilities":{"tools":{"listChanged":true},"resources":{}},"serverInfo":{"name":"docs-mcp","version":"2.0.0"}}`),
+		"tools/list": mcptest.Result(`{"tools":[{"name":"read_doc"}]}`),
+	})
+	cfg.Servers["broken"] = &config.ServerConfig{Command: "/nonexistent/server"}
+	server := NewServer(cfg)
+	defer server.Close()
+	server.IndexTools()
+
+	resp, err := server.handleToolsCall(&MCPRequest{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  "tools/call",
+		Params:  json.RawMessage(`{"name":"hub_status","arguments":{}}`),
+	})
+	if err != nil || resp.Error != nil {
+		t.Fatalf("hub_status failed: %v %+v", err, resp)
+	}
+	text := resp.Result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+
+	var status struct {
+		Servers []struct {
+			Name            string `json:"name"`
+			Running         bool   `json:"running"`
+			ProtocolVersion string `json:"protocolVersion"`
+			Capabilities    *struct {
+				Tools            bool `json:"tools"`
+				ToolsListChanged bool `json:"toolsListChanged"`
+				Resources        bool `json:"resources"`
+			} `json:"capabilities"`
+			Hint  string `json:"hint"`
+			Error string `json:"error"`
+		} `json:"servers"`
+		Pool struct {
+			Size      int                      `json:"size"`
+			Running   int                      `json:"running"`
+			Processes []map[string]interface{} `json:"processes"`
+		} `json:"pool"`
+	}
+	if err := json.Unmarshal([]byte(text), &status); err != nil {
+		t.Fatalf("invalid status JSON: %v\n%s", err, text)
+	}
+	if len(status.Servers) != 2 {
+		t.Fatalf("expected 2 servers, got %s", text)
+	}
+	if status.Pool.Size != config.DefaultProcessPoolSize || status.Pool.Running != 1 || status.Pool.Processes[0]["server"] != "docs" {
+		t.Errorf("unexpected pool section: %+v", status.Pool)
+	}
+	broken, docs := status.Servers[0], status.Servers[1]
+	if broken.Running || broken.Error == "" {
+		t.Errorf("expected broken server to report its error: %+v", broken)
+	}
+	if !docs.Running || docs.ProtocolVersion != "2025-03-26" || docs.Capabilities == nil ||
+		!docs.Capabilities.ToolsListChanged || !docs.Capabilities.Resources || docs.Hint == "" {
+		t.Errorf("unexpected docs status: %s", text)
+	}
+}
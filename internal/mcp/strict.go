@@ -0,0 +1,58 @@
+package mcp
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+)
+
+// VerifyServers validates, spawns and initializes every enabled server
+// up front and indexes their tools. It returns an error naming each server
+// that failed, for strict mode where partial availability is not acceptable.
+func (s *Server) VerifyServers() error {
+	failures := make(map[string]string)
+
+	s.configMu.RLock()
+	total := 0
+	for name, cfg := range s.config.Servers {
+		if cfg.Disabled {
+			continue
+		}
+		total++
+		if err := config.ValidateServer(name, cfg); err != nil {
+			failures[name] = err.Error()
+		}
+	}
+	s.configMu.RUnlock()
+
+	if len(failures) == 0 {
+		if err := s.IndexTools(); err != nil {
+			return fmt.Errorf("failed to index tools: %w", err)
+		}
+
+		s.configMu.RLock()
+		for name, msg := range s.failedServers {
+			failures[name] = msg
+		}
+		s.configMu.RUnlock()
+	}
+
+	if len(failures) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(failures))
+	for name := range failures {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d of %d servers failed:", len(failures), total)
+	for _, name := range names {
+		fmt.Fprintf(&b, "\n  %s: %s", name, failures[name])
+	}
+	return fmt.Errorf("%s", b.String())
+}
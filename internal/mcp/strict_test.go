@@ -0,0 +1,58 @@
+package mcp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+)
+
+func TestVerifyServers(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	script := writeFakeServer(t, "1.0.0", `[{"name":"search","description":"Search issues"}]`)
+	cfg := config.NewConfig()
+	cfg.Servers["jira"] = &config.ServerConfig{Command: "sh", Args: []string{script}}
+
+	server := NewServer(cfg)
+	defer server.Close()
+
+	if err := server.VerifyServers(); err != nil {
+		t.Fatalf("healthy servers should verify: %v", err)
+	}
+}
+
+func TestVerifyServersReportsFailures(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	script := writeFakeServer(t, "1.0.0", `[{"name":"search","description":"Search issues"}]`)
+	cfg := config.NewConfig()
+	cfg.Servers["jira"] = &config.ServerConfig{Command: "sh", Args: []string{script}}
+	cfg.Servers["broken"] = &config.ServerConfig{Command: ""}
+
+	server := NewServer(cfg)
+	defer server.Close()
+
+	err := server.VerifyServers()
+	if err == nil {
+		t.Fatal("expected strict verification to fail")
+	}
+	if !strings.Contains(err.Error(), "1 of 2 servers failed") || !strings.Contains(err.Error(), "broken: server 'broken': empty command") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestVerifyServersReportsSpawnFailures(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg := config.NewConfig()
+	cfg.Servers["missing"] = &config.ServerConfig{Command: "/nonexistent/tool-hub-test-server"}
+
+	server := NewServer(cfg)
+	defer server.Close()
+
+	err := server.VerifyServers()
+	if err == nil || !strings.Contains(err.Error(), "missing:") {
+		t.Errorf("expected spawn failure for 'missing', got %v", err)
+	}
+}
@@ -0,0 +1,43 @@
+package mcp
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+)
+
+func TestHandleRequestExportsSpans(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	var mu sync.Mutex
+	var traces []string
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.URL.Path == "/v1/traces" {
+			mu.Lock()
+			traces = append(traces, string(body))
+			mu.Unlock()
+		}
+	}))
+	defer collector.Close()
+
+	cfg := config.NewConfig()
+	cfg.Settings = &config.Settings{Telemetry: &config.TelemetrySettings{Endpoint: collector.URL}}
+	server := NewServer(cfg)
+
+	if _, err := server.handleRequest([]byte(`{"jsonrpc":"2.0","id":1,"method":"ping"}`)); err != nil {
+		t.Fatal(err)
+	}
+	server.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(traces) != 1 || !strings.Contains(traces[0], `"name":"ping"`) || !strings.Contains(traces[0], `"rpc.method"`) {
+		t.Errorf("expected a ping span, got %v", traces)
+	}
+}
@@ -0,0 +1,59 @@
+package mcp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+)
+
+func TestSearchAndExecuteAreTraced(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	script := writeCountingServer(t, `[{"name":"create_issue","description":"Create an issue"},{"name":"search_issues","description":"Search issues"}]`)
+	cfg := config.NewConfig()
+	cfg.Settings.Audit = &config.AuditSettings{RecordQueries: true}
+	cfg.Servers["jira"] = &config.ServerConfig{Command: "sh", Args: []string{script}}
+	server := NewServer(cfg)
+	defer server.Close()
+	if err := server.IndexTools(); err != nil {
+		t.Fatalf("IndexTools failed: %v", err)
+	}
+
+	out, err := server.runHubSearch(searchRequest{Query: "create issue"})
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	var search struct {
+		SearchID string `json:"searchId"`
+	}
+	json.Unmarshal([]byte(out), &search)
+
+	resp, _ := server.handleToolsCall(&MCPRequest{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  "tools/call",
+		Params:  json.RawMessage(`{"name":"hub_execute","arguments":{"server":"jira","tool":"create_issue","searchId":"` + search.SearchID + `"}}`),
+	})
+	if resp.Error != nil {
+		t.Fatalf("hub_execute failed: %+v", resp.Error)
+	}
+
+	trace, err := server.storage.GetTrace(search.SearchID)
+	if err != nil || trace == nil {
+		t.Fatalf("GetTrace() = %+v, %v", trace, err)
+	}
+	if trace.Search.Query != "create issue" || len(trace.Search.Results) == 0 || trace.Search.Results[0] != "jira/create_issue" {
+		t.Errorf("unexpected search record: %+v", trace.Search)
+	}
+	if len(trace.Executions) != 1 {
+		t.Fatalf("expected 1 execution, got %+v", trace.Executions)
+	}
+	if e := trace.Executions[0]; e.Tool != "create_issue" || !e.Success || e.ExecutionID == "" {
+		t.Errorf("unexpected execution: %+v", e)
+	}
+
+	if missing, _ := server.storage.GetTrace("unknown"); missing != nil {
+		t.Errorf("expected no trace for an unknown search, got %+v", missing)
+	}
+}
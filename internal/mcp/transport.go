@@ -0,0 +1,225 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+	"sync/atomic"
+)
+
+// maxInFlight bounds requests handled concurrently; further messages wait
+// to be read until a handler finishes.
+const maxInFlight = 64
+
+// errMessageTooLarge is returned for client messages over the size limit.
+var errMessageTooLarge = errors.New("message too large")
+
+// pendingRequest is a message read from the client awaiting handling.
+// Request IDs are registered on read, so a notifications/cancelled that
+// follows is always matched.
+type pendingRequest struct {
+	session   *session
+	data      []byte
+	key       string       // canonical ID; "" for notifications
+	cancelled *atomic.Bool // nil for notifications
+}
+
+// serve reads newline-delimited JSON-RPC messages from r. Each request is
+// handled on its own goroutine so a slow hub_execute does not block other
+// requests; responses carry their request ID and are written whole as they
+// complete. Notifications and initialize are handled inline, in order.
+// Batch arrays are answered with one array.
+func (s *Server) serve(r io.Reader) error {
+	reader := bufio.NewReaderSize(r, 64*1024)
+	slots := make(chan struct{}, maxInFlight)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	async := func(fn func()) {
+		slots <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			fn()
+		}()
+	}
+
+	for {
+		s.configMu.RLock()
+		limit := s.config.Settings.MessageLimit()
+		s.configMu.RUnlock()
+
+		data, err := readMessage(reader, limit)
+		if errors.Is(err, errMessageTooLarge) {
+			s.sendResponse(&MCPResponse{
+				JSONRPC: "2.0",
+				Error:   &MCPError{Code: -32600, Message: fmt.Sprintf("message exceeds %d bytes (settings.maxMessageBytes)", limit)},
+			})
+			continue
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		data = bytes.TrimSpace(data)
+		if len(data) == 0 {
+			continue
+		}
+
+		if data[0] == '[' {
+			var batch []json.RawMessage
+			if err := json.Unmarshal(data, &batch); err != nil {
+				s.sendError(fmt.Errorf("invalid JSON-RPC batch: %w", err))
+				continue
+			}
+			if len(batch) == 0 {
+				s.sendResponse(&MCPResponse{JSONRPC: "2.0", Error: &MCPError{Code: -32600, Message: "Invalid Request: empty batch"}})
+				continue
+			}
+			reqs := make([]pendingRequest, len(batch))
+			for i, msg := range batch {
+				reqs[i] = begin(s.stdio, msg)
+			}
+			async(func() { s.replyBatch(reqs) })
+			continue
+		}
+
+		req := begin(s.stdio, data)
+		if req.key == "" || isInitialize(data) {
+			s.reply(req)
+			continue
+		}
+		async(func() { s.reply(req) })
+	}
+}
+
+// begin registers a message's request ID (if any) with its session for
+// cancellation.
+func begin(sess *session, data []byte) pendingRequest {
+	req := pendingRequest{session: sess, data: data, key: requestKey(data)}
+	if req.key != "" {
+		req.cancelled = sess.trackRequest(req.key)
+	}
+	return req
+}
+
+// reply handles one message and writes its response, if any.
+func (s *Server) reply(req pendingRequest) {
+	if resp := s.respond(req); resp != nil {
+		s.sendResponse(resp)
+	}
+}
+
+// replyBatch handles a batch concurrently and writes the responses as one
+// array in batch order. A batch of only notifications gets no reply.
+func (s *Server) replyBatch(reqs []pendingRequest) {
+	responses := make([]*MCPResponse, len(reqs))
+	var wg sync.WaitGroup
+	for i, req := range reqs {
+		wg.Add(1)
+		go func(i int, req pendingRequest) {
+			defer wg.Done()
+			responses[i] = s.respond(req)
+		}(i, req)
+	}
+	wg.Wait()
+
+	replies := make([]*MCPResponse, 0, len(responses))
+	for _, resp := range responses {
+		if resp != nil {
+			replies = append(replies, resp)
+		}
+	}
+	if len(replies) == 0 {
+		return
+	}
+	out, _ := json.Marshal(replies)
+	s.writeLine(out)
+}
+
+// respond handles a single message, turning handler errors into a JSON-RPC
+// error response. Requests the client cancelled meanwhile get no response.
+func (s *Server) respond(req pendingRequest) *MCPResponse {
+	if req.key == "" {
+		return errorResponse(s.handleSessionRequest(req.session, req.data))
+	}
+	defer req.session.untrackRequest(req.key)
+
+	resp := errorResponse(s.handleSessionRequest(req.session, req.data))
+	if req.cancelled.Load() {
+		log.Printf("Dropping response to cancelled request %s", req.key)
+		return nil
+	}
+	return resp
+}
+
+// errorResponse turns a handler error into a JSON-RPC error response.
+func errorResponse(resp *MCPResponse, err error) *MCPResponse {
+	if err != nil {
+		return &MCPResponse{
+			JSONRPC: "2.0",
+			ID:      nil,
+			Error:   &MCPError{Code: -32700, Message: err.Error()},
+		}
+	}
+	return resp
+}
+
+// isInitialize reports whether a single (non-batch) message is initialize.
+func isInitialize(data []byte) bool {
+	if data[0] != '{' {
+		return false
+	}
+	var req struct {
+		Method string `json:"method"`
+	}
+	return json.Unmarshal(data, &req) == nil && req.Method == "initialize"
+}
+
+// readMessage reads one newline-terminated message of any length up to
+// limit bytes (0 = unlimited). An oversized message is consumed up to its
+// newline and reported as errMessageTooLarge, so reading can continue.
+func readMessage(r *bufio.Reader, limit int) ([]byte, error) {
+	var msg []byte
+	tooLarge := false
+
+	for {
+		chunk, err := r.ReadSlice('\n')
+		if !tooLarge {
+			msg = append(msg, chunk...)
+			if limit > 0 && len(msg) > limit+1 {
+				tooLarge = true
+				msg = nil
+			}
+		}
+
+		switch {
+		case err == bufio.ErrBufferFull:
+			continue
+		case err == io.EOF && (len(msg) > 0 || tooLarge):
+			// Final message without a trailing newline
+			if tooLarge {
+				return nil, errMessageTooLarge
+			}
+			return msg, nil
+		case err != nil:
+			return nil, err
+		}
+
+		if tooLarge {
+			return nil, errMessageTooLarge
+		}
+		return msg, nil
+	}
+}
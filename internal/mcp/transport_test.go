@@ -0,0 +1,113 @@
+package mcp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/mcptest"
+)
+
+func TestReadMessage(t *testing.T) {
+	long := strings.Repeat("x", 200*1024)
+	input := long + "\n" + strings.Repeat("y", 2000) + "\nshort\nlast"
+	r := bufio.NewReaderSize(strings.NewReader(input), 16)
+
+	msg, err := readMessage(r, 300*1024)
+	if err != nil || len(msg) != len(long)+1 {
+		t.Fatalf("expected %d byte message, got %d, %v", len(long)+1, len(msg), err)
+	}
+
+	if _, err := readMessage(r, 1000); !errors.Is(err, errMessageTooLarge) {
+		t.Fatalf("expected errMessageTooLarge, got %v", err)
+	}
+
+	msg, err = readMessage(r, 1000)
+	if err != nil || string(msg) != "short\n" {
+		t.Fatalf("reading should resume after an oversized message, got %q, %v", msg, err)
+	}
+
+	msg, err = readMessage(r, 0)
+	if err != nil || string(msg) != "last" {
+		t.Fatalf("expected final message without newline, got %q, %v", msg, err)
+	}
+}
+
+func TestServeBatch(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	server := NewServer(config.NewConfig())
+	defer server.Close()
+	out := &syncBuffer{}
+	server.out = out
+
+	input := `[{"jsonrpc":"2.0","id":1,"method":"tools/list"},{"jsonrpc":"2.0","id":2,"method":"unknown/method"}]` + "\n"
+	if err := server.serve(strings.NewReader(input)); err != nil {
+		t.Fatal(err)
+	}
+
+	var replies []MCPResponse
+	if err := json.Unmarshal([]byte(out.String()), &replies); err != nil {
+		t.Fatalf("expected one batch reply, got %q: %v", out.String(), err)
+	}
+	if len(replies) != 2 || replies[0].ID != 1.0 || replies[0].Error != nil || replies[1].ID != 2.0 || replies[1].Error == nil {
+		t.Errorf("unexpected batch replies: %+v", replies)
+	}
+}
+
+func TestServeHandlesRequestsConcurrently(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg := config.NewConfig()
+	cfg.Servers["slow"] = mcptest.Server(t, mcptest.Handlers{
+		"initialize": mcptest.Result(`{}`),
+		"tools/call": "sleep 0.5; " + mcptest.Result(`{"content":[]}`),
+	})
+	server := NewServer(cfg)
+	defer server.Close()
+	out := &syncBuffer{}
+	server.out = out
+
+	input := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"hub_execute","arguments":{"server":"slow","tool":"wait"}}}
+{"jsonrpc":"2.0","id":2,"method":"tools/list"}
+`
+	if err := server.serve(strings.NewReader(input)); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 responses, got %q", out.String())
+	}
+	var first MCPResponse
+	json.Unmarshal([]byte(lines[0]), &first)
+	if first.ID != 2.0 {
+		t.Errorf("tools/list should not wait behind the slow call, first response was %s", lines[0])
+	}
+}
+
+func TestServeRejectsOversizedMessage(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg := config.NewConfig()
+	cfg.Settings.MaxMessageBytes = 64
+	server := NewServer(cfg)
+	defer server.Close()
+	out := &syncBuffer{}
+	server.out = out
+
+	input := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"hub_search","arguments":{"query":"` + strings.Repeat("a", 100) + `"}}}
+{"jsonrpc":"2.0","id":2,"method":"ping"}
+`
+	if err := server.serve(strings.NewReader(input)); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "maxMessageBytes") || !strings.Contains(lines[1], `"id":2`) {
+		t.Errorf("expected a size error followed by the next response, got %q", out.String())
+	}
+}
@@ -0,0 +1,175 @@
+package mcp
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/redact"
+)
+
+// serverPatch holds the fields a hub_manage update changes. Nil fields
+// keep their current value.
+type serverPatch struct {
+	Command *string
+	Args    []string           // replaces the args when non-nil
+	Env     map[string]*string // merged into the env; a nil value removes the key
+	Enabled *bool
+}
+
+// empty reports whether the patch changes nothing.
+func (p serverPatch) empty() bool {
+	return p.Command == nil && p.Args == nil && len(p.Env) == 0 && p.Enabled == nil
+}
+
+// parseServerPatch reads the update fields of hub_manage arguments.
+func parseServerPatch(arguments map[string]interface{}) serverPatch {
+	var patch serverPatch
+	if command, ok := arguments["command"].(string); ok {
+		patch.Command = &command
+	}
+	if argsInterface, ok := arguments["args"].([]interface{}); ok {
+		patch.Args = make([]string, len(argsInterface))
+		for i, v := range argsInterface {
+			if str, ok := v.(string); ok {
+				patch.Args[i] = str
+			}
+		}
+	}
+	if envInterface, ok := arguments["env"].(map[string]interface{}); ok {
+		patch.Env = make(map[string]*string, len(envInterface))
+		for k, v := range envInterface {
+			switch value := v.(type) {
+			case string:
+				patch.Env[k] = &value
+			case nil:
+				patch.Env[k] = nil
+			}
+		}
+	}
+	if enabled, ok := arguments["enabled"].(bool); ok {
+		patch.Enabled = &enabled
+	}
+	return patch
+}
+
+// execHubUpdate handles the hub_manage update operation.
+func (s *Server) execHubUpdate(name string, patch serverPatch) (string, error) {
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+
+	if strings.TrimSpace(name) == "" {
+		return "", fmt.Errorf("server name cannot be empty")
+	}
+	return s.updateServer(strings.TrimSpace(name), patch)
+}
+
+// updateServer merges a patch into an existing server's configuration.
+// The merged config is validated and saved; on failure the previous
+// config is restored. A running process is stopped so the next call
+// spawns it with the new command, args and env.
+func (s *Server) updateServer(name string, patch serverPatch) (string, error) {
+	key, exists := s.config.ResolveServer(name)
+	if !exists {
+		return "", s.config.ServerNotFound(name)
+	}
+	if patch.empty() {
+		return "", fmt.Errorf("nothing to update: provide command, args, env or enabled")
+	}
+
+	// Backup server config for potential rollback
+	backupCfg := s.config.Servers[key]
+
+	updated := *backupCfg
+	var changes []string
+	if patch.Command != nil {
+		command := strings.TrimSpace(*patch.Command)
+		if command == "" {
+			return "", fmt.Errorf("command cannot be empty for update operation")
+		}
+		updated.Command = command
+	}
+	if patch.Args != nil {
+		updated.Args = patch.Args
+	}
+	if patch.Command != nil {
+		// Words split off the command line come before the args
+		if _, err := updated.NormalizeCommand(); err != nil {
+			return "", err
+		}
+		changes = append(changes, "Command: "+updated.Command)
+	}
+	if patch.Command != nil || patch.Args != nil {
+		changes = append(changes, fmt.Sprintf("Args: %v", redact.Args(updated.Args)))
+	}
+	if len(patch.Env) > 0 {
+		updated.Env = make(map[string]string, len(backupCfg.Env)+len(patch.Env))
+		for k, v := range backupCfg.Env {
+			updated.Env[k] = v
+		}
+		var set, removed []string
+		for k, v := range patch.Env {
+			if v == nil {
+				delete(updated.Env, k)
+				removed = append(removed, k)
+			} else {
+				updated.Env[k] = *v
+				set = append(set, k)
+			}
+		}
+		sort.Strings(set)
+		sort.Strings(removed)
+		if len(set) > 0 {
+			changes = append(changes, "Env set: "+strings.Join(set, ", "))
+		}
+		if len(removed) > 0 {
+			changes = append(changes, "Env removed: "+strings.Join(removed, ", "))
+		}
+	}
+	if patch.Enabled != nil {
+		updated.Disabled = !*patch.Enabled
+		changes = append(changes, fmt.Sprintf("Enabled: %v", *patch.Enabled))
+	}
+
+	if err := config.ValidateServer(key, &updated); err != nil {
+		return "", fmt.Errorf("invalid update: %w", err)
+	}
+
+	s.config.Servers[key] = &updated
+	redact.Secrets.AddEnv(updated.Env)
+
+	// Save config atomically
+	configPath, err := config.GetDefaultConfigPath()
+	if err != nil {
+		// Rollback
+		s.config.Servers[key] = backupCfg
+		return "", fmt.Errorf("failed to get config path: %w", err)
+	}
+
+	if err := config.Save(s.config, configPath); err != nil {
+		// Rollback
+		s.config.Servers[key] = backupCfg
+		return "", fmt.Errorf("failed to save config: %w. Config rolled back", err)
+	}
+
+	s.spawner.Stop(key)
+	s.forgetIndexed(key)
+	s.cache.invalidate(key)
+	s.circuits.reset(key)
+	delete(s.failedServers, key)
+
+	if s.indexer != nil {
+		if err := s.indexer.RemoveServer(key); err != nil {
+			log.Printf("Warning: failed to remove server '%s' from index: %v", key, err)
+		}
+	}
+	s.notifyToolsListChanged()
+
+	// Reindex only the updated server (must hold lock)
+	s.indexServersUnsafe(key)
+
+	return fmt.Sprintf("✓ Server '%s' updated successfully.\n\n%s\n\nConfig saved to: %s\nIndexing triggered.",
+		key, strings.Join(changes, "\n"), configPath), nil
+}
@@ -0,0 +1,110 @@
+package mcp
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+)
+
+func TestHubManageUpdate(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg := config.NewConfig()
+	cfg.Servers["jira"] = &config.ServerConfig{
+		Command: "/nonexistent/jira",
+		Args:    []string{"--verbose"},
+		Env:     map[string]string{"API_KEY": "old-key-123", "REGION": "us"},
+		Aliases: []string{"tickets"},
+	}
+	server := NewServer(cfg)
+	defer server.Close()
+
+	resp, _ := server.handleToolsCall(&MCPRequest{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  "tools/call",
+		Params:  json.RawMessage(`{"name":"hub_manage","arguments":{"operation":"update","name":"tickets","env":{"API_KEY":"new-key-456","REGION":null},"enabled":false}}`),
+	})
+	if resp.Error != nil {
+		t.Fatalf("update failed: %v", resp.Error.Message)
+	}
+	text := resp.Result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	if strings.Contains(text, "new-key-456") {
+		t.Errorf("confirmation should not echo env values: %s", text)
+	}
+
+	updated := server.config.Servers["jira"]
+	if updated.Env["API_KEY"] != "new-key-456" {
+		t.Errorf("API_KEY = %q, want new-key-456", updated.Env["API_KEY"])
+	}
+	if _, ok := updated.Env["REGION"]; ok {
+		t.Error("REGION should be removed by a null value")
+	}
+	if !updated.Disabled {
+		t.Error("server should be disabled")
+	}
+	if updated.Command != "/nonexistent/jira" || len(updated.Args) != 1 || len(updated.Aliases) != 1 {
+		t.Errorf("omitted fields should be kept, got %+v", updated)
+	}
+
+	saved, err := config.Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if saved.Servers["jira"].Env["API_KEY"] != "new-key-456" {
+		t.Error("update was not saved")
+	}
+}
+
+func TestHubManageUpdateErrors(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg := config.NewConfig()
+	cfg.Servers["jira"] = &config.ServerConfig{Command: "/nonexistent/jira"}
+	server := NewServer(cfg)
+	defer server.Close()
+
+	if _, err := server.execHubUpdate("missing", parseServerPatch(map[string]interface{}{"command": "x"})); err == nil {
+		t.Error("expected error updating unknown server")
+	}
+	if _, err := server.execHubUpdate("jira", parseServerPatch(map[string]interface{}{})); err == nil {
+		t.Error("expected error for an empty update")
+	}
+
+	// An invalid merge is rejected and leaves the config untouched
+	if _, err := server.execHubUpdate("jira", parseServerPatch(map[string]interface{}{"command": "  "})); err == nil {
+		t.Error("expected error for an empty command")
+	}
+	if server.config.Servers["jira"].Command != "/nonexistent/jira" {
+		t.Error("config should be unchanged after a failed update")
+	}
+}
+
+func TestHubManageSplitsCommand(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg := config.NewConfig()
+	cfg.Servers["jira"] = &config.ServerConfig{Command: "/nonexistent/jira", Args: []string{"--verbose"}}
+	server := NewServer(cfg)
+	defer server.Close()
+
+	for _, params := range []string{
+		`{"name":"hub_manage","arguments":{"operation":"add","name":"fetch","command":"/nonexistent/uvx mcp-server-fetch"}}`,
+		`{"name":"hub_manage","arguments":{"operation":"update","name":"jira","command":"/nonexistent/npx -y @lvmk/jira-mcp"}}`,
+	} {
+		resp, _ := server.handleToolsCall(&MCPRequest{JSONRPC: "2.0", ID: 1, Method: "tools/call", Params: json.RawMessage(params)})
+		if resp.Error != nil {
+			t.Fatalf("hub_manage failed: %v", resp.Error.Message)
+		}
+	}
+
+	if fetch := server.config.Servers["fetch"]; fetch.Command != "/nonexistent/uvx" || len(fetch.Args) != 1 || fetch.Args[0] != "mcp-server-fetch" {
+		t.Errorf("add: got %q %q", fetch.Command, fetch.Args)
+	}
+	jira := server.config.Servers["jira"]
+	if jira.Command != "/nonexistent/npx" || strings.Join(jira.Args, " ") != "-y @lvmk/jira-mcp --verbose" {
+		t.Errorf("update: got %q %q", jira.Command, jira.Args)
+	}
+}
@@ -0,0 +1,54 @@
+package mcp
+
+import (
+	"sort"
+	"time"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/redact"
+	"github.com/khanglvm/tool-hub-mcp/internal/webhook"
+)
+
+// webhookShutdownTimeout bounds delivering queued events on Close.
+const webhookShutdownTimeout = 5 * time.Second
+
+// announceExecution sends the succeeded or failed event of a hub_execute call.
+func (s *Server) announceExecution(serverName, toolName, executionID string, started time.Time, err error) {
+	event := webhook.Event{
+		Type:        webhook.ExecutionSucceeded,
+		Server:      serverName,
+		Tool:        toolName,
+		ExecutionID: executionID,
+		DurationMs:  time.Since(started).Milliseconds(),
+	}
+	if err != nil {
+		event.Type = webhook.ExecutionFailed
+		event.Error = redact.String(err.Error())
+	}
+	s.webhooks.Send(event)
+}
+
+// announceServerChanges sends server.added and server.removed events for
+// the difference between two server sets, in name order.
+func (s *Server) announceServerChanges(before, after map[string]*config.ServerConfig) {
+	var added, removed []string
+	for name := range after {
+		if _, ok := before[name]; !ok {
+			added = append(added, name)
+		}
+	}
+	for name := range before {
+		if _, ok := after[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+
+	for _, name := range added {
+		s.webhooks.Send(webhook.Event{Type: webhook.ServerAdded, Server: name})
+	}
+	for _, name := range removed {
+		s.webhooks.Send(webhook.Event{Type: webhook.ServerRemoved, Server: name})
+	}
+}
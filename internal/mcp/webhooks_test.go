@@ -0,0 +1,94 @@
+package mcp
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/webhook"
+)
+
+// webhookRecorder collects the events POSTed to it.
+type webhookRecorder struct {
+	mu     sync.Mutex
+	events []webhook.Event
+}
+
+func (r *webhookRecorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	var event webhook.Event
+	json.Unmarshal(body, &event)
+	r.mu.Lock()
+	r.events = append(r.events, event)
+	r.mu.Unlock()
+}
+
+func (r *webhookRecorder) types() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var types []string
+	for _, e := range r.events {
+		types = append(types, e.Type+":"+e.Server)
+	}
+	return strings.Join(types, ",")
+}
+
+func TestWebhooksOnExecution(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	recorder := &webhookRecorder{}
+	endpoint := httptest.NewServer(recorder)
+	defer endpoint.Close()
+
+	script := writeCountingServer(t, `[{"name":"get_issue"}]`)
+	cfg := config.NewConfig()
+	cfg.Servers["jira"] = &config.ServerConfig{Command: "sh", Args: []string{script}}
+	cfg.Settings = &config.Settings{Webhooks: []config.WebhookConfig{{URL: endpoint.URL, Events: []string{"execution.*"}}}}
+	server := NewServer(cfg)
+
+	callText(t, server, "hub_execute", map[string]interface{}{"server": "jira", "tool": "get_issue"})
+	callText(t, server, "hub_execute", map[string]interface{}{"server": "missing", "tool": "get_issue"})
+	server.Close()
+
+	if got := recorder.types(); got != "execution.started:jira,execution.succeeded:jira,execution.started:missing,execution.failed:missing" {
+		t.Errorf("unexpected events: %s", got)
+	}
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	if id := recorder.events[0].ExecutionID; id == "" || id != recorder.events[1].ExecutionID {
+		t.Errorf("started and succeeded events should share an execution id: %+v", recorder.events[:2])
+	}
+	if recorder.events[3].Error == "" {
+		t.Errorf("failed event should carry the error: %+v", recorder.events[3])
+	}
+}
+
+func TestWebhooksOnServerChanges(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	recorder := &webhookRecorder{}
+	endpoint := httptest.NewServer(recorder)
+	defer endpoint.Close()
+
+	settings := &config.Settings{Webhooks: []config.WebhookConfig{{URL: endpoint.URL, Events: []string{"server.*"}}}}
+	cfg := config.NewConfig()
+	cfg.Servers["old"] = &config.ServerConfig{Command: "/nonexistent/old"}
+	cfg.Settings = settings
+	server := NewServer(cfg)
+
+	reloaded := config.NewConfig()
+	reloaded.Servers["new"] = &config.ServerConfig{Command: "/nonexistent/new"}
+	reloaded.Settings = settings
+	server.ReloadConfig(reloaded)
+	server.Close()
+
+	if got := recorder.types(); got != "server.added:new,server.removed:old" {
+		t.Errorf("unexpected events: %s", got)
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Package mcptest writes fake stdio MCP servers for tests.
+
+A fake server is a small sh script that reads newline-delimited JSON-RPC
+requests and answers each by method:
+
+	cfg := mcptest.Server(t, mcptest.Handlers{
+		"initialize": mcptest.Result(`{}`),
+		"tools/list": mcptest.Result(`{"tools":[{"name":"search"}]}`),
+	})
+
+Handlers are shell commands, so a test can add behavior around the
+response, e.g. "sleep 0.3; " + mcptest.Result(`{}`). In them, $line is the
+request and $id its ID. Requests for other methods get no response.
+*/
+package mcptest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+)
+
+// Handlers maps JSON-RPC methods to the shell commands answering them.
+type Handlers map[string]string
+
+// Script writes a fake server answering requests with handlers and
+// returns its path.
+func Script(t testing.TB, handlers Handlers) string {
+	t.Helper()
+
+	methods := make([]string, 0, len(handlers))
+	for method := range handlers {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+
+	var b strings.Builder
+	b.WriteString("while read line; do\n")
+	b.WriteString(`  id=$(echo "$line" | sed 's/^{"id":\([0-9]*\).*/\1/')` + "\n")
+	b.WriteString("  case \"$line\" in\n")
+	for _, method := range methods {
+		fmt.Fprintf(&b, "    *'\"method\":\"%s\"'*)\n      %s ;;\n", method, handlers[method])
+	}
+	b.WriteString("  esac\ndone\n")
+
+	script := filepath.Join(t.TempDir(), "server.sh")
+	if err := os.WriteFile(script, []byte(b.String()), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return script
+}
+
+// Server writes a fake server with Script and returns a config running it.
+func Server(t testing.TB, handlers Handlers) *config.ServerConfig {
+	t.Helper()
+	return &config.ServerConfig{Command: "sh", Args: []string{Script(t, handlers)}}
+}
+
+// Result answers the request with a JSON result.
+func Result(result string) string {
+	return `echo '{"jsonrpc":"2.0","id":'$id',"result":` + result + `}'`
+}
+
+// Error answers the request with a JSON-RPC error.
+func Error(code int, message string) string {
+	return fmt.Sprintf(`echo '{"jsonrpc":"2.0","id":'$id',"error":{"code":%d,"message":%q}}'`, code, message)
+}
+
+// Notify sends a notification with JSON params ahead of the response.
+func Notify(method, params string) string {
+	if params == "" {
+		return fmt.Sprintf(`echo '{"jsonrpc":"2.0","method":%q}'`, method)
+	}
+	return fmt.Sprintf(`echo '{"jsonrpc":"2.0","method":%q,"params":%s}'`, method, params)
+}
+
+// CrashOnce exits the server the first time it runs, leaving a marker in
+// dir so the next server process carries on with the commands after it.
+func CrashOnce(dir string) string {
+	marker := filepath.Join(dir, "crashed")
+	return `if [ ! -f ` + marker + ` ]; then touch ` + marker + `; exit 1; fi`
+}
@@ -0,0 +1,51 @@
+package mcptest
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestServerAnswersByMethod(t *testing.T) {
+	cfg := Server(t, Handlers{
+		"initialize": Result(`{"serverInfo":{"name":"fake"}}`),
+		"tools/call": Notify("notifications/progress", `{"progress":1}`) + "; " + Error(-32602, "bad args"),
+	})
+
+	cmd := exec.Command(cfg.Command, cfg.Args...)
+	cmd.Stdin = strings.NewReader(`{"id":1,"jsonrpc":"2.0","method":"initialize"}
+{"id":2,"jsonrpc":"2.0","method":"ping"}
+{"id":3,"jsonrpc":"2.0","method":"tools/call"}
+`)
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("fake server failed: %v", err)
+	}
+
+	want := `{"jsonrpc":"2.0","id":1,"result":{"serverInfo":{"name":"fake"}}}
+{"jsonrpc":"2.0","method":"notifications/progress","params":{"progress":1}}
+{"jsonrpc":"2.0","id":3,"error":{"code":-32602,"message":"bad args"}}
+`
+	if string(out) != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", out, want)
+	}
+}
+
+func TestCrashOnce(t *testing.T) {
+	dir := t.TempDir()
+	script := Script(t, Handlers{"tools/call": CrashOnce(dir) + "; " + Result(`{}`)})
+
+	run := func() (string, error) {
+		cmd := exec.Command("sh", script)
+		cmd.Stdin = strings.NewReader(`{"id":7,"jsonrpc":"2.0","method":"tools/call"}` + "\n")
+		out, err := cmd.Output()
+		return string(out), err
+	}
+
+	if out, err := run(); err == nil || out != "" {
+		t.Errorf("first run should exit without answering, got %q, %v", out, err)
+	}
+	if out, err := run(); err != nil || out != `{"jsonrpc":"2.0","id":7,"result":{}}`+"\n" {
+		t.Errorf("second run should answer, got %q, %v", out, err)
+	}
+}
@@ -0,0 +1,111 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWriteText(t *testing.T) {
+	r := NewRegistry()
+	calls := r.Counter("calls_total", "Calls.", "server", "status")
+	latency := r.Histogram("latency_seconds", "Latency.", []float64{1, 0.1}, "server")
+	r.GaugeFunc("queue_depth", "Queue depth.", func() float64 { return 3 })
+
+	calls.Inc("a", "success")
+	calls.Add(2, "a", "success")
+	calls.Inc(`b"x`, "error")
+	latency.Observe(0.05, "a")
+	latency.Observe(0.5, "a")
+	latency.Observe(5, "a")
+
+	var buf bytes.Buffer
+	if err := r.WriteText(&buf); err != nil {
+		t.Fatalf("WriteText failed: %v", err)
+	}
+
+	want := `# HELP calls_total Calls.
+# TYPE calls_total counter
+calls_total{server="a",status="success"} 3
+calls_total{server="b\"x",status="error"} 1
+# HELP latency_seconds Latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{server="a",le="0.1"} 1
+latency_seconds_bucket{server="a",le="1"} 2
+latency_seconds_bucket{server="a",le="+Inf"} 3
+latency_seconds_sum{server="a"} 5.55
+latency_seconds_count{server="a"} 3
+# HELP queue_depth Queue depth.
+# TYPE queue_depth gauge
+queue_depth 3
+`
+	if got := buf.String(); got != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestUnlabeledCounterStartsAtZero(t *testing.T) {
+	r := NewRegistry()
+	r.Counter("events_total", "Events.")
+
+	var buf bytes.Buffer
+	r.WriteText(&buf)
+	if !strings.Contains(buf.String(), "events_total 0\n") {
+		t.Errorf("expected zero sample, got:\n%s", buf.String())
+	}
+}
+
+func TestHandler(t *testing.T) {
+	r := NewRegistry()
+	r.Counter("events_total", "Events.").Inc()
+
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("unexpected content type %q", ct)
+	}
+	if !strings.Contains(rec.Body.String(), "events_total 1") {
+		t.Errorf("unexpected body:\n%s", rec.Body.String())
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	r := NewRegistry()
+	calls := r.Counter("calls_total", "Calls.", "server")
+	latency := r.Histogram("latency_seconds", "Latency.", []float64{1, 0.1}, "server")
+	r.GaugeFunc("queue_depth", "Queue depth.", func() float64 { return 3 })
+
+	calls.Add(2, "a")
+	latency.Observe(0.05, "a")
+	latency.Observe(0.5, "a")
+	latency.Observe(5, "a")
+
+	families := r.Snapshot()
+	if len(families) != 3 {
+		t.Fatalf("expected 3 families, got %d", len(families))
+	}
+
+	counter := families[0]
+	if counter.Kind != KindCounter || len(counter.Points) != 1 || counter.Points[0].Value != 2 || counter.Points[0].Labels["server"] != "a" {
+		t.Errorf("unexpected counter snapshot: %+v", counter)
+	}
+
+	histogram := families[1]
+	if histogram.Kind != KindHistogram || len(histogram.Bounds) != 2 || histogram.Bounds[0] != 0.1 {
+		t.Fatalf("unexpected histogram snapshot: %+v", histogram)
+	}
+	p := histogram.Points[0]
+	if p.Count != 3 || p.Sum != 5.55 {
+		t.Errorf("histogram count/sum = %d/%v, want 3/5.55", p.Count, p.Sum)
+	}
+	if len(p.BucketCounts) != 3 || p.BucketCounts[0] != 1 || p.BucketCounts[1] != 1 || p.BucketCounts[2] != 1 {
+		t.Errorf("bucket counts = %v, want [1 1 1]", p.BucketCounts)
+	}
+
+	gauge := families[2]
+	if gauge.Kind != KindGauge || gauge.Points[0].Value != 3 {
+		t.Errorf("unexpected gauge snapshot: %+v", gauge)
+	}
+}
@@ -0,0 +1,99 @@
+package metrics
+
+import "strings"
+
+// Metric kinds reported in a Family.
+const (
+	KindCounter   = "counter"
+	KindHistogram = "histogram"
+	KindGauge     = "gauge"
+)
+
+// Family is a point-in-time copy of one metric, for exporters other than
+// the Prometheus text format (e.g. OTLP).
+type Family struct {
+	Name   string
+	Help   string
+	Kind   string
+	Bounds []float64 // histogram upper bounds, ascending
+	Points []Point
+}
+
+// Point is one label set's value. Counters and gauges use Value;
+// histograms use BucketCounts (non-cumulative, one per bound plus +Inf),
+// Count and Sum.
+type Point struct {
+	Labels       map[string]string
+	Value        float64
+	BucketCounts []uint64
+	Count        uint64
+	Sum          float64
+}
+
+// snapshotter is implemented by families that can copy themselves.
+type snapshotter interface {
+	snapshot() Family
+}
+
+// Snapshot copies every registered metric, in registration order.
+func (r *Registry) Snapshot() []Family {
+	r.mu.Lock()
+	families := append([]family(nil), r.families...)
+	r.mu.Unlock()
+
+	snapshots := make([]Family, 0, len(families))
+	for _, f := range families {
+		if s, ok := f.(snapshotter); ok {
+			snapshots = append(snapshots, s.snapshot())
+		}
+	}
+	return snapshots
+}
+
+func (c *CounterVec) snapshot() Family {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	f := Family{Name: c.name, Help: c.help, Kind: KindCounter}
+	for _, key := range sortedKeys(c.values) {
+		f.Points = append(f.Points, Point{Labels: labelMap(c.labels, key), Value: c.values[key]})
+	}
+	return f
+}
+
+func (h *HistogramVec) snapshot() Family {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	f := Family{Name: h.name, Help: h.help, Kind: KindHistogram, Bounds: append([]float64(nil), h.buckets...)}
+	for _, key := range sortedKeys(h.series) {
+		s := h.series[key]
+		counts := append([]uint64(nil), s.counts...)
+		var inBounds uint64
+		for _, c := range s.counts {
+			inBounds += c
+		}
+		counts = append(counts, s.count-inBounds)
+		f.Points = append(f.Points, Point{Labels: labelMap(h.labels, key), BucketCounts: counts, Count: s.count, Sum: s.sum})
+	}
+	return f
+}
+
+func (g *gaugeFunc) snapshot() Family {
+	return Family{Name: g.name, Help: g.help, Kind: KindGauge, Points: []Point{{Value: g.fn()}}}
+}
+
+// labelMap pairs label names with the values joined in key.
+func labelMap(names []string, key string) map[string]string {
+	if len(names) == 0 {
+		return nil
+	}
+	values := strings.Split(key, labelSeparator)
+	labels := make(map[string]string, len(names))
+	for i, name := range names {
+		if i < len(values) {
+			labels[name] = values[i]
+		}
+	}
+	return labels
+}
@@ -0,0 +1,144 @@
+/*
+Package paths resolves where tool-hub-mcp keeps its files.
+
+The layout follows the XDG base directory spec:
+
+	$XDG_CONFIG_HOME/tool-hub-mcp (~/.config/tool-hub-mcp)      config.json
+	$XDG_DATA_HOME/tool-hub-mcp   (~/.local/share/tool-hub-mcp) history.db, index.jsonl, search-index/, update-cache.json
+
+Setting TOOL_HUB_HOME keeps every file in that one directory instead.
+Files left in the legacy dotfile layout (~/.tool-hub-mcp.json,
+~/.tool-hub-mcp/history.db, ~/.tool-hub-mcp-index.jsonl,
+~/.tool-hub-mcp-cache.json) are moved to their new location the first
+time they are resolved.
+*/
+package paths
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// HomeEnvVar overrides the config and data directories with one directory.
+const HomeEnvVar = "TOOL_HUB_HOME"
+
+// appName is the directory name used under the XDG base directories.
+const appName = "tool-hub-mcp"
+
+// ConfigDir returns the directory holding config.json.
+func ConfigDir() (string, error) {
+	return baseDir("XDG_CONFIG_HOME", ".config")
+}
+
+// DataDir returns the directory holding the learning database, the
+// exported tool index and the update cache.
+func DataDir() (string, error) {
+	return baseDir("XDG_DATA_HOME", filepath.Join(".local", "share"))
+}
+
+// ConfigFile returns the path of the configuration file.
+func ConfigFile() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return migrateLegacy(".tool-hub-mcp.json", filepath.Join(dir, "config.json")), nil
+}
+
+// HistoryDB returns the path of the learning database.
+func HistoryDB() (string, error) {
+	dir, err := DataDir()
+	if err != nil {
+		return "", err
+	}
+	target := filepath.Join(dir, "history.db")
+	legacy := filepath.Join(".tool-hub-mcp", "history.db")
+
+	// Move the WAL files along with the database so no writes are lost
+	for _, suffix := range []string{"-wal", "-shm"} {
+		migrateLegacy(legacy+suffix, target+suffix)
+	}
+	path := migrateLegacy(legacy, target)
+	if path == target {
+		if home, err := os.UserHomeDir(); err == nil {
+			// Only succeeds once the legacy directory is empty
+			os.Remove(filepath.Join(home, ".tool-hub-mcp"))
+		}
+	}
+	return path, nil
+}
+
+// IndexFile returns the path of the exported tool index with the given
+// extension (".jsonl" or ".json").
+func IndexFile(ext string) (string, error) {
+	dir, err := DataDir()
+	if err != nil {
+		return "", err
+	}
+	return migrateLegacy(".tool-hub-mcp-index"+ext, filepath.Join(dir, "index"+ext)), nil
+}
+
+// SearchIndex returns the directory of the hub's persisted search index.
+func SearchIndex() (string, error) {
+	dir, err := DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "search-index"), nil
+}
+
+// UpdateCacheFile returns the path of the release check cache.
+func UpdateCacheFile() (string, error) {
+	dir, err := DataDir()
+	if err != nil {
+		return "", err
+	}
+	return migrateLegacy(".tool-hub-mcp-cache.json", filepath.Join(dir, "update-cache.json")), nil
+}
+
+// baseDir resolves an XDG base directory for tool-hub-mcp: TOOL_HUB_HOME
+// when set, else $envVar/tool-hub-mcp when it is an absolute path, else
+// ~/fallback/tool-hub-mcp.
+func baseDir(envVar, fallback string) (string, error) {
+	if dir := os.Getenv(HomeEnvVar); dir != "" {
+		return dir, nil
+	}
+	if dir := os.Getenv(envVar); filepath.IsAbs(dir) {
+		return filepath.Join(dir, appName), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, fallback, appName), nil
+}
+
+// migrateLegacy moves ~/legacy to target when only the legacy file
+// exists, and returns the path callers should use: target, or the legacy
+// path if the move failed so existing data is never lost.
+func migrateLegacy(legacy, target string) string {
+	if _, err := os.Stat(target); err == nil {
+		return target
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return target
+	}
+	legacyPath := filepath.Join(home, legacy)
+	if _, err := os.Stat(legacyPath); err != nil {
+		return target
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		log.Printf("Warning: failed to create %s, still using %s: %v", filepath.Dir(target), legacyPath, err)
+		return legacyPath
+	}
+	if err := os.Rename(legacyPath, target); err != nil {
+		log.Printf("Warning: failed to move %s to %s, still using it: %v", legacyPath, target, err)
+		return legacyPath
+	}
+	log.Printf("Moved %s to %s", legacyPath, target)
+	return target
+}
@@ -0,0 +1,111 @@
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// setHome isolates a test from the user's real directories.
+func setHome(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(HomeEnvVar, "")
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_DATA_HOME", "")
+	return home
+}
+
+func TestDefaultLayout(t *testing.T) {
+	home := setHome(t)
+
+	cases := []struct {
+		name string
+		get  func() (string, error)
+		want string
+	}{
+		{"config", ConfigFile, filepath.Join(home, ".config", "tool-hub-mcp", "config.json")},
+		{"history", HistoryDB, filepath.Join(home, ".local", "share", "tool-hub-mcp", "history.db")},
+		{"index", func() (string, error) { return IndexFile(".jsonl") }, filepath.Join(home, ".local", "share", "tool-hub-mcp", "index.jsonl")},
+		{"search index", SearchIndex, filepath.Join(home, ".local", "share", "tool-hub-mcp", "search-index")},
+		{"update cache", UpdateCacheFile, filepath.Join(home, ".local", "share", "tool-hub-mcp", "update-cache.json")},
+	}
+	for _, c := range cases {
+		got, err := c.get()
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if got != c.want {
+			t.Errorf("%s = %q, want %q", c.name, got, c.want)
+		}
+	}
+}
+
+func TestOverrides(t *testing.T) {
+	setHome(t)
+
+	xdg := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(xdg, "config"))
+	t.Setenv("XDG_DATA_HOME", "relative/ignored")
+	if got, _ := ConfigDir(); got != filepath.Join(xdg, "config", "tool-hub-mcp") {
+		t.Errorf("ConfigDir() = %q, want XDG_CONFIG_HOME based path", got)
+	}
+	if got, _ := DataDir(); filepath.Base(filepath.Dir(got)) != "share" {
+		t.Errorf("DataDir() = %q, relative XDG_DATA_HOME should be ignored", got)
+	}
+
+	root := t.TempDir()
+	t.Setenv(HomeEnvVar, root)
+	config, _ := ConfigFile()
+	history, _ := HistoryDB()
+	if config != filepath.Join(root, "config.json") || history != filepath.Join(root, "history.db") {
+		t.Errorf("%s should hold every file, got %q and %q", HomeEnvVar, config, history)
+	}
+}
+
+func TestMigrateLegacy(t *testing.T) {
+	home := setHome(t)
+
+	legacyDir := filepath.Join(home, ".tool-hub-mcp")
+	if err := os.MkdirAll(legacyDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		filepath.Join(home, ".tool-hub-mcp.json"):  `{"servers":{}}`,
+		filepath.Join(legacyDir, "history.db"):     "db",
+		filepath.Join(legacyDir, "history.db-wal"): "wal",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config, _ := ConfigFile()
+	if data, err := os.ReadFile(config); err != nil || string(data) != `{"servers":{}}` {
+		t.Errorf("config not moved to %s: %q, %v", config, data, err)
+	}
+
+	history, _ := HistoryDB()
+	if data, err := os.ReadFile(history + "-wal"); err != nil || string(data) != "wal" {
+		t.Errorf("WAL file not moved with the database: %q, %v", data, err)
+	}
+	if _, err := os.Stat(history); err != nil {
+		t.Errorf("database not moved: %v", err)
+	}
+	if _, err := os.Stat(legacyDir); !os.IsNotExist(err) {
+		t.Errorf("empty legacy directory should be removed, stat err = %v", err)
+	}
+
+	// The new location wins once it exists
+	if err := os.WriteFile(filepath.Join(home, ".tool-hub-mcp.json"), []byte("stale"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := ConfigFile(); again != config {
+		t.Errorf("ConfigFile() = %q after migration, want %q", again, config)
+	}
+	if data, _ := os.ReadFile(config); string(data) != `{"servers":{}}` {
+		t.Errorf("existing config overwritten by legacy file: %q", data)
+	}
+}
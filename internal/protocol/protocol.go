@@ -0,0 +1,63 @@
+/*
+Package protocol lists the MCP protocol revisions tool-hub-mcp speaks,
+negotiates the revision used with a peer, and defines the Tool type
+shared by the spawner, the search index and the catalog.
+
+Revisions are dated strings that sort chronologically, so feature checks
+compare them directly:
+  - 2024-11-05: the original revision
+  - 2025-03-26: tool annotations, audio content, JSON-RPC batching
+  - 2025-06-18: structured tool output, titles, resource links
+*/
+package protocol
+
+// MCP protocol revisions.
+const (
+	Version20241105 = "2024-11-05"
+	Version20250326 = "2025-03-26"
+	Version20250618 = "2025-06-18"
+)
+
+// Latest is the newest revision tool-hub-mcp supports, requested from
+// children and offered to clients that ask for an unknown revision.
+const Latest = Version20250618
+
+// Supported lists the supported revisions, newest first.
+var Supported = []string{Version20250618, Version20250326, Version20241105}
+
+// IsSupported reports whether version is a supported revision.
+func IsSupported(version string) bool {
+	for _, v := range Supported {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
+// Negotiate picks the revision to answer an initialize request with: the
+// client's requested revision if supported, otherwise Latest, which the
+// client may accept or disconnect over.
+func Negotiate(requested string) string {
+	if IsSupported(requested) {
+		return requested
+	}
+	return Latest
+}
+
+// AtLeast reports whether version is min or a later revision.
+func AtLeast(version, min string) bool {
+	return version >= min
+}
+
+// HasToolAnnotations reports whether a revision defines tool annotations
+// (readOnlyHint, destructiveHint, ...).
+func HasToolAnnotations(version string) bool {
+	return AtLeast(version, Version20250326)
+}
+
+// HasStructuredOutput reports whether a revision defines titles, tool
+// outputSchema and structuredContent results.
+func HasStructuredOutput(version string) bool {
+	return AtLeast(version, Version20250618)
+}
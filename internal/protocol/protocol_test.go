@@ -0,0 +1,27 @@
+package protocol
+
+import "testing"
+
+func TestNegotiate(t *testing.T) {
+	tests := map[string]string{
+		Version20241105: Version20241105,
+		Version20250326: Version20250326,
+		Version20250618: Version20250618,
+		"2099-01-01":    Latest,
+		"":              Latest,
+	}
+	for requested, want := range tests {
+		if got := Negotiate(requested); got != want {
+			t.Errorf("Negotiate(%q) = %q, want %q", requested, got, want)
+		}
+	}
+}
+
+func TestFeatures(t *testing.T) {
+	if HasToolAnnotations(Version20241105) || !HasToolAnnotations(Version20250326) {
+		t.Error("tool annotations arrived in 2025-03-26")
+	}
+	if HasStructuredOutput(Version20250326) || !HasStructuredOutput(Version20250618) {
+		t.Error("structured output arrived in 2025-06-18")
+	}
+}
@@ -0,0 +1,27 @@
+package protocol
+
+// Tool represents a tool definition from a child MCP server.
+type Tool struct {
+	Name        string      `json:"name"`
+	Title       string      `json:"title,omitempty"`
+	Description string      `json:"description"`
+	InputSchema interface{} `json:"inputSchema"`
+	// OutputSchema describes the tool's structuredContent (2025-06-18)
+	OutputSchema interface{} `json:"outputSchema,omitempty"`
+	// Annotations are the behavior hints of 2025-03-26 and later servers
+	// (readOnlyHint, destructiveHint, ...)
+	Annotations map[string]interface{} `json:"annotations,omitempty"`
+}
+
+// ReadOnly reports whether the server annotated the tool as read-only.
+func (t Tool) ReadOnly() bool {
+	readOnly, _ := t.Annotations["readOnlyHint"].(bool)
+	return readOnly
+}
+
+// Idempotent reports whether the server annotated the tool as idempotent
+// or read-only, i.e. safe to call again with the same arguments.
+func (t Tool) Idempotent() bool {
+	idempotent, _ := t.Annotations["idempotentHint"].(bool)
+	return idempotent || t.ReadOnly()
+}
@@ -0,0 +1,63 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Bucket is a token bucket: tokens refill at a steady rate up to burst,
+// and each call takes one. Unlike a Window it smooths calls over time, so
+// a tight agent loop is slowed down instead of spending a whole quota at once.
+type Bucket struct {
+	rate  float64 // tokens per second
+	burst int
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewBucket creates a full bucket refilling perMinute tokens per minute,
+// holding at most burst.
+func NewBucket(perMinute float64, burst int) *Bucket {
+	return &Bucket{
+		rate:   perMinute / 60,
+		burst:  burst,
+		tokens: float64(burst),
+	}
+}
+
+// PerMinute returns the refill rate in tokens per minute.
+func (b *Bucket) PerMinute() float64 {
+	return b.rate * 60
+}
+
+// Burst returns the bucket's capacity.
+func (b *Bucket) Burst() int {
+	return b.burst
+}
+
+// Take removes a token at now if one is available. Otherwise it returns
+// false and how long until the next token.
+func (b *Bucket) Take(now time.Time) (bool, time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.last.IsZero() && now.After(b.last) {
+		b.tokens = math.Min(float64(b.burst), b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	}
+	if b.last.IsZero() || now.After(b.last) {
+		b.last = now
+	}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	if b.rate <= 0 {
+		return false, time.Duration(math.MaxInt64)
+	}
+	wait := (1 - b.tokens) / b.rate
+	return false, time.Duration(math.Ceil(wait * float64(time.Second)))
+}
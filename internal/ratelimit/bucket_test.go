@@ -0,0 +1,51 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBucketTake(t *testing.T) {
+	b := NewBucket(60, 2) // one token per second, bursts of 2
+	start := time.Now()
+
+	for i := 0; i < 2; i++ {
+		if ok, _ := b.Take(start); !ok {
+			t.Fatalf("call %d within the burst should be allowed", i+1)
+		}
+	}
+
+	ok, retryAfter := b.Take(start)
+	if ok {
+		t.Fatal("call beyond the burst should be rejected")
+	}
+	if retryAfter != time.Second {
+		t.Errorf("Expected retry after 1s, got %v", retryAfter)
+	}
+
+	// Half a token refilled: still rejected, with the remaining wait
+	if ok, retryAfter = b.Take(start.Add(500 * time.Millisecond)); ok || retryAfter != 500*time.Millisecond {
+		t.Errorf("Expected rejection with 500ms wait, got %v %v", ok, retryAfter)
+	}
+	if ok, _ := b.Take(start.Add(time.Second)); !ok {
+		t.Error("call after a token refilled should be allowed")
+	}
+
+	// Refill never exceeds the burst
+	later := start.Add(time.Hour)
+	for i := 0; i < 2; i++ {
+		if ok, _ := b.Take(later); !ok {
+			t.Fatalf("call %d after a long pause should be allowed", i+1)
+		}
+	}
+	if ok, _ := b.Take(later); ok {
+		t.Error("refill should be capped at the burst")
+	}
+}
+
+func TestBucketAccessors(t *testing.T) {
+	b := NewBucket(30, 5)
+	if b.PerMinute() != 30 || b.Burst() != 5 {
+		t.Errorf("got %v per minute, burst %d", b.PerMinute(), b.Burst())
+	}
+}
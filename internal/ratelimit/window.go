@@ -0,0 +1,99 @@
+/*
+Package ratelimit implements request quota tracking for child MCP servers.
+
+A Window tracks executions against a provider quota (e.g. GitHub's 5000
+requests per hour) using a sliding window, so the hub can delay or reject
+calls before the child server hits the provider's 429 response. A Bucket
+throttles the call rate of a server or client with a token bucket.
+*/
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Window is a sliding-window request counter.
+type Window struct {
+	limit    int
+	period   time.Duration
+	paceFrom int
+
+	mu     sync.Mutex
+	events []time.Time
+}
+
+// NewWindow creates a window allowing limit requests per period.
+func NewWindow(limit int, period time.Duration) *Window {
+	return NewPacedWindow(limit, period, limit)
+}
+
+// NewPacedWindow creates a window allowing limit requests per period that
+// spaces requests period/limit apart once paceFrom of them are in the
+// window, so a nearly exhausted quota lasts until requests leave it.
+func NewPacedWindow(limit int, period time.Duration, paceFrom int) *Window {
+	return &Window{
+		limit:    limit,
+		period:   period,
+		paceFrom: paceFrom,
+	}
+}
+
+// Limit returns the number of requests allowed per period.
+func (w *Window) Limit() int {
+	return w.limit
+}
+
+// Period returns the window length.
+func (w *Window) Period() time.Duration {
+	return w.period
+}
+
+// PaceFrom returns the number of requests in the window after which
+// requests are paced.
+func (w *Window) PaceFrom() int {
+	return w.paceFrom
+}
+
+// Allow records a request at now if the quota permits it.
+// When the quota is exhausted, it returns false and how long the caller
+// must wait until the oldest request leaves the window; when it is nearly
+// exhausted, how long until the request is due at the paced rate.
+func (w *Window) Allow(now time.Time) (bool, time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.prune(now)
+
+	if len(w.events) >= w.limit {
+		return false, w.events[0].Add(w.period).Sub(now)
+	}
+	if len(w.events) >= w.paceFrom {
+		due := w.events[len(w.events)-1].Add(w.period / time.Duration(w.limit))
+		if now.Before(due) {
+			return false, due.Sub(now)
+		}
+	}
+
+	w.events = append(w.events, now)
+	return true, 0
+}
+
+// Remaining returns how many requests are still available at now.
+func (w *Window) Remaining(now time.Time) int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.prune(now)
+	return w.limit - len(w.events)
+}
+
+// prune drops requests that fell out of the window (caller must hold lock).
+func (w *Window) prune(now time.Time) {
+	cutoff := now.Add(-w.period)
+	i := 0
+	for i < len(w.events) && !w.events[i].After(cutoff) {
+		i++
+	}
+	w.events = w.events[i:]
+}
@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWindowAllow(t *testing.T) {
+	w := NewWindow(2, time.Minute)
+	start := time.Now()
+
+	if ok, _ := w.Allow(start); !ok {
+		t.Fatal("first request should be allowed")
+	}
+	if ok, _ := w.Allow(start.Add(10 * time.Second)); !ok {
+		t.Fatal("second request should be allowed")
+	}
+
+	ok, retryAfter := w.Allow(start.Add(20 * time.Second))
+	if ok {
+		t.Fatal("third request should be rejected")
+	}
+	if retryAfter != 40*time.Second {
+		t.Errorf("Expected retry after 40s, got %v", retryAfter)
+	}
+
+	// Oldest request leaves the window after one period
+	if ok, _ := w.Allow(start.Add(61 * time.Second)); !ok {
+		t.Error("request after window slides should be allowed")
+	}
+}
+
+func TestWindowPacesNearExhaustion(t *testing.T) {
+	w := NewPacedWindow(4, time.Minute, 2)
+	start := time.Now()
+
+	for i := 0; i < 2; i++ {
+		if ok, _ := w.Allow(start); !ok {
+			t.Fatalf("request %d should be allowed in a burst", i+1)
+		}
+	}
+
+	// Past the threshold requests are spaced period/limit apart
+	ok, retryAfter := w.Allow(start.Add(5 * time.Second))
+	if ok || retryAfter != 10*time.Second {
+		t.Fatalf("Allow() = %v, %v; want paced retry after 10s", ok, retryAfter)
+	}
+	if ok, _ := w.Allow(start.Add(15 * time.Second)); !ok {
+		t.Fatal("request due at the paced rate should be allowed")
+	}
+	if w.Remaining(start.Add(15*time.Second)) != 1 {
+		t.Errorf("rejected requests should not count")
+	}
+}
+
+func TestWindowRemaining(t *testing.T) {
+	w := NewWindow(3, time.Hour)
+	now := time.Now()
+
+	if got := w.Remaining(now); got != 3 {
+		t.Errorf("Expected 3 remaining, got %d", got)
+	}
+
+	w.Allow(now)
+	if got := w.Remaining(now); got != 2 {
+		t.Errorf("Expected 2 remaining, got %d", got)
+	}
+
+	if got := w.Remaining(now.Add(2 * time.Hour)); got != 3 {
+		t.Errorf("Expected window to reset, got %d remaining", got)
+	}
+}
+
+func TestWindowAccessors(t *testing.T) {
+	w := NewWindow(5000, time.Hour)
+	if w.Limit() != 5000 || w.Period() != time.Hour || w.PaceFrom() != 5000 {
+		t.Errorf("Unexpected limit/period: %d/%v", w.Limit(), w.Period())
+	}
+}
@@ -0,0 +1,276 @@
+/*
+Package redact masks secrets before they reach logs, error messages and
+command output.
+
+Secrets are recognized two ways: by key, for env vars, arguments and
+flags whose name looks like a token, key, secret or password; and by
+value, for secret values registered from the config and the process
+environment, wherever they later appear in free text.
+*/
+package redact
+
+import (
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Placeholder replaces every redacted value.
+const Placeholder = "[REDACTED]"
+
+// minSecretLength is the shortest registered value masked in free text;
+// shorter values ("1", "true") would mangle unrelated output.
+const minSecretLength = 6
+
+// secretKeyParts mark keys whose values are never shown.
+var secretKeyParts = []string{"password", "passwd", "secret", "token", "apikey", "api_key", "authorization", "credential", "private_key"}
+
+// IsSecretKey reports whether an argument name likely holds a secret,
+// i.e. contains one of secretKeyParts.
+func IsSecretKey(key string) bool {
+	lower := strings.ToLower(strings.ReplaceAll(strings.TrimLeft(key, "-"), "-", "_"))
+	for _, part := range secretKeyParts {
+		if strings.Contains(lower, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSecretEnvKey reports whether an env var or command-line flag likely
+// holds a secret. Beyond IsSecretKey, any "key" word counts (ACCESS_KEY,
+// LICENSE_KEY, --key); tool arguments don't use this, so an issueKey
+// argument is still recorded.
+func IsSecretEnvKey(key string) bool {
+	if IsSecretKey(key) {
+		return true
+	}
+	for _, word := range splitWords(key) {
+		if word == "key" {
+			return true
+		}
+	}
+	return false
+}
+
+// splitWords splits a name on separators and camelCase boundaries,
+// lowercasing the words.
+func splitWords(name string) []string {
+	var words []string
+	var current []rune
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, strings.ToLower(string(current)))
+			current = current[:0]
+		}
+	}
+	runes := []rune(name)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && i > 0 && unicode.IsLower(runes[i-1]):
+			flush()
+			current = append(current, r)
+		default:
+			current = append(current, r)
+		}
+	}
+	flush()
+	return words
+}
+
+// Value returns a copy of a decoded JSON value with secret-looking keys
+// redacted at any depth.
+func Value(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(v))
+		for key, inner := range v {
+			if IsSecretKey(key) {
+				redacted[key] = Placeholder
+			} else {
+				redacted[key] = Value(inner)
+			}
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, inner := range v {
+			redacted[i] = Value(inner)
+		}
+		return redacted
+	case string:
+		return String(v)
+	default:
+		return value
+	}
+}
+
+// Env returns a copy of an env map with the values of secret keys masked.
+func Env(env map[string]string) map[string]string {
+	if env == nil {
+		return nil
+	}
+	redacted := make(map[string]string, len(env))
+	for key, value := range env {
+		if IsSecretEnvKey(key) && value != "" {
+			value = Placeholder
+		}
+		redacted[key] = value
+	}
+	return redacted
+}
+
+// Args returns a copy of command arguments with secrets masked: the value
+// after a secret flag (--token abc), inline values (--token=abc,
+// API_KEY=abc) and registered secret values anywhere.
+func Args(args []string) []string {
+	if args == nil {
+		return nil
+	}
+	redacted := make([]string, len(args))
+	maskNext := false
+	for i, arg := range args {
+		switch {
+		case maskNext && !strings.HasPrefix(arg, "-"):
+			redacted[i] = Placeholder
+			maskNext = false
+			continue
+		case strings.Contains(arg, "="):
+			key, _, _ := strings.Cut(arg, "=")
+			if IsSecretEnvKey(key) {
+				redacted[i] = key + "=" + Placeholder
+				maskNext = false
+				continue
+			}
+		}
+		maskNext = strings.HasPrefix(arg, "-") && IsSecretEnvKey(arg)
+		redacted[i] = String(arg)
+	}
+	return redacted
+}
+
+// URL returns a URL with secrets masked: a userinfo password, query
+// parameters with secret names (?api_key=...) and registered secret
+// values anywhere.
+func URL(raw string) string {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return String(raw)
+	}
+	if _, hasPassword := parsed.User.Password(); hasPassword {
+		parsed.User = url.UserPassword(parsed.User.Username(), Placeholder)
+	}
+	query := parsed.Query()
+	masked := false
+	for key := range query {
+		if IsSecretEnvKey(key) {
+			query.Set(key, Placeholder)
+			masked = true
+		}
+	}
+	if masked {
+		parsed.RawQuery = query.Encode()
+	}
+	return String(parsed.String())
+}
+
+// Set holds known secret values to mask in free text.
+type Set struct {
+	mu     sync.RWMutex
+	values map[string]bool
+	sorted []string // longest first, so overlapping secrets mask fully
+}
+
+// Add registers secret values. Values shorter than minSecretLength are
+// ignored.
+func (s *Set) Add(values ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.values == nil {
+		s.values = make(map[string]bool)
+	}
+	changed := false
+	for _, v := range values {
+		if len(v) >= minSecretLength && v != Placeholder && !s.values[v] {
+			s.values[v] = true
+			changed = true
+		}
+	}
+	if !changed {
+		return
+	}
+	s.sorted = s.sorted[:0]
+	for v := range s.values {
+		s.sorted = append(s.sorted, v)
+	}
+	sort.Slice(s.sorted, func(i, j int) bool { return len(s.sorted[i]) > len(s.sorted[j]) })
+}
+
+// AddEnv registers the values of secret keys in an env map.
+func (s *Set) AddEnv(env map[string]string) {
+	var values []string
+	for key, value := range env {
+		if IsSecretEnvKey(key) {
+			values = append(values, value)
+		}
+	}
+	s.Add(values...)
+}
+
+// Replace masks every registered secret value in text.
+func (s *Set) Replace(text string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, v := range s.sorted {
+		if strings.Contains(text, v) {
+			text = strings.ReplaceAll(text, v, Placeholder)
+		}
+	}
+	return text
+}
+
+// Secrets is the process-wide set of known secret values. config.LoadFrom
+// registers the secret env values of every server.
+var Secrets = &Set{}
+
+// String masks registered secret values in text.
+func String(text string) string {
+	return Secrets.Replace(text)
+}
+
+// AddEnviron registers secret values from KEY=VALUE pairs, as returned
+// by os.Environ.
+func AddEnviron(environ []string) {
+	env := make(map[string]string, len(environ))
+	for _, kv := range environ {
+		if key, value, ok := strings.Cut(kv, "="); ok {
+			env[key] = value
+		}
+	}
+	Secrets.AddEnv(env)
+}
+
+// writer masks registered secrets in everything written through it.
+type writer struct {
+	w io.Writer
+}
+
+// NewWriter wraps w so registered secrets never reach it, e.g. for
+// log.SetOutput.
+func NewWriter(w io.Writer) io.Writer {
+	return &writer{w: w}
+}
+
+// Write masks secrets in p. It reports len(p) on success so callers
+// don't mistake a shorter masked write for a short write.
+func (r *writer) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(r.w, String(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
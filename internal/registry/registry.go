@@ -0,0 +1,254 @@
+/*
+Package registry looks up MCP servers in the official MCP server registry
+(registry.modelcontextprotocol.io), or a compatible registry, and resolves
+them to a server config with the command that installs and runs them.
+
+Registry entries describe packages (npm, PyPI, OCI images) and remote
+endpoints rather than commands; Resolve picks the first package the hub
+can run and maps it to npx, uvx or a container runtime.
+*/
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/httpclient"
+)
+
+// DefaultURL is the official MCP server registry.
+const DefaultURL = "https://registry.modelcontextprotocol.io"
+
+// requestTimeout bounds a registry lookup.
+const requestTimeout = 15 * time.Second
+
+// Server is a registry entry.
+type Server struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Version     string    `json:"version"`
+	Packages    []Package `json:"packages"`
+	Remotes     []Remote  `json:"remotes"`
+}
+
+// Package is a distribution of a server, e.g. an npm package.
+type Package struct {
+	// RegistryType is "npm", "pypi" or "oci" (others are not runnable here).
+	RegistryType string `json:"registryType"`
+	Identifier   string `json:"identifier"`
+	Version      string `json:"version"`
+
+	// RuntimeHint suggests the runner, e.g. "npx", "uvx" or "docker".
+	RuntimeHint string    `json:"runtimeHint"`
+	Transport   Transport `json:"transport"`
+
+	RuntimeArguments     []Argument `json:"runtimeArguments"`
+	PackageArguments     []Argument `json:"packageArguments"`
+	EnvironmentVariables []EnvVar   `json:"environmentVariables"`
+}
+
+// Transport is how a package talks MCP ("stdio", "streamable-http", "sse").
+type Transport struct {
+	Type string `json:"type"`
+}
+
+// Argument is a command-line argument of a package: "positional" or
+// "named" (Name is the flag, e.g. "--port").
+type Argument struct {
+	Type       string `json:"type"`
+	Name       string `json:"name"`
+	Value      string `json:"value"`
+	Default    string `json:"default"`
+	ValueHint  string `json:"valueHint"`
+	IsRequired bool   `json:"isRequired"`
+}
+
+// EnvVar is an environment variable a package reads.
+type EnvVar struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Default     string `json:"default"`
+	IsRequired  bool   `json:"isRequired"`
+	IsSecret    bool   `json:"isSecret"`
+}
+
+// Remote is a hosted endpoint of a server.
+type Remote struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+// entry is an element of the servers list. Current registries wrap the
+// server in "server" alongside registry metadata; older ones return it bare.
+type entry struct {
+	Wrapped *Server `json:"server"`
+	Server
+}
+
+// server returns the entry's server, wrapped or bare.
+func (e *entry) server() *Server {
+	if e.Wrapped != nil {
+		return e.Wrapped
+	}
+	return &e.Server
+}
+
+// Lookup finds the server called name in the registry at baseURL (empty
+// uses DefaultURL). name may be the full registry name
+// ("io.github.owner/server") or its last segment when that is unambiguous.
+func Lookup(baseURL, name string) (*Server, error) {
+	if baseURL == "" {
+		baseURL = DefaultURL
+	}
+	endpoint := strings.TrimRight(baseURL, "/") + "/v0/servers?" + url.Values{
+		"search":  {name},
+		"version": {"latest"},
+	}.Encode()
+
+	resp, err := httpclient.New(requestTimeout).Get(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query registry: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("registry returned %s", resp.Status)
+	}
+
+	var body struct {
+		Servers []entry `json:"servers"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to parse registry response: %w", err)
+	}
+
+	var matches []*Server
+	for i := range body.Servers {
+		server := body.Servers[i].server()
+		if strings.EqualFold(server.Name, name) {
+			return server, nil
+		}
+		if strings.EqualFold(ShortName(server.Name), name) {
+			matches = append(matches, server)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("server '%s' not found in registry %s", name, baseURL)
+	case 1:
+		return matches[0], nil
+	}
+	names := make([]string, len(matches))
+	for i, m := range matches {
+		names[i] = m.Name
+	}
+	return nil, fmt.Errorf("'%s' matches several servers, use the full name: %s", name, strings.Join(names, ", "))
+}
+
+// ShortName returns the last segment of a registry name, e.g. "github"
+// for "io.github.owner/github", used as the default server name.
+func ShortName(name string) string {
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
+// Resolve maps a registry server to a server config using its first
+// runnable package: npm packages run with npx, PyPI packages with uvx and
+// OCI images in Docker. Servers with only remote endpoints resolve to a
+// remote config. The returned variables are those the package reads;
+// their defaults are pre-filled in Env, the rest are for the caller to
+// ask for.
+func Resolve(server *Server) (*config.ServerConfig, []EnvVar, error) {
+	for _, pkg := range server.Packages {
+		if pkg.Transport.Type != "" && pkg.Transport.Type != "stdio" {
+			continue
+		}
+		cfg := packageConfig(pkg)
+		if cfg == nil {
+			continue
+		}
+		for _, v := range pkg.EnvironmentVariables {
+			if v.Default != "" {
+				if cfg.Env == nil {
+					cfg.Env = make(map[string]string)
+				}
+				cfg.Env[v.Name] = v.Default
+			}
+		}
+		return cfg, pkg.EnvironmentVariables, nil
+	}
+
+	for _, remote := range server.Remotes {
+		if remote.URL != "" {
+			return &config.ServerConfig{Type: remote.Type, URL: remote.URL}, nil, nil
+		}
+	}
+
+	types := make([]string, 0, len(server.Packages))
+	for _, pkg := range server.Packages {
+		types = append(types, pkg.RegistryType)
+	}
+	return nil, nil, fmt.Errorf("server '%s' has no package tool-hub-mcp can run (found: %s)", server.Name, strings.Join(types, ", "))
+}
+
+// packageConfig returns the config running pkg, or nil for unsupported
+// registry types.
+func packageConfig(pkg Package) *config.ServerConfig {
+	runtimeArgs := arguments(pkg.RuntimeArguments)
+	packageArgs := arguments(pkg.PackageArguments)
+
+	switch pkg.RegistryType {
+	case "npm":
+		spec := pkg.Identifier
+		if pkg.Version != "" {
+			spec += "@" + pkg.Version
+		}
+		args := append([]string{"-y"}, runtimeArgs...)
+		return &config.ServerConfig{Command: "npx", Args: append(append(args, spec), packageArgs...)}
+	case "pypi":
+		spec := pkg.Identifier
+		if pkg.Version != "" {
+			spec += "==" + pkg.Version
+		}
+		return &config.ServerConfig{Command: "uvx", Args: append(append(runtimeArgs, spec), packageArgs...)}
+	case "oci", "docker":
+		image := pkg.Identifier
+		if pkg.Version != "" && !strings.Contains(image[strings.LastIndex(image, "/")+1:], ":") {
+			image += ":" + pkg.Version
+		}
+		return &config.ServerConfig{
+			Runtime:   config.RuntimeDocker,
+			Args:      packageArgs,
+			Container: &config.ContainerSettings{Image: image},
+		}
+	}
+	return nil
+}
+
+// arguments renders registry arguments that have a value or default.
+// Arguments without one are left for the user to add.
+func arguments(args []Argument) []string {
+	var rendered []string
+	for _, arg := range args {
+		value := arg.Value
+		if value == "" {
+			value = arg.Default
+		}
+		switch {
+		case arg.Type == "named" && value != "":
+			rendered = append(rendered, arg.Name, value)
+		case arg.Type == "named" && arg.IsRequired:
+			rendered = append(rendered, arg.Name)
+		case value != "":
+			rendered = append(rendered, value)
+		}
+	}
+	return rendered
+}
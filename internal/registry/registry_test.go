@@ -0,0 +1,121 @@
+package registry
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+)
+
+const listResponse = `{"servers": [
+  {"server": {"name": "io.github.acme/jira", "version": "1.2.0", "description": "Jira tools",
+    "packages": [{"registryType": "npm", "identifier": "@acme/jira-mcp", "version": "1.2.0", "transport": {"type": "stdio"},
+      "environmentVariables": [{"name": "JIRA_TOKEN", "isRequired": true, "isSecret": true}, {"name": "JIRA_URL", "default": "https://jira.acme.dev"}]}]},
+   "_meta": {"io.modelcontextprotocol.registry/official": {"isLatest": true}}},
+  {"name": "io.github.other/jira-tools", "version": "0.1.0"},
+  {"server": {"name": "io.github.other/github", "version": "2.0.0"}},
+  {"server": {"name": "io.github.acme/github", "version": "1.0.0"}}
+]}`
+
+func newRegistry(t *testing.T) *httptest.Server {
+	t.Helper()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v0/servers" || r.URL.Query().Get("search") == "" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(listResponse))
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestLookup(t *testing.T) {
+	ts := newRegistry(t)
+
+	server, err := Lookup(ts.URL, "io.github.acme/jira")
+	if err != nil || server.Version != "1.2.0" || len(server.Packages) != 1 {
+		t.Fatalf("Lookup by full name = %+v, %v", server, err)
+	}
+
+	// Bare entries from older registries, matched by last segment
+	server, err = Lookup(ts.URL, "jira-tools")
+	if err != nil || server.Name != "io.github.other/jira-tools" {
+		t.Errorf("Lookup by short name = %+v, %v", server, err)
+	}
+
+	if _, err := Lookup(ts.URL, "github"); err == nil || !strings.Contains(err.Error(), "several servers") {
+		t.Errorf("expected an ambiguity error, got %v", err)
+	}
+	if _, err := Lookup(ts.URL, "confluence"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name string
+		pkg  Package
+		want *config.ServerConfig
+	}{
+		{
+			name: "npm",
+			pkg: Package{RegistryType: "npm", Identifier: "@acme/jira-mcp", Version: "1.2.0",
+				PackageArguments: []Argument{{Type: "named", Name: "--mode", Default: "read"}, {Type: "positional", ValueHint: "path"}}},
+			want: &config.ServerConfig{Command: "npx", Args: []string{"-y", "@acme/jira-mcp@1.2.0", "--mode", "read"}},
+		},
+		{
+			name: "pypi",
+			pkg:  Package{RegistryType: "pypi", Identifier: "mcp-server-git", Version: "0.6.2"},
+			want: &config.ServerConfig{Command: "uvx", Args: []string{"mcp-server-git==0.6.2"}},
+		},
+		{
+			name: "oci",
+			pkg:  Package{RegistryType: "oci", Identifier: "docker.io/mcp/fetch", Version: "1.0.0"},
+			want: &config.ServerConfig{Runtime: config.RuntimeDocker, Container: &config.ContainerSettings{Image: "docker.io/mcp/fetch:1.0.0"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := Resolve(&Server{Name: "x", Packages: []Package{tt.pkg}})
+			if err != nil {
+				t.Fatalf("Resolve failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Resolve = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveEnvAndFallbacks(t *testing.T) {
+	server := &Server{Name: "jira", Packages: []Package{
+		{RegistryType: "nuget", Identifier: "Acme.Jira"},
+		{RegistryType: "npm", Identifier: "@acme/jira-mcp", EnvironmentVariables: []EnvVar{
+			{Name: "JIRA_TOKEN", IsRequired: true, IsSecret: true},
+			{Name: "JIRA_URL", Default: "https://jira.acme.dev"},
+		}},
+	}}
+	cfg, vars, err := Resolve(server)
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if cfg.Command != "npx" || len(vars) != 2 {
+		t.Errorf("expected the npm package to be picked, got %+v with %d vars", cfg, len(vars))
+	}
+	if want := map[string]string{"JIRA_URL": "https://jira.acme.dev"}; !reflect.DeepEqual(cfg.Env, want) {
+		t.Errorf("Env = %v, want defaults %v", cfg.Env, want)
+	}
+
+	remote, _, err := Resolve(&Server{Name: "linear", Remotes: []Remote{{Type: "sse", URL: "https://mcp.linear.app/sse"}}})
+	if err != nil || !remote.IsRemote() || remote.URL != "https://mcp.linear.app/sse" {
+		t.Errorf("expected a remote config, got %+v, %v", remote, err)
+	}
+
+	if _, _, err := Resolve(&Server{Name: "dotnet", Packages: []Package{{RegistryType: "nuget"}}}); err == nil || !strings.Contains(err.Error(), "nuget") {
+		t.Errorf("expected an unsupported package error, got %v", err)
+	}
+}
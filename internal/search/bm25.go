@@ -9,28 +9,11 @@ import (
 
 // SearchBM25 performs BM25 keyword search using Bleve.
 func (i *Indexer) SearchBM25(query string, limit int) ([]SearchResult, error) {
-	i.mu.RLock()
-	defer i.mu.RUnlock()
-
-	if limit <= 0 {
-		limit = 10
-	}
-
-	// Build search query
-	searchQuery := i.buildMatchQuery(query)
-
-	// Create search request
-	searchRequest := bleve.NewSearchRequestOptions(searchQuery, limit, 0, false)
-	searchRequest.Fields = []string{"name", "description", "server", "inputSchema"}
-
-	// Execute search
-	results, err := i.bleveIndex.Search(searchRequest)
+	page, err := i.SearchBM25Page(query, limit, 0)
 	if err != nil {
-		return nil, fmt.Errorf("bleve search failed: %w", err)
+		return nil, err
 	}
-
-	// Convert results
-	return convertBleveResults(results), nil
+	return page.Results, nil
 }
 
 // convertBleveResults converts Bleve search results to our SearchResult format.
@@ -56,9 +39,18 @@ func convertBleveResults(results *bleve.SearchResult) []SearchResult {
 			ToolName:    name,
 			Description: description,
 			InputSchema: inputSchema,
+			Example:     decodeExample(hit.Fields["example"]),
 			ServerName:  server,
+			Tags:        fieldStrings(hit.Fields["tags"]),
+			Highlights:  convertHighlights(hit.Fragments),
 			Score:       hit.Score,
 		}
+		for _, field := range []string{"name", "description", "server"} {
+			_, ok := hit.Locations[field]
+			if _, cjk := hit.Locations[cjkFields[field]]; ok || cjk {
+				result.MatchedFields = append(result.MatchedFields, field)
+			}
+		}
 
 		searchResults = append(searchResults, result)
 	}
@@ -68,31 +60,11 @@ func convertBleveResults(results *bleve.SearchResult) []SearchResult {
 
 // SearchByServer performs BM25 search scoped to a specific server.
 func (i *Indexer) SearchByServer(query, serverName string, limit int) ([]SearchResult, error) {
-	i.mu.RLock()
-	defer i.mu.RUnlock()
-
-	if limit <= 0 {
-		limit = 10
-	}
-
-	// Create conjunction query: (match query) AND (server filter)
-	matchQuery := i.buildMatchQuery(query)
-	serverQuery := bleve.NewTermQuery(serverName)
-	serverQuery.SetField("server")
-
-	conjunctionQuery := bleve.NewConjunctionQuery(matchQuery, serverQuery)
-
-	// Create search request
-	searchRequest := bleve.NewSearchRequestOptions(conjunctionQuery, limit, 0, false)
-	searchRequest.Fields = []string{"name", "description", "server", "inputSchema"}
-
-	// Execute search
-	results, err := i.bleveIndex.Search(searchRequest)
+	page, err := i.SearchByServerPage(query, serverName, limit, 0)
 	if err != nil {
-		return nil, fmt.Errorf("bleve search failed: %w", err)
+		return nil, err
 	}
-
-	return convertBleveResults(results), nil
+	return page.Results, nil
 }
 
 // GetAllTools retrieves all indexed tools (up to limit).
@@ -107,7 +79,7 @@ func (i *Indexer) GetAllTools(limit int) ([]SearchResult, error) {
 	// Match all documents
 	query := bleve.NewMatchAllQuery()
 	searchRequest := bleve.NewSearchRequestOptions(query, limit, 0, false)
-	searchRequest.Fields = []string{"name", "description", "server", "inputSchema"}
+	searchRequest.Fields = resultFields
 
 	results, err := i.bleveIndex.Search(searchRequest)
 	if err != nil {
@@ -116,3 +88,55 @@ func (i *Indexer) GetAllTools(limit int) ([]SearchResult, error) {
 
 	return convertBleveResults(results), nil
 }
+
+// DefaultToolPageSize is the number of tools EachTool loads at a time.
+const DefaultToolPageSize = 500
+
+// EachTool calls fn for every indexed tool in ID ("server/tool") order,
+// loading pageSize tools at a time (DefaultToolPageSize if <= 0), so that
+// large indexes are read without holding them in memory. Unlike
+// GetAllTools it has no cap. An error from fn stops the iteration and is
+// returned. The index is not locked while fn runs.
+func (i *Indexer) EachTool(pageSize int, fn func(SearchResult) error) error {
+	if pageSize <= 0 {
+		pageSize = DefaultToolPageSize
+	}
+
+	var after []string
+	for {
+		page, last, err := i.toolPage(pageSize, after)
+		if err != nil {
+			return err
+		}
+		for _, result := range page {
+			if err := fn(result); err != nil {
+				return err
+			}
+		}
+		if len(page) < pageSize {
+			return nil
+		}
+		after = []string{last}
+	}
+}
+
+// toolPage loads the size tools following the ID in after, sorted by ID,
+// and returns the ID of the last one.
+func (i *Indexer) toolPage(size int, after []string) ([]SearchResult, string, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	searchRequest := bleve.NewSearchRequestOptions(bleve.NewMatchAllQuery(), size, 0, false)
+	searchRequest.Fields = resultFields
+	searchRequest.SortBy([]string{"_id"})
+	searchRequest.SearchAfter = after
+
+	results, err := i.bleveIndex.Search(searchRequest)
+	if err != nil {
+		return nil, "", fmt.Errorf("bleve search failed: %w", err)
+	}
+	if len(results.Hits) == 0 {
+		return nil, "", nil
+	}
+	return convertBleveResults(results), results.Hits[len(results.Hits)-1].ID, nil
+}
@@ -0,0 +1,45 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/protocol"
+)
+
+func TestFieldBoostsAndMatchedFields(t *testing.T) {
+	indexer, err := NewIndexer()
+	if err != nil {
+		t.Fatalf("failed to create indexer: %v", err)
+	}
+	defer indexer.Close()
+
+	tools := []protocol.Tool{
+		{Name: "deploy", Description: "Run the pipeline"},
+		{Name: "run_pipeline", Description: "Deploy the current build"},
+	}
+	if err := indexer.IndexServer("ci", tools); err != nil {
+		t.Fatalf("failed to index server: %v", err)
+	}
+
+	results, err := indexer.SearchBM25("deploy", 5)
+	if err != nil || len(results) != 2 {
+		t.Fatalf("expected 2 results, got %+v, %v", results, err)
+	}
+	if results[0].ToolName != "deploy" {
+		t.Errorf("name match should rank first by default, got %+v", results)
+	}
+	if !reflect.DeepEqual(results[0].MatchedFields, []string{"name"}) ||
+		!reflect.DeepEqual(results[1].MatchedFields, []string{"description"}) {
+		t.Errorf("unexpected matched fields: %v, %v", results[0].MatchedFields, results[1].MatchedFields)
+	}
+
+	indexer.SetFieldBoosts(FieldBoosts{Name: 1, Description: 10})
+	results, err = indexer.SearchBM25("deploy", 5)
+	if err != nil || len(results) != 2 {
+		t.Fatalf("expected 2 results, got %+v, %v", results, err)
+	}
+	if results[0].ToolName != "run_pipeline" {
+		t.Errorf("description boost should rank run_pipeline first, got %+v", results)
+	}
+}
@@ -0,0 +1,161 @@
+package search
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// maxExampleDepth bounds how deep ExampleArguments follows nested objects
+// and arrays, so recursive schemas stay finite.
+const maxExampleDepth = 5
+
+// exampleFormats are placeholders for well-known string formats.
+var exampleFormats = map[string]string{
+	"date-time": "2025-01-01T00:00:00Z",
+	"date":      "2025-01-01",
+	"time":      "00:00:00Z",
+	"email":     "user@example.com",
+	"uri":       "https://example.com",
+	"url":       "https://example.com",
+	"uuid":      "00000000-0000-0000-0000-000000000000",
+}
+
+// ExampleArguments builds a minimal argument object from a tool's input
+// schema: its required properties, each set to the schema's const,
+// default, first example or first enum value, or else a placeholder of
+// the property's type. Optional properties are left out. It returns nil
+// when the schema is not an object schema.
+func ExampleArguments(schema interface{}) map[string]interface{} {
+	object, ok := schema.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if _, ok := object["properties"]; !ok && schemaType(object) != "object" {
+		return nil
+	}
+	example, _ := exampleObject(object, 0).(map[string]interface{})
+	return example
+}
+
+// exampleValue returns a placeholder value for one property schema.
+func exampleValue(name string, schema map[string]interface{}, depth int) interface{} {
+	if value, ok := schema["const"]; ok {
+		return value
+	}
+	if value, ok := schema["default"]; ok {
+		return value
+	}
+	if values, ok := schema["examples"].([]interface{}); ok && len(values) > 0 {
+		return values[0]
+	}
+	if values, ok := schema["enum"].([]interface{}); ok && len(values) > 0 {
+		return values[0]
+	}
+	for _, key := range []string{"anyOf", "oneOf", "allOf"} {
+		if options, ok := schema[key].([]interface{}); ok && len(options) > 0 {
+			if option, ok := options[0].(map[string]interface{}); ok {
+				return exampleValue(name, option, depth)
+			}
+		}
+	}
+
+	switch schemaType(schema) {
+	case "string":
+		if placeholder, ok := exampleFormats[stringValue(schema["format"])]; ok {
+			return placeholder
+		}
+		return "{" + name + "}"
+	case "integer", "number":
+		if minimum, ok := schema["minimum"].(float64); ok {
+			return minimum
+		}
+		return 0
+	case "boolean":
+		return false
+	case "array":
+		items, _ := schema["items"].(map[string]interface{})
+		if minItems, _ := schema["minItems"].(float64); minItems > 0 && items != nil && depth < maxExampleDepth {
+			return []interface{}{exampleValue(name, items, depth+1)}
+		}
+		return []interface{}{}
+	case "object":
+		return exampleObject(schema, depth)
+	case "null":
+		return nil
+	}
+	return "{" + name + "}"
+}
+
+// exampleObject returns an object with the schema's required properties.
+func exampleObject(schema map[string]interface{}, depth int) interface{} {
+	example := map[string]interface{}{}
+	if depth >= maxExampleDepth {
+		return example
+	}
+	properties, _ := schema["properties"].(map[string]interface{})
+	required, _ := schema["required"].([]interface{})
+	names := make([]string, 0, len(required))
+	for _, name := range required {
+		if name, ok := name.(string); ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		property, _ := properties[name].(map[string]interface{})
+		example[name] = exampleValue(name, property, depth+1)
+	}
+	return example
+}
+
+// schemaType returns a schema's type. Of a list of types, the first that
+// is not "null" is used.
+func schemaType(schema map[string]interface{}) string {
+	switch t := schema["type"].(type) {
+	case string:
+		return t
+	case []interface{}:
+		for _, option := range t {
+			if option, ok := option.(string); ok && option != "null" {
+				return option
+			}
+		}
+	}
+	if _, ok := schema["properties"]; ok {
+		return "object"
+	}
+	return ""
+}
+
+// stringValue returns value if it is a string, or "".
+func stringValue(value interface{}) string {
+	s, _ := value.(string)
+	return s
+}
+
+// encodeExample serializes a tool's example arguments for the index,
+// which stores it as an opaque string.
+func encodeExample(schema interface{}) string {
+	example := ExampleArguments(schema)
+	if example == nil {
+		return ""
+	}
+	data, err := json.Marshal(example)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// decodeExample parses example arguments stored by encodeExample.
+func decodeExample(field interface{}) map[string]interface{} {
+	data, ok := field.(string)
+	if !ok || data == "" {
+		return nil
+	}
+	var example map[string]interface{}
+	if err := json.Unmarshal([]byte(data), &example); err != nil {
+		return nil
+	}
+	return example
+}
@@ -0,0 +1,74 @@
+package search
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/protocol"
+)
+
+func TestExampleArguments(t *testing.T) {
+	var schema map[string]interface{}
+	if err := json.Unmarshal([]byte(`{
+		"type": "object",
+		"required": ["project", "summary", "priority", "due", "count", "labels", "watchers", "fields", "notify"],
+		"properties": {
+			"project": {"type": "string", "examples": ["PROJ"]},
+			"summary": {"type": "string"},
+			"priority": {"type": "string", "enum": ["high", "low"]},
+			"due": {"type": ["string", "null"], "format": "date"},
+			"count": {"type": "integer", "minimum": 1},
+			"labels": {"type": "array", "items": {"type": "string"}},
+			"watchers": {"type": "array", "minItems": 1, "items": {"type": "string", "format": "email"}},
+			"fields": {"type": "object", "required": ["team"], "properties": {"team": {"type": "string"}, "note": {"type": "string"}}},
+			"notify": {"anyOf": [{"type": "boolean"}, {"type": "string"}]},
+			"description": {"type": "string"}
+		}
+	}`), &schema); err != nil {
+		t.Fatal(err)
+	}
+
+	got, _ := json.Marshal(ExampleArguments(schema))
+	want := `{"count":1,"due":"2025-01-01","fields":{"team":"{team}"},"labels":[],"notify":false,"priority":"high","project":"PROJ","summary":"{summary}","watchers":["user@example.com"]}`
+	if string(got) != want {
+		t.Errorf("ExampleArguments =\n%s\nwant\n%s", got, want)
+	}
+
+	if got := ExampleArguments(map[string]interface{}{"type": "object"}); got == nil || len(got) != 0 {
+		t.Errorf("schema without required properties should give an empty object, got %v", got)
+	}
+	if got := ExampleArguments(nil); got != nil {
+		t.Errorf("missing schema should give nil, got %v", got)
+	}
+}
+
+func TestIndexServerStoresExamples(t *testing.T) {
+	indexer, err := NewIndexer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer indexer.Close()
+
+	tools := []protocol.Tool{{
+		Name:        "create_issue",
+		Description: "Create an issue",
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"required":   []interface{}{"summary"},
+			"properties": map[string]interface{}{"summary": map[string]interface{}{"type": "string"}},
+		},
+	}}
+	if err := indexer.IndexServer("jira", tools); err != nil {
+		t.Fatal(err)
+	}
+	if err := indexer.RenameServer("jira", "tickets"); err != nil {
+		t.Fatal(err)
+	}
+	results, err := indexer.SearchBM25("create issue", 5)
+	if err != nil || len(results) != 1 {
+		t.Fatalf("search failed: %v (%d results)", err, len(results))
+	}
+	if got := results[0].Example["summary"]; got != "{summary}" {
+		t.Errorf("expected the stored example to survive a rename, got %v", results[0].Example)
+	}
+}
@@ -0,0 +1,49 @@
+package search
+
+import (
+	"github.com/blevesearch/bleve/v2"
+	bsearch "github.com/blevesearch/bleve/v2/search"
+)
+
+// maxFacetTerms caps the number of servers and tags counted per search.
+const maxFacetTerms = 50
+
+// Facets count the tools matching a search by server and by tag, so that
+// callers can refine a search ("12 results in github, 3 in gitlab")
+// without issuing more searches.
+type Facets struct {
+	Servers map[string]int `json:"servers,omitempty"`
+	Tags    map[string]int `json:"tags,omitempty"`
+}
+
+// addFacetRequests asks bleve to count matches per server and tag.
+func addFacetRequests(req *bleve.SearchRequest) {
+	req.AddFacet("servers", bleve.NewFacetRequest("serverKey", maxFacetTerms))
+	req.AddFacet("tags", bleve.NewFacetRequest("tags", maxFacetTerms))
+}
+
+// convertFacets reads the counts requested by addFacetRequests, or returns
+// nil when nothing matched.
+func convertFacets(results bsearch.FacetResults) *Facets {
+	servers := facetCounts(results["servers"])
+	if len(servers) == 0 {
+		return nil
+	}
+	return &Facets{Servers: servers, Tags: facetCounts(results["tags"])}
+}
+
+// facetCounts maps each term of a facet to its count.
+func facetCounts(result *bsearch.FacetResult) map[string]int {
+	if result == nil || result.Terms == nil {
+		return nil
+	}
+	terms := result.Terms.Terms()
+	if len(terms) == 0 {
+		return nil
+	}
+	counts := make(map[string]int, len(terms))
+	for _, term := range terms {
+		counts[term.Term] = term.Count
+	}
+	return counts
+}
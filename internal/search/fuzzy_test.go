@@ -0,0 +1,109 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/khanglvm/tool-hub-mcp/internal/protocol"
+)
+
+// TestSearchBM25Misspellings measures recall@3 on misspelled queries
+// against a plain match query.
+func TestSearchBM25Misspellings(t *testing.T) {
+	indexer, err := NewIndexer()
+	if err != nil {
+		t.Fatalf("failed to create indexer: %v", err)
+	}
+	defer indexer.Close()
+
+	servers := map[string][]protocol.Tool{
+		"jira": {
+			{Name: "create_issue", Description: "Create a new Jira issue"},
+			{Name: "search_issues", Description: "Search issues using JQL"},
+		},
+		"github": {
+			{Name: "list_repositories", Description: "List repositories for the authenticated user"},
+		},
+		"playwright": {
+			{Name: "browser_screenshot", Description: "Take a screenshot of the current page"},
+		},
+	}
+	for name, tools := range servers {
+		if err := indexer.IndexServer(name, tools); err != nil {
+			t.Fatalf("failed to index %s: %v", name, err)
+		}
+	}
+
+	cases := []struct {
+		query string
+		want  string
+	}{
+		{"jirra create isue", "create_issue"},
+		{"serch isues", "search_issues"},
+		{"list repositorys", "list_repositories"},
+		{"screenshoot", "browser_screenshot"},
+		{"screensh", "browser_screenshot"},
+	}
+
+	recall := func(search func(q string) []SearchResult) int {
+		found := 0
+		for _, c := range cases {
+			results := search(c.query)
+			for i := 0; i < len(results) && i < 3; i++ {
+				if results[i].ToolName == c.want {
+					found++
+					break
+				}
+			}
+		}
+		return found
+	}
+
+	fuzzy := recall(func(q string) []SearchResult {
+		results, err := indexer.SearchBM25(q, 10)
+		if err != nil {
+			t.Fatalf("search failed: %v", err)
+		}
+		return results
+	})
+	plain := recall(func(q string) []SearchResult {
+		req := bleve.NewSearchRequestOptions(bleve.NewMatchQuery(q), 10, 0, false)
+		req.Fields = []string{"name", "description", "server"}
+		results, err := indexer.bleveIndex.Search(req)
+		if err != nil {
+			t.Fatalf("search failed: %v", err)
+		}
+		return convertBleveResults(results)
+	})
+
+	if fuzzy != len(cases) {
+		t.Errorf("recall@3 on misspelled queries = %d/%d, want all", fuzzy, len(cases))
+	}
+	if fuzzy <= plain {
+		t.Errorf("fuzzy recall %d should beat plain match recall %d", fuzzy, plain)
+	}
+}
+
+func TestSearchBM25ExactBeforeFuzzy(t *testing.T) {
+	indexer, err := NewIndexer()
+	if err != nil {
+		t.Fatalf("failed to create indexer: %v", err)
+	}
+	defer indexer.Close()
+
+	tools := []protocol.Tool{
+		{Name: "get_page", Description: "Get a page"},
+		{Name: "get_pages", Description: "Get pages"},
+	}
+	if err := indexer.IndexServer("docs", tools); err != nil {
+		t.Fatalf("failed to index server: %v", err)
+	}
+
+	results, err := indexer.SearchBM25("get_pages", 5)
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if len(results) < 2 || results[0].ToolName != "get_pages" {
+		t.Errorf("exact name should rank first, got %+v", results)
+	}
+}
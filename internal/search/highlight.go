@@ -0,0 +1,81 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/registry"
+	"github.com/blevesearch/bleve/v2/search/highlight"
+	simplefragmenter "github.com/blevesearch/bleve/v2/search/highlight/fragmenter/simple"
+	simplehighlighter "github.com/blevesearch/bleve/v2/search/highlight/highlighter/simple"
+)
+
+// highlightStyle names the highlighter that marks matched terms in bold
+// Markdown (**term**), which reads well in JSON, unlike HTML or ANSI.
+const highlightStyle = "toolhubMarkdown"
+
+// highlightFields are the fields whose matched fragments are returned.
+var highlightFields = []string{"name", "description"}
+
+func init() {
+	err := registry.RegisterHighlighter(highlightStyle, func(config map[string]interface{}, cache *registry.Cache) (highlight.Highlighter, error) {
+		fragmenter, err := cache.FragmenterNamed(simplefragmenter.Name)
+		if err != nil {
+			return nil, fmt.Errorf("error building fragmenter: %v", err)
+		}
+		return simplehighlighter.NewHighlighter(fragmenter, markdownFormatter{}, simplehighlighter.DefaultSeparator), nil
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+// addHighlightRequest asks bleve for the matched fragments of a search.
+func addHighlightRequest(req *bleve.SearchRequest) {
+	req.Highlight = bleve.NewHighlightWithStyle(highlightStyle)
+	for _, field := range highlightFields {
+		req.Highlight.AddField(field)
+	}
+}
+
+// markdownFormatter formats a fragment with its matched terms in bold.
+type markdownFormatter struct{}
+
+// Format implements highlight.FragmentFormatter.
+func (markdownFormatter) Format(f *highlight.Fragment, locations highlight.TermLocations) string {
+	var b strings.Builder
+	curr := f.Start
+	for _, location := range locations {
+		if location == nil || !location.ArrayPositions.Equals(f.ArrayPositions) || location.Start < curr {
+			continue
+		}
+		if location.End > f.End {
+			break
+		}
+		b.Write(f.Orig[curr:location.Start])
+		b.WriteString("**")
+		b.Write(f.Orig[location.Start:location.End])
+		b.WriteString("**")
+		curr = location.End
+	}
+	b.Write(f.Orig[curr:f.End])
+	return b.String()
+}
+
+// convertHighlights keeps the fragments that mark a match, by field.
+func convertHighlights(fragments map[string][]string) map[string][]string {
+	var highlights map[string][]string
+	for _, field := range highlightFields {
+		for _, fragment := range fragments[field] {
+			if !strings.Contains(fragment, "**") {
+				continue
+			}
+			if highlights == nil {
+				highlights = make(map[string][]string)
+			}
+			highlights[field] = append(highlights[field], strings.TrimSpace(fragment))
+		}
+	}
+	return highlights
+}
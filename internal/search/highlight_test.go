@@ -0,0 +1,31 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/protocol"
+)
+
+func TestSearchHighlights(t *testing.T) {
+	indexer, err := NewIndexer()
+	if err != nil {
+		t.Fatalf("failed to create indexer: %v", err)
+	}
+	defer indexer.Close()
+
+	indexer.IndexServer("jira", []protocol.Tool{
+		{Name: "create_issue", Description: `Create a "bug" or story issue in a Jira project`},
+	})
+
+	results, err := indexer.SearchBM25("story", 5)
+	if err != nil || len(results) != 1 {
+		t.Fatalf("search failed: %v, %+v", err, results)
+	}
+	got := results[0].Highlights
+	if len(got["description"]) != 1 || got["description"][0] != `Create a "bug" or **story** issue in a Jira project` {
+		t.Errorf("unexpected description highlights: %q", got["description"])
+	}
+	if _, ok := got["name"]; ok {
+		t.Errorf("name did not match and should not be highlighted: %q", got["name"])
+	}
+}
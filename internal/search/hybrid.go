@@ -126,6 +126,7 @@ func fuseScores(bm25Results, semanticResults []SearchResult, config FusionConfig
 			ToolName:    baseResult.ToolName,
 			Description: baseResult.Description,
 			InputSchema: baseResult.InputSchema,
+			Example:     baseResult.Example,
 			ServerName:  baseResult.ServerName,
 			Score:       fusedScore,
 		}
@@ -5,13 +5,16 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/blevesearch/bleve/v2"
 	"github.com/blevesearch/bleve/v2/index/scorch"
 	"github.com/blevesearch/bleve/v2/mapping"
+	bsearch "github.com/blevesearch/bleve/v2/search"
 	"github.com/blevesearch/bleve/v2/search/query"
-	"github.com/khanglvm/tool-hub-mcp/internal/spawner"
+	"github.com/khanglvm/tool-hub-mcp/internal/protocol"
 )
 
 // Indexer manages the search index for all tools.
@@ -19,12 +22,38 @@ type Indexer struct {
 	bleveIndex bleve.Index
 	mu         sync.RWMutex
 	indexPath  string
+	expander   *Expander
+	boosts     FieldBoosts
+	// maxDescription caps indexed descriptions in characters (0: no cap)
+	maxDescription int
+	// rebuilt is set when a persisted index was discarded at open, and
+	// rebuiltFrom is the schema version it had
+	rebuilt     bool
+	rebuiltFrom int
+	// language selects the text analyzers (see SetLanguage)
+	language string
 }
 
+// SchemaVersion identifies the index mapping and document layout. Bump it
+// whenever buildIndexMapping or the indexed documents change, so that
+// persisted indexes built for an older layout are rebuilt at open.
+const SchemaVersion = 4
+
+// schemaVersionKey stores SchemaVersion in the index's internal metadata,
+// which is kept apart from the tool documents.
+var schemaVersionKey = []byte("toolhub.schemaVersion")
+
+// languageKey stores the language a persisted index was built for.
+var languageKey = []byte("toolhub.language")
+
+// openConfig makes opening an index held by another process fail after a
+// second instead of blocking, as bolt locks the index file.
+var openConfig = map[string]interface{}{"bolt_timeout": "1s"}
+
 // NewIndexer creates a new search indexer with in-memory Bleve index.
 func NewIndexer() (*Indexer, error) {
 	// Use scorch (modern, fast index) with in-memory storage
-	indexMapping := buildIndexMapping()
+	indexMapping := buildIndexMapping(LanguageAuto)
 
 	// Create in-memory index for fast startup
 	index, err := bleve.NewMemOnly(indexMapping)
@@ -35,6 +64,9 @@ func NewIndexer() (*Indexer, error) {
 	return &Indexer{
 		bleveIndex: index,
 		indexPath:  "",
+		expander:   NewExpander(nil),
+		boosts:     DefaultFieldBoosts,
+		language:   LanguageAuto,
 	}, nil
 }
 
@@ -45,75 +77,189 @@ func NewIndexerWithPath(indexPath string) (*Indexer, error) {
 		return nil, fmt.Errorf("failed to create index directory: %w", err)
 	}
 
-	indexMapping := buildIndexMapping()
-
 	// Open or create index with Scorch backend
-	index, err := bleve.NewUsing(indexPath, indexMapping, scorch.Name, scorch.Name, nil)
+	rebuilt := false
+	rebuiltFrom := 0
+	language := LanguageAuto
+	index, err := createIndex(indexPath, language)
 	if err != nil {
 		// If index exists, open it
-		index, err = bleve.Open(indexPath)
+		index, err = bleve.OpenUsing(indexPath, openConfig)
 		if err != nil {
 			return nil, fmt.Errorf("failed to open/create index: %w", err)
 		}
+
+		// An index built with another mapping is discarded and recreated
+		if version := storedSchemaVersion(index); version != SchemaVersion {
+			log.Printf("Index at %s has schema version %d, rebuilding for version %d", indexPath, version, SchemaVersion)
+			index.Close()
+			if err := os.RemoveAll(indexPath); err != nil {
+				return nil, fmt.Errorf("failed to remove outdated index: %w", err)
+			}
+			if index, err = createIndex(indexPath, language); err != nil {
+				return nil, fmt.Errorf("failed to rebuild index: %w", err)
+			}
+			rebuilt = true
+			rebuiltFrom = version
+		} else if stored, err := index.GetInternal(languageKey); err == nil {
+			language = normalizeLanguage(string(stored))
+		}
 	}
 
 	return &Indexer{
-		bleveIndex: index,
-		indexPath:  indexPath,
+		bleveIndex:  index,
+		indexPath:   indexPath,
+		expander:    NewExpander(nil),
+		boosts:      DefaultFieldBoosts,
+		rebuilt:     rebuilt,
+		rebuiltFrom: rebuiltFrom,
+		language:    language,
 	}, nil
 }
 
-// buildIndexMapping creates the Bleve index mapping.
-func buildIndexMapping() mapping.IndexMapping {
+// createIndex creates a persistent index with the current mapping for
+// language, and records its schema version and language.
+func createIndex(indexPath, language string) (bleve.Index, error) {
+	index, err := bleve.NewUsing(indexPath, buildIndexMapping(language), scorch.Name, scorch.Name, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := index.SetInternal(schemaVersionKey, []byte(strconv.Itoa(SchemaVersion))); err != nil {
+		index.Close()
+		return nil, fmt.Errorf("failed to record schema version: %w", err)
+	}
+	if err := index.SetInternal(languageKey, []byte(language)); err != nil {
+		index.Close()
+		return nil, fmt.Errorf("failed to record language: %w", err)
+	}
+	return index, nil
+}
+
+// storedSchemaVersion returns the schema version recorded in an index, or
+// 0 for indexes created before versioning.
+func storedSchemaVersion(index bleve.Index) int {
+	data, err := index.GetInternal(schemaVersionKey)
+	if err != nil {
+		return 0
+	}
+	version, _ := strconv.Atoi(string(data))
+	return version
+}
+
+// Rebuilt reports whether a persisted index was discarded at open because
+// its schema version differs from SchemaVersion. Its tools must be indexed
+// again, from the metadata cache or live discovery.
+func (i *Indexer) Rebuilt() bool {
+	return i.rebuilt
+}
+
+// RebuiltFrom returns the schema version of the index discarded at open,
+// 0 for indexes created before versioning (see Rebuilt).
+func (i *Indexer) RebuiltFrom() int {
+	return i.rebuiltFrom
+}
+
+// buildIndexMapping creates the Bleve index mapping, with the text
+// analyzers of language.
+func buildIndexMapping(language string) mapping.IndexMapping {
 	// Create a mapping for tool documents
 	toolMapping := bleve.NewDocumentMapping()
 
 	// Name field: searchable text
 	nameFieldMapping := bleve.NewTextFieldMapping()
+	nameFieldMapping.Analyzer = textAnalyzer(language)
 	toolMapping.AddFieldMappingsAt("name", nameFieldMapping)
 
 	// Description field: searchable text
 	descFieldMapping := bleve.NewTextFieldMapping()
+	descFieldMapping.Analyzer = textAnalyzer(language)
 	toolMapping.AddFieldMappingsAt("description", descFieldMapping)
 
 	// Server field: searchable text for filtering
 	serverFieldMapping := bleve.NewTextFieldMapping()
 	toolMapping.AddFieldMappingsAt("server", serverFieldMapping)
 
+	// serverKey: the exact server name, for Filter.Servers
+	serverKeyMapping := bleve.NewKeywordFieldMapping()
+	serverKeyMapping.Store = false
+	serverKeyMapping.IncludeInAll = false
+	toolMapping.AddFieldMappingsAt("serverKey", serverKeyMapping)
+
+	// tags: the server's tags (lowercase keywords), for Filter.Tags
+	tagsMapping := bleve.NewKeywordFieldMapping()
+	tagsMapping.IncludeInAll = false
+	toolMapping.AddFieldMappingsAt("tags", tagsMapping)
+
 	// InputSchema: stored but not indexed (for retrieval)
 	inputSchemaMapping := bleve.NewTextFieldMapping()
 	inputSchemaMapping.Index = false
 	inputSchemaMapping.IncludeInAll = false
 	toolMapping.AddFieldMappingsAt("inputSchema", inputSchemaMapping)
 
+	// example: synthesized example arguments (JSON), stored only
+	exampleMapping := bleve.NewTextFieldMapping()
+	exampleMapping.Index = false
+	exampleMapping.IncludeInAll = false
+	toolMapping.AddFieldMappingsAt("example", exampleMapping)
+
 	// Create index mapping
 	indexMapping := bleve.NewIndexMapping()
+	addLanguageMappings(indexMapping, toolMapping, language)
 	indexMapping.AddDocumentMapping("_default", toolMapping)
 
 	return indexMapping
 }
 
-// IndexServer indexes all tools from a server.
-func (i *Indexer) IndexServer(serverName string, tools []spawner.Tool) error {
+// resultFields are the stored fields loaded into a SearchResult.
+var resultFields = []string{"name", "description", "server", "inputSchema", "example", "tags"}
+
+// IndexServer indexes all tools from a server, labelled with the server's
+// tags (see Filter).
+func (i *Indexer) IndexServer(serverName string, tools []protocol.Tool, tags ...string) error {
 	i.mu.Lock()
 	defer i.mu.Unlock()
 
+	// Tools the server no longer lists are dropped in the same batch
+	previous, err := i.serverDocs(serverName, nil)
+	if err != nil {
+		return err
+	}
+	stale := make(map[string]bool, len(previous))
+	for _, hit := range previous {
+		stale[hit.ID] = true
+	}
+
 	batch := i.bleveIndex.NewBatch()
+	tags = normalizeTags(tags)
 
 	for _, tool := range tools {
+		description := SummarizeDescription(tool.Description, schemaProperties(tool.InputSchema), i.maxDescription)
 		doc := map[string]interface{}{
 			"name":        tool.Name,
-			"description": tool.Description,
+			"description": description,
 			"server":      serverName,
+			"serverKey":   serverName,
 			"inputSchema": tool.InputSchema,
 		}
+		if example := encodeExample(tool.InputSchema); example != "" {
+			doc["example"] = example
+		}
+		if len(tags) > 0 {
+			doc["tags"] = tags
+		}
+		i.addLanguageFields(doc)
 
 		// Use serverName/toolName as document ID
 		docID := fmt.Sprintf("%s/%s", serverName, tool.Name)
 
 		if err := batch.Index(docID, doc); err != nil {
 			log.Printf("Warning: failed to index tool %s: %v", docID, err)
+			continue
 		}
+		delete(stale, docID)
+	}
+	for docID := range stale {
+		batch.Delete(docID)
 	}
 
 	if err := i.bleveIndex.Batch(batch); err != nil {
@@ -128,17 +274,13 @@ func (i *Indexer) RemoveServer(serverName string) error {
 	i.mu.Lock()
 	defer i.mu.Unlock()
 
-	// Delete all documents with this server prefix
-	query := bleve.NewWildcardQuery(fmt.Sprintf("%s/*", serverName))
-	searchRequest := bleve.NewSearchRequestOptions(query, 1000, 0, false)
-
-	results, err := i.bleveIndex.Search(searchRequest)
+	docs, err := i.serverDocs(serverName, nil)
 	if err != nil {
-		return fmt.Errorf("failed to find server docs: %w", err)
+		return err
 	}
 
 	batch := i.bleveIndex.NewBatch()
-	for _, hit := range results.Hits {
+	for _, hit := range docs {
 		batch.Delete(hit.ID)
 	}
 
@@ -149,6 +291,78 @@ func (i *Indexer) RemoveServer(serverName string) error {
 	return nil
 }
 
+// RenameServer moves a server's tools to a new name, re-keying their
+// document IDs (server/tool) in a single batch so searches never see a
+// mix of both names.
+func (i *Indexer) RenameServer(oldName, newName string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	docs, err := i.serverDocs(oldName, resultFields)
+	if err != nil {
+		return err
+	}
+
+	batch := i.bleveIndex.NewBatch()
+	for _, hit := range docs {
+		name, _ := hit.Fields["name"].(string)
+		doc := map[string]interface{}{
+			"name":        name,
+			"description": hit.Fields["description"],
+			"server":      newName,
+			"serverKey":   newName,
+			"inputSchema": hit.Fields["inputSchema"],
+		}
+		if example, ok := hit.Fields["example"].(string); ok {
+			doc["example"] = example
+		}
+		if tags := fieldStrings(hit.Fields["tags"]); len(tags) > 0 {
+			doc["tags"] = tags
+		}
+		i.addLanguageFields(doc)
+		batch.Delete(hit.ID)
+		if err := batch.Index(fmt.Sprintf("%s/%s", newName, name), doc); err != nil {
+			return fmt.Errorf("failed to re-key %s: %w", hit.ID, err)
+		}
+	}
+
+	if err := i.bleveIndex.Batch(batch); err != nil {
+		return fmt.Errorf("failed to batch rename: %w", err)
+	}
+	return nil
+}
+
+// serverDocs returns every indexed tool of a server, loading the given
+// stored fields. Documents are matched by exact server name on the
+// serverKey keyword field and by their "server/" ID prefix, so servers
+// whose names contain one another never match (caller must hold lock).
+func (i *Indexer) serverDocs(serverName string, fields []string) ([]*bsearch.DocumentMatch, error) {
+	count, err := i.bleveIndex.DocCount()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get doc count: %w", err)
+	}
+	if count == 0 {
+		return nil, nil
+	}
+
+	serverQuery := bleve.NewTermQuery(serverName)
+	serverQuery.SetField("serverKey")
+	searchRequest := bleve.NewSearchRequestOptions(serverQuery, int(count), 0, false)
+	searchRequest.Fields = fields
+	results, err := i.bleveIndex.Search(searchRequest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find server docs: %w", err)
+	}
+
+	docs := make([]*bsearch.DocumentMatch, 0, len(results.Hits))
+	for _, hit := range results.Hits {
+		if strings.HasPrefix(hit.ID, serverName+"/") {
+			docs = append(docs, hit)
+		}
+	}
+	return docs, nil
+}
+
 // Count returns the total number of indexed tools.
 func (i *Indexer) Count() (uint64, error) {
 	i.mu.RLock()
@@ -174,8 +388,131 @@ func (i *Indexer) Close() error {
 	return nil
 }
 
-// buildMatchQuery creates a match query for BM25 search.
+// SetSynonyms replaces the user-defined query synonyms; the built-in
+// table always applies.
+func (i *Indexer) SetSynonyms(extra map[string][]string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.expander = NewExpander(extra)
+}
+
+// SetMaxDescriptionLength caps the length of descriptions indexed from
+// now on (see SummarizeDescription). Zero or negative disables the cap.
+func (i *Indexer) SetMaxDescriptionLength(max int) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.maxDescription = max
+}
+
+// FieldBoosts weights matches per field. Bleve has no index-time field
+// boosts, so they are applied to the per-field clauses of every query,
+// which ranks the same as boosting the indexed fields.
+type FieldBoosts struct {
+	Name        float64
+	Description float64
+	Server      float64
+}
+
+// DefaultFieldBoosts makes a hit in the tool name outweigh one in the
+// description or server name.
+var DefaultFieldBoosts = FieldBoosts{Name: 3, Description: 1, Server: 1}
+
+// Relative weight of approximate matches, so exact terms rank first.
+const (
+	fuzzyWeight  = 0.5
+	prefixWeight = 0.3
+)
+
+// SetFieldBoosts sets the per-field match weights. Zero or negative
+// weights keep the default for that field.
+func (i *Indexer) SetFieldBoosts(b FieldBoosts) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	pick := func(v, def float64) float64 {
+		if v > 0 {
+			return v
+		}
+		return def
+	}
+	i.boosts = FieldBoosts{
+		Name:        pick(b.Name, DefaultFieldBoosts.Name),
+		Description: pick(b.Description, DefaultFieldBoosts.Description),
+		Server:      pick(b.Server, DefaultFieldBoosts.Server),
+	}
+}
+
+// boostedField is an indexed field searched with a ranking weight.
+type boostedField struct {
+	name  string
+	boost float64
+}
+
+// buildMatchQuery creates the BM25 query: a disjunction of exact matches on
+// the synonym-expanded text plus fuzzy (typo-tolerant) and prefix matches
+// on the original words, each boosted per field.
 func (i *Indexer) buildMatchQuery(searchText string) query.Query {
-	// Use match query with fuzzy matching
-	return bleve.NewMatchQuery(searchText)
+	expanded := i.expander.Expand(searchText)
+	boosts := i.boosts
+	if boosts == (FieldBoosts{}) {
+		boosts = DefaultFieldBoosts
+	}
+
+	fields := []boostedField{
+		{"name", boosts.Name},
+		{"description", boosts.Description},
+		{"server", boosts.Server},
+	}
+
+	// Queries in CJK scripts also search the CJK bigram fields
+	matchFields := fields
+	if i.language == LanguageAuto && containsCJK(searchText) {
+		matchFields = append(matchFields,
+			boostedField{cjkFields["name"], boosts.Name},
+			boostedField{cjkFields["description"], boosts.Description})
+	}
+
+	var queries []query.Query
+	for _, f := range matchFields {
+		match := bleve.NewMatchQuery(expanded)
+		match.SetField(f.name)
+		match.SetBoost(f.boost)
+		queries = append(queries, match)
+	}
+
+	for _, word := range queryWords(searchText) {
+		for _, f := range fields {
+			if fuzziness := fuzzinessFor(word); fuzziness > 0 {
+				fuzzy := bleve.NewFuzzyQuery(word)
+				fuzzy.SetField(f.name)
+				fuzzy.SetFuzziness(fuzziness)
+				fuzzy.SetBoost(f.boost * fuzzyWeight)
+				queries = append(queries, fuzzy)
+			}
+			if len(word) >= 3 {
+				prefix := bleve.NewPrefixQuery(word)
+				prefix.SetField(f.name)
+				prefix.SetBoost(f.boost * prefixWeight)
+				queries = append(queries, prefix)
+			}
+		}
+	}
+
+	return bleve.NewDisjunctionQuery(queries...)
+}
+
+// fuzzinessFor returns the edit distance tolerated for a query word:
+// none for short words, where one edit changes the meaning, 1 for
+// medium words and 2 for long ones.
+func fuzzinessFor(word string) int {
+	switch n := len([]rune(word)); {
+	case n < 4:
+		return 0
+	case n < 7:
+		return 1
+	default:
+		return 2
+	}
 }
@@ -1,9 +1,12 @@
 package search
 
 import (
+	"errors"
+	"fmt"
+	"path/filepath"
 	"testing"
 
-	"github.com/khanglvm/tool-hub-mcp/internal/spawner"
+	"github.com/khanglvm/tool-hub-mcp/internal/protocol"
 )
 
 func TestNewIndexer(t *testing.T) {
@@ -26,7 +29,7 @@ func TestIndexServer(t *testing.T) {
 	defer indexer.Close()
 
 	// Create mock tools
-	tools := []spawner.Tool{
+	tools := []protocol.Tool{
 		{
 			Name:        "test_tool",
 			Description: "A test tool for searching",
@@ -68,7 +71,7 @@ func TestSearchBM25(t *testing.T) {
 	defer indexer.Close()
 
 	// Index test tools
-	tools := []spawner.Tool{
+	tools := []protocol.Tool{
 		{
 			Name:        "create_jira_ticket",
 			Description: "Create a new Jira ticket",
@@ -135,7 +138,7 @@ func TestSearchBM25NoResults(t *testing.T) {
 	defer indexer.Close()
 
 	// Index test tools
-	tools := []spawner.Tool{
+	tools := []protocol.Tool{
 		{
 			Name:        "create_ticket",
 			Description: "Create a ticket",
@@ -169,7 +172,7 @@ func TestGetAllTools(t *testing.T) {
 	defer indexer.Close()
 
 	// Index test tools
-	tools := []spawner.Tool{
+	tools := []protocol.Tool{
 		{
 			Name:        "tool1",
 			Description: "Tool 1",
@@ -201,3 +204,198 @@ func TestGetAllTools(t *testing.T) {
 		t.Errorf("expected 2 tools, got %d", len(results))
 	}
 }
+
+func TestEachTool(t *testing.T) {
+	indexer, err := NewIndexer()
+	if err != nil {
+		t.Fatalf("failed to create indexer: %v", err)
+	}
+	defer indexer.Close()
+
+	// More tools than GetAllTools' default cap, read 7 at a time
+	tools := make([]protocol.Tool, 150)
+	for n := range tools {
+		tools[n] = protocol.Tool{Name: fmt.Sprintf("tool_%03d", n), Description: "Tool"}
+	}
+	if err := indexer.IndexServer("big", tools); err != nil {
+		t.Fatalf("failed to index server: %v", err)
+	}
+
+	var names []string
+	err = indexer.EachTool(7, func(r SearchResult) error {
+		names = append(names, r.ToolName)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("EachTool failed: %v", err)
+	}
+	if len(names) != len(tools) {
+		t.Fatalf("expected %d tools, got %d", len(tools), len(names))
+	}
+	for n, name := range names {
+		if name != tools[n].Name {
+			t.Fatalf("tool %d = %s, want %s (ID order)", n, name, tools[n].Name)
+		}
+	}
+
+	stop := errors.New("stop")
+	visited := 0
+	err = indexer.EachTool(7, func(SearchResult) error {
+		visited++
+		return stop
+	})
+	if err != stop || visited != 1 {
+		t.Errorf("expected iteration to stop at the first error, got %v after %d tools", err, visited)
+	}
+}
+
+func TestRemoveServer(t *testing.T) {
+	indexer, err := NewIndexer()
+	if err != nil {
+		t.Fatalf("failed to create indexer: %v", err)
+	}
+	defer indexer.Close()
+
+	// "git" is a prefix of "github", and gitlab's description mentions it
+	indexer.IndexServer("git", []protocol.Tool{{Name: "commit", Description: "Commit changes"}})
+	indexer.IndexServer("github", []protocol.Tool{{Name: "create_pr", Description: "Open a pull request"}})
+	indexer.IndexServer("gitlab", []protocol.Tool{{Name: "mirror", Description: "Mirror git/commit history"}})
+
+	// More tools than a single default-sized search returns
+	many := make([]protocol.Tool, 1200)
+	for n := range many {
+		many[n] = protocol.Tool{Name: fmt.Sprintf("tool_%04d", n), Description: "Tool"}
+	}
+	indexer.IndexServer("big", many)
+
+	if err := indexer.RemoveServer("git"); err != nil {
+		t.Fatalf("RemoveServer failed: %v", err)
+	}
+	if err := indexer.RemoveServer("big"); err != nil {
+		t.Fatalf("RemoveServer failed: %v", err)
+	}
+
+	var ids []string
+	indexer.EachTool(0, func(r SearchResult) error {
+		ids = append(ids, toolID(r))
+		return nil
+	})
+	if fmt.Sprint(ids) != "[github/create_pr gitlab/mirror]" {
+		t.Errorf("remaining tools = %v, want github and gitlab only", ids)
+	}
+}
+
+func TestIndexServerDropsRemovedTools(t *testing.T) {
+	indexer, err := NewIndexer()
+	if err != nil {
+		t.Fatalf("failed to create indexer: %v", err)
+	}
+	defer indexer.Close()
+
+	indexer.IndexServer("jira", []protocol.Tool{{Name: "search"}, {Name: "legacy_search"}})
+	indexer.IndexServer("jiraCloud", []protocol.Tool{{Name: "legacy_search"}})
+	indexer.IndexServer("jira", []protocol.Tool{{Name: "search"}})
+
+	var ids []string
+	indexer.EachTool(0, func(r SearchResult) error {
+		ids = append(ids, toolID(r))
+		return nil
+	})
+	if fmt.Sprint(ids) != "[jira/search jiraCloud/legacy_search]" {
+		t.Errorf("indexed tools = %v, want the dropped jira tool removed", ids)
+	}
+}
+
+// toolID formats a result as its "server/tool" document ID.
+func toolID(r SearchResult) string {
+	return r.ServerName + "/" + r.ToolName
+}
+
+func TestRenameServer(t *testing.T) {
+	indexer, err := NewIndexer()
+	if err != nil {
+		t.Fatalf("failed to create indexer: %v", err)
+	}
+	defer indexer.Close()
+
+	tools := []protocol.Tool{{Name: "create_ticket", Description: "Create a Jira ticket"}}
+	if err := indexer.IndexServer("jira", tools); err != nil {
+		t.Fatalf("failed to index server: %v", err)
+	}
+	if err := indexer.IndexServer("jiraLegacy", tools); err != nil {
+		t.Fatalf("failed to index server: %v", err)
+	}
+
+	if err := indexer.RenameServer("jira", "jiraCloud"); err != nil {
+		t.Fatalf("RenameServer failed: %v", err)
+	}
+
+	results, err := indexer.SearchBM25("ticket", 10)
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	servers := map[string]bool{}
+	for _, r := range results {
+		servers[r.ServerName] = true
+	}
+	if !servers["jiraCloud"] || !servers["jiraLegacy"] || servers["jira"] {
+		t.Errorf("unexpected servers after rename: %v", servers)
+	}
+}
+
+func TestNewIndexerWithPathRebuildsOutdatedSchema(t *testing.T) {
+	indexPath := filepath.Join(t.TempDir(), "index")
+
+	indexer, err := NewIndexerWithPath(indexPath)
+	if err != nil {
+		t.Fatalf("failed to create indexer: %v", err)
+	}
+	indexer.IndexServer("jira", []protocol.Tool{{Name: "search", Description: "Search issues"}})
+	indexer.Close()
+
+	// Reopening an up-to-date index keeps its tools
+	indexer, err = NewIndexerWithPath(indexPath)
+	if err != nil {
+		t.Fatalf("failed to reopen indexer: %v", err)
+	}
+	if count, _ := indexer.Count(); count != 1 || indexer.Rebuilt() {
+		t.Errorf("expected the index to be kept, got %d tools, rebuilt=%v", count, indexer.Rebuilt())
+	}
+
+	// Simulate an index written by an older release
+	indexer.bleveIndex.SetInternal(schemaVersionKey, []byte("1"))
+	indexer.Close()
+
+	indexer, err = NewIndexerWithPath(indexPath)
+	if err != nil {
+		t.Fatalf("failed to reopen outdated indexer: %v", err)
+	}
+	if count, _ := indexer.Count(); count != 0 || !indexer.Rebuilt() || indexer.RebuiltFrom() != 1 {
+		t.Errorf("expected an empty index rebuilt from version 1, got %d tools, rebuilt=%v from %d", count, indexer.Rebuilt(), indexer.RebuiltFrom())
+	}
+	if version := storedSchemaVersion(indexer.bleveIndex); version != SchemaVersion {
+		t.Errorf("rebuilt index has schema version %d, want %d", version, SchemaVersion)
+	}
+
+	// Version 3 indexes were written before argument examples were stored
+	indexer.bleveIndex.SetInternal(schemaVersionKey, []byte("3"))
+	indexer.Close()
+	indexer, err = NewIndexerWithPath(indexPath)
+	if err != nil {
+		t.Fatalf("failed to reopen version 3 indexer: %v", err)
+	}
+	defer indexer.Close()
+	if !indexer.Rebuilt() || indexer.RebuiltFrom() != 3 {
+		t.Errorf("expected a version 3 index to be rebuilt, rebuilt=%v from %d", indexer.Rebuilt(), indexer.RebuiltFrom())
+	}
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{"key": map[string]interface{}{"type": "string"}},
+		"required":   []interface{}{"key"},
+	}
+	indexer.IndexServer("jira", []protocol.Tool{{Name: "get_issue", Description: "Get an issue", InputSchema: schema}})
+	results, err := indexer.GetAllTools(10)
+	if err != nil || len(results) != 1 || results[0].Example["key"] == nil {
+		t.Errorf("expected the rebuilt index to store examples, got %+v (%v)", results, err)
+	}
+}
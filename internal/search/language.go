@@ -0,0 +1,156 @@
+package search
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"unicode"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/analysis/analyzer/custom"
+	"github.com/blevesearch/bleve/v2/analysis/analyzer/standard"
+	"github.com/blevesearch/bleve/v2/analysis/lang/cjk"
+	"github.com/blevesearch/bleve/v2/analysis/token/lowercase"
+	unicodetokenizer "github.com/blevesearch/bleve/v2/analysis/tokenizer/unicode"
+	"github.com/blevesearch/bleve/v2/mapping"
+)
+
+// Languages select how the name and description of tools are tokenized.
+const (
+	// LanguageAuto uses the standard analyzer, and also indexes tools
+	// with Chinese, Japanese or Korean text in CJK bigrams, which queries
+	// in those scripts then search.
+	LanguageAuto = "auto"
+
+	// LanguageStandard splits words on Unicode boundaries, lowercases them
+	// and drops English stop words.
+	LanguageStandard = "standard"
+
+	// LanguageCJK indexes all text in CJK bigrams.
+	LanguageCJK = "cjk"
+
+	// LanguageUnicode is LanguageStandard keeping every word, for
+	// languages whose words are English stop words.
+	LanguageUnicode = "unicode"
+)
+
+// unicodeAnalyzer names the custom analyzer of LanguageUnicode.
+const unicodeAnalyzer = "unicodeLower"
+
+// cjkFields map the analyzed text fields to their CJK bigram copies,
+// indexed under LanguageAuto for tools with CJK text.
+var cjkFields = map[string]string{
+	"name":        "nameCJK",
+	"description": "descriptionCJK",
+}
+
+// normalizeLanguage maps unknown and empty languages to LanguageAuto.
+func normalizeLanguage(language string) string {
+	switch language {
+	case LanguageStandard, LanguageCJK, LanguageUnicode:
+		return language
+	}
+	return LanguageAuto
+}
+
+// textAnalyzer returns the analyzer of the name and description fields.
+func textAnalyzer(language string) string {
+	switch language {
+	case LanguageCJK:
+		return cjk.AnalyzerName
+	case LanguageUnicode:
+		return unicodeAnalyzer
+	}
+	return standard.Name
+}
+
+// addLanguageMappings configures the analyzers of language on an index
+// mapping whose tool documents are described by toolMapping.
+func addLanguageMappings(indexMapping *mapping.IndexMappingImpl, toolMapping *mapping.DocumentMapping, language string) {
+	if language == LanguageUnicode {
+		indexMapping.AddCustomAnalyzer(unicodeAnalyzer, map[string]interface{}{
+			"type":          custom.Name,
+			"tokenizer":     unicodetokenizer.Name,
+			"token_filters": []string{lowercase.Name},
+		})
+	}
+	if language != LanguageAuto {
+		return
+	}
+	for _, field := range cjkFields {
+		cjkMapping := bleve.NewTextFieldMapping()
+		cjkMapping.Analyzer = cjk.AnalyzerName
+		cjkMapping.Store = false
+		cjkMapping.IncludeInAll = false
+		toolMapping.AddFieldMappingsAt(field, cjkMapping)
+	}
+}
+
+// addLanguageFields copies the text of a tool document with CJK text to
+// its CJK bigram fields, under LanguageAuto.
+func (i *Indexer) addLanguageFields(doc map[string]interface{}) {
+	if i.language != LanguageAuto {
+		return
+	}
+	for field, cjkField := range cjkFields {
+		if text, _ := doc[field].(string); containsCJK(text) {
+			doc[cjkField] = text
+		}
+	}
+}
+
+// containsCJK reports whether text has Chinese, Japanese or Korean
+// characters, which the standard analyzer cannot split into words.
+func containsCJK(text string) bool {
+	for _, r := range text {
+		if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) {
+			return true
+		}
+	}
+	return false
+}
+
+// Language returns the index language (see SetLanguage).
+func (i *Indexer) Language() string {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.language
+}
+
+// SetLanguage selects how tool text is tokenized: LanguageAuto (also for
+// unknown values), LanguageStandard, LanguageCJK or LanguageUnicode. The
+// analyzers are part of the index mapping, so a change replaces the index
+// with an empty one and reports true; all servers must then be indexed
+// again.
+func (i *Indexer) SetLanguage(language string) (bool, error) {
+	language = normalizeLanguage(language)
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if language == i.language {
+		return false, nil
+	}
+
+	var index bleve.Index
+	var err error
+	if i.indexPath == "" {
+		if index, err = bleve.NewMemOnly(buildIndexMapping(language)); err != nil {
+			return false, fmt.Errorf("failed to create bleve index: %w", err)
+		}
+		i.bleveIndex.Close()
+	} else {
+		i.bleveIndex.Close()
+		if err := os.RemoveAll(i.indexPath); err != nil {
+			return false, fmt.Errorf("failed to remove index: %w", err)
+		}
+		if index, err = createIndex(i.indexPath, language); err != nil {
+			return false, fmt.Errorf("failed to rebuild index: %w", err)
+		}
+	}
+
+	log.Printf("Search language changed from %s to %s, rebuilding index", i.language, language)
+	i.bleveIndex = index
+	i.language = language
+	return true, nil
+}
@@ -0,0 +1,68 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/protocol"
+)
+
+func TestAutoLanguageFindsCJKDescriptions(t *testing.T) {
+	indexer, err := NewIndexer()
+	if err != nil {
+		t.Fatalf("failed to create indexer: %v", err)
+	}
+	defer indexer.Close()
+
+	indexer.IndexServer("feishu", []protocol.Tool{
+		{Name: "create_doc", Description: "创建云文档并分享给团队成员"},
+		{Name: "send_message", Description: "发送消息到群聊"},
+	})
+	indexer.IndexServer("jira", []protocol.Tool{{Name: "create_issue", Description: "Create an issue"}})
+
+	// The standard analyzer would index the whole sentence as one token
+	results, err := indexer.SearchBM25("云文档", 10)
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if len(results) == 0 || results[0].ToolName != "create_doc" {
+		t.Fatalf("expected create_doc first, got %+v", results)
+	}
+	if len(results[0].MatchedFields) != 1 || results[0].MatchedFields[0] != "description" {
+		t.Errorf("expected a description match, got %v", results[0].MatchedFields)
+	}
+
+	// English queries are unaffected
+	if results, _ := indexer.SearchBM25("create issue", 10); len(results) == 0 || results[0].ToolName != "create_issue" {
+		t.Errorf("expected create_issue first, got %+v", results)
+	}
+}
+
+func TestSetLanguage(t *testing.T) {
+	indexer, err := NewIndexer()
+	if err != nil {
+		t.Fatalf("failed to create indexer: %v", err)
+	}
+	defer indexer.Close()
+
+	if changed, err := indexer.SetLanguage(""); changed || err != nil {
+		t.Errorf("empty language should keep auto, got changed=%v err=%v", changed, err)
+	}
+
+	indexer.IndexServer("notes", []protocol.Tool{{Name: "find", Description: "Find notes that were written before the meeting"}})
+	changed, err := indexer.SetLanguage(LanguageUnicode)
+	if !changed || err != nil {
+		t.Fatalf("SetLanguage(unicode) = %v, %v", changed, err)
+	}
+	if count, _ := indexer.Count(); count != 0 {
+		t.Errorf("expected the index to be cleared, got %d tools", count)
+	}
+
+	// The unicode analyzer keeps English stop words
+	indexer.IndexServer("notes", []protocol.Tool{{Name: "find", Description: "Find notes that were written before the meeting"}})
+	if results, _ := indexer.SearchBM25("were", 10); len(results) != 1 {
+		t.Errorf("expected the stop word to match under unicode, got %+v", results)
+	}
+	if indexer.Language() != LanguageUnicode {
+		t.Errorf("Language() = %q", indexer.Language())
+	}
+}
@@ -0,0 +1,180 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/search/query"
+)
+
+// Page is one window of a ranked result set.
+type Page struct {
+	// Results are the hits in [Offset, Offset+limit).
+	Results []SearchResult
+
+	// Offset is the rank of the first result in this page.
+	Offset int
+
+	// Total is the number of matching tools, or -1 if unknown.
+	Total int
+
+	// HasMore reports whether results exist beyond this page.
+	HasMore bool
+
+	// Facets count all matching tools by server and tag; nil when nothing
+	// matched or the search cannot count them (hybrid search).
+	Facets *Facets
+}
+
+// SearchBM25Page performs BM25 search and returns one page of results.
+func (i *Indexer) SearchBM25Page(query string, limit, offset int) (*Page, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	return i.searchPage(i.buildMatchQuery(query), limit, offset)
+}
+
+// SearchByServerPage performs BM25 search scoped to a server and returns
+// one page of results.
+func (i *Indexer) SearchByServerPage(query, serverName string, limit, offset int) (*Page, error) {
+	return i.SearchFilteredPage(query, Filter{Servers: []string{serverName}}, limit, offset)
+}
+
+// Filter scopes a search to tools of some servers and/or tags. A tool
+// matches when its server is one of Servers and it has any of Tags; empty
+// fields don't filter.
+type Filter struct {
+	Servers []string
+	Tags    []string
+}
+
+// IsEmpty reports whether the filter lets every tool through.
+func (f Filter) IsEmpty() bool {
+	return len(f.Servers) == 0 && len(f.Tags) == 0
+}
+
+// SearchFilteredPage performs BM25 search scoped by filter and returns one
+// page of results.
+func (i *Indexer) SearchFilteredPage(searchText string, filter Filter, limit, offset int) (*Page, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	clauses := []query.Query{i.buildMatchQuery(searchText)}
+	if terms := termsQuery("serverKey", filter.Servers); terms != nil {
+		clauses = append(clauses, terms)
+	}
+	if terms := termsQuery("tags", normalizeTags(filter.Tags)); terms != nil {
+		clauses = append(clauses, terms)
+	}
+	return i.searchPage(bleve.NewConjunctionQuery(clauses...), limit, offset)
+}
+
+// termsQuery matches documents whose keyword field holds any of values,
+// or returns nil when values is empty.
+func termsQuery(field string, values []string) query.Query {
+	if len(values) == 0 {
+		return nil
+	}
+	terms := make([]query.Query, len(values))
+	for n, value := range values {
+		term := bleve.NewTermQuery(value)
+		term.SetField(field)
+		terms[n] = term
+	}
+	return bleve.NewDisjunctionQuery(terms...)
+}
+
+// normalizeTags lowercases and trims tags, dropping empty ones, so
+// "DevOps" and "devops " are the same tag.
+func normalizeTags(tags []string) []string {
+	var normalized []string
+	for _, tag := range tags {
+		if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" {
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized
+}
+
+// fieldStrings reads a stored multi-value field, which bleve returns as
+// a string for a single value and a slice otherwise.
+func fieldStrings(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// SearchHybridPage performs hybrid search and returns one page of results.
+// Fused rankings have no exact total, so Total is -1.
+func (i *Indexer) SearchHybridPage(query string, limit, offset int, config FusionConfig) (*Page, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	// Fetch one extra result to learn whether another page exists
+	results, err := i.SearchHybrid(query, offset+limit+1, config)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &Page{Offset: offset, Total: -1}
+	if offset < len(results) {
+		results = results[offset:]
+		if len(results) > limit {
+			results = results[:limit]
+			page.HasMore = true
+		}
+		page.Results = results
+	} else {
+		page.Results = []SearchResult{}
+	}
+
+	return page, nil
+}
+
+// searchPage executes a query for one page (caller must hold read lock).
+func (i *Indexer) searchPage(q query.Query, limit, offset int) (*Page, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	// Create search request
+	searchRequest := bleve.NewSearchRequestOptions(q, limit, offset, false)
+	searchRequest.Fields = resultFields
+	searchRequest.IncludeLocations = true
+	addFacetRequests(searchRequest)
+	addHighlightRequest(searchRequest)
+
+	// Execute search
+	results, err := i.bleveIndex.Search(searchRequest)
+	if err != nil {
+		return nil, fmt.Errorf("bleve search failed: %w", err)
+	}
+
+	page := &Page{
+		Results: convertBleveResults(results),
+		Offset:  offset,
+		Total:   int(results.Total),
+		Facets:  convertFacets(results.Facets),
+	}
+	page.HasMore = offset+len(page.Results) < page.Total
+
+	return page, nil
+}
@@ -0,0 +1,166 @@
+package search
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/protocol"
+)
+
+// newPagedIndexer indexes n tools that all match "issue".
+func newPagedIndexer(t *testing.T, n int) *Indexer {
+	t.Helper()
+	indexer, err := NewIndexer()
+	if err != nil {
+		t.Fatalf("failed to create indexer: %v", err)
+	}
+	t.Cleanup(func() { indexer.Close() })
+
+	tools := make([]protocol.Tool, n)
+	for i := range tools {
+		tools[i] = protocol.Tool{Name: fmt.Sprintf("tool_%02d", i), Description: "Manage issue tracker"}
+	}
+	if err := indexer.IndexServer("jira", tools); err != nil {
+		t.Fatalf("failed to index: %v", err)
+	}
+	return indexer
+}
+
+func TestSearchBM25Page(t *testing.T) {
+	indexer := newPagedIndexer(t, 7)
+
+	seen := make(map[string]bool)
+	offset := 0
+	for pages := 0; pages < 10; pages++ {
+		page, err := indexer.SearchBM25Page("issue", 3, offset)
+		if err != nil {
+			t.Fatalf("SearchBM25Page failed: %v", err)
+		}
+		if page.Total != 7 {
+			t.Errorf("expected total 7, got %d", page.Total)
+		}
+		for _, r := range page.Results {
+			if seen[r.ToolName] {
+				t.Errorf("duplicate result across pages: %s", r.ToolName)
+			}
+			seen[r.ToolName] = true
+		}
+		offset += len(page.Results)
+		if !page.HasMore {
+			break
+		}
+	}
+
+	if len(seen) != 7 {
+		t.Errorf("expected to page through 7 tools, saw %d", len(seen))
+	}
+}
+
+func TestSearchByServerPageOffsetPastEnd(t *testing.T) {
+	indexer := newPagedIndexer(t, 2)
+
+	page, err := indexer.SearchByServerPage("issue", "jira", 5, 10)
+	if err != nil {
+		t.Fatalf("SearchByServerPage failed: %v", err)
+	}
+	if len(page.Results) != 0 || page.HasMore {
+		t.Errorf("expected empty last page, got %+v", page)
+	}
+}
+
+func TestSearchHybridPage(t *testing.T) {
+	indexer := newPagedIndexer(t, 5)
+
+	page, err := indexer.SearchHybridPage("issue", 2, 2, DefaultFusionConfig)
+	if err != nil {
+		t.Fatalf("SearchHybridPage failed: %v", err)
+	}
+	if len(page.Results) != 2 || !page.HasMore || page.Total != -1 {
+		t.Errorf("unexpected middle page: %+v", page)
+	}
+
+	page, _ = indexer.SearchHybridPage("issue", 2, 4, DefaultFusionConfig)
+	if len(page.Results) != 1 || page.HasMore {
+		t.Errorf("unexpected last page: %+v", page)
+	}
+}
+
+func TestSearchFilteredPage(t *testing.T) {
+	indexer, err := NewIndexer()
+	if err != nil {
+		t.Fatalf("failed to create indexer: %v", err)
+	}
+	defer indexer.Close()
+
+	issue := func(name string) []protocol.Tool {
+		return []protocol.Tool{{Name: name, Description: "Create an issue"}}
+	}
+	indexer.IndexServer("jiraCloud", issue("jira_create"), "Tracking")
+	indexer.IndexServer("github", issue("gh_create"), "tracking", "code")
+	indexer.IndexServer("linear", issue("linear_create"))
+
+	cases := []struct {
+		name   string
+		filter Filter
+		want   []string
+	}{
+		{"servers", Filter{Servers: []string{"jiraCloud", "linear"}}, []string{"jira_create", "linear_create"}},
+		{"tags", Filter{Tags: []string{"tracking"}}, []string{"gh_create", "jira_create"}},
+		{"servers and tags", Filter{Servers: []string{"jiraCloud", "linear"}, Tags: []string{"TRACKING"}}, []string{"jira_create"}},
+		{"unknown tag", Filter{Tags: []string{"docs"}}, nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			page, err := indexer.SearchFilteredPage("issue", tc.filter, 10, 0)
+			if err != nil {
+				t.Fatalf("SearchFilteredPage failed: %v", err)
+			}
+			var got []string
+			for _, r := range page.Results {
+				got = append(got, r.ToolName)
+			}
+			sort.Strings(got)
+			if fmt.Sprint(got) != fmt.Sprint(tc.want) {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	page, _ := indexer.SearchFilteredPage("issue", Filter{Servers: []string{"github"}}, 10, 0)
+	if len(page.Results) != 1 || fmt.Sprint(page.Results[0].Tags) != "[tracking code]" {
+		t.Errorf("expected github result tagged [tracking code], got %+v", page.Results)
+	}
+}
+
+func TestSearchPageFacets(t *testing.T) {
+	indexer, err := NewIndexer()
+	if err != nil {
+		t.Fatalf("failed to create indexer: %v", err)
+	}
+	defer indexer.Close()
+
+	indexer.IndexServer("github", []protocol.Tool{
+		{Name: "create_issue", Description: "Create an issue"},
+		{Name: "close_issue", Description: "Close an issue"},
+	}, "code")
+	indexer.IndexServer("gitlab", []protocol.Tool{{Name: "new_issue", Description: "Open an issue"}}, "code", "self-hosted")
+
+	page, err := indexer.SearchBM25Page("issue", 1, 0)
+	if err != nil {
+		t.Fatalf("SearchBM25Page failed: %v", err)
+	}
+	if page.Facets == nil {
+		t.Fatal("expected facets")
+	}
+	if page.Facets.Servers["github"] != 2 || page.Facets.Servers["gitlab"] != 1 {
+		t.Errorf("server facets = %v, want github:2 gitlab:1", page.Facets.Servers)
+	}
+	if page.Facets.Tags["code"] != 3 || page.Facets.Tags["self-hosted"] != 1 {
+		t.Errorf("tag facets = %v, want code:3 self-hosted:1", page.Facets.Tags)
+	}
+
+	if page, _ := indexer.SearchBM25Page("nothing matches this", 5, 0); page.Facets != nil {
+		t.Errorf("expected no facets without matches, got %+v", page.Facets)
+	}
+}
@@ -13,6 +13,21 @@ type SearchResult struct {
 	InputSchema interface{} `json:"inputSchema"`
 	ServerName  string      `json:"server"`
 	Score       float64     `json:"score"`
+
+	// Example is a minimal argument object synthesized from InputSchema
+	// at index time (see ExampleArguments).
+	Example map[string]interface{} `json:"example,omitempty"`
+
+	// Tags are the tags of the tool's server.
+	Tags []string `json:"tags,omitempty"`
+
+	// Highlights are the matched fragments of the name and description,
+	// by field, with matched terms in bold Markdown (**term**).
+	Highlights map[string][]string `json:"highlights,omitempty"`
+
+	// MatchedFields lists the indexed fields the query matched
+	// (name, description, server), for ranking transparency.
+	MatchedFields []string `json:"matchedFields,omitempty"`
 }
 
 // ToolDocument represents a tool as stored in the search index.
@@ -5,13 +5,19 @@ import (
 	"log"
 	"math"
 	"sync"
-
-	"github.com/khanglvm/tool-hub-mcp/internal/storage"
 )
 
+// EmbeddingStore persists embedding vectors. storage.Storage satisfies it;
+// keeping the dependency this narrow lets the search package build without
+// SQLite (see cmd/tool-hub-catalog).
+type EmbeddingStore interface {
+	SaveEmbedding(toolName string, vector []float32, version string) error
+	GetEmbedding(toolName string) ([]float32, string, error)
+}
+
 // EmbeddingModel provides semantic search via vector embeddings (optional).
 type EmbeddingModel struct {
-	storage storage.Storage
+	storage EmbeddingStore
 	cache   map[string][]float32
 	mu      sync.RWMutex
 	enabled bool
@@ -19,7 +25,7 @@ type EmbeddingModel struct {
 
 // NewEmbeddingModel creates a new embedding model wrapper.
 // Embeddings are optional - if not available, search falls back to BM25.
-func NewEmbeddingModel(store storage.Storage) *EmbeddingModel {
+func NewEmbeddingModel(store EmbeddingStore) *EmbeddingModel {
 	return &EmbeddingModel{
 		storage: store,
 		cache:   make(map[string][]float32),
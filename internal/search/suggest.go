@@ -0,0 +1,119 @@
+package search
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/fuzzy"
+)
+
+// maxSuggestions caps the alternative queries Suggest returns.
+const maxSuggestions = 5
+
+// Suggest proposes queries for a search that found nothing ("did you
+// mean"): the query with each unknown word replaced by the closest word
+// of the indexed names, descriptions and servers, followed by the servers
+// whose names are close to a query word. Words are replaced only when
+// within one edit more than fuzzy matching tolerates.
+func (i *Indexer) Suggest(searchText string) ([]string, error) {
+	words := queryWords(searchText)
+	if len(words) == 0 {
+		return nil, nil
+	}
+
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	vocabulary := make(map[string]uint64)
+	for _, field := range []string{"name", "description", "server"} {
+		if err := i.collectTerms(field, func(term string, count uint64) {
+			for _, word := range queryWords(term) {
+				vocabulary[word] += count
+			}
+		}); err != nil {
+			return nil, err
+		}
+	}
+	var servers []string
+	if err := i.collectTerms("serverKey", func(term string, _ uint64) {
+		servers = append(servers, term)
+	}); err != nil {
+		return nil, err
+	}
+
+	var suggestions []string
+	corrected := make([]string, len(words))
+	changed := false
+	for n, word := range words {
+		corrected[n] = word
+		if _, known := vocabulary[word]; known {
+			continue
+		}
+		if term := closestTerm(word, vocabulary); term != "" {
+			corrected[n] = term
+			changed = true
+		}
+	}
+	if changed {
+		suggestions = append(suggestions, strings.Join(corrected, " "))
+	}
+
+	sort.Strings(servers)
+	for _, server := range servers {
+		if changed && server == suggestions[0] {
+			continue
+		}
+		for _, word := range words {
+			if fuzzy.Distance(strings.ToLower(server), word) <= fuzzinessFor(word)+1 {
+				suggestions = append(suggestions, server)
+				break
+			}
+		}
+	}
+
+	if len(suggestions) > maxSuggestions {
+		suggestions = suggestions[:maxSuggestions]
+	}
+	return suggestions, nil
+}
+
+// collectTerms calls fn with each indexed term of a field and the number
+// of documents holding it (caller must hold read lock).
+func (i *Indexer) collectTerms(field string, fn func(term string, count uint64)) error {
+	dict, err := i.bleveIndex.FieldDict(field)
+	if err != nil {
+		return fmt.Errorf("failed to read %s terms: %w", field, err)
+	}
+	defer dict.Close()
+
+	for {
+		entry, err := dict.Next()
+		if err != nil {
+			return fmt.Errorf("failed to read %s terms: %w", field, err)
+		}
+		if entry == nil {
+			return nil
+		}
+		fn(entry.Term, entry.Count)
+	}
+}
+
+// closestTerm returns the vocabulary word nearest to word, preferring the
+// more frequent one, or "" when none is within tolerance.
+func closestTerm(word string, vocabulary map[string]uint64) string {
+	limit := fuzzinessFor(word) + 1
+	best, bestDistance := "", limit+1
+	var bestCount uint64
+	for term, count := range vocabulary {
+		distance := fuzzy.Distance(word, term)
+		if distance > limit {
+			continue
+		}
+		if distance < bestDistance ||
+			(distance == bestDistance && (count > bestCount || (count == bestCount && term < best))) {
+			best, bestDistance, bestCount = term, distance, count
+		}
+	}
+	return best
+}
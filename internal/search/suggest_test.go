@@ -0,0 +1,42 @@
+package search
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/protocol"
+)
+
+func TestSuggest(t *testing.T) {
+	indexer, err := NewIndexer()
+	if err != nil {
+		t.Fatalf("failed to create indexer: %v", err)
+	}
+	defer indexer.Close()
+
+	indexer.IndexServer("jira", []protocol.Tool{
+		{Name: "create_issue", Description: "Create an issue"},
+		{Name: "search_issues", Description: "Search issues with JQL"},
+	})
+	indexer.IndexServer("figma", []protocol.Tool{{Name: "export_frame", Description: "Export a frame as an image"}})
+
+	cases := []struct {
+		query string
+		want  []string
+	}{
+		// Beyond fuzzy matching: 2 edits on a medium word, 1 on a short one
+		{"craete isue", []string{"create issue"}},
+		{"jra", []string{"jira"}},
+		{"figmaa exprot", []string{"figma export", "figma"}},
+		{"unrelated words", nil},
+	}
+	for _, tc := range cases {
+		got, err := indexer.Suggest(tc.query)
+		if err != nil {
+			t.Fatalf("Suggest(%q) failed: %v", tc.query, err)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tc.want) {
+			t.Errorf("Suggest(%q) = %q, want %q", tc.query, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package search
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// truncationMarker ends a description that was shortened for the index.
+const truncationMarker = " …"
+
+// SummarizeDescription shortens a tool description to at most max
+// characters (runes) for the index. It keeps the first sentence, then the
+// remaining sentences and lines that mention one of params (the tool's
+// input property names), in their original order, while they fit.
+// Descriptions within max, or a max of 0 or less, are returned unchanged.
+func SummarizeDescription(description string, params []string, max int) string {
+	if max <= 0 || len([]rune(description)) <= max {
+		return description
+	}
+
+	segments := splitSegments(description)
+	if len(segments) == 0 {
+		return truncateRunes(description, max)
+	}
+
+	budget := max - len([]rune(truncationMarker))
+	summary := truncateRunes(segments[0], budget)
+	if summary != segments[0] {
+		return summary
+	}
+	for _, segment := range segments[1:] {
+		if !mentionsAny(segment, params) {
+			continue
+		}
+		candidate := summary + " " + segment
+		if len([]rune(candidate)) > budget {
+			continue
+		}
+		summary = candidate
+	}
+	return summary + truncationMarker
+}
+
+// splitSegments splits text into trimmed sentences, treating line breaks
+// as boundaries too so bullet lists stay one item per segment.
+func splitSegments(text string) []string {
+	var segments []string
+	for _, line := range strings.Split(text, "\n") {
+		rest := strings.TrimSpace(line)
+		for rest != "" {
+			end := sentenceEnd(rest)
+			if segment := strings.TrimSpace(rest[:end]); segment != "" {
+				segments = append(segments, segment)
+			}
+			rest = strings.TrimSpace(rest[end:])
+		}
+	}
+	return segments
+}
+
+// sentenceEnd returns the index just past the first sentence terminator
+// followed by whitespace, or len(s) if there is none.
+func sentenceEnd(s string) int {
+	for i := 0; i < len(s)-1; i++ {
+		switch s[i] {
+		case '.', '!', '?':
+			if s[i+1] == ' ' || s[i+1] == '\t' {
+				return i + 1
+			}
+		}
+	}
+	return len(s)
+}
+
+// mentionsAny reports whether text contains one of names as a whole word.
+func mentionsAny(text string, names []string) bool {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '-'
+	})
+	for _, word := range words {
+		for _, name := range names {
+			if strings.EqualFold(word, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// truncateRunes cuts s to at most max runes, on a word boundary when one
+// is close, and marks the cut.
+func truncateRunes(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	limit := max - len([]rune(truncationMarker))
+	if limit < 0 {
+		limit = 0
+	}
+	cut := string(runes[:limit])
+	if space := strings.LastIndexAny(cut, " \t"); space > len(cut)/2 {
+		cut = cut[:space]
+	}
+	return strings.TrimSpace(cut) + truncationMarker
+}
+
+// schemaProperties returns the property names of a tool's input schema.
+func schemaProperties(schema interface{}) []string {
+	object, _ := schema.(map[string]interface{})
+	properties, _ := object["properties"].(map[string]interface{})
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,62 @@
+package search
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/protocol"
+)
+
+func TestSummarizeDescription(t *testing.T) {
+	short := "Create an issue."
+	if got := SummarizeDescription(short, nil, 100); got != short {
+		t.Errorf("short description changed: %q", got)
+	}
+
+	long := "Create a Jira issue in a project. " + strings.Repeat("This tool is very useful. ", 20) +
+		"\n- project: the project key\n- summary: the issue title\n" + strings.Repeat("More background. ", 20)
+	got := SummarizeDescription(long, []string{"project", "summary"}, 200)
+	if len([]rune(got)) > 200 {
+		t.Errorf("summary exceeds limit: %d chars", len([]rune(got)))
+	}
+	for _, want := range []string{"Create a Jira issue in a project.", "- project: the project key", "- summary: the issue title"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("summary missing %q: %q", want, got)
+		}
+	}
+	if strings.Contains(got, "very useful") || !strings.HasSuffix(got, truncationMarker) {
+		t.Errorf("unexpected summary: %q", got)
+	}
+
+	sentence := strings.Repeat("word ", 100)
+	if got := SummarizeDescription(sentence, nil, 50); len([]rune(got)) > 50 || !strings.HasSuffix(got, truncationMarker) {
+		t.Errorf("long first sentence not truncated: %q", got)
+	}
+	if got := SummarizeDescription(long, nil, 0); got != long {
+		t.Error("zero limit should keep the description")
+	}
+}
+
+func TestIndexServerSummarizesDescriptions(t *testing.T) {
+	indexer, err := NewIndexer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer indexer.Close()
+	indexer.SetMaxDescriptionLength(80)
+
+	tools := []protocol.Tool{{
+		Name:        "create_issue",
+		Description: "Create an issue. " + strings.Repeat("Background text. ", 50),
+	}}
+	if err := indexer.IndexServer("jira", tools); err != nil {
+		t.Fatal(err)
+	}
+	results, err := indexer.SearchBM25("create issue", 5)
+	if err != nil || len(results) == 0 {
+		t.Fatalf("search failed: %v (%d results)", err, len(results))
+	}
+	if got := results[0].Description; got != "Create an issue."+truncationMarker {
+		t.Errorf("indexed description = %q", got)
+	}
+}
@@ -0,0 +1,117 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// builtinSynonyms are groups of interchangeable words and phrases used to
+// expand queries, so "make a PR" also matches "create pull request".
+var builtinSynonyms = [][]string{
+	{"create", "make", "add"},
+	{"delete", "remove", "destroy"},
+	{"update", "edit", "modify", "change"},
+	{"get", "fetch", "read", "retrieve"},
+	{"list", "show", "enumerate"},
+	{"search", "find", "lookup", "query"},
+	{"issue", "ticket", "bug"},
+	{"pr", "pull request", "merge request", "mr"},
+	{"repo", "repository"},
+	{"screenshot", "capture", "screen capture"},
+	{"doc", "docs", "document", "documentation"},
+	{"msg", "message"},
+	{"db", "database"},
+	{"config", "configuration", "settings"},
+}
+
+// Expander rewrites queries to include synonyms of the phrases they contain.
+// Expansion only adds terms, so BM25 still ranks exact matches highest.
+type Expander struct {
+	groups [][][]string // group → phrase → words
+}
+
+// NewExpander creates an expander from the built-in table plus extra
+// synonyms (term → alternatives). Each extra entry forms its own group.
+func NewExpander(extra map[string][]string) *Expander {
+	e := &Expander{}
+	for _, group := range builtinSynonyms {
+		e.addGroup(group)
+	}
+	for term, alternatives := range extra {
+		e.addGroup(append([]string{term}, alternatives...))
+	}
+	return e
+}
+
+// addGroup tokenizes and adds a synonym group, ignoring empty phrases.
+func (e *Expander) addGroup(phrases []string) {
+	group := make([][]string, 0, len(phrases))
+	for _, p := range phrases {
+		if words := queryWords(p); len(words) > 0 {
+			group = append(group, words)
+		}
+	}
+	if len(group) > 1 {
+		e.groups = append(e.groups, group)
+	}
+}
+
+// Expand returns the query followed by the synonyms of every phrase it
+// contains that are not already present. A nil expander returns the query.
+func (e *Expander) Expand(query string) string {
+	if e == nil {
+		return query
+	}
+
+	words := queryWords(query)
+	var added []string
+	seen := make(map[string]bool)
+	for _, group := range e.groups {
+		matched := false
+		for _, phrase := range group {
+			if containsPhrase(words, phrase) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+		for _, phrase := range group {
+			joined := strings.Join(phrase, " ")
+			if !seen[joined] && !containsPhrase(words, phrase) {
+				seen[joined] = true
+				added = append(added, joined)
+			}
+		}
+	}
+
+	if len(added) == 0 {
+		return query
+	}
+	return query + " " + strings.Join(added, " ")
+}
+
+// queryWords splits text into lowercase words.
+func queryWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(c rune) bool {
+		return !unicode.IsLetter(c) && !unicode.IsDigit(c)
+	})
+}
+
+// containsPhrase reports whether phrase occurs as consecutive words.
+func containsPhrase(words, phrase []string) bool {
+	for i := 0; i+len(phrase) <= len(words); i++ {
+		match := true
+		for j, w := range phrase {
+			if words[i+j] != w {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package search
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/protocol"
+)
+
+func TestExpand(t *testing.T) {
+	e := NewExpander(map[string][]string{"deploy": {"release", "ship it"}})
+
+	tests := []struct {
+		query    string
+		contains []string
+		absent   []string
+	}{
+		{"make a PR", []string{"create", "pull request", "merge request"}, nil},
+		{"open ticket", []string{"issue", "bug"}, []string{"create"}},
+		{"deploy app", []string{"release", "ship it"}, nil},
+		{"weather forecast", nil, nil},
+	}
+
+	for _, tt := range tests {
+		got := e.Expand(tt.query)
+		if !strings.HasPrefix(got, tt.query) {
+			t.Errorf("Expand(%q) = %q, should keep the original query first", tt.query, got)
+		}
+		for _, want := range tt.contains {
+			if !strings.Contains(got, want) {
+				t.Errorf("Expand(%q) = %q, missing %q", tt.query, got, want)
+			}
+		}
+		for _, unwanted := range tt.absent {
+			if strings.Contains(got, unwanted) {
+				t.Errorf("Expand(%q) = %q, should not contain %q", tt.query, got, unwanted)
+			}
+		}
+	}
+
+	if got := (*Expander)(nil).Expand("make a PR"); got != "make a PR" {
+		t.Errorf("nil expander should return the query, got %q", got)
+	}
+}
+
+func TestSearchBM25Synonyms(t *testing.T) {
+	indexer, err := NewIndexer()
+	if err != nil {
+		t.Fatalf("failed to create indexer: %v", err)
+	}
+	defer indexer.Close()
+
+	tools := []protocol.Tool{
+		{Name: "create_pull_request", Description: "Create a pull request"},
+		{Name: "get_weather", Description: "Get the weather forecast"},
+	}
+	if err := indexer.IndexServer("github", tools); err != nil {
+		t.Fatalf("failed to index server: %v", err)
+	}
+
+	results, err := indexer.SearchBM25("make a PR", 5)
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if len(results) == 0 || results[0].ToolName != "create_pull_request" {
+		t.Errorf("expected create_pull_request first, got %+v", results)
+	}
+
+	indexer.SetSynonyms(map[string][]string{"rain": {"weather"}})
+	results, err = indexer.SearchBM25("will it rain", 5)
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if len(results) == 0 || results[0].ToolName != "get_weather" {
+		t.Errorf("expected user synonym to match get_weather, got %+v", results)
+	}
+}
@@ -0,0 +1,68 @@
+package spawner
+
+import "strings"
+
+// Capabilities are the features a child server declared in the
+// capabilities object of its initialize response.
+type Capabilities struct {
+	// Declared is false when the child sent no capabilities object; the
+	// hub then assumes it offers tools, as older servers did.
+	Declared         bool `json:"declared"`
+	Tools            bool `json:"tools"`
+	ToolsListChanged bool `json:"toolsListChanged,omitempty"`
+	Resources        bool `json:"resources,omitempty"`
+	Prompts          bool `json:"prompts,omitempty"`
+	Logging          bool `json:"logging,omitempty"`
+}
+
+// parseCapabilities reads the capabilities object of an initialize result.
+// A capability is declared by the presence of its key, whatever the value.
+func parseCapabilities(raw interface{}) Capabilities {
+	caps, ok := raw.(map[string]interface{})
+	if !ok {
+		return Capabilities{Tools: true}
+	}
+
+	var parsed Capabilities
+	parsed.Declared = true
+	if tools, ok := caps["tools"]; ok {
+		parsed.Tools = true
+		if opts, ok := tools.(map[string]interface{}); ok {
+			parsed.ToolsListChanged, _ = opts["listChanged"].(bool)
+		}
+	}
+	_, parsed.Resources = caps["resources"]
+	_, parsed.Prompts = caps["prompts"]
+	_, parsed.Logging = caps["logging"]
+
+	// Some servers answer with an empty object and serve tools anyway
+	if len(caps) == 0 {
+		parsed.Tools = true
+	}
+	return parsed
+}
+
+// String lists the declared capabilities, e.g. "tools (listChanged), resources".
+func (c Capabilities) String() string {
+	var names []string
+	if c.Tools {
+		if c.ToolsListChanged {
+			names = append(names, "tools (listChanged)")
+		} else {
+			names = append(names, "tools")
+		}
+	}
+	if c.Resources {
+		names = append(names, "resources")
+	}
+	if c.Prompts {
+		names = append(names, "prompts")
+	}
+	if c.Logging {
+		names = append(names, "logging")
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ", ")
+}
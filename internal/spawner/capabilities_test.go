@@ -0,0 +1,62 @@
+package spawner
+
+import (
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/mcptest"
+)
+
+func TestParseCapabilities(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  interface{}
+		want Capabilities
+	}{
+		{"missing", nil, Capabilities{Tools: true}},
+		{"empty", map[string]interface{}{}, Capabilities{Declared: true, Tools: true}},
+		{
+			"full",
+			map[string]interface{}{
+				"tools":     map[string]interface{}{"listChanged": true},
+				"resources": map[string]interface{}{"subscribe": true},
+				"prompts":   map[string]interface{}{},
+				"logging":   map[string]interface{}{},
+			},
+			Capabilities{Declared: true, Tools: true, ToolsListChanged: true, Resources: true, Prompts: true, Logging: true},
+		},
+		{"no tools", map[string]interface{}{"prompts": map[string]interface{}{}}, Capabilities{Declared: true, Prompts: true}},
+	}
+	for _, tt := range tests {
+		if got := parseCapabilities(tt.raw); got != tt.want {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+
+	caps := Capabilities{Tools: true, ToolsListChanged: true, Prompts: true}
+	if got := caps.String(); got != "tools (listChanged), prompts" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+// TestGetToolsSkipsServersWithoutTools verifies tools/list is not sent to a
+// child that declared capabilities without tools.
+func TestGetToolsSkipsServersWithoutTools(t *testing.T) {
+	cfg := mcptest.Server(t, mcptest.Handlers{
+		"initialize": mcptest.Result(`{"capabilities":{"prompts":{}}}`),
+		"tools/list": mcptest.Result(`{"tools":[{"name":"unexpected"}]}`),
+	})
+
+	pool := NewPool(1)
+	defer pool.Close()
+
+	tools, err := pool.GetTools("prompts", cfg)
+	if err != nil {
+		t.Fatalf("GetTools failed: %v", err)
+	}
+	if len(tools) != 0 {
+		t.Errorf("expected no tools from a prompts-only server, got %+v", tools)
+	}
+	if caps, ok := pool.Capabilities("prompts"); !ok || !caps.Prompts || caps.Tools {
+		t.Errorf("unexpected capabilities: %+v (ok=%v)", caps, ok)
+	}
+}
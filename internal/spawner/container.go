@@ -0,0 +1,117 @@
+package spawner
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+)
+
+// containerRef identifies a running container so it can be removed when
+// its process is killed; killing the docker CLI alone leaves it running.
+type containerRef struct {
+	runtime string
+	name    string
+}
+
+// readyImages remembers images already present or pulled this process
+// under the "missing" policy, so later spawns skip the inspect.
+var readyImages sync.Map
+
+// containerCommand returns the runtime binary and arguments that run a
+// containerized server with stdio attached: -i keeps stdin open for
+// JSON-RPC and no TTY is allocated, so stdout carries only the
+// server's messages. Env vars are passed by name (-e KEY) and read from
+// the runtime's own environment, keeping values out of the process list.
+func containerCommand(cfg *config.ServerConfig, envKeys []string, name string) (string, []string) {
+	args := []string{"run", "--rm", "-i", "--name", name}
+	for _, key := range envKeys {
+		args = append(args, "-e", key)
+	}
+	for _, volume := range cfg.Container.Volumes {
+		args = append(args, "-v", volume)
+	}
+	if cfg.Container.Network != "" && !cfg.Sandbox.NoNetwork() {
+		args = append(args, "--network", cfg.Container.Network)
+	}
+	if cfg.Cwd != "" {
+		args = append(args, "--workdir", cfg.Cwd)
+	}
+	args = append(args, containerSandboxArgs(cfg.Sandbox)...)
+	args = append(args, containerLimitArgs(cfg.Limits)...)
+	args = append(args, cfg.Container.Image)
+	switch {
+	case cfg.Shell:
+		// Containers are Linux, whatever the host
+		line := strings.Join(append([]string{cfg.Command}, cfg.Args...), " ")
+		args = append(args, "sh", "-c", line)
+	case cfg.Command != "":
+		args = append(args, cfg.Command)
+		args = append(args, cfg.Args...)
+	default:
+		args = append(args, cfg.Args...)
+	}
+	return cfg.Runtime, args
+}
+
+// containerEnvKeys returns the sorted names of the env vars a container
+// receives: the pool defaults and the server's own env.
+func containerEnvKeys(defaults, serverEnv map[string]string) []string {
+	seen := make(map[string]bool, len(defaults)+len(serverEnv))
+	keys := make([]string, 0, len(defaults)+len(serverEnv))
+	for _, env := range []map[string]string{defaults, serverEnv} {
+		for key := range env {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// containerName returns a unique name for a server's container.
+func containerName() string {
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	return "tool-hub-mcp-" + hex.EncodeToString(suffix)
+}
+
+// ensureImage makes sure a container's image is available before it is
+// started, so a slow first pull does not count against the initialize
+// timeout.
+func ensureImage(cfg *config.ServerConfig) error {
+	runtime, image := cfg.Runtime, cfg.Container.Image
+	key := runtime + "\x00" + image
+
+	switch cfg.Container.Pull {
+	case config.PullNever:
+		return nil
+	case config.PullAlways:
+	default:
+		if _, ok := readyImages.Load(key); ok {
+			return nil
+		}
+		if err := execCommand(runtime, "image", "inspect", image).Run(); err == nil {
+			readyImages.Store(key, true)
+			return nil
+		}
+	}
+
+	out, err := execCommand(runtime, "pull", image).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to pull image %s: %v: %s", image, err, strings.TrimSpace(string(out)))
+	}
+	readyImages.Store(key, true)
+	return nil
+}
+
+// remove force-removes the container in the background.
+func (c *containerRef) remove() {
+	go execCommand(c.runtime, "rm", "-f", c.name).Run()
+}
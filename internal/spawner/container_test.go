@@ -0,0 +1,100 @@
+package spawner
+
+import (
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+)
+
+func TestContainerCommand(t *testing.T) {
+	cfg := &config.ServerConfig{
+		Runtime: config.RuntimeDocker,
+		Command: "node",
+		Args:    []string{"server.js", "--stdio"},
+		Container: &config.ContainerSettings{
+			Image:   "mcp/github:latest",
+			Volumes: []string{"/tmp/data:/data:ro"},
+			Network: "none",
+		},
+	}
+
+	keys := containerEnvKeys(map[string]string{"TZ": "UTC"}, map[string]string{"GITHUB_TOKEN": "secret", "TZ": "Europe/Berlin"})
+	if !reflect.DeepEqual(keys, []string{"GITHUB_TOKEN", "TZ"}) {
+		t.Errorf("env keys = %v", keys)
+	}
+
+	command, args := containerCommand(cfg, keys, "tool-hub-mcp-test")
+	want := []string{
+		"run", "--rm", "-i", "--name", "tool-hub-mcp-test",
+		"-e", "GITHUB_TOKEN", "-e", "TZ",
+		"-v", "/tmp/data:/data:ro",
+		"--network", "none",
+		"mcp/github:latest", "node", "server.js", "--stdio",
+	}
+	if command != "docker" || !reflect.DeepEqual(args, want) {
+		t.Errorf("containerCommand() = %s %v", command, args)
+	}
+	for _, arg := range args {
+		if strings.Contains(arg, "secret") {
+			t.Errorf("env values must not appear in arguments: %v", args)
+		}
+	}
+
+	// Without a command the image entrypoint runs
+	cfg.Command, cfg.Args = "", nil
+	if _, args := containerCommand(cfg, nil, "n"); args[len(args)-1] != "mcp/github:latest" {
+		t.Errorf("expected the image last, got %v", args)
+	}
+}
+
+func TestEnsureImage(t *testing.T) {
+	original := execCommand
+	defer func() { execCommand = original }()
+
+	var calls []string
+	present := false
+	execCommand = func(name string, args ...string) *exec.Cmd {
+		calls = append(calls, name+" "+strings.Join(args, " "))
+		if args[0] == "image" && !present {
+			return exec.Command("false")
+		}
+		return exec.Command("true")
+	}
+
+	cfg := &config.ServerConfig{
+		Runtime:   config.RuntimePodman,
+		Container: &config.ContainerSettings{Image: "example/ensure-image-test"},
+	}
+
+	// Missing image is pulled once, then remembered
+	if err := ensureImage(cfg); err != nil {
+		t.Fatalf("ensureImage() failed: %v", err)
+	}
+	if err := ensureImage(cfg); err != nil {
+		t.Fatalf("ensureImage() failed: %v", err)
+	}
+	want := []string{"podman image inspect example/ensure-image-test", "podman pull example/ensure-image-test"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+
+	calls = nil
+	cfg.Container.Pull = config.PullNever
+	ensureImage(cfg)
+	cfg.Container.Pull = config.PullAlways
+	ensureImage(cfg)
+	if !reflect.DeepEqual(calls, []string{"podman pull example/ensure-image-test"}) {
+		t.Errorf("calls = %v", calls)
+	}
+
+	// Pull failures are reported
+	execCommand = func(name string, args ...string) *exec.Cmd { return exec.Command("false") }
+	cfg.Container.Image = "example/missing"
+	cfg.Container.Pull = ""
+	if err := ensureImage(cfg); err == nil || !strings.Contains(err.Error(), "failed to pull image example/missing") {
+		t.Errorf("expected pull error, got %v", err)
+	}
+}
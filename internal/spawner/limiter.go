@@ -0,0 +1,73 @@
+package spawner
+
+import "sync"
+
+// QueueFunc is notified when a call has to wait for a free slot, with its
+// 1-based position in the server's queue.
+type QueueFunc func(position int)
+
+// callLimiter bounds in-flight calls to one server. Waiters are admitted
+// in arrival order: a released slot is handed directly to the oldest
+// waiter, so later callers cannot overtake queued ones.
+type callLimiter struct {
+	mu      sync.Mutex
+	limit   int
+	active  int
+	waiters []chan struct{}
+}
+
+// acquire blocks until a slot is free. onQueued (optional) is called once
+// if the call has to wait.
+func (l *callLimiter) acquire(onQueued QueueFunc) {
+	l.mu.Lock()
+	if l.limit <= 0 || l.active < l.limit {
+		l.active++
+		l.mu.Unlock()
+		return
+	}
+
+	ready := make(chan struct{})
+	l.waiters = append(l.waiters, ready)
+	position := len(l.waiters)
+	l.mu.Unlock()
+
+	if onQueued != nil {
+		onQueued(position)
+	}
+	<-ready
+}
+
+// release frees a slot, handing it to the oldest waiter if there is one.
+func (l *callLimiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.waiters) > 0 {
+		next := l.waiters[0]
+		l.waiters = l.waiters[1:]
+		close(next)
+		return
+	}
+	l.active--
+}
+
+// setLimit changes the limit and admits waiters that now fit. A limit
+// of 0 or less admits every waiter.
+func (l *callLimiter) setLimit(n int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.limit = n
+	for len(l.waiters) > 0 && (n <= 0 || l.active < n) {
+		close(l.waiters[0])
+		l.waiters = l.waiters[1:]
+		l.active++
+	}
+}
+
+// queued returns the number of waiting calls.
+func (l *callLimiter) queued() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.waiters)
+}
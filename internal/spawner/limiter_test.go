@@ -0,0 +1,78 @@
+package spawner
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCallLimiterFIFO(t *testing.T) {
+	l := &callLimiter{limit: 1}
+	l.acquire(nil)
+
+	order := make(chan int, 3)
+	for i := 1; i <= 3; i++ {
+		queued := make(chan int, 1)
+		go func(i int) {
+			l.acquire(func(position int) { queued <- position })
+			order <- i
+			l.release()
+		}(i)
+		if position := <-queued; position != i {
+			t.Fatalf("call %d queued at position %d", i, position)
+		}
+	}
+	if got := l.queued(); got != 3 {
+		t.Fatalf("expected 3 queued calls, got %d", got)
+	}
+
+	l.release()
+	for want := 1; want <= 3; want++ {
+		select {
+		case got := <-order:
+			if got != want {
+				t.Fatalf("expected call %d to run next, got %d", want, got)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("queued call was never admitted")
+		}
+	}
+}
+
+func TestCallLimiterSetLimit(t *testing.T) {
+	l := &callLimiter{limit: 1}
+	l.acquire(nil)
+
+	admitted := make(chan struct{})
+	go func() {
+		l.acquire(nil)
+		close(admitted)
+	}()
+	for l.queued() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	l.setLimit(2)
+	select {
+	case <-admitted:
+	case <-time.After(time.Second):
+		t.Fatal("raising the limit should admit the waiting call")
+	}
+}
+
+func TestPoolMaxConcurrentCalls(t *testing.T) {
+	pool := NewPool(3)
+	if pool.limiter("a") != nil {
+		t.Error("calls should be unlimited by default")
+	}
+
+	pool.SetMaxConcurrentCalls(2)
+	l := pool.limiter("a")
+	if l == nil || l.limit != 2 || pool.limiter("a") != l {
+		t.Fatalf("expected one shared limiter with limit 2, got %+v", l)
+	}
+
+	pool.SetMaxConcurrentCalls(0)
+	if pool.limiter("a") != nil || pool.QueuedCalls("a") != 0 {
+		t.Error("removing the limit should drop the limiters")
+	}
+}
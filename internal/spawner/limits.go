@@ -0,0 +1,126 @@
+package spawner
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+)
+
+// ChildLimitError is returned when a server cannot be spawned because
+// the pool already runs its maximum number of children.
+type ChildLimitError struct {
+	Server string
+	Max    int
+}
+
+func (e *ChildLimitError) Error() string {
+	return fmt.Sprintf("cannot start server '%s': %d child processes already running (settings.maxChildProcesses). Stop another server or raise the limit", e.Server, e.Max)
+}
+
+// LimitExceededError reports a child that exited because of one of its
+// resource limits.
+type LimitExceededError struct {
+	// Resource is "CPU time" or "memory".
+	Resource string
+	// Limit is the configured limit, e.g. "60s" or "512 MB".
+	Limit string
+	// State describes how the process exited.
+	State string
+	// Inferred is set when the exit only suggests the cause: a process
+	// hitting its memory limit fails its allocations and then aborts,
+	// crashes or is killed.
+	Inferred bool
+}
+
+func (e *LimitExceededError) Error() string {
+	if e.Inferred {
+		return fmt.Sprintf("server process exited (%s), possibly after exceeding its %s limit of %s", e.State, e.Resource, e.Limit)
+	}
+	return fmt.Sprintf("server process exceeded its %s limit of %s and was terminated (%s)", e.Resource, e.Limit, e.State)
+}
+
+// Exit codes container runtimes report for a container killed by a
+// signal: 128 plus the Linux signal number.
+const (
+	containerKilled = 128 + 9  // SIGKILL, e.g. from the OOM killer
+	containerXCPU   = 128 + 24 // SIGXCPU
+)
+
+// limitExceeded explains an abnormal exit of a process with resource
+// limits from the cause the OS reports, or returns nil if no limit is to
+// blame. A plain failing exit status is never attributed to a limit.
+func limitExceeded(limits *config.ResourceLimits, state *os.ProcessState, job *limitJob, container bool) *LimitExceededError {
+	if limits == nil || state == nil || state.Success() {
+		return nil
+	}
+	if !container {
+		return processLimitExceeded(limits, state, job)
+	}
+
+	// The runtime exits with the container's status
+	switch code := state.ExitCode(); {
+	case code == containerXCPU && limits.CPUSeconds > 0:
+		return cpuLimitError(limits, state)
+	case code == containerKilled && limits.MemoryMB > 0:
+		// Killing a container that ignored the CPU soft limit also
+		// reports SIGKILL
+		return memoryLimitError(limits, state, true)
+	}
+	return nil
+}
+
+func cpuLimitError(limits *config.ResourceLimits, state *os.ProcessState) *LimitExceededError {
+	limit := time.Duration(limits.CPUSeconds) * time.Second
+	return &LimitExceededError{Resource: "CPU time", Limit: limit.String(), State: state.String()}
+}
+
+func memoryLimitError(limits *config.ResourceLimits, state *os.ProcessState, inferred bool) *LimitExceededError {
+	return &LimitExceededError{Resource: "memory", Limit: fmt.Sprintf("%d MB", limits.MemoryMB), State: state.String(), Inferred: inferred}
+}
+
+// containerLimitArgs returns the runtime flags that apply resource limits
+// to a container: the runtime enforces them inside the container, where
+// limits on the docker CLI process would have no effect.
+func containerLimitArgs(limits *config.ResourceLimits) []string {
+	if limits == nil {
+		return nil
+	}
+	var args []string
+	if limits.MemoryMB > 0 {
+		args = append(args, "--memory", strconv.Itoa(limits.MemoryMB)+"m")
+	}
+	if limits.CPUSeconds > 0 {
+		// A soft limit below the hard one makes the kernel send SIGXCPU,
+		// which tells the CPU limit apart from the OOM killer's SIGKILL
+		args = append(args, "--ulimit", fmt.Sprintf("cpu=%d:%d", limits.CPUSeconds, limits.CPUSeconds+1))
+	}
+	return args
+}
+
+// exitReason returns why a process's stdout closed. For processes with
+// resource limits it waits briefly for the exit status so a limit being
+// exceeded is reported instead of a bare EOF.
+func (proc *Process) exitReason() error {
+	if proc.limits == nil || proc.cmd == nil {
+		return proc.readErr
+	}
+
+	done := make(chan struct{})
+	go func() {
+		proc.wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		return proc.readErr
+	}
+
+	if err := limitExceeded(proc.limits, proc.cmd.ProcessState, proc.job, proc.container != nil); err != nil {
+		return err
+	}
+	return proc.readErr
+}
@@ -0,0 +1,33 @@
+//go:build !unix && !windows
+
+package spawner
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+)
+
+// limitCommand returns the command unchanged; applyLimits reports that
+// limits are unsupported.
+func limitCommand(limits *config.ResourceLimits, command string, args []string) (string, []string) {
+	return command, args
+}
+
+// limitJob is unused on this platform.
+type limitJob struct{}
+
+func (job *limitJob) close() {}
+
+// applyLimits fails: this platform has no way to limit a child process.
+func applyLimits(cmd *exec.Cmd, limits *config.ResourceLimits) (*limitJob, error) {
+	return nil, fmt.Errorf("resource limits are not supported on %s", runtime.GOOS)
+}
+
+// processLimitExceeded returns nil: no limits are applied here.
+func processLimitExceeded(limits *config.ResourceLimits, state *os.ProcessState, _ *limitJob) *LimitExceededError {
+	return nil
+}
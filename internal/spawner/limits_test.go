@@ -0,0 +1,125 @@
+package spawner
+
+import (
+	"errors"
+	"os/exec"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+)
+
+func TestMaxChildren(t *testing.T) {
+	pool := NewPool(3)
+	pool.SetMaxChildren(1)
+	pool.processes["running"] = &Process{}
+
+	_, err := pool.getOrSpawn("other", &config.ServerConfig{Command: "true"}, true)
+	var limitErr *ChildLimitError
+	if !errors.As(err, &limitErr) || limitErr.Server != "other" || limitErr.Max != 1 {
+		t.Fatalf("getOrSpawn() error = %v, want ChildLimitError", err)
+	}
+
+	// A child whose stdout closed no longer counts
+	closed := make(chan struct{})
+	close(closed)
+	pool.processes["running"].closed = closed
+	pool.mu.Lock()
+	running := pool.runningLocked()
+	pool.mu.Unlock()
+	if running != 0 {
+		t.Errorf("runningLocked() = %d, want 0", running)
+	}
+}
+
+func TestContainerLimitArgs(t *testing.T) {
+	args := containerLimitArgs(&config.ResourceLimits{MemoryMB: 256, CPUSeconds: 30})
+	want := []string{"--memory", "256m", "--ulimit", "cpu=30:31"}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("containerLimitArgs() = %v, want %v", args, want)
+	}
+	if args := containerLimitArgs(nil); args != nil {
+		t.Errorf("containerLimitArgs(nil) = %v, want nil", args)
+	}
+}
+
+// TestCPULimitExceeded runs a busy loop under a 1s CPU limit and checks
+// its exit is reported as the limit being exceeded.
+func TestCPULimitExceeded(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	limits := &config.ResourceLimits{CPUSeconds: 1}
+	command, args := limitCommand(limits, "sh", []string{"-c", "while :; do :; done"})
+	cmd := exec.Command(command, args...)
+	if err := cmd.Run(); err == nil {
+		t.Fatal("busy loop should be terminated")
+	}
+
+	err := limitExceeded(limits, cmd.ProcessState, nil, false)
+	if err == nil || err.Inferred || err.Resource != "CPU time" {
+		t.Fatalf("limitExceeded() = %v, want CPU time limit", err)
+	}
+	if !strings.Contains(err.Error(), "CPU time limit of 1s") {
+		t.Errorf("error = %q", err.Error())
+	}
+}
+
+// TestMemoryLimitInferred checks only an exit signal suggesting failed
+// allocations is attributed to the memory limit.
+func TestMemoryLimitInferred(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	limits := &config.ResourceLimits{MemoryMB: 64, CPUSeconds: 60}
+
+	cmd := exec.Command("sh", "-c", "kill -ABRT $$")
+	cmd.Run()
+	err := limitExceeded(limits, cmd.ProcessState, nil, false)
+	if err == nil || !err.Inferred || !strings.Contains(err.Error(), "possibly after exceeding its memory limit of 64 MB") {
+		t.Errorf("limitExceeded() = %v, want inferred memory limit", err)
+	}
+	if err := limitExceeded(nil, cmd.ProcessState, nil, false); err != nil {
+		t.Errorf("limitExceeded(nil) = %v, want nil", err)
+	}
+
+	// A failing exit status or an unrelated signal names no limit
+	for _, script := range []string{"exit 3", "kill -TERM $$", "kill -KILL $$"} {
+		cmd := exec.Command("sh", "-c", script)
+		cmd.Run()
+		if err := limitExceeded(limits, cmd.ProcessState, nil, false); err != nil {
+			t.Errorf("%s: limitExceeded() = %v, want nil", script, err)
+		}
+	}
+}
+
+// TestContainerLimitExceeded checks the exit codes container runtimes
+// report for a killed container.
+func TestContainerLimitExceeded(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	limits := &config.ResourceLimits{MemoryMB: 64, CPUSeconds: 60}
+	tests := []struct {
+		code     string
+		resource string
+	}{
+		{"152", "CPU time"},
+		{"137", "memory"},
+		{"1", ""},
+	}
+	for _, tt := range tests {
+		cmd := exec.Command("sh", "-c", "exit "+tt.code)
+		cmd.Run()
+		err := limitExceeded(limits, cmd.ProcessState, nil, true)
+		var resource string
+		if err != nil {
+			resource = err.Resource
+		}
+		if resource != tt.resource {
+			t.Errorf("exit %s: limitExceeded() = %v, want %q", tt.code, err, tt.resource)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+//go:build unix
+
+package spawner
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+)
+
+// limitCommand wraps a command in sh so the limits are set with ulimit
+// before it execs, applying them from the first instruction and to every
+// process it starts. The kernel fails allocations beyond the data limit,
+// sends SIGXCPU at the CPU soft limit and SIGKILL a second later at the
+// hard limit, should the process survive SIGXCPU.
+func limitCommand(limits *config.ResourceLimits, command string, args []string) (string, []string) {
+	var steps []string
+	if limits.MemoryMB > 0 {
+		steps = append(steps, fmt.Sprintf("ulimit -d %d", limits.MemoryMB*1024))
+	}
+	if limits.CPUSeconds > 0 {
+		// The soft limit first: the hard one may not go below it
+		steps = append(steps, fmt.Sprintf("ulimit -S -t %d && ulimit -H -t %d", limits.CPUSeconds, limits.CPUSeconds+1))
+	}
+	if len(steps) == 0 {
+		return command, args
+	}
+
+	script := strings.Join(steps, " && ") + ` && exec "$@"`
+	return "sh", append([]string{"-c", script, "tool-hub-mcp", command}, args...)
+}
+
+// limitJob is unused on Unix, where the wait status tells which limit a
+// process exceeded.
+type limitJob struct{}
+
+func (job *limitJob) close() {}
+
+// applyLimits is a no-op on Unix, where limitCommand sets the limits.
+func applyLimits(cmd *exec.Cmd, limits *config.ResourceLimits) (*limitJob, error) {
+	return nil, nil
+}
+
+// processLimitExceeded reads the signal that ended the process. SIGXCPU
+// is the CPU soft limit, SIGKILL after using the CPU time the hard limit.
+// SIGABRT and SIGSEGV are how processes commonly die when allocations
+// fail, so they suggest the memory limit.
+func processLimitExceeded(limits *config.ResourceLimits, state *os.ProcessState, _ *limitJob) *LimitExceededError {
+	status, ok := state.Sys().(syscall.WaitStatus)
+	if !ok || !status.Signaled() {
+		return nil
+	}
+
+	cpuLimit := time.Duration(limits.CPUSeconds) * time.Second
+	switch sig := status.Signal(); {
+	case limits.CPUSeconds > 0 && sig == syscall.SIGXCPU,
+		limits.CPUSeconds > 0 && sig == syscall.SIGKILL && state.UserTime()+state.SystemTime() >= cpuLimit:
+		return cpuLimitError(limits, state)
+	case limits.MemoryMB > 0 && (sig == syscall.SIGABRT || sig == syscall.SIGSEGV):
+		return memoryLimitError(limits, state, true)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+//go:build windows
+
+package spawner
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"unsafe"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"golang.org/x/sys/windows"
+)
+
+// limitCommand returns the command unchanged; on Windows the limits are
+// applied by applyLimits once the process has started.
+func limitCommand(limits *config.ResourceLimits, command string, args []string) (string, []string) {
+	return command, args
+}
+
+// limitJob is the job object a limited process is assigned to, kept open
+// so its accounting can be read after the process exits.
+type limitJob struct {
+	handle windows.Handle
+}
+
+func (job *limitJob) close() {
+	if job != nil && job.handle != 0 {
+		windows.CloseHandle(job.handle)
+		job.handle = 0
+	}
+}
+
+// jobAccounting is JOBOBJECT_BASIC_ACCOUNTING_INFORMATION.
+type jobAccounting struct {
+	TotalUserTime             int64
+	TotalKernelTime           int64
+	ThisPeriodTotalUserTime   int64
+	ThisPeriodTotalKernelTime int64
+	TotalPageFaultCount       uint32
+	TotalProcesses            uint32
+	ActiveProcesses           uint32
+	TotalTerminatedProcesses  uint32
+}
+
+// applyLimits assigns a started process to a job object with the
+// configured per-process memory and user-mode CPU time limits. Processes
+// it starts later (e.g. node under npx) join the job too.
+func applyLimits(cmd *exec.Cmd, limits *config.ResourceLimits) (*limitJob, error) {
+	var info windows.JOBOBJECT_EXTENDED_LIMIT_INFORMATION
+	if limits.MemoryMB > 0 {
+		info.BasicLimitInformation.LimitFlags |= windows.JOB_OBJECT_LIMIT_PROCESS_MEMORY
+		info.ProcessMemoryLimit = uintptr(limits.MemoryMB) << 20
+	}
+	if limits.CPUSeconds > 0 {
+		info.BasicLimitInformation.LimitFlags |= windows.JOB_OBJECT_LIMIT_PROCESS_TIME
+		// In 100-nanosecond ticks
+		info.BasicLimitInformation.PerProcessUserTimeLimit = int64(limits.CPUSeconds) * 10_000_000
+	}
+	if info.BasicLimitInformation.LimitFlags == 0 {
+		return nil, nil
+	}
+
+	handle, err := windows.CreateJobObject(nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create job object: %w", err)
+	}
+	job := &limitJob{handle: handle}
+
+	if _, err := windows.SetInformationJobObject(handle, windows.JobObjectExtendedLimitInformation,
+		uintptr(unsafe.Pointer(&info)), uint32(unsafe.Sizeof(info))); err != nil {
+		job.close()
+		return nil, fmt.Errorf("failed to set job object limits: %w", err)
+	}
+
+	process, err := windows.OpenProcess(windows.PROCESS_SET_QUOTA|windows.PROCESS_TERMINATE, false, uint32(cmd.Process.Pid))
+	if err != nil {
+		job.close()
+		return nil, fmt.Errorf("failed to open process: %w", err)
+	}
+	defer windows.CloseHandle(process)
+
+	if err := windows.AssignProcessToJobObject(handle, process); err != nil {
+		job.close()
+		return nil, fmt.Errorf("failed to assign process to job object: %w", err)
+	}
+	return job, nil
+}
+
+// processLimitExceeded asks the job object whether it terminated a
+// process, which it only does at the CPU time limit; the memory limit
+// fails allocations instead, which often ends in STATUS_NO_MEMORY.
+func processLimitExceeded(limits *config.ResourceLimits, state *os.ProcessState, job *limitJob) *LimitExceededError {
+	if limits.CPUSeconds > 0 && job != nil {
+		var info jobAccounting
+		err := windows.QueryInformationJobObject(job.handle, windows.JobObjectBasicAccountingInformation,
+			uintptr(unsafe.Pointer(&info)), uint32(unsafe.Sizeof(info)), nil)
+		if err == nil && info.TotalTerminatedProcesses > 0 {
+			return cpuLimitError(limits, state)
+		}
+	}
+	if limits.MemoryMB > 0 && windows.NTStatus(state.ExitCode()) == windows.STATUS_NO_MEMORY {
+		return memoryLimitError(limits, state, true)
+	}
+	return nil
+}
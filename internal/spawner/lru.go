@@ -0,0 +1,139 @@
+package spawner
+
+import (
+	"log"
+	"sort"
+	"time"
+)
+
+// ProcessStats describes one running child process for status reports.
+type ProcessStats struct {
+	// Server is the config name the process runs.
+	Server string `json:"server"`
+
+	// PID is the process ID (of the container runtime CLI for containers).
+	PID int `json:"pid"`
+
+	// Started is when the process was spawned; LastUsed when a request was
+	// last sent to it.
+	Started  time.Time `json:"started"`
+	LastUsed time.Time `json:"lastUsed"`
+
+	// ActiveRequests counts calls using the process.
+	ActiveRequests int `json:"activeRequests"`
+
+	// MemoryBytes is the resident memory, 0 when it cannot be read.
+	MemoryBytes int64 `json:"memoryBytes,omitempty"`
+}
+
+// SetMaxSize sets how many children the pool keeps running. Spawning
+// another server first stops the least recently used idle child; when
+// every child is busy the pool grows past the size instead (use
+// SetMaxChildren for a hard limit). 0 disables eviction.
+func (p *Pool) SetMaxSize(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.maxSize = n
+}
+
+// MaxSize returns the pool size set by NewPool or SetMaxSize.
+func (p *Pool) MaxSize() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.maxSize
+}
+
+// SetPinned replaces the servers that are never stopped to make room in
+// the pool; they do not count against its size either (preload servers).
+func (p *Pool) SetPinned(names []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.pinned = make(map[string]bool, len(names))
+	for _, name := range names {
+		p.pinned[name] = true
+	}
+}
+
+// evictLocked stops least recently used idle children until at most keep
+// count against the pool size. Callers must hold p.mu.
+func (p *Pool) evictLocked(keep int) {
+	if p.maxSize <= 0 {
+		return
+	}
+	for p.pooledLocked() > keep {
+		name := p.leastRecentlyUsedLocked()
+		if name == "" {
+			log.Printf("Warning: all %d pooled processes are busy, exceeding processPoolSize", p.pooledLocked())
+			return
+		}
+		idle := time.Since(p.processes[name].lastUsed).Round(time.Second)
+		log.Printf("Stopping %s to stay within processPoolSize %d (idle %v)", name, p.maxSize, idle)
+		p.stopLocked(name)
+	}
+}
+
+// evictable reports whether a server's process counts against the pool
+// size: it is live, has been used by a call and is not pinned. Callers
+// must hold p.mu.
+func (p *Pool) evictable(name string, proc *Process) bool {
+	return proc.alive() && proc.pooled && !p.pinned[name]
+}
+
+// pooledLocked counts the processes that count against the pool size.
+// Callers must hold p.mu.
+func (p *Pool) pooledLocked() int {
+	count := 0
+	for name, proc := range p.processes {
+		if p.evictable(name, proc) {
+			count++
+		}
+	}
+	return count
+}
+
+// leastRecentlyUsedLocked returns the evictable child without requests in
+// flight that was used longest ago, or "" if there is none. Callers must
+// hold p.mu.
+func (p *Pool) leastRecentlyUsedLocked() string {
+	var oldest string
+	for name, proc := range p.processes {
+		if !p.evictable(name, proc) || proc.active.Load() > 0 {
+			continue
+		}
+		if oldest == "" || proc.lastUsed.Before(p.processes[oldest].lastUsed) {
+			oldest = name
+		}
+	}
+	return oldest
+}
+
+// Stats returns the running child processes, least recently used first.
+func (p *Pool) Stats() []ProcessStats {
+	p.mu.Lock()
+	stats := make([]ProcessStats, 0, len(p.processes))
+	for name, proc := range p.processes {
+		if !proc.alive() {
+			continue
+		}
+		stat := ProcessStats{
+			Server:         name,
+			Started:        proc.started,
+			LastUsed:       proc.lastUsed,
+			ActiveRequests: int(proc.active.Load()),
+		}
+		if proc.cmd != nil && proc.cmd.Process != nil {
+			stat.PID = proc.cmd.Process.Pid
+		}
+		stats = append(stats, stat)
+	}
+	p.mu.Unlock()
+
+	// Reading memory may run ps, so it happens outside the pool lock
+	for i := range stats {
+		if stats[i].PID > 0 {
+			stats[i].MemoryBytes = processMemory(stats[i].PID)
+		}
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].LastUsed.Before(stats[j].LastUsed) })
+	return stats
+}
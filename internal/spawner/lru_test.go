@@ -0,0 +1,130 @@
+package spawner
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/mcptest"
+)
+
+// writeIdleServer writes a fake MCP server that answers initialize and
+// lists no tools.
+func writeIdleServer(t *testing.T) *config.ServerConfig {
+	t.Helper()
+	return mcptest.Server(t, mcptest.Handlers{
+		"initialize": mcptest.Result(`{}`),
+		"tools/list": mcptest.Result(`{"tools":[]}`),
+	})
+}
+
+// running returns the names of the pool's running servers.
+func running(pool *Pool) map[string]bool {
+	names := make(map[string]bool)
+	for _, stat := range pool.Stats() {
+		names[stat.Server] = true
+	}
+	return names
+}
+
+// TestPoolEvictsLeastRecentlyUsed verifies spawning past the pool size
+// stops the idle server used longest ago.
+func TestPoolEvictsLeastRecentlyUsed(t *testing.T) {
+	cfg := writeIdleServer(t)
+	pool := NewPool(2)
+	defer pool.Close()
+
+	for _, name := range []string{"a", "b"} {
+		if err := pool.Prespawn(name, cfg); err != nil {
+			t.Fatalf("Prespawn %s failed: %v", name, err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	// Using a again makes b the least recently used
+	if err := pool.Prespawn("a", cfg); err != nil {
+		t.Fatal(err)
+	}
+	if err := pool.Prespawn("c", cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if names := running(pool); len(names) != 2 || !names["a"] || !names["c"] {
+		t.Errorf("expected b to be evicted, running: %v", names)
+	}
+	stats := pool.Stats()
+	if stats[0].Server != "a" || stats[0].PID == 0 || stats[0].Started.IsZero() {
+		t.Errorf("unexpected stats, least recently used first: %+v", stats)
+	}
+}
+
+// TestPoolKeepsBusyProcesses verifies processes in use are not evicted
+// and the pool grows past its size instead.
+func TestPoolKeepsBusyProcesses(t *testing.T) {
+	cfg := writeIdleServer(t)
+	pool := NewPool(1)
+	defer pool.Close()
+
+	busy, err := pool.getOrSpawn("a", cfg, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pool.Prespawn("b", cfg); err != nil {
+		t.Fatal(err)
+	}
+	if names := running(pool); !names["a"] || !names["b"] {
+		t.Errorf("busy server should not be evicted, running: %v", names)
+	}
+
+	busy.release()
+	pool.SetMaxSize(0)
+	if err := pool.Prespawn("c", cfg); err != nil {
+		t.Fatal(err)
+	}
+	if names := running(pool); len(names) != 3 {
+		t.Errorf("size 0 should never evict, running: %v", names)
+	}
+}
+
+// TestProcessMemory verifies the resident memory of a live process is read.
+func TestProcessMemory(t *testing.T) {
+	if got := processMemory(os.Getpid()); got <= 0 {
+		t.Skipf("resident memory not available on this system (%d)", got)
+	}
+	if got := processMemory(-1); got != 0 {
+		t.Errorf("expected 0 for an invalid pid, got %d", got)
+	}
+}
+
+// TestPoolSkipsPinnedAndDiscovered verifies pinned servers and processes
+// spawned only to list tools neither count against nor are evicted from
+// the pool.
+func TestPoolSkipsPinnedAndDiscovered(t *testing.T) {
+	cfg := writeIdleServer(t)
+	pool := NewPool(1)
+	pool.SetPinned([]string{"preload"})
+	defer pool.Close()
+
+	if err := pool.Prespawn("preload", cfg); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a", "b"} {
+		if _, err := pool.DiscoverTools(name, cfg); err != nil {
+			t.Fatalf("DiscoverTools %s failed: %v", name, err)
+		}
+	}
+	if err := pool.Prespawn("c", cfg); err != nil {
+		t.Fatal(err)
+	}
+	if names := running(pool); len(names) != 4 {
+		t.Errorf("pinned and discovered servers should not be evicted, running: %v", names)
+	}
+
+	// Once a call uses a discovered server it joins the pool
+	if err := pool.Prespawn("a", cfg); err != nil {
+		t.Fatal(err)
+	}
+	if names := running(pool); names["c"] || !names["a"] || !names["preload"] || !names["b"] {
+		t.Errorf("expected c to be evicted, running: %v", names)
+	}
+}
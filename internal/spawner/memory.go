@@ -0,0 +1,50 @@
+package spawner
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// processMemory returns a process's resident memory in bytes, read from
+// /proc on Linux and from ps elsewhere, or 0 if it cannot be determined.
+func processMemory(pid int) int64 {
+	if kb, ok := procStatusRSS(pid); ok {
+		return kb * 1024
+	}
+	if runtime.GOOS == "windows" {
+		return 0
+	}
+	out, err := exec.Command("ps", "-o", "rss=", "-p", strconv.Itoa(pid)).Output()
+	if err != nil {
+		return 0
+	}
+	kb, err := strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return kb * 1024
+}
+
+// procStatusRSS reads VmRSS (in kB) from /proc/<pid>/status.
+func procStatusRSS(pid int) (int64, bool) {
+	f, err := os.Open(fmt.Sprintf("/proc/%d/status", pid))
+	if err != nil {
+		return 0, false
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "VmRSS:" {
+			kb, err := strconv.ParseInt(fields[1], 10, 64)
+			return kb, err == nil
+		}
+	}
+	return 0, false
+}
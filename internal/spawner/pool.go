@@ -3,7 +3,8 @@ Package spawner handles on-demand spawning and management of child MCP servers.
 
 The spawner maintains a pool of active processes and handles:
   - Lazy spawning (only when a tool is first executed)
-  - Process lifecycle management
+  - Process lifecycle management, keeping at most the pool size running
+    by stopping the least recently used idle child
   - Communication with child MCP servers via stdio
   - Timeout handling
 */
@@ -12,33 +13,74 @@ package spawner
 import (
 	"bufio"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"os/exec"
+	"runtime"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/protocol"
+	"github.com/khanglvm/tool-hub-mcp/internal/telemetry"
 )
 
-// Tool represents a tool definition from a child MCP server.
-type Tool struct {
-	Name        string      `json:"name"`
-	Description string      `json:"description"`
-	InputSchema interface{} `json:"inputSchema"`
+// ServerInfo identifies a child server implementation, as reported in
+// the serverInfo field of its initialize response, along with the protocol
+// revision it agreed to.
+type ServerInfo struct {
+	Name            string `json:"name"`
+	Version         string `json:"version"`
+	ProtocolVersion string `json:"protocolVersion,omitempty"`
 }
 
 // Pool manages a pool of child MCP server processes.
 type Pool struct {
+	// maxSize is how many children are kept running; spawning beyond it
+	// evicts the least recently used idle one (0 = unlimited)
 	maxSize int
 	mu      sync.Mutex
 
+	// pinned names servers that are never evicted and do not count
+	// against maxSize (preload servers)
+	pinned map[string]bool
+
 	// processes maps server names to active processes
 	processes map[string]*Process
+
+	// pending maps server names to spawns in progress
+	pending map[string]*pendingSpawn
+
+	// defaultEnv is injected into every child before its own env
+	defaultEnv map[string]string
+
+	// onSpawn is notified after a child starts successfully (optional)
+	onSpawn func(name string)
+
+	// onNotification receives child notifications (optional)
+	onNotification NotificationFunc
+
+	// maxCalls bounds in-flight tools/call requests per server (0 = unlimited)
+	maxCalls int
+	limiters map[string]*callLimiter
+
+	// maxChildren bounds running child processes (0 = unlimited)
+	maxChildren int
+
+	// tracer records a span per request sent to a child (optional)
+	tracer *telemetry.Exporter
+
+	// retry governs retries of transient failures (zero = no retries)
+	retry RetryPolicy
 }
 
 // Process represents a running MCP server process.
@@ -53,16 +95,148 @@ type Process struct {
 	reqID int64
 	// cancel cancels the stderr draining goroutine on process termination
 	cancel context.CancelFunc
+	// serverInfo is the child's self-reported name and version
+	serverInfo ServerInfo
+	// capabilities are the features the child declared in initialize
+	capabilities Capabilities
+	// container is set when the process runs a containerized server
+	container *containerRef
+	// limits are the resource limits the process runs under (optional)
+	limits *config.ResourceLimits
+	// job tells which limit the process exceeded where the OS keeps
+	// that apart from its exit status (Windows)
+	job *limitJob
+	// waitOnce guards cmd.Wait, which may be called only once
+	waitOnce sync.Once
+	waitErr  error
+
+	// responses carries response lines from readLoop to sendRequest;
+	// inFlight is the ID of the request awaiting one (0 when none)
+	responses chan []byte
+	inFlight  atomic.Int64
+	// closed is closed when stdout ends; readErr holds the reason
+	closed  chan struct{}
+	readErr error
+	// onNotification receives notifications the child sends (optional)
+	onNotification func(method string, params json.RawMessage)
+	// name and tracer label the spans sendRequest records (tracer optional)
+	name   string
+	tracer *telemetry.Exporter
+
+	// started is when the process was spawned; lastUsed when the pool last
+	// handed it to a caller (guarded by the pool lock), for LRU eviction
+	started  time.Time
+	lastUsed time.Time
+	// active counts callers using the process (see getOrSpawn); busy
+	// processes are never evicted
+	active atomic.Int32
+	// pooled is false for a process spawned to list tools for the index
+	// until a call uses it; only pooled processes count against maxSize
+	// (guarded by the pool lock)
+	pooled bool
 }
 
+// NotificationFunc receives a notification sent by a child server.
+type NotificationFunc func(server, method string, params json.RawMessage)
+
 // NewPool creates a new process pool.
 func NewPool(maxSize int) *Pool {
 	return &Pool{
 		maxSize:   maxSize,
 		processes: make(map[string]*Process),
+		pending:   make(map[string]*pendingSpawn),
+		limiters:  make(map[string]*callLimiter),
 	}
 }
 
+// SetDefaultEnv sets variables injected into every newly spawned child.
+// A server's own env entries override these defaults.
+func (p *Pool) SetDefaultEnv(env map[string]string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.defaultEnv = env
+}
+
+// SetTracer records a span for every request sent to a child started
+// after the call. A nil exporter disables tracing.
+func (p *Pool) SetTracer(tracer *telemetry.Exporter) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.tracer = tracer
+}
+
+// SetSpawnObserver registers a callback invoked with the server name each
+// time a child process is spawned and initialized (e.g., for metrics).
+// The callback runs while the pool lock is held and must not block.
+func (p *Pool) SetSpawnObserver(fn func(name string)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onSpawn = fn
+}
+
+// SetMaxConcurrentCalls bounds in-flight tool calls per server; further
+// calls wait in a first-come, first-served queue. 0 disables the limit.
+// Raising or removing the limit admits queued calls immediately.
+func (p *Pool) SetMaxConcurrentCalls(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.maxCalls = n
+	for name, l := range p.limiters {
+		l.setLimit(n)
+		if n <= 0 {
+			delete(p.limiters, name)
+		}
+	}
+}
+
+// SetMaxChildren bounds the number of child processes running at once.
+// Spawning another server fails with a ChildLimitError until one exits or
+// is stopped. 0 disables the limit.
+func (p *Pool) SetMaxChildren(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.maxChildren = n
+}
+
+// QueuedCalls returns the number of calls waiting for a slot on a server.
+func (p *Pool) QueuedCalls(name string) int {
+	p.mu.Lock()
+	limiter := p.limiters[name]
+	p.mu.Unlock()
+
+	if limiter == nil {
+		return 0
+	}
+	return limiter.queued()
+}
+
+// limiter returns the server's call limiter, or nil when calls are unlimited.
+func (p *Pool) limiter(name string) *callLimiter {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.maxCalls <= 0 {
+		return nil
+	}
+	l, ok := p.limiters[name]
+	if !ok {
+		l = &callLimiter{limit: p.maxCalls}
+		p.limiters[name] = l
+	}
+	return l
+}
+
+// SetNotificationHandler registers a callback for notifications sent by
+// children (e.g., notifications/tools/list_changed). It applies to servers
+// spawned afterwards and runs on the child's reader goroutine, so it must
+// not call back into the same server synchronously.
+func (p *Pool) SetNotificationHandler(fn NotificationFunc) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onNotification = fn
+}
+
 // Close terminates all spawned processes and cleans up resources.
 // Implements graceful shutdown: closes stdin first, waits 2s, then force kills.
 func (p *Pool) Close() error {
@@ -84,7 +258,7 @@ func (p *Pool) Close() error {
 		// Step 2: Wait briefly for graceful exit (2s timeout)
 		done := make(chan error, 1)
 		go func() {
-			done <- proc.cmd.Wait()
+			done <- proc.wait()
 		}()
 
 		select {
@@ -109,22 +283,79 @@ func (p *Pool) Close() error {
 	return nil
 }
 
-// GetTools spawns a server (if needed) and returns its tool list.
-func (p *Pool) GetTools(name string, cfg *config.ServerConfig) ([]Tool, error) {
-	proc, err := p.getOrSpawn(name, cfg)
-	if err != nil {
-		return nil, err
+// Rename moves a server's call limiter to a new name and stops its running
+// process, which reports notifications under the old name; the next call
+// spawns it again as newName.
+func (p *Pool) Rename(oldName, newName string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if l, ok := p.limiters[oldName]; ok {
+		delete(p.limiters, oldName)
+		p.limiters[newName] = l
 	}
+	p.stopLocked(oldName)
+}
 
-	// Send tools/list request
-	response, err := proc.sendRequest("tools/list", nil)
-	if err != nil {
+// Stop terminates a server's running process, if any, so the next call
+// spawns it again with its current configuration.
+func (p *Pool) Stop(name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.stopLocked(name)
+}
+
+// stopLocked kills a server's running process. Callers must hold p.mu.
+func (p *Pool) stopLocked(name string) {
+	if proc, ok := p.processes[name]; ok {
+		delete(p.processes, name)
+		if proc.stdin != nil {
+			proc.stdin.Close()
+		}
+		proc.kill()
+	}
+}
+
+// GetTools spawns a server (if needed) and returns its tool list.
+// Transient failures are retried according to the retry policy.
+func (p *Pool) GetTools(name string, cfg *config.ServerConfig) ([]protocol.Tool, error) {
+	return p.listTools(name, cfg, true)
+}
+
+// DiscoverTools is GetTools for indexing: a server it spawns does not
+// count against the pool size, so listing every server never stops the
+// ones in use. The process joins the pool once a call uses it.
+func (p *Pool) DiscoverTools(name string, cfg *config.ServerConfig) ([]protocol.Tool, error) {
+	return p.listTools(name, cfg, false)
+}
+
+// listTools returns a server's tool list; pooled is passed to getOrSpawn.
+func (p *Pool) listTools(name string, cfg *config.ServerConfig, pooled bool) ([]protocol.Tool, error) {
+	var response interface{}
+	err := p.withRetry(name, "tools/list", nil, func() (*Process, error) {
+		proc, err := p.getOrSpawn(name, cfg, pooled)
+		if err != nil {
+			return nil, err
+		}
+		defer proc.release()
+
+		// A child that declared capabilities without tools has nothing to list
+		if !proc.capabilities.Tools {
+			response = nil
+			return proc, nil
+		}
+
+		// Send tools/list request
+		response, err = proc.sendRequest("tools/list", nil)
+		return proc, err
+	})
+	if err != nil || response == nil {
 		return nil, err
 	}
 
 	// Parse response
 	var result struct {
-		Tools []Tool `json:"tools"`
+		Tools []protocol.Tool `json:"tools"`
 	}
 
 	resultBytes, err := json.Marshal(response)
@@ -141,18 +372,7 @@ func (p *Pool) GetTools(name string, cfg *config.ServerConfig) ([]Tool, error) {
 
 // ExecuteTool executes a tool on a child server.
 func (p *Pool) ExecuteTool(name string, cfg *config.ServerConfig, toolName string, args map[string]interface{}) (string, error) {
-	proc, err := p.getOrSpawn(name, cfg)
-	if err != nil {
-		return "", err
-	}
-
-	// Send tools/call request
-	params := map[string]interface{}{
-		"name":      toolName,
-		"arguments": args,
-	}
-
-	response, err := proc.sendRequest("tools/call", params)
+	response, err := p.CallTool(name, cfg, toolName, args)
 	if err != nil {
 		return "", err
 	}
@@ -166,6 +386,66 @@ func (p *Pool) ExecuteTool(name string, cfg *config.ServerConfig, toolName strin
 	return string(resultBytes), nil
 }
 
+// CallTool executes a tool on a child server and returns the decoded
+// tools/call result, preserving its content blocks.
+func (p *Pool) CallTool(name string, cfg *config.ServerConfig, toolName string, args map[string]interface{}) (interface{}, error) {
+	return p.CallToolWithOptions(name, cfg, toolName, args, CallOptions{})
+}
+
+// CallOptions tunes a single tool call.
+type CallOptions struct {
+	// OnQueued is called if the call has to wait behind others because
+	// the server is at its concurrency limit.
+	OnQueued QueueFunc
+
+	// ProgressToken is sent to the child as _meta.progressToken so it can
+	// report progress for this call via notifications/progress.
+	ProgressToken interface{}
+
+	// Idempotent allows the call itself to be retried after a transient
+	// failure. Spawning the server is retried either way, as the tool has
+	// not been reached yet.
+	Idempotent bool
+
+	// OnRetry is called before each retry.
+	OnRetry RetryFunc
+}
+
+// CallToolWithOptions is CallTool with per-call options. Queued calls wait
+// for a slot instead of failing; the response timeout starts once the
+// call is admitted.
+func (p *Pool) CallToolWithOptions(name string, cfg *config.ServerConfig, toolName string, args map[string]interface{}, opts CallOptions) (interface{}, error) {
+	if limiter := p.limiter(name); limiter != nil {
+		limiter.acquire(opts.OnQueued)
+		defer limiter.release()
+	}
+
+	// Send tools/call request
+	params := map[string]interface{}{
+		"name":      toolName,
+		"arguments": args,
+	}
+	if opts.ProgressToken != nil {
+		params["_meta"] = map[string]interface{}{"progressToken": opts.ProgressToken}
+	}
+
+	var response interface{}
+	err := p.withRetry(name, "tools/call", opts.OnRetry, func() (*Process, error) {
+		proc, err := p.getOrSpawn(name, cfg, true)
+		if err != nil {
+			return nil, err
+		}
+		defer proc.release()
+		response, err = proc.sendRequest("tools/call", params)
+		if err != nil && !opts.Idempotent {
+			// The tool may have run; only idempotent calls are sent twice
+			return proc, &finalError{err: err}
+		}
+		return proc, err
+	})
+	return response, err
+}
+
 // GetToolHelp gets detailed help for a specific tool.
 func (p *Pool) GetToolHelp(name string, cfg *config.ServerConfig, toolName string) (string, error) {
 	tools, err := p.GetTools(name, cfg)
@@ -186,20 +466,166 @@ func (p *Pool) GetToolHelp(name string, cfg *config.ServerConfig, toolName strin
 	return "", fmt.Errorf("tool '%s' not found on server '%s'", toolName, name)
 }
 
-// getOrSpawn returns an existing process or spawns a new one.
-func (p *Pool) getOrSpawn(name string, cfg *config.ServerConfig) (*Process, error) {
+// ServerInfo returns the name and version reported by a running server.
+// Returns false if the server has not been spawned.
+func (p *Pool) ServerInfo(name string) (ServerInfo, bool) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
+	proc, exists := p.processes[name]
+	if !exists {
+		return ServerInfo{}, false
+	}
+	return proc.serverInfo, true
+}
+
+// Capabilities returns the capabilities a running server declared.
+// Returns false if the server has not been spawned.
+func (p *Pool) Capabilities(name string) (Capabilities, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	proc, exists := p.processes[name]
+	if !exists {
+		return Capabilities{}, false
+	}
+	return proc.capabilities, true
+}
+
+// ToolsFingerprint returns a stable hash of a tool list (names, descriptions,
+// and schemas), independent of the order the server listed them in.
+func ToolsFingerprint(tools []protocol.Tool) string {
+	sorted := append([]protocol.Tool{}, tools...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	data, err := json.Marshal(sorted)
+	if err != nil {
+		return ""
+	}
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+// pendingSpawn is a spawn in progress; concurrent callers for the same
+// server wait on done instead of starting a second process.
+type pendingSpawn struct {
+	done chan struct{}
+	proc *Process
+	err  error
+}
+
+// Prespawn starts and initializes a server ahead of its first call.
+// It is a no-op if the server is already running.
+func (p *Pool) Prespawn(name string, cfg *config.ServerConfig) error {
+	return p.withRetry(name, "initialize", nil, func() (*Process, error) {
+		proc, err := p.getOrSpawn(name, cfg, true)
+		if err == nil {
+			proc.release()
+		}
+		return proc, err
+	})
+}
+
+// getOrSpawn returns an existing process or spawns a new one. A process
+// that has exited is replaced. The process counts as in use, and cannot
+// be evicted, until the caller calls release. A pooled use counts the
+// process against the pool size, stopping idle ones to make room.
+// The pool lock is not held while a child starts, so different servers
+// spawn in parallel; callers for the same server share one spawn.
+func (p *Pool) getOrSpawn(name string, cfg *config.ServerConfig, pooled bool) (*Process, error) {
+	p.mu.Lock()
 	if proc, exists := p.processes[name]; exists {
-		return proc, nil
+		if proc.alive() {
+			proc.lastUsed = time.Now()
+			proc.active.Add(1)
+			if pooled && !proc.pooled {
+				proc.pooled = true
+				p.evictLocked(p.maxSize)
+			}
+			p.mu.Unlock()
+			return proc, nil
+		}
+		p.stopLocked(name)
+	}
+	if pending, exists := p.pending[name]; exists {
+		p.mu.Unlock()
+		<-pending.done
+		if pending.err == nil {
+			pending.proc.active.Add(1)
+			if pooled {
+				p.mu.Lock()
+				if !pending.proc.pooled {
+					pending.proc.pooled = true
+					p.evictLocked(p.maxSize)
+				}
+				p.mu.Unlock()
+			}
+		}
+		return pending.proc, pending.err
 	}
+	if pooled {
+		p.evictLocked(p.maxSize - 1)
+	}
+	if p.maxChildren > 0 && p.runningLocked() >= p.maxChildren {
+		p.mu.Unlock()
+		return nil, &ChildLimitError{Server: name, Max: p.maxChildren}
+	}
+	pending := &pendingSpawn{done: make(chan struct{})}
+	p.pending[name] = pending
+	defaultEnv := p.defaultEnv
+	onNotification := p.onNotification
+	tracer := p.tracer
+	p.mu.Unlock()
+
+	pending.proc, pending.err = start(name, cfg, defaultEnv, onNotification, tracer)
+
+	p.mu.Lock()
+	delete(p.pending, name)
+	if pending.err == nil {
+		pending.proc.started = time.Now()
+		pending.proc.lastUsed = pending.proc.started
+		pending.proc.active.Add(1)
+		pending.proc.pooled = pooled
+		p.processes[name] = pending.proc
+		if p.onSpawn != nil {
+			p.onSpawn(name)
+		}
+	}
+	p.mu.Unlock()
+	close(pending.done)
+
+	return pending.proc, pending.err
+}
+
+// release ends a use of the process started by getOrSpawn.
+func (proc *Process) release() {
+	proc.active.Add(-1)
+}
+
+// runningLocked counts live and starting children. Callers must hold p.mu.
+func (p *Pool) runningLocked() int {
+	running := len(p.pending)
+	for _, proc := range p.processes {
+		if proc.alive() {
+			running++
+		}
+	}
+	return running
+}
 
-	// Spawn new process
-	proc, err := p.spawn(cfg)
+// start spawns and initializes a server process.
+func start(name string, cfg *config.ServerConfig, defaultEnv map[string]string, onNotification NotificationFunc, tracer *telemetry.Exporter) (*Process, error) {
+	proc, err := spawn(cfg, defaultEnv)
 	if err != nil {
 		return nil, err
 	}
+	proc.name, proc.tracer = name, tracer
+	if onNotification != nil {
+		proc.onNotification = func(method string, params json.RawMessage) {
+			onNotification(name, method, params)
+		}
+	}
+	go proc.readLoop()
 
 	// Initialize the server
 	if err := proc.initialize(); err != nil {
@@ -208,13 +634,17 @@ func (p *Pool) getOrSpawn(name string, cfg *config.ServerConfig) (*Process, erro
 		if strings.Contains(err.Error(), "EOF") {
 			pkg := getNpmPackageFromConfig(cfg)
 			if pkg != "" {
-				return nil, fmt.Errorf("MCP server failed to start. Package '%s' may not exist or failed to load. Verify with: npm view %s", pkg, pkg)
+				msg := fmt.Errorf("MCP server failed to start. Package '%s' may not exist or failed to load. Verify with: npm view %s", pkg, pkg)
+				if isTransient(err) {
+					// An EOF right after a cold start may not recur
+					return nil, &transientError{err: msg}
+				}
+				return nil, msg
 			}
 		}
 		return nil, fmt.Errorf("failed to initialize server: %w", err)
 	}
 
-	p.processes[name] = proc
 	return proc, nil
 }
 
@@ -222,15 +652,65 @@ func (p *Pool) getOrSpawn(name string, cfg *config.ServerConfig) (*Process, erro
 var execCommand = exec.Command
 
 // spawn starts a new MCP server process.
-func (p *Pool) spawn(cfg *config.ServerConfig) (*Process, error) {
-	cmd := execCommand(cfg.Command, cfg.Args...)
+func spawn(cfg *config.ServerConfig, defaultEnv map[string]string) (*Process, error) {
+	if cfg.IsRemote() {
+		transport := cfg.Type
+		if transport == "" {
+			transport = "http"
+		}
+		return nil, fmt.Errorf("remote servers (%s transport) cannot be connected to yet; the server is kept in the config but only stdio servers can be called", transport)
+	}
 
-	// Set environment variables
-	cmd.Env = os.Environ()
-	for key, value := range cfg.Env {
-		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", key, value))
+	// Expand ~ and $VAR references so imported configs work from any cwd
+	cfg = cfg.Expanded()
+
+	// The container runtime CLI keeps the host environment (DOCKER_HOST,
+	// registry credentials); the container itself only gets explicit keys.
+	base := os.Environ()
+	if !cfg.IsContainer() {
+		base = passthroughEnv(base, cfg)
+	}
+	env := buildEnv(base, defaultEnv, cfg.Env)
+
+	var cmd *exec.Cmd
+	var container *containerRef
+	if cfg.IsContainer() {
+		if err := ensureImage(cfg); err != nil {
+			return nil, err
+		}
+		container = &containerRef{runtime: cfg.Runtime, name: containerName()}
+		command, args := containerCommand(cfg, containerEnvKeys(defaultEnv, cfg.Env), container.name)
+		cmd = execCommand(command, args...)
+	} else {
+		if cfg.Cwd != "" {
+			if info, err := os.Stat(cfg.Cwd); err != nil || !info.IsDir() {
+				return nil, fmt.Errorf("working directory %s does not exist or is not a directory", cfg.Cwd)
+			}
+		}
+		command, args := cfg.Command, cfg.Args
+		if cfg.Shell {
+			command, args = shellCommand(command, args)
+		}
+		command, resolvedEnv, err := resolveCommand(command, env)
+		if err != nil {
+			return nil, err
+		}
+		env = resolvedEnv
+		if cfg.Limits != nil {
+			command, args = limitCommand(cfg.Limits, command, args)
+		}
+		cmd = execCommand(command, args...)
+		cmd.Dir = cfg.Cwd
+		if cfg.Sandbox.Enabled() {
+			if err := sandboxCommand(cmd, cfg.Sandbox); err != nil {
+				return nil, err
+			}
+		}
 	}
 
+	// Set environment variables
+	cmd.Env = env
+
 	stdin, err := cmd.StdinPipe()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create stdin pipe: %w", err)
@@ -250,9 +730,22 @@ func (p *Pool) spawn(cfg *config.ServerConfig) (*Process, error) {
 	}
 
 	if err := cmd.Start(); err != nil {
+		if cfg.Sandbox.NoNetwork() && !cfg.IsContainer() && runtime.GOOS == "linux" {
+			return nil, fmt.Errorf("failed to start sandboxed process (network: false needs unprivileged user namespaces): %w", err)
+		}
 		return nil, fmt.Errorf("failed to start process: %w", err)
 	}
 
+	// Containers get their limits from the runtime flags instead
+	var job *limitJob
+	if cfg.Limits != nil && container == nil {
+		if job, err = applyLimits(cmd, cfg.Limits); err != nil {
+			cmd.Process.Kill()
+			cmd.Wait()
+			return nil, fmt.Errorf("failed to apply resource limits: %w", err)
+		}
+	}
+
 	// Create cancellable context for stderr draining goroutine
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -270,18 +763,50 @@ func (p *Pool) spawn(cfg *config.ServerConfig) (*Process, error) {
 	}()
 
 	return &Process{
-		cmd:    cmd,
-		stdin:  stdin,
-		stdout: bufio.NewReader(stdout),
-		cancel: cancel,
+		cmd:       cmd,
+		container: container,
+		limits:    cfg.Limits,
+		job:       job,
+		stdin:     stdin,
+		stdout:    bufio.NewReader(stdout),
+		cancel:    cancel,
+		responses: make(chan []byte, 1),
+		closed:    make(chan struct{}),
 	}, nil
 }
 
+// passthroughEnv keeps the KEY=VALUE pairs of environ the server inherits
+// (ServerConfig.EnvPassthrough), so host secrets don't leak to every child.
+func passthroughEnv(environ []string, cfg *config.ServerConfig) []string {
+	var kept []string
+	for _, kv := range environ {
+		key, _, _ := strings.Cut(kv, "=")
+		// Windows keeps per-drive working directories as "=C:=C:\dir"
+		if key == "" || cfg.InheritsEnv(key) {
+			kept = append(kept, kv)
+		}
+	}
+	return kept
+}
+
+// buildEnv layers pool defaults and server env over the inherited environment.
+// exec.Cmd keeps the last value for duplicate keys, so later layers win.
+func buildEnv(base []string, defaults, serverEnv map[string]string) []string {
+	env := append([]string{}, base...)
+	for key, value := range defaults {
+		env = append(env, fmt.Sprintf("%s=%s", key, value))
+	}
+	for key, value := range serverEnv {
+		env = append(env, fmt.Sprintf("%s=%s", key, value))
+	}
+	return env
+}
+
 // initialize sends the MCP initialize request and initialized notification.
 func (proc *Process) initialize() error {
 	// Step 1: Send initialize request
-	_, err := proc.sendRequest("initialize", map[string]interface{}{
-		"protocolVersion": "2024-11-05",
+	result, err := proc.sendRequest("initialize", map[string]interface{}{
+		"protocolVersion": protocol.Latest,
 		"capabilities":    map[string]interface{}{},
 		"clientInfo": map[string]interface{}{
 			"name":    "tool-hub-mcp",
@@ -292,6 +817,23 @@ func (proc *Process) initialize() error {
 		return err
 	}
 
+	// Remember serverInfo for cache fingerprinting (optional in the protocol)
+	proc.capabilities = parseCapabilities(nil)
+	if resultMap, ok := result.(map[string]interface{}); ok {
+		if info, ok := resultMap["serverInfo"].(map[string]interface{}); ok {
+			proc.serverInfo.Name, _ = info["name"].(string)
+			proc.serverInfo.Version, _ = info["version"].(string)
+		}
+		// The child answers with the revision it speaks, which may be older
+		// than requested. Unknown revisions are used as-is: the messages the
+		// hub relies on are unchanged across revisions.
+		proc.capabilities = parseCapabilities(resultMap["capabilities"])
+		proc.serverInfo.ProtocolVersion, _ = resultMap["protocolVersion"].(string)
+		if v := proc.serverInfo.ProtocolVersion; v != "" && !protocol.IsSupported(v) {
+			log.Printf("Warning: server %s speaks unsupported protocol version %s", proc.serverInfo.Name, v)
+		}
+	}
+
 	// Step 2: Send initialized notification (required by MCP protocol)
 	// This is a notification, not a request - no response expected
 	notification := map[string]interface{}{
@@ -315,8 +857,26 @@ func (proc *Process) initialize() error {
 // Set to 60s to handle npx package downloads on cold start.
 const DefaultTimeout = 60 * time.Second
 
-// sendRequest sends a JSON-RPC request and waits for response with timeout.
+// sendRequest sends a JSON-RPC request and waits for response with timeout,
+// recording a client span when the process has a tracer.
 func (proc *Process) sendRequest(method string, params interface{}) (interface{}, error) {
+	span := proc.tracer.Start("spawner.sendRequest", telemetry.KindClient)
+	span.SetAttribute("rpc.system", "jsonrpc")
+	span.SetAttribute("rpc.method", method)
+	span.SetAttribute("mcp.server", proc.name)
+	if method == "tools/call" {
+		if call, ok := params.(map[string]interface{}); ok {
+			span.SetAttribute("mcp.tool", call["name"])
+		}
+	}
+
+	result, err := proc.roundTrip(method, params)
+	span.End(err)
+	return result, err
+}
+
+// roundTrip writes one request and waits for its response.
+func (proc *Process) roundTrip(method string, params interface{}) (interface{}, error) {
 	proc.mu.Lock()
 	defer proc.mu.Unlock()
 
@@ -342,53 +902,166 @@ func (proc *Process) sendRequest(method string, params interface{}) (interface{}
 	}
 	reqBytes = append(reqBytes, '\n')
 
+	proc.inFlight.Store(reqID)
+	defer proc.inFlight.Store(0)
+
+	// Discard a late response to an earlier request that timed out
+	select {
+	case <-proc.responses:
+	default:
+	}
+
 	if _, err := proc.stdin.Write(reqBytes); err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		return nil, &transientError{err: fmt.Errorf("failed to send request: %w", err)}
+	}
+
+	// Wait for the response with timeout. readLoop only passes on responses
+	// to reqID, but one for an earlier request may have been queued just
+	// before reqID was stored.
+	timeout := time.After(DefaultTimeout)
+	for {
+		select {
+		case line := <-proc.responses:
+			if !respondsTo(line, reqID) {
+				continue
+			}
+			return parseResponse(line)
+
+		case <-proc.closed:
+			// Prefer a response written just before the child exited
+			select {
+			case line := <-proc.responses:
+				if respondsTo(line, reqID) {
+					return parseResponse(line)
+				}
+			default:
+			}
+			reason := proc.exitReason()
+			err := fmt.Errorf("failed to read response: %w", reason)
+			var limitErr *LimitExceededError
+			if errors.As(reason, &limitErr) {
+				// Exceeding a resource limit would happen again
+				return nil, err
+			}
+			return nil, &transientError{err: err}
+
+		case <-timeout:
+			return nil, &transientError{err: fmt.Errorf("timeout after %v waiting for MCP response", DefaultTimeout)}
+		}
 	}
+}
 
-	// Read response with timeout
-	responseChan := make(chan []byte, 1)
-	errorChan := make(chan error, 1)
+// respondsTo reports whether a response line answers request id. A null
+// or missing ID, sent with errors for unparseable requests, answers the
+// request in flight.
+func respondsTo(line []byte, id int64) bool {
+	var msg struct {
+		ID json.RawMessage `json:"id"`
+	}
+	if err := json.Unmarshal(line, &msg); err != nil {
+		// parseResponse reports the malformed line
+		return true
+	}
+	if len(msg.ID) == 0 || string(msg.ID) == "null" {
+		return true
+	}
+	var got int64
+	return json.Unmarshal(msg.ID, &got) == nil && got == id
+}
 
-	go func() {
+// RPCError is a JSON-RPC error a child answered a request with: the
+// child is running, but refused or failed the request.
+type RPCError struct {
+	Code    int
+	Message string
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("MCP error %d: %s", e.Code, e.Message)
+}
+
+// parseResponse decodes a JSON-RPC response line into its result.
+func parseResponse(line []byte) (interface{}, error) {
+	var resp struct {
+		JSONRPC string      `json:"jsonrpc"`
+		ID      interface{} `json:"id"`
+		Result  interface{} `json:"result"`
+		Error   *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+
+	if err := json.Unmarshal(line, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if resp.Error != nil {
+		return nil, &RPCError{Code: resp.Error.Code, Message: resp.Error.Message}
+	}
+
+	return resp.Result, nil
+}
+
+// readLoop reads the child's stdout until it closes. Notifications go to
+// onNotification; responses are passed to the request in flight when
+// they answer it (requests are sent one at a time), and late responses to
+// requests that timed out are dropped.
+func (proc *Process) readLoop() {
+	defer close(proc.closed)
+
+	for {
 		line, err := proc.stdout.ReadBytes('\n')
 		if err != nil {
-			errorChan <- fmt.Errorf("failed to read response: %w", err)
+			proc.readErr = err
 			return
 		}
-		responseChan <- line
-	}()
 
-	select {
-	case line := <-responseChan:
-		var resp struct {
-			JSONRPC string      `json:"jsonrpc"`
-			ID      interface{} `json:"id"`
-			Result  interface{} `json:"result"`
-			Error   *struct {
-				Code    int    `json:"code"`
-				Message string `json:"message"`
-			} `json:"error"`
+		var msg struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
 		}
-
-		if err := json.Unmarshal(line, &resp); err != nil {
-			return nil, fmt.Errorf("failed to parse response: %w", err)
+		if json.Unmarshal(line, &msg) == nil && msg.Method != "" {
+			if (len(msg.ID) == 0 || string(msg.ID) == "null") && proc.onNotification != nil {
+				proc.onNotification(msg.Method, msg.Params)
+			}
+			// Requests from the child (e.g., roots/list) are not supported
+			continue
 		}
 
-		if resp.Error != nil {
-			return nil, fmt.Errorf("MCP error %d: %s", resp.Error.Code, resp.Error.Message)
+		inFlight := proc.inFlight.Load()
+		if inFlight == 0 || !respondsTo(line, inFlight) {
+			log.Printf("Warning: dropping unexpected response from child: %s", strings.TrimSpace(string(line)))
+			continue
 		}
+		select {
+		case proc.responses <- line:
+		default:
+			log.Printf("Warning: dropping unexpected response from child: %s", strings.TrimSpace(string(line)))
+		}
+	}
+}
 
-		return resp.Result, nil
-
-	case err := <-errorChan:
-		return nil, err
-
-	case <-time.After(DefaultTimeout):
-		return nil, fmt.Errorf("timeout after %v waiting for MCP response", DefaultTimeout)
+// alive reports whether the process's stdout is still open.
+func (proc *Process) alive() bool {
+	select {
+	case <-proc.closed:
+		return false
+	default:
+		return true
 	}
 }
 
+// wait waits for the process to exit. Unlike cmd.Wait it may be called
+// more than once; later calls return the first result.
+func (proc *Process) wait() error {
+	proc.waitOnce.Do(func() {
+		proc.waitErr = proc.cmd.Wait()
+	})
+	return proc.waitErr
+}
+
 // kill terminates the process and cancels the stderr goroutine.
 func (proc *Process) kill() {
 	// Cancel stderr draining goroutine first
@@ -400,6 +1073,10 @@ func (proc *Process) kill() {
 	if proc.cmd != nil && proc.cmd.Process != nil {
 		proc.cmd.Process.Kill()
 	}
+	if proc.container != nil {
+		proc.container.remove()
+	}
+	proc.job.close()
 }
 
 // getNpmPackageFromConfig extracts npm package name from server config.
@@ -2,13 +2,18 @@ package spawner
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/mcptest"
+	"github.com/khanglvm/tool-hub-mcp/internal/protocol"
 )
 
 func TestNewPool(t *testing.T) {
@@ -293,9 +298,9 @@ func TestProcessKill(t *testing.T) {
 	}
 }
 
-// TestToolStruct verifies Tool struct can be marshalled
+// TestToolStruct verifies protocol.Tool struct can be marshalled
 func TestToolStruct(t *testing.T) {
-	tool := Tool{
+	tool := protocol.Tool{
 		Name:        "test-tool",
 		Description: "A test tool",
 		InputSchema: map[string]interface{}{
@@ -330,3 +335,213 @@ func TestPoolMaxSize(t *testing.T) {
 		})
 	}
 }
+
+// TestBuildEnv verifies that server env overrides pool defaults,
+// which override the inherited environment.
+func TestBuildEnv(t *testing.T) {
+	env := buildEnv(
+		[]string{"TZ=America/Los_Angeles", "PATH=/bin"},
+		map[string]string{"TZ": "UTC", "LANG": "en_US.UTF-8"},
+		map[string]string{"LANG": "de_DE.UTF-8"},
+	)
+
+	// exec.Cmd uses the last value for duplicate keys
+	last := make(map[string]string)
+	for _, kv := range env {
+		key, value, _ := strings.Cut(kv, "=")
+		last[key] = value
+	}
+
+	if last["TZ"] != "UTC" {
+		t.Errorf("expected default TZ to override inherited, got %s", last["TZ"])
+	}
+	if last["LANG"] != "de_DE.UTF-8" {
+		t.Errorf("expected server LANG to override default, got %s", last["LANG"])
+	}
+	if last["PATH"] != "/bin" {
+		t.Errorf("expected inherited PATH to be kept, got %s", last["PATH"])
+	}
+}
+
+// TestPassthroughEnv verifies children only inherit allowed host variables.
+func TestPassthroughEnv(t *testing.T) {
+	environ := []string{"PATH=/bin", "HOME=/home/me", "AWS_SECRET_ACCESS_KEY=s3cr3t", "=C:=C:\\work"}
+
+	got := passthroughEnv(environ, &config.ServerConfig{Command: "npx"})
+	want := []string{"PATH=/bin", "HOME=/home/me", "=C:=C:\\work"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("default passthrough = %v, want %v", got, want)
+	}
+
+	got = passthroughEnv(environ, &config.ServerConfig{Command: "npx", EnvPassthrough: []string{"PATH", "AWS_*"}})
+	want = []string{"PATH=/bin", "AWS_SECRET_ACCESS_KEY=s3cr3t", "=C:=C:\\work"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("explicit passthrough = %v, want %v", got, want)
+	}
+}
+
+// TestServerInfoFromInitialize verifies serverInfo is captured on spawn.
+func TestServerInfoFromInitialize(t *testing.T) {
+	cfg := mcptest.Server(t, mcptest.Handlers{
+		"initialize": mcptest.Result(`{"serverInfo":{"name":"jira-mcp","version":"1.3.0"}}`),
+		"tools/list": mcptest.Result(`{"tools":[{"name":"search"}]}`),
+	})
+
+	pool := NewPool(1)
+	defer pool.Close()
+
+	if _, ok := pool.ServerInfo("jira"); ok {
+		t.Error("expected no info before spawn")
+	}
+
+	tools, err := pool.GetTools("jira", cfg)
+	if err != nil {
+		t.Fatalf("GetTools failed: %v", err)
+	}
+	if len(tools) != 1 {
+		t.Fatalf("expected 1 tool, got %d", len(tools))
+	}
+
+	info, ok := pool.ServerInfo("jira")
+	if !ok || info.Name != "jira-mcp" || info.Version != "1.3.0" {
+		t.Errorf("unexpected server info: %+v (ok=%v)", info, ok)
+	}
+}
+
+// TestProtocolVersionFromInitialize verifies the newest revision is
+// requested and the child's answer is recorded.
+func TestProtocolVersionFromInitialize(t *testing.T) {
+	cfg := mcptest.Server(t, mcptest.Handlers{
+		"initialize": `case "$line" in
+        *'"protocolVersion":"` + protocol.Latest + `"'*) ` +
+			mcptest.Result(`{"protocolVersion":"2025-03-26","serverInfo":{"name":"jira-mcp","version":"1.3.0"}}`) + ` ;;
+        *) ` + mcptest.Result(`{"protocolVersion":"wrong-request"}`) + ` ;;
+      esac`,
+		"tools/list": mcptest.Result(`{"tools":[]}`),
+	})
+
+	pool := NewPool(1)
+	defer pool.Close()
+
+	if _, err := pool.GetTools("jira", cfg); err != nil {
+		t.Fatalf("GetTools failed: %v", err)
+	}
+	info, _ := pool.ServerInfo("jira")
+	if info.ProtocolVersion != protocol.Version20250326 {
+		t.Errorf("expected negotiated version 2025-03-26, got %q", info.ProtocolVersion)
+	}
+}
+
+// TestToolsFingerprint verifies fingerprints ignore order but not content.
+func TestToolsFingerprint(t *testing.T) {
+	a := []protocol.Tool{{Name: "a", Description: "first"}, {Name: "b", Description: "second"}}
+	b := []protocol.Tool{{Name: "b", Description: "second"}, {Name: "a", Description: "first"}}
+	c := []protocol.Tool{{Name: "a", Description: "first"}, {Name: "b", Description: "changed"}}
+
+	if ToolsFingerprint(a) != ToolsFingerprint(b) {
+		t.Error("fingerprint should not depend on order")
+	}
+	if ToolsFingerprint(a) == ToolsFingerprint(c) {
+		t.Error("fingerprint should change with descriptions")
+	}
+}
+
+// TestPrespawnSharesOneSpawn verifies concurrent callers for the same server
+// start one process, while different servers start in parallel.
+func TestPrespawnSharesOneSpawn(t *testing.T) {
+	cfg := mcptest.Server(t, mcptest.Handlers{
+		"initialize": "sleep 0.3; " + mcptest.Result(`{}`),
+	})
+
+	pool := NewPool(3)
+	defer pool.Close()
+
+	spawns := make(chan string, 10)
+	pool.SetSpawnObserver(func(name string) { spawns <- name })
+
+	started := time.Now()
+	errs := make(chan error, 4)
+	for _, name := range []string{"a", "a", "b", "b"} {
+		go func(name string) { errs <- pool.Prespawn(name, cfg) }(name)
+	}
+	for i := 0; i < 4; i++ {
+		if err := <-errs; err != nil {
+			t.Fatalf("Prespawn failed: %v", err)
+		}
+	}
+	if elapsed := time.Since(started); elapsed > 550*time.Millisecond {
+		t.Errorf("servers should start in parallel, took %v", elapsed)
+	}
+
+	close(spawns)
+	counts := make(map[string]int)
+	for name := range spawns {
+		counts[name]++
+	}
+	if counts["a"] != 1 || counts["b"] != 1 {
+		t.Errorf("expected one spawn per server, got %v", counts)
+	}
+}
+
+// TestChildNotifications verifies notifications are routed to the handler
+// and do not get mistaken for the response they precede.
+func TestChildNotifications(t *testing.T) {
+	cfg := mcptest.Server(t, mcptest.Handlers{
+		"initialize": mcptest.Result(`{}`),
+		"tools/list": mcptest.Notify("notifications/tools/list_changed", "") + "; " +
+			mcptest.Result(`{"tools":[{"name":"search"}]}`),
+	})
+
+	pool := NewPool(1)
+	defer pool.Close()
+
+	notified := make(chan string, 1)
+	pool.SetNotificationHandler(func(server, method string, params json.RawMessage) {
+		notified <- server + " " + method
+	})
+
+	tools, err := pool.GetTools("jira", cfg)
+	if err != nil || len(tools) != 1 {
+		t.Fatalf("expected 1 tool, got %v, %v", tools, err)
+	}
+
+	select {
+	case got := <-notified:
+		if got != "jira notifications/tools/list_changed" {
+			t.Errorf("unexpected notification: %s", got)
+		}
+	case <-time.After(time.Second):
+		t.Error("notification handler was not called")
+	}
+}
+
+// TestLateResponsesDropped verifies a response to an earlier request,
+// arriving after it timed out, is not taken for the one in flight.
+func TestLateResponsesDropped(t *testing.T) {
+	// Answer the previous request ID first, as a late response would
+	cfg := mcptest.Server(t, mcptest.Handlers{
+		"initialize": mcptest.Result(`{}`),
+		"tools/list": "(id=$((id-1)); " + mcptest.Result(`{"tools":[{"name":"stale"}]}`) + "); sleep 0.1; " +
+			mcptest.Result(`{"tools":[{"name":"search"}]}`),
+	})
+
+	pool := NewPool(1)
+	defer pool.Close()
+
+	for n := 0; n < 2; n++ {
+		tools, err := pool.GetTools("jira", cfg)
+		if err != nil || len(tools) != 1 || tools[0].Name != "search" {
+			t.Fatalf("call %d: expected the response to its own request, got %v, %v", n, tools, err)
+		}
+	}
+}
+
+func TestSpawnRemoteServer(t *testing.T) {
+	_, err := spawn(&config.ServerConfig{Type: "http", URL: "https://mcp.example.com/mcp?token=secret123"}, nil)
+	if err == nil || !strings.Contains(err.Error(), "remote servers (http transport)") {
+		t.Fatalf("error = %v, want remote server error", err)
+	}
+	if strings.Contains(err.Error(), "secret123") {
+		t.Error("error should not include the URL")
+	}
+}
@@ -0,0 +1,167 @@
+package spawner
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CommandNotFoundError is returned when a server's command is neither in
+// the PATH it runs with nor in the usual install locations.
+type CommandNotFoundError struct {
+	Command  string
+	Searched []string
+}
+
+func (e *CommandNotFoundError) Error() string {
+	return fmt.Sprintf("command '%s' not found in PATH (searched: %s)", e.Command, strings.Join(e.Searched, string(os.PathListSeparator)))
+}
+
+// resolveCommand returns the absolute path of a bare command name. It
+// searches the PATH of the child's environment, then fallbackDirs:
+// clients launched from a GUI (Dock, Start menu) often inherit a minimal
+// PATH without Homebrew, nvm or npm global directories. When the command
+// is found in a fallback directory, that directory is prepended to the
+// child's PATH too, so wrappers like npx find node next to them.
+// Commands containing a path separator are returned as is; relative ones
+// run relative to the server's working directory.
+func resolveCommand(command string, env []string) (string, []string, error) {
+	if strings.ContainsRune(command, '/') || strings.ContainsRune(command, filepath.Separator) {
+		return command, env, nil
+	}
+
+	pathList := envValue(env, "PATH")
+	searched := filepath.SplitList(pathList)
+	for _, dir := range searched {
+		if path, ok := findExecutable(dir, command); ok {
+			return path, env, nil
+		}
+	}
+
+	for _, dir := range fallbackDirs() {
+		if containsString(searched, dir) {
+			continue
+		}
+		searched = append(searched, dir)
+		if path, ok := findExecutable(dir, command); ok {
+			if pathList != "" {
+				dir += string(os.PathListSeparator) + pathList
+			}
+			return path, append(env, "PATH="+dir), nil
+		}
+	}
+
+	return "", env, &CommandNotFoundError{Command: command, Searched: searched}
+}
+
+// shellCommand returns the shell invocation that runs a server with
+// shell: true. The command and args are joined into one command line.
+func shellCommand(command string, args []string) (string, []string) {
+	line := strings.Join(append([]string{command}, args...), " ")
+	if runtime.GOOS == "windows" {
+		return "cmd", []string{"/d", "/s", "/c", line}
+	}
+	return "sh", []string{"-c", line}
+}
+
+// findExecutable looks for an executable command in dir (trying PATHEXT
+// extensions on Windows).
+func findExecutable(dir, command string) (string, bool) {
+	if dir == "" {
+		return "", false
+	}
+	path, err := exec.LookPath(filepath.Join(dir, command))
+	if err != nil {
+		return "", false
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return path, true
+}
+
+// fallbackDirs returns the directories searched after PATH: common
+// install locations of node, npm, uv, cargo and go binaries.
+func fallbackDirs() []string {
+	home, _ := os.UserHomeDir()
+	if runtime.GOOS == "windows" {
+		return []string{
+			filepath.Join(os.Getenv("APPDATA"), "npm"),
+			filepath.Join(os.Getenv("ProgramFiles"), "nodejs"),
+			filepath.Join(home, ".local", "bin"),
+			filepath.Join(home, ".cargo", "bin"),
+			filepath.Join(home, "go", "bin"),
+		}
+	}
+
+	dirs := []string{"/opt/homebrew/bin", "/usr/local/bin"}
+	if home != "" {
+		dirs = append(dirs, nvmDirs(home)...)
+		dirs = append(dirs,
+			filepath.Join(home, ".volta", "bin"),
+			filepath.Join(home, ".npm-global", "bin"),
+			filepath.Join(home, ".bun", "bin"),
+			filepath.Join(home, ".local", "bin"),
+			filepath.Join(home, ".cargo", "bin"),
+			filepath.Join(home, "go", "bin"),
+		)
+	}
+	return append(dirs, "/usr/bin", "/bin")
+}
+
+// nvmDirs returns the bin directories of node versions installed with
+// nvm, newest first.
+func nvmDirs(home string) []string {
+	dirs, _ := filepath.Glob(filepath.Join(home, ".nvm", "versions", "node", "*", "bin"))
+	sort.Slice(dirs, func(i, j int) bool {
+		return newerVersion(filepath.Base(filepath.Dir(dirs[i])), filepath.Base(filepath.Dir(dirs[j])))
+	})
+	return dirs
+}
+
+// newerVersion reports whether version a ("v20.11.0") is newer than b,
+// comparing numerically so v20 sorts before v9.
+func newerVersion(a, b string) bool {
+	partsA := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	partsB := strings.Split(strings.TrimPrefix(b, "v"), ".")
+	for i := 0; i < len(partsA) && i < len(partsB); i++ {
+		numA, errA := strconv.Atoi(partsA[i])
+		numB, errB := strconv.Atoi(partsB[i])
+		if errA != nil || errB != nil {
+			return a > b
+		}
+		if numA != numB {
+			return numA > numB
+		}
+	}
+	return len(partsA) > len(partsB)
+}
+
+// envValue returns the last value of key in env ("KEY=value" entries),
+// matching case-insensitively on Windows where the variable is "Path".
+func envValue(env []string, key string) string {
+	for i := len(env) - 1; i >= 0; i-- {
+		name, value, ok := strings.Cut(env[i], "=")
+		if !ok {
+			continue
+		}
+		if name == key || (runtime.GOOS == "windows" && strings.EqualFold(name, key)) {
+			return value
+		}
+	}
+	return ""
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package spawner
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+)
+
+// writeExecutable creates an executable script named name in dir.
+func writeExecutable(t *testing.T, dir, name string) string {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestResolveCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses shell scripts")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	pathDir := t.TempDir()
+	inPath := writeExecutable(t, pathDir, "in-path-mcp")
+	inFallback := writeExecutable(t, filepath.Join(home, ".local", "bin"), "fallback-mcp")
+
+	env := []string{"PATH=/nonexistent", "PATH=" + pathDir}
+	path, gotEnv, err := resolveCommand("in-path-mcp", env)
+	if err != nil || path != inPath {
+		t.Fatalf("resolveCommand() = %q, %v; want %q", path, err, inPath)
+	}
+	if len(gotEnv) != len(env) {
+		t.Errorf("env should be unchanged, got %v", gotEnv)
+	}
+
+	// Found outside PATH: its directory is added to the child's PATH
+	path, gotEnv, err = resolveCommand("fallback-mcp", env)
+	if err != nil || path != inFallback {
+		t.Fatalf("resolveCommand() = %q, %v; want %q", path, err, inFallback)
+	}
+	if want := filepath.Dir(inFallback) + ":" + pathDir; envValue(gotEnv, "PATH") != want {
+		t.Errorf("PATH = %q, want %q", envValue(gotEnv, "PATH"), want)
+	}
+
+	// Paths are left to exec
+	if path, _, _ := resolveCommand("./server", env); path != "./server" {
+		t.Errorf("relative path resolved to %q", path)
+	}
+
+	_, _, err = resolveCommand("missing-mcp", env)
+	var notFound *CommandNotFoundError
+	if !errors.As(err, &notFound) {
+		t.Fatalf("error = %v, want CommandNotFoundError", err)
+	}
+	if !strings.Contains(err.Error(), "command 'missing-mcp' not found in PATH (searched: "+pathDir+":") ||
+		!strings.Contains(err.Error(), "/usr/local/bin") {
+		t.Errorf("error = %q", err.Error())
+	}
+}
+
+func TestNewerVersion(t *testing.T) {
+	if !newerVersion("v20.11.0", "v9.8.0") || newerVersion("v18.2.0", "v18.10.1") {
+		t.Error("versions should compare numerically")
+	}
+}
+
+func TestSpawnCwd(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	proc, err := spawn(&config.ServerConfig{Command: "sh", Args: []string{"-c", "pwd; cat"}, Cwd: dir}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer proc.kill()
+
+	line, err := proc.stdout.ReadString('\n')
+	if err != nil || strings.TrimSpace(line) != dir {
+		t.Errorf("working directory = %q, %v; want %q", line, err, dir)
+	}
+
+	_, err = spawn(&config.ServerConfig{Command: "sh", Cwd: filepath.Join(dir, "missing")}, nil)
+	if err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Errorf("error = %v, want missing directory error", err)
+	}
+}
+
+func TestSpawnShell(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	proc, err := spawn(&config.ServerConfig{Command: "echo $((1 + 2)) | cat;", Args: []string{"cat"}, Shell: true}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer proc.kill()
+
+	line, err := proc.stdout.ReadString('\n')
+	if err != nil || strings.TrimSpace(line) != "3" {
+		t.Errorf("shell output = %q, %v; want 3", line, err)
+	}
+}
@@ -0,0 +1,106 @@
+package spawner
+
+import (
+	"errors"
+	"log"
+	"time"
+)
+
+// RetryPolicy retries requests to a child that failed for a transient
+// reason: the child exited, closed its pipes or did not answer in time.
+// The delay before each retry doubles, starting at Backoff and capped at
+// MaxBackoff.
+type RetryPolicy struct {
+	// Attempts is the total number of tries; 1 or less disables retries.
+	Attempts int
+
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+// delay returns how long to wait before the given retry (1-based).
+func (r RetryPolicy) delay(retry int) time.Duration {
+	d := r.Backoff
+	for i := 1; i < retry && (r.MaxBackoff <= 0 || d < r.MaxBackoff); i++ {
+		d *= 2
+	}
+	if r.MaxBackoff > 0 && d > r.MaxBackoff {
+		d = r.MaxBackoff
+	}
+	return d
+}
+
+// RetryFunc is notified before a request is retried, with the 1-based
+// number of the attempt that failed and its error.
+type RetryFunc func(attempt int, err error)
+
+// transientError marks a failure that may not recur on a fresh attempt.
+type transientError struct {
+	err error
+}
+
+func (e *transientError) Error() string { return e.err.Error() }
+func (e *transientError) Unwrap() error { return e.err }
+
+// isTransient reports whether err is worth retrying.
+func isTransient(err error) bool {
+	var transient *transientError
+	return errors.As(err, &transient)
+}
+
+// finalError marks an error that must not be retried even if transient,
+// such as a failed tool call that may have reached the tool.
+type finalError struct {
+	err error
+}
+
+func (e *finalError) Error() string { return e.err.Error() }
+
+// SetRetryPolicy sets how transient failures of spawns, tools/list and
+// idempotent tool calls are retried.
+func (p *Pool) SetRetryPolicy(policy RetryPolicy) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.retry = policy
+}
+
+// withRetry runs call until it succeeds, fails for a non-transient reason
+// or the retry policy's attempts are used up. After a transient failure
+// the process call used is discarded, so the next attempt spawns a fresh
+// one. onRetry (optional) is notified before each retry.
+func (p *Pool) withRetry(name, method string, onRetry RetryFunc, call func() (*Process, error)) error {
+	p.mu.Lock()
+	policy := p.retry
+	p.mu.Unlock()
+
+	for attempt := 1; ; attempt++ {
+		proc, err := call()
+		var final *finalError
+		if errors.As(err, &final) {
+			return final.err
+		}
+		if err == nil || !isTransient(err) || attempt >= policy.Attempts {
+			return err
+		}
+
+		if proc != nil {
+			p.discard(name, proc)
+		}
+		delay := policy.delay(attempt)
+		log.Printf("Warning: %s on %s failed (attempt %d of %d), retrying in %v: %v", method, name, attempt, policy.Attempts, delay, err)
+		if onRetry != nil {
+			onRetry(attempt, err)
+		}
+		time.Sleep(delay)
+	}
+}
+
+// discard stops proc if it is still the server's running process; a
+// concurrent retry may already have replaced it.
+func (p *Pool) discard(name string, proc *Process) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.processes[name] == proc {
+		p.stopLocked(name)
+	}
+}
@@ -0,0 +1,93 @@
+package spawner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"github.com/khanglvm/tool-hub-mcp/internal/mcptest"
+)
+
+// writeFlakyServer writes a fake server whose first process exits without
+// answering the request matching method; later processes answer it.
+func writeFlakyServer(t *testing.T, method string) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	calls := filepath.Join(dir, "calls")
+	script := mcptest.Script(t, mcptest.Handlers{
+		"initialize": mcptest.Result(`{}`),
+		method: "echo call >> " + calls + "; " + mcptest.CrashOnce(dir) + "; " +
+			mcptest.Result(`{"tools":[{"name":"search"}],"content":[]}`),
+	})
+	return script, calls
+}
+
+// callCount returns how many requests the flaky server received.
+func callCount(t *testing.T, calls string) int {
+	t.Helper()
+	data, err := os.ReadFile(calls)
+	if err != nil {
+		return 0
+	}
+	return strings.Count(string(data), "call")
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := RetryPolicy{Attempts: 5, Backoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond}
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond, 300 * time.Millisecond}
+	for i, expected := range want {
+		if got := policy.delay(i + 1); got != expected {
+			t.Errorf("delay(%d) = %v, want %v", i+1, got, expected)
+		}
+	}
+}
+
+func TestGetToolsRetriesTransientFailure(t *testing.T) {
+	script, calls := writeFlakyServer(t, "tools/list")
+	cfg := &config.ServerConfig{Command: "sh", Args: []string{script}}
+
+	pool := NewPool(1)
+	defer pool.Close()
+	pool.SetRetryPolicy(RetryPolicy{Attempts: 2, Backoff: time.Millisecond})
+
+	tools, err := pool.GetTools("jira", cfg)
+	if err != nil {
+		t.Fatalf("GetTools should succeed on retry: %v", err)
+	}
+	if len(tools) != 1 || callCount(t, calls) != 2 {
+		t.Errorf("expected 1 tool after 2 calls, got %v after %d", tools, callCount(t, calls))
+	}
+}
+
+func TestCallToolRetriesOnlyIdempotentCalls(t *testing.T) {
+	script, calls := writeFlakyServer(t, "tools/call")
+	cfg := &config.ServerConfig{Command: "sh", Args: []string{script}}
+
+	pool := NewPool(1)
+	defer pool.Close()
+	pool.SetRetryPolicy(RetryPolicy{Attempts: 3, Backoff: time.Millisecond})
+
+	// The tool may have run before the child crashed, so it is not resent
+	if _, err := pool.CallTool("jira", cfg, "create_issue", nil); err == nil {
+		t.Fatal("expected the non-idempotent call to fail")
+	}
+	if n := callCount(t, calls); n != 1 {
+		t.Fatalf("expected 1 call, got %d", n)
+	}
+
+	os.Remove(filepath.Join(filepath.Dir(calls), "crashed"))
+	var retried []int
+	_, err := pool.CallToolWithOptions("jira", cfg, "get_issue", nil, CallOptions{
+		Idempotent: true,
+		OnRetry:    func(attempt int, err error) { retried = append(retried, attempt) },
+	})
+	if err != nil {
+		t.Fatalf("idempotent call should succeed on retry: %v", err)
+	}
+	if len(retried) != 1 || retried[0] != 1 {
+		t.Errorf("expected one retry after attempt 1, got %v", retried)
+	}
+}
@@ -0,0 +1,63 @@
+package spawner
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+)
+
+// SandboxHelperCommand is the hidden CLI command that restricts itself
+// and then execs a sandboxed server (used for Landlock on Linux, which a
+// process can only apply to itself).
+const SandboxHelperCommand = "__sandbox"
+
+// sandboxHelper returns the tool-hub-mcp binary that runs
+// SandboxHelperCommand; tests replace it.
+var sandboxHelper = os.Executable
+
+// helperArgs returns the arguments that run command through the sandbox
+// helper with the given writable paths.
+func helperArgs(exe string, sb *config.SandboxSettings, command string, args []string) []string {
+	helper := []string{exe, SandboxHelperCommand}
+	for _, path := range sb.Writable {
+		helper = append(helper, "--writable", path)
+	}
+	helper = append(helper, "--", command)
+	return append(helper, args...)
+}
+
+// seatbeltProfile returns the sandbox-exec profile for a macOS server.
+// Later rules take precedence, so writable paths are allowed after the
+// blanket write denial.
+func seatbeltProfile(sb *config.SandboxSettings) string {
+	rules := []string{"(version 1)", "(allow default)"}
+	if sb.NoNetwork() {
+		rules = append(rules, "(deny network*)", `(allow network* (local unix))`)
+	}
+	if sb.ReadOnly() {
+		rules = append(rules, "(deny file-write*)", `(allow file-write* (literal "/dev/null"))`)
+		for _, path := range sb.Writable {
+			rules = append(rules, fmt.Sprintf("(allow file-write* (subpath %q))", path))
+		}
+	}
+	return strings.Join(rules, "\n")
+}
+
+// containerSandboxArgs returns the runtime flags that sandbox a
+// container: no network, and a read-only root with a tmpfs mounted on
+// each writable path.
+func containerSandboxArgs(sb *config.SandboxSettings) []string {
+	var args []string
+	if sb.NoNetwork() {
+		args = append(args, "--network", "none")
+	}
+	if sb.ReadOnly() {
+		args = append(args, "--read-only")
+		for _, path := range sb.Writable {
+			args = append(args, "--tmpfs", path)
+		}
+	}
+	return args
+}
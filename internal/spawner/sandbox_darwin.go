@@ -0,0 +1,27 @@
+//go:build darwin
+
+package spawner
+
+import (
+	"fmt"
+	"os/exec"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+)
+
+// sandboxCommand runs cmd under sandbox-exec with a profile that denies
+// network access and/or file writes.
+func sandboxCommand(cmd *exec.Cmd, sb *config.SandboxSettings) error {
+	path, err := exec.LookPath("sandbox-exec")
+	if err != nil {
+		return fmt.Errorf("sandbox-exec not found: %w", err)
+	}
+	cmd.Args = append([]string{path, "-p", seatbeltProfile(sb), cmd.Path}, cmd.Args[1:]...)
+	cmd.Path = path
+	return nil
+}
+
+// ExecSandboxed is only used on Linux; macOS sandboxes with sandbox-exec.
+func ExecSandboxed(writable []string, argv []string) error {
+	return fmt.Errorf("the sandbox helper is only used on Linux")
+}
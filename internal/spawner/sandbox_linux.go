@@ -0,0 +1,139 @@
+//go:build linux
+
+package spawner
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"syscall"
+	"unsafe"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+	"golang.org/x/sys/unix"
+)
+
+// sandboxCommand restricts cmd before it starts. Without network the
+// process gets new user and network namespaces (like unshare -U -n),
+// which hold only a loopback device that is down. A read-only file
+// system is enforced with Landlock by the sandbox helper, which then
+// execs the server.
+func sandboxCommand(cmd *exec.Cmd, sb *config.SandboxSettings) error {
+	if sb.NoNetwork() {
+		uid, gid := os.Getuid(), os.Getgid()
+		cmd.SysProcAttr = &syscall.SysProcAttr{
+			Cloneflags:  syscall.CLONE_NEWUSER | syscall.CLONE_NEWNET,
+			UidMappings: []syscall.SysProcIDMap{{ContainerID: uid, HostID: uid, Size: 1}},
+			GidMappings: []syscall.SysProcIDMap{{ContainerID: gid, HostID: gid, Size: 1}},
+		}
+	}
+	if sb.ReadOnly() {
+		exe, err := sandboxHelper()
+		if err != nil {
+			return fmt.Errorf("failed to locate the sandbox helper: %w", err)
+		}
+		cmd.Args = helperArgs(exe, sb, cmd.Path, cmd.Args[1:])
+		cmd.Path = exe
+	}
+	return nil
+}
+
+// landlockWriteAccess are the Landlock file system rights that modify
+// files or directories, by the ABI version that introduced them.
+var landlockWriteAccess = []uint64{
+	1: unix.LANDLOCK_ACCESS_FS_WRITE_FILE | unix.LANDLOCK_ACCESS_FS_REMOVE_DIR |
+		unix.LANDLOCK_ACCESS_FS_REMOVE_FILE | unix.LANDLOCK_ACCESS_FS_MAKE_CHAR |
+		unix.LANDLOCK_ACCESS_FS_MAKE_DIR | unix.LANDLOCK_ACCESS_FS_MAKE_REG |
+		unix.LANDLOCK_ACCESS_FS_MAKE_SOCK | unix.LANDLOCK_ACCESS_FS_MAKE_FIFO |
+		unix.LANDLOCK_ACCESS_FS_MAKE_BLOCK | unix.LANDLOCK_ACCESS_FS_MAKE_SYM,
+	2: unix.LANDLOCK_ACCESS_FS_REFER,
+	3: unix.LANDLOCK_ACCESS_FS_TRUNCATE,
+}
+
+// landlockFileAccess are the rights that apply to a file, not a directory.
+const landlockFileAccess = unix.LANDLOCK_ACCESS_FS_WRITE_FILE | unix.LANDLOCK_ACCESS_FS_TRUNCATE
+
+// ExecSandboxed makes the file system read-only for the current process,
+// except for the writable paths and /dev/null, then replaces it with
+// argv. Writable paths that do not exist are skipped. It only returns on
+// error.
+func ExecSandboxed(writable []string, argv []string) error {
+	if len(argv) == 0 {
+		return fmt.Errorf("no command to run")
+	}
+	path, err := exec.LookPath(argv[0])
+	if err != nil {
+		return err
+	}
+
+	// Landlock and no_new_privs apply to the calling thread, which must
+	// be the one that execs
+	runtime.LockOSThread()
+	if err := restrictWrites(append([]string{"/dev/null"}, writable...)); err != nil {
+		return err
+	}
+	return syscall.Exec(path, argv, os.Environ())
+}
+
+// restrictWrites denies writes outside the given paths with Landlock.
+func restrictWrites(writable []string) error {
+	abi, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET, 0, 0, unix.LANDLOCK_CREATE_RULESET_VERSION)
+	if errno != 0 {
+		return fmt.Errorf("fsReadOnly needs Landlock (Linux 5.13+ with Landlock enabled): %w", errno)
+	}
+	var handled uint64
+	for version := 1; version < len(landlockWriteAccess) && uintptr(version) <= abi; version++ {
+		handled |= landlockWriteAccess[version]
+	}
+
+	attr := unix.LandlockRulesetAttr{Access_fs: handled}
+	ruleset, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET, uintptr(unsafe.Pointer(&attr)), unsafe.Sizeof(attr), 0)
+	if errno != 0 {
+		return fmt.Errorf("failed to create Landlock ruleset: %w", errno)
+	}
+	defer unix.Close(int(ruleset))
+
+	for _, path := range writable {
+		if err := allowWrites(int(ruleset), path, handled); err != nil {
+			return err
+		}
+	}
+
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return fmt.Errorf("failed to set no_new_privs: %w", err)
+	}
+	if _, _, errno := unix.Syscall(unix.SYS_LANDLOCK_RESTRICT_SELF, ruleset, 0, 0); errno != 0 {
+		return fmt.Errorf("failed to apply Landlock ruleset: %w", errno)
+	}
+	return nil
+}
+
+// allowWrites adds a Landlock rule that keeps path writable.
+func allowWrites(ruleset int, path string, handled uint64) error {
+	fd, err := unix.Open(path, unix.O_PATH|unix.O_CLOEXEC, 0)
+	if errors.Is(err, unix.ENOENT) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open writable path %s: %w", path, err)
+	}
+	defer unix.Close(fd)
+
+	var stat unix.Stat_t
+	if err := unix.Fstat(fd, &stat); err != nil {
+		return fmt.Errorf("failed to stat writable path %s: %w", path, err)
+	}
+	allowed := handled
+	if stat.Mode&unix.S_IFMT != unix.S_IFDIR {
+		allowed &= landlockFileAccess
+	}
+
+	rule := unix.LandlockPathBeneathAttr{Allowed_access: allowed, Parent_fd: int32(fd)}
+	if _, _, errno := unix.Syscall6(unix.SYS_LANDLOCK_ADD_RULE, uintptr(ruleset), unix.LANDLOCK_RULE_PATH_BENEATH,
+		uintptr(unsafe.Pointer(&rule)), 0, 0, 0); errno != 0 {
+		return fmt.Errorf("failed to allow writes to %s: %w", path, errno)
+	}
+	return nil
+}
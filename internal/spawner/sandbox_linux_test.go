@@ -0,0 +1,30 @@
+package spawner
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+)
+
+// TestSandboxNoNetwork checks a server without network runs in its own
+// network namespace, which has only the loopback device.
+func TestSandboxNoNetwork(t *testing.T) {
+	network := false
+	cmd := exec.Command("cat", "/proc/self/net/dev")
+	if err := sandboxCommand(cmd, &config.SandboxSettings{Network: &network}); err != nil {
+		t.Fatal(err)
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		t.Skipf("user namespaces unavailable: %v", err)
+	}
+
+	for _, line := range strings.Split(string(out), "\n") {
+		device, _, found := strings.Cut(line, ":")
+		if found && strings.TrimSpace(device) != "lo" {
+			t.Errorf("unexpected network device in sandbox: %s", line)
+		}
+	}
+}
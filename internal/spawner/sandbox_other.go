@@ -0,0 +1,22 @@
+//go:build !linux && !darwin
+
+package spawner
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+)
+
+// sandboxCommand fails: this platform has no sandbox facility, and
+// running an untrusted server unrestricted is not an option.
+func sandboxCommand(cmd *exec.Cmd, sb *config.SandboxSettings) error {
+	return fmt.Errorf("sandboxing is not supported on %s; run the server in a container instead", runtime.GOOS)
+}
+
+// ExecSandboxed is only used on Linux.
+func ExecSandboxed(writable []string, argv []string) error {
+	return fmt.Errorf("sandboxing is not supported on %s", runtime.GOOS)
+}
@@ -0,0 +1,54 @@
+package spawner
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/config"
+)
+
+func TestHelperArgs(t *testing.T) {
+	sb := &config.SandboxSettings{FSReadOnly: true, Writable: []string{"/tmp/cache", "/srv/out"}}
+	args := helperArgs("/usr/local/bin/tool-hub-mcp", sb, "/usr/bin/node", []string{"server.js", "--"})
+	want := []string{
+		"/usr/local/bin/tool-hub-mcp", SandboxHelperCommand,
+		"--writable", "/tmp/cache", "--writable", "/srv/out",
+		"--", "/usr/bin/node", "server.js", "--",
+	}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("helperArgs() = %v, want %v", args, want)
+	}
+}
+
+func TestSeatbeltProfile(t *testing.T) {
+	network := false
+	profile := seatbeltProfile(&config.SandboxSettings{Network: &network, FSReadOnly: true, Writable: []string{"/tmp/out"}})
+	for _, rule := range []string{"(allow default)", "(deny network*)", "(deny file-write*)", `(allow file-write* (subpath "/tmp/out"))`} {
+		if !strings.Contains(profile, rule) {
+			t.Errorf("profile missing %s:\n%s", rule, profile)
+		}
+	}
+	if strings.Index(profile, "(deny file-write*)") > strings.Index(profile, `(subpath "/tmp/out")`) {
+		t.Error("writable paths must be allowed after the write denial")
+	}
+
+	profile = seatbeltProfile(&config.SandboxSettings{FSReadOnly: true})
+	if strings.Contains(profile, "network") {
+		t.Errorf("network should not be restricted:\n%s", profile)
+	}
+}
+
+func TestContainerSandboxArgs(t *testing.T) {
+	network := false
+	cfg := &config.ServerConfig{
+		Runtime:   config.RuntimeDocker,
+		Container: &config.ContainerSettings{Image: "mcp/fetch", Network: "bridge"},
+		Sandbox:   &config.SandboxSettings{Network: &network, FSReadOnly: true, Writable: []string{"/tmp"}},
+	}
+	_, args := containerCommand(cfg, nil, "tool-hub-mcp-test")
+	want := []string{"run", "--rm", "-i", "--name", "tool-hub-mcp-test", "--network", "none", "--read-only", "--tmpfs", "/tmp", "mcp/fetch"}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("containerCommand() = %v, want %v", args, want)
+	}
+}
@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"log"
+	"strings"
+	"time"
+)
+
+// auditTimeFormat is a fixed-width UTC timestamp, so string comparison
+// in SQLite orders entries chronologically.
+const auditTimeFormat = "2006-01-02T15:04:05.000Z"
+
+// RecordAudit appends a hub_execute call to the audit log.
+func (s *SQLiteStorage) RecordAudit(entry AuditEntry) error {
+	if !s.enabled || s.db == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	query := `
+		INSERT INTO audit_log (timestamp, server, tool, args_hash, args, duration_ms, success, error, execution_id, search_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	success := 0
+	if entry.Success {
+		success = 1
+	}
+
+	_, err := s.db.Exec(query,
+		entry.Timestamp.UTC().Format(auditTimeFormat),
+		entry.Server,
+		entry.Tool,
+		entry.ArgsHash,
+		entry.Args,
+		entry.DurationMs,
+		success,
+		entry.Error,
+		entry.ExecutionID,
+		entry.SearchID,
+	)
+
+	if err != nil {
+		log.Printf("Warning: failed to record audit entry: %v", err)
+	}
+
+	return nil
+}
+
+// GetAuditEntries returns audit entries matching the filter, newest first.
+func (s *SQLiteStorage) GetAuditEntries(filter AuditFilter) ([]AuditEntry, error) {
+	if !s.enabled || s.db == nil {
+		return []AuditEntry{}, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var conditions []string
+	var args []interface{}
+	if !filter.Since.IsZero() {
+		conditions = append(conditions, "timestamp >= ?")
+		args = append(args, filter.Since.UTC().Format(auditTimeFormat))
+	}
+	if !filter.Until.IsZero() {
+		conditions = append(conditions, "timestamp < ?")
+		args = append(args, filter.Until.UTC().Format(auditTimeFormat))
+	}
+	if filter.Server != "" {
+		conditions = append(conditions, "server = ?")
+		args = append(args, filter.Server)
+	}
+	if filter.Tool != "" {
+		conditions = append(conditions, "tool = ?")
+		args = append(args, filter.Tool)
+	}
+	if filter.FailedOnly {
+		conditions = append(conditions, "success = 0")
+	}
+	if filter.SucceededOnly {
+		conditions = append(conditions, "success = 1")
+	}
+	if filter.WithArgs {
+		conditions = append(conditions, "COALESCE(args, '') != ''")
+	}
+	if filter.SearchID != "" {
+		conditions = append(conditions, "search_id = ?")
+		args = append(args, filter.SearchID)
+	}
+
+	query := `
+		SELECT id, timestamp, server, tool, COALESCE(args_hash, ''), COALESCE(args, ''),
+		       duration_ms, success, COALESCE(error, ''), COALESCE(execution_id, ''), COALESCE(search_id, '')
+		FROM audit_log
+	`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY timestamp DESC, id DESC"
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		log.Printf("Warning: failed to query audit log: %v", err)
+		return []AuditEntry{}, nil
+	}
+	defer rows.Close()
+
+	entries := []AuditEntry{}
+	for rows.Next() {
+		var e AuditEntry
+		var timestamp string
+		var success int
+		if err := rows.Scan(&e.ID, &timestamp, &e.Server, &e.Tool, &e.ArgsHash, &e.Args, &e.DurationMs, &success, &e.Error, &e.ExecutionID, &e.SearchID); err != nil {
+			log.Printf("Warning: failed to scan audit row: %v", err)
+			continue
+		}
+		e.Timestamp, _ = time.Parse(auditTimeFormat, timestamp)
+		e.Success = success == 1
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
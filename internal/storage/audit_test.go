@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestAuditLogFilters verifies audit entries round-trip and filter correctly.
+func TestAuditLogFilters(t *testing.T) {
+	storage := &SQLiteStorage{
+		dbPath:  filepath.Join(t.TempDir(), "test.db"),
+		enabled: true,
+	}
+	if err := storage.Init(); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	defer storage.Close()
+
+	now := time.Now()
+	entries := []AuditEntry{
+		{Timestamp: now.Add(-48 * time.Hour), Server: "jira", Tool: "search", ArgsHash: "h1", DurationMs: 10, Success: true},
+		{Timestamp: now.Add(-time.Hour), Server: "jira", Tool: "create_issue", ArgsHash: "h2", DurationMs: 250, Success: false, Error: "boom"},
+		{Timestamp: now, Server: "github", Tool: "search", ArgsHash: "h3", Args: `{"q":"x"}`, DurationMs: 5, Success: true},
+	}
+	for _, e := range entries {
+		if err := storage.RecordAudit(e); err != nil {
+			t.Fatalf("RecordAudit failed: %v", err)
+		}
+	}
+
+	all, err := storage.GetAuditEntries(AuditFilter{})
+	if err != nil {
+		t.Fatalf("GetAuditEntries failed: %v", err)
+	}
+	if len(all) != 3 || all[0].Server != "github" {
+		t.Fatalf("expected 3 entries newest first, got %+v", all)
+	}
+	if all[0].Args != `{"q":"x"}` || all[0].ID == 0 {
+		t.Errorf("fields not round-tripped: %+v", all[0])
+	}
+
+	failed, _ := storage.GetAuditEntries(AuditFilter{FailedOnly: true})
+	if len(failed) != 1 || failed[0].Error != "boom" || failed[0].DurationMs != 250 {
+		t.Errorf("unexpected failed entries: %+v", failed)
+	}
+
+	recent, _ := storage.GetAuditEntries(AuditFilter{Since: now.Add(-2 * time.Hour), Server: "jira"})
+	if len(recent) != 1 || recent[0].Tool != "create_issue" {
+		t.Errorf("unexpected since/server filter result: %+v", recent)
+	}
+
+	examples, _ := storage.GetAuditEntries(AuditFilter{Tool: "search", SucceededOnly: true, WithArgs: true})
+	if len(examples) != 1 || examples[0].Server != "github" {
+		t.Errorf("unexpected succeeded/with-args result: %+v", examples)
+	}
+
+	limited, _ := storage.GetAuditEntries(AuditFilter{Tool: "search", Limit: 1})
+	if len(limited) != 1 || limited[0].Server != "github" {
+		t.Errorf("unexpected tool/limit result: %+v", limited)
+	}
+}
@@ -4,8 +4,9 @@ Package storage implements a persistent storage layer for learning and history.
 This package provides SQLite-based storage for tool usage tracking, search history,
 and embedding caching with graceful degradation if the database is unavailable.
 
-The database is stored at ~/.tool-hub-mcp/history.db and uses modernc.org/sqlite
-(a pure Go, CGo-free implementation).
+The database is stored at ~/.local/share/tool-hub-mcp/history.db and uses modernc.org/sqlite
+(a pure Go, CGo-free implementation). It runs in WAL mode with a busy timeout so
+serve and concurrent CLI commands can share it.
 */
 package storage
 
@@ -20,6 +21,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/khanglvm/tool-hub-mcp/internal/paths"
 	_ "modernc.org/sqlite"
 )
 
@@ -50,6 +52,10 @@ type Storage interface {
 	Close() error
 }
 
+// busyTimeout is how long a connection waits for another process's write
+// lock (serve and CLI commands share history.db) before "database is locked".
+const busyTimeout = 5 * time.Second
+
 // SQLiteStorage implements the Storage interface using SQLite.
 type SQLiteStorage struct {
 	db       *sql.DB
@@ -61,19 +67,16 @@ type SQLiteStorage struct {
 
 // NewStorage creates a new SQLite storage instance.
 //
-// The database is created at ~/.tool-hub-mcp/history.db.
+// The database is created at paths.HistoryDB() (~/.local/share/tool-hub-mcp/history.db).
 // If the directory doesn't exist, it will be created.
 // If the database cannot be opened, the storage will be disabled but operations will not fail.
 func NewStorage() *SQLiteStorage {
-	home, err := os.UserHomeDir()
+	dbPath, err := paths.HistoryDB()
 	if err != nil {
-		log.Printf("Warning: failed to get home directory: %v", err)
+		log.Printf("Warning: failed to resolve database path: %v", err)
 		return &SQLiteStorage{enabled: false}
 	}
 
-	dbDir := filepath.Join(home, ".tool-hub-mcp")
-	dbPath := filepath.Join(dbDir, "history.db")
-
 	return &SQLiteStorage{
 		dbPath:  dbPath,
 		enabled: true,
@@ -100,7 +103,7 @@ func (s *SQLiteStorage) Init() error {
 		}
 
 		// Open database
-		db, err := sql.Open("sqlite", s.dbPath)
+		db, err := sql.Open("sqlite", dataSourceName(s.dbPath))
 		if err != nil {
 			initErr = fmt.Errorf("failed to open database: %w", err)
 			s.enabled = false
@@ -129,6 +132,15 @@ func (s *SQLiteStorage) Init() error {
 	return initErr
 }
 
+// dataSourceName configures every connection for multi-process use: WAL
+// lets readers proceed while another process writes, busy_timeout waits
+// for the write lock instead of failing, and IMMEDIATE transactions take
+// that lock up front so two writers cannot deadlock upgrading read locks.
+func dataSourceName(dbPath string) string {
+	return fmt.Sprintf("%s?_pragma=busy_timeout(%d)&_pragma=journal_mode(WAL)&_pragma=synchronous(NORMAL)&_txlock=immediate",
+		dbPath, busyTimeout.Milliseconds())
+}
+
 // Close closes the database connection.
 func (s *SQLiteStorage) Close() error {
 	if !s.enabled || s.db == nil {
@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+)
+
+// RecordArgumentMistake counts an invalid-params error a child answered a
+// call to one of its tools with. Repeats of the same message add up.
+func (s *SQLiteStorage) RecordArgumentMistake(server, tool, message string) error {
+	if !s.enabled || s.db == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.db.Exec(`
+		INSERT INTO argument_mistakes (server, tool, error, count, last_seen)
+		VALUES (?, ?, ?, 1, ?)
+		ON CONFLICT(server, tool, error) DO UPDATE SET
+			count = count + 1,
+			last_seen = excluded.last_seen
+	`, server, tool, message, time.Now().UTC().Format(auditTimeFormat))
+	if err != nil {
+		return fmt.Errorf("failed to record argument mistake: %w", err)
+	}
+	return nil
+}
+
+// GetArgumentMistakes returns a tool's most frequent argument mistakes,
+// most recent first among equally frequent ones (0 limit means all).
+func (s *SQLiteStorage) GetArgumentMistakes(server, tool string, limit int) ([]ArgumentMistake, error) {
+	mistakes := []ArgumentMistake{}
+	if !s.enabled || s.db == nil {
+		return mistakes, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	query := `
+		SELECT server, tool, error, count, last_seen
+		FROM argument_mistakes
+		WHERE server = ? AND tool = ?
+		ORDER BY count DESC, last_seen DESC
+	`
+	args := []interface{}{server, tool}
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query argument mistakes: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var mistake ArgumentMistake
+		var lastSeen string
+		if err := rows.Scan(&mistake.Server, &mistake.Tool, &mistake.Error, &mistake.Count, &lastSeen); err != nil {
+			return nil, fmt.Errorf("failed to read argument mistake: %w", err)
+		}
+		mistake.LastSeen, _ = time.Parse(auditTimeFormat, lastSeen)
+		mistakes = append(mistakes, mistake)
+	}
+	return mistakes, rows.Err()
+}
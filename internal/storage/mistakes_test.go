@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// TestArgumentMistakes verifies repeated mistakes are counted and ranked.
+func TestArgumentMistakes(t *testing.T) {
+	storage := &SQLiteStorage{
+		dbPath:  filepath.Join(t.TempDir(), "test.db"),
+		enabled: true,
+	}
+	if err := storage.Init(); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	defer storage.Close()
+
+	for _, message := range []string{"missing jql", "bad limit", "missing jql"} {
+		if err := storage.RecordArgumentMistake("jira", "search", message); err != nil {
+			t.Fatalf("RecordArgumentMistake failed: %v", err)
+		}
+	}
+	storage.RecordArgumentMistake("jira", "create", "missing project")
+
+	mistakes, err := storage.GetArgumentMistakes("jira", "search", 0)
+	if err != nil {
+		t.Fatalf("GetArgumentMistakes failed: %v", err)
+	}
+	if len(mistakes) != 2 || mistakes[0].Error != "missing jql" || mistakes[0].Count != 2 || mistakes[0].LastSeen.IsZero() {
+		t.Fatalf("unexpected mistakes: %+v", mistakes)
+	}
+	if limited, _ := storage.GetArgumentMistakes("jira", "search", 1); len(limited) != 1 {
+		t.Errorf("expected limit to apply, got %+v", limited)
+	}
+
+	if err := storage.RenameServer("jira", "tickets"); err != nil {
+		t.Fatalf("RenameServer failed: %v", err)
+	}
+	if renamed, _ := storage.GetArgumentMistakes("tickets", "search", 0); len(renamed) != 2 {
+		t.Errorf("expected mistakes to follow the rename, got %+v", renamed)
+	}
+}
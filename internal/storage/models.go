@@ -27,6 +27,31 @@ type UsageEvent struct {
 
 	// WasRecommended indicates if the tool was recommended by the learning system.
 	WasRecommended bool `json:"was_recommended"`
+
+	// Success indicates whether the tool execution completed without error.
+	Success bool `json:"success"`
+
+	// Server is the config name of the server that ran the tool (optional).
+	Server string `json:"server,omitempty"`
+
+	// SearchID links the event to the hub_search that led to it (optional).
+	SearchID string `json:"search_id,omitempty"`
+}
+
+// ToolAffinity summarizes how a tool fared after searches for one query.
+type ToolAffinity struct {
+	// Server and ToolName identify the tool.
+	Server   string `json:"server"`
+	ToolName string `json:"tool_name"`
+
+	// Selections is how often the tool was executed after the query.
+	Selections int `json:"selections"`
+
+	// Successes is how many of those executions succeeded.
+	Successes int `json:"successes"`
+
+	// LastUsed is the most recent such execution.
+	LastUsed time.Time `json:"last_used"`
 }
 
 // SearchRecord represents a search query for analytics.
@@ -42,6 +67,24 @@ type SearchRecord struct {
 
 	// ResultsCount is the number of results returned.
 	ResultsCount int `json:"results_count"`
+
+	// Results are the ids ("server/tool") of the results shown, in rank order.
+	Results []string `json:"results,omitempty"`
+
+	// Query is the query text, empty unless query recording is enabled.
+	Query string `json:"query,omitempty"`
+}
+
+// SearchSnapshot is the last result set returned for a normalized query.
+type SearchSnapshot struct {
+	// QueryHash is the SHA256 hash of the normalized query.
+	QueryHash string `json:"query_hash"`
+
+	// Tools are the result identifiers ("server/tool") in ranked order.
+	Tools []string `json:"tools"`
+
+	// UpdatedAt is when the snapshot was last written.
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 // ToolEmbedding represents a cached embedding vector for a tool.
@@ -58,3 +101,98 @@ type ToolEmbedding struct {
 	// CreatedAt is when the embedding was generated.
 	CreatedAt time.Time `json:"created_at"`
 }
+
+// AuditEntry records a single hub_execute call.
+type AuditEntry struct {
+	// ID is the row identifier, assigned on insert.
+	ID int64 `json:"id"`
+
+	// Timestamp is when the call started.
+	Timestamp time.Time `json:"timestamp"`
+
+	// Server is the config name of the server that was called.
+	Server string `json:"server"`
+
+	// Tool is the name of the executed tool.
+	Tool string `json:"tool"`
+
+	// ArgsHash is the SHA256 hash of the call's JSON arguments.
+	ArgsHash string `json:"args_hash"`
+
+	// Args holds the redacted JSON arguments, empty unless argument recording is enabled.
+	Args string `json:"args,omitempty"`
+
+	// DurationMs is how long the call took in milliseconds.
+	DurationMs int64 `json:"duration_ms"`
+
+	// Success indicates whether the call completed without error.
+	Success bool `json:"success"`
+
+	// Error is the failure message for unsuccessful calls.
+	Error string `json:"error,omitempty"`
+
+	// ExecutionID identifies the call; SearchID is the search it followed.
+	ExecutionID string `json:"execution_id,omitempty"`
+	SearchID    string `json:"search_id,omitempty"`
+}
+
+// AuditFilter selects audit entries. Zero values match everything.
+type AuditFilter struct {
+	// Since and Until bound the entry timestamps.
+	Since time.Time
+	Until time.Time
+
+	// Server and Tool match exactly.
+	Server string
+	Tool   string
+
+	// FailedOnly restricts results to unsuccessful calls.
+	FailedOnly bool
+
+	// SucceededOnly restricts results to successful calls.
+	SucceededOnly bool
+
+	// WithArgs restricts results to calls whose arguments were recorded.
+	WithArgs bool
+
+	// SearchID matches calls that followed that search.
+	SearchID string
+
+	// Limit caps the number of entries returned (newest first).
+	Limit int
+}
+
+// ArgumentMistake is an invalid-params error a child answered calls to
+// one of its tools with.
+type ArgumentMistake struct {
+	// Server and Tool identify the called tool.
+	Server string `json:"server"`
+	Tool   string `json:"tool"`
+
+	// Error is the child's error message.
+	Error string `json:"error"`
+
+	// Count is how many calls failed with this error.
+	Count int `json:"count"`
+
+	// LastSeen is when the error last occurred.
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// ServerStatus is the outcome of the latest tool discovery for a server.
+type ServerStatus struct {
+	// Server is the server's config key.
+	Server string `json:"server"`
+
+	// ToolCount is the number of tools found by the last successful discovery.
+	ToolCount int `json:"tool_count"`
+
+	// LastSuccess is when tools were last listed successfully (zero if never).
+	LastSuccess time.Time `json:"last_success,omitempty"`
+
+	// LastError is the failure of the latest discovery, empty if it succeeded.
+	LastError string `json:"last_error,omitempty"`
+
+	// LastErrorAt is when LastError occurred.
+	LastErrorAt time.Time `json:"last_error_at,omitempty"`
+}
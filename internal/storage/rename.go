@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// RenameServer moves stored history from one server name to another in a
+// single transaction: audit log entries, usage events, argument mistakes,
+// discovery status and the "server/tool" IDs kept in search snapshots.
+func (s *SQLiteStorage) RenameServer(oldName, newName string) error {
+	if !s.enabled || s.db == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin rename: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`UPDATE audit_log SET server = ? WHERE server = ?`, newName, oldName); err != nil {
+		return fmt.Errorf("failed to rename audit entries: %w", err)
+	}
+
+	if _, err := tx.Exec(`UPDATE tool_usage SET server = ? WHERE server = ?`, newName, oldName); err != nil {
+		return fmt.Errorf("failed to rename usage events: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM argument_mistakes WHERE server = ?`, newName); err != nil {
+		return fmt.Errorf("failed to rename argument mistakes: %w", err)
+	}
+	if _, err := tx.Exec(`UPDATE argument_mistakes SET server = ? WHERE server = ?`, newName, oldName); err != nil {
+		return fmt.Errorf("failed to rename argument mistakes: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM server_status WHERE server = ?`, newName); err != nil {
+		return fmt.Errorf("failed to rename server status: %w", err)
+	}
+	if _, err := tx.Exec(`UPDATE server_status SET server = ? WHERE server = ?`, newName, oldName); err != nil {
+		return fmt.Errorf("failed to rename server status: %w", err)
+	}
+
+	rows, err := tx.Query(`SELECT query_hash, tools FROM search_snapshots WHERE tools LIKE ?`, "%\""+oldName+"/%")
+	if err != nil {
+		return fmt.Errorf("failed to read search snapshots: %w", err)
+	}
+	updated := make(map[string]string)
+	for rows.Next() {
+		var queryHash, toolsJSON string
+		if err := rows.Scan(&queryHash, &toolsJSON); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to read search snapshot: %w", err)
+		}
+		var tools []string
+		if err := json.Unmarshal([]byte(toolsJSON), &tools); err != nil {
+			continue
+		}
+		for i, id := range tools {
+			if strings.HasPrefix(id, oldName+"/") {
+				tools[i] = newName + strings.TrimPrefix(id, oldName)
+			}
+		}
+		data, err := json.Marshal(tools)
+		if err != nil {
+			continue
+		}
+		updated[queryHash] = string(data)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read search snapshots: %w", err)
+	}
+
+	for queryHash, toolsJSON := range updated {
+		if _, err := tx.Exec(`UPDATE search_snapshots SET tools = ? WHERE query_hash = ?`, toolsJSON, queryHash); err != nil {
+			return fmt.Errorf("failed to rename search snapshot: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit rename: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestRenameServer verifies audit entries and snapshot IDs move to the new name.
+func TestRenameServer(t *testing.T) {
+	storage := &SQLiteStorage{
+		dbPath:  filepath.Join(t.TempDir(), "test.db"),
+		enabled: true,
+	}
+	if err := storage.Init(); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	defer storage.Close()
+
+	if err := storage.RecordAudit(AuditEntry{Timestamp: time.Now(), Server: "jira", Tool: "search", ArgsHash: "h1", Success: true}); err != nil {
+		t.Fatalf("RecordAudit failed: %v", err)
+	}
+	if err := storage.SaveSearchSnapshot("q1", []string{"jira/search", "jiraLegacy/search", "github/search"}); err != nil {
+		t.Fatalf("SaveSearchSnapshot failed: %v", err)
+	}
+
+	if err := storage.RenameServer("jira", "jiraCloud"); err != nil {
+		t.Fatalf("RenameServer failed: %v", err)
+	}
+
+	entries, err := storage.GetAuditEntries(AuditFilter{Server: "jiraCloud"})
+	if err != nil {
+		t.Fatalf("GetAuditEntries failed: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected 1 audit entry under new name, got %d", len(entries))
+	}
+
+	snap, err := storage.GetSearchSnapshot("q1")
+	if err != nil || snap == nil {
+		t.Fatalf("GetSearchSnapshot failed: %v", err)
+	}
+	want := []string{"jiraCloud/search", "jiraLegacy/search", "github/search"}
+	for i, id := range want {
+		if snap.Tools[i] != id {
+			t.Errorf("snapshot tools = %v, want %v", snap.Tools, want)
+			break
+		}
+	}
+}
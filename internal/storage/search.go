@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"encoding/json"
 	"log"
 	"time"
 )
@@ -15,15 +16,18 @@ func (s *SQLiteStorage) RecordSearch(search SearchRecord) error {
 	defer s.mu.Unlock()
 
 	query := `
-		INSERT INTO search_history (search_id, query_hash, timestamp, results_count)
-		VALUES (?, ?, ?, ?)
+		INSERT INTO search_history (search_id, query_hash, timestamp, results_count, results, query)
+		VALUES (?, ?, ?, ?, ?, ?)
 	`
 
+	results, _ := json.Marshal(search.Results)
 	_, err := s.db.Exec(query,
 		search.SearchID,
 		search.QueryHash,
 		search.Timestamp.Format(time.RFC3339),
 		search.ResultsCount,
+		string(results),
+		search.Query,
 	)
 
 	if err != nil {
@@ -33,6 +37,79 @@ func (s *SQLiteStorage) RecordSearch(search SearchRecord) error {
 	return nil
 }
 
+// SaveSearchSnapshot replaces the stored result set for a query hash.
+func (s *SQLiteStorage) SaveSearchSnapshot(queryHash string, tools []string) error {
+	if !s.enabled || s.db == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if tools == nil {
+		tools = []string{}
+	}
+	toolsJSON, err := json.Marshal(tools)
+	if err != nil {
+		log.Printf("Warning: failed to marshal search snapshot: %v", err)
+		return nil
+	}
+
+	query := `
+		INSERT OR REPLACE INTO search_snapshots (query_hash, tools, updated_at)
+		VALUES (?, ?, ?)
+	`
+
+	if _, err := s.db.Exec(query, queryHash, string(toolsJSON), time.Now().Format(time.RFC3339)); err != nil {
+		log.Printf("Warning: failed to save search snapshot: %v", err)
+	}
+
+	return nil
+}
+
+// GetSearchSnapshot returns the stored result set for a query hash,
+// or nil if the query has not been seen before.
+func (s *SQLiteStorage) GetSearchSnapshot(queryHash string) (*SearchSnapshot, error) {
+	if !s.enabled || s.db == nil {
+		return nil, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	query := `
+		SELECT tools, updated_at
+		FROM search_snapshots
+		WHERE query_hash = ?
+	`
+
+	rows, err := s.db.Query(query, queryHash)
+	if err != nil {
+		log.Printf("Warning: failed to query search snapshot: %v", err)
+		return nil, nil
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, nil
+	}
+
+	var toolsJSON, updatedAt string
+	if err := rows.Scan(&toolsJSON, &updatedAt); err != nil {
+		log.Printf("Warning: failed to scan search snapshot: %v", err)
+		return nil, nil
+	}
+
+	snapshot := &SearchSnapshot{QueryHash: queryHash}
+	if err := json.Unmarshal([]byte(toolsJSON), &snapshot.Tools); err != nil {
+		log.Printf("Warning: failed to parse search snapshot: %v", err)
+		return nil, nil
+	}
+	snapshot.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
+
+	return snapshot, nil
+}
+
 // Cleanup removes old records based on retention policy.
 func (s *SQLiteStorage) Cleanup(retention time.Duration) error {
 	if !s.enabled || s.db == nil {
@@ -54,6 +131,22 @@ func (s *SQLiteStorage) Cleanup(retention time.Duration) error {
 		log.Printf("Warning: failed to cleanup search_history: %v", err)
 	}
 
+	// Cleanup search_snapshots
+	if _, err := s.db.Exec("DELETE FROM search_snapshots WHERE updated_at < ?", cutoff); err != nil {
+		log.Printf("Warning: failed to cleanup search_snapshots: %v", err)
+	}
+
+	// Cleanup audit_log
+	auditCutoff := time.Now().Add(-retention).UTC().Format(auditTimeFormat)
+	if _, err := s.db.Exec("DELETE FROM audit_log WHERE timestamp < ?", auditCutoff); err != nil {
+		log.Printf("Warning: failed to cleanup audit_log: %v", err)
+	}
+
+	// Cleanup argument_mistakes
+	if _, err := s.db.Exec("DELETE FROM argument_mistakes WHERE last_seen < ?", auditCutoff); err != nil {
+		log.Printf("Warning: failed to cleanup argument_mistakes: %v", err)
+	}
+
 	// Vacuum to reclaim space
 	if _, err := s.db.Exec("VACUUM"); err != nil {
 		log.Printf("Warning: failed to vacuum database: %v", err)
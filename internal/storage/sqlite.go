@@ -7,6 +7,7 @@ utilities for the storage layer.
 package storage
 
 import (
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -23,8 +24,18 @@ func (s *SQLiteStorage) runMigrations() error {
 		return err
 	}
 
+	// Migrations run in one IMMEDIATE transaction (see dataSourceName), so
+	// when serve and a CLI command open a fresh database at the same time
+	// the second waits for the first and then sees its schema version
+	// instead of applying the same migrations twice.
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to lock schema for migration: %w", err)
+	}
+	defer tx.Rollback()
+
 	// Get current version
-	version, err := s.getCurrentMigrationVersion()
+	version, err := getCurrentMigrationVersion(tx)
 	if err != nil {
 		return err
 	}
@@ -32,28 +43,35 @@ func (s *SQLiteStorage) runMigrations() error {
 	// Run migrations in order
 	migrations := []migration{
 		{version: 1, name: "initial_schema", up: s.migration001InitialSchema},
+		{version: 2, name: "usage_success", up: s.migration002UsageSuccess},
+		{version: 3, name: "search_snapshots", up: s.migration003SearchSnapshots},
+		{version: 4, name: "audit_log", up: s.migration004AuditLog},
+		{version: 5, name: "server_status", up: s.migration005ServerStatus},
+		{version: 6, name: "usage_context", up: s.migration006UsageContext},
+		{version: 7, name: "tracing", up: s.migration007Tracing},
+		{version: 8, name: "argument_mistakes", up: s.migration008ArgumentMistakes},
 	}
 
 	for _, m := range migrations {
 		if version < m.version {
 			log.Printf("Running migration %d: %s", m.version, m.name)
-			if err := m.up(); err != nil {
+			if err := m.up(tx); err != nil {
 				return fmt.Errorf("migration %d failed: %w", m.version, err)
 			}
-			if err := s.setMigrationVersion(m.version); err != nil {
+			if err := setMigrationVersion(tx, m.version); err != nil {
 				return err
 			}
 		}
 	}
 
-	return nil
+	return tx.Commit()
 }
 
 // migration represents a single database migration.
 type migration struct {
 	version int
 	name    string
-	up      func() error
+	up      func(tx *sql.Tx) error
 }
 
 // createMigrationsTable creates the schema_migrations table.
@@ -70,9 +88,9 @@ func (s *SQLiteStorage) createMigrationsTable() error {
 }
 
 // getCurrentMigrationVersion returns the highest applied migration version.
-func (s *SQLiteStorage) getCurrentMigrationVersion() (int, error) {
+func getCurrentMigrationVersion(tx *sql.Tx) (int, error) {
 	query := "SELECT COALESCE(MAX(version), 0) FROM schema_migrations"
-	row := s.db.QueryRow(query)
+	row := tx.QueryRow(query)
 
 	var version int
 	if err := row.Scan(&version); err != nil {
@@ -83,16 +101,16 @@ func (s *SQLiteStorage) getCurrentMigrationVersion() (int, error) {
 }
 
 // setMigrationVersion records a migration as applied.
-func (s *SQLiteStorage) setMigrationVersion(version int) error {
+func setMigrationVersion(tx *sql.Tx, version int) error {
 	query := "INSERT INTO schema_migrations (version, name) VALUES (?, ?)"
-	_, err := s.db.Exec(query, version, fmt.Sprintf("migration_%d", version))
+	_, err := tx.Exec(query, version, fmt.Sprintf("migration_%d", version))
 	return err
 }
 
 // migration001InitialSchema creates the initial database schema.
-func (s *SQLiteStorage) migration001InitialSchema() error {
+func (s *SQLiteStorage) migration001InitialSchema(tx *sql.Tx) error {
 	// Create tool_usage table
-	if _, err := s.db.Exec(`
+	if _, err := tx.Exec(`
 		CREATE TABLE IF NOT EXISTS tool_usage (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			tool_name TEXT NOT NULL,
@@ -107,21 +125,21 @@ func (s *SQLiteStorage) migration001InitialSchema() error {
 	}
 
 	// Create indexes for tool_usage
-	if _, err := s.db.Exec(`
+	if _, err := tx.Exec(`
 		CREATE INDEX IF NOT EXISTS idx_tool_usage_tool
 		ON tool_usage(tool_name)
 	`); err != nil {
 		return fmt.Errorf("failed to create tool_usage tool index: %w", err)
 	}
 
-	if _, err := s.db.Exec(`
+	if _, err := tx.Exec(`
 		CREATE INDEX IF NOT EXISTS idx_tool_usage_context
 		ON tool_usage(context_hash)
 	`); err != nil {
 		return fmt.Errorf("failed to create tool_usage context index: %w", err)
 	}
 
-	if _, err := s.db.Exec(`
+	if _, err := tx.Exec(`
 		CREATE INDEX IF NOT EXISTS idx_tool_usage_timestamp
 		ON tool_usage(timestamp DESC)
 	`); err != nil {
@@ -129,7 +147,7 @@ func (s *SQLiteStorage) migration001InitialSchema() error {
 	}
 
 	// Create search_history table
-	if _, err := s.db.Exec(`
+	if _, err := tx.Exec(`
 		CREATE TABLE IF NOT EXISTS search_history (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			search_id TEXT NOT NULL UNIQUE,
@@ -142,7 +160,7 @@ func (s *SQLiteStorage) migration001InitialSchema() error {
 	}
 
 	// Create index for search_history
-	if _, err := s.db.Exec(`
+	if _, err := tx.Exec(`
 		CREATE INDEX IF NOT EXISTS idx_search_history_timestamp
 		ON search_history(timestamp DESC)
 	`); err != nil {
@@ -150,7 +168,7 @@ func (s *SQLiteStorage) migration001InitialSchema() error {
 	}
 
 	// Create tool_embeddings table
-	if _, err := s.db.Exec(`
+	if _, err := tx.Exec(`
 		CREATE TABLE IF NOT EXISTS tool_embeddings (
 			tool_name TEXT PRIMARY KEY,
 			vector BLOB NOT NULL,
@@ -164,6 +182,135 @@ func (s *SQLiteStorage) migration001InitialSchema() error {
 	return nil
 }
 
+// migration002UsageSuccess adds the execution outcome to tool_usage.
+// Rows recorded before this migration are treated as successful.
+func (s *SQLiteStorage) migration002UsageSuccess(tx *sql.Tx) error {
+	if _, err := tx.Exec(`
+		ALTER TABLE tool_usage ADD COLUMN success INTEGER NOT NULL DEFAULT 1
+	`); err != nil {
+		return fmt.Errorf("failed to add tool_usage success column: %w", err)
+	}
+
+	return nil
+}
+
+// migration003SearchSnapshots stores the last result set per normalized query,
+// so hub_search can report tools that appeared or disappeared between sessions.
+func (s *SQLiteStorage) migration003SearchSnapshots(tx *sql.Tx) error {
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS search_snapshots (
+			query_hash TEXT PRIMARY KEY,
+			tools TEXT NOT NULL,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)
+	`); err != nil {
+		return fmt.Errorf("failed to create search_snapshots table: %w", err)
+	}
+
+	return nil
+}
+
+// migration004AuditLog records every hub_execute call for compliance
+// and debugging of agent behavior.
+func (s *SQLiteStorage) migration004AuditLog(tx *sql.Tx) error {
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS audit_log (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			timestamp TEXT NOT NULL,
+			server TEXT NOT NULL,
+			tool TEXT NOT NULL,
+			args_hash TEXT,
+			args TEXT,
+			duration_ms INTEGER NOT NULL DEFAULT 0,
+			success INTEGER NOT NULL DEFAULT 1,
+			error TEXT
+		)
+	`); err != nil {
+		return fmt.Errorf("failed to create audit_log table: %w", err)
+	}
+
+	if _, err := tx.Exec(`CREATE INDEX IF NOT EXISTS idx_audit_log_timestamp ON audit_log(timestamp)`); err != nil {
+		return fmt.Errorf("failed to create audit_log index: %w", err)
+	}
+
+	return nil
+}
+
+// migration005ServerStatus tracks the latest tool discovery per server
+// for `list`.
+func (s *SQLiteStorage) migration005ServerStatus(tx *sql.Tx) error {
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS server_status (
+			server TEXT PRIMARY KEY,
+			tool_count INTEGER NOT NULL DEFAULT 0,
+			last_success TEXT,
+			last_error TEXT,
+			last_error_at TEXT
+		)
+	`); err != nil {
+		return fmt.Errorf("failed to create server_status table: %w", err)
+	}
+
+	return nil
+}
+
+// migration006UsageContext links usage events to the search that led to
+// them and the server that ran the tool, for per-query learning.
+func (s *SQLiteStorage) migration006UsageContext(tx *sql.Tx) error {
+	if _, err := tx.Exec(`ALTER TABLE tool_usage ADD COLUMN search_id TEXT`); err != nil {
+		return fmt.Errorf("failed to add tool_usage search_id column: %w", err)
+	}
+	if _, err := tx.Exec(`ALTER TABLE tool_usage ADD COLUMN server TEXT`); err != nil {
+		return fmt.Errorf("failed to add tool_usage server column: %w", err)
+	}
+	if _, err := tx.Exec(`CREATE INDEX IF NOT EXISTS idx_tool_usage_search ON tool_usage(search_id)`); err != nil {
+		return fmt.Errorf("failed to create tool_usage search index: %w", err)
+	}
+	if _, err := tx.Exec(`CREATE INDEX IF NOT EXISTS idx_search_history_query ON search_history(query_hash)`); err != nil {
+		return fmt.Errorf("failed to create search_history query index: %w", err)
+	}
+
+	return nil
+}
+
+// migration007Tracing links audited executions to the search they followed
+// and keeps the results each search showed, for `trace`.
+func (s *SQLiteStorage) migration007Tracing(tx *sql.Tx) error {
+	statements := []string{
+		`ALTER TABLE search_history ADD COLUMN results TEXT`,
+		`ALTER TABLE search_history ADD COLUMN query TEXT`,
+		`ALTER TABLE audit_log ADD COLUMN execution_id TEXT`,
+		`ALTER TABLE audit_log ADD COLUMN search_id TEXT`,
+		`CREATE INDEX IF NOT EXISTS idx_audit_log_search ON audit_log(search_id)`,
+	}
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement); err != nil {
+			return fmt.Errorf("failed to add tracing columns: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// migration008ArgumentMistakes counts the invalid-params errors children
+// answer hub_execute calls with, per tool and message, for hub_help.
+func (s *SQLiteStorage) migration008ArgumentMistakes(tx *sql.Tx) error {
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS argument_mistakes (
+			server TEXT NOT NULL,
+			tool TEXT NOT NULL,
+			error TEXT NOT NULL,
+			count INTEGER NOT NULL DEFAULT 1,
+			last_seen TEXT NOT NULL,
+			PRIMARY KEY (server, tool, error)
+		)
+	`); err != nil {
+		return fmt.Errorf("failed to create argument_mistakes table: %w", err)
+	}
+
+	return nil
+}
+
 // vectorToJSON converts a float32 vector to JSON for storage.
 func vectorToJSON(vector []float32) string {
 	data, err := json.Marshal(vector)
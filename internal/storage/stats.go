@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"log"
+	"time"
+)
+
+// DailyToolUsage aggregates executions of a single tool on a single day.
+type DailyToolUsage struct {
+	// Day is the local calendar day (YYYY-MM-DD) of the executions.
+	Day string `json:"day"`
+
+	// ToolName is the name of the executed tool.
+	ToolName string `json:"tool_name"`
+
+	// Uses is the total number of executions.
+	Uses int `json:"uses"`
+
+	// Successes is the number of executions that completed without error.
+	Successes int `json:"successes"`
+
+	// Contexts is the number of distinct search contexts the tool was used from.
+	Contexts int `json:"contexts"`
+}
+
+// SuccessRate returns the fraction of successful executions (0-1).
+func (d DailyToolUsage) SuccessRate() float64 {
+	if d.Uses == 0 {
+		return 0.0
+	}
+	return float64(d.Successes) / float64(d.Uses)
+}
+
+// GetDailyUsage aggregates tool usage per day and per tool since a given time.
+// Results are ordered by day, then by tool name.
+func (s *SQLiteStorage) GetDailyUsage(since time.Time) ([]DailyToolUsage, error) {
+	if !s.enabled || s.db == nil {
+		return []DailyToolUsage{}, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Timestamps are stored as RFC3339 strings, so the first 10 characters
+	// are the calendar day in the timezone the event was recorded in. Their
+	// offsets differ (e.g. across DST), so the range is compared as
+	// instants with julianday rather than as strings.
+	query := `
+		SELECT substr(timestamp, 1, 10) AS day,
+		       tool_name,
+		       COUNT(*),
+		       SUM(success),
+		       COUNT(DISTINCT NULLIF(context_hash, ''))
+		FROM tool_usage
+		WHERE julianday(timestamp) >= julianday(?)
+		GROUP BY day, tool_name
+		ORDER BY day ASC, tool_name ASC
+	`
+
+	rows, err := s.db.Query(query, since.Format(time.RFC3339))
+	if err != nil {
+		log.Printf("Warning: failed to query daily usage: %v", err)
+		return []DailyToolUsage{}, nil
+	}
+	defer rows.Close()
+
+	var stats []DailyToolUsage
+	for rows.Next() {
+		var d DailyToolUsage
+		if err := rows.Scan(&d.Day, &d.ToolName, &d.Uses, &d.Successes, &d.Contexts); err != nil {
+			log.Printf("Warning: failed to scan daily usage row: %v", err)
+			continue
+		}
+		stats = append(stats, d)
+	}
+
+	return stats, nil
+}
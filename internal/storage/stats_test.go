@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestGetDailyUsage verifies per-day, per-tool aggregation with success counts.
+func TestGetDailyUsage(t *testing.T) {
+	storage := &SQLiteStorage{
+		dbPath:  filepath.Join(t.TempDir(), "test.db"),
+		enabled: true,
+	}
+	if err := storage.Init(); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	defer storage.Close()
+
+	now := time.Now()
+	yesterday := now.Add(-24 * time.Hour)
+	events := []UsageEvent{
+		{ToolName: "create_issue", ContextHash: "a", Timestamp: now, Selected: true, Success: true},
+		{ToolName: "create_issue", ContextHash: "b", Timestamp: now, Selected: true, Success: false},
+		{ToolName: "create_issue", ContextHash: "b", Timestamp: now, Selected: true, Success: true},
+		{ToolName: "search", ContextHash: "", Timestamp: yesterday, Selected: true, Success: true},
+	}
+	for _, e := range events {
+		if err := storage.RecordUsage(e); err != nil {
+			t.Fatalf("RecordUsage failed: %v", err)
+		}
+	}
+
+	stats, err := storage.GetDailyUsage(now.Add(-48 * time.Hour))
+	if err != nil {
+		t.Fatalf("GetDailyUsage failed: %v", err)
+	}
+	if len(stats) != 2 {
+		t.Fatalf("Expected 2 rows, got %d: %+v", len(stats), stats)
+	}
+
+	// Ordered by day: yesterday's search first
+	if stats[0].ToolName != "search" || stats[0].Day != yesterday.Format("2006-01-02") {
+		t.Errorf("Unexpected first row: %+v", stats[0])
+	}
+	if stats[0].Contexts != 0 {
+		t.Errorf("Expected empty context to be ignored, got %d", stats[0].Contexts)
+	}
+
+	issue := stats[1]
+	if issue.Uses != 3 || issue.Successes != 2 || issue.Contexts != 2 {
+		t.Errorf("Unexpected create_issue row: %+v", issue)
+	}
+	if rate := issue.SuccessRate(); rate < 0.66 || rate > 0.67 {
+		t.Errorf("Expected success rate ~0.667, got %f", rate)
+	}
+}
+
+// TestGetDailyUsageComparesInstants verifies events recorded under a
+// different UTC offset are filtered by time, not by their text.
+func TestGetDailyUsageComparesInstants(t *testing.T) {
+	storage := &SQLiteStorage{
+		dbPath:  filepath.Join(t.TempDir(), "test.db"),
+		enabled: true,
+	}
+	if err := storage.Init(); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	defer storage.Close()
+
+	since := time.Date(2026, 3, 1, 5, 0, 0, 0, time.UTC)
+	east := time.FixedZone("UTC+7", 7*60*60)
+	west := time.FixedZone("UTC-5", -5*60*60)
+	events := []UsageEvent{
+		// 03:00 UTC, but "10:00+07:00" sorts after "05:00Z"
+		{ToolName: "before", Timestamp: since.Add(-2 * time.Hour).In(east)},
+		// 06:00 UTC, but "01:00-05:00" sorts before "05:00Z"
+		{ToolName: "after", Timestamp: since.Add(time.Hour).In(west)},
+	}
+	for _, e := range events {
+		if err := storage.RecordUsage(e); err != nil {
+			t.Fatalf("RecordUsage failed: %v", err)
+		}
+	}
+
+	stats, err := storage.GetDailyUsage(since)
+	if err != nil {
+		t.Fatalf("GetDailyUsage failed: %v", err)
+	}
+	if len(stats) != 1 || stats[0].ToolName != "after" {
+		t.Errorf("Expected only the later event, got %+v", stats)
+	}
+}
+
+// TestGetUsageHistorySuccess verifies the success flag round-trips.
+func TestGetUsageHistorySuccess(t *testing.T) {
+	storage := &SQLiteStorage{
+		dbPath:  filepath.Join(t.TempDir(), "test.db"),
+		enabled: true,
+	}
+	if err := storage.Init(); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	defer storage.Close()
+
+	storage.RecordUsage(UsageEvent{ToolName: "ok", Timestamp: time.Now(), Success: true})
+	storage.RecordUsage(UsageEvent{ToolName: "bad", Timestamp: time.Now(), Success: false})
+
+	ok, _ := storage.GetUsageHistory("ok", time.Now().Add(-time.Hour))
+	bad, _ := storage.GetUsageHistory("bad", time.Now().Add(-time.Hour))
+	if len(ok) != 1 || !ok[0].Success {
+		t.Errorf("Expected successful event, got %+v", ok)
+	}
+	if len(bad) != 1 || bad[0].Success {
+		t.Errorf("Expected failed event, got %+v", bad)
+	}
+}
+
+// TestGetDailyUsageDisabled verifies graceful degradation.
+func TestGetDailyUsageDisabled(t *testing.T) {
+	storage := &SQLiteStorage{enabled: false}
+	stats, err := storage.GetDailyUsage(time.Now())
+	if err != nil {
+		t.Errorf("Expected no error on disabled storage, got: %v", err)
+	}
+	if len(stats) != 0 {
+		t.Errorf("Expected no rows, got %d", len(stats))
+	}
+}
@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+)
+
+// RecordDiscovery stores the outcome of listing a server's tools. A
+// failure keeps the tool count and time of the last successful discovery.
+func (s *SQLiteStorage) RecordDiscovery(server string, toolCount int, discoveryErr error) error {
+	if !s.enabled || s.db == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC().Format(auditTimeFormat)
+
+	var err error
+	if discoveryErr == nil {
+		_, err = s.db.Exec(`
+			INSERT INTO server_status (server, tool_count, last_success, last_error, last_error_at)
+			VALUES (?, ?, ?, NULL, NULL)
+			ON CONFLICT(server) DO UPDATE SET
+				tool_count = excluded.tool_count,
+				last_success = excluded.last_success,
+				last_error = NULL,
+				last_error_at = NULL
+		`, server, toolCount, now)
+	} else {
+		_, err = s.db.Exec(`
+			INSERT INTO server_status (server, last_error, last_error_at)
+			VALUES (?, ?, ?)
+			ON CONFLICT(server) DO UPDATE SET
+				last_error = excluded.last_error,
+				last_error_at = excluded.last_error_at
+		`, server, discoveryErr.Error(), now)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record discovery: %w", err)
+	}
+	return nil
+}
+
+// GetServerStatuses returns the latest discovery outcome per server.
+func (s *SQLiteStorage) GetServerStatuses() (map[string]ServerStatus, error) {
+	statuses := make(map[string]ServerStatus)
+	if !s.enabled || s.db == nil {
+		return statuses, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rows, err := s.db.Query(`SELECT server, tool_count, last_success, last_error, last_error_at FROM server_status`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query server status: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var status ServerStatus
+		var lastSuccess, lastError, lastErrorAt *string
+		if err := rows.Scan(&status.Server, &status.ToolCount, &lastSuccess, &lastError, &lastErrorAt); err != nil {
+			return nil, fmt.Errorf("failed to read server status: %w", err)
+		}
+		if lastSuccess != nil {
+			status.LastSuccess, _ = time.Parse(auditTimeFormat, *lastSuccess)
+		}
+		if lastError != nil {
+			status.LastError = *lastError
+		}
+		if lastErrorAt != nil {
+			status.LastErrorAt, _ = time.Parse(auditTimeFormat, *lastErrorAt)
+		}
+		statuses[status.Server] = status
+	}
+	return statuses, rows.Err()
+}
@@ -0,0 +1,50 @@
+package storage
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+// TestRecordDiscovery verifies failures keep the last successful discovery.
+func TestRecordDiscovery(t *testing.T) {
+	storage := &SQLiteStorage{
+		dbPath:  filepath.Join(t.TempDir(), "test.db"),
+		enabled: true,
+	}
+	if err := storage.Init(); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	defer storage.Close()
+
+	if err := storage.RecordDiscovery("jira", 12, nil); err != nil {
+		t.Fatalf("RecordDiscovery failed: %v", err)
+	}
+	if err := storage.RecordDiscovery("jira", 0, errors.New("spawn failed")); err != nil {
+		t.Fatalf("RecordDiscovery failed: %v", err)
+	}
+	if err := storage.RecordDiscovery("github", 0, errors.New("missing token")); err != nil {
+		t.Fatalf("RecordDiscovery failed: %v", err)
+	}
+
+	statuses, err := storage.GetServerStatuses()
+	if err != nil {
+		t.Fatalf("GetServerStatuses failed: %v", err)
+	}
+	jira := statuses["jira"]
+	if jira.ToolCount != 12 || jira.LastSuccess.IsZero() || jira.LastError != "spawn failed" || jira.LastErrorAt.IsZero() {
+		t.Errorf("unexpected jira status: %+v", jira)
+	}
+	github := statuses["github"]
+	if github.ToolCount != 0 || !github.LastSuccess.IsZero() || github.LastError != "missing token" {
+		t.Errorf("unexpected github status: %+v", github)
+	}
+
+	if err := storage.RecordDiscovery("jira", 13, nil); err != nil {
+		t.Fatalf("RecordDiscovery failed: %v", err)
+	}
+	statuses, _ = storage.GetServerStatuses()
+	if statuses["jira"].ToolCount != 13 || statuses["jira"].LastError != "" {
+		t.Errorf("success should clear the error: %+v", statuses["jira"])
+	}
+}
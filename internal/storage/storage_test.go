@@ -4,8 +4,10 @@ Package storage provides tests for the storage layer.
 package storage
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 )
@@ -93,6 +95,37 @@ func TestRecordUsage(t *testing.T) {
 	}
 }
 
+// TestSearchSnapshot verifies snapshots round-trip and are replaced on save.
+func TestSearchSnapshot(t *testing.T) {
+	storage := &SQLiteStorage{
+		dbPath:  filepath.Join(t.TempDir(), "test.db"),
+		enabled: true,
+	}
+	if err := storage.Init(); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	defer storage.Close()
+
+	snapshot, err := storage.GetSearchSnapshot("q1")
+	if err != nil || snapshot != nil {
+		t.Fatalf("Expected no snapshot, got %+v (err %v)", snapshot, err)
+	}
+
+	storage.SaveSearchSnapshot("q1", []string{"jira/create_issue", "jira/search"})
+	storage.SaveSearchSnapshot("q1", []string{"jira/search"})
+
+	snapshot, err = storage.GetSearchSnapshot("q1")
+	if err != nil || snapshot == nil {
+		t.Fatalf("Expected snapshot, got %+v (err %v)", snapshot, err)
+	}
+	if len(snapshot.Tools) != 1 || snapshot.Tools[0] != "jira/search" {
+		t.Errorf("Expected latest snapshot, got %v", snapshot.Tools)
+	}
+	if snapshot.UpdatedAt.IsZero() {
+		t.Error("Expected UpdatedAt to be set")
+	}
+}
+
 // TestHashQuery verifies query hashing consistency.
 func TestHashQuery(t *testing.T) {
 	query := "test query for hashing"
@@ -143,3 +176,64 @@ func TestGracefulDegradation(t *testing.T) {
 		t.Errorf("Expected empty history on disabled storage, got %d events", len(history))
 	}
 }
+
+// TestConcurrentInstances verifies that separate handles on one database,
+// as serve and CLI commands hold, can migrate and write concurrently.
+func TestConcurrentInstances(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	const instances = 4
+	const writes = 25
+
+	var wg sync.WaitGroup
+	errs := make(chan error, instances*writes)
+	handles := make([]*SQLiteStorage, instances)
+	for i := range handles {
+		handles[i] = &SQLiteStorage{dbPath: dbPath, enabled: true}
+		wg.Add(1)
+		go func(s *SQLiteStorage, n int) {
+			defer wg.Done()
+			if err := s.Init(); err != nil {
+				errs <- fmt.Errorf("instance %d init: %w", n, err)
+				return
+			}
+			for j := 0; j < writes; j++ {
+				if err := s.RecordUsage(UsageEvent{
+					ToolName:    "tool",
+					ContextHash: "ctx",
+					Timestamp:   time.Now(),
+					Selected:    true,
+					Success:     true,
+				}); err != nil {
+					errs <- fmt.Errorf("instance %d write %d: %w", n, j, err)
+				}
+			}
+		}(handles[i], i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+	defer func() {
+		for _, s := range handles {
+			s.Close()
+		}
+	}()
+
+	var mode string
+	if err := handles[0].db.QueryRow("PRAGMA journal_mode").Scan(&mode); err != nil {
+		t.Fatalf("failed to read journal mode: %v", err)
+	}
+	if mode != "wal" {
+		t.Errorf("expected WAL journal mode, got %q", mode)
+	}
+
+	history, err := handles[0].GetUsageHistory("tool", time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("GetUsageHistory failed: %v", err)
+	}
+	if len(history) != instances*writes {
+		t.Errorf("expected %d events, got %d", instances*writes, len(history))
+	}
+}
@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Trace is the decision chain of one hub_search: the search and the
+// hub_execute calls made with its searchId.
+type Trace struct {
+	Search     SearchRecord `json:"search"`
+	Executions []AuditEntry `json:"executions"`
+}
+
+// GetTrace returns the trace of a search, or nil if the search is unknown.
+// Executions are ordered oldest first.
+func (s *SQLiteStorage) GetTrace(searchID string) (*Trace, error) {
+	if !s.enabled || s.db == nil {
+		return nil, nil
+	}
+
+	s.mu.Lock()
+	row := s.db.QueryRow(`
+		SELECT query_hash, timestamp, results_count, COALESCE(results, ''), COALESCE(query, '')
+		FROM search_history
+		WHERE search_id = ?
+	`, searchID)
+
+	trace := &Trace{Search: SearchRecord{SearchID: searchID}}
+	var timestamp, results string
+	err := row.Scan(&trace.Search.QueryHash, &timestamp, &trace.Search.ResultsCount, &results, &trace.Search.Query)
+	s.mu.Unlock()
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query search: %w", err)
+	}
+	trace.Search.Timestamp, _ = time.Parse(time.RFC3339, timestamp)
+	if results != "" {
+		json.Unmarshal([]byte(results), &trace.Search.Results)
+	}
+
+	executions, err := s.GetAuditEntries(AuditFilter{SearchID: searchID})
+	if err != nil {
+		return nil, err
+	}
+	for i, j := 0, len(executions)-1; i < j; i, j = i+1, j-1 {
+		executions[i], executions[j] = executions[j], executions[i]
+	}
+	trace.Executions = executions
+
+	return trace, nil
+}
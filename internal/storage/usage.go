@@ -22,10 +22,14 @@ func (s *SQLiteStorage) RecordUsage(event UsageEvent) error {
 	if event.WasRecommended {
 		wasRecommended = 1
 	}
+	success := 0
+	if event.Success {
+		success = 1
+	}
 
 	query := `
-		INSERT INTO tool_usage (tool_name, context_hash, timestamp, selected, rating, was_recommended)
-		VALUES (?, ?, ?, ?, ?, ?)
+		INSERT INTO tool_usage (tool_name, context_hash, timestamp, selected, rating, was_recommended, success, server, search_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	_, err := s.db.Exec(query,
@@ -35,6 +39,9 @@ func (s *SQLiteStorage) RecordUsage(event UsageEvent) error {
 		selected,
 		event.Rating,
 		wasRecommended,
+		success,
+		nullString(event.Server),
+		nullString(event.SearchID),
 	)
 
 	if err != nil {
@@ -54,7 +61,7 @@ func (s *SQLiteStorage) GetUsageHistory(toolName string, since time.Time) ([]Usa
 	defer s.mu.Unlock()
 
 	query := `
-		SELECT tool_name, context_hash, timestamp, selected, rating, was_recommended
+		SELECT tool_name, context_hash, timestamp, selected, rating, was_recommended, success
 		FROM tool_usage
 		WHERE tool_name = ? AND timestamp >= ?
 		ORDER BY timestamp DESC
@@ -71,7 +78,7 @@ func (s *SQLiteStorage) GetUsageHistory(toolName string, since time.Time) ([]Usa
 	for rows.Next() {
 		var event UsageEvent
 		var timestampStr string
-		var selected, wasRecommended int
+		var selected, wasRecommended, success int
 		var rating int
 
 		if err := rows.Scan(
@@ -81,6 +88,7 @@ func (s *SQLiteStorage) GetUsageHistory(toolName string, since time.Time) ([]Usa
 			&selected,
 			&rating,
 			&wasRecommended,
+			&success,
 		); err != nil {
 			log.Printf("Warning: failed to scan usage row: %v", err)
 			continue
@@ -88,6 +96,7 @@ func (s *SQLiteStorage) GetUsageHistory(toolName string, since time.Time) ([]Usa
 
 		event.Selected = selected == 1
 		event.WasRecommended = wasRecommended == 1
+		event.Success = success == 1
 		event.Rating = rating
 
 		event.Timestamp, err = time.Parse(time.RFC3339, timestampStr)
@@ -101,3 +110,53 @@ func (s *SQLiteStorage) GetUsageHistory(toolName string, since time.Time) ([]Usa
 
 	return events, nil
 }
+
+// GetQueryAffinity returns, per server/tool, how executions went after
+// searches whose query hashed to queryHash, since a given time. Usage
+// events are joined to search_history through their search ID.
+func (s *SQLiteStorage) GetQueryAffinity(queryHash string, since time.Time) ([]ToolAffinity, error) {
+	if !s.enabled || s.db == nil {
+		return nil, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	query := `
+		SELECT COALESCE(u.server, ''), u.tool_name, COUNT(*), SUM(u.success), MAX(u.timestamp)
+		FROM tool_usage u
+		JOIN search_history h ON h.search_id = u.search_id
+		WHERE h.query_hash = ? AND u.timestamp >= ?
+		GROUP BY u.server, u.tool_name
+		ORDER BY COUNT(*) DESC
+	`
+
+	rows, err := s.db.Query(query, queryHash, since.Format(time.RFC3339))
+	if err != nil {
+		log.Printf("Warning: failed to query tool affinity: %v", err)
+		return nil, nil
+	}
+	defer rows.Close()
+
+	var affinities []ToolAffinity
+	for rows.Next() {
+		var a ToolAffinity
+		var lastUsed string
+		if err := rows.Scan(&a.Server, &a.ToolName, &a.Selections, &a.Successes, &lastUsed); err != nil {
+			log.Printf("Warning: failed to scan tool affinity: %v", err)
+			continue
+		}
+		a.LastUsed, _ = time.Parse(time.RFC3339, lastUsed)
+		affinities = append(affinities, a)
+	}
+
+	return affinities, nil
+}
+
+// nullString stores empty strings as NULL.
+func nullString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestGetQueryAffinity verifies usage is attributed to the query of the
+// search it followed.
+func TestGetQueryAffinity(t *testing.T) {
+	storage := &SQLiteStorage{
+		dbPath:  filepath.Join(t.TempDir(), "test.db"),
+		enabled: true,
+	}
+	if err := storage.Init(); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	defer storage.Close()
+
+	now := time.Now()
+	storage.RecordSearch(SearchRecord{SearchID: "s1", QueryHash: HashQuery("create issue"), Timestamp: now, ResultsCount: 2})
+	storage.RecordSearch(SearchRecord{SearchID: "s2", QueryHash: HashQuery("create issue"), Timestamp: now, ResultsCount: 2})
+	storage.RecordSearch(SearchRecord{SearchID: "s3", QueryHash: HashQuery("send message"), Timestamp: now, ResultsCount: 1})
+
+	storage.RecordUsage(UsageEvent{ToolName: "create_issue", Server: "linear", SearchID: "s1", Timestamp: now, Selected: true, Success: true})
+	storage.RecordUsage(UsageEvent{ToolName: "create_issue", Server: "linear", SearchID: "s2", Timestamp: now, Selected: true, Success: false})
+	storage.RecordUsage(UsageEvent{ToolName: "create_issue", Server: "jira", SearchID: "s2", Timestamp: now, Selected: true, Success: true})
+	storage.RecordUsage(UsageEvent{ToolName: "send_message", Server: "slack", SearchID: "s3", Timestamp: now, Selected: true, Success: true})
+	storage.RecordUsage(UsageEvent{ToolName: "create_issue", Server: "jira", Timestamp: now, Selected: true, Success: true})
+
+	affinities, err := storage.GetQueryAffinity(HashQuery("create issue"), now.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("GetQueryAffinity failed: %v", err)
+	}
+	if len(affinities) != 2 {
+		t.Fatalf("expected 2 tools, got %+v", affinities)
+	}
+	if a := affinities[0]; a.Server != "linear" || a.Selections != 2 || a.Successes != 1 {
+		t.Errorf("unexpected linear affinity: %+v", a)
+	}
+	if a := affinities[1]; a.Server != "jira" || a.Selections != 1 || a.Successes != 1 {
+		t.Errorf("unexpected jira affinity (events without a search must not count): %+v", a)
+	}
+}
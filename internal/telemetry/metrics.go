@@ -0,0 +1,76 @@
+package telemetry
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/metrics"
+)
+
+// OTLP aggregation temporality: the registry's values only grow.
+const temporalityCumulative = 2
+
+// encodeMetrics converts registry families to OTLP JSON metrics.
+func encodeMetrics(families []metrics.Family, start, now time.Time) []map[string]interface{} {
+	startNano, nowNano := fmt.Sprint(start.UnixNano()), fmt.Sprint(now.UnixNano())
+
+	encoded := make([]map[string]interface{}, 0, len(families))
+	for _, f := range families {
+		points := make([]map[string]interface{}, 0, len(f.Points))
+		for _, p := range f.Points {
+			point := map[string]interface{}{
+				"attributes":        labelAttributes(p.Labels),
+				"startTimeUnixNano": startNano,
+				"timeUnixNano":      nowNano,
+			}
+			if f.Kind == metrics.KindHistogram {
+				counts := make([]string, len(p.BucketCounts))
+				for i, c := range p.BucketCounts {
+					counts[i] = fmt.Sprint(c)
+				}
+				point["count"] = fmt.Sprint(p.Count)
+				point["sum"] = p.Sum
+				point["bucketCounts"] = counts
+				point["explicitBounds"] = f.Bounds
+			} else {
+				point["asDouble"] = p.Value
+			}
+			points = append(points, point)
+		}
+
+		metric := map[string]interface{}{"name": f.Name, "description": f.Help}
+		switch f.Kind {
+		case metrics.KindCounter:
+			metric["sum"] = map[string]interface{}{
+				"dataPoints":             points,
+				"aggregationTemporality": temporalityCumulative,
+				"isMonotonic":            true,
+			}
+		case metrics.KindHistogram:
+			metric["histogram"] = map[string]interface{}{
+				"dataPoints":             points,
+				"aggregationTemporality": temporalityCumulative,
+			}
+		default:
+			metric["gauge"] = map[string]interface{}{"dataPoints": points}
+		}
+		encoded = append(encoded, metric)
+	}
+	return encoded
+}
+
+// labelAttributes converts labels to OTLP attributes in name order.
+func labelAttributes(labels map[string]string) []map[string]interface{} {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	attributes := make([]map[string]interface{}, 0, len(names))
+	for _, name := range names {
+		attributes = append(attributes, attribute(name, labels[name]))
+	}
+	return attributes
+}
@@ -0,0 +1,112 @@
+package telemetry
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// SpanKind is the OTLP span kind.
+type SpanKind int
+
+// Span kinds used by the hub.
+const (
+	KindInternal SpanKind = 1
+	KindServer   SpanKind = 2
+	KindClient   SpanKind = 3
+)
+
+// Span is one timed operation. End it exactly once.
+type Span struct {
+	exporter *Exporter
+	traceID  string
+	spanID   string
+	parentID string
+	name     string
+	kind     SpanKind
+	start    time.Time
+
+	mu         sync.Mutex
+	end        time.Time
+	attributes []map[string]interface{}
+	err        error
+}
+
+// Start begins a root span, or returns nil when e is nil.
+func (e *Exporter) Start(name string, kind SpanKind) *Span {
+	if e == nil {
+		return nil
+	}
+	return &Span{
+		exporter: e,
+		traceID:  randomID(16),
+		spanID:   randomID(8),
+		name:     name,
+		kind:     kind,
+		start:    time.Now(),
+	}
+}
+
+// Child begins a span in the same trace as s, or returns nil when s is nil.
+func (s *Span) Child(name string, kind SpanKind) *Span {
+	if s == nil {
+		return nil
+	}
+	return &Span{
+		exporter: s.exporter,
+		traceID:  s.traceID,
+		spanID:   randomID(8),
+		parentID: s.spanID,
+		name:     name,
+		kind:     kind,
+		start:    time.Now(),
+	}
+}
+
+// SetAttribute records a string, bool, int, int64 or float64 attribute.
+func (s *Span) SetAttribute(key string, value interface{}) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.attributes = append(s.attributes, attribute(key, value))
+	s.mu.Unlock()
+}
+
+// End finishes the span, marking it failed when err is non-nil, and
+// queues it for export.
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.end = time.Now()
+	s.err = err
+	s.mu.Unlock()
+	s.exporter.enqueue(s)
+}
+
+// encode renders the span in the OTLP JSON encoding.
+func (s *Span) encode() map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	span := map[string]interface{}{
+		"traceId":           s.traceID,
+		"spanId":            s.spanID,
+		"name":              s.name,
+		"kind":              int(s.kind),
+		"startTimeUnixNano": fmt.Sprint(s.start.UnixNano()),
+		"endTimeUnixNano":   fmt.Sprint(s.end.UnixNano()),
+		"attributes":        s.attributes,
+	}
+	if s.parentID != "" {
+		span["parentSpanId"] = s.parentID
+	}
+	if s.err != nil {
+		span["status"] = map[string]interface{}{"code": 2, "message": s.err.Error()}
+	} else {
+		span["status"] = map[string]interface{}{"code": 1}
+	}
+	return span
+}
@@ -0,0 +1,330 @@
+/*
+Package telemetry exports spans and metrics to an OpenTelemetry collector
+over OTLP/HTTP with the JSON encoding.
+
+Like the metrics package, it implements only what the hub reports instead
+of pulling in the OpenTelemetry SDK: spans are buffered and sent in batches
+to <endpoint>/v1/traces, and a metrics.Registry snapshot is sent to
+<endpoint>/v1/metrics on an interval. All methods are safe on a nil
+*Exporter and nil *Span, so callers need no checks when export is off.
+*/
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/httpclient"
+	"github.com/khanglvm/tool-hub-mcp/internal/metrics"
+)
+
+const (
+	// DefaultServiceName is the service.name resource attribute.
+	DefaultServiceName = "tool-hub-mcp"
+
+	// spanInterval is how often buffered spans are sent.
+	spanInterval = 5 * time.Second
+
+	// metricInterval is how often metrics are sent.
+	metricInterval = 30 * time.Second
+
+	// maxQueuedSpans bounds buffered spans; later spans are dropped until
+	// the next export.
+	maxQueuedSpans = 2048
+
+	// exportTimeout bounds a single export request.
+	exportTimeout = 10 * time.Second
+)
+
+// Config selects the collector.
+type Config struct {
+	// Endpoint is the collector's base URL, e.g. http://localhost:4318.
+	Endpoint string
+
+	// Headers are sent with every export request.
+	Headers map[string]string
+
+	// ServiceName defaults to DefaultServiceName.
+	ServiceName string
+
+	// ServiceVersion is the service.version resource attribute.
+	ServiceVersion string
+}
+
+// WithEnv fills unset fields from the standard OTEL_EXPORTER_OTLP_ENDPOINT,
+// OTEL_EXPORTER_OTLP_HEADERS (key=value,key=value) and OTEL_SERVICE_NAME.
+func (c Config) WithEnv() Config {
+	if c.Endpoint == "" {
+		c.Endpoint = os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+	}
+	if len(c.Headers) == 0 {
+		c.Headers = parseHeaders(os.Getenv("OTEL_EXPORTER_OTLP_HEADERS"))
+	}
+	if c.ServiceName == "" {
+		c.ServiceName = os.Getenv("OTEL_SERVICE_NAME")
+	}
+	return c
+}
+
+// parseHeaders parses a comma-separated key=value list.
+func parseHeaders(value string) map[string]string {
+	headers := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		key, val, ok := strings.Cut(pair, "=")
+		if ok && strings.TrimSpace(key) != "" {
+			headers[strings.TrimSpace(key)] = strings.TrimSpace(val)
+		}
+	}
+	return headers
+}
+
+// Exporter buffers spans and periodically sends them, and metrics, to the
+// collector. Thread-safe.
+type Exporter struct {
+	cfg      Config
+	client   *http.Client
+	resource map[string]interface{}
+	started  time.Time
+
+	mu      sync.Mutex
+	spans   []*Span
+	dropped int
+	failing bool // last export failed; logged once until it recovers
+
+	registry *metrics.Registry
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// New starts an exporter, or returns nil when cfg has no endpoint.
+func New(cfg Config) *Exporter {
+	if cfg.Endpoint == "" {
+		return nil
+	}
+	if cfg.ServiceName == "" {
+		cfg.ServiceName = DefaultServiceName
+	}
+	cfg.Endpoint = strings.TrimSuffix(cfg.Endpoint, "/")
+
+	attributes := []map[string]interface{}{attribute("service.name", cfg.ServiceName)}
+	if cfg.ServiceVersion != "" {
+		attributes = append(attributes, attribute("service.version", cfg.ServiceVersion))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	e := &Exporter{
+		cfg:      cfg,
+		client:   httpclient.New(exportTimeout),
+		resource: map[string]interface{}{"attributes": attributes},
+		started:  time.Now(),
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+	go e.run(ctx)
+	return e
+}
+
+// Endpoint returns the collector base URL.
+func (e *Exporter) Endpoint() string {
+	if e == nil {
+		return ""
+	}
+	return e.cfg.Endpoint
+}
+
+// SetMetrics selects the registry exported on every metrics interval.
+func (e *Exporter) SetMetrics(r *metrics.Registry) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	e.registry = r
+	e.mu.Unlock()
+}
+
+// run exports on intervals until ctx is cancelled.
+func (e *Exporter) run(ctx context.Context) {
+	defer close(e.done)
+
+	spanTicker := time.NewTicker(spanInterval)
+	defer spanTicker.Stop()
+	metricTicker := time.NewTicker(metricInterval)
+	defer metricTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-spanTicker.C:
+			e.exportSpans()
+		case <-metricTicker.C:
+			e.exportMetrics()
+		}
+	}
+}
+
+// Close stops the exporter after sending what is buffered.
+func (e *Exporter) Close() error {
+	if e == nil {
+		return nil
+	}
+	e.cancel()
+	<-e.done
+	e.exportSpans()
+	e.exportMetrics()
+	return nil
+}
+
+// Flush sends buffered spans and current metrics now.
+func (e *Exporter) Flush() {
+	if e == nil {
+		return
+	}
+	e.exportSpans()
+	e.exportMetrics()
+}
+
+// enqueue buffers a finished span.
+func (e *Exporter) enqueue(s *Span) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if len(e.spans) >= maxQueuedSpans {
+		e.dropped++
+		return
+	}
+	e.spans = append(e.spans, s)
+}
+
+// exportSpans sends and clears the buffered spans.
+func (e *Exporter) exportSpans() {
+	e.mu.Lock()
+	spans := e.spans
+	dropped := e.dropped
+	e.spans, e.dropped = nil, 0
+	e.mu.Unlock()
+
+	if dropped > 0 {
+		log.Printf("Warning: dropped %d spans: export queue full", dropped)
+	}
+	if len(spans) == 0 {
+		return
+	}
+
+	encoded := make([]map[string]interface{}, len(spans))
+	for i, s := range spans {
+		encoded[i] = s.encode()
+	}
+	e.post("/v1/traces", map[string]interface{}{
+		"resourceSpans": []map[string]interface{}{{
+			"resource":   e.resource,
+			"scopeSpans": []map[string]interface{}{{"scope": e.scope(), "spans": encoded}},
+		}},
+	})
+}
+
+// exportMetrics sends a snapshot of the registry.
+func (e *Exporter) exportMetrics() {
+	e.mu.Lock()
+	registry := e.registry
+	e.mu.Unlock()
+	if registry == nil {
+		return
+	}
+
+	encoded := encodeMetrics(registry.Snapshot(), e.started, time.Now())
+	if len(encoded) == 0 {
+		return
+	}
+	e.post("/v1/metrics", map[string]interface{}{
+		"resourceMetrics": []map[string]interface{}{{
+			"resource":     e.resource,
+			"scopeMetrics": []map[string]interface{}{{"scope": e.scope(), "metrics": encoded}},
+		}},
+	})
+}
+
+// scope is the instrumentation scope of everything the hub exports.
+func (e *Exporter) scope() map[string]interface{} {
+	return map[string]interface{}{"name": DefaultServiceName, "version": e.cfg.ServiceVersion}
+}
+
+// post sends one OTLP/HTTP JSON request. Failures are logged once until
+// an export succeeds again; the payload is dropped.
+func (e *Exporter) post(path string, payload interface{}) {
+	err := e.send(path, payload)
+
+	e.mu.Lock()
+	wasFailing := e.failing
+	e.failing = err != nil
+	e.mu.Unlock()
+
+	if err != nil && !wasFailing {
+		log.Printf("Warning: telemetry export failed: %v", err)
+	} else if err == nil && wasFailing {
+		log.Printf("Telemetry export recovered")
+	}
+}
+
+func (e *Exporter) send(path string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", path, err)
+	}
+	req, err := http.NewRequest(http.MethodPost, e.cfg.Endpoint+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range e.cfg.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("%s: collector returned %s", path, resp.Status)
+	}
+	return nil
+}
+
+// randomID returns n random bytes, hex encoded.
+func randomID(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// attribute encodes an OTLP key/value attribute.
+func attribute(key string, value interface{}) map[string]interface{} {
+	var v map[string]interface{}
+	switch val := value.(type) {
+	case string:
+		v = map[string]interface{}{"stringValue": val}
+	case bool:
+		v = map[string]interface{}{"boolValue": val}
+	case int:
+		v = map[string]interface{}{"intValue": fmt.Sprint(val)}
+	case int64:
+		v = map[string]interface{}{"intValue": fmt.Sprint(val)}
+	case float64:
+		v = map[string]interface{}{"doubleValue": val}
+	default:
+		v = map[string]interface{}{"stringValue": fmt.Sprint(val)}
+	}
+	return map[string]interface{}{"key": key, "value": v}
+}
@@ -0,0 +1,168 @@
+package telemetry
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/metrics"
+)
+
+// collector records the OTLP requests it receives.
+type collector struct {
+	mu       sync.Mutex
+	payloads map[string][]map[string]interface{}
+	headers  http.Header
+}
+
+func newCollector(t *testing.T) (*collector, *httptest.Server) {
+	c := &collector{payloads: make(map[string][]map[string]interface{})}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var payload map[string]interface{}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Errorf("invalid JSON posted to %s: %v", r.URL.Path, err)
+		}
+		c.mu.Lock()
+		c.payloads[r.URL.Path] = append(c.payloads[r.URL.Path], payload)
+		c.headers = r.Header.Clone()
+		c.mu.Unlock()
+	}))
+	t.Cleanup(srv.Close)
+	return c, srv
+}
+
+func TestNewWithoutEndpoint(t *testing.T) {
+	if e := New(Config{}); e != nil {
+		t.Fatal("expected nil exporter without an endpoint")
+	}
+
+	// A nil exporter and its spans are no-ops
+	var e *Exporter
+	span := e.Start("noop", KindInternal)
+	span.SetAttribute("key", "value")
+	span.Child("child", KindClient).End(nil)
+	span.End(errors.New("ignored"))
+	e.SetMetrics(metrics.NewRegistry())
+	e.Flush()
+	if err := e.Close(); err != nil {
+		t.Errorf("Close on nil exporter: %v", err)
+	}
+}
+
+func TestExportSpans(t *testing.T) {
+	c, srv := newCollector(t)
+	e := New(Config{Endpoint: srv.URL + "/", Headers: map[string]string{"X-Api-Key": "secret"}, ServiceVersion: "1.2.3"})
+
+	root := e.Start("tools/call", KindServer)
+	root.SetAttribute("mcp.tool", "hub_execute")
+	child := root.Child("spawner.sendRequest", KindClient)
+	child.End(errors.New("timeout"))
+	root.End(nil)
+
+	if err := e.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if got := c.headers.Get("X-Api-Key"); got != "secret" {
+		t.Errorf("X-Api-Key header = %q, want secret", got)
+	}
+	if got := c.headers.Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", got)
+	}
+
+	posted := c.payloads["/v1/traces"]
+	if len(posted) != 1 {
+		t.Fatalf("expected 1 traces request, got %d", len(posted))
+	}
+	resource := posted[0]["resourceSpans"].([]interface{})[0].(map[string]interface{})
+	attrs := resource["resource"].(map[string]interface{})["attributes"].([]interface{})
+	name := attrs[0].(map[string]interface{})
+	if name["key"] != "service.name" || name["value"].(map[string]interface{})["stringValue"] != DefaultServiceName {
+		t.Errorf("unexpected service.name attribute: %v", name)
+	}
+
+	spans := resource["scopeSpans"].([]interface{})[0].(map[string]interface{})["spans"].([]interface{})
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	childSpan, rootSpan := spans[0].(map[string]interface{}), spans[1].(map[string]interface{})
+	if childSpan["traceId"] != rootSpan["traceId"] || childSpan["parentSpanId"] != rootSpan["spanId"] {
+		t.Errorf("child span not linked to root: %v / %v", childSpan, rootSpan)
+	}
+	if len(rootSpan["traceId"].(string)) != 32 || len(rootSpan["spanId"].(string)) != 16 {
+		t.Errorf("unexpected id lengths: %v", rootSpan)
+	}
+	if status := childSpan["status"].(map[string]interface{}); status["code"] != float64(2) || status["message"] != "timeout" {
+		t.Errorf("child status = %v, want error timeout", status)
+	}
+	if status := rootSpan["status"].(map[string]interface{}); status["code"] != float64(1) {
+		t.Errorf("root status = %v, want ok", status)
+	}
+	if rootSpan["kind"] != float64(KindServer) {
+		t.Errorf("root kind = %v, want %d", rootSpan["kind"], KindServer)
+	}
+}
+
+func TestExportMetrics(t *testing.T) {
+	c, srv := newCollector(t)
+	e := New(Config{Endpoint: srv.URL})
+
+	r := metrics.NewRegistry()
+	r.Counter("calls_total", "Calls.", "server").Inc("a")
+	r.Histogram("latency_seconds", "Latency.", []float64{0.1, 1}, "server").Observe(0.5, "a")
+	r.GaugeFunc("queue_depth", "Queue depth.", func() float64 { return 3 })
+	e.SetMetrics(r)
+	e.Close()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	posted := c.payloads["/v1/metrics"]
+	if len(posted) != 1 {
+		t.Fatalf("expected 1 metrics request, got %d", len(posted))
+	}
+	scope := posted[0]["resourceMetrics"].([]interface{})[0].(map[string]interface{})["scopeMetrics"].([]interface{})[0]
+	encoded := scope.(map[string]interface{})["metrics"].([]interface{})
+	if len(encoded) != 3 {
+		t.Fatalf("expected 3 metrics, got %d", len(encoded))
+	}
+
+	sum := encoded[0].(map[string]interface{})["sum"].(map[string]interface{})
+	if sum["isMonotonic"] != true || sum["aggregationTemporality"] != float64(temporalityCumulative) {
+		t.Errorf("unexpected counter encoding: %v", sum)
+	}
+	histogram := encoded[1].(map[string]interface{})["histogram"].(map[string]interface{})
+	point := histogram["dataPoints"].([]interface{})[0].(map[string]interface{})
+	if point["count"] != "1" || len(point["bucketCounts"].([]interface{})) != 3 {
+		t.Errorf("unexpected histogram point: %v", point)
+	}
+	if _, ok := encoded[2].(map[string]interface{})["gauge"]; !ok {
+		t.Errorf("expected a gauge, got %v", encoded[2])
+	}
+}
+
+func TestConfigWithEnv(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://collector:4318")
+	t.Setenv("OTEL_EXPORTER_OTLP_HEADERS", "api-key=abc, x-team = hub")
+	t.Setenv("OTEL_SERVICE_NAME", "hub-prod")
+
+	cfg := Config{}.WithEnv()
+	if cfg.Endpoint != "http://collector:4318" || cfg.ServiceName != "hub-prod" {
+		t.Errorf("unexpected config from env: %+v", cfg)
+	}
+	if cfg.Headers["api-key"] != "abc" || cfg.Headers["x-team"] != "hub" {
+		t.Errorf("unexpected headers from env: %v", cfg.Headers)
+	}
+
+	// Explicit settings win over the environment
+	cfg = Config{Endpoint: "http://explicit:4318", ServiceName: "mine"}.WithEnv()
+	if cfg.Endpoint != "http://explicit:4318" || cfg.ServiceName != "mine" {
+		t.Errorf("settings overridden by env: %+v", cfg)
+	}
+}
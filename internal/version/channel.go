@@ -0,0 +1,83 @@
+package version
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Release channels selectable in settings.
+const (
+	// ChannelStable follows the latest non-prerelease GitHub release.
+	ChannelStable = "stable"
+	// ChannelBeta also follows prereleases.
+	ChannelBeta = "beta"
+)
+
+// ReleasesURL lists all releases, newest first, including prereleases.
+const ReleasesURL = "https://api.github.com/repos/" + RepoOwner + "/" + RepoName + "/releases?per_page=10"
+
+// Installer identifies a package manager that owns the running binary.
+type Installer struct {
+	// Name is the package manager (e.g., "npm", "homebrew").
+	Name string
+	// UpgradeCommand is what the user runs to upgrade instead.
+	UpgradeCommand string
+}
+
+// DetectInstaller reports which package manager installed the binary at
+// execPath, or nil for a standalone install the self-updater may replace.
+func DetectInstaller(execPath string) *Installer {
+	path := filepath.ToSlash(execPath)
+	switch {
+	case strings.Contains(path, "/node_modules/"):
+		return &Installer{Name: "npm", UpgradeCommand: "npm install -g @khanglvm/tool-hub-mcp@latest"}
+	case strings.Contains(path, "/Cellar/") || strings.Contains(path, "/homebrew/") || strings.Contains(path, "/.linuxbrew/"):
+		return &Installer{Name: "homebrew", UpgradeCommand: "brew upgrade tool-hub-mcp"}
+	case isGoInstall(path):
+		return &Installer{Name: "go", UpgradeCommand: "go install github.com/" + RepoOwner + "/" + RepoName + "/cmd/tool-hub-mcp@latest"}
+	}
+	return nil
+}
+
+// isGoInstall reports whether path is inside GOBIN or GOPATH/bin.
+func isGoInstall(path string) bool {
+	dirs := []string{os.Getenv("GOBIN")}
+	if gopath := os.Getenv("GOPATH"); gopath != "" {
+		for _, p := range filepath.SplitList(gopath) {
+			dirs = append(dirs, filepath.Join(p, "bin"))
+		}
+	} else if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(home, "go", "bin"))
+	}
+
+	dir := filepath.ToSlash(filepath.Dir(path))
+	for _, d := range dirs {
+		if d != "" && dir == filepath.ToSlash(filepath.Clean(d)) {
+			return true
+		}
+	}
+	return false
+}
+
+// CurrentInstaller is DetectInstaller for the running executable.
+func CurrentInstaller() *Installer {
+	execPath, err := os.Executable()
+	if err != nil {
+		return nil
+	}
+	if resolved, err := filepath.EvalSymlinks(execPath); err == nil {
+		execPath = resolved
+	}
+	return DetectInstaller(execPath)
+}
+
+// ManagedInstallError is returned when a package manager owns the binary.
+type ManagedInstallError struct {
+	Installer *Installer
+}
+
+func (e *ManagedInstallError) Error() string {
+	return fmt.Sprintf("installed via %s; upgrade with: %s", e.Installer.Name, e.Installer.UpgradeCommand)
+}
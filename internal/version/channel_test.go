@@ -0,0 +1,54 @@
+package version
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectInstaller(t *testing.T) {
+	gobin := t.TempDir()
+	t.Setenv("GOBIN", gobin)
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/usr/local/lib/node_modules/@khanglvm/tool-hub-mcp/bin/tool-hub-mcp", "npm"},
+		{"/home/u/.npm/_npx/abc/node_modules/@khanglvm/tool-hub-mcp-linux-x64/bin/tool-hub-mcp", "npm"},
+		{"/opt/homebrew/Cellar/tool-hub-mcp/1.2.0/bin/tool-hub-mcp", "homebrew"},
+		{"/home/linuxbrew/.linuxbrew/bin/tool-hub-mcp", "homebrew"},
+		{filepath.Join(gobin, "tool-hub-mcp"), "go"},
+		{"/usr/local/bin/tool-hub-mcp", ""},
+	}
+
+	for _, tt := range tests {
+		got := DetectInstaller(tt.path)
+		name := ""
+		if got != nil {
+			name = got.Name
+			if got.UpgradeCommand == "" {
+				t.Errorf("DetectInstaller(%q) has no upgrade command", tt.path)
+			}
+		}
+		if name != tt.want {
+			t.Errorf("DetectInstaller(%q) = %q, want %q", tt.path, name, tt.want)
+		}
+	}
+}
+
+func TestParseRelease(t *testing.T) {
+	stable, err := parseRelease([]byte(`{"tag_name": "v1.2.0"}`), ChannelStable)
+	if err != nil || stable.TagName != "v1.2.0" {
+		t.Fatalf("stable release = %+v (err %v)", stable, err)
+	}
+
+	list := []byte(`[
+		{"tag_name": "v1.3.0-rc.2", "draft": true},
+		{"tag_name": "v1.3.0-rc.1", "prerelease": true},
+		{"tag_name": "v1.2.0"}
+	]`)
+	beta, err := parseRelease(list, ChannelBeta)
+	if err != nil || beta.TagName != "v1.3.0-rc.1" {
+		t.Fatalf("beta release = %+v (err %v), want newest non-draft", beta, err)
+	}
+}
@@ -0,0 +1,105 @@
+package version
+
+import (
+	"strconv"
+	"strings"
+)
+
+// semver is a parsed semantic version; build metadata is dropped.
+type semver struct {
+	core       [3]int
+	prerelease []string
+}
+
+// parseSemver parses "1.2.3", "v1.2.3" or "1.2.3-beta.1". ok is false for
+// anything else, such as the "dev" version of local builds.
+func parseSemver(s string) (v semver, ok bool) {
+	s = strings.TrimPrefix(s, "v")
+	s, _, _ = strings.Cut(s, "+")
+	s, pre, hasPre := strings.Cut(s, "-")
+
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 {
+		return semver{}, false
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return semver{}, false
+		}
+		v.core[i] = n
+	}
+	if hasPre {
+		if pre == "" {
+			return semver{}, false
+		}
+		v.prerelease = strings.Split(pre, ".")
+	}
+	return v, true
+}
+
+// compare returns -1, 0 or 1 as v is older than, equal to or newer than
+// o, ordering prereleases before their release as semver does.
+func (v semver) compare(o semver) int {
+	for i := range v.core {
+		if v.core[i] != o.core[i] {
+			return sign(v.core[i] - o.core[i])
+		}
+	}
+
+	switch {
+	case len(v.prerelease) == 0 && len(o.prerelease) == 0:
+		return 0
+	case len(v.prerelease) == 0:
+		return 1
+	case len(o.prerelease) == 0:
+		return -1
+	}
+	for i := 0; i < len(v.prerelease) && i < len(o.prerelease); i++ {
+		if c := compareIdentifier(v.prerelease[i], o.prerelease[i]); c != 0 {
+			return c
+		}
+	}
+	return sign(len(v.prerelease) - len(o.prerelease))
+}
+
+// compareIdentifier orders prerelease identifiers: numeric ones by value
+// and before alphanumeric ones, which compare as strings.
+func compareIdentifier(a, b string) int {
+	na, errA := strconv.Atoi(a)
+	nb, errB := strconv.Atoi(b)
+	switch {
+	case errA == nil && errB == nil:
+		return sign(na - nb)
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
+
+// isNewer reports whether candidate is a strictly newer version than
+// current. Any valid version is newer than an unparseable current one
+// (a "dev" build); an unparseable candidate never is.
+func isNewer(candidate, current string) bool {
+	c, ok := parseSemver(candidate)
+	if !ok {
+		return false
+	}
+	cur, ok := parseSemver(current)
+	if !ok {
+		return true
+	}
+	return c.compare(cur) > 0
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -15,6 +16,9 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/httpclient"
+	"github.com/khanglvm/tool-hub-mcp/internal/paths"
 )
 
 const (
@@ -30,30 +34,59 @@ var (
 
 // GitHubRelease represents a GitHub release API response.
 type GitHubRelease struct {
-	TagName string `json:"tag_name"`
-	HTMLURL string `json:"html_url"`
+	TagName    string `json:"tag_name"`
+	HTMLURL    string `json:"html_url"`
+	Draft      bool   `json:"draft"`
+	Prerelease bool   `json:"prerelease"`
 }
 
 // UpdateCache stores update check state.
 type UpdateCache struct {
 	LastUpdateCheck  time.Time `json:"lastUpdateCheck"`
 	LastKnownVersion string    `json:"lastKnownVersion"`
+
+	// Channel is the release channel LastKnownVersion was checked on.
+	Channel string `json:"channel,omitempty"`
+
+	// Pending is a downloaded, checksum-verified release awaiting install.
+	Pending *PendingUpdate `json:"pendingUpdate,omitempty"`
 }
 
-// CheckUpdate checks for new version (cached for 24h).
-func CheckUpdate(ctx context.Context) (string, error) {
+// PendingUpdate records a downloaded binary that can be applied on the
+// next start.
+type PendingUpdate struct {
+	Version string `json:"version"`
+	Path    string `json:"path"`
+	SHA256  string `json:"sha256"`
+}
+
+// CheckUpdate checks for a new version on the given release channel
+// (cached for 24h per channel). An empty channel means stable.
+func CheckUpdate(ctx context.Context, channel string) (string, error) {
 	checkMu.Lock()
 	defer checkMu.Unlock()
 
+	if channel == "" {
+		channel = ChannelStable
+	}
+
 	// Check cache
 	cache, err := loadUpdateCache()
-	if err == nil && time.Since(cache.LastUpdateCheck) < 24*time.Hour {
+	if err == nil && cacheChannel(cache) == channel && time.Since(cache.LastUpdateCheck) < 24*time.Hour {
 		// Already checked recently
 		return "", nil
 	}
+	if err != nil {
+		cache = &UpdateCache{}
+	}
+
+	url := UpdateURL
+	if channel == ChannelBeta {
+		url = ReleasesURL
+	}
 
 	// Create HTTP request with timeout
-	req, err := http.NewRequestWithContext(ctx, "GET", UpdateURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to create request: %w", err)
 	}
@@ -61,7 +94,7 @@ func CheckUpdate(ctx context.Context) (string, error) {
 	req.Header.Set("Accept", "application/vnd.github.v3+json")
 
 	// Make request
-	client := &http.Client{Timeout: 10 * time.Second}
+	client := httpclient.New(10 * time.Second)
 	resp, err := client.Do(req)
 	if err != nil {
 		return "", fmt.Errorf("failed to check for updates: %w", err)
@@ -78,8 +111,8 @@ func CheckUpdate(ctx context.Context) (string, error) {
 		return "", fmt.Errorf("failed to read response: %w", err)
 	}
 
-	var release GitHubRelease
-	if err := json.Unmarshal(body, &release); err != nil {
+	release, err := parseRelease(body, channel)
+	if err != nil {
 		return "", fmt.Errorf("failed to parse response: %w", err)
 	}
 
@@ -89,18 +122,50 @@ func CheckUpdate(ctx context.Context) (string, error) {
 	// Update cache
 	cache.LastUpdateCheck = time.Now()
 	cache.LastKnownVersion = latestVersion
+	cache.Channel = channel
 	if err := saveUpdateCache(cache); err != nil {
 		log.Printf("Warning: failed to save update cache: %v", err)
 	}
 
 	// If current version is different from latest
-	if latestVersion != Version {
+	if latestVersion != "" && latestVersion != Version {
 		return latestVersion, nil
 	}
 
 	return "", nil
 }
 
+// parseRelease extracts the newest release for channel from an API
+// response: a single release for stable, a newest-first list for beta.
+func parseRelease(body []byte, channel string) (*GitHubRelease, error) {
+	if channel != ChannelBeta {
+		var release GitHubRelease
+		if err := json.Unmarshal(body, &release); err != nil {
+			return nil, err
+		}
+		return &release, nil
+	}
+
+	var releases []GitHubRelease
+	if err := json.Unmarshal(body, &releases); err != nil {
+		return nil, err
+	}
+	for _, r := range releases {
+		if !r.Draft {
+			return &r, nil
+		}
+	}
+	return &GitHubRelease{}, nil
+}
+
+// cacheChannel returns the channel a cache entry was recorded on.
+func cacheChannel(cache *UpdateCache) string {
+	if cache.Channel == "" {
+		return ChannelStable
+	}
+	return cache.Channel
+}
+
 // DownloadUpdate downloads new binary to temp location with SHA256 verification.
 func DownloadUpdate(ctx context.Context, version string) (string, error) {
 	// Determine binary name for platform
@@ -134,7 +199,7 @@ func DownloadUpdate(ctx context.Context, version string) (string, error) {
 	}
 
 	// Download
-	client := &http.Client{Timeout: 5 * time.Minute}
+	client := httpclient.New(5 * time.Minute)
 	resp, err := client.Do(req)
 	if err != nil {
 		return "", fmt.Errorf("download failed: %w", err)
@@ -178,9 +243,122 @@ func DownloadUpdate(ctx context.Context, version string) (string, error) {
 		return "", fmt.Errorf("failed to make executable: %w", err)
 	}
 
+	// Only verified downloads are applied automatically
+	if expectedChecksum != "" {
+		if err := recordPendingUpdate(&PendingUpdate{
+			Version: version,
+			Path:    tempPath,
+			SHA256:  strings.ToLower(expectedChecksum),
+		}); err != nil {
+			log.Printf("Warning: failed to record pending update: %v", err)
+		}
+	}
+
 	return tempPath, nil
 }
 
+// recordPendingUpdate stores a verified download in the update cache.
+func recordPendingUpdate(pending *PendingUpdate) error {
+	checkMu.Lock()
+	defer checkMu.Unlock()
+
+	cache, err := loadUpdateCache()
+	if err != nil {
+		return err
+	}
+	cache.Pending = pending
+	return saveUpdateCache(cache)
+}
+
+// clearPendingUpdate forgets any pending download.
+func clearPendingUpdate() error {
+	checkMu.Lock()
+	defer checkMu.Unlock()
+
+	cache, err := loadUpdateCache()
+	if err != nil {
+		return err
+	}
+	if cache.Pending == nil {
+		return nil
+	}
+	cache.Pending = nil
+	return saveUpdateCache(cache)
+}
+
+// GetPendingUpdate returns the verified pending update, or nil if there is
+// none. A pending binary that is missing, no longer matches its checksum, or
+// is not newer than the running version is discarded.
+func GetPendingUpdate() (*PendingUpdate, error) {
+	checkMu.Lock()
+	cache, err := loadUpdateCache()
+	checkMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	pending := cache.Pending
+	if pending == nil {
+		return nil, nil
+	}
+
+	if !isNewer(pending.Version, Version) {
+		// Already installed, or older than the running binary
+		os.Remove(pending.Path)
+		return nil, clearPendingUpdate()
+	}
+
+	sum, err := fileChecksum(pending.Path)
+	if err != nil || !strings.EqualFold(sum, pending.SHA256) {
+		if err == nil {
+			log.Printf("Warning: pending update %s failed checksum verification, discarding", pending.Version)
+		}
+		os.Remove(pending.Path)
+		return nil, clearPendingUpdate()
+	}
+
+	return pending, nil
+}
+
+// ApplyPendingUpdate installs the verified pending update, if any, and
+// returns its version ("" when nothing was applied).
+func ApplyPendingUpdate() (string, error) {
+	pending, err := GetPendingUpdate()
+	if err != nil || pending == nil {
+		return "", err
+	}
+
+	if err := ApplyUpdate(pending.Path); err != nil {
+		var managed *ManagedInstallError
+		if errors.As(err, &managed) {
+			// The package manager owns upgrades; don't retry every start
+			os.Remove(pending.Path)
+			clearPendingUpdate()
+		}
+		return "", err
+	}
+
+	if err := clearPendingUpdate(); err != nil {
+		log.Printf("Warning: failed to clear pending update: %v", err)
+	}
+	return pending.Version, nil
+}
+
+// fileChecksum returns the hex SHA256 of a file.
+func fileChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
 // fetchChecksum retrieves the SHA256 checksum from the checksum file.
 func fetchChecksum(ctx context.Context, checksumURL string) (string, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", checksumURL, nil)
@@ -188,7 +366,7 @@ func fetchChecksum(ctx context.Context, checksumURL string) (string, error) {
 		return "", err
 	}
 
-	client := &http.Client{Timeout: 10 * time.Second}
+	client := httpclient.New(10 * time.Second)
 	resp, err := client.Do(req)
 	if err != nil {
 		return "", err
@@ -215,6 +393,8 @@ func fetchChecksum(ctx context.Context, checksumURL string) (string, error) {
 }
 
 // ApplyUpdate atomically replaces binary with downloaded version.
+// Binaries owned by a package manager are never replaced; a
+// *ManagedInstallError carries the upgrade command to use instead.
 func ApplyUpdate(tempPath string) error {
 	// Get current binary path
 	execPath, err := os.Executable()
@@ -222,6 +402,10 @@ func ApplyUpdate(tempPath string) error {
 		return fmt.Errorf("failed to get executable path: %w", err)
 	}
 
+	if installer := CurrentInstaller(); installer != nil {
+		return &ManagedInstallError{Installer: installer}
+	}
+
 	// Backup current binary
 	backupPath := execPath + ".bak"
 	if err := os.Rename(execPath, backupPath); err != nil {
@@ -246,11 +430,7 @@ func ApplyUpdate(tempPath string) error {
 
 // getCachePath returns the path to the update cache file.
 func getCachePath() (string, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return "", err
-	}
-	return filepath.Join(home, ".tool-hub-mcp-cache.json"), nil
+	return paths.UpdateCacheFile()
 }
 
 // loadUpdateCache loads the update cache from disk.
@@ -288,5 +468,8 @@ func saveUpdateCache(cache *UpdateCache) error {
 		return err
 	}
 
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0755); err != nil {
+		return err
+	}
 	return os.WriteFile(cachePath, data, 0644)
 }
@@ -1,6 +1,10 @@
 package version
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -109,7 +113,7 @@ func TestGetCachePath(t *testing.T) {
 	}
 
 	// Verify path contains expected filename
-	if !strings.Contains(path, ".tool-hub-mcp-cache.json") {
+	if !strings.Contains(path, "update-cache.json") {
 		t.Errorf("Path %q does not contain cache filename", path)
 	}
 }
@@ -245,3 +249,106 @@ func TestVersionConstant(t *testing.T) {
 		t.Logf("Version has unexpected format: %q (this may be OK)", Version)
 	}
 }
+
+func TestGetPendingUpdate(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	content := []byte("new binary")
+	sum := sha256.Sum256(content)
+	path := filepath.Join(t.TempDir(), "tool-hub-mcp-9.9.9")
+	if err := os.WriteFile(path, content, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	pending, err := GetPendingUpdate()
+	if err != nil || pending != nil {
+		t.Fatalf("expected no pending update, got %+v (err %v)", pending, err)
+	}
+
+	if err := recordPendingUpdate(&PendingUpdate{
+		Version: "9.9.9",
+		Path:    path,
+		SHA256:  hex.EncodeToString(sum[:]),
+	}); err != nil {
+		t.Fatalf("recordPendingUpdate() failed: %v", err)
+	}
+
+	pending, err = GetPendingUpdate()
+	if err != nil {
+		t.Fatalf("GetPendingUpdate() failed: %v", err)
+	}
+	if pending == nil || pending.Version != "9.9.9" {
+		t.Fatalf("expected pending 9.9.9, got %+v", pending)
+	}
+
+	// A tampered binary is discarded
+	if err := os.WriteFile(path, []byte("tampered"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	pending, err = GetPendingUpdate()
+	if err != nil || pending != nil {
+		t.Fatalf("expected tampered update to be discarded, got %+v (err %v)", pending, err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("tampered binary should be removed")
+	}
+	cache, _ := loadUpdateCache()
+	if cache.Pending != nil {
+		t.Error("pending update should be cleared from the cache")
+	}
+}
+
+// TestGetPendingUpdateDiscardsOlder verifies a pending download that is
+// not newer than the running version is never applied as a downgrade.
+func TestGetPendingUpdateDiscardsOlder(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	running := Version
+	Version = "v2.0.0"
+	t.Cleanup(func() { Version = running })
+
+	for _, pendingVersion := range []string{"1.9.0", "2.0.0", "2.0.0-beta.1"} {
+		content := []byte("binary " + pendingVersion)
+		sum := sha256.Sum256(content)
+		path := filepath.Join(t.TempDir(), "tool-hub-mcp-"+pendingVersion)
+		if err := os.WriteFile(path, content, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := recordPendingUpdate(&PendingUpdate{Version: pendingVersion, Path: path, SHA256: hex.EncodeToString(sum[:])}); err != nil {
+			t.Fatalf("recordPendingUpdate() failed: %v", err)
+		}
+
+		pending, err := GetPendingUpdate()
+		if err != nil || pending != nil {
+			t.Errorf("%s: expected pending update to be discarded, got %+v (err %v)", pendingVersion, pending, err)
+		}
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s: discarded binary should be removed", pendingVersion)
+		}
+	}
+}
+
+func TestIsNewer(t *testing.T) {
+	tests := []struct {
+		candidate, current string
+		want               bool
+	}{
+		{"1.0.1", "1.0.0", true},
+		{"1.10.0", "1.9.0", true},
+		{"v2.0.0", "1.99.99", true},
+		{"1.0.0", "1.0.0", false},
+		{"1.0.0", "v1.0.1", false},
+		{"1.9.0", "1.10.0", false},
+		{"1.0.0", "1.0.0-beta.2", true},
+		{"1.0.0-beta.10", "1.0.0-beta.2", true},
+		{"1.0.0-beta", "1.0.0-alpha.1", true},
+		{"1.0.0-beta.1", "1.0.0", false},
+		{"1.0.0+build.5", "1.0.0", false},
+		{"1.0.0", "dev", true},
+		{"garbage", "1.0.0", false},
+	}
+	for _, tt := range tests {
+		if got := isNewer(tt.candidate, tt.current); got != tt.want {
+			t.Errorf("isNewer(%q, %q) = %v, want %v", tt.candidate, tt.current, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,271 @@
+/*
+Package webhook posts hub events (tool executions, server changes) as JSON
+to configured HTTP endpoints.
+
+Events are queued and delivered by a background worker, so a slow or
+unreachable endpoint never delays tool calls. Failed deliveries are retried
+with exponential backoff; events that still fail, or that arrive while the
+queue is full, are dropped with a warning.
+*/
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/khanglvm/tool-hub-mcp/internal/httpclient"
+)
+
+// Event types.
+const (
+	ExecutionStarted   = "execution.started"
+	ExecutionSucceeded = "execution.succeeded"
+	ExecutionFailed    = "execution.failed"
+	ServerAdded        = "server.added"
+	ServerRemoved      = "server.removed"
+)
+
+const (
+	// maxQueued bounds pending deliveries across all targets.
+	maxQueued = 1000
+
+	// requestTimeout bounds a single delivery attempt.
+	requestTimeout = 10 * time.Second
+)
+
+// retryDelays are the waits before each retry of a failed delivery.
+// A variable so tests can shorten it.
+var retryDelays = []time.Duration{time.Second, 4 * time.Second, 16 * time.Second}
+
+// Event is the JSON body POSTed to a webhook.
+type Event struct {
+	Type        string    `json:"event"`
+	Time        time.Time `json:"timestamp"`
+	Server      string    `json:"server,omitempty"`
+	Tool        string    `json:"tool,omitempty"`
+	ExecutionID string    `json:"executionId,omitempty"`
+	DurationMs  int64     `json:"durationMs,omitempty"`
+	Error       string    `json:"error,omitempty"`
+
+	// Text is a one-line summary, the field Slack and Teams display.
+	Text string `json:"text"`
+}
+
+// summary renders the event's Text.
+func (e Event) summary() string {
+	target := e.Server
+	if e.Tool != "" {
+		target += "/" + e.Tool
+	}
+	switch e.Type {
+	case ExecutionStarted:
+		return fmt.Sprintf("tool-hub-mcp: %s started", target)
+	case ExecutionSucceeded:
+		return fmt.Sprintf("tool-hub-mcp: %s succeeded in %dms", target, e.DurationMs)
+	case ExecutionFailed:
+		return fmt.Sprintf("tool-hub-mcp: %s failed after %dms: %s", target, e.DurationMs, e.Error)
+	case ServerAdded:
+		return fmt.Sprintf("tool-hub-mcp: server '%s' added", e.Server)
+	case ServerRemoved:
+		return fmt.Sprintf("tool-hub-mcp: server '%s' removed", e.Server)
+	}
+	return fmt.Sprintf("tool-hub-mcp: %s %s", e.Type, target)
+}
+
+// Target is an endpoint and the events it receives.
+type Target struct {
+	URL     string
+	Events  []string // globs of event types; empty matches all
+	Headers map[string]string
+}
+
+// wants reports whether the target subscribes to an event type.
+func (t Target) wants(eventType string) bool {
+	if len(t.Events) == 0 {
+		return true
+	}
+	for _, pattern := range t.Events {
+		if ok, _ := path.Match(pattern, eventType); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// delivery is one event bound for one target.
+type delivery struct {
+	target Target
+	body   []byte
+	event  string
+}
+
+// Dispatcher queues events and delivers them to the targets. Thread-safe.
+type Dispatcher struct {
+	client *http.Client
+
+	mu      sync.RWMutex
+	targets []Target
+
+	queue  chan delivery
+	stop   chan struct{} // closed by Close: finish the queue, no more retries
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	closeOnce sync.Once
+}
+
+// New starts a dispatcher for targets.
+func New(targets []Target) *Dispatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	d := &Dispatcher{
+		client:  httpclient.New(requestTimeout),
+		targets: targets,
+		queue:   make(chan delivery, maxQueued),
+		stop:    make(chan struct{}),
+		ctx:     ctx,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	go d.run()
+	return d
+}
+
+// SetTargets replaces the targets, e.g. after a config reload. Queued
+// deliveries still go to the old targets.
+func (d *Dispatcher) SetTargets(targets []Target) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.targets = targets
+}
+
+// Send queues an event for every target that subscribes to it. It never
+// blocks: when the queue is full the event is dropped.
+func (d *Dispatcher) Send(event Event) {
+	if d == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+	event.Text = event.summary()
+
+	d.mu.RLock()
+	targets := d.targets
+	d.mu.RUnlock()
+
+	var body []byte
+	for _, target := range targets {
+		if !target.wants(event.Type) {
+			continue
+		}
+		if body == nil {
+			var err error
+			if body, err = json.Marshal(event); err != nil {
+				log.Printf("Warning: failed to encode webhook event: %v", err)
+				return
+			}
+		}
+		select {
+		case d.queue <- delivery{target: target, body: body, event: event.Type}:
+		default:
+			log.Printf("Warning: webhook queue full, dropped %s event", event.Type)
+		}
+	}
+}
+
+// run delivers queued events until Close, then drains the queue.
+func (d *Dispatcher) run() {
+	defer close(d.done)
+	for {
+		select {
+		case job := <-d.queue:
+			d.deliver(job)
+		case <-d.stop:
+			for {
+				select {
+				case job := <-d.queue:
+					d.deliver(job)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// deliver posts one event, retrying network errors, 429 and 5xx
+// responses with backoff. Close stops pending retries.
+func (d *Dispatcher) deliver(job delivery) {
+	var err error
+	for attempt := 0; ; attempt++ {
+		var retry bool
+		if retry, err = d.post(d.ctx, job); err == nil {
+			return
+		}
+		if !retry || attempt >= len(retryDelays) {
+			break
+		}
+		select {
+		case <-time.After(retryDelays[attempt]):
+		case <-d.stop:
+			log.Printf("Warning: webhook %s event not delivered (shutting down): %v", job.event, err)
+			return
+		}
+	}
+	log.Printf("Warning: webhook %s event not delivered: %v", job.event, err)
+}
+
+// post makes one delivery attempt and reports whether a failure is worth
+// retrying.
+func (d *Dispatcher) post(ctx context.Context, job delivery) (retry bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, job.target.URL, bytes.NewReader(job.body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "tool-hub-mcp")
+	for key, value := range job.target.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 == 2 {
+		return false, nil
+	}
+	retry = resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("endpoint returned %s", resp.Status)
+}
+
+// Close stops the dispatcher. Queued events get one more attempt each
+// (no retries) until timeout, after which the rest are dropped.
+func (d *Dispatcher) Close(timeout time.Duration) {
+	if d == nil {
+		return
+	}
+	d.closeOnce.Do(func() {
+		close(d.stop)
+		select {
+		case <-d.done:
+		case <-time.After(timeout):
+			log.Printf("Warning: webhook delivery timed out on shutdown, dropping %d queued events", len(d.queue))
+			d.cancel()
+			<-d.done
+		}
+		d.cancel()
+	})
+}
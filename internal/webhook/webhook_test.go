@@ -0,0 +1,134 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fastRetries shortens the backoff for the duration of a test.
+func fastRetries(t *testing.T) {
+	saved := retryDelays
+	retryDelays = []time.Duration{time.Millisecond, time.Millisecond}
+	t.Cleanup(func() { retryDelays = saved })
+}
+
+func TestSendDeliversMatchingEvents(t *testing.T) {
+	var mu sync.Mutex
+	var events []Event
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var event Event
+		if err := json.Unmarshal(body, &event); err != nil {
+			t.Errorf("invalid event JSON: %v", err)
+		}
+		mu.Lock()
+		events = append(events, event)
+		auth = r.Header.Get("Authorization")
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	d := New([]Target{{URL: srv.URL, Events: []string{"execution.f*", "server.*"}, Headers: map[string]string{"Authorization": "Bearer t"}}})
+	d.Send(Event{Type: ExecutionStarted, Server: "jira", Tool: "create_issue"})
+	d.Send(Event{Type: ExecutionFailed, Server: "jira", Tool: "create_issue", DurationMs: 12, Error: "boom"})
+	d.Send(Event{Type: ServerAdded, Server: "github"})
+	d.Close(time.Second)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %+v", events)
+	}
+	if events[0].Type != ExecutionFailed || events[0].Text != "tool-hub-mcp: jira/create_issue failed after 12ms: boom" {
+		t.Errorf("unexpected first event: %+v", events[0])
+	}
+	if events[0].Time.IsZero() {
+		t.Error("event timestamp not set")
+	}
+	if events[1].Type != ServerAdded || events[1].Server != "github" {
+		t.Errorf("unexpected second event: %+v", events[1])
+	}
+	if auth != "Bearer t" {
+		t.Errorf("Authorization header = %q", auth)
+	}
+}
+
+func TestDeliverRetries(t *testing.T) {
+	fastRetries(t)
+
+	var attempts atomic.Int32
+	delivered := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		close(delivered)
+	}))
+	defer srv.Close()
+
+	d := New([]Target{{URL: srv.URL}})
+	defer d.Close(time.Second)
+	d.Send(Event{Type: ServerRemoved, Server: "jira"})
+
+	select {
+	case <-delivered:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("event not delivered after retries (%d attempts)", attempts.Load())
+	}
+}
+
+func TestDeliverDoesNotRetryClientErrors(t *testing.T) {
+	fastRetries(t)
+
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	d := New([]Target{{URL: srv.URL}})
+	d.Send(Event{Type: ServerAdded, Server: "jira"})
+	time.Sleep(100 * time.Millisecond)
+	d.Close(time.Second)
+
+	if got := attempts.Load(); got != 1 {
+		t.Errorf("expected 1 attempt for a 400, got %d", got)
+	}
+}
+
+func TestSetTargetsAndNil(t *testing.T) {
+	var nilDispatcher *Dispatcher
+	nilDispatcher.Send(Event{Type: ServerAdded})
+	nilDispatcher.Close(time.Second)
+
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { hits.Add(1) }))
+	defer srv.Close()
+
+	d := New(nil)
+	d.Send(Event{Type: ServerAdded, Server: "ignored"})
+	d.SetTargets([]Target{{URL: srv.URL}})
+	d.Send(Event{Type: ServerAdded, Server: "jira"})
+	d.Close(time.Second)
+
+	if got := hits.Load(); got != 1 {
+		t.Errorf("expected 1 delivery after SetTargets, got %d", got)
+	}
+}
+
+func TestSummary(t *testing.T) {
+	got := Event{Type: ExecutionSucceeded, Server: "jira", Tool: "get_issue", DurationMs: 40}.summary()
+	if !strings.Contains(got, "jira/get_issue succeeded in 40ms") {
+		t.Errorf("unexpected summary %q", got)
+	}
+}